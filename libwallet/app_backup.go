@@ -0,0 +1,322 @@
+package libwallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"decred.org/dcrwallet/v3/errors"
+
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+)
+
+const appBackupVersion = 1
+
+// AppBackup holds the app settings and the wallets restored from an encrypted
+// app backup archive.
+type AppBackup struct {
+	Version   int                        `json:"version"`
+	NetType   utils.NetworkType          `json:"net_type"`
+	CreatedAt time.Time                  `json:"created_at"`
+	Settings  map[string]json.RawMessage `json:"settings"`
+	Wallets   []*sharedW.WalletBackup    `json:"wallets"`
+}
+
+// WalletsWithSeed returns the backed up wallets whose encrypted seed is
+// included in the backup. Their spending passphrases are required to import
+// the backup.
+func (backup *AppBackup) WalletsWithSeed() []*sharedW.WalletBackup {
+	wallets := make([]*sharedW.WalletBackup, 0, len(backup.Wallets))
+	for _, wallet := range backup.Wallets {
+		if wallet.EncryptedSeed != nil {
+			wallets = append(wallets, wallet)
+		}
+	}
+	return wallets
+}
+
+// DowngradedWallets returns the backed up wallets that could spend but have
+// no encrypted seed in the backup, most likely because their seed was backed
+// up. They can only be recreated as watch only wallets.
+func (backup *AppBackup) DowngradedWallets() []*sharedW.WalletBackup {
+	wallets := make([]*sharedW.WalletBackup, 0, len(backup.Wallets))
+	for _, wallet := range backup.Wallets {
+		if wallet.EncryptedSeed == nil && !wallet.WatchingOnly {
+			wallets = append(wallets, wallet)
+		}
+	}
+	return wallets
+}
+
+// WalletImportResult reports the import of a backed up wallet.
+type WalletImportResult struct {
+	Name string
+	// Skipped is set if the wallet was not imported because a wallet with
+	// the same name already exists.
+	Skipped bool
+	// Downgraded is set if the wallet was recreated as a watch only wallet
+	// although it could spend when it was backed up.
+	Downgraded bool
+	// Err is the error that prevented the import of the wallet.
+	Err error
+}
+
+// appBackupSettings are the app settings saved in app backups. Security
+// settings such as the startup passphrase and settings that refer to wallets
+// by ID are left out. The sync settings of each wallet are saved with its
+// user config.
+var appBackupSettings = map[string]bool{
+	sharedW.LogLevelConfigKey:                true,
+	sharedW.PrivacyModeConfigKey:             true,
+	sharedW.SpendUnconfirmedConfigKey:        true,
+	sharedW.CurrencyConversionConfigKey:      true,
+	sharedW.IncomingTxNotificationsConfigKey: true,
+	sharedW.BeepNewBlocksConfigKey:           true,
+	sharedW.SyncOnCellularConfigKey:          true,
+	sharedW.NetworkModeConfigKey:             true,
+	sharedW.UserAgentConfigKey:               true,
+	sharedW.ProxyConfigKey:                   true,
+	sharedW.SyncPolicyConfigKey:              true,
+	sharedW.PoliteiaNotificationConfigKey:    true,
+	sharedW.KnownVSPsConfigKey:               true,
+	sharedW.ExchangeSourceDstnTypeConfigKey:  true,
+	sharedW.HideBalanceConfigKey:             true,
+	sharedW.AutoSyncConfigKey:                true,
+	sharedW.OpenOnDemandConfigKey:            true,
+	sharedW.FetchProposalConfigKey:           true,
+	sharedW.ProposalNotificationConfigKey:    true,
+	sharedW.TransactionNotificationConfigKey: true,
+	sharedW.KnownDexServersConfigKey:         true,
+	sharedW.LanguagePreferenceKey:            true,
+	sharedW.DarkModeConfigKey:                true,
+	sharedW.HideTotalBalanceConfigKey:        true,
+}
+
+// ExportAppBackup writes the app settings, each wallet's encrypted seed, user
+// config values and transaction labels into an archive encrypted with the
+// provided passphrase. The path of the archive is returned.
+func (mgr *AssetsManager) ExportAppBackup(passphrase string) (string, error) {
+	const op errors.Op = "mgr.ExportAppBackup"
+
+	settings, err := sharedW.ReadBucketValues(mgr.params.DB, walletsMetadataBucketName)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+	for key := range settings {
+		if !appBackupSettings[key] {
+			delete(settings, key)
+		}
+	}
+
+	backup := &AppBackup{
+		Version:   appBackupVersion,
+		NetType:   mgr.NetType(),
+		CreatedAt: time.Now(),
+		Settings:  settings,
+	}
+
+	for _, wallet := range mgr.AllWallets() {
+		walletBackup, err := wallet.ExportBackup()
+		if err != nil {
+			return "", errors.E(op, err)
+		}
+
+		walletBackup.XPub, err = wallet.GetExtendedPubKey(0)
+		if err != nil {
+			return "", errors.E(op, err)
+		}
+		walletBackup.WatchingOnly = wallet.IsWatchingOnlyWallet()
+		backup.Wallets = append(backup.Wallets, walletBackup)
+	}
+
+	data, err := json.Marshal(backup)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+
+	encrypted, err := sharedW.EncryptBackup(passphrase, data)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+
+	fileName := fmt.Sprintf("cryptopower_backup_%d.bak", backup.CreatedAt.Unix())
+	filePath := filepath.Join(mgr.params.RootDir, fileName)
+	if err = os.WriteFile(filePath, encrypted, utils.UserFilePerm); err != nil {
+		return "", errors.E(op, err)
+	}
+	return filePath, nil
+}
+
+// OpenAppBackup decrypts the app backup archive at the provided path.
+func (mgr *AssetsManager) OpenAppBackup(filePath, passphrase string) (*AppBackup, error) {
+	const op errors.Op = "mgr.OpenAppBackup"
+
+	encrypted, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	data, err := sharedW.DecryptBackup(passphrase, encrypted)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	backup := new(AppBackup)
+	if err = json.Unmarshal(data, backup); err != nil {
+		return nil, errors.E(op, utils.ErrInvalid, err)
+	}
+
+	if backup.Version != appBackupVersion {
+		return nil, errors.E(op, utils.ErrInvalid, fmt.Sprintf("unsupported backup version %d", backup.Version))
+	}
+
+	if backup.NetType != mgr.NetType() {
+		return nil, errors.E(op, utils.ErrInvalidNet)
+	}
+	return backup, nil
+}
+
+// ImportAppBackup recreates every wallet in the backup along with its
+// preferences and restores the app settings listed in appBackupSettings.
+// Wallets with an encrypted seed are restored from the seed using their
+// spending passphrase from walletPassphrases, which is keyed by wallet name.
+// Other wallets are recreated as watch only wallets. Wallets whose name is
+// already in use are skipped. A wallet that fails to import is removed again
+// and the import continues with the next wallet, the outcome of each wallet is
+// returned. A rescan is started for every recreated wallet.
+func (mgr *AssetsManager) ImportAppBackup(backup *AppBackup, walletPassphrases map[string]string) ([]*WalletImportResult, error) {
+	const op errors.Op = "mgr.ImportAppBackup"
+
+	existing := make(map[string]bool)
+	for _, wallet := range mgr.AllWallets() {
+		existing[wallet.GetWalletName()] = true
+	}
+
+	results := make([]*WalletImportResult, 0, len(backup.Wallets))
+	imported := make([]sharedW.Asset, 0, len(backup.Wallets))
+	for _, walletBackup := range backup.Wallets {
+		result := &WalletImportResult{Name: walletBackup.Name}
+		results = append(results, result)
+		if existing[walletBackup.Name] {
+			log.Infof("Skipping the import of existing wallet %s", walletBackup.Name)
+			result.Skipped = true
+			continue
+		}
+
+		wallet, err := mgr.importWalletBackup(walletBackup, walletPassphrases[walletBackup.Name])
+		if err != nil {
+			log.Errorf("Importing wallet %s failed: %v", walletBackup.Name, err)
+			result.Err = err
+			continue
+		}
+
+		result.Downgraded = walletBackup.EncryptedSeed == nil && !walletBackup.WatchingOnly
+		if result.Downgraded {
+			log.Warnf("Wallet %s was recreated as a watch only wallet, its backup holds no seed", walletBackup.Name)
+		}
+		imported = append(imported, wallet)
+	}
+
+	for key, value := range backup.Settings {
+		if !appBackupSettings[key] {
+			log.Infof("Skipping the import of app setting %s", key)
+			continue
+		}
+		if err := mgr.params.DB.Set(walletsMetadataBucketName, key, value); err != nil {
+			return results, errors.E(op, err)
+		}
+	}
+
+	// Recreated wallets are marked as restored, so syncing them discovers
+	// their addresses and rescans the chain from their birthday.
+	for _, wallet := range imported {
+		if err := wallet.SpvSync(); err != nil {
+			log.Errorf("[%d] error starting rescan of imported wallet: %v", wallet.GetWalletID(), err)
+		}
+	}
+	return results, nil
+}
+
+// importWalletBackup recreates the backed up wallet and applies its
+// preferences. A wallet that was created but could not be set up is deleted
+// again so that the import can be retried.
+func (mgr *AssetsManager) importWalletBackup(backup *sharedW.WalletBackup, privatePassphrase string) (sharedW.Asset, error) {
+	wallet, err := mgr.recreateWallet(backup, privatePassphrase)
+	if err == nil {
+		err = wallet.ImportBackup(backup)
+	}
+	if err == nil && wallet.IsMultisigWallet() {
+		err = restoreMultisig(wallet, privatePassphrase)
+	}
+	if err != nil {
+		if wallet != nil {
+			if delErr := mgr.DeleteWallet(wallet.GetWalletID(), privatePassphrase); delErr != nil {
+				log.Errorf("Removing partly imported wallet %s failed: %v", backup.Name, delErr)
+			}
+		}
+		return nil, err
+	}
+	return wallet, nil
+}
+
+// restoreMultisig sets up the multisig of a recreated multisig wallet from
+// the multisig configuration imported from its backup.
+func restoreMultisig(wallet sharedW.Asset, privatePassphrase string) error {
+	restorer, ok := wallet.(interface {
+		RestoreMultisig(privPass string) error
+	})
+	if !ok {
+		return utils.ErrAssetUnknown
+	}
+	return restorer.RestoreMultisig(privatePassphrase)
+}
+
+// recreateWallet restores the backed up wallet from its encrypted seed or
+// creates it as a watch only wallet if the backup has no seed.
+func (mgr *AssetsManager) recreateWallet(backup *sharedW.WalletBackup, privatePassphrase string) (sharedW.Asset, error) {
+	if backup.EncryptedSeed != nil {
+		seed, err := backup.DecryptSeed(privatePassphrase)
+		if err != nil {
+			return nil, err
+		}
+
+		seedPassphrase, err := backup.DecryptSeedPassphrase(privatePassphrase)
+		if err != nil {
+			return nil, err
+		}
+
+		wallet, err := mgr.RestoreWallet(backup.Type, backup.Name, seed, seedPassphrase, privatePassphrase, backup.PrivatePassphraseType)
+		if err != nil {
+			return nil, err
+		}
+		// Unlocked wallets discover their accounts as soon as they sync.
+		return wallet, wallet.UnlockWallet(privatePassphrase)
+	}
+
+	var descriptor string
+	if value, ok := backup.UserConfig[sharedW.WatchOnlyDescriptorConfigKey]; ok {
+		if err := json.Unmarshal(value, &descriptor); err != nil {
+			return nil, err
+		}
+	}
+
+	switch backup.Type {
+	case utils.DCRWalletAsset:
+		return mgr.CreateNewDCRWatchOnlyWallet(backup.Name, backup.XPub)
+	case utils.BTCWalletAsset:
+		if descriptor != "" {
+			return mgr.CreateNewBTCWatchOnlyWalletFromDescriptor(backup.Name, descriptor)
+		}
+		return mgr.CreateNewBTCWatchOnlyWallet(backup.Name, backup.XPub)
+	case utils.LTCWalletAsset:
+		if descriptor != "" {
+			return mgr.CreateNewLTCWatchOnlyWalletFromDescriptor(backup.Name, descriptor)
+		}
+		return mgr.CreateNewLTCWatchOnlyWallet(backup.Name, backup.XPub)
+	default:
+		return nil, utils.ErrAssetUnknown
+	}
+}
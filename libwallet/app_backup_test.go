@@ -0,0 +1,160 @@
+package libwallet
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+)
+
+func TestDowngradedWallets(t *testing.T) {
+	backup := &AppBackup{Wallets: []*sharedW.WalletBackup{
+		{Name: "seed not backed up", EncryptedSeed: []byte("seed")},
+		{Name: "seed backed up"},
+		{Name: "watch only", WatchingOnly: true},
+	}}
+
+	tests := []struct {
+		name     string
+		got      []*sharedW.WalletBackup
+		expected []string
+	}{
+		{name: "with seed", got: backup.WalletsWithSeed(), expected: []string{"seed not backed up"}},
+		{name: "downgraded", got: backup.DowngradedWallets(), expected: []string{"seed backed up"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			names := make([]string, 0, len(tc.got))
+			for _, wallet := range tc.got {
+				names = append(names, wallet.Name)
+			}
+			if !reflect.DeepEqual(names, tc.expected) {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.expected, names)
+			}
+		})
+	}
+}
+
+// multisigAsset is implemented by the BTC and LTC assets.
+type multisigAsset interface {
+	sharedW.Asset
+	SetupMultisig(threshold int32, cosignerXpubs []string, privPass string, birthday time.Time) error
+	MultisigXpub() (string, error)
+	MultisigConfig() *sharedW.MultisigConfig
+	CurrentMultisigAddress() (string, error)
+	NextMultisigAddress() (string, error)
+}
+
+func TestImportMultisigWalletBackup(t *testing.T) {
+	const passphrase = "passphrase"
+
+	rootDir := t.TempDir()
+	mgr, err := NewAssetsManager(rootDir, rootDir, string(utils.Simulation))
+	if err != nil {
+		t.Fatalf("NewAssetsManager error: %v", err)
+	}
+	defer mgr.Shutdown()
+
+	tests := []struct {
+		name   string
+		create func(name string) (sharedW.Asset, error)
+		// tracked returns true if the wallet tracks the payments made to the
+		// provided multisig address.
+		tracked func(wallet sharedW.Asset, address string) bool
+	}{
+		{
+			name: "btc",
+			create: func(name string) (sharedW.Asset, error) {
+				return mgr.CreateNewBTCMultisigWallet(name, passphrase, sharedW.PassphraseTypePass)
+			},
+			tracked: func(wallet sharedW.Asset, address string) bool {
+				return wallet.HaveAddress(address)
+			},
+		},
+		{
+			name: "ltc",
+			create: func(name string) (sharedW.Asset, error) {
+				return mgr.CreateNewLTCMultisigWallet(name, passphrase, sharedW.PassphraseTypePass)
+			},
+			tracked: func(wallet sharedW.Asset, address string) bool {
+				// The multisig addresses are watched once.
+				var watched []string
+				_ = wallet.ReadUserConfigValue(sharedW.WatchedAddressesConfigKey, &watched)
+				count := 0
+				for _, addr := range watched {
+					if addr == address {
+						count++
+					}
+				}
+				return count == 1
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			createMultisig := func(name string) multisigAsset {
+				t.Helper()
+				wallet, err := tc.create(name)
+				if err != nil {
+					t.Fatalf("error creating the multisig wallet: %v", err)
+				}
+				return wallet.(multisigAsset)
+			}
+
+			cosigner := createMultisig(tc.name + " cosigner")
+			cosignerXpub, err := cosigner.MultisigXpub()
+			if err != nil {
+				t.Fatalf("MultisigXpub error: %v", err)
+			}
+
+			wallet := createMultisig(tc.name + " multisig")
+			if err := wallet.SetupMultisig(2, []string{cosignerXpub}, passphrase, time.Time{}); err != nil {
+				t.Fatalf("SetupMultisig error: %v", err)
+			}
+			// Receive addresses are handed out past the imported gap.
+			for i := 0; i < 25; i++ {
+				if _, err := wallet.NextMultisigAddress(); err != nil {
+					t.Fatalf("NextMultisigAddress error: %v", err)
+				}
+			}
+
+			xpub, _ := wallet.MultisigXpub()
+			address, _ := wallet.CurrentMultisigAddress()
+			cfg := wallet.MultisigConfig()
+
+			backup, err := wallet.ExportBackup()
+			if err != nil {
+				t.Fatalf("ExportBackup error: %v", err)
+			}
+			if err := mgr.DeleteWallet(wallet.GetWalletID(), passphrase); err != nil {
+				t.Fatalf("DeleteWallet error: %v", err)
+			}
+
+			imported, err := mgr.importWalletBackup(backup, passphrase)
+			if err != nil {
+				t.Fatalf("importWalletBackup error: %v", err)
+			}
+			restored := imported.(multisigAsset)
+
+			if got, _ := restored.MultisigXpub(); got != xpub {
+				t.Errorf("expected the multisig key (%v), got (%v)", xpub, got)
+			}
+			if got, _ := restored.CurrentMultisigAddress(); got != address {
+				t.Errorf("expected the receive address (%v), got (%v)", address, got)
+			}
+
+			restoredCfg := restored.MultisigConfig()
+			if restoredCfg.ImportedIndex != cfg.ImportedIndex || restoredCfg.InternalIndex != 0 {
+				t.Errorf("expected the scripts imported up to (%d), got (%d) with the change index (%d)",
+					cfg.ImportedIndex, restoredCfg.ImportedIndex, restoredCfg.InternalIndex)
+			}
+			if !tc.tracked(restored, address) {
+				t.Errorf("the receive address (%v) is not tracked by the restored wallet", address)
+			}
+		})
+	}
+}
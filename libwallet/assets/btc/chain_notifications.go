@@ -0,0 +1,136 @@
+package btc
+
+import (
+	"github.com/btcsuite/btcwallet/chain"
+)
+
+// notificationTee is the chain source handed to btcwallet. It forwards every
+// notification of the wrapped chain client to btcwallet and queues a copy for
+// the sync progress handler, so that neither of them misses the notifications
+// received by the other. The copies are queued without bound so that a slow
+// handler never holds back btcwallet.
+type notificationTee struct {
+	chain.Interface
+
+	walletNtfns chan interface{}
+	syncNtfns   chan interface{}
+}
+
+// newNotificationTee starts forwarding the notifications of client until its
+// notification channel is closed and the queued notifications are delivered,
+// or until quit is closed.
+func newNotificationTee(client chain.Interface, quit <-chan struct{}) *notificationTee {
+	tee := &notificationTee{
+		Interface:   client,
+		walletNtfns: make(chan interface{}),
+		syncNtfns:   make(chan interface{}),
+	}
+	go tee.forward(client.Notifications(), quit)
+	return tee
+}
+
+// Notifications returns the notifications forwarded to btcwallet.
+func (tee *notificationTee) Notifications() <-chan interface{} {
+	return tee.walletNtfns
+}
+
+// SyncNotifications returns the copies of the notifications queued for the
+// sync progress handler.
+func (tee *notificationTee) SyncNotifications() <-chan interface{} {
+	return tee.syncNtfns
+}
+
+func (tee *notificationTee) forward(source <-chan interface{}, quit <-chan struct{}) {
+	walletOut, syncOut := tee.walletNtfns, tee.syncNtfns
+	defer func() {
+		// The outputs that were not closed once drained are closed on quit.
+		if walletOut != nil {
+			close(walletOut)
+		}
+		if syncOut != nil {
+			close(syncOut)
+		}
+	}()
+
+	var walletQueue, syncQueue []interface{}
+	for {
+		// Each output is closed once the source is closed and its queue is
+		// drained.
+		if source == nil && walletOut != nil && len(walletQueue) == 0 {
+			close(walletOut)
+			walletOut = nil
+		}
+		if source == nil && syncOut != nil && len(syncQueue) == 0 {
+			close(syncOut)
+			syncOut = nil
+		}
+		if walletOut == nil && syncOut == nil {
+			return
+		}
+
+		// Sends on nil channels block, so a queue is only drained while it
+		// holds notifications.
+		var walletSend, syncSend chan interface{}
+		var walletNext, syncNext interface{}
+		if len(walletQueue) > 0 {
+			walletSend, walletNext = walletOut, walletQueue[0]
+		}
+		if len(syncQueue) > 0 {
+			syncSend, syncNext = syncOut, syncQueue[0]
+		}
+
+		select {
+		case n, ok := <-source:
+			if !ok {
+				source = nil
+				continue
+			}
+			walletQueue = append(walletQueue, n)
+			syncQueue = append(syncQueue, n)
+		case walletSend <- walletNext:
+			walletQueue[0] = nil
+			walletQueue = walletQueue[1:]
+		case syncSend <- syncNext:
+			syncQueue[0] = nil
+			syncQueue = syncQueue[1:]
+		case <-quit:
+			return
+		}
+	}
+}
+
+// synchronizeWallet makes btcwallet sync from the chain source through a new
+// notificationTee. btcwallet sets the birthday of the chain clients that need
+// it only when they are handed to it unwrapped, it is set here instead.
+func (asset *Asset) synchronizeWallet() {
+	source := asset.chainSource()
+	birthday := asset.Internal().BTC.Manager.Birthday()
+	switch client := source.(type) {
+	case *chain.NeutrinoClient:
+		client.SetStartTime(birthday)
+	case *chain.BitcoindClient:
+		client.SetBirthday(birthday)
+	}
+
+	var quit <-chan struct{}
+	if asset.syncCtx != nil {
+		quit = asset.syncCtx.Done()
+	}
+	tee := newNotificationTee(source, quit)
+	asset.syncData.mu.Lock()
+	asset.syncData.ntfnTee = tee
+	asset.syncData.mu.Unlock()
+
+	asset.Internal().BTC.SynchronizeRPC(tee)
+}
+
+// syncNotifications returns the chain notifications of the sync progress
+// handler.
+func (asset *Asset) syncNotifications() <-chan interface{} {
+	asset.syncData.mu.RLock()
+	defer asset.syncData.mu.RUnlock()
+	if asset.syncData.ntfnTee == nil {
+		return nil
+	}
+	return asset.syncData.ntfnTee.SyncNotifications()
+}
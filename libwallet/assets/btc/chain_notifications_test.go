@@ -0,0 +1,71 @@
+package btc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcwallet/chain"
+)
+
+// testChainSource is a chain client whose notifications are sent by the test.
+type testChainSource struct {
+	chain.Interface
+	ntfns chan interface{}
+}
+
+func (source *testChainSource) Notifications() <-chan interface{} {
+	return source.ntfns
+}
+
+// receive returns the notifications received from ntfns until it is closed.
+func receive(t *testing.T, ntfns <-chan interface{}) []interface{} {
+	t.Helper()
+	var received []interface{}
+	for {
+		select {
+		case n, ok := <-ntfns:
+			if !ok {
+				return received
+			}
+			received = append(received, n)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for notifications")
+		}
+	}
+}
+
+func TestNotificationTee(t *testing.T) {
+	source := &testChainSource{ntfns: make(chan interface{})}
+	tee := newNotificationTee(source, nil)
+
+	// The sync handler does not read until every notification is delivered
+	// to the wallet, which must not be held back.
+	sent := []interface{}{
+		chain.ClientConnected{},
+		&chain.RescanProgress{Height: 1},
+		&chain.RescanProgress{Height: 2},
+		&chain.RescanFinished{Height: 2},
+	}
+	go func() {
+		for _, n := range sent {
+			source.ntfns <- n
+		}
+		close(source.ntfns)
+	}()
+
+	for i, n := range receive(t, tee.Notifications()) {
+		if n != sent[i] {
+			t.Errorf("expected wallet notification (%v), got (%v)", sent[i], n)
+		}
+	}
+
+	received := receive(t, tee.SyncNotifications())
+	if len(received) != len(sent) {
+		t.Fatalf("expected (%d) sync notifications, got (%d)", len(sent), len(received))
+	}
+	for i, n := range received {
+		if n != sent[i] {
+			t.Errorf("expected sync notification (%v), got (%v)", sent[i], n)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package btc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcwallet/walletdb"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+	"github.com/lightninglabs/neutrino"
+)
+
+const (
+	// chainServiceDirName is the directory, next to the wallets' directories,
+	// that holds the headers and filters shared by the wallets of a network.
+	chainServiceDirName = "neutrino"
+
+	// chainServiceDBName is the name of the shared neutrino database.
+	chainServiceDBName = "neutrino.db"
+
+	chainServiceDBTimeout = 10 * time.Second
+)
+
+var (
+	chainServicesMtx sync.Mutex
+	// chainServices are the shared chain services, keyed by their directory.
+	chainServices = make(map[string]*sharedChainService)
+)
+
+// sharedChainService is a neutrino chain service shared by all the loaded
+// wallets of a network. Headers and filters are downloaded and stored once and
+// each wallet runs its own neutrino client and rescans on top of the service.
+// A stopped chain service cannot be restarted, so the service is replaced when
+// a wallet starts syncing after every wallet stopped.
+type sharedChainService struct {
+	dir         string
+	chainParams *chaincfg.Params
+
+	mtx     sync.Mutex
+	db      walletdb.DB
+	cs      *neutrino.ChainService
+	stopped bool
+	// started is set once a wallet started syncing with cs. Stopping a
+	// chain service that was never started blocks forever.
+	started bool
+	// cancelDial cancels the pending connections of cs.
+	cancelDial context.CancelFunc
+	// users are the loaded wallets and active the syncing wallets.
+	users  map[*Asset]struct{}
+	active map[*Asset]struct{}
+}
+
+// acquireChainService returns the chain service shared by the wallets of the
+// asset's network, creating it if the asset is its first user.
+func (asset *Asset) acquireChainService() (*sharedChainService, error) {
+	dir := filepath.Join(filepath.Dir(asset.DataDir()), chainServiceDirName)
+
+	chainServicesMtx.Lock()
+	defer chainServicesMtx.Unlock()
+
+	s, ok := chainServices[dir]
+	if !ok {
+		if err := os.MkdirAll(dir, utils.UserFilePerm); err != nil {
+			return nil, err
+		}
+		db, err := walletdb.Create("bdb", filepath.Join(dir, chainServiceDBName), true, chainServiceDBTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't open the neutrino database: %v", err)
+		}
+		s = &sharedChainService{
+			dir:         dir,
+			chainParams: asset.chainParams,
+			db:          db,
+			stopped:     true,
+			users:       make(map[*Asset]struct{}),
+			active:      make(map[*Asset]struct{}),
+		}
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.users[asset] = struct{}{}
+	if s.cs == nil {
+		if err := s.load(); err != nil {
+			delete(s.users, asset)
+			if !ok {
+				s.db.Close()
+			}
+			return nil, err
+		}
+	}
+	chainServices[dir] = s
+	return s, nil
+}
+
+// release removes the wallet from the users of the chain service. The chain
+// service and its database are closed when the last wallet is released.
+func (s *sharedChainService) release(asset *Asset) {
+	chainServicesMtx.Lock()
+	defer chainServicesMtx.Unlock()
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, ok := s.users[asset]; !ok {
+		return
+	}
+	delete(s.active, asset)
+	delete(s.users, asset)
+	if len(s.users) > 0 {
+		return
+	}
+
+	s.stop()
+	if err := s.db.Close(); err != nil {
+		log.Errorf("closing the neutrino database failed: %v", err)
+	}
+	delete(chainServices, s.dir)
+}
+
+// activate marks the wallet as syncing and returns the chain service it
+// should sync with, replacing the service if it was stopped.
+func (s *sharedChainService) activate(asset *Asset) (*neutrino.ChainService, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.stopped {
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+	}
+	s.active[asset] = struct{}{}
+	s.started = true
+	return s.cs, nil
+}
+
+// deactivate marks the wallet as no longer syncing. The chain service is
+// stopped, which disconnects its peers, when no wallet is syncing.
+func (s *sharedChainService) deactivate(asset *Asset) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	delete(s.active, asset)
+	if len(s.active) == 0 {
+		s.stop()
+	}
+}
+
+// isActive returns true if a wallet is syncing with the chain service.
+func (s *sharedChainService) isActive() bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return len(s.active) > 0
+}
+
+// chainService returns the current chain service.
+func (s *sharedChainService) chainService() *neutrino.ChainService {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.cs
+}
+
+// reload replaces the chain service so that it uses the peer configurations
+// of the wallets. The wallets that were syncing resume syncing
+// with the new service.
+func (s *sharedChainService) reload() error {
+	s.mtx.Lock()
+	active := make([]*Asset, 0, len(s.active))
+	for asset := range s.active {
+		active = append(active, asset)
+	}
+	s.mtx.Unlock()
+
+	for _, asset := range active {
+		asset.CancelSync()
+		asset.syncData.wg.Wait()
+	}
+
+	s.mtx.Lock()
+	s.stop()
+	err := s.load()
+	s.mtx.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, asset := range active {
+		if err := asset.SpvSync(); err != nil {
+			log.Errorf("Restarting the sync of %s failed: %v", asset.GetWalletName(), err)
+		}
+	}
+	return nil
+}
+
+// stop stops the chain service. s.mtx must be held.
+func (s *sharedChainService) stop() {
+	if s.stopped {
+		return
+	}
+	s.cancelDial()
+	if s.started {
+		if err := s.cs.Stop(); err != nil {
+			log.Errorf("Stopping the chain service failed: %v", err)
+		}
+	}
+	s.stopped = true
+}
+
+// load creates a new chain service and hands it to every user. s.mtx must be
+// held.
+func (s *sharedChainService) load() error {
+	persistentPeers, preferredPeers, bans, err := s.peers()
+	if err != nil {
+		return err
+	}
+
+	dialCtx, cancelDial := context.WithCancel(context.Background())
+	cs, err := neutrino.NewChainService(neutrino.Config{
+		DataDir:       s.dir,
+		Database:      s.db,
+		ChainParams:   *s.chainParams,
+		PersistToDisk: true, // keep cfilter headers on disk for efficient rescanning
+		ConnectPeers:  persistentPeers,
+		AddPeers:      preferredPeers,
+		// Dailer function helps to better control the dailer functionality.
+		// It refuses to connect to the banned peers.
+		Dialer: sharedW.NewPeerDialer(utils.BTCPeersSubsystem, bans).Dialer(dialCtx),
+		// DNS seeds are resolved through the proxy, if any.
+		NameResolver: utils.ProxyLookupIP,
+		// WARNING: PublishTransaction currently uses the entire duration
+		// because if an external bug, but even if the resolved, a typical
+		// inv/getdata round trip is ~4 seconds, so we set this so neutrino does
+		// not cancel queries too readily.
+		BroadcastTimeout: 6 * time.Second,
+	})
+	if err != nil {
+		cancelDial()
+		log.Error(err)
+		return fmt.Errorf("couldn't create Neutrino ChainService: %v", err)
+	}
+
+	s.cs, s.cancelDial, s.stopped, s.started = cs, cancelDial, false, false
+	for asset := range s.users {
+		if asset.chainClient != nil {
+			asset.chainClient.CS = cs
+		}
+	}
+	return nil
+}
+
+// peers returns the union of the peer configurations of the users. The chain
+// service only connects to the persistent peers, so they are only used as
+// such if every user has the same persistent peers. Otherwise they are used
+// as preferred peers so that a wallet's persistent peers do not cut the other
+// wallets off the network. Invalid configurations are skipped.
+func (s *sharedChainService) peers() (persistent, preferred []string, bans []*sharedW.PeerBan, err error) {
+	seen := make(map[string]bool)
+	var persistentSets []string
+	for asset := range s.users {
+		cfg, err := asset.ValidPeerConfig()
+		if err != nil {
+			log.Warnf("Ignoring the invalid peer config of %s: %v", asset.GetWalletName(), err)
+			continue
+		}
+
+		persistentSets = append(persistentSets, strings.Join(cfg.Persistent, ";"))
+		for _, address := range cfg.Persistent {
+			if !seen[address] {
+				seen[address] = true
+				persistent = append(persistent, address)
+			}
+		}
+		for _, address := range cfg.Preferred {
+			if !seen[address] {
+				seen[address] = true
+				preferred = append(preferred, address)
+			}
+		}
+		bans = append(bans, cfg.Bans...)
+	}
+
+	if len(persistent) > 0 && !allEqual(persistentSets) {
+		log.Warnf("The wallets' persistent peers differ, connecting to them as preferred peers")
+		preferred = append(persistent, preferred...)
+		persistent = nil
+	}
+	return persistent, preferred, bans, nil
+}
+
+func allEqual(values []string) bool {
+	for _, value := range values {
+		if value != values[0] {
+			return false
+		}
+	}
+	return true
+}
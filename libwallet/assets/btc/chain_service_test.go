@@ -0,0 +1,130 @@
+package btc
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightninglabs/neutrino"
+)
+
+// testChainService returns a shared chain service used by the provided
+// wallets. Its neutrino chain service is only created by the tests that
+// stop it.
+func testChainService(t *testing.T, stopped bool, users ...*Asset) *sharedChainService {
+	t.Helper()
+	dir := t.TempDir()
+	db, err := walletdb.Create("bdb", filepath.Join(dir, chainServiceDBName), true, chainServiceDBTimeout)
+	if err != nil {
+		t.Fatalf("creating the neutrino database: %v", err)
+	}
+
+	s := &sharedChainService{
+		dir:         dir,
+		chainParams: &chaincfg.RegressionNetParams,
+		db:          db,
+		stopped:     stopped,
+		cancelDial:  func() {},
+		users:       make(map[*Asset]struct{}),
+		active:      make(map[*Asset]struct{}),
+	}
+	for _, asset := range users {
+		s.users[asset] = struct{}{}
+	}
+
+	chainServicesMtx.Lock()
+	chainServices[dir] = s
+	chainServicesMtx.Unlock()
+	t.Cleanup(func() {
+		chainServicesMtx.Lock()
+		if chainServices[dir] == s {
+			delete(chainServices, dir)
+			s.db.Close()
+		}
+		chainServicesMtx.Unlock()
+	})
+	return s
+}
+
+func isShared(s *sharedChainService) bool {
+	chainServicesMtx.Lock()
+	defer chainServicesMtx.Unlock()
+	return chainServices[s.dir] == s
+}
+
+func TestChainServiceRelease(t *testing.T) {
+	first, second, other := new(Asset), new(Asset), new(Asset)
+	s := testChainService(t, true, first, second)
+
+	s.release(other)
+	if !isShared(s) || len(s.users) != 2 {
+		t.Fatalf("releasing a wallet that doesn't use the chain service changed its users")
+	}
+
+	s.release(first)
+	if !isShared(s) {
+		t.Fatalf("the chain service was closed while a wallet still uses it")
+	}
+	if err := walletdb.View(s.db, func(walletdb.ReadTx) error { return nil }); err != nil {
+		t.Fatalf("the neutrino database was closed while a wallet still uses it: %v", err)
+	}
+
+	// Releasing a wallet twice must not release the other user.
+	s.release(first)
+	if !isShared(s) || len(s.users) != 1 {
+		t.Fatalf("releasing a wallet twice released the other wallet")
+	}
+
+	s.release(second)
+	if isShared(s) {
+		t.Fatalf("the chain service is still shared after its last wallet released it")
+	}
+	if err := walletdb.View(s.db, func(walletdb.ReadTx) error { return nil }); err == nil {
+		t.Fatalf("the neutrino database is still open after the last wallet released it")
+	}
+}
+
+func TestChainServiceActivation(t *testing.T) {
+	first, second := new(Asset), new(Asset)
+	s := testChainService(t, false, first, second)
+
+	// The regression network has no DNS seeds, the chain service doesn't
+	// connect to any peer.
+	cs, err := neutrino.NewChainService(neutrino.Config{
+		DataDir:     s.dir,
+		Database:    s.db,
+		ChainParams: *s.chainParams,
+	})
+	if err != nil {
+		t.Fatalf("creating the chain service: %v", err)
+	}
+	if err := cs.Start(); err != nil {
+		t.Fatalf("starting the chain service: %v", err)
+	}
+	s.cs = cs
+
+	var dialsCanceled int
+	s.cancelDial = func() { dialsCanceled++ }
+
+	if _, err := s.activate(first); err != nil {
+		t.Fatalf("activate: %v", err)
+	}
+	if _, err := s.activate(second); err != nil {
+		t.Fatalf("activate: %v", err)
+	}
+	if !s.isActive() {
+		t.Fatalf("expected the chain service to be active")
+	}
+
+	s.deactivate(first)
+	if !s.isActive() || s.stopped || dialsCanceled != 0 {
+		t.Fatalf("the chain service was stopped while a wallet is still syncing")
+	}
+
+	s.deactivate(second)
+	if s.isActive() || !s.stopped || dialsCanceled != 1 {
+		t.Fatalf("expected the chain service to stop once no wallet is syncing, "+
+			"stopped (%v), dials canceled (%d)", s.stopped, dialsCanceled)
+	}
+}
@@ -24,8 +24,11 @@ func (asset *Asset) decodeTxHex(txHex string) (*wire.MsgTx, error) {
 	return tx, nil
 }
 
-func (asset *Asset) decodeTxInputs(mtx *wire.MsgTx,
-	walletInputs []wallet.TransactionSummaryInput) (inputs []*sharedW.TxInput, totalWalletInputs int64) {
+// decodeTxInputs decodes the inputs of the provided tx. watchedOutput returns
+// the watch only output spent by an input that doesn't spend a wallet output,
+// if any.
+func (asset *Asset) decodeTxInputs(mtx *wire.MsgTx, walletInputs []wallet.TransactionSummaryInput,
+	watchedOutput func(prevOut wire.OutPoint) *sharedW.TxOutput) (inputs []*sharedW.TxInput, totalWalletInputs int64) {
 	inputs = make([]*sharedW.TxInput, len(mtx.TxIn))
 
 	for i, txIn := range mtx.TxIn {
@@ -45,6 +48,16 @@ func (asset *Asset) decodeTxInputs(mtx *wire.MsgTx,
 			}
 		}
 
+		// Spends of the watched addresses are sent from the imported account
+		// even though the wallet doesn't hold their keys.
+		if input.AccountNumber == -1 {
+			if output := watchedOutput(txIn.PreviousOutPoint); output != nil {
+				input.AccountNumber = ImportedAccountNumber
+				input.Amount = output.Amount
+				input.WatchOnly = true
+			}
+		}
+
 		if input.AccountNumber != -1 {
 			totalWalletInputs += input.Amount
 		}
@@ -54,8 +67,10 @@ func (asset *Asset) decodeTxInputs(mtx *wire.MsgTx,
 	return
 }
 
-func (asset *Asset) decodeTxOutputs(mtx *wire.MsgTx,
-	walletOutputs []wallet.TransactionSummaryOutput) (outputs []*sharedW.TxOutput, totalWalletOutput int64) {
+// decodeTxOutputs decodes the outputs of the provided tx. Outputs paying to
+// the provided watched addresses are marked as watch only.
+func (asset *Asset) decodeTxOutputs(mtx *wire.MsgTx, walletOutputs []wallet.TransactionSummaryOutput,
+	watched map[string]bool) (outputs []*sharedW.TxOutput, totalWalletOutput int64) {
 	outputs = make([]*sharedW.TxOutput, len(mtx.TxOut))
 
 	for i, txOut := range mtx.TxOut {
@@ -88,6 +103,13 @@ func (asset *Asset) decodeTxOutputs(mtx *wire.MsgTx,
 			}
 		}
 
+		// Payments to the watched addresses are received by the imported
+		// account even though the wallet doesn't hold their keys.
+		if output.AccountNumber == -1 && watched[address] {
+			output.AccountNumber = ImportedAccountNumber
+			output.WatchOnly = true
+		}
+
 		if output.AccountNumber != -1 {
 			totalWalletOutput += output.Amount
 		}
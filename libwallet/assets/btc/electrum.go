@@ -0,0 +1,331 @@
+package btc
+
+import (
+	"bytes"
+	"time"
+
+	"decred.org/dcrwallet/v3/errors"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/internal/electrum"
+)
+
+// electrumClient is a chain.Interface that syncs the wallet from Electrum
+// servers, see electrum.Chain.
+type electrumClient struct {
+	*electrum.Chain
+
+	chainParams   *chaincfg.Params
+	notifications *chain.ConcurrentQueue
+}
+
+var _ chain.Interface = (*electrumClient)(nil)
+
+// newElectrumClient returns a client verifying the servers' chain from the
+// chain's checkpoints and the optional wallet checkpoint, a block the wallet
+// already synced.
+func newElectrumClient(chainParams *chaincfg.Params, servers []sharedW.ElectrumServer,
+	dial electrum.DialFunc, walletCheckpoint *electrum.Checkpoint) *electrumClient {
+	configs := make([]electrum.ServerConfig, 0, len(servers))
+	for _, server := range servers {
+		configs = append(configs, electrum.ServerConfig{
+			Address:         server.Address,
+			TLS:             server.TLS,
+			CertFingerprint: server.CertFingerprint,
+		})
+	}
+
+	checkpoints := []electrum.Checkpoint{{Height: 0, Hash: *chainParams.GenesisHash}}
+	for _, checkpoint := range chainParams.Checkpoints {
+		checkpoints = append(checkpoints, electrum.Checkpoint{
+			Height: checkpoint.Height,
+			Hash:   *checkpoint.Hash,
+		})
+	}
+	if walletCheckpoint != nil {
+		checkpoints = append(checkpoints, *walletCheckpoint)
+	}
+
+	c := &electrumClient{
+		chainParams:   chainParams,
+		notifications: chain.NewConcurrentQueue(20),
+	}
+	c.Chain = electrum.NewChain(electrum.ChainConfig{
+		Servers: configs,
+		Dial:    dial,
+		Params: &electrum.ChainParams{
+			Checkpoints:              checkpoints,
+			PowLimit:                 chainParams.PowLimit,
+			ReduceMinDifficulty:      chainParams.ReduceMinDifficulty,
+			RetargetInterval:         int32(chainParams.TargetTimespan / chainParams.TargetTimePerBlock),
+			RetargetAdjustmentFactor: chainParams.RetargetAdjustmentFactor,
+			PowHash:                  chainhash.DoubleHashH,
+		},
+		DecodeTx: decodeElectrumTx,
+		Notifier: c,
+	})
+	return c
+}
+
+func decodeElectrumTx(raw []byte) (*electrum.Tx, error) {
+	msgTx := new(wire.MsgTx)
+	if err := msgTx.Deserialize(bytes.NewReader(raw)); err != nil {
+		return nil, err
+	}
+
+	tx := &electrum.Tx{
+		Hash:   msgTx.TxHash(),
+		Inputs: make([]chainhash.Hash, 0, len(msgTx.TxIn)),
+		Msg:    msgTx,
+	}
+	for _, in := range msgTx.TxIn {
+		tx.Inputs = append(tx.Inputs, in.PreviousOutPoint.Hash)
+	}
+	return tx, nil
+}
+
+// Start connects to the first reachable server.
+func (c *electrumClient) Start() error {
+	if err := c.Chain.Start(); err != nil {
+		return err
+	}
+
+	c.notifications.Start()
+	c.notify(chain.ClientConnected{})
+	return nil
+}
+
+// Stop disconnects from the server.
+func (c *electrumClient) Stop() {
+	c.Chain.Stop()
+	c.notifications.Stop()
+}
+
+// notify queues the notification unless the client is stopped.
+func (c *electrumClient) notify(notification interface{}) {
+	select {
+	case c.notifications.ChanIn() <- notification:
+	case <-c.Done():
+	}
+}
+
+// BlockConnected implements electrum.Notifier.
+func (c *electrumClient) BlockConnected(header *electrum.BlockHeader) {
+	c.notify(chain.BlockConnected(blockMeta(header)))
+}
+
+// BlockDisconnected implements electrum.Notifier.
+func (c *electrumClient) BlockDisconnected(header *electrum.BlockHeader) {
+	c.notify(chain.BlockDisconnected(blockMeta(header)))
+}
+
+// RelevantTx implements electrum.Notifier.
+func (c *electrumClient) RelevantTx(tx *electrum.Tx, block *electrum.BlockHeader) {
+	received := time.Now()
+	var meta *wtxmgr.BlockMeta
+	if block != nil {
+		m := blockMeta(block)
+		meta, received = &m, block.Timestamp
+	}
+
+	rec, err := wtxmgr.NewTxRecordFromMsgTx(tx.Msg.(*wire.MsgTx), received)
+	if err != nil {
+		log.Errorf("Unable to record electrum transaction %s: %v", tx.Hash, err)
+		return
+	}
+	c.notify(chain.RelevantTx{TxRecord: rec, Block: meta})
+}
+
+// GetBlockHeight returns the height of the block with the hash. Only blocks
+// near the tip or recently looked up are known.
+func (c *electrumClient) GetBlockHeight(hash *chainhash.Hash) (int32, error) {
+	height, ok := c.BlockHeight(hash)
+	if !ok {
+		return -1, errors.Errorf("unknown block %s", hash)
+	}
+	return height, nil
+}
+
+// GetBestBlock returns the hash and height of the server's tip.
+func (c *electrumClient) GetBestBlock() (*chainhash.Hash, int32, error) {
+	tip := c.Tip()
+	return &tip.Hash, tip.Height, nil
+}
+
+// GetBlock is not supported, Electrum servers do not serve blocks.
+func (c *electrumClient) GetBlock(*chainhash.Hash) (*wire.MsgBlock, error) {
+	return nil, errors.New("electrum servers do not serve blocks")
+}
+
+// GetBlockHash returns the hash of the main chain block at the height.
+func (c *electrumClient) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	header, err := c.Header(int32(height))
+	if err != nil {
+		return nil, err
+	}
+	return &header.Hash, nil
+}
+
+// GetBlockHeader returns the header of the block with the hash.
+func (c *electrumClient) GetBlockHeader(hash *chainhash.Hash) (*wire.BlockHeader, error) {
+	height, ok := c.BlockHeight(hash)
+	if !ok {
+		return nil, errors.Errorf("unknown block %s", hash)
+	}
+	header, err := c.Header(height)
+	if err != nil {
+		return nil, err
+	}
+
+	blockHeader := new(wire.BlockHeader)
+	return blockHeader, blockHeader.Deserialize(bytes.NewReader(header.Raw))
+}
+
+// BlockStamp returns the server's tip.
+func (c *electrumClient) BlockStamp() (*waddrmgr.BlockStamp, error) {
+	tip := c.Tip()
+	return &waddrmgr.BlockStamp{
+		Hash:      tip.Hash,
+		Height:    tip.Height,
+		Timestamp: tip.Timestamp,
+	}, nil
+}
+
+// SendRawTransaction broadcasts the transaction through the server.
+func (c *electrumClient) SendRawTransaction(tx *wire.MsgTx, _ bool) (*chainhash.Hash, error) {
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		return nil, err
+	}
+	return c.Broadcast(buf.Bytes())
+}
+
+// NotifyBlocks starts the delivery of block notifications.
+func (c *electrumClient) NotifyBlocks() error {
+	c.Chain.NotifyBlocks()
+	return nil
+}
+
+// NotifyReceived watches the addresses and delivers their existing
+// transactions.
+func (c *electrumClient) NotifyReceived(addrs []btcutil.Address) error {
+	pkScripts, err := payToAddrScripts(addrs)
+	if err != nil {
+		return err
+	}
+	return c.Chain.NotifyReceived(pkScripts)
+}
+
+// Rescan watches the addresses and the outpoints' addresses and delivers their
+// transactions from the start block.
+func (c *electrumClient) Rescan(startHash *chainhash.Hash, addrs []btcutil.Address,
+	outPoints map[wire.OutPoint]btcutil.Address) error {
+	for _, addr := range outPoints {
+		addrs = append(addrs, addr)
+	}
+	pkScripts, err := payToAddrScripts(addrs)
+	if err != nil {
+		return err
+	}
+	if err := c.Chain.Rescan(startHash, pkScripts); err != nil {
+		return err
+	}
+
+	tip := c.Tip()
+	c.notify(&chain.RescanFinished{
+		Hash:   &tip.Hash,
+		Height: tip.Height,
+		Time:   tip.Timestamp,
+	})
+	return nil
+}
+
+// FilterBlocks returns the relevant transactions of the first block of the
+// request that has any, found from the history of the request's addresses.
+func (c *electrumClient) FilterBlocks(req *chain.FilterBlocksRequest) (*chain.FilterBlocksResponse, error) {
+	if len(req.Blocks) == 0 {
+		return nil, nil
+	}
+
+	heights := make([]int32, 0, len(req.Blocks))
+	for _, block := range req.Blocks {
+		heights = append(heights, block.Height)
+	}
+	addrs := make([]btcutil.Address, 0, len(req.ExternalAddrs)+len(req.InternalAddrs)+len(req.WatchedOutPoints))
+	for _, addr := range req.ExternalAddrs {
+		addrs = append(addrs, addr)
+	}
+	for _, addr := range req.InternalAddrs {
+		addrs = append(addrs, addr)
+	}
+	for _, addr := range req.WatchedOutPoints {
+		addrs = append(addrs, addr)
+	}
+	pkScripts, err := payToAddrScripts(addrs)
+	if err != nil {
+		return nil, err
+	}
+
+	index, txs, err := c.BlockTxs(heights, pkScripts)
+	if err != nil || index < 0 {
+		return nil, err
+	}
+	msgTxs := make([]*wire.MsgTx, 0, len(txs))
+	for _, tx := range txs {
+		msgTxs = append(msgTxs, tx.Msg.(*wire.MsgTx))
+	}
+
+	filterer := chain.NewBlockFilterer(c.chainParams, req)
+	if !filterer.FilterBlock(&wire.MsgBlock{Transactions: msgTxs}) {
+		return nil, nil
+	}
+
+	return &chain.FilterBlocksResponse{
+		BatchIndex:         uint32(index),
+		BlockMeta:          req.Blocks[index],
+		FoundExternalAddrs: filterer.FoundExternal,
+		FoundInternalAddrs: filterer.FoundInternal,
+		FoundOutPoints:     filterer.FoundOutPoints,
+		RelevantTxns:       filterer.RelevantTxns,
+	}, nil
+}
+
+// Notifications returns the channel the wallet receives chain notifications
+// on.
+func (c *electrumClient) Notifications() <-chan interface{} {
+	return c.notifications.ChanOut()
+}
+
+// BackEnd returns the name of the backend.
+func (c *electrumClient) BackEnd() string {
+	return string(sharedW.ElectrumBackend)
+}
+
+func payToAddrScripts(addrs []btcutil.Address) ([][]byte, error) {
+	pkScripts := make([][]byte, 0, len(addrs))
+	for _, addr := range addrs {
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, err
+		}
+		pkScripts = append(pkScripts, pkScript)
+	}
+	return pkScripts, nil
+}
+
+func blockMeta(header *electrum.BlockHeader) wtxmgr.BlockMeta {
+	return wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{
+			Hash:   header.Hash,
+			Height: header.Height,
+		},
+		Time: header.Timestamp,
+	}
+}
@@ -62,10 +62,17 @@ type feeEstimateCache struct {
 func (asset *Asset) fetchAPIFeeRate() ([]sharedW.FeeEstimate, error) {
 	var feerateURL string
 	net := asset.NetType()
-	switch net {
-	case utils.Mainnet:
+	switch {
+	case utils.IsDevMode():
+		// Stand-ins serve the mainnet API.
+		var err error
+		feerateURL, err = utils.ServiceURL(utils.BTCFeeRateService, MainnetAPIFeeRateURL)
+		if err != nil {
+			return nil, err
+		}
+	case net == utils.Mainnet:
 		feerateURL = MainnetAPIFeeRateURL
-	case utils.Testnet:
+	case net == utils.Testnet:
 		feerateURL = TestnetAPIFeeRateURL
 	default:
 		return nil, fmt.Errorf("%v network is not supported", net)
@@ -0,0 +1,148 @@
+package btc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wallet"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+)
+
+func TestParseWIF(t *testing.T) {
+	privKey, _ := btcec.PrivKeyFromBytes(bytes.Repeat([]byte{1}, 32))
+	wif := func(net *chaincfg.Params, compress bool) string {
+		w, err := btcutil.NewWIF(privKey, net, compress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return w.String()
+	}
+	compressed := wif(&chaincfg.RegressionNetParams, true)
+	uncompressed := wif(&chaincfg.RegressionNetParams, false)
+
+	tests := []struct {
+		name          string
+		wif           string
+		expectedScope waddrmgr.KeyScope
+		wantErr       bool
+	}{
+		{name: "compressed key", wif: compressed, expectedScope: waddrmgr.KeyScopeBIP0084},
+		{name: "surrounding spaces", wif: " " + compressed + " ", expectedScope: waddrmgr.KeyScopeBIP0084},
+		{name: "uncompressed key", wif: uncompressed, expectedScope: waddrmgr.KeyScopeBIP0044},
+		{name: "p2pkh prefix", wif: "p2pkh:" + compressed, expectedScope: waddrmgr.KeyScopeBIP0044},
+		{name: "p2wpkh-p2sh prefix", wif: "P2WPKH-P2SH:" + compressed, expectedScope: waddrmgr.KeyScopeBIP0049Plus},
+		{name: "p2wpkh prefix", wif: "p2wpkh:" + compressed, expectedScope: waddrmgr.KeyScopeBIP0084},
+		{name: "uncompressed p2pkh", wif: "p2pkh:" + uncompressed, expectedScope: waddrmgr.KeyScopeBIP0044},
+		{name: "uncompressed segwit", wif: "p2wpkh:" + uncompressed, wantErr: true},
+		{name: "unknown prefix", wif: "p2tr:" + compressed, wantErr: true},
+		{name: "wrong network", wif: wif(&chaincfg.MainNetParams, true), wantErr: true},
+		{name: "invalid key", wif: "notakey", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			decodedWIF, scope, err := parseWIF(tc.wif, waddrmgr.KeyScopeBIP0084, &chaincfg.RegressionNetParams)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("(%v), expected an error", tc.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("(%v), unexpected error (%v)", tc.name, err)
+			}
+			if scope != tc.expectedScope {
+				t.Errorf("(%v), expected scope (%v), got (%v)", tc.name, tc.expectedScope, scope)
+			}
+			if !decodedWIF.PrivKey.Key.Equals(&privKey.Key) {
+				t.Errorf("(%v), decoded the wrong key", tc.name)
+			}
+		})
+	}
+}
+
+func TestDecodeWatchOnlyTx(t *testing.T) {
+	asset := &Asset{chainParams: &chaincfg.RegressionNetParams}
+	pkScript := func(seed byte) (string, []byte) {
+		addr, err := btcutil.NewAddressWitnessPubKeyHash(bytes.Repeat([]byte{seed}, 20), asset.chainParams)
+		if err != nil {
+			t.Fatal(err)
+		}
+		script, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return addr.String(), script
+	}
+	watchedAddr, watchedScript := pkScript(1)
+	_, walletScript := pkScript(2)
+	_, externalScript := pkScript(3)
+
+	watchedPrevOut := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 0}
+	walletPrevOut := wire.OutPoint{Hash: chainhash.Hash{2}, Index: 0}
+	externalPrevOut := wire.OutPoint{Hash: chainhash.Hash{3}, Index: 0}
+
+	mtx := wire.NewMsgTx(wire.TxVersion)
+	mtx.AddTxIn(wire.NewTxIn(&watchedPrevOut, nil, nil))
+	mtx.AddTxIn(wire.NewTxIn(&walletPrevOut, nil, nil))
+	mtx.AddTxIn(wire.NewTxIn(&externalPrevOut, nil, nil))
+	mtx.AddTxOut(wire.NewTxOut(100, watchedScript))
+	mtx.AddTxOut(wire.NewTxOut(200, walletScript))
+	mtx.AddTxOut(wire.NewTxOut(300, externalScript))
+
+	watchedOutput := func(prevOut wire.OutPoint) *sharedW.TxOutput {
+		if prevOut == watchedPrevOut {
+			return &sharedW.TxOutput{Amount: 1000, WatchOnly: true}
+		}
+		return nil
+	}
+	walletInputs := []wallet.TransactionSummaryInput{{Index: 1, PreviousAccount: 0, PreviousAmount: 2000}}
+	walletOutputs := []wallet.TransactionSummaryOutput{{Index: 1, Account: 0}}
+
+	inputs, totalInputs := asset.decodeTxInputs(mtx, walletInputs, watchedOutput)
+	outputs, totalOutputs := asset.decodeTxOutputs(mtx, walletOutputs, map[string]bool{watchedAddr: true})
+
+	if totalInputs != 3000 {
+		t.Errorf("expected the wallet inputs total (3000), got (%v)", totalInputs)
+	}
+	if totalOutputs != 300 {
+		t.Errorf("expected the wallet outputs total (300), got (%v)", totalOutputs)
+	}
+
+	tests := []struct {
+		name              string
+		account           int32
+		watchOnly         bool
+		expectedAccount   int32
+		expectedWatchOnly bool
+	}{
+		{name: "watched input", account: inputs[0].AccountNumber, watchOnly: inputs[0].WatchOnly,
+			expectedAccount: ImportedAccountNumber, expectedWatchOnly: true},
+		{name: "wallet input", account: inputs[1].AccountNumber, watchOnly: inputs[1].WatchOnly},
+		{name: "external input", account: inputs[2].AccountNumber, watchOnly: inputs[2].WatchOnly,
+			expectedAccount: -1},
+		{name: "watched output", account: outputs[0].AccountNumber, watchOnly: outputs[0].WatchOnly,
+			expectedAccount: ImportedAccountNumber, expectedWatchOnly: true},
+		{name: "wallet output", account: outputs[1].AccountNumber, watchOnly: outputs[1].WatchOnly},
+		{name: "external output", account: outputs[2].AccountNumber, watchOnly: outputs[2].WatchOnly,
+			expectedAccount: -1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.account != tc.expectedAccount {
+				t.Errorf("(%v), expected account (%v), got (%v)", tc.name, tc.expectedAccount, tc.account)
+			}
+			if tc.watchOnly != tc.expectedWatchOnly {
+				t.Errorf("(%v), expected watch only (%v), got (%v)", tc.name, tc.expectedWatchOnly, tc.watchOnly)
+			}
+		})
+	}
+}
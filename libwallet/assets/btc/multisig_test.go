@@ -0,0 +1,421 @@
+package btc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+)
+
+// testMultisigKeys returns n account keys derived from distinct seeds.
+func testMultisigKeys(t *testing.T, n int) []*hdkeychain.ExtendedKey {
+	t.Helper()
+	keys := make([]*hdkeychain.ExtendedKey, 0, n)
+	for i := 0; i < n; i++ {
+		seed := bytes.Repeat([]byte{byte(i + 1)}, hdkeychain.RecommendedSeedLen)
+		key, err := hdkeychain.NewMaster(seed, &chaincfg.RegressionNetParams)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func testMultisigConfig(t *testing.T, keys []*hdkeychain.ExtendedKey, threshold int32, keepKeyOrder bool) *sharedW.MultisigConfig {
+	t.Helper()
+	cfg := &sharedW.MultisigConfig{Threshold: threshold, KeepKeyOrder: keepKeyOrder}
+	for i, key := range keys {
+		pubKey, err := key.Neuter()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			cfg.LocalXpub = pubKey.String()
+		} else {
+			cfg.CosignerXpubs = append(cfg.CosignerXpubs, pubKey.String())
+		}
+	}
+	return cfg
+}
+
+func TestMultisigScript(t *testing.T) {
+	asset := &Asset{chainParams: &chaincfg.RegressionNetParams}
+	keys := testMultisigKeys(t, 3)
+	reversed := []*hdkeychain.ExtendedKey{keys[2], keys[1], keys[0]}
+
+	script := func(keys []*hdkeychain.ExtendedKey, threshold int32, keepKeyOrder bool, index uint32) []byte {
+		path, err := asset.multisigScript(testMultisigConfig(t, keys, threshold, keepKeyOrder), 0, index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return path.script
+	}
+
+	tests := []struct {
+		name  string
+		a, b  []byte
+		equal bool
+	}{
+		{
+			name:  "sorted keys ignore the key order",
+			a:     script(keys, 2, false, 0),
+			b:     script(reversed, 2, false, 0),
+			equal: true,
+		},
+		{
+			name: "kept key order",
+			a:    script(keys, 2, true, 0),
+			b:    script(reversed, 2, true, 0),
+		},
+		{
+			name: "threshold",
+			a:    script(keys, 2, false, 0),
+			b:    script(keys, 3, false, 0),
+		},
+		{
+			name: "index",
+			a:    script(keys, 2, false, 0),
+			b:    script(keys, 2, false, 1),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := bytes.Equal(tc.a, tc.b); got != tc.equal {
+				t.Errorf("(%v), expected equal scripts (%v), got (%v)", tc.name, tc.equal, got)
+			}
+		})
+	}
+
+	class, _, required, err := txscript.ExtractPkScriptAddrs(script(keys, 2, false, 0), asset.chainParams)
+	if err != nil || class != txscript.MultiSigTy || required != 2 {
+		t.Errorf("expected a 2 of 3 multisig script, got (%v) requiring (%d): %v", class, required, err)
+	}
+
+	addr, err := asset.multisigAddress(script(keys, 2, false, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript := payToWitnessScriptHash(script(keys, 2, false, 0))
+	if class := txscript.GetScriptClass(pkScript); class != txscript.WitnessV0ScriptHashTy {
+		t.Errorf("expected a P2WSH output script, got (%v)", class)
+	}
+	if expected, _ := txscript.PayToAddrScript(addr); !bytes.Equal(expected, pkScript) {
+		t.Errorf("expected the output script of (%v), got (%x)", addr, pkScript)
+	}
+}
+
+func TestMultisigScriptDerivations(t *testing.T) {
+	asset := &Asset{chainParams: &chaincfg.RegressionNetParams}
+	keys := testMultisigKeys(t, 3)
+	cfg := testMultisigConfig(t, keys, 2, false)
+
+	// The origin of the local key is known, the cosigner keys are their own
+	// master keys.
+	originPath := []uint32{84 + hdkeychain.HardenedKeyStart, 1 + hdkeychain.HardenedKeyStart, hdkeychain.HardenedKeyStart}
+	desc := &sharedW.Descriptor{Type: sharedW.DescriptorWSHSortedMulti, Threshold: 2}
+	for i, xpub := range cfg.Xpubs() {
+		key := &sharedW.DescriptorKey{XPub: xpub}
+		if i == 0 {
+			key.Fingerprint, key.OriginPath = 0x01020304, originPath
+		}
+		desc.Keys = append(desc.Keys, key)
+	}
+	cfg.Descriptor = desc.String()
+
+	expected := make(map[string]*psbt.Bip32Derivation)
+	for i, key := range keys {
+		childKey, err := key.Derive(1)
+		if err == nil {
+			childKey, err = childKey.Derive(5)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubKey, err := childKey.ECPubKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		derivation := &psbt.Bip32Derivation{PubKey: pubKey.SerializeCompressed(), Bip32Path: []uint32{1, 5}}
+		if i == 0 {
+			derivation.MasterKeyFingerprint = 0x04030201
+			derivation.Bip32Path = append(originPath, 1, 5)
+		} else {
+			masterKey, err := key.ECPubKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			derivation.MasterKeyFingerprint = binary.LittleEndian.Uint32(btcutil.Hash160(masterKey.SerializeCompressed())[:4])
+		}
+		expected[string(derivation.PubKey)] = derivation
+	}
+
+	path, err := asset.multisigScript(cfg, 1, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(path.script, asset.chainParams)
+	if err != nil || len(addrs) != len(path.derivations) {
+		t.Fatalf("expected (%d) key derivations, got (%d): %v", len(addrs), len(path.derivations), err)
+	}
+	for i, derivation := range path.derivations {
+		if !bytes.Equal(derivation.PubKey, addrs[i].ScriptAddress()) {
+			t.Errorf("the derivation (%d) is not the derivation of the key (%d) of the script", i, i)
+		}
+		if !reflect.DeepEqual(derivation, expected[string(derivation.PubKey)]) {
+			t.Errorf("expected (%+v), got (%+v)", expected[string(derivation.PubKey)], derivation)
+		}
+	}
+}
+
+func TestMultisigSignAndCombine(t *testing.T) {
+	asset := &Asset{chainParams: &chaincfg.RegressionNetParams}
+	keys := testMultisigKeys(t, 3)
+	cfg := testMultisigConfig(t, keys, 2, false)
+
+	path, err := asset.multisigScript(cfg, 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := map[string]*multisigPath{"address": path}
+	prevOut := wire.NewTxOut(100000, payToWitnessScriptHash(path.script))
+
+	newPacket := func(t *testing.T, value int64) *psbt.Packet {
+		t.Helper()
+		tx := wire.NewMsgTx(wire.TxVersion)
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+		tx.AddTxOut(wire.NewTxOut(value, prevOut.PkScript))
+		packet, err := psbt.NewFromUnsignedTx(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		packet.Inputs[0].WitnessUtxo = prevOut
+		packet.Inputs[0].WitnessScript = path.script
+		packet.Inputs[0].SighashType = txscript.SigHashAll
+		return packet
+	}
+
+	signWith := func(signer int) func(*multisigPath) (*btcec.PrivateKey, error) {
+		return func(path *multisigPath) (*btcec.PrivateKey, error) {
+			branchKey, err := keys[signer].Derive(path.branch)
+			if err != nil {
+				return nil, err
+			}
+			childKey, err := branchKey.Derive(path.index)
+			if err != nil {
+				return nil, err
+			}
+			return childKey.ECPrivKey()
+		}
+	}
+
+	tests := []struct {
+		name       string
+		signers    []int
+		values     []int64
+		signatures int
+		combineErr bool
+		final      bool
+	}{
+		{name: "single signature", signers: []int{0}, values: []int64{90000}, signatures: 1},
+		{name: "threshold met", signers: []int{0, 2}, values: []int64{90000, 90000}, signatures: 2, final: true},
+		{name: "extra signatures", signers: []int{0, 1, 2}, values: []int64{90000, 90000, 90000}, signatures: 2, final: true},
+		{name: "duplicate signatures", signers: []int{1, 1}, values: []int64{90000, 90000}, signatures: 1},
+		{name: "different transactions", signers: []int{0, 1}, values: []int64{90000, 80000}, combineErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			packets := make([]*psbt.Packet, 0, len(tc.signers))
+			for i, signer := range tc.signers {
+				packet := newPacket(t, tc.values[i])
+				signed, err := signMultisigInputs(packet, paths, signWith(signer))
+				if err != nil || signed != 1 {
+					t.Fatalf("(%v), expected one signed input, got (%d): %v", tc.name, signed, err)
+				}
+				packets = append(packets, packet)
+			}
+
+			combined, err := combineMultisigPackets(packets, int(cfg.Threshold))
+			if (err != nil) != tc.combineErr {
+				t.Fatalf("(%v), expected combine error (%v), got (%v)", tc.name, tc.combineErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if got := len(combined.Inputs[0].PartialSigs); got != tc.signatures {
+				t.Errorf("(%v), expected (%d) signatures, got (%d)", tc.name, tc.signatures, got)
+			}
+
+			err = psbt.MaybeFinalizeAll(combined)
+			if (err == nil) != tc.final {
+				t.Fatalf("(%v), expected finalized (%v), got (%v)", tc.name, tc.final, err)
+			}
+			if err != nil {
+				return
+			}
+
+			tx, err := psbt.Extract(combined)
+			if err != nil {
+				t.Fatal(err)
+			}
+			prevOutFetcher := txscript.NewCannedPrevOutputFetcher(prevOut.PkScript, prevOut.Value)
+			vm, err := txscript.NewEngine(prevOut.PkScript, tx, 0, txscript.StandardVerifyFlags, nil,
+				txscript.NewTxSigHashes(tx, prevOutFetcher), prevOut.Value, prevOutFetcher)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := vm.Execute(); err != nil {
+				t.Errorf("(%v), expected a valid spend, got (%v)", tc.name, err)
+			}
+		})
+	}
+
+	// Inputs that don't spend from the multisig are left unsigned.
+	packet := newPacket(t, 90000)
+	signed, err := signMultisigInputs(packet, map[string]*multisigPath{}, signWith(0))
+	if err != nil || signed != 0 {
+		t.Errorf("expected no signed input, got (%d): %v", signed, err)
+	}
+}
+
+func TestCheckMultisigPSBT(t *testing.T) {
+	asset := &Asset{chainParams: &chaincfg.RegressionNetParams}
+	keys := testMultisigKeys(t, 3)
+	cfg := testMultisigConfig(t, keys, 2, false)
+
+	derivePath := func(branch, index uint32) *multisigPath {
+		path, err := asset.multisigScript(cfg, branch, index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	inputPath, changePath := derivePath(0, 0), derivePath(1, 0)
+	paths := map[string]*multisigPath{"input": inputPath, "change": changePath}
+
+	// The witness script of a multisig the wallet is not part of.
+	otherCfg := testMultisigConfig(t, testMultisigKeys(t, 4)[1:], 2, false)
+	otherPath, err := asset.multisigScript(otherCfg, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	externalScript := payToWitnessScriptHash(otherPath.script)
+
+	// newPacket spends 100000 from the multisig to an external output and
+	// a change output.
+	newPacket := func(t *testing.T, sendValue, changeValue int64) *psbt.Packet {
+		t.Helper()
+		tx := wire.NewMsgTx(wire.TxVersion)
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+		tx.AddTxOut(wire.NewTxOut(sendValue, externalScript))
+		tx.AddTxOut(wire.NewTxOut(changeValue, payToWitnessScriptHash(changePath.script)))
+		packet, err := psbt.NewFromUnsignedTx(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		packet.Inputs[0].WitnessUtxo = wire.NewTxOut(100000, payToWitnessScriptHash(inputPath.script))
+		packet.Inputs[0].WitnessScript = inputPath.script
+		packet.Outputs[1].WitnessScript = changePath.script
+		return packet
+	}
+
+	tests := []struct {
+		name        string
+		packet      func(t *testing.T) *psbt.Packet
+		expectedFee int64
+		wantErr     bool
+	}{
+		{
+			name:        "valid spend",
+			packet:      func(t *testing.T) *psbt.Packet { return newPacket(t, 60000, 39000) },
+			expectedFee: 1000,
+		},
+		{
+			name: "missing witness utxo",
+			packet: func(t *testing.T) *psbt.Packet {
+				packet := newPacket(t, 60000, 39000)
+				packet.Inputs[0].WitnessUtxo = nil
+				return packet
+			},
+			wantErr: true,
+		},
+		{
+			name: "foreign input",
+			packet: func(t *testing.T) *psbt.Packet {
+				packet := newPacket(t, 60000, 39000)
+				packet.Inputs[0].WitnessUtxo.PkScript = externalScript
+				packet.Inputs[0].WitnessScript = otherPath.script
+				return packet
+			},
+			wantErr: true,
+		},
+		{
+			name: "witness script not matching the output script",
+			packet: func(t *testing.T) *psbt.Packet {
+				packet := newPacket(t, 60000, 39000)
+				packet.Inputs[0].WitnessUtxo.PkScript = externalScript
+				return packet
+			},
+			wantErr: true,
+		},
+		{
+			name: "fake change output",
+			packet: func(t *testing.T) *psbt.Packet {
+				packet := newPacket(t, 60000, 39000)
+				packet.Outputs[0].WitnessScript = changePath.script
+				return packet
+			},
+			wantErr: true,
+		},
+		{
+			name:    "outputs above the inputs",
+			packet:  func(t *testing.T) *psbt.Packet { return newPacket(t, 60000, 41000) },
+			wantErr: true,
+		},
+		{
+			name: "fee rate too high",
+			packet: func(t *testing.T) *psbt.Packet {
+				packet := newPacket(t, 60000, 39000)
+				packet.Inputs[0].WitnessUtxo.Value = 10000000
+				return packet
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			summary, err := checkMultisigPSBT(tc.packet(t), paths, int(cfg.Threshold), len(cfg.Xpubs()))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("(%v), expected an error", tc.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("(%v), unexpected error (%v)", tc.name, err)
+			}
+			if summary.fee != tc.expectedFee {
+				t.Errorf("(%v), expected fee (%v), got (%v)", tc.name, tc.expectedFee, summary.fee)
+			}
+			if summary.change[0] || !summary.change[1] {
+				t.Errorf("(%v), expected only the second output as change, got (%v)", tc.name, summary.change)
+			}
+		})
+	}
+}
@@ -32,6 +32,32 @@ func (asset *Asset) RescanBlocksFromHeight(startHeight int32) error {
 	return asset.rescanBlocks(startHeight, nil)
 }
 
+// RescanBlocksFromTime rescans the blockchain for all addresses in the wallet
+// starting from the last block mined before t.
+func (asset *Asset) RescanBlocksFromTime(t time.Time) error {
+	height, err := asset.BlockHeightAtTime(t)
+	if err != nil {
+		return err
+	}
+	return asset.RescanBlocksFromHeight(height)
+}
+
+// BlockHeightAtTime returns the height of the last block mined before t,
+// found by searching the synced block headers.
+func (asset *Asset) BlockHeightAtTime(t time.Time) (int32, error) {
+	if !asset.IsSynced() {
+		return 0, errors.E(utils.ErrNotSynced)
+	}
+
+	return sharedW.SearchBlockHeightAtTime(t, asset.GetBestBlockHeight(), func(height int32) (int64, error) {
+		bs, err := asset.getblockStamp(height)
+		if err != nil {
+			return 0, err
+		}
+		return bs.Timestamp.Unix(), nil
+	})
+}
+
 func (asset *Asset) rescanBlocks(startHeight int32, addrs []btcutil.Address) error {
 	if !asset.IsConnectedToBitcoinNetwork() {
 		return errors.E(utils.ErrNotConnected)
@@ -55,7 +81,14 @@ func (asset *Asset) rescanBlocks(startHeight int32, addrs []btcutil.Address) err
 	}
 
 	if addrs == nil {
-		addrs = []btcutil.Address{}
+		// The plain watched addresses are not held by the address manager
+		// whose addresses are rescanned.
+		addrs = asset.watchedAddresses()
+	}
+
+	// Txs found by the rescan are indexed once it finishes.
+	if err := asset.GetWalletDataDb().SaveLastIndexPoint(startHeight); err != nil {
+		return err
 	}
 
 	asset.syncData.mu.Lock()
@@ -136,7 +169,7 @@ func (asset *Asset) rescanAsync() error {
 
 	asset.Internal().BTC.Stop() // stops Wallet and chainClient (not chainService)
 	asset.Internal().BTC.WaitForShutdown()
-	asset.chainClient.WaitForShutdown()
+	asset.chainSource().WaitForShutdown()
 
 	// Attempt to drop the the tx history. See the btcwallet/cmd/dropwtxmgr app
 	// for more information. Because of how often a forces rescan will be triggered,
@@ -151,15 +184,25 @@ func (asset *Asset) rescanAsync() error {
 		// continue with the rescan despite the error occurring
 	}
 
+	if err := asset.clearIndexedTransactions(); err != nil {
+		log.Errorf("Failed to clear the indexed transactions: %v", err)
+	}
+
 	log.Info("Starting wallet...")
 	asset.Internal().BTC.Start()
 
-	if err := asset.chainClient.Start(); err != nil {
-		return fmt.Errorf("couldn't start Neutrino client: %v", err)
+	// Stopped full node clients cannot be restarted, a new one is connected.
+	if err := asset.connectFullNode(); err != nil {
+		return fmt.Errorf("couldn't connect to the full node: %v", err)
+	}
+	if !asset.isRPCSync() {
+		if err := asset.startChainClient(); err != nil {
+			return fmt.Errorf("couldn't start Neutrino client: %v", err)
+		}
 	}
 
 	log.Infof("Synchronizing wallet (%s) with network...", asset.GetWalletName())
-	asset.Internal().BTC.SynchronizeRPC(asset.chainClient)
+	asset.synchronizeWallet()
 	return nil
 }
 
@@ -212,14 +255,61 @@ func (asset *Asset) forceRescan() {
 	asset.handleSyncUIUpdate()
 }
 
+// SetBirthday sets the wallet birthday chosen by the user along with the
+// birthday and birthday block of the address manager, which wallet recovery
+// starts from, to the last block mined before it. The wallet must be synced
+// for the block to be found. A birthday set by the user is kept once the
+// address discovery of a restored wallet completes.
+func (asset *Asset) SetBirthday(birthday time.Time) error {
+	const op errors.Op = "btc.SetBirthday"
+
+	if !asset.WalletOpened() {
+		return utils.ErrBTCNotInitialized
+	}
+
+	height, err := asset.BlockHeightAtTime(birthday)
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	block, err := asset.getblockStamp(height)
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	if err := asset.Wallet.SetBirthday(birthday); err != nil {
+		return errors.E(op, err)
+	}
+
+	err = walletdb.Update(asset.Internal().BTC.Database(), func(dbtx walletdb.ReadWriteTx) error {
+		ns := dbtx.ReadWriteBucket(wAddrMgrBkt)
+		if err := asset.Internal().BTC.Manager.SetBirthday(ns, birthday); err != nil {
+			return err
+		}
+		return asset.Internal().BTC.Manager.SetBirthdayBlock(ns, *block, true)
+	})
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	asset.SetBoolConfigValueForKey(sharedW.BirthdaySetByUserConfigKey, true)
+	return nil
+}
+
 // updateAssetBirthday updates the appropriate birthday and birthday block
-// immediately after initial rescan is completed.
+// immediately after initial rescan is completed unless the birthday was set
+// by the user.
 func (asset *Asset) updateAssetBirthday() {
 	const op errors.Op = "updateAssetBirthday"
 
-	txs, err := asset.getTransactionsRaw(0, 0, true)
+	if asset.ReadBoolConfigValueForKey(sharedW.BirthdaySetByUserConfigKey, false) {
+		log.Debugf("(%v) Keeping the birthday set by the user", asset.GetWalletName())
+		return
+	}
+
+	txs, err := asset.GetTransactionsRaw(0, 0, utils.TxFilterAll, true)
 	if err != nil {
-		log.Error(errors.E(op, "getTransactionsRaw failed %v", err))
+		log.Error(errors.E(op, "GetTransactionsRaw failed %v", err))
 		// try updating birthday block on next startup.
 		return
 	}
@@ -294,7 +384,9 @@ func (asset *Asset) updateAssetBirthday() {
 	}
 
 	// At the wallet level update the new birthday chosen.
-	asset.SetBirthday(block.Timestamp)
+	if err := asset.Wallet.SetBirthday(block.Timestamp); err != nil {
+		log.Error(errors.E(op, err))
+	}
 
 	// At the address manager level update the new birthday and birthday block chosen.
 	err = walletdb.Update(asset.Internal().BTC.Database(), func(dbtx walletdb.ReadWriteTx) error {
@@ -379,15 +471,15 @@ func (asset *Asset) getblockStamp(height int32) (*waddrmgr.BlockStamp, error) {
 		return nil, fmt.Errorf("invalid block height provided: Error: %v", err)
 	}
 
-	block, err := asset.chainClient.GetBlock(startHash)
+	header, err := asset.chainSource().GetBlockHeader(startHash)
 	if err != nil {
 		return nil, fmt.Errorf("invalid block hash provided: Error: %v", err)
 	}
 
 	return &waddrmgr.BlockStamp{
-		Hash:      block.BlockHash(),
+		Hash:      *startHash,
 		Height:    height,
-		Timestamp: block.Header.Timestamp,
+		Timestamp: header.Timestamp,
 	}, nil
 }
 
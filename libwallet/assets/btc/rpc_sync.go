@@ -0,0 +1,236 @@
+package btc
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"decred.org/dcrwallet/v3/errors"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/btcsuite/btcwallet/walletdb"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/internal/electrum"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+)
+
+const (
+	// rpcReconnectAttempts is the number of times the connection to btcd is
+	// attempted before the sync fails.
+	rpcReconnectAttempts = 3
+
+	// electrumCheckpointDepth is the depth of the block the wallet synced to
+	// that the electrum servers' chain is verified from, deeper than the
+	// blocks that are reorganized.
+	electrumCheckpointDepth = 100
+
+	// bitcoindPollingInterval is the interval at which bitcoind is polled
+	// for new blocks and mempool transactions when its ZMQ endpoints are not
+	// configured.
+	bitcoindPollingInterval = 10 * time.Second
+)
+
+// rpcPorts are the default RPC ports of each backend on each network.
+var rpcPorts = map[sharedW.RPCBackend]map[string]string{
+	sharedW.BtcdBackend: {
+		"mainnet":  "8334",
+		"testnet3": "18334",
+		"regtest":  "18334",
+		"simnet":   "18556",
+		"signet":   "38332",
+	},
+	sharedW.BitcoindBackend: {
+		"mainnet":  "8332",
+		"testnet3": "18332",
+		"regtest":  "18443",
+		"signet":   "38332",
+	},
+}
+
+// chainSource returns the chain backend the wallet syncs from, the full node
+// if the wallet is connected to one and neutrino otherwise.
+func (asset *Asset) chainSource() chain.Interface {
+	if asset.rpcClient != nil {
+		return asset.rpcClient
+	}
+	return asset.chainClient
+}
+
+// isRPCSync returns true if the wallet syncs from a full node.
+func (asset *Asset) isRPCSync() bool {
+	return asset.rpcClient != nil
+}
+
+// connectFullNode starts the client of the full node the wallet is configured
+// to sync from, if any. If the node cannot be reached and the config allows
+// it, no error is returned and the wallet syncs over SPV instead. Nodes and
+// electrum servers on private hosts are connected to directly and the others
+// through the app's proxy, if any. Since the btcd and bitcoind clients cannot
+// dial through the proxy, only private hosts are used while it is set.
+func (asset *Asset) connectFullNode() error {
+	asset.rpcClient = nil
+	asset.stopBitcoindConn()
+
+	cfg := asset.RPCSyncConfig()
+	if cfg == nil {
+		return nil
+	}
+
+	client, err := asset.startRPCClient(cfg)
+	if err != nil {
+		asset.stopBitcoindConn()
+		if !cfg.FallbackToSPV {
+			return err
+		}
+		log.Warnf("Unable to sync %s from %s, falling back to SPV sync: %v",
+			asset.GetWalletName(), cfg.Backend, err)
+		return nil
+	}
+
+	asset.rpcClient = client
+	return nil
+}
+
+func (asset *Asset) startRPCClient(cfg *sharedW.RPCSyncConfig) (chain.Interface, error) {
+	if cfg.Backend == sharedW.ElectrumBackend {
+		client := newElectrumClient(asset.chainParams, cfg.ElectrumServers,
+			utils.NodeDialContext(utils.BTCPeersSubsystem), asset.electrumCheckpoint())
+		if err := client.Start(); err != nil {
+			return nil, err
+		}
+		return client, nil
+	}
+
+	host, err := utils.NormalizeAddress(cfg.Host, rpcPorts[cfg.Backend][asset.chainParams.Name])
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckDirectNodeHosts(host, cfg.ZMQBlockHost, cfg.ZMQTxHost); err != nil {
+		return nil, err
+	}
+
+	if cfg.Backend == sharedW.BtcdBackend {
+		client, err := chain.NewRPCClient(asset.chainParams, host, cfg.User, cfg.Pass,
+			[]byte(cfg.Certificate), cfg.DisableTLS, rpcReconnectAttempts)
+		if err != nil {
+			return nil, err
+		}
+		if err := client.Start(); err != nil {
+			return nil, err
+		}
+		return client, nil
+	}
+
+	dial := utils.NodeDialContext(utils.BTCPeersSubsystem)
+	bitcoindCfg := &chain.BitcoindConfig{
+		ChainParams: asset.chainParams,
+		Host:        host,
+		User:        cfg.User,
+		Pass:        cfg.Pass,
+		// Blocks a pruned node no longer has are fetched from its peers.
+		Dialer: func(addr string) (net.Conn, error) {
+			return dial(context.Background(), "tcp", addr)
+		},
+	}
+	if cfg.ZMQBlockHost != "" && cfg.ZMQTxHost != "" {
+		bitcoindCfg.ZMQConfig = &chain.ZMQConfig{
+			ZMQBlockHost:           cfg.ZMQBlockHost,
+			ZMQTxHost:              cfg.ZMQTxHost,
+			ZMQReadDeadline:        5 * time.Second,
+			MempoolPollingInterval: bitcoindPollingInterval,
+		}
+	} else {
+		bitcoindCfg.PollingConfig = &chain.PollingConfig{
+			BlockPollingInterval: bitcoindPollingInterval,
+			TxPollingInterval:    bitcoindPollingInterval,
+		}
+	}
+
+	conn, err := chain.NewBitcoindConn(bitcoindCfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Start(); err != nil {
+		return nil, err
+	}
+	asset.bitcoindConn = conn
+
+	client := conn.NewBitcoindClient()
+	if err := client.Start(); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// stopBitcoindConn closes the connection to bitcoind, if any. Stopping the
+// bitcoind client does not close it.
+func (asset *Asset) stopBitcoindConn() {
+	if asset.bitcoindConn != nil {
+		asset.bitcoindConn.Stop()
+		asset.bitcoindConn = nil
+	}
+}
+
+// electrumCheckpoint returns the block electrumCheckpointDepth blocks below
+// the block the wallet synced to, for the electrum client to verify the
+// servers' chain from it instead of the older chain checkpoints. It is nil
+// if the wallet did not sync that far.
+func (asset *Asset) electrumCheckpoint() *electrum.Checkpoint {
+	manager := asset.Internal().BTC.Manager
+	height := manager.SyncedTo().Height - electrumCheckpointDepth
+	if height <= 0 {
+		return nil
+	}
+
+	var hash chainhash.Hash
+	err := walletdb.View(asset.Internal().BTC.Database(), func(dbtx walletdb.ReadTx) error {
+		blockHash, err := manager.BlockHash(dbtx.ReadBucket(wAddrMgrBkt), height)
+		if err == nil {
+			hash = *blockHash
+		}
+		return err
+	})
+	if err != nil {
+		log.Debugf("Wallet block %d is unknown, verifying the electrum chain from a chain checkpoint: %v", height, err)
+		return nil
+	}
+	return &electrum.Checkpoint{Height: height, Hash: hash}
+}
+
+// rpcBlockHeight returns the height of the block with the hash from the full
+// node the wallet syncs from.
+func (asset *Asset) rpcBlockHeight(hash *chainhash.Hash) (int32, error) {
+	switch client := asset.rpcClient.(type) {
+	case *chain.BitcoindClient:
+		return client.GetBlockHeight(hash)
+	case *electrumClient:
+		return client.GetBlockHeight(hash)
+	case *chain.RPCClient:
+		header, err := client.GetBlockHeaderVerbose(hash)
+		if err != nil {
+			return -1, err
+		}
+		return header.Height, nil
+	default:
+		return -1, errors.New(utils.ErrNotConnected)
+	}
+}
+
+// rpcBestBlock returns the tip of the full node the wallet syncs from.
+func (asset *Asset) rpcBestBlock() *sharedW.BlockInfo {
+	hash, height, err := asset.rpcClient.GetBestBlock()
+	if err != nil {
+		log.Error("GetBestBlock hash for BTC failed, Err: ", err)
+		return sharedW.InvalidBlock
+	}
+	header, err := asset.rpcClient.GetBlockHeader(hash)
+	if err != nil {
+		log.Error("GetBestBlock header for BTC failed, Err: ", err)
+		return sharedW.InvalidBlock
+	}
+
+	return &sharedW.BlockInfo{
+		Height:    height,
+		Timestamp: header.Timestamp.Unix(),
+	}
+}
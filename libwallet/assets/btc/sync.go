@@ -2,7 +2,8 @@ package btc
 
 import (
 	"fmt"
-	"strings"
+	"os"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -10,8 +11,8 @@ import (
 	"decred.org/dcrwallet/v3/errors"
 	"github.com/btcsuite/btcwallet/chain"
 	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
 	"github.com/crypto-power/cryptopower/libwallet/utils"
-	"github.com/lightninglabs/neutrino"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -33,10 +34,9 @@ const (
 type SyncData struct {
 	mu sync.RWMutex
 
-	bestBlockheight     int32 // Synced peers best block height.
-	syncstarted         uint32
-	txlistening         uint32
-	chainServiceStopped bool
+	bestBlockheight int32 // Synced peers best block height.
+	syncstarted     uint32
+	txlistening     uint32
 
 	syncing            bool
 	synced             bool
@@ -45,10 +45,11 @@ type SyncData struct {
 	rescanStartHeight  *int32
 	isSyncShuttingDown bool
 
-	wg sync.WaitGroup
+	// ntfnTee delivers the chain notifications to the wallet and to
+	// handleNotifications.
+	ntfnTee *notificationTee
 
-	// Listeners
-	syncProgressListeners map[string]*sharedW.SyncProgressListener
+	wg sync.WaitGroup
 
 	*activeSyncData
 }
@@ -115,30 +116,16 @@ func (asset *Asset) resetSyncProgressData() {
 	asset.syncData.isRescan = false
 }
 
-// AddSyncProgressListener registers a sync progress listener to the asset.
-func (asset *Asset) AddSyncProgressListener(syncProgressListener *sharedW.SyncProgressListener, uniqueIdentifier string) error {
-	asset.syncData.mu.Lock()
-	defer asset.syncData.mu.Unlock()
-
-	if _, ok := asset.syncData.syncProgressListeners[uniqueIdentifier]; ok {
-		return errors.New(utils.ErrListenerAlreadyExist)
-	}
-
-	asset.syncData.syncProgressListeners[uniqueIdentifier] = syncProgressListener
-	return nil
-}
-
-// RemoveSyncProgressListener unregisters a sync progress listener from the asset.
-func (asset *Asset) RemoveSyncProgressListener(uniqueIdentifier string) {
-	asset.syncData.mu.Lock()
-	defer asset.syncData.mu.Unlock()
-
-	delete(asset.syncData.syncProgressListeners, uniqueIdentifier)
-}
-
 // bestServerPeerBlockHeight accesses the connected peers and requests for the
 // last synced block height.
 func (asset *Asset) bestServerPeerBlockHeight() {
+	if asset.isRPCSync() {
+		if _, height, err := asset.chainSource().GetBestBlock(); err == nil {
+			asset.syncData.bestBlockheight = height
+		}
+		return
+	}
+
 	serverPeers := asset.chainClient.CS.Peers()
 	for _, p := range serverPeers {
 		if p.LastBlock() > asset.syncData.bestBlockheight {
@@ -193,11 +180,7 @@ func (asset *Asset) updateSyncProgress(rawBlockHeight int32) {
 	asset.syncData.headersFetchProgress.GeneralSyncProgress.TotalTimeRemainingSeconds = int64((timeSpentSoFar * remainingHeaders) / headersFetchedSoFar)
 
 	// publish the sync progress results to all listeners.
-	for _, listener := range asset.syncData.syncProgressListeners {
-		if listener.OnHeadersFetchProgress != nil {
-			listener.OnHeadersFetchProgress(&asset.syncData.headersFetchProgress)
-		}
-	}
+	asset.PublishSyncProgress(&asset.syncData.headersFetchProgress)
 }
 
 func (asset *Asset) publishHeadersFetchComplete() {
@@ -210,12 +193,19 @@ func (asset *Asset) publishHeadersFetchComplete() {
 	asset.syncData.syncing = false
 }
 
+// notifySyncError notifies the sync progress listeners that the sync failed
+// to start.
+func (asset *Asset) notifySyncError(err error) {
+	asset.syncData.mu.RLock()
+	defer asset.syncData.mu.RUnlock()
+
+	asset.ClearSyncProgress()
+	asset.PublishEvent(eventbus.TopicSyncEndedWithError, err)
+}
+
 func (asset *Asset) handleSyncUIUpdate() {
-	for _, listener := range asset.syncData.syncProgressListeners {
-		if listener.OnSyncCompleted != nil {
-			listener.OnSyncCompleted()
-		}
-	}
+	asset.ClearSyncProgress()
+	asset.PublishEvent(eventbus.TopicSyncCompleted, nil)
 }
 
 func (asset *Asset) handleNotifications() {
@@ -224,7 +214,7 @@ func (asset *Asset) handleNotifications() {
 notificationsLoop:
 	for {
 		select {
-		case n, ok := <-asset.chainClient.Notifications():
+		case n, ok := <-asset.syncNotifications():
 			if !ok {
 				continue notificationsLoop
 			}
@@ -292,10 +282,17 @@ notificationsLoop:
 				// new block or transaction detected.
 				t.Reset(1 * time.Second)
 
+				// Index the txs found by the initial sync or the rescan before
+				// listening for new ones.
+				if err := asset.IndexTransactions(); err != nil {
+					log.Errorf("Tx Index Error: %v", err)
+				}
+
 				// Only run the listener once the chain is synced and ready to listen
 				// for newly mined block. This prevents unnecessary CPU use spikes
 				// on startup when a wallet is syncing from scratch.
 				go asset.listenForTransactions()
+				asset.notifyWatchedAddresses()
 
 				// Since the initial run on a restored wallet, address discovery
 				// is complete, mark discovered accounts as true.
@@ -303,6 +300,7 @@ notificationsLoop:
 					// Update the assets birthday from genesis block to a date closer
 					// to when the privatekey was first used.
 					asset.updateAssetBirthday()
+					asset.selectUsedKeyScope()
 					asset.MarkWalletAsDiscoveredAccounts()
 				}
 
@@ -338,61 +336,33 @@ func (asset *Asset) prepareChain() error {
 	}
 
 	log.Debug("Starting native BTC wallet sync...")
-	chainService, err := asset.loadChainService()
+	chainService, err := asset.acquireChainService()
 	if err != nil {
 		return err
 	}
 
-	asset.chainClient = chain.NewNeutrinoClient(asset.chainParams, chainService)
-
-	return nil
-}
-
-func (asset *Asset) loadChainService() (chainService *neutrino.ChainService, err error) {
-	// Read config for persistent peers, if set parse and set neutrino's ConnectedPeers
-	// persistentPeers.
-	var persistentPeers []string
-	peerAddresses := asset.ReadStringConfigValueForKey(sharedW.SpvPersistentPeerAddressesConfigKey, "")
-	if peerAddresses != "" {
-		addresses := strings.Split(peerAddresses, ";")
-		for _, address := range addresses {
-			peerAddress, err := utils.NormalizeAddress(address, asset.chainParams.DefaultPort)
-			if err != nil {
-				log.Errorf("SPV peer address(%s) is invalid: %v", peerAddress, err)
-			} else {
-				persistentPeers = append(persistentPeers, peerAddress)
-			}
-		}
+	asset.chainService = chainService
+	asset.chainClient = chain.NewNeutrinoClient(asset.chainParams, chainService.chainService())
 
-		if len(persistentPeers) == 0 {
-			return chainService, errors.New(utils.ErrInvalidPeers)
+	// Headers used to be stored by each wallet, they are now shared.
+	for _, name := range []string{"block_headers.bin", "reg_filter_headers.bin"} {
+		if err := os.Remove(filepath.Join(asset.DataDir(), name)); err != nil && !os.IsNotExist(err) {
+			log.Warnf("Removing the wallet's own %s failed: %v", name, err)
 		}
 	}
 
-	asset.dailerCtx, asset.dailerCancel = asset.ShutdownContextWithCancel()
-	chainService, err = neutrino.NewChainService(neutrino.Config{
-		DataDir:       asset.DataDir(),
-		Database:      asset.GetWalletDataDb().BTC,
-		ChainParams:   *asset.chainParams,
-		PersistToDisk: true, // keep cfilter headers on disk for efficient rescanning
-		ConnectPeers:  persistentPeers,
-		// Dailer function helps to better control the dailer functionality.
-		Dialer: utils.DialerFunc(asset.dailerCtx),
-		// WARNING: PublishTransaction currently uses the entire duration
-		// because if an external bug, but even if the resolved, a typical
-		// inv/getdata round trip is ~4 seconds, so we set this so neutrino does
-		// not cancel queries too readily.
-		BroadcastTimeout: 6 * time.Second,
-	})
+	return nil
+}
+
+// startChainClient starts the wallet's neutrino client on the shared chain
+// service.
+func (asset *Asset) startChainClient() error {
+	chainService, err := asset.chainService.activate(asset)
 	if err != nil {
-		log.Error(err)
-		return nil, fmt.Errorf("couldn't create Neutrino ChainService: %v", err)
+		return err
 	}
-	asset.syncData.mu.Lock()
-	asset.syncData.chainServiceStopped = false
-	asset.syncData.mu.Unlock()
-
-	return chainService, nil
+	asset.chainClient.CS = chainService
+	return asset.chainClient.Start()
 }
 
 // CancelSync stops the sync process.
@@ -402,9 +372,6 @@ func (asset *Asset) CancelSync() {
 
 	log.Info("Canceling sync. May take a while for sync to fully cancel.")
 
-	// Cancel all the pending tcp connection at the node level.
-	asset.dailerCancel()
-
 	// reset the sync data first.
 	asset.resetSyncProgressData()
 
@@ -413,6 +380,9 @@ func (asset *Asset) CancelSync() {
 	asset.syncData.wg.Add(1)
 	go asset.stopSync()
 
+	asset.ClearSyncProgress()
+	asset.PublishEvent(eventbus.TopicSyncCanceled, false)
+
 	log.Infof("(%v) SPV wallet closed", asset.GetWalletName())
 }
 
@@ -436,26 +406,23 @@ func (asset *Asset) stopSync() {
 	}
 
 	// 2. shutdown the chain client.
-	asset.chainClient.Stop() // If active, attempt to shut it down.
+	asset.chainSource().Stop() // If active, attempt to shut it down.
 
 	if asset.WalletOpened() {
-		// Neutrino performs explicit chain service start but never explicit
-		// chain service stop thus the need to have it done here when stopping
-		// a wallet sync.
-		// 3. Disabling the peers connectivity allows the upstream handleChainNotification
-		// goroutine to return.
-		if err := asset.chainClient.CS.Stop(); err != nil {
-			// ignore the error and proceed with shutdown.
-			log.Errorf("Stopping chain client failed: %v", err)
+		if !asset.isRPCSync() {
+			// 3. The chain service is shared with the other wallets, it is
+			// only stopped if no other wallet is syncing.
+			asset.chainService.deactivate(asset)
 		}
-
-		asset.syncData.chainServiceStopped = true
 		// 4. Wait for the upstream wallet to shutdown completely.
 		loadedAsset.WaitForShutdown()
 	}
 
-	// 5. Wait for the chain client to shutdown
-	asset.chainClient.WaitForShutdown()
+	// 5. Wait for the chain client to shutdown. Full node clients are not
+	// reused, the next sync connects a new one.
+	asset.chainSource().WaitForShutdown()
+	asset.stopBitcoindConn()
+	asset.rpcClient = nil
 
 	// Declares that the sync context is done and goroutines listening to it
 	// should exit. The shutdown protocol will eventually attempt to end this
@@ -482,35 +449,36 @@ func (asset *Asset) stopSync() {
 // startSync initiates the full chain sync starting protocols. It attempts to
 // restart the chain service if it hasn't been initialized.
 func (asset *Asset) startSync() error {
-	g, _ := errgroup.WithContext(asset.syncCtx)
-
-	if asset.syncData.chainServiceStopped {
-		chainService, err := asset.loadChainService()
-		if err != nil {
-			return err
-		}
-		asset.chainClient.CS = chainService
+	// A full node client is started as soon as it is connected.
+	if err := asset.connectFullNode(); err != nil {
+		asset.CancelSync()
+		log.Errorf("couldn't connect to the full node: %v", err)
+		return err
 	}
 
-	// Chain client performs explicit chain service start up thus no need
-	// to re-initialize it.
-	g.Go(asset.chainClient.Start)
+	if !asset.isRPCSync() {
+		g, _ := errgroup.WithContext(asset.syncCtx)
 
-	if err := g.Wait(); err != nil {
-		asset.CancelSync()
-		log.Errorf("couldn't start Neutrino client: %v", err)
-		return err
+		// Chain client performs explicit chain service start up thus no need
+		// to re-initialize it.
+		g.Go(asset.startChainClient)
+
+		if err := g.Wait(); err != nil {
+			asset.CancelSync()
+			log.Errorf("couldn't start Neutrino client: %v", err)
+			return err
+		}
 	}
 
 	// Subscribe to chainclient notifications.
-	if err := asset.chainClient.NotifyBlocks(); err != nil {
+	if err := asset.chainSource().NotifyBlocks(); err != nil {
 		log.Errorf("subscribing to notifications failed: %v", err)
 		return err
 	}
 
 	log.Infof("Synchronizing wallet (%s) with network...", asset.GetWalletName())
 	// Initializes the goroutines handling chain notifications, rescan progress and handlers.
-	asset.Internal().BTC.SynchronizeRPC(asset.chainClient)
+	asset.synchronizeWallet()
 
 	select {
 	// Wait for 5 seconds so that all goroutines initialized in SynchronizeRPC()
@@ -564,7 +532,7 @@ func (asset *Asset) waitForSyncCompletion() {
 	for {
 		select {
 		case <-t.C:
-			if asset.chainClient.IsCurrent() {
+			if asset.chainSource().IsCurrent() {
 				asset.syncData.mu.Lock()
 				asset.syncData.synced = true
 				asset.syncData.syncing = false
@@ -610,46 +578,25 @@ func (asset *Asset) SpvSync() (err error) {
 	asset.syncData.synced = false
 	asset.syncData.mu.Unlock()
 
-	for _, listener := range asset.syncData.syncProgressListeners {
-		if listener.OnSyncStarted != nil {
-			listener.OnSyncStarted()
-		}
-	}
+	asset.PublishEvent(eventbus.TopicSyncStarted, nil)
 
 	go func() {
 		err = asset.startWallet()
 		if err != nil {
 			log.Warn("error occurred when starting BTC sync: ", err)
+			asset.notifySyncError(err)
 		}
 	}()
 
 	return err
 }
 
-// reloadChainService loads a new instance of chain service to be used
-// for sync. It restarts sync if the wallet was previously connected to the btc newtork
-// before the function call.
+// reloadChainService loads a new instance of the chain service shared by the
+// wallets of the network. It restarts the sync of the wallets that were
+// connected to the btc network before the function call.
 func (asset *Asset) reloadChainService() error {
 	if !asset.WalletOpened() {
 		return utils.ErrBTCNotInitialized
 	}
-
-	isPrevConnected := asset.IsConnectedToNetwork()
-	if isPrevConnected {
-		asset.CancelSync()
-	}
-
-	asset.chainClient.CS.Stop()
-	chainService, err := asset.loadChainService()
-	if err != nil {
-		return err
-	}
-	asset.chainClient.CS = chainService
-
-	// If the asset is previously connected to the network call SpvSync to
-	// start sync using the new instance of chain service.
-	if isPrevConnected {
-		return asset.SpvSync()
-	}
-	return nil
+	return asset.chainService.reload()
 }
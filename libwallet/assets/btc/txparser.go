@@ -5,6 +5,7 @@ import (
 
 	"github.com/btcsuite/btcd/blockchain"
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
 	w "github.com/btcsuite/btcwallet/wallet"
 	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
 	"github.com/crypto-power/cryptopower/libwallet/txhelper"
@@ -24,8 +25,16 @@ func (asset *Asset) decodeTransactionWithTxSummary(blockheight int32, txsummary
 		txType = txhelper.TxTypeCoinBase
 	}
 
-	inputs, totalInputsAmount := asset.decodeTxInputs(decodedTx, txsummary.MyInputs)
-	outputs, totalOutputsAmount := asset.decodeTxOutputs(decodedTx, txsummary.MyOutputs)
+	watched := asset.watchedAddressSet()
+	watchedOutput := func(prevOut wire.OutPoint) *sharedW.TxOutput {
+		if len(watched) == 0 {
+			return nil
+		}
+		return asset.watchedTxOutput(prevOut)
+	}
+
+	inputs, totalInputsAmount := asset.decodeTxInputs(decodedTx, txsummary.MyInputs, watchedOutput)
+	outputs, totalOutputsAmount := asset.decodeTxOutputs(decodedTx, txsummary.MyOutputs, watched)
 	amount, direction := txhelper.TransactionAmountAndDirection(totalInputsAmount, totalOutputsAmount, int64(txsummary.Fee))
 
 	return &sharedW.Transaction{
@@ -0,0 +1,57 @@
+package btc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/btcsuite/btcwallet/waddrmgr"
+)
+
+func TestUsedKeyScopes(t *testing.T) {
+	tests := []struct {
+		name     string
+		balances map[waddrmgr.KeyScope]float64
+		expected []waddrmgr.KeyScope
+	}{
+		{name: "no funds", expected: []waddrmgr.KeyScope{}},
+		{
+			name:     "default scope only",
+			balances: map[waddrmgr.KeyScope]float64{waddrmgr.KeyScopeBIP0084: 1},
+			expected: []waddrmgr.KeyScope{waddrmgr.KeyScopeBIP0084},
+		},
+		{
+			name: "most funds first",
+			balances: map[waddrmgr.KeyScope]float64{
+				waddrmgr.KeyScopeBIP0084: 0.5,
+				waddrmgr.KeyScopeBIP0044: 2,
+				waddrmgr.KeyScopeBIP0086: 1,
+			},
+			expected: []waddrmgr.KeyScope{waddrmgr.KeyScopeBIP0044, waddrmgr.KeyScopeBIP0086, waddrmgr.KeyScopeBIP0084},
+		},
+		{
+			name: "same funds in order of preference",
+			balances: map[waddrmgr.KeyScope]float64{
+				waddrmgr.KeyScopeBIP0044:     1,
+				waddrmgr.KeyScopeBIP0049Plus: 1,
+			},
+			expected: []waddrmgr.KeyScope{waddrmgr.KeyScopeBIP0049Plus, waddrmgr.KeyScopeBIP0044},
+		},
+		{
+			name: "unknown and empty scopes",
+			balances: map[waddrmgr.KeyScope]float64{
+				{Purpose: 1017, Coin: 0}:     5,
+				waddrmgr.KeyScopeBIP0084:     0,
+				waddrmgr.KeyScopeBIP0049Plus: 0.1,
+			},
+			expected: []waddrmgr.KeyScope{waddrmgr.KeyScopeBIP0049Plus},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := usedKeyScopes(tc.balances); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.expected, got)
+			}
+		})
+	}
+}
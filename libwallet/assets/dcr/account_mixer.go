@@ -11,6 +11,7 @@ import (
 	w "decred.org/dcrwallet/v3/wallet"
 	"decred.org/dcrwallet/v3/wallet/udb"
 	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
 	"github.com/crypto-power/cryptopower/libwallet/internal/certs"
 	"github.com/crypto-power/cryptopower/libwallet/utils"
 	"github.com/decred/dcrd/chaincfg/v3"
@@ -25,23 +26,29 @@ const (
 	MixedAccountBranch = int32(udb.ExternalBranch)
 )
 
+// AddAccountMixerNotificationListener registers a listener notified when the
+// account mixer of the wallet starts or stops.
 func (asset *Asset) AddAccountMixerNotificationListener(accountMixerNotificationListener *AccountMixerNotificationListener, uniqueIdentifier string) error {
-	asset.notificationListenersMu.Lock()
-	defer asset.notificationListenersMu.Unlock()
-
-	if _, ok := asset.accountMixerNotificationListeners[uniqueIdentifier]; ok {
-		return errors.New(utils.ErrListenerAlreadyExist)
+	filter := eventbus.Filter{
+		WalletID: asset.ID,
+		Topics:   []eventbus.Topic{eventbus.TopicMixerStarted, eventbus.TopicMixerEnded},
 	}
-
-	asset.accountMixerNotificationListeners[uniqueIdentifier] = accountMixerNotificationListener
-	return nil
+	return asset.Events().Subscribe(asset.EventSubscriberID("mixer", uniqueIdentifier), filter, func(e eventbus.Event) {
+		switch e.Topic {
+		case eventbus.TopicMixerStarted:
+			if accountMixerNotificationListener.OnAccountMixerStarted != nil {
+				accountMixerNotificationListener.OnAccountMixerStarted(e.WalletID)
+			}
+		case eventbus.TopicMixerEnded:
+			if accountMixerNotificationListener.OnAccountMixerEnded != nil {
+				accountMixerNotificationListener.OnAccountMixerEnded(e.WalletID)
+			}
+		}
+	})
 }
 
 func (asset *Asset) RemoveAccountMixerNotificationListener(uniqueIdentifier string) {
-	asset.notificationListenersMu.Lock()
-	defer asset.notificationListenersMu.Unlock()
-
-	delete(asset.accountMixerNotificationListeners, uniqueIdentifier)
+	asset.Events().Unsubscribe(asset.EventSubscriberID("mixer", uniqueIdentifier))
 }
 
 // CreateMixerAccounts creates the two accounts needed for the account mixer. This function
@@ -183,9 +190,7 @@ func (asset *Asset) StartAccountMixer(walletPassphrase string) error {
 
 	go func() {
 		log.Info("Running account mixer")
-		if asset.accountMixerNotificationListeners != nil {
-			asset.publishAccountMixerStarted(asset.ID)
-		}
+		asset.PublishEvent(eventbus.TopicMixerStarted, nil)
 
 		ctx, cancel := asset.ShutdownContextWithCancel()
 		asset.cancelAccountMixer = cancel
@@ -195,9 +200,7 @@ func (asset *Asset) StartAccountMixer(walletPassphrase string) error {
 		}
 
 		asset.cancelAccountMixer = nil
-		if asset.accountMixerNotificationListeners != nil {
-			asset.publishAccountMixerEnded(asset.ID)
-		}
+		asset.PublishEvent(eventbus.TopicMixerEnded, nil)
 	}()
 
 	return nil
@@ -224,9 +227,9 @@ func (asset *Asset) readCSPPConfig() *CSPPConfig {
 		csppTLSConfig.ServerName = ShuffleServer
 		csppTLSConfig.RootCAs = pool
 
-		dailer := new(net.Dialer)
+		dial := utils.ProxyDialContext(utils.MixerSubsystem)
 		dialCSPPServer = func(ctx context.Context, network, addr string) (net.Conn, error) {
-			conn, err := dailer.DialContext(context.Background(), network, addr)
+			conn, err := dial(context.Background(), network, addr)
 			if err != nil {
 				return nil, err
 			}
@@ -304,25 +307,3 @@ func (asset *Asset) accountHasMixableOutput(accountNumber int32) bool {
 func (asset *Asset) IsAccountMixerActive() bool {
 	return asset.cancelAccountMixer != nil
 }
-
-func (asset *Asset) publishAccountMixerStarted(walletID int) {
-	asset.notificationListenersMu.RLock()
-	defer asset.notificationListenersMu.RUnlock()
-
-	for _, accountMixerNotificationListener := range asset.accountMixerNotificationListeners {
-		if accountMixerNotificationListener.OnAccountMixerStarted != nil {
-			accountMixerNotificationListener.OnAccountMixerStarted(walletID)
-		}
-	}
-}
-
-func (asset *Asset) publishAccountMixerEnded(walletID int) {
-	asset.notificationListenersMu.RLock()
-	defer asset.notificationListenersMu.RUnlock()
-
-	for _, accountMixerNotificationListener := range asset.accountMixerNotificationListeners {
-		if accountMixerNotificationListener.OnAccountMixerEnded != nil {
-			accountMixerNotificationListener.OnAccountMixerEnded(walletID)
-		}
-	}
-}
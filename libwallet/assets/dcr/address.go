@@ -5,6 +5,7 @@ import (
 
 	"decred.org/dcrwallet/v3/errors"
 	w "decred.org/dcrwallet/v3/wallet"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
 	"github.com/crypto-power/cryptopower/libwallet/utils"
 	"github.com/decred/dcrd/txscript/v4/stdaddr"
 )
@@ -141,3 +142,80 @@ func (asset *Asset) AddressPubKey(address string) (string, error) {
 		return "", fmt.Errorf("address is not a managed pub key address")
 	}
 }
+
+// AccountAddresses returns all the addresses derived by the provided account
+// up to the account's external and internal key counts, together with their
+// derivation path and usage details. For the imported account, the imported
+// addresses are returned instead.
+func (asset *Asset) AccountAddresses(account int32) ([]*sharedW.AddressUsage, error) {
+	const op errors.Op = "dcr.AccountAddresses"
+
+	if !asset.WalletOpened() {
+		return nil, utils.ErrDCRNotInitialized
+	}
+
+	acct, err := asset.GetAccount(account)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, _ := asset.ShutdownContextWithCancel()
+	addrs := make([]*sharedW.AddressUsage, 0, acct.ExternalKeyCount+acct.InternalKeyCount+acct.ImportedKeyCount)
+	if account == ImportedAccountNumber {
+		imported, err := asset.Internal().DCR.ImportedAddresses(ctx, acct.Name)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+
+		for _, addr := range imported {
+			addrs = append(addrs, &sharedW.AddressUsage{
+				Address:       addr.String(),
+				AccountNumber: account,
+				Imported:      true,
+			})
+		}
+	} else {
+		hdPath, err := asset.HDPathForAccount(account)
+		if err != nil {
+			return nil, err
+		}
+
+		derive := func(branch uint32, count int32) error {
+			for index := uint32(0); index < uint32(count); index++ {
+				addr, err := asset.Internal().DCR.AddressAtIdx(ctx, uint32(account), branch, index)
+				if err != nil {
+					return err
+				}
+
+				addrs = append(addrs, &sharedW.AddressUsage{
+					Address:       addr.String(),
+					AccountNumber: account,
+					Internal:      branch == 1,
+					Index:         index,
+					HDPath:        fmt.Sprintf("%s' / %d / %d", hdPath, branch, index),
+				})
+			}
+			return nil
+		}
+
+		if err := derive(0, acct.ExternalKeyCount); err != nil {
+			return nil, errors.E(op, err)
+		}
+		if err := derive(1, acct.InternalKeyCount); err != nil {
+			return nil, errors.E(op, err)
+		}
+	}
+
+	txs, err := asset.GetTransactionsRaw(0, 0, utils.TxFilterAll, true)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	utxos, err := asset.UnspentOutputs(account)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	asset.PopulateAddressUsage(addrs, txs, utxos, asset.ToAmount)
+	return addrs, nil
+}
@@ -244,6 +244,10 @@ func AllVoteAgendas(chainParams *chaincfg.Params, newestFirst bool) ([]*Agenda,
 	if chainParams.Net == wire.TestNet3 {
 		host = dcrdataAgendasAPITestnetURL
 	}
+	host, err := utils.ServiceURL(utils.DcrdataService, host)
+	if err != nil {
+		return nil, err
+	}
 
 	req := &utils.ReqConfig{
 		Method:  http.MethodGet,
@@ -0,0 +1,89 @@
+package dcr
+
+import (
+	"os"
+	"path/filepath"
+
+	"decred.org/dcrwallet/v3/errors"
+	"github.com/crypto-power/cryptopower/libwallet/internal/loader/dcr"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+)
+
+const (
+	// BdbDriver identifies the bolt wallet database driver.
+	BdbDriver = dcr.BdbDriver
+	// BadgerDriver identifies the badger wallet database driver.
+	BadgerDriver = dcr.BadgerDriver
+)
+
+func (asset *Asset) walletDbPath() string {
+	return filepath.Join(asset.DataDir(), dcr.WalletDbName)
+}
+
+// DatabaseDriver returns the driver used by the wallet database.
+func (asset *Asset) DatabaseDriver() (string, error) {
+	return dcr.DatabaseDriver(asset.walletDbPath())
+}
+
+// DatabaseSize returns the size in bytes of the wallet database files.
+func (asset *Asset) DatabaseSize() (int64, error) {
+	var size int64
+	err := filepath.Walk(asset.walletDbPath(), func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return err
+	})
+	return size, err
+}
+
+// MigrateDatabaseDriver moves the wallet database to the provided driver. The
+// wallet is closed during the migration and reopened afterwards. The previous
+// database is kept until ConfirmDatabaseDriverMigration or
+// RevertDatabaseDriverMigration is called.
+func (asset *Asset) MigrateDatabaseDriver(driver string) error {
+	if asset.IsConnectedToDecredNetwork() {
+		return errors.New(utils.ErrSyncAlreadyInProgress)
+	}
+
+	if err := asset.CloseWallet(); err != nil {
+		return err
+	}
+
+	err := dcr.MigrateDatabaseDriver(asset.walletDbPath(), driver)
+	if openErr := asset.OpenWallet(); err == nil {
+		err = openErr
+	}
+	return err
+}
+
+// HasPendingDatabaseDriverMigration returns true if the database kept by the
+// last driver migration has neither been removed nor restored.
+func (asset *Asset) HasPendingDatabaseDriverMigration() bool {
+	_, pending := dcr.PendingDriverBackup(asset.walletDbPath())
+	return pending
+}
+
+// ConfirmDatabaseDriverMigration deletes the database kept by the last driver
+// migration.
+func (asset *Asset) ConfirmDatabaseDriverMigration() error {
+	return dcr.RemoveDriverBackup(asset.walletDbPath())
+}
+
+// RevertDatabaseDriverMigration restores the database kept by the last driver
+// migration, discarding the migrated database.
+func (asset *Asset) RevertDatabaseDriverMigration() error {
+	if asset.IsConnectedToDecredNetwork() {
+		return errors.New(utils.ErrSyncAlreadyInProgress)
+	}
+
+	if err := asset.CloseWallet(); err != nil {
+		return err
+	}
+
+	err := dcr.RestoreDriverBackup(asset.walletDbPath())
+	if openErr := asset.OpenWallet(); err == nil {
+		err = openErr
+	}
+	return err
+}
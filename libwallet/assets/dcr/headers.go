@@ -0,0 +1,136 @@
+package dcr
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+
+	w "decred.org/dcrwallet/v3/wallet"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/gcs/v4"
+)
+
+// maxSeededHeaders is the number of headers added to the syncing wallet at
+// once, the same batch size the spv syncer uses.
+const maxSeededHeaders = 2000
+
+var (
+	headerSourcesMtx sync.Mutex
+	// headerSources are the wallets that synced with the network, keyed by
+	// the directory of their network. The dcrwallet spv syncer is bound to a
+	// single wallet and stores the headers in the wallet's database, so the
+	// wallets that start syncing copy the headers they miss from them instead
+	// of downloading them again.
+	headerSources = make(map[string]map[*Asset]struct{})
+)
+
+// networkDir returns the directory holding the wallets of the asset's network.
+func (asset *Asset) networkDir() string {
+	return filepath.Dir(asset.DataDir())
+}
+
+// addHeaderSource makes the wallet's headers available to the other wallets
+// of its network.
+func (asset *Asset) addHeaderSource() {
+	headerSourcesMtx.Lock()
+	defer headerSourcesMtx.Unlock()
+
+	dir := asset.networkDir()
+	if headerSources[dir] == nil {
+		headerSources[dir] = make(map[*Asset]struct{})
+	}
+	headerSources[dir][asset] = struct{}{}
+}
+
+// removeHeaderSource stops sharing the wallet's headers, it is called before
+// the wallet is closed.
+func (asset *Asset) removeHeaderSource() {
+	headerSourcesMtx.Lock()
+	defer headerSourcesMtx.Unlock()
+
+	dir := asset.networkDir()
+	delete(headerSources[dir], asset)
+	if len(headerSources[dir]) == 0 {
+		delete(headerSources, dir)
+	}
+}
+
+// bestHeaderSource returns the wallet of the asset's network with the longest
+// main chain that contains the asset's tip block, if it is ahead of the asset.
+func (asset *Asset) bestHeaderSource(ctx context.Context) *Asset {
+	headerSourcesMtx.Lock()
+	sources := make([]*Asset, 0, len(headerSources[asset.networkDir()]))
+	for source := range headerSources[asset.networkDir()] {
+		if source != asset {
+			sources = append(sources, source)
+		}
+	}
+	headerSourcesMtx.Unlock()
+
+	tipHash, tipHeight := asset.Internal().DCR.MainChainTip(ctx)
+	var best *Asset
+	bestHeight := tipHeight
+	for _, source := range sources {
+		sourceWallet := source.Internal().DCR
+		if sourceWallet == nil {
+			continue
+		}
+		_, height := sourceWallet.MainChainTip(ctx)
+		if height <= bestHeight {
+			continue
+		}
+		// Headers are only copied on top of the asset's tip.
+		haveBlock, invalidated, err := sourceWallet.BlockInMainChain(ctx, &tipHash)
+		if err != nil || !haveBlock || invalidated {
+			continue
+		}
+		best, bestHeight = source, height
+	}
+	return best
+}
+
+// seedHeaders copies the headers and cfilters the wallet misses from the
+// wallet of its network that synced the furthest. The spv syncer then only
+// fetches the headers mined since and rescans the copied cfilters without
+// downloading them. The headers were validated when the source wallet synced.
+func (asset *Asset) seedHeaders(ctx context.Context) error {
+	source := asset.bestHeaderSource(ctx)
+	if source == nil {
+		return nil
+	}
+
+	dcrWallet, sourceWallet := asset.Internal().DCR, source.Internal().DCR
+	_, tipHeight := dcrWallet.MainChainTip(ctx)
+	_, sourceHeight := sourceWallet.MainChainTip(ctx)
+	log.Infof("[%d] Copying %d headers from %s", asset.ID, sourceHeight-tipHeight, source.GetWalletName())
+
+	for height := tipHeight + 1; height <= sourceHeight; height += maxSeededHeaders {
+		endHeight := height + maxSeededHeaders - 1
+		if endHeight > sourceHeight {
+			endHeight = sourceHeight
+		}
+
+		chain := make([]*w.BlockNode, 0, endHeight-height+1)
+		err := sourceWallet.RangeCFiltersV2(ctx, w.NewBlockIdentifierFromHeight(height),
+			w.NewBlockIdentifierFromHeight(endHeight),
+			func(hash chainhash.Hash, _ [gcs.KeySize]byte, filter *gcs.FilterV2) (bool, error) {
+				header, err := sourceWallet.BlockHeader(ctx, &hash)
+				if err != nil {
+					return false, err
+				}
+				chain = append(chain, w.NewBlockNode(header, &hash, filter))
+				return false, nil
+			})
+		if err != nil {
+			return err
+		}
+		if len(chain) == 0 {
+			return nil
+		}
+
+		if _, err := dcrWallet.ChainSwitch(ctx, new(w.SidechainForest), chain, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package dcr
+
+import (
+	"encoding/hex"
+	"time"
+
+	"decred.org/dcrwallet/v3/errors"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+// ImportPrivateKey imports the provided WIF encoded private key into the
+// imported account and rescans the blockchain starting from the block mined
+// around the provided birthday. The imported address is returned.
+func (asset *Asset) ImportPrivateKey(wif, privPass string, birthday time.Time) (string, error) {
+	const op errors.Op = "dcr.ImportPrivateKey"
+
+	if !asset.WalletOpened() {
+		return "", utils.ErrDCRNotInitialized
+	}
+
+	if asset.IsWatchingOnlyWallet() {
+		return "", errors.E(op, utils.ErrWalletIsWatchOnly)
+	}
+
+	if !asset.IsSynced() {
+		return "", errors.E(op, utils.ErrNotSynced)
+	}
+
+	decodedWIF, err := dcrutil.DecodeWIF(wif, asset.chainParams.PrivateKeyID)
+	if err != nil {
+		return "", errors.E(op, utils.ErrInvalid, err)
+	}
+
+	err = asset.UnlockWallet(privPass)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+	defer asset.LockWallet()
+
+	ctx, _ := asset.ShutdownContextWithCancel()
+	address, err := asset.Internal().DCR.ImportPrivateKey(ctx, decodedWIF)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+
+	return address, asset.rescanFromBirthday(birthday)
+}
+
+// WatchAddress adds the address of the provided hex encoded compressed public
+// key to the imported account as a watch only address and rescans the
+// blockchain starting from the block mined around the provided birthday. The
+// watched address is returned.
+//
+// Unlike BTC and LTC, plain DCR addresses cannot be watched: the dcrwallet SPV
+// syncer only matches the block cfilters against the addresses held by its
+// address manager, which can't hold an address without its public key.
+func (asset *Asset) WatchAddress(pubKey string, birthday time.Time) (string, error) {
+	const op errors.Op = "dcr.WatchAddress"
+
+	if !asset.WalletOpened() {
+		return "", utils.ErrDCRNotInitialized
+	}
+
+	if !asset.IsSynced() {
+		return "", errors.E(op, utils.ErrNotSynced)
+	}
+
+	pubKeyBytes, err := hex.DecodeString(pubKey)
+	if err != nil {
+		return "", errors.E(op, utils.ErrInvalid, "plain DCR addresses cannot be watched, enter the public key of the address")
+	}
+
+	ctx, _ := asset.ShutdownContextWithCancel()
+	address, err := asset.Internal().DCR.ImportPublicKey(ctx, pubKeyBytes)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+
+	return address, asset.rescanFromBirthday(birthday)
+}
+
+// rescanFromBirthday rescans the blockchain starting from the block mined
+// around the provided birthday.
+func (asset *Asset) rescanFromBirthday(birthday time.Time) error {
+	return asset.RescanBlocksFromTime(birthday)
+}
@@ -15,6 +15,33 @@ func (asset *Asset) RescanBlocks() error {
 	return asset.RescanBlocksFromHeight(0)
 }
 
+// RescanBlocksFromTime rescans the blockchain for all addresses in the wallet
+// starting from the last block mined before t.
+func (asset *Asset) RescanBlocksFromTime(t time.Time) error {
+	height, err := asset.BlockHeightAtTime(t)
+	if err != nil {
+		return err
+	}
+	return asset.RescanBlocksFromHeight(height)
+}
+
+// BlockHeightAtTime returns the height of the last block mined before t,
+// found by searching the synced block headers.
+func (asset *Asset) BlockHeightAtTime(t time.Time) (int32, error) {
+	if !asset.IsSynced() {
+		return 0, errors.E(utils.ErrNotSynced)
+	}
+
+	ctx, _ := asset.ShutdownContextWithCancel()
+	return sharedW.SearchBlockHeightAtTime(t, asset.GetBestBlockHeight(), func(height int32) (int64, error) {
+		info, err := asset.Internal().DCR.BlockInfo(ctx, w.NewBlockIdentifierFromHeight(height))
+		if err != nil {
+			return 0, err
+		}
+		return info.Timestamp, nil
+	})
+}
+
 func (asset *Asset) RescanBlocksFromHeight(startHeight int32) error {
 	netBackend, err := asset.Internal().DCR.NetworkBackend()
 	if err != nil {
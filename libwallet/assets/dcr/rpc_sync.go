@@ -0,0 +1,110 @@
+package dcr
+
+import (
+	"sync/atomic"
+
+	"decred.org/dcrwallet/v3/chain"
+	"decred.org/dcrwallet/v3/errors"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+)
+
+// dcrdRPCPorts are the default RPC ports of dcrd on each network.
+var dcrdRPCPorts = map[string]string{
+	"mainnet":  "9109",
+	"testnet3": "19109",
+	"simnet":   "19556",
+	"regnet":   "18656",
+}
+
+// rpcSync syncs the wallet from the dcrd node in cfg. If the node cannot be
+// reached and cfg allows it, the wallet falls back to SPV sync. Nodes on
+// private hosts are connected to directly and the others through the app's
+// proxy, if any.
+func (asset *Asset) rpcSync(cfg *sharedW.RPCSyncConfig) error {
+	// init activeSyncData to be used to hold data used
+	// to calculate sync estimates only during sync
+	asset.initActiveSyncData()
+
+	asset.waitingForHeaders = true
+	asset.syncing = true
+
+	syncer := chain.NewSyncer(asset.Internal().DCR, &chain.RPCOptions{
+		Address:     cfg.Host,
+		DefaultPort: dcrdRPCPorts[asset.chainParams.Name],
+		User:        cfg.User,
+		Pass:        cfg.Pass,
+		Dial:        utils.NodeDialContext(utils.DCRPeersSubsystem),
+		CA:          []byte(cfg.Certificate),
+		Insecure:    cfg.DisableTLS,
+	})
+
+	// The node counts as the wallet's only peer once the syncer has
+	// verified its network and API version.
+	var connected uint32
+	callbacks := asset.rpcSyncNotificationCallbacks()
+	fetchCFiltersStarted := callbacks.FetchMissingCFiltersStarted
+	callbacks.FetchMissingCFiltersStarted = func() {
+		atomic.StoreUint32(&connected, 1)
+		asset.handlePeerCountUpdate(1)
+		fetchCFiltersStarted()
+	}
+	syncer.SetCallbacks(callbacks)
+
+	ctx, cancel := asset.ShutdownContextWithCancel()
+
+	asset.syncData.mu.Lock()
+	asset.syncData.restartSyncRequested = false
+	asset.syncData.syncing = true
+	asset.syncData.cancelSync = cancel
+	asset.syncData.syncCanceled = make(chan struct{})
+	asset.syncData.mu.Unlock()
+
+	asset.PublishEvent(eventbus.TopicSyncStarted, nil)
+
+	go func() {
+		syncError := syncer.Run(ctx)
+		if atomic.LoadUint32(&connected) == 1 {
+			asset.handlePeerCountUpdate(0)
+		}
+
+		fallback := syncError != nil && ctx.Err() == nil &&
+			atomic.LoadUint32(&connected) == 0 && cfg.FallbackToSPV
+		if fallback {
+			log.Warnf("Unable to sync %s from %s, falling back to SPV sync: %v",
+				asset.GetWalletName(), cfg.Host, syncError)
+		} else {
+			asset.notifySyncEnded(syncError)
+		}
+
+		// Close the syncer channel after the syncer.Run stops.
+		close(asset.syncData.syncCanceled)
+		// reset sync variables
+		asset.resetSyncData()
+
+		if fallback {
+			if err := asset.spvSync(); err != nil {
+				asset.notifySyncError(errors.Errorf("SPV sync fallback failed: %v", err))
+			}
+		}
+	}()
+	return nil
+}
+
+func (asset *Asset) rpcSyncNotificationCallbacks() *chain.Callbacks {
+	return &chain.Callbacks{
+		Synced:                       asset.syncedWallet,
+		FetchHeadersStarted:          asset.fetchHeadersStarted,
+		FetchHeadersProgress:         asset.fetchHeadersProgress,
+		FetchHeadersFinished:         asset.fetchHeadersFinished,
+		FetchMissingCFiltersStarted:  asset.fetchCFiltersStarted,
+		FetchMissingCFiltersProgress: asset.fetchCFiltersProgress,
+		FetchMissingCFiltersFinished: asset.fetchCFiltersEnded,
+		DiscoverAddressesStarted:     asset.discoverAddressesStarted,
+		DiscoverAddressesFinished:    asset.discoverAddressesFinished,
+		RescanStarted:                asset.rescanStarted,
+		RescanProgress:               asset.rescanProgress,
+		RescanFinished:               asset.rescanFinished,
+	}
+}
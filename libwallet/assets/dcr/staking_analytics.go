@@ -0,0 +1,308 @@
+package dcr
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"decred.org/dcrwallet/v3/wallet/udb"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+const daysPerYear = 365
+
+// StakingAnalytics computes the cost, the reward and the return of every
+// ticket of the wallet and aggregates them in total, by month and by VSP.
+func (asset *Asset) StakingAnalytics() (*StakingAnalytics, error) {
+	if !asset.WalletOpened() {
+		return nil, utils.ErrDCRNotInitialized
+	}
+
+	tickets, err := asset.GetTransactionsRaw(0, 0, TxFilterTickets, false)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := asset.ShutdownContextWithCancel()
+	defer cancel()
+
+	analytics := &StakingAnalytics{
+		Total:   new(StakingStats),
+		Tickets: make([]*TicketAnalytics, 0, len(tickets)),
+	}
+	byMonth := make(map[string]*StakingStats)
+	byVSP := make(map[string]*StakingStats)
+	group := func(groups map[string]*StakingStats, key string) *StakingStats {
+		stats, ok := groups[key]
+		if !ok {
+			stats = &StakingStats{Key: key}
+			groups[key] = stats
+		}
+		return stats
+	}
+
+	for _, ticket := range tickets {
+		ta, err := asset.ticketAnalytics(ctx, ticket)
+		if err != nil {
+			return nil, err
+		}
+
+		analytics.Tickets = append(analytics.Tickets, ta)
+		analytics.Total.add(ta)
+		group(byMonth, ta.Month).add(ta)
+		group(byVSP, ta.VSP).add(ta)
+	}
+
+	analytics.Total.finalize()
+	for _, stats := range byMonth {
+		stats.finalize()
+		analytics.ByMonth = append(analytics.ByMonth, stats)
+	}
+	for _, stats := range byVSP {
+		stats.finalize()
+		analytics.ByVSP = append(analytics.ByVSP, stats)
+	}
+	sort.Slice(analytics.ByMonth, func(i, j int) bool {
+		return analytics.ByMonth[i].Key < analytics.ByMonth[j].Key
+	})
+	sort.Slice(analytics.ByVSP, func(i, j int) bool {
+		return analytics.ByVSP[i].Key < analytics.ByVSP[j].Key
+	})
+
+	return analytics, nil
+}
+
+// ticketAnalytics computes the cost and the reward of ticket.
+func (asset *Asset) ticketAnalytics(ctx context.Context, ticket *sharedW.Transaction) (*TicketAnalytics, error) {
+	ta := &TicketAnalytics{
+		TicketHash:   ticket.Hash,
+		PurchaseTime: ticket.Timestamp,
+		TicketFee:    ticket.Fee,
+	}
+	if len(ticket.Outputs) > 0 {
+		ta.TicketPrice = ticket.Outputs[0].Amount
+	}
+
+	bestBlock := asset.GetBestBlockHeight()
+	maturity, expiry := asset.TicketMaturity(), asset.TicketExpiry()
+	expiryHeight := ticket.BlockHeight + maturity + expiry
+
+	spender, err := asset.TicketSpender(ticket.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case spender != nil:
+		ta.SpenderHash = spender.Hash
+		ta.SpendTime = spender.Timestamp
+		// The vote reward is computed against the ticket inputs, which
+		// pay for the ticket fee too.
+		ta.Reward = spender.VoteReward + ta.TicketFee
+		ta.DaysToVote = float64(spender.Timestamp-ticket.Timestamp) / (24 * time.Hour).Seconds()
+		spendHeight := spender.BlockHeight
+		if spendHeight <= 0 {
+			spendHeight = bestBlock
+		}
+		switch {
+		case spender.Type == TxTypeVote:
+			ta.Status = TicketStatusVoted
+		case spendHeight > expiryHeight:
+			ta.Status = TicketStatusExpired
+		default:
+			ta.Status = TicketStatusMissed
+		}
+	case ticket.BlockHeight <= 0:
+		ta.Status = TicketStatusUnmined
+	case bestBlock-ticket.BlockHeight < maturity:
+		ta.Status = TicketStatusImmature
+	case bestBlock > expiryHeight:
+		ta.Status = TicketStatusExpired
+	default:
+		ta.Status = TicketStatusLive
+	}
+
+	ta.SplitFee = asset.ticketSplitFee(ticket)
+	ta.VSP, ta.VSPFee = asset.ticketVSPFee(ctx, ticket.Hash)
+	ta.NetReward = ta.Reward - ta.TicketFee - ta.SplitFee - ta.VSPFee
+	if spender != nil && ta.TicketPrice > 0 {
+		ta.ROI = float64(ta.NetReward) / float64(ta.TicketPrice)
+		if ta.DaysToVote > 0 {
+			ta.AnnualizedReturn = ta.ROI * daysPerYear / ta.DaysToVote
+		}
+	}
+
+	month := ta.PurchaseTime
+	if ta.SpendTime > 0 {
+		month = ta.SpendTime
+	}
+	ta.Month = time.Unix(month, 0).UTC().Format("2006-01")
+
+	return ta, nil
+}
+
+// ticketSplitFee returns the share of the fee of the split tx funding ticket.
+// The fee is shared by the split outputs of the ticket price, 0 is returned if
+// the ticket was not funded by a split tx of the wallet.
+func (asset *Asset) ticketSplitFee(ticket *sharedW.Transaction) int64 {
+	if len(ticket.Inputs) == 0 {
+		return 0
+	}
+
+	input := ticket.Inputs[0]
+	var splitTx sharedW.Transaction
+	err := asset.GetWalletDataDb().FindOne("Hash", input.PreviousTransactionHash, &splitTx)
+	if err != nil || splitTx.Type != TxTypeRegular {
+		return 0
+	}
+
+	var splitOutputs int64
+	for _, output := range splitTx.Outputs {
+		if output.Amount == input.Amount {
+			splitOutputs++
+		}
+	}
+	if splitOutputs == 0 {
+		return 0
+	}
+	return splitTx.Fee / splitOutputs
+}
+
+// ticketVSPFee returns the VSP of the ticket and the fee paid to it. Solo
+// tickets and tickets whose fee was not paid return no fee.
+func (asset *Asset) ticketVSPFee(ctx context.Context, ticketHash string) (string, int64) {
+	hash, err := chainhash.NewHashFromStr(ticketHash)
+	if err != nil {
+		return "", 0
+	}
+
+	info, err := asset.Internal().DCR.VSPTicketInfo(ctx, hash)
+	if err != nil {
+		return "", 0
+	}
+	if status := udb.FeeStatus(info.FeeTxStatus); status != udb.VSPFeeProcessPaid && status != udb.VSPFeeProcessConfirmed {
+		return info.Host, 0
+	}
+
+	feeTx, err := asset.GetTransactionRaw(info.FeeHash.String())
+	if err != nil {
+		log.Warnf("unable to read the vsp fee tx of ticket %s: %v", ticketHash, err)
+		return info.Host, 0
+	}
+
+	// The fee is the amount paid out of the wallet, i.e. the outputs to the
+	// VSP and the tx fee.
+	fee := feeTx.Fee
+	for _, output := range feeTx.Outputs {
+		if output.AccountNumber < 0 {
+			fee += output.Amount
+		}
+	}
+	return info.Host, fee
+}
+
+// add accounts for ta in the stats.
+func (stats *StakingStats) add(ta *TicketAnalytics) {
+	stats.Tickets++
+	stats.Invested += ta.TicketPrice
+	stats.TicketFees += ta.TicketFee + ta.SplitFee
+	stats.VSPFees += ta.VSPFee
+	stats.Rewards += ta.Reward
+	stats.NetRewards += ta.NetReward
+
+	switch ta.Status {
+	case TicketStatusVoted:
+		stats.Voted++
+	case TicketStatusMissed:
+		stats.Missed++
+		stats.Revoked++
+	case TicketStatusExpired:
+		stats.Expired++
+		if ta.SpenderHash != "" {
+			stats.Revoked++
+		}
+	}
+
+	if ta.SpenderHash != "" {
+		stats.spentInvested += ta.TicketPrice
+		stats.spentNetRewards += ta.NetReward
+		stats.investedDays += float64(ta.TicketPrice) * ta.DaysToVote
+	}
+	if ta.Status == TicketStatusVoted {
+		stats.daysToVoteSum += ta.DaysToVote
+	}
+}
+
+// finalize computes the averages and the returns of the stats once all the
+// tickets are added.
+func (stats *StakingStats) finalize() {
+	if stats.Voted > 0 {
+		stats.AverageDaysToVote = stats.daysToVoteSum / float64(stats.Voted)
+	}
+	if stats.spentInvested > 0 {
+		stats.ROI = float64(stats.spentNetRewards) / float64(stats.spentInvested)
+	}
+	if stats.investedDays > 0 {
+		// The returns are weighted by the amount invested and the time it
+		// was locked in the tickets.
+		stats.AnnualizedReturn = float64(stats.spentNetRewards) * daysPerYear / stats.investedDays
+	}
+}
+
+// ExportStakingAnalyticsCSV writes the analytics of every ticket to w in the
+// CSV format.
+func ExportStakingAnalyticsCSV(w io.Writer, analytics *StakingAnalytics) error {
+	formatTime := func(timestamp int64) string {
+		if timestamp <= 0 {
+			return ""
+		}
+		return time.Unix(timestamp, 0).UTC().Format(time.RFC3339)
+	}
+	formatAmount := func(amount int64) string {
+		return strconv.FormatFloat(dcrutil.Amount(amount).ToCoin(), 'f', -1, 64)
+	}
+	formatFloat := func(f float64) string {
+		return strconv.FormatFloat(f, 'f', 6, 64)
+	}
+
+	csvWriter := csv.NewWriter(w)
+	err := csvWriter.Write([]string{"ticket", "spender", "status", "vsp", "month", "purchase_time",
+		"spend_time", "ticket_price", "ticket_fee", "split_fee", "vsp_fee", "reward", "net_reward", "days_to_vote",
+		"roi", "annualized_return"})
+	if err != nil {
+		return err
+	}
+
+	for _, ta := range analytics.Tickets {
+		err = csvWriter.Write([]string{
+			ta.TicketHash,
+			ta.SpenderHash,
+			ta.Status,
+			ta.VSP,
+			ta.Month,
+			formatTime(ta.PurchaseTime),
+			formatTime(ta.SpendTime),
+			formatAmount(ta.TicketPrice),
+			formatAmount(ta.TicketFee),
+			formatAmount(ta.SplitFee),
+			formatAmount(ta.VSPFee),
+			formatAmount(ta.Reward),
+			formatAmount(ta.NetReward),
+			formatFloat(ta.DaysToVote),
+			formatFloat(ta.ROI),
+			formatFloat(ta.AnnualizedReturn),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
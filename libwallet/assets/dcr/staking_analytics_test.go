@@ -0,0 +1,131 @@
+package dcr
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestStakingStats(t *testing.T) {
+	voted := &TicketAnalytics{Status: TicketStatusVoted, SpenderHash: "vote", TicketPrice: 1000, TicketFee: 1,
+		SplitFee: 1, VSPFee: 2, Reward: 14, NetReward: 10, DaysToVote: 36.5}
+	missed := &TicketAnalytics{Status: TicketStatusMissed, SpenderHash: "revocation", TicketPrice: 1000,
+		TicketFee: 1, VSPFee: 2, Reward: 1, NetReward: -2, DaysToVote: 73}
+	revokedExpired := &TicketAnalytics{Status: TicketStatusExpired, SpenderHash: "revocation", TicketPrice: 1000,
+		TicketFee: 1, Reward: 1, NetReward: -1, DaysToVote: 146}
+	expired := &TicketAnalytics{Status: TicketStatusExpired, TicketPrice: 1000, TicketFee: 1, NetReward: -1}
+	live := &TicketAnalytics{Status: TicketStatusLive, TicketPrice: 1000, TicketFee: 1, NetReward: -1}
+
+	tests := []struct {
+		name     string
+		tickets  []*TicketAnalytics
+		expected StakingStats
+	}{
+		{name: "no tickets"},
+		{
+			name:    "voted",
+			tickets: []*TicketAnalytics{voted},
+			expected: StakingStats{Tickets: 1, Voted: 1, Invested: 1000, TicketFees: 2, VSPFees: 2, Rewards: 14,
+				NetRewards: 10, AverageDaysToVote: 36.5, ROI: 0.01, AnnualizedReturn: 0.1},
+		},
+		{
+			name:    "missed tickets are revoked",
+			tickets: []*TicketAnalytics{missed},
+			expected: StakingStats{Tickets: 1, Missed: 1, Revoked: 1, Invested: 1000, TicketFees: 1, VSPFees: 2,
+				Rewards: 1, NetRewards: -2, ROI: -0.002, AnnualizedReturn: -0.01},
+		},
+		{
+			name:    "expired tickets",
+			tickets: []*TicketAnalytics{revokedExpired, expired},
+			expected: StakingStats{Tickets: 2, Expired: 2, Revoked: 1, Invested: 2000, TicketFees: 2, Rewards: 1,
+				NetRewards: -2, ROI: -0.001, AnnualizedReturn: -0.0025},
+		},
+		{
+			name:     "unspent tickets have no return",
+			tickets:  []*TicketAnalytics{live},
+			expected: StakingStats{Tickets: 1, Invested: 1000, TicketFees: 1, NetRewards: -1},
+		},
+		{
+			name:    "returns weighted by amount and time",
+			tickets: []*TicketAnalytics{voted, missed, live},
+			expected: StakingStats{Tickets: 3, Voted: 1, Missed: 1, Revoked: 1, Invested: 3000, TicketFees: 4,
+				VSPFees: 4, Rewards: 15, NetRewards: 7, AverageDaysToVote: 36.5, ROI: 0.004,
+				AnnualizedReturn: 8 * daysPerYear / (1000*36.5 + 1000*73)},
+		},
+	}
+
+	floatsEqual := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			stats := new(StakingStats)
+			for _, ta := range tc.tickets {
+				stats.add(ta)
+			}
+			stats.finalize()
+
+			got, expected := *stats, tc.expected
+			if !floatsEqual(got.AverageDaysToVote, expected.AverageDaysToVote) ||
+				!floatsEqual(got.ROI, expected.ROI) || !floatsEqual(got.AnnualizedReturn, expected.AnnualizedReturn) {
+				t.Errorf("(%v), expected (%+v), got (%+v)", tc.name, expected, got)
+			}
+
+			// The floats are compared above, the unexported sums are
+			// intermediate values.
+			got.AverageDaysToVote, got.ROI, got.AnnualizedReturn = 0, 0, 0
+			expected.AverageDaysToVote, expected.ROI, expected.AnnualizedReturn = 0, 0, 0
+			got.spentInvested, got.spentNetRewards, got.investedDays, got.daysToVoteSum = 0, 0, 0, 0
+			if got != expected {
+				t.Errorf("(%v), expected (%+v), got (%+v)", tc.name, expected, got)
+			}
+		})
+	}
+}
+
+func TestExportStakingAnalyticsCSV(t *testing.T) {
+	purchase := time.Date(2024, time.January, 5, 10, 0, 0, 0, time.UTC).Unix()
+	analytics := &StakingAnalytics{
+		Tickets: []*TicketAnalytics{
+			{
+				TicketHash:       "ticket1",
+				SpenderHash:      "vote1",
+				Status:           TicketStatusVoted,
+				VSP:              "https://vsp.example.com",
+				Month:            "2024-02",
+				PurchaseTime:     purchase,
+				SpendTime:        purchase + 36*24*3600,
+				TicketPrice:      100e8,
+				TicketFee:        2980,
+				SplitFee:         1000,
+				VSPFee:           0.2e8,
+				Reward:           0.5e8,
+				NetReward:        0.5e8 - 2980 - 1000 - 0.2e8,
+				DaysToVote:       36,
+				ROI:              0.002999602,
+				AnnualizedReturn: 0.002999602 * daysPerYear / 36,
+			},
+			{
+				TicketHash:   "ticket2",
+				Status:       TicketStatusLive,
+				Month:        "2024-01",
+				PurchaseTime: purchase,
+				TicketPrice:  100e8,
+				TicketFee:    2980,
+			},
+		},
+	}
+
+	expected := "ticket,spender,status,vsp,month,purchase_time,spend_time,ticket_price,ticket_fee,split_fee," +
+		"vsp_fee,reward,net_reward,days_to_vote,roi,annualized_return\n" +
+		"ticket1,vote1,voted,https://vsp.example.com,2024-02,2024-01-05T10:00:00Z,2024-02-10T10:00:00Z,100," +
+		"0.0000298,0.00001,0.2,0.5,0.2999602,36.000000,0.003000,0.030413\n" +
+		"ticket2,,live,,2024-01,2024-01-05T10:00:00Z,,100,0.0000298,0,0,0,0,0.000000,0.000000,0.000000\n"
+
+	var buf bytes.Buffer
+	if err := ExportStakingAnalyticsCSV(&buf, analytics); err != nil {
+		t.Fatalf("ExportStakingAnalyticsCSV: %v", err)
+	}
+	if got := buf.String(); got != expected {
+		t.Errorf("expected (%v), got (%v)", expected, got)
+	}
+}
@@ -6,7 +6,7 @@ import (
 	"fmt"
 	"net"
 	"sort"
-	"strings"
+	"strconv"
 	"sync"
 
 	"decred.org/dcrwallet/v3/errors"
@@ -14,16 +14,18 @@ import (
 	"decred.org/dcrwallet/v3/spv"
 	w "decred.org/dcrwallet/v3/wallet"
 	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
 	"github.com/crypto-power/cryptopower/libwallet/utils"
 	"github.com/decred/dcrd/addrmgr/v2"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
 )
 
 // reading/writing of properties of this struct are protected by mutex.x
 type SyncData struct {
 	mu sync.RWMutex
 
-	syncProgressListeners map[string]*sharedW.SyncProgressListener
-	showLogs              bool
+	showLogs bool
 
 	synced       bool
 	syncing      bool
@@ -45,7 +47,8 @@ type SyncData struct {
 
 // reading/writing of properties of this struct are protected by syncData.mu.
 type activeSyncData struct {
-	syncer *spv.Syncer
+	syncer     *spv.Syncer
+	peerDialer *sharedW.PeerDialer
 
 	syncStage utils.SyncStage
 
@@ -104,75 +107,6 @@ func (asset *Asset) initActiveSyncData() {
 	asset.syncData.mu.Unlock()
 }
 
-func (asset *Asset) IsSyncProgressListenerRegisteredFor(uniqueIdentifier string) bool {
-	asset.syncData.mu.RLock()
-	_, exists := asset.syncData.syncProgressListeners[uniqueIdentifier]
-	asset.syncData.mu.RUnlock()
-	return exists
-}
-
-func (asset *Asset) AddSyncProgressListener(syncProgressListener *sharedW.SyncProgressListener, uniqueIdentifier string) error {
-	if asset.IsSyncProgressListenerRegisteredFor(uniqueIdentifier) {
-		return errors.New(utils.ErrListenerAlreadyExist)
-	}
-
-	asset.syncData.mu.Lock()
-	asset.syncData.syncProgressListeners[uniqueIdentifier] = syncProgressListener
-	asset.syncData.mu.Unlock()
-
-	// If sync is already on, notify this newly added listener of the current progress report.
-	return asset.PublishLastSyncProgress(uniqueIdentifier)
-}
-
-func (asset *Asset) RemoveSyncProgressListener(uniqueIdentifier string) {
-	asset.syncData.mu.Lock()
-	delete(asset.syncData.syncProgressListeners, uniqueIdentifier)
-	asset.syncData.mu.Unlock()
-}
-
-func (asset *Asset) syncProgressListeners() []*sharedW.SyncProgressListener {
-	asset.syncData.mu.RLock()
-	defer asset.syncData.mu.RUnlock()
-
-	listeners := make([]*sharedW.SyncProgressListener, 0, len(asset.syncData.syncProgressListeners))
-	for _, listener := range asset.syncData.syncProgressListeners {
-		listeners = append(listeners, listener)
-	}
-
-	return listeners
-}
-
-func (asset *Asset) PublishLastSyncProgress(uniqueIdentifier string) error {
-	asset.syncData.mu.RLock()
-	defer asset.syncData.mu.RUnlock()
-
-	syncProgressListener, exists := asset.syncData.syncProgressListeners[uniqueIdentifier]
-	if !exists {
-		return errors.New(utils.ErrInvalid)
-	}
-
-	if asset.syncData.syncing && asset.syncData.activeSyncData != nil {
-		switch asset.syncData.activeSyncData.syncStage {
-		case HeadersFetchSyncStage:
-			if syncProgressListener.OnHeadersFetchProgress != nil {
-				syncProgressListener.OnHeadersFetchProgress(&asset.syncData.headersFetchProgress)
-			}
-
-		case AddressDiscoverySyncStage:
-			if syncProgressListener.OnAddressDiscoveryProgress != nil {
-				syncProgressListener.OnAddressDiscoveryProgress(&asset.syncData.addressDiscoveryProgress)
-			}
-
-		case HeadersRescanSyncStage:
-			if syncProgressListener.OnHeadersRescanProgress != nil {
-				syncProgressListener.OnHeadersRescanProgress(&asset.syncData.headersRescanProgress)
-			}
-		}
-	}
-
-	return nil
-}
-
 func (asset *Asset) EnableSyncLogs() {
 	asset.syncData.mu.Lock()
 	asset.syncData.showLogs = true
@@ -195,42 +129,68 @@ func (asset *Asset) SyncInactiveForPeriod(totalInactiveSeconds int64) {
 	}
 }
 
+// SetSpecificPeer makes address the only peer the wallet connects to.
 func (asset *Asset) SetSpecificPeer(address string) {
-	asset.SaveUserConfigValue(sharedW.SpvPersistentPeerAddressesConfigKey, address)
-	asset.RestartSpvSync()
+	cfg := asset.PeerConfig()
+	cfg.Persistent = []string{address}
+	if err := asset.SetPeerConfig(cfg); err != nil {
+		log.Error(err)
+	}
 }
 
+// RemovePeers removes the persistent peers of the wallet.
 func (asset *Asset) RemovePeers() {
-	asset.SaveUserConfigValue(sharedW.SpvPersistentPeerAddressesConfigKey, "")
-	asset.RestartSpvSync()
+	cfg := asset.PeerConfig()
+	cfg.Persistent = nil
+	if err := asset.SetPeerConfig(cfg); err != nil {
+		log.Error(err)
+	}
+}
+
+// SetPeerConfig saves the peer configuration of the wallet and restarts the
+// sync, if the wallet is syncing, to apply it.
+func (asset *Asset) SetPeerConfig(cfg *sharedW.PeerConfig) error {
+	if err := asset.SavePeerConfig(cfg); err != nil {
+		return err
+	}
+	if asset.IsSyncing() || asset.IsSynced() {
+		return asset.RestartSpvSync()
+	}
+	return nil
 }
 
+// SpvSync connects the wallet to the network. Wallets configured to sync from
+// a full node use RPC sync, others use SPV.
 func (asset *Asset) SpvSync() error {
 	// prevent an attempt to sync when the previous syncing has not been canceled
 	if asset.IsSyncing() || asset.IsSynced() {
 		return errors.New(utils.ErrSyncAlreadyInProgress)
 	}
 
-	addr := &net.TCPAddr{IP: net.ParseIP("::1"), Port: 0}
-	addrManager := addrmgr.New(asset.DataDir(), net.LookupIP) // TODO: be mindful of tor
-	lp := p2p.NewLocalPeer(asset.chainParams, addr, addrManager)
+	if cfg := asset.RPCSyncConfig(); cfg != nil {
+		return asset.rpcSync(cfg)
+	}
+	return asset.spvSync()
+}
 
-	var validPeerAddresses []string
-	peerAddresses := asset.ReadStringConfigValueForKey(sharedW.SpvPersistentPeerAddressesConfigKey, "")
-	if peerAddresses != "" {
-		addresses := strings.Split(peerAddresses, ";")
-		for _, address := range addresses {
-			peerAddress, err := utils.NormalizeAddress(address, asset.chainParams.DefaultPort)
-			if err != nil {
-				log.Errorf("SPV peer address(%s) is invalid: %v", peerAddress, err)
-			} else {
-				validPeerAddresses = append(validPeerAddresses, peerAddress)
-			}
-		}
+func (asset *Asset) spvSync() error {
+	peerCfg, err := asset.ValidPeerConfig()
+	if err != nil {
+		return errors.E(utils.ErrInvalidPeers, err)
+	}
 
-		if len(validPeerAddresses) == 0 {
-			return errors.New(utils.ErrInvalidPeers)
-		}
+	addr := &net.TCPAddr{IP: net.ParseIP("::1"), Port: 0}
+	addrManager := addrmgr.New(asset.DataDir(), utils.ProxyLookupIP)
+	lp := p2p.NewLocalPeer(asset.chainParams, addr, addrManager)
+	peerDialer := sharedW.NewPeerDialer(utils.DCRPeersSubsystem, peerCfg.Bans)
+	lp.SetDialFunc(peerDialer.DialContext)
+
+	if len(peerCfg.Preferred) > 0 {
+		// The address manager loads the known addresses when it starts, so
+		// it is started before the preferred peers are marked as good, which
+		// makes it favour them. Starting it again in the syncer is a no-op.
+		addrManager.Start()
+		asset.addPreferredPeers(addrManager, peerCfg.Preferred)
 	}
 
 	// init activeSyncData to be used to hold data used
@@ -242,8 +202,8 @@ func (asset *Asset) SpvSync() error {
 
 	syncer := spv.NewSyncer(asset.Internal().DCR, lp)
 	syncer.SetNotifications(asset.spvSyncNotificationCallbacks())
-	if len(validPeerAddresses) > 0 {
-		syncer.SetPersistentPeers(validPeerAddresses)
+	if len(peerCfg.Persistent) > 0 {
+		syncer.SetPersistentPeers(peerCfg.Persistent)
 	}
 
 	ctx, cancel := asset.ShutdownContextWithCancel()
@@ -254,29 +214,29 @@ func (asset *Asset) SpvSync() error {
 	asset.syncData.cancelSync = cancel
 	asset.syncData.syncCanceled = make(chan struct{})
 	asset.syncData.syncer = syncer
+	asset.syncData.peerDialer = peerDialer
 	asset.syncData.mu.Unlock()
 
-	for _, listener := range asset.syncProgressListeners() {
-		if listener.OnSyncStarted != nil {
-			listener.OnSyncStarted()
-		}
-	}
+	asset.PublishEvent(eventbus.TopicSyncStarted, nil)
 
 	// syncer.Run uses a wait group to block the thread until the sync context
 	// expires or is canceled or some other error occurs such as
 	// losing connection to all persistent peers.
+	asset.addHeaderSource()
+
 	go func() {
+		// Headers already downloaded by another wallet of the network are
+		// copied instead of being fetched again.
+		if err := asset.seedHeaders(ctx); err != nil {
+			log.Warnf("[%d] Copying the headers of another wallet failed: %v", asset.ID, err)
+		}
+
 		syncError := syncer.Run(ctx)
+		// The syncer stops the address manager unless it failed before
+		// starting it, stopping it again is a no-op.
+		addrManager.Stop()
 		// sync has ended or errored
-		if syncError != nil {
-			if syncError == context.DeadlineExceeded {
-				asset.notifySyncError(errors.Errorf("SPV synchronization deadline exceeded: %v", syncError))
-			} else if syncError == context.Canceled {
-				asset.notifySyncCanceled()
-			} else {
-				asset.notifySyncError(syncError)
-			}
-		}
+		asset.notifySyncEnded(syncError)
 
 		// Close the syncer channel after the syncer.Run stops.
 		close(asset.syncData.syncCanceled)
@@ -286,6 +246,43 @@ func (asset *Asset) SpvSync() error {
 	return nil
 }
 
+// addPreferredPeers adds the preferred peers to the started address manager
+// as known good addresses.
+func (asset *Asset) addPreferredPeers(addrManager *addrmgr.AddrManager, peers []string) {
+	for _, peer := range peers {
+		host, portStr, _ := net.SplitHostPort(peer)
+		port, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil {
+			log.Errorf("Preferred peer %s has an invalid port: %v", peer, err)
+			continue
+		}
+		na, err := addrManager.HostToNetAddress(host, uint16(port), wire.SFNodeNetwork)
+		if err != nil {
+			log.Errorf("Resolving preferred peer %s failed: %v", peer, err)
+			continue
+		}
+		addrManager.AddAddresses([]*addrmgr.NetAddress{na}, na)
+		if err := addrManager.Good(na); err != nil {
+			log.Errorf("Adding preferred peer %s failed: %v", peer, err)
+		}
+	}
+}
+
+// notifySyncEnded notifies the sync progress listeners of the error the
+// syncer returned, if any.
+func (asset *Asset) notifySyncEnded(syncError error) {
+	if syncError == nil {
+		return
+	}
+	if errors.Is(syncError, context.DeadlineExceeded) {
+		asset.notifySyncError(errors.Errorf("synchronization deadline exceeded: %v", syncError))
+	} else if errors.Is(syncError, context.Canceled) {
+		asset.notifySyncCanceled()
+	} else {
+		asset.notifySyncError(syncError)
+	}
+}
+
 func (asset *Asset) RestartSpvSync() error {
 	asset.syncData.mu.Lock()
 	asset.syncData.restartSyncRequested = true
@@ -392,10 +389,18 @@ func (asset *Asset) PeerInfoRaw() ([]sharedW.PeerInfo, error) {
 		return nil, errors.New(utils.ErrNotConnected)
 	}
 
-	syncer := asset.syncData.syncer
+	asset.syncData.mu.RLock()
+	syncer, peerDialer := asset.syncData.syncer, asset.syncData.peerDialer
+	asset.syncData.mu.RUnlock()
+	if syncer == nil {
+		// RPC sync has no peers.
+		return []sharedW.PeerInfo{}, nil
+	}
 
-	infos := make([]sharedW.PeerInfo, 0, len(syncer.GetRemotePeers()))
-	for _, rp := range syncer.GetRemotePeers() {
+	recentBlocks := asset.recentMainChainBlocks(peerAnnouncedBlocksDepth)
+	remotePeers := syncer.GetRemotePeers()
+	infos := make([]sharedW.PeerInfo, 0, len(remotePeers))
+	for _, rp := range remotePeers {
 		info := sharedW.PeerInfo{
 			ID:             int32(rp.ID()),
 			Addr:           rp.RemoteAddr().String(),
@@ -405,6 +410,22 @@ func (asset *Asset) PeerInfoRaw() ([]sharedW.PeerInfo, error) {
 			SubVer:         rp.UA(),
 			StartingHeight: int64(rp.InitialHeight()),
 			BanScore:       int32(rp.BanScore()),
+			BestHeight:     int64(rp.InitialHeight()),
+		}
+		// The best height of the peer is the newest main chain block it
+		// announced since the handshake.
+		for _, block := range recentBlocks {
+			if rp.KnownHeaders().Contains(block.hash) {
+				if int64(block.height) > info.BestHeight {
+					info.BestHeight = int64(block.height)
+				}
+				break
+			}
+		}
+		if stats, ok := peerDialer.Stats(info.Addr); ok {
+			info.LatencyMs = stats.Latency.Milliseconds()
+			info.BytesSent = stats.BytesSent
+			info.BytesReceived = stats.BytesReceived
 		}
 
 		infos = append(infos, info)
@@ -417,6 +438,33 @@ func (asset *Asset) PeerInfoRaw() ([]sharedW.PeerInfo, error) {
 	return infos, nil
 }
 
+// peerAnnouncedBlocksDepth is the number of most recent main chain blocks
+// looked up in the headers announced by the peers to find their best height.
+const peerAnnouncedBlocksDepth = 16
+
+type mainChainBlock struct {
+	hash   chainhash.Hash
+	height int32
+}
+
+// recentMainChainBlocks returns up to depth main chain blocks, from the tip
+// backwards.
+func (asset *Asset) recentMainChainBlocks(depth int) []mainChainBlock {
+	ctx, _ := asset.ShutdownContextWithCancel()
+	hash, height := asset.Internal().DCR.MainChainTip(ctx)
+	blocks := make([]mainChainBlock, 0, depth)
+	for len(blocks) < depth && height >= 0 {
+		blocks = append(blocks, mainChainBlock{hash: hash, height: height})
+		header, err := asset.Internal().DCR.BlockHeader(ctx, &hash)
+		if err != nil {
+			log.Errorf("block header %v error: %v", hash, err)
+			break
+		}
+		hash, height = header.PrevBlock, height-1
+	}
+	return blocks
+}
+
 func (asset *Asset) PeerInfo() (string, error) {
 	infos, err := asset.PeerInfoRaw()
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"decred.org/dcrwallet/v3/spv"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -37,11 +38,7 @@ func (asset *Asset) handlePeerCountUpdate(peerCount int32) {
 	shouldLog := asset.syncData.showLogs && asset.syncData.syncing
 	asset.syncData.mu.Unlock()
 
-	for _, syncProgressListener := range asset.syncProgressListeners() {
-		if syncProgressListener.OnPeerConnectedOrDisconnected != nil {
-			syncProgressListener.OnPeerConnectedOrDisconnected(peerCount)
-		}
-	}
+	asset.PublishPeersChanged(peerCount)
 
 	if shouldLog {
 		if peerCount == 1 {
@@ -122,14 +119,8 @@ func (asset *Asset) fetchCFiltersProgress(startCFiltersHeight, endCFiltersHeight
 
 func (asset *Asset) publishFetchCFiltersProgress() {
 	asset.syncData.mu.RLock()
-	cfilters := &asset.syncData.cfiltersFetchProgress
+	asset.PublishSyncProgress(&asset.syncData.cfiltersFetchProgress)
 	asset.syncData.mu.RUnlock()
-
-	for _, syncProgressListener := range asset.syncProgressListeners() {
-		if syncProgressListener.OnCFiltersFetchProgress != nil {
-			syncProgressListener.OnCFiltersFetchProgress(cfilters)
-		}
-	}
 }
 
 func (asset *Asset) fetchCFiltersEnded() {
@@ -241,14 +232,8 @@ func (asset *Asset) fetchHeadersProgress(lastFetchedHeaderHeight int32, _ int64)
 
 func (asset *Asset) publishFetchHeadersProgress() {
 	asset.syncData.mu.RLock()
-	headerFetch := &asset.syncData.headersFetchProgress
+	asset.PublishSyncProgress(&asset.syncData.headersFetchProgress)
 	asset.syncData.mu.RUnlock()
-
-	for _, syncProgressListener := range asset.syncProgressListeners() {
-		if syncProgressListener.OnHeadersFetchProgress != nil {
-			syncProgressListener.OnHeadersFetchProgress(headerFetch)
-		}
-	}
 }
 
 func (asset *Asset) fetchHeadersFinished() {
@@ -391,11 +376,9 @@ func (asset *Asset) updateAddressDiscoveryProgress(totalHeadersFetchTime float64
 }
 
 func (asset *Asset) publishAddressDiscoveryProgress() {
-	for _, syncProgressListener := range asset.syncProgressListeners() {
-		if syncProgressListener.OnAddressDiscoveryProgress != nil {
-			syncProgressListener.OnAddressDiscoveryProgress(&asset.syncData.addressDiscoveryProgress)
-		}
-	}
+	asset.syncData.mu.RLock()
+	asset.PublishSyncProgress(&asset.syncData.addressDiscoveryProgress)
+	asset.syncData.mu.RUnlock()
 }
 
 func (asset *Asset) discoverAddressesFinished() {
@@ -501,14 +484,8 @@ func (asset *Asset) rescanProgress(rescannedThrough int32) {
 
 func (asset *Asset) publishHeadersRescanProgress() {
 	asset.syncData.mu.RLock()
-	headersRescan := &asset.syncData.headersRescanProgress
+	asset.PublishSyncProgress(&asset.syncData.headersRescanProgress)
 	asset.syncData.mu.RUnlock()
-
-	for _, syncProgressListener := range asset.syncProgressListeners() {
-		if syncProgressListener.OnHeadersRescanProgress != nil {
-			syncProgressListener.OnHeadersRescanProgress(headersRescan)
-		}
-	}
 }
 
 func (asset *Asset) rescanFinished() {
@@ -544,11 +521,8 @@ func (asset *Asset) estimateBlockHeadersCountAfter(lastHeaderTime int64) int32 {
 }
 
 func (asset *Asset) notifySyncError(err error) {
-	for _, syncProgressListener := range asset.syncProgressListeners() {
-		if syncProgressListener.OnSyncEndedWithError != nil {
-			syncProgressListener.OnSyncEndedWithError(err)
-		}
-	}
+	asset.ClearSyncProgress()
+	asset.PublishEvent(eventbus.TopicSyncEndedWithError, err)
 }
 
 func (asset *Asset) notifySyncCanceled() {
@@ -556,11 +530,8 @@ func (asset *Asset) notifySyncCanceled() {
 	restartSyncRequested := asset.syncData.restartSyncRequested
 	asset.syncData.mu.RUnlock()
 
-	for _, syncProgressListener := range asset.syncProgressListeners() {
-		if syncProgressListener.OnSyncCanceled != nil {
-			syncProgressListener.OnSyncCanceled(restartSyncRequested)
-		}
-	}
+	asset.ClearSyncProgress()
+	asset.PublishEvent(eventbus.TopicSyncCanceled, restartSyncRequested)
 }
 
 func (asset *Asset) resetSyncData() {
@@ -586,7 +557,7 @@ func (asset *Asset) syncedWallet(synced bool) {
 
 	indexTransactions := func() {
 		// begin indexing transactions after sync is completed,
-		// syncProgressListeners.OnSynced() will be invoked after transactions are indexed
+		// the sync completed event is published after transactions are indexed
 		txIndexing, _ := errgroup.WithContext(ctx)
 		txIndexing.Go(asset.IndexTransactions)
 
@@ -596,16 +567,11 @@ func (asset *Asset) syncedWallet(synced bool) {
 				log.Errorf("Tx Index Error: %v", err)
 			}
 
-			for _, syncProgressListener := range asset.syncProgressListeners() {
-				if synced {
-					if syncProgressListener.OnSyncCompleted != nil {
-						syncProgressListener.OnSyncCompleted()
-					}
-				} else {
-					if syncProgressListener.OnSyncCanceled != nil {
-						syncProgressListener.OnSyncCanceled(false)
-					}
-				}
+			asset.ClearSyncProgress()
+			if synced {
+				asset.PublishEvent(eventbus.TopicSyncCompleted, nil)
+			} else {
+				asset.PublishEvent(eventbus.TopicSyncCanceled, false)
 			}
 		}()
 	}
@@ -224,7 +224,7 @@ func (asset *Asset) VSPTicketInfo(hash string) (*VSPTicketInfo, error) {
 
 // StartTicketBuyer starts the automatic ticket buyer. The wallet
 // should already be configured with the required parameters using
-// asset.SetAutoTicketsBuyerConfig().
+// asset.SetAutoTicketsBuyerConfig() or asset.SaveTicketBuyerConfig().
 func (asset *Asset) StartTicketBuyer(passphrase string) error {
 	if !asset.WalletOpened() {
 		return utils.ErrDCRNotInitialized
@@ -234,8 +234,8 @@ func (asset *Asset) StartTicketBuyer(passphrase string) error {
 	if cfg.VspHost == "" {
 		return errors.New("ticket buyer config not set for this wallet")
 	}
-	if cfg.BalanceToMaintain < 0 {
-		return errors.New("Negative balance to maintain in ticket buyer config")
+	if err := cfg.Validate(); err != nil {
+		return err
 	}
 
 	asset.cancelAutoTicketBuyerMu.Lock()
@@ -257,13 +257,18 @@ func (asset *Asset) StartTicketBuyer(passphrase string) error {
 	asset.cancelAutoTicketBuyer = cancel
 	asset.cancelAutoTicketBuyerMu.Unlock()
 
-	// Check the VSP.
-	vspInfo, err := vspInfo(cfg.VspHost)
-	if err == nil {
-		cfg.VspClient, err = asset.VSPClient(cfg.VspHost, vspInfo.PubKey)
-	}
-	if err != nil {
-		return fmt.Errorf("error setting up vsp client: %v", err)
+	// Check the VSPs.
+	for _, host := range cfg.vspHosts() {
+		vspInfo, err := vspInfo(host)
+		var vspClient *vsp.Client
+		if err == nil {
+			vspClient, err = asset.VSPClient(host, vspInfo.PubKey)
+		}
+		if err != nil {
+			asset.StopAutoTicketsPurchase()
+			return fmt.Errorf("error setting up vsp client for %s: %v", host, err)
+		}
+		cfg.vspClients = append(cfg.vspClients, vspClient)
 	}
 
 	go func() {
@@ -307,6 +312,11 @@ func (asset *Asset) runTicketBuyer(ctx context.Context, passphrase string, cfg *
 
 	var nextIntervalStart, expiry int32
 	var cancels []func()
+	// purchases counts the tickets bought for the ticket price interval
+	// they are mined in and nextVSP is the index of the VSP of the next
+	// ticket.
+	var purchases intervalPurchases
+	var nextVSP int
 	for {
 		select {
 		case <-ctx.Done():
@@ -333,7 +343,7 @@ func (asset *Asset) runTicketBuyer(ctx context.Context, passphrase string, cfg *
 				return err
 			}
 			if rp != nil {
-				log.Debugf("[%d] Skipping autobuyer actions: transactions are not synced", asset.ID)
+				asset.skipTicketPurchase(cfg, 0, 0, "transactions are not synced")
 				continue
 			}
 
@@ -363,7 +373,7 @@ func (asset *Asset) runTicketBuyer(ctx context.Context, passphrase string, cfg *
 				// blocks from now, with the next block containing the split transaction
 				// that the ticket purchase spends.
 				if height+2 == nextIntervalStart {
-					log.Debugf("[%d] Skipping purchase: next sdiff interval starts soon", asset.ID)
+					asset.skipTicketPurchase(cfg, height, 0, "next sdiff interval starts soon")
 					continue
 				}
 				// Set expiry to prevent tickets from being mined in the next
@@ -377,6 +387,32 @@ func (asset *Asset) runTicketBuyer(ctx context.Context, passphrase string, cfg *
 				}
 			}
 
+			if !cfg.Schedule.allows(time.Now()) {
+				asset.skipTicketPurchase(cfg, height, 0, "outside the purchase schedule")
+				continue
+			}
+
+			// The tickets are capped in the interval they are mined in, which
+			// is the next one from the block before it starts.
+			interval := ticketWindow(height, int32(w.ChainParams().StakeDiffWindowSize))
+			purchases.setInterval(interval)
+			intervalTickets := purchases.count()
+			if cfg.MaxTicketsPerInterval > 0 && intervalTickets >= cfg.MaxTicketsPerInterval {
+				asset.skipTicketPurchase(cfg, height, 0, "ticket cap of the sdiff interval reached")
+				continue
+			}
+
+			sdiff, err := asset.Internal().DCR.NextStakeDifficultyAfterHeader(ctx, tipHeader)
+			if err != nil {
+				return err
+			}
+
+			if cfg.MaxTicketPrice > 0 && int64(sdiff) > cfg.MaxTicketPrice {
+				asset.skipTicketPurchase(cfg, height, sdiff, fmt.Sprintf("ticket price is above the maximum of %v",
+					dcrutil.Amount(cfg.MaxTicketPrice)))
+				continue
+			}
+
 			// Get the account balance to determine how many tickets to buy
 			bal, err := asset.GetAccountBalance(cfg.PurchaseAccount)
 			if err != nil {
@@ -385,26 +421,53 @@ func (asset *Asset) runTicketBuyer(ctx context.Context, passphrase string, cfg *
 
 			spendable := bal.Spendable.ToInt()
 			if spendable < cfg.BalanceToMaintain {
-				log.Debugf("[%d] Skipping purchase: low available balance", asset.ID)
+				asset.skipTicketPurchase(cfg, height, sdiff, "low available balance")
 				continue
 			}
 
 			spendable -= cfg.BalanceToMaintain
-			sdiff, err := asset.Internal().DCR.NextStakeDifficultyAfterHeader(ctx, tipHeader)
-			if err != nil {
-				return err
-			}
-
 			buy := int(dcrutil.Amount(spendable) / sdiff)
 			if buy == 0 {
-				log.Debugf("[%d] Skipping purchase: low available balance", asset.ID)
+				asset.skipTicketPurchase(cfg, height, sdiff, "low available balance")
+				continue
+			}
+
+			reason := fmt.Sprintf("balance above %v affords %d ticket(s)", dcrutil.Amount(cfg.BalanceToMaintain), buy)
+			if cfg.MaxTicketsPerInterval > 0 && int32(buy) > cfg.MaxTicketsPerInterval-intervalTickets {
+				buy = int(cfg.MaxTicketsPerInterval - intervalTickets)
+				reason = fmt.Sprintf("%s, capped to %d by the ticket cap of the sdiff interval", reason, buy)
+			}
+
+			// Spread the tickets across the VSPs in turn.
+			var vspClients []*vsp.Client
+			var vspHosts []string
+			vspClients, vspHosts, nextVSP = cfg.nextVSPs(nextVSP, buy)
+
+			asset.recordTicketBuyerDecision(&TicketBuyerDecision{
+				Timestamp:   time.Now().Unix(),
+				Height:      height,
+				TicketPrice: int64(sdiff),
+				Tickets:     buy,
+				VSPs:        vspHosts,
+				DryRun:      cfg.DryRun,
+				Reason:      reason,
+			})
+
+			purchases.begin(interval, int32(buy))
+			if cfg.DryRun {
+				// The tickets of a dry run count as bought so that the
+				// decisions follow the ticket cap.
+				for i := 0; i < buy; i++ {
+					purchases.end(interval, true)
+				}
 				continue
 			}
 
 			cancelCtx, cancel := context.WithCancel(ctx)
 			cancels = append(cancels, cancel)
-			buyTicket := func() {
-				err := asset.buyTicket(cancelCtx, passphrase, sdiff, expiry, cfg)
+			buyTicket := func(vspClient *vsp.Client) {
+				err := asset.buyTicket(cancelCtx, passphrase, sdiff, expiry, cfg, vspClient)
+				purchases.end(interval, err == nil)
 				if err != nil {
 					switch {
 					// silence these errors
@@ -425,15 +488,16 @@ func (asset *Asset) runTicketBuyer(ctx context.Context, passphrase string, cfg *
 
 			// start separate ticket purchase for as many tickets that can be purchased
 			// each purchase only buy 1 ticket.
-			for i := 0; i < buy; i++ {
-				go buyTicket()
+			for _, vspClient := range vspClients {
+				go buyTicket(vspClient)
 			}
 		}
 	}
 }
 
 // buyTicket purchases one ticket with the asset.
-func (asset *Asset) buyTicket(ctx context.Context, passphrase string, sdiff dcrutil.Amount, expiry int32, cfg *TicketBuyerConfig) error {
+func (asset *Asset) buyTicket(ctx context.Context, passphrase string, sdiff dcrutil.Amount, expiry int32,
+	cfg *TicketBuyerConfig, vspClient *vsp.Client) error {
 	ctx, task := trace.NewTask(ctx, "ticketbuyer.buy")
 	defer task.End()
 
@@ -449,22 +513,28 @@ func (asset *Asset) buyTicket(ctx context.Context, passphrase string, sdiff dcru
 		return err
 	}
 
+	changeAccount := uint32(cfg.PurchaseAccount)
+	if cfg.ChangeAccount != -1 {
+		changeAccount = uint32(cfg.ChangeAccount)
+	}
+
 	// Count is 1 to prevent combining multiple split outputs in one tx,
 	// which can be used to link the tickets eventually purchased with the
 	// split outputs.
 	vspPolicy := vsp.Policy{
 		MaxFee:     0.2e8,
 		FeeAcct:    uint32(cfg.PurchaseAccount),
-		ChangeAcct: uint32(cfg.PurchaseAccount),
+		ChangeAcct: changeAccount,
 	}
 	request := &w.PurchaseTicketsRequest{
 		Count:         1,
 		SourceAccount: uint32(cfg.PurchaseAccount),
+		ChangeAccount: changeAccount,
 		Expiry:        expiry,
 		MinConf:       asset.RequiredConfirmations(),
-		VSPFeeProcess: cfg.VspClient.FeePercentage,
+		VSPFeeProcess: vspClient.FeePercentage,
 		VSPFeePaymentProcess: func(ctx context.Context, ticketHash *chainhash.Hash, feeTx *wire.MsgTx) error {
-			return cfg.VspClient.Process(ctx, ticketHash, feeTx, vspPolicy)
+			return vspClient.Process(ctx, ticketHash, feeTx, vspPolicy)
 		},
 	}
 	// Mixed split buying through CoinShuffle++, if configured.
@@ -474,6 +544,9 @@ func (asset *Asset) buyTicket(ctx context.Context, passphrase string, sdiff dcru
 		request.MixedAccount = csppCfg.MixedAccount
 		request.MixedAccountBranch = csppCfg.MixedAccountBranch
 		request.ChangeAccount = csppCfg.ChangeAccount
+		if cfg.ChangeAccount != -1 {
+			request.ChangeAccount = changeAccount
+		}
 		request.MixedSplitAccount = csppCfg.TicketSplitAccount
 	}
 
@@ -508,34 +581,52 @@ func (asset *Asset) StopAutoTicketsPurchase() error {
 	return nil
 }
 
-// SetAutoTicketsBuyerConfig sets ticket buyer config for the asset.
+// SetAutoTicketsBuyerConfig sets ticket buyer config for the asset. The other
+// settings of a previously saved config are kept.
 func (asset *Asset) SetAutoTicketsBuyerConfig(vspHost string, purchaseAccount int32, amountToMaintain int64) {
-	asset.SetLongConfigValueForKey(sharedW.TicketBuyerATMConfigKey, amountToMaintain)
-	asset.SetInt32ConfigValueForKey(sharedW.TicketBuyerAccountConfigKey, purchaseAccount)
-	asset.SetStringConfigValueForKey(sharedW.TicketBuyerVSPHostConfigKey, vspHost)
+	cfg := asset.AutoTicketsBuyerConfig()
+	cfg.VspHost = vspHost
+	cfg.PurchaseAccount = purchaseAccount
+	cfg.BalanceToMaintain = amountToMaintain
+	asset.SaveUserConfigValue(sharedW.TicketBuyerConfigKey, cfg)
+}
+
+// SaveTicketBuyerConfig validates and sets the ticket buyer config for the
+// asset. It is used by the ticket buyer the next time it is started.
+func (asset *Asset) SaveTicketBuyerConfig(cfg *TicketBuyerConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	asset.SaveUserConfigValue(sharedW.TicketBuyerConfigKey, cfg)
+	return nil
 }
 
 // AutoTicketsBuyerConfig returns the previously set ticket buyer config for
 // the asset.
 func (asset *Asset) AutoTicketsBuyerConfig() *TicketBuyerConfig {
-	btm := asset.ReadLongConfigValueForKey(sharedW.TicketBuyerATMConfigKey, -1)
-	accNum := asset.ReadInt32ConfigValueForKey(sharedW.TicketBuyerAccountConfigKey, -1)
-	vspHost := asset.ReadStringConfigValueForKey(sharedW.TicketBuyerVSPHostConfigKey, "")
+	cfg := new(TicketBuyerConfig)
+	if err := asset.ReadUserConfigValue(sharedW.TicketBuyerConfigKey, cfg); err == nil {
+		return cfg
+	}
 
+	// Fall back to the config saved before the ticket buyer strategies
+	// were added.
 	return &TicketBuyerConfig{
-		VspHost:           vspHost,
-		PurchaseAccount:   accNum,
-		BalanceToMaintain: btm,
+		VspHost:           asset.ReadStringConfigValueForKey(sharedW.TicketBuyerVSPHostConfigKey, ""),
+		PurchaseAccount:   asset.ReadInt32ConfigValueForKey(sharedW.TicketBuyerAccountConfigKey, -1),
+		BalanceToMaintain: asset.ReadLongConfigValueForKey(sharedW.TicketBuyerATMConfigKey, -1),
+		ChangeAccount:     -1,
 	}
 }
 
 // TicketBuyerConfigIsSet checks if ticket buyer config is set for the asset.
 func (asset *Asset) TicketBuyerConfigIsSet() bool {
-	return asset.ReadStringConfigValueForKey(sharedW.TicketBuyerVSPHostConfigKey, "") != ""
+	return asset.AutoTicketsBuyerConfig().VspHost != ""
 }
 
 // ClearTicketBuyerConfig clears the wallet's ticket buyer config.
 func (asset *Asset) ClearTicketBuyerConfig(_ int) error {
+	asset.DeleteUserConfigValueForKey(sharedW.TicketBuyerConfigKey)
 	asset.SetLongConfigValueForKey(sharedW.TicketBuyerATMConfigKey, -1)
 	asset.SetInt32ConfigValueForKey(sharedW.TicketBuyerAccountConfigKey, -1)
 	asset.SetStringConfigValueForKey(sharedW.TicketBuyerVSPHostConfigKey, "")
@@ -0,0 +1,210 @@
+package dcr
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/internal/vsp"
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+// maxTicketBuyerDecisions is the number of ticket buyer decisions kept in the
+// decision log of a wallet.
+const maxTicketBuyerDecisions = 100
+
+// Validate checks that the ticket buyer can run with the config.
+func (cfg *TicketBuyerConfig) Validate() error {
+	if cfg.BalanceToMaintain < 0 {
+		return fmt.Errorf("negative balance to maintain in ticket buyer config")
+	}
+	if cfg.MaxTicketPrice < 0 {
+		return fmt.Errorf("negative maximum ticket price in ticket buyer config")
+	}
+	if cfg.MaxTicketsPerInterval < 0 {
+		return fmt.Errorf("negative ticket cap in ticket buyer config")
+	}
+	if cfg.ChangeAccount < -1 {
+		return fmt.Errorf("invalid change account %d in ticket buyer config", cfg.ChangeAccount)
+	}
+
+	seen := map[string]bool{cfg.VspHost: true}
+	for _, host := range cfg.VspHosts {
+		if host == "" || seen[host] {
+			return fmt.Errorf("invalid or duplicate VSP %q in ticket buyer config", host)
+		}
+		seen[host] = true
+	}
+
+	if s := cfg.Schedule; s != nil {
+		if s.StartHour < 0 || s.StartHour > 23 || s.EndHour < 0 || s.EndHour > 23 {
+			return fmt.Errorf("the hours of the ticket buyer schedule must be between 0 and 23")
+		}
+		for _, day := range s.Weekdays {
+			if day < time.Sunday || day > time.Saturday {
+				return fmt.Errorf("invalid weekday %d in ticket buyer schedule", day)
+			}
+		}
+	}
+	return nil
+}
+
+// vspHosts returns all the VSPs the tickets are spread across.
+func (cfg *TicketBuyerConfig) vspHosts() []string {
+	return append([]string{cfg.VspHost}, cfg.VspHosts...)
+}
+
+// nextVSPs returns the VSPs of the next count tickets, taken in turn starting
+// with the VSP at index next, and the index of the VSP of the ticket after
+// them.
+func (cfg *TicketBuyerConfig) nextVSPs(next, count int) ([]*vsp.Client, []string, int) {
+	hosts := cfg.vspHosts()
+	vspClients := make([]*vsp.Client, count)
+	vspHosts := make([]string, count)
+	for i := range vspClients {
+		vspClients[i] = cfg.vspClients[next%len(cfg.vspClients)]
+		vspHosts[i] = hosts[next%len(cfg.vspClients)]
+		next++
+	}
+	return vspClients, vspHosts, next % len(cfg.vspClients)
+}
+
+// allows returns true if tickets may be bought at t. A nil schedule allows
+// every time. The hours after midnight of a window running over midnight
+// belong to the day the window started.
+func (s *TicketBuyerSchedule) allows(t time.Time) bool {
+	if s == nil {
+		return true
+	}
+
+	hour, day := t.Hour(), t.Weekday()
+	switch {
+	case s.StartHour == s.EndHour:
+	case s.StartHour < s.EndHour:
+		if hour < s.StartHour || hour >= s.EndHour {
+			return false
+		}
+	case hour < s.EndHour:
+		day = (day + 6) % 7
+	case hour < s.StartHour:
+		return false
+	}
+
+	if len(s.Weekdays) == 0 {
+		return true
+	}
+	for _, weekday := range s.Weekdays {
+		if weekday == day {
+			return true
+		}
+	}
+	return false
+}
+
+// ticketWindow returns the start of the sdiff interval the tickets bought at
+// height are mined in. The earliest a ticket may be mined is two blocks after
+// height, so the tickets bought at the block before an interval starts are
+// mined in that interval.
+func ticketWindow(height, intervalSize int32) int32 {
+	return (height + 1) / intervalSize * intervalSize
+}
+
+// intervalPurchases counts the tickets bought for the sdiff interval they are
+// mined in. The purchases in progress count against the ticket cap until they
+// end, only the successful ones remain counted.
+type intervalPurchases struct {
+	mtx      sync.Mutex
+	interval int32
+	bought   int32
+	pending  int32
+}
+
+// setInterval starts counting the purchases of the interval starting at
+// interval if it is not the interval counted already. The purchases of
+// previous intervals that end later are ignored.
+func (p *intervalPurchases) setInterval(interval int32) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if interval != p.interval {
+		p.interval, p.bought, p.pending = interval, 0, 0
+	}
+}
+
+// count returns the tickets bought and being bought in the current interval.
+func (p *intervalPurchases) count() int32 {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.bought + p.pending
+}
+
+// begin records that n tickets of interval are being bought.
+func (p *intervalPurchases) begin(interval, n int32) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if interval == p.interval {
+		p.pending += n
+	}
+}
+
+// end records the result of the purchase of a ticket of interval.
+func (p *intervalPurchases) end(interval int32, bought bool) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if interval != p.interval || p.pending == 0 {
+		return
+	}
+	p.pending--
+	if bought {
+		p.bought++
+	}
+}
+
+// skipTicketPurchase records that no ticket is bought at height for reason.
+func (asset *Asset) skipTicketPurchase(cfg *TicketBuyerConfig, height int32, sdiff dcrutil.Amount, reason string) {
+	asset.recordTicketBuyerDecision(&TicketBuyerDecision{
+		Timestamp:   time.Now().Unix(),
+		Height:      height,
+		TicketPrice: int64(sdiff),
+		DryRun:      cfg.DryRun,
+		Reason:      reason,
+	})
+}
+
+// recordTicketBuyerDecision logs decision and adds it to the decision log of
+// the wallet. A skipped purchase repeating the reason of the previous skipped
+// purchase replaces it, so that the log is not flooded by a skip at every
+// block.
+func (asset *Asset) recordTicketBuyerDecision(decision *TicketBuyerDecision) {
+	if decision.Tickets == 0 {
+		log.Debugf("[%d] Skipping purchase: %s", asset.ID, decision.Reason)
+	} else if decision.DryRun {
+		log.Infof("[%d] Dry run, would buy %d ticket(s) at %v: %s", asset.ID, decision.Tickets,
+			dcrutil.Amount(decision.TicketPrice), decision.Reason)
+	} else {
+		log.Infof("[%d] Buying %d ticket(s) at %v: %s", asset.ID, decision.Tickets,
+			dcrutil.Amount(decision.TicketPrice), decision.Reason)
+	}
+
+	decisions := asset.TicketBuyerDecisions()
+	if n := len(decisions); n > 0 && decision.Tickets == 0 &&
+		decisions[n-1].Tickets == 0 && decisions[n-1].Reason == decision.Reason {
+		decisions[n-1] = decision
+	} else {
+		decisions = append(decisions, decision)
+	}
+	if len(decisions) > maxTicketBuyerDecisions {
+		decisions = decisions[len(decisions)-maxTicketBuyerDecisions:]
+	}
+	asset.SaveUserConfigValue(sharedW.TicketBuyerDecisionsConfigKey, decisions)
+}
+
+// TicketBuyerDecisions returns the last decisions of the ticket buyer, the
+// most recent last.
+func (asset *Asset) TicketBuyerDecisions() []*TicketBuyerDecision {
+	var decisions []*TicketBuyerDecision
+	if err := asset.ReadUserConfigValue(sharedW.TicketBuyerDecisionsConfigKey, &decisions); err != nil {
+		return nil
+	}
+	return decisions
+}
@@ -0,0 +1,269 @@
+package dcr
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/crypto-power/cryptopower/libwallet/internal/vsp"
+)
+
+func TestTicketBuyerScheduleAllows(t *testing.T) {
+	// 2024-01-05 is a Friday.
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, time.January, day, hour, 30, 0, 0, time.Local)
+	}
+	weekend := []time.Weekday{time.Saturday, time.Sunday}
+
+	tests := []struct {
+		name     string
+		schedule *TicketBuyerSchedule
+		t        time.Time
+		expected bool
+	}{
+		{name: "no schedule", t: at(5, 3), expected: true},
+		{name: "whole day", schedule: &TicketBuyerSchedule{StartHour: 8, EndHour: 8}, t: at(5, 3), expected: true},
+		{name: "in window", schedule: &TicketBuyerSchedule{StartHour: 8, EndHour: 17}, t: at(5, 8), expected: true},
+		{name: "window end", schedule: &TicketBuyerSchedule{StartHour: 8, EndHour: 17}, t: at(5, 17)},
+		{name: "before window", schedule: &TicketBuyerSchedule{StartHour: 8, EndHour: 17}, t: at(5, 7)},
+		{name: "weekday", schedule: &TicketBuyerSchedule{Weekdays: weekend}, t: at(6, 12), expected: true},
+		{name: "other weekday", schedule: &TicketBuyerSchedule{Weekdays: weekend}, t: at(5, 12)},
+		{name: "over midnight before", schedule: &TicketBuyerSchedule{StartHour: 22, EndHour: 2}, t: at(5, 23), expected: true},
+		{name: "over midnight after", schedule: &TicketBuyerSchedule{StartHour: 22, EndHour: 2}, t: at(6, 1), expected: true},
+		{name: "over midnight end", schedule: &TicketBuyerSchedule{StartHour: 22, EndHour: 2}, t: at(6, 2)},
+		{name: "over midnight outside", schedule: &TicketBuyerSchedule{StartHour: 22, EndHour: 2}, t: at(5, 12)},
+		{
+			name:     "over midnight from the weekday",
+			schedule: &TicketBuyerSchedule{Weekdays: []time.Weekday{time.Friday}, StartHour: 22, EndHour: 2},
+			t:        at(6, 1),
+			expected: true,
+		},
+		{
+			name:     "over midnight into the weekday",
+			schedule: &TicketBuyerSchedule{Weekdays: []time.Weekday{time.Friday}, StartHour: 22, EndHour: 2},
+			t:        at(5, 1),
+		},
+		{
+			name:     "over midnight from sunday",
+			schedule: &TicketBuyerSchedule{Weekdays: []time.Weekday{time.Sunday}, StartHour: 22, EndHour: 2},
+			t:        at(8, 1),
+			expected: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.schedule.allows(tc.t); got != tc.expected {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestTicketBuyerConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     TicketBuyerConfig
+		wantErr bool
+	}{
+		{name: "valid", cfg: TicketBuyerConfig{VspHost: "vsp1", VspHosts: []string{"vsp2"}, ChangeAccount: -1}},
+		{name: "negative balance", cfg: TicketBuyerConfig{VspHost: "vsp1", BalanceToMaintain: -1}, wantErr: true},
+		{name: "negative price", cfg: TicketBuyerConfig{VspHost: "vsp1", MaxTicketPrice: -1}, wantErr: true},
+		{name: "negative cap", cfg: TicketBuyerConfig{VspHost: "vsp1", MaxTicketsPerInterval: -1}, wantErr: true},
+		{name: "invalid change account", cfg: TicketBuyerConfig{VspHost: "vsp1", ChangeAccount: -2}, wantErr: true},
+		{name: "empty vsp", cfg: TicketBuyerConfig{VspHost: "vsp1", VspHosts: []string{""}}, wantErr: true},
+		{name: "duplicate vsp", cfg: TicketBuyerConfig{VspHost: "vsp1", VspHosts: []string{"vsp1"}}, wantErr: true},
+		{
+			name: "valid schedule",
+			cfg: TicketBuyerConfig{VspHost: "vsp1", Schedule: &TicketBuyerSchedule{
+				Weekdays: []time.Weekday{time.Sunday, time.Saturday}, StartHour: 0, EndHour: 23,
+			}},
+		},
+		{name: "invalid hour", cfg: TicketBuyerConfig{VspHost: "vsp1", Schedule: &TicketBuyerSchedule{EndHour: 24}}, wantErr: true},
+		{
+			name:    "invalid weekday",
+			cfg:     TicketBuyerConfig{VspHost: "vsp1", Schedule: &TicketBuyerSchedule{Weekdays: []time.Weekday{7}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.cfg.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("(%v), expected error (%v), got (%v)", tc.name, tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTicketBuyerNextVSPs(t *testing.T) {
+	clients := []*vsp.Client{new(vsp.Client), new(vsp.Client), new(vsp.Client)}
+	cfg := &TicketBuyerConfig{VspHost: "vsp1", VspHosts: []string{"vsp2", "vsp3"}, vspClients: clients}
+
+	tests := []struct {
+		name     string
+		next     int
+		count    int
+		expected []string
+		nextVSP  int
+	}{
+		{name: "first", next: 0, count: 1, expected: []string{"vsp1"}, nextVSP: 1},
+		{name: "in turn", next: 1, count: 2, expected: []string{"vsp2", "vsp3"}, nextVSP: 0},
+		{name: "wrap around", next: 2, count: 4, expected: []string{"vsp3", "vsp1", "vsp2", "vsp3"}, nextVSP: 0},
+		{name: "none", next: 1, count: 0, expected: []string{}, nextVSP: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			vspClients, hosts, next := cfg.nextVSPs(tc.next, tc.count)
+			if !reflect.DeepEqual(hosts, tc.expected) || next != tc.nextVSP {
+				t.Errorf("(%v), expected (%v, %d), got (%v, %d)", tc.name, tc.expected, tc.nextVSP, hosts, next)
+			}
+			for i, host := range hosts {
+				index := map[string]int{"vsp1": 0, "vsp2": 1, "vsp3": 2}[host]
+				if vspClients[i] != clients[index] {
+					t.Errorf("(%v), the client of ticket %d is not the client of %s", tc.name, i, host)
+				}
+			}
+		})
+	}
+}
+
+func TestIntervalPurchases(t *testing.T) {
+	var p intervalPurchases
+	p.setInterval(144)
+
+	p.begin(144, 3)
+	if got := p.count(); got != 3 {
+		t.Fatalf("expected (3) tickets counted while being bought, got (%d)", got)
+	}
+
+	p.end(144, true)
+	p.end(144, false)
+	if got := p.count(); got != 2 {
+		t.Fatalf("expected (2) tickets counted once a purchase failed, got (%d)", got)
+	}
+
+	// Setting the counted interval again keeps its purchases.
+	p.setInterval(144)
+	if got := p.count(); got != 2 {
+		t.Fatalf("expected (2) tickets counted in the same interval, got (%d)", got)
+	}
+
+	// The purchases of a previous interval don't count in the next one.
+	p.setInterval(288)
+	p.end(144, true)
+	p.begin(144, 1)
+	if got := p.count(); got != 0 {
+		t.Fatalf("expected (0) tickets counted in the new interval, got (%d)", got)
+	}
+
+	p.begin(288, 1)
+	p.end(288, true)
+	p.end(288, true)
+	if got := p.count(); got != 1 {
+		t.Fatalf("expected (1) ticket counted, got (%d)", got)
+	}
+}
+
+func TestTicketBuyerIntervalCap(t *testing.T) {
+	tests := []struct {
+		name         string
+		intervalSize int32
+		cap          int32
+		startHeight  int32
+		endHeight    int32
+		// failAt is the height at which the purchases fail.
+		failAt int32
+		// expected is the number of tickets bought by the start of the
+		// interval they are mined in.
+		expected map[int32]int32
+	}{
+		{
+			name:         "across a boundary",
+			intervalSize: 144,
+			cap:          5,
+			startHeight:  139,
+			endHeight:    146,
+			expected:     map[int32]int32{0: 5, 144: 5},
+		},
+		{
+			name:         "across two boundaries",
+			intervalSize: 8,
+			cap:          2,
+			startHeight:  3,
+			endHeight:    20,
+			expected:     map[int32]int32{0: 2, 8: 2, 16: 2},
+		},
+		{
+			name:         "failed purchases are bought again",
+			intervalSize: 144,
+			cap:          3,
+			startHeight:  140,
+			endHeight:    145,
+			failAt:       143,
+			expected:     map[int32]int32{0: 3, 144: 3},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Walk the heights like the ticket buyer does, buying as many
+			// tickets as the cap allows at every block.
+			var p intervalPurchases
+			bought := make(map[int32]int32)
+			for height := tc.startHeight; height <= tc.endHeight; height++ {
+				if (height+2)%tc.intervalSize == 0 {
+					// The next sdiff is unknown.
+					continue
+				}
+
+				interval := ticketWindow(height, tc.intervalSize)
+				p.setInterval(interval)
+				buy := tc.cap - p.count()
+				if buy <= 0 {
+					continue
+				}
+				p.begin(interval, buy)
+				for i := int32(0); i < buy; i++ {
+					ok := height != tc.failAt
+					p.end(interval, ok)
+					if ok {
+						bought[interval]++
+					}
+				}
+			}
+
+			for interval, got := range bought {
+				if got != tc.expected[interval] {
+					t.Errorf("(%v), expected (%d) tickets mined from height %d, got (%d)", tc.name,
+						tc.expected[interval], interval, got)
+				}
+			}
+			if len(bought) != len(tc.expected) {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.expected, bought)
+			}
+		})
+	}
+}
+
+func TestTicketWindow(t *testing.T) {
+	tests := []struct {
+		height   int32
+		expected int32
+	}{
+		{height: 0, expected: 0},
+		{height: 141, expected: 0},
+		{height: 142, expected: 0},
+		{height: 143, expected: 144},
+		{height: 144, expected: 144},
+		{height: 286, expected: 144},
+		{height: 287, expected: 288},
+	}
+
+	for _, tc := range tests {
+		if got := ticketWindow(tc.height, 144); got != tc.expected {
+			t.Errorf("(%v), expected (%v), got (%v)", tc.height, tc.expected, got)
+		}
+	}
+}
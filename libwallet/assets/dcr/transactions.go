@@ -46,6 +46,8 @@ const (
 	TicketStatusLive           = "live"
 	TicketStatusVotedOrRevoked = "votedrevoked"
 	TicketStatusExpired        = "expired"
+	TicketStatusVoted          = "voted"
+	TicketStatusMissed         = "missed"
 )
 
 func (asset *Asset) PublishUnminedTransactions() error {
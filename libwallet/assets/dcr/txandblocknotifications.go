@@ -1,9 +1,8 @@
 package dcr
 
 import (
-	"decred.org/dcrwallet/v3/errors"
 	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
-	"github.com/crypto-power/cryptopower/libwallet/utils"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
 )
 
 func (asset *Asset) listenForTransactions() {
@@ -66,52 +65,6 @@ func (asset *Asset) listenForTransactions() {
 	}()
 }
 
-// AddTxAndBlockNotificationListener registers a set of functions to be invoked
-// when a transaction or block update is processed by the asset. If async is
-// true, the provided callback methods will be called from separate goroutines,
-// allowing notification senders to continue their operation without waiting
-// for the listener to complete processing the notification. This asyncrhonous
-// handling is especially important for cases where the wallet process that
-// sends the notification temporarily prevents access to other wallet features
-// until all notification handlers finish processing the notification. If a
-// notification handler were to try to access such features, it would result
-// in a deadlock.
-func (asset *Asset) AddTxAndBlockNotificationListener(txAndBlockNotificationListener *sharedW.TxAndBlockNotificationListener, uniqueIdentifier string) error {
-	asset.notificationListenersMu.Lock()
-	defer asset.notificationListenersMu.Unlock()
-
-	_, ok := asset.txAndBlockNotificationListeners[uniqueIdentifier]
-	if ok {
-		return errors.New(utils.ErrListenerAlreadyExist)
-	}
-
-	asset.txAndBlockNotificationListeners[uniqueIdentifier] = &sharedW.TxAndBlockNotificationListener{
-		OnTransaction: func(transaction *sharedW.Transaction) {
-			if txAndBlockNotificationListener.OnTransaction != nil {
-				go txAndBlockNotificationListener.OnTransaction(transaction)
-			}
-		},
-		OnBlockAttached: func(walletID int, blockHeight int32) {
-			if txAndBlockNotificationListener.OnBlockAttached != nil {
-				go txAndBlockNotificationListener.OnBlockAttached(walletID, blockHeight)
-			}
-		},
-		OnTransactionConfirmed: func(walletID int, hash string, blockHeight int32) {
-			if txAndBlockNotificationListener.OnTransactionConfirmed != nil {
-				txAndBlockNotificationListener.OnTransactionConfirmed(walletID, hash, blockHeight)
-			}
-		},
-	}
-	return nil
-}
-
-func (asset *Asset) RemoveTxAndBlockNotificationListener(uniqueIdentifier string) {
-	asset.notificationListenersMu.Lock()
-	defer asset.notificationListenersMu.Unlock()
-
-	delete(asset.txAndBlockNotificationListeners, uniqueIdentifier)
-}
-
 func (asset *Asset) checkWalletMixers() {
 	if asset.IsAccountMixerActive() {
 		unmixedAccount := asset.ReadInt32ConfigValueForKey(sharedW.AccountMixerUnmixedAccount, -1)
@@ -127,28 +80,16 @@ func (asset *Asset) checkWalletMixers() {
 }
 
 func (asset *Asset) mempoolTransactionNotification(transaction *sharedW.Transaction) {
-	asset.notificationListenersMu.RLock()
-	defer asset.notificationListenersMu.RUnlock()
-
-	for _, txAndBlockNotifcationListener := range asset.txAndBlockNotificationListeners {
-		txAndBlockNotifcationListener.OnTransaction(transaction)
-	}
+	asset.PublishEvent(eventbus.TopicTransaction, transaction)
 }
 
 func (asset *Asset) publishTransactionConfirmed(transactionHash string, blockHeight int32) {
-	asset.notificationListenersMu.RLock()
-	defer asset.notificationListenersMu.RUnlock()
-
-	for _, txAndBlockNotifcationListener := range asset.txAndBlockNotificationListeners {
-		txAndBlockNotifcationListener.OnTransactionConfirmed(asset.ID, transactionHash, blockHeight)
-	}
+	asset.PublishEvent(eventbus.TopicTransactionConfirmed, &sharedW.TxConfirmation{
+		Hash:        transactionHash,
+		BlockHeight: blockHeight,
+	})
 }
 
 func (asset *Asset) publishBlockAttached(blockHeight int32) {
-	asset.notificationListenersMu.RLock()
-	defer asset.notificationListenersMu.RUnlock()
-
-	for _, txAndBlockNotifcationListener := range asset.txAndBlockNotificationListeners {
-		txAndBlockNotifcationListener.OnBlockAttached(asset.ID, blockHeight)
-	}
+	asset.PublishEvent(eventbus.TopicBlockAttached, blockHeight)
 }
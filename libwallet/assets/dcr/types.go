@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"time"
 
 	"decred.org/dcrwallet/v3/wallet/udb"
 	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
@@ -82,6 +83,69 @@ type StakingOverview struct {
 	Expired  int
 }
 
+// TicketAnalytics describes the cost and the reward of a single ticket.
+type TicketAnalytics struct {
+	TicketHash string
+	// SpenderHash is the hash of the vote or the revocation of the ticket,
+	// empty if the ticket is unspent.
+	SpenderHash string
+	Status      string
+	VSP         string
+	// Month is the month the ticket was voted or revoked in, or bought in
+	// if it is unspent, formatted as 2006-01.
+	Month        string
+	PurchaseTime int64
+	SpendTime    int64
+	TicketPrice  int64
+	TicketFee    int64
+	// SplitFee is the share of the fee of the split tx funding the ticket.
+	SplitFee int64
+	VSPFee   int64
+	// Reward is the vote reward, before any fee, and NetReward is the
+	// reward net of the ticket, split tx and VSP fees.
+	Reward    int64
+	NetReward int64
+	// DaysToVote is the number of days between the purchase and the vote or
+	// the revocation of the ticket.
+	DaysToVote       float64
+	ROI              float64
+	AnnualizedReturn float64
+}
+
+// StakingStats aggregates the analytics of a group of tickets. TicketFees
+// include the split tx fees. ROI and AnnualizedReturn only account for the
+// spent tickets.
+type StakingStats struct {
+	// Key is the month or the VSP of the group, empty for the totals.
+	Key               string
+	Tickets           int
+	Voted             int
+	Missed            int
+	Expired           int
+	Revoked           int
+	Invested          int64
+	TicketFees        int64
+	VSPFees           int64
+	Rewards           int64
+	NetRewards        int64
+	AverageDaysToVote float64
+	ROI               float64
+	AnnualizedReturn  float64
+
+	spentInvested   int64
+	spentNetRewards int64
+	investedDays    float64
+	daysToVoteSum   float64
+}
+
+// StakingAnalytics is the staking performance of a wallet.
+type StakingAnalytics struct {
+	Total   *StakingStats
+	ByMonth []*StakingStats
+	ByVSP   []*StakingStats
+	Tickets []*TicketAnalytics
+}
+
 // TicketBuyerConfig defines configuration parameters for running
 // an automated ticket buyer.
 type TicketBuyerConfig struct {
@@ -89,7 +153,54 @@ type TicketBuyerConfig struct {
 	PurchaseAccount   int32
 	BalanceToMaintain int64
 
-	VspClient *vsp.Client
+	// VspHosts are further VSPs the tickets are spread across, in turn with
+	// VspHost.
+	VspHosts []string
+	// ChangeAccount receives the change of the ticket purchases, e.g. a
+	// mixed account when buying from the mixed account. -1 leaves the change
+	// in the purchase account, or in the unmixed account for mixed split
+	// purchases.
+	ChangeAccount int32
+	// MaxTicketPrice is the ticket price in atoms above which no ticket is
+	// bought. 0 means no limit.
+	MaxTicketPrice int64
+	// MaxTicketsPerInterval caps the tickets bought in a ticket price
+	// interval. 0 means no cap.
+	MaxTicketsPerInterval int32
+	// Schedule limits the purchases to a time window. A nil Schedule allows
+	// purchases at any time.
+	Schedule *TicketBuyerSchedule
+	// DryRun makes the ticket buyer log its decisions without buying any
+	// ticket.
+	DryRun bool
+
+	vspClients []*vsp.Client
+}
+
+// TicketBuyerSchedule is the time window in which the ticket buyer may buy
+// tickets, in local time.
+type TicketBuyerSchedule struct {
+	// Weekdays are the days tickets may be bought on, the days the window
+	// starts on for a window running over midnight. Empty means every day.
+	Weekdays []time.Weekday
+	// StartHour and EndHour delimit the hours of the day tickets may be
+	// bought in, from StartHour up to but excluding EndHour. A window ending
+	// before it starts runs over midnight, equal hours allow the whole day.
+	StartHour int
+	EndHour   int
+}
+
+// TicketBuyerDecision records what the ticket buyer did at a block and why.
+type TicketBuyerDecision struct {
+	Timestamp   int64
+	Height      int32
+	TicketPrice int64
+	// Tickets is the number of tickets bought, or that would have been
+	// bought in a dry run.
+	Tickets int
+	VSPs    []string
+	DryRun  bool
+	Reason  string
 }
 
 // VSPFeeStatus represents the current fee status of a ticket.
@@ -35,7 +35,7 @@ func (asset *Asset) VSPClient(host string, pubKey []byte) (*vsp.Client, error) {
 	cfg := vsp.Config{
 		URL:    host,
 		PubKey: pubKey,
-		Dialer: nil, // optional, but consider providing a value
+		Dialer: utils.ProxyDialContext(utils.VSPSubsystem),
 		Wallet: asset.Internal().DCR,
 	}
 	client, err := vsp.New(cfg)
@@ -196,10 +196,15 @@ func vspInfo(vspHost string) (*VspInfoResponse, error) {
 
 // defaultVSPs returns a list of known VSPs.
 func defaultVSPs(network string) ([]string, error) {
+	vspsURL, err := utils.ServiceURL(utils.VSPListService, defaultVSPsURL)
+	if err != nil {
+		return nil, err
+	}
+
 	var vspInfoResponse map[string]*VspInfoResponse
 	req := &utils.ReqConfig{
 		Method:  http.MethodGet,
-		HTTPURL: defaultVSPsURL,
+		HTTPURL: vspsURL,
 	}
 
 	if _, err := utils.HTTPRequest(req, &vspInfoResponse); err != nil {
@@ -8,6 +8,7 @@ import (
 	dcrW "decred.org/dcrwallet/v3/wallet"
 	"decred.org/dcrwallet/v3/wallet/txrules"
 	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/badgerdb"
 	"github.com/crypto-power/cryptopower/libwallet/internal/loader"
 	"github.com/crypto-power/cryptopower/libwallet/internal/loader/dcr"
 	"github.com/crypto-power/cryptopower/libwallet/internal/vsp"
@@ -35,11 +36,8 @@ type Asset struct {
 	vspMu        sync.RWMutex
 	vsps         []*VSP
 
-	notificationListenersMu           sync.RWMutex
-	syncData                          *SyncData
-	accountMixerNotificationListeners map[string]*AccountMixerNotificationListener
-	txAndBlockNotificationListeners   map[string]*sharedW.TxAndBlockNotificationListener
-	blocksRescanProgressListener      *sharedW.BlocksRescanProgressListener
+	syncData                     *SyncData
+	blocksRescanProgressListener *sharedW.BlocksRescanProgressListener
 }
 
 // Verify that DCR implements the shared assets interface.
@@ -115,12 +113,8 @@ func CreateNewWallet(pass *sharedW.AuthInfo, params *sharedW.InitParams) (shared
 	dcrWallet := &Asset{
 		Wallet:      w,
 		chainParams: chainParams,
-		syncData: &SyncData{
-			syncProgressListeners: make(map[string]*sharedW.SyncProgressListener),
-		},
-		txAndBlockNotificationListeners:   make(map[string]*sharedW.TxAndBlockNotificationListener),
-		accountMixerNotificationListeners: make(map[string]*AccountMixerNotificationListener),
-		vspClients:                        make(map[string]*vsp.Client),
+		syncData:    &SyncData{},
+		vspClients:  make(map[string]*vsp.Client),
 	}
 
 	dcrWallet.SetNetworkCancelCallback(dcrWallet.SafelyCancelSync)
@@ -152,11 +146,7 @@ func CreateWatchOnlyWallet(walletName, extendedPublicKey string, params *sharedW
 	dcrWallet := &Asset{
 		Wallet:      w,
 		chainParams: chainParams,
-		syncData: &SyncData{
-			syncProgressListeners: make(map[string]*sharedW.SyncProgressListener),
-		},
-		txAndBlockNotificationListeners:   make(map[string]*sharedW.TxAndBlockNotificationListener),
-		accountMixerNotificationListeners: make(map[string]*AccountMixerNotificationListener),
+		syncData:    &SyncData{},
 	}
 
 	dcrWallet.SetNetworkCancelCallback(dcrWallet.SafelyCancelSync)
@@ -186,12 +176,8 @@ func RestoreWallet(seedMnemonic string, pass *sharedW.AuthInfo, params *sharedW.
 	dcrWallet := &Asset{
 		Wallet:      w,
 		chainParams: chainParams,
-		syncData: &SyncData{
-			syncProgressListeners: make(map[string]*sharedW.SyncProgressListener),
-		},
-		vspClients:                        make(map[string]*vsp.Client),
-		txAndBlockNotificationListeners:   make(map[string]*sharedW.TxAndBlockNotificationListener),
-		accountMixerNotificationListeners: make(map[string]*AccountMixerNotificationListener),
+		syncData:    &SyncData{},
+		vspClients:  make(map[string]*vsp.Client),
 	}
 
 	dcrWallet.SetNetworkCancelCallback(dcrWallet.SafelyCancelSync)
@@ -217,11 +203,7 @@ func LoadExisting(w *sharedW.Wallet, params *sharedW.InitParams) (sharedW.Asset,
 		Wallet:      w,
 		vspClients:  make(map[string]*vsp.Client),
 		chainParams: chainParams,
-		syncData: &SyncData{
-			syncProgressListeners: make(map[string]*sharedW.SyncProgressListener),
-		},
-		txAndBlockNotificationListeners:   make(map[string]*sharedW.TxAndBlockNotificationListener),
-		accountMixerNotificationListeners: make(map[string]*AccountMixerNotificationListener),
+		syncData:    &SyncData{},
 	}
 
 	err = dcrWallet.Prepare(ldr, params)
@@ -277,6 +259,7 @@ func (asset *Asset) SafelyCancelSync() {
 	if asset.IsConnectedToDecredNetwork() {
 		asset.CancelSync()
 	}
+	asset.removeHeaderSource()
 }
 
 func (asset *Asset) IsConnectedToNetwork() bool {
@@ -307,3 +290,9 @@ func (asset *Asset) GetWalletBalance() (*sharedW.Balance, error) {
 		ImmatureReward: Amount(totalImmatureReward),
 	}, nil
 }
+
+// CompactDB compacts the wallet database while the wallet remains open. Only
+// wallets using the badger database driver can be compacted.
+func (asset *Asset) CompactDB() error {
+	return badgerdb.Compact(asset.walletDbPath())
+}
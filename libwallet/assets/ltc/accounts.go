@@ -4,13 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
-	"strconv"
 	"time"
 
 	"decred.org/dcrwallet/v3/errors"
 	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
 	"github.com/crypto-power/cryptopower/libwallet/utils"
-	"github.com/ltcsuite/ltcd/chaincfg"
 	"github.com/ltcsuite/ltcd/ltcutil"
 	"github.com/ltcsuite/ltcwallet/waddrmgr"
 )
@@ -47,7 +45,7 @@ func (asset *Asset) GetAccountsRaw() (*sharedW.Accounts, error) {
 		return nil, utils.ErrLTCNotInitialized
 	}
 
-	resp, err := asset.Internal().LTC.Accounts(GetScope())
+	resp, err := asset.Internal().LTC.Accounts(asset.keyScope())
 	if err != nil {
 		return nil, err
 	}
@@ -109,10 +107,20 @@ func (asset *Asset) GetAccountBalance(accountNumber int32) (*sharedW.Balance, er
 		return nil, err
 	}
 
+	// The outputs of the watched addresses are received by the imported
+	// account but are not credited by the upstream wallet.
+	var watchOnly int64
+	if accountNumber == ImportedAccountNumber {
+		if watchOnly, err = asset.watchOnlyBalance(); err != nil {
+			return nil, err
+		}
+	}
+
 	return &sharedW.Balance{
-		Total:          Amount(balance.Total),
+		Total:          Amount(int64(balance.Total) + watchOnly),
 		Spendable:      Amount(balance.Spendable),
 		ImmatureReward: Amount(balance.ImmatureReward),
+		WatchOnly:      Amount(watchOnly),
 	}, nil
 }
 
@@ -197,7 +205,7 @@ func (asset *Asset) NextAccount(accountName string) (int32, error) {
 		return -1, errors.New(utils.ErrWalletLocked)
 	}
 
-	accountNumber, err := asset.Internal().LTC.NextAccount(GetScope(), accountName)
+	accountNumber, err := asset.Internal().LTC.NextAccount(asset.keyScope(), accountName)
 	if err != nil {
 		return -1, err
 	}
@@ -211,7 +219,7 @@ func (asset *Asset) RenameAccount(accountNumber int32, newName string) error {
 		return utils.ErrLTCNotInitialized
 	}
 
-	err := asset.Internal().LTC.RenameAccount(GetScope(), uint32(accountNumber), newName)
+	err := asset.Internal().LTC.RenameAccount(asset.keyScope(), uint32(accountNumber), newName)
 	if err != nil {
 		return utils.TranslateError(err)
 	}
@@ -235,7 +243,7 @@ func (asset *Asset) AccountNameRaw(accountNumber uint32) (string, error) {
 		return "", utils.ErrLTCNotInitialized
 	}
 
-	return asset.Internal().LTC.AccountName(GetScope(), accountNumber)
+	return asset.Internal().LTC.AccountName(asset.keyScope(), accountNumber)
 }
 
 // AccountNumber returns the account number for the provided account name.
@@ -244,7 +252,7 @@ func (asset *Asset) AccountNumber(accountName string) (int32, error) {
 		return -1, utils.ErrLTCNotInitialized
 	}
 
-	accountNumber, err := asset.Internal().LTC.AccountNumber(GetScope(), accountName)
+	accountNumber, err := asset.Internal().LTC.AccountNumber(asset.keyScope(), accountName)
 	return int32(accountNumber), utils.TranslateError(err)
 }
 
@@ -255,18 +263,18 @@ func (asset *Asset) HasAccount(accountName string) bool {
 		return false
 	}
 
-	_, err := asset.Internal().LTC.AccountNumber(GetScope(), accountName)
+	_, err := asset.Internal().LTC.AccountNumber(asset.keyScope(), accountName)
 	return err == nil
 }
 
-// HDPathForAccount returns the HD path for the provided account number.
+// HDPathForAccount returns the HD path for the provided account number in
+// the key scope of the wallet's accounts.
 func (asset *Asset) HDPathForAccount(accountNumber int32) (string, error) {
-	var hdPath string
-	if asset.chainParams.Name == chaincfg.MainNetParams.Name {
-		hdPath = MainnetHDPath
-	} else {
-		hdPath = TestnetHDPath
-	}
+	return hdPathForAccount(asset.keyScope(), accountNumber), nil
+}
 
-	return hdPath + strconv.Itoa(int(accountNumber)), nil
+// hdPathForAccount returns the HD path of the provided account of the key
+// scope, without the hardened marker of the account.
+func hdPathForAccount(scope waddrmgr.KeyScope, accountNumber int32) string {
+	return fmt.Sprintf("m / %d' / %d' / %d", scope.Purpose, scope.Coin, accountNumber)
 }
@@ -0,0 +1,28 @@
+package ltc
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/ltcwallet/waddrmgr"
+)
+
+func TestHDPathForAccount(t *testing.T) {
+	tests := []struct {
+		name     string
+		scope    waddrmgr.KeyScope
+		account  int32
+		expected string
+	}{
+		{name: "bip44", scope: waddrmgr.KeyScopeBIP0044, account: 0, expected: "m / 44' / 0' / 0"},
+		{name: "bip49", scope: waddrmgr.KeyScopeBIP0049Plus, account: 1, expected: "m / 49' / 0' / 1"},
+		{name: "bip84", scope: waddrmgr.KeyScopeBIP0084, account: 2, expected: "m / 84' / 0' / 2"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := hdPathForAccount(tc.scope, tc.account); got != tc.expected {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.expected, got)
+			}
+		})
+	}
+}
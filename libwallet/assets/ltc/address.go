@@ -4,8 +4,11 @@ import (
 	"fmt"
 
 	"decred.org/dcrwallet/v3/errors"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
 	"github.com/crypto-power/cryptopower/libwallet/utils"
 	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcwallet/waddrmgr"
+	"github.com/ltcsuite/ltcwallet/walletdb"
 )
 
 // AddressInfo holds information about an address.
@@ -97,7 +100,12 @@ func (asset *Asset) CurrentAddress(account int32) (string, error) {
 		return "", utils.ErrLTCNotInitialized
 	}
 
-	addr, err := asset.Internal().LTC.CurrentAddress(uint32(account), GetScope())
+	// The receive addresses of multisig wallets are those of the multisig.
+	if asset.IsMultisigWallet() {
+		return asset.CurrentMultisigAddress()
+	}
+
+	addr, err := asset.Internal().LTC.CurrentAddress(uint32(account), asset.keyScope())
 	if err != nil {
 		log.Errorf("CurrentAddress error: %v", err)
 		return "", err
@@ -117,8 +125,13 @@ func (asset *Asset) NextAddress(account int32) (string, error) {
 		return "", utils.ErrLTCNotInitialized
 	}
 
+	// The receive addresses of multisig wallets are those of the multisig.
+	if asset.IsMultisigWallet() {
+		return asset.NextMultisigAddress()
+	}
+
 	// NewAddress returns the next external chained address for a wallet.
-	address, err := asset.Internal().LTC.NewAddress(uint32(account), GetScope())
+	address, err := asset.Internal().LTC.NewAddress(uint32(account), asset.keyScope())
 	if err != nil {
 		log.Errorf("NewExternalAddress error: %w", err)
 		return "", err
@@ -178,3 +191,94 @@ func (asset *Asset) AddressPubKey(address string) (string, error) {
 	}
 	return pubKeyAddr.String(), nil
 }
+
+// AccountAddresses returns all the addresses derived by the provided account
+// up to the account's external and internal key counts, together with their
+// derivation path and usage details. For the imported account, the imported
+// addresses are returned instead.
+func (asset *Asset) AccountAddresses(account int32) ([]*sharedW.AddressUsage, error) {
+	const op errors.Op = "ltc.AccountAddresses"
+
+	if !asset.WalletOpened() {
+		return nil, utils.ErrLTCNotInitialized
+	}
+
+	acct, err := asset.GetAccount(account)
+	if err != nil {
+		return nil, err
+	}
+
+	props := acct.AccountProperties
+	addrs := make([]*sharedW.AddressUsage, 0, props.ExternalKeyCount+props.InternalKeyCount+props.ImportedKeyCount)
+	if props.AccountNumber == ImportedAccountNumber {
+		imported, err := asset.Internal().LTC.AccountAddresses(ImportedAccountNumber)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+
+		for _, addr := range imported {
+			addrs = append(addrs, &sharedW.AddressUsage{
+				Address:       addr.String(),
+				AccountNumber: account,
+				Imported:      true,
+			})
+		}
+	} else {
+		hdPath, err := asset.HDPathForAccount(account)
+		if err != nil {
+			return nil, err
+		}
+
+		err = walletdb.View(asset.Internal().LTC.Database(), func(dbtx walletdb.ReadTx) error {
+			ns := dbtx.ReadBucket(wAddrMgrBkt)
+			scopedMgr, err := asset.Internal().LTC.Manager.FetchScopedKeyManager(asset.keyScope())
+			if err != nil {
+				return err
+			}
+
+			derive := func(branch, count uint32) error {
+				for index := uint32(0); index < count; index++ {
+					maddr, err := scopedMgr.DeriveFromKeyPath(ns, waddrmgr.DerivationPath{
+						InternalAccount: props.AccountNumber,
+						Account:         props.AccountNumber,
+						Branch:          branch,
+						Index:           index,
+					})
+					if err != nil {
+						return err
+					}
+
+					addrs = append(addrs, &sharedW.AddressUsage{
+						Address:       maddr.Address().String(),
+						AccountNumber: account,
+						Internal:      branch == waddrmgr.InternalBranch,
+						Index:         index,
+						HDPath:        fmt.Sprintf("%s' / %d / %d", hdPath, branch, index),
+					})
+				}
+				return nil
+			}
+
+			if err := derive(waddrmgr.ExternalBranch, props.ExternalKeyCount); err != nil {
+				return err
+			}
+			return derive(waddrmgr.InternalBranch, props.InternalKeyCount)
+		})
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+	}
+
+	txs, err := asset.GetTransactionsRaw(0, 0, utils.TxFilterAll, true)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	utxos, err := asset.UnspentOutputs(account)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	asset.PopulateAddressUsage(addrs, txs, utxos, asset.ToAmount)
+	return addrs, nil
+}
@@ -0,0 +1,300 @@
+package ltc
+
+import (
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"decred.org/dcrwallet/v3/errors"
+	"github.com/asdine/storm"
+	"github.com/asdine/storm/q"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/ltcsuite/ltcwallet/waddrmgr"
+	"github.com/ltcsuite/ltcwallet/walletdb"
+)
+
+// wifKeyScopes are the key scopes of the address types that may prefix a WIF
+// encoded private key, as done by Electrum e.g. "p2wpkh-p2sh:<wif>".
+var wifKeyScopes = map[string]waddrmgr.KeyScope{
+	"p2pkh":       waddrmgr.KeyScopeBIP0044,
+	"p2wpkh-p2sh": waddrmgr.KeyScopeBIP0049Plus,
+	"p2wpkh":      waddrmgr.KeyScopeBIP0084,
+}
+
+// parseWIF decodes the provided WIF encoded private key and returns it along
+// with the key scope of the address to import it as. The address type may be
+// set by prefixing the key with "p2pkh:", "p2wpkh-p2sh:" or "p2wpkh:",
+// otherwise uncompressed keys have legacy P2PKH addresses and compressed keys
+// have addresses of the provided default scope.
+func parseWIF(wif string, defaultScope waddrmgr.KeyScope, chainParams *chaincfg.Params) (*ltcutil.WIF, waddrmgr.KeyScope, error) {
+	scope := defaultScope
+	wif = strings.TrimSpace(wif)
+	i := strings.Index(wif, ":")
+	if i >= 0 {
+		var ok bool
+		if scope, ok = wifKeyScopes[strings.ToLower(wif[:i])]; !ok {
+			return nil, scope, errors.E(utils.ErrInvalid, "unsupported address type "+wif[:i])
+		}
+		wif = wif[i+1:]
+	}
+
+	decodedWIF, err := ltcutil.DecodeWIF(wif)
+	if err != nil {
+		return nil, scope, errors.E(utils.ErrInvalid, err)
+	}
+
+	if !decodedWIF.IsForNet(chainParams) {
+		return nil, scope, errors.E(utils.ErrInvalidNet)
+	}
+
+	// Segwit addresses require compressed public keys.
+	if !decodedWIF.CompressPubKey {
+		if i >= 0 && scope != waddrmgr.KeyScopeBIP0044 {
+			return nil, scope, errors.E(utils.ErrInvalid, "uncompressed keys only have P2PKH addresses")
+		}
+		scope = waddrmgr.KeyScopeBIP0044
+	}
+	return decodedWIF, scope, nil
+}
+
+// ImportPrivateKey imports the provided WIF encoded private key into the
+// imported account and rescans the blockchain for the transactions of its
+// address starting from the block mined around the provided birthday. The
+// address type of the key may be set by prefixing it with "p2pkh:",
+// "p2wpkh-p2sh:" or "p2wpkh:", otherwise uncompressed keys are imported as
+// legacy P2PKH addresses and compressed keys as addresses of the wallet's
+// key scope. The imported address is returned.
+func (asset *Asset) ImportPrivateKey(wif, privPass string, birthday time.Time) (string, error) {
+	const op errors.Op = "ltc.ImportPrivateKey"
+
+	if !asset.WalletOpened() {
+		return "", utils.ErrLTCNotInitialized
+	}
+
+	if asset.IsWatchingOnlyWallet() {
+		return "", errors.E(op, utils.ErrWalletIsWatchOnly)
+	}
+
+	if !asset.IsSynced() {
+		return "", errors.E(op, utils.ErrNotSynced)
+	}
+
+	decodedWIF, scope, err := parseWIF(wif, asset.keyScope(), asset.chainParams)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+
+	bs, err := asset.importBlockStamp(birthday)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+
+	err = asset.UnlockWallet(privPass)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+	defer asset.LockWallet()
+
+	address, err := asset.Internal().LTC.ImportPrivateKey(scope, decodedWIF, bs, false)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+
+	return address, asset.rescanImportedAddress(address, bs.Height)
+}
+
+// WatchAddress watches the provided address, or the address of the provided
+// hex encoded compressed public key, without the ability to spend from it and
+// rescans the blockchain for its transactions starting from the block mined
+// around the provided birthday. Public keys are imported into the imported
+// account as addresses of the wallet's key scope. Since the keys of plain
+// addresses are unknown, they are tracked by the wallet data db and their funds
+// are credited to the imported account as watch only funds. The watched address
+// is returned.
+func (asset *Asset) WatchAddress(pubKeyOrAddress string, birthday time.Time) (string, error) {
+	const op errors.Op = "ltc.WatchAddress"
+
+	if !asset.WalletOpened() {
+		return "", utils.ErrLTCNotInitialized
+	}
+
+	if !asset.IsSynced() {
+		return "", errors.E(op, utils.ErrNotSynced)
+	}
+
+	pubKeyOrAddress = strings.TrimSpace(pubKeyOrAddress)
+	bs, err := asset.importBlockStamp(birthday)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+
+	var addr ltcutil.Address
+	if pubKeyBytes, decodeErr := hex.DecodeString(pubKeyOrAddress); decodeErr == nil {
+		addr, err = asset.importPublicKey(pubKeyBytes, bs)
+	} else {
+		addr, err = asset.watchPlainAddress(pubKeyOrAddress)
+	}
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+
+	return addr.String(), asset.rescanBlocks(bs.Height, []ltcutil.Address{addr})
+}
+
+// importPublicKey imports the provided compressed public key into the
+// imported account of the wallet's key scope.
+func (asset *Asset) importPublicKey(pubKeyBytes []byte, bs *waddrmgr.BlockStamp) (ltcutil.Address, error) {
+	if len(pubKeyBytes) != btcec.PubKeyBytesLenCompressed {
+		return nil, errors.E(utils.ErrInvalid, "the public key must be compressed")
+	}
+
+	key, err := btcec.ParsePubKey(pubKeyBytes)
+	if err != nil {
+		return nil, errors.E(utils.ErrInvalid, err)
+	}
+
+	manager, err := asset.Internal().LTC.Manager.FetchScopedKeyManager(asset.keyScope())
+	if err != nil {
+		return nil, err
+	}
+
+	var addr ltcutil.Address
+	err = walletdb.Update(asset.Internal().LTC.Database(), func(dbtx walletdb.ReadWriteTx) error {
+		ns := dbtx.ReadWriteBucket(wAddrMgrBkt)
+		managedAddr, err := manager.ImportPublicKey(ns, key, bs)
+		if err != nil {
+			return err
+		}
+		addr = managedAddr.Address()
+		return nil
+	})
+	return addr, err
+}
+
+// watchPlainAddress adds the provided address to the watched addresses whose
+// received payments are tracked. The address must not belong to the wallet.
+func (asset *Asset) watchPlainAddress(address string) (ltcutil.Address, error) {
+	addr, err := decodeAddress(address, asset.chainParams)
+	if err != nil {
+		return nil, errors.E(utils.ErrInvalidAddress, err)
+	}
+
+	owned, err := asset.Internal().LTC.HaveAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	if owned {
+		return nil, errors.E(utils.ErrExist, "the address belongs to the wallet")
+	}
+
+	addresses := asset.watchedAddressStrings()
+	for _, watched := range addresses {
+		if watched == addr.String() {
+			return nil, errors.E(utils.ErrExist, "the address is already watched")
+		}
+	}
+	asset.SaveUserConfigValue(sharedW.WatchedAddressesConfigKey, append(addresses, addr.String()))
+	return addr, nil
+}
+
+// watchedAddressStrings returns the plain addresses watched by the wallet.
+func (asset *Asset) watchedAddressStrings() []string {
+	var addresses []string
+	_ = asset.ReadUserConfigValue(sharedW.WatchedAddressesConfigKey, &addresses)
+	return addresses
+}
+
+// watchedAddressSet returns the set of the plain addresses watched by the
+// wallet.
+func (asset *Asset) watchedAddressSet() map[string]bool {
+	addresses := asset.watchedAddressStrings()
+	watched := make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		watched[address] = true
+	}
+	return watched
+}
+
+// watchedTxOutput returns the indexed output of the provided outpoint if it
+// pays to a watched address, otherwise nil.
+func (asset *Asset) watchedTxOutput(outpoint wire.OutPoint) *sharedW.TxOutput {
+	tx := new(sharedW.Transaction)
+	if err := asset.GetWalletDataDb().FindOne("Hash", outpoint.Hash.String(), tx); err != nil {
+		return nil
+	}
+	for _, output := range tx.Outputs {
+		if output.Index == int32(outpoint.Index) && output.WatchOnly {
+			return output
+		}
+	}
+	return nil
+}
+
+// watchOnlyBalance returns the total of the unspent outputs of the watched
+// addresses found in the indexed transactions.
+func (asset *Asset) watchOnlyBalance() (int64, error) {
+	if len(asset.watchedAddressStrings()) == 0 {
+		return 0, nil
+	}
+
+	var txs []*sharedW.Transaction
+	err := asset.GetWalletDataDb().Find(q.True(), &txs)
+	if err != nil && err != storm.ErrNotFound {
+		return 0, err
+	}
+	return sharedW.UnspentWatchOnlyAmount(txs), nil
+}
+
+// watchedAddresses returns the decoded plain addresses watched by the wallet.
+func (asset *Asset) watchedAddresses() []ltcutil.Address {
+	addresses := asset.watchedAddressStrings()
+	addrs := make([]ltcutil.Address, 0, len(addresses))
+	for _, address := range addresses {
+		addr, err := decodeAddress(address, asset.chainParams)
+		if err != nil {
+			log.Errorf("invalid watched address: %v", err)
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// notifyWatchedAddresses registers the plain addresses watched by the wallet
+// with the chain source for their new transactions to be recorded.
+func (asset *Asset) notifyWatchedAddresses() {
+	addrs := asset.watchedAddresses()
+	if len(addrs) == 0 {
+		return
+	}
+
+	if err := asset.chainSource().NotifyReceived(addrs); err != nil {
+		log.Errorf("unable to watch the imported addresses: %v", err)
+	}
+}
+
+// importBlockStamp returns the block stamp of the block mined around the
+// provided birthday.
+func (asset *Asset) importBlockStamp(birthday time.Time) (*waddrmgr.BlockStamp, error) {
+	height, err := asset.BlockHeightAtTime(birthday)
+	if err != nil {
+		return nil, err
+	}
+	return asset.getblockStamp(height)
+}
+
+// rescanImportedAddress rescans the blockchain for the transactions of the
+// provided address starting from the provided height.
+func (asset *Asset) rescanImportedAddress(address string, startHeight int32) error {
+	addr, err := decodeAddress(address, asset.chainParams)
+	if err != nil {
+		return err
+	}
+
+	return asset.rescanBlocks(startHeight, []ltcutil.Address{addr})
+}
@@ -0,0 +1,275 @@
+package ltc
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/asdine/storm"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/ltcutil/hdkeychain"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/ltcsuite/ltcwallet/waddrmgr"
+	"github.com/ltcsuite/ltcwallet/wallet"
+)
+
+func TestParseWIF(t *testing.T) {
+	privKey, _ := btcec.PrivKeyFromBytes(bytes.Repeat([]byte{1}, 32))
+	wif := func(net *chaincfg.Params, compress bool) string {
+		w, err := ltcutil.NewWIF(privKey, net, compress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return w.String()
+	}
+	compressed := wif(&chaincfg.RegressionNetParams, true)
+	uncompressed := wif(&chaincfg.RegressionNetParams, false)
+
+	tests := []struct {
+		name          string
+		wif           string
+		expectedScope waddrmgr.KeyScope
+		wantErr       bool
+	}{
+		{name: "compressed key", wif: compressed, expectedScope: waddrmgr.KeyScopeBIP0084},
+		{name: "surrounding spaces", wif: " " + compressed + " ", expectedScope: waddrmgr.KeyScopeBIP0084},
+		{name: "uncompressed key", wif: uncompressed, expectedScope: waddrmgr.KeyScopeBIP0044},
+		{name: "p2pkh prefix", wif: "p2pkh:" + compressed, expectedScope: waddrmgr.KeyScopeBIP0044},
+		{name: "p2wpkh-p2sh prefix", wif: "P2WPKH-P2SH:" + compressed, expectedScope: waddrmgr.KeyScopeBIP0049Plus},
+		{name: "p2wpkh prefix", wif: "p2wpkh:" + compressed, expectedScope: waddrmgr.KeyScopeBIP0084},
+		{name: "uncompressed p2pkh", wif: "p2pkh:" + uncompressed, expectedScope: waddrmgr.KeyScopeBIP0044},
+		{name: "uncompressed segwit", wif: "p2wpkh:" + uncompressed, wantErr: true},
+		{name: "unknown prefix", wif: "p2tr:" + compressed, wantErr: true},
+		{name: "wrong network", wif: wif(&chaincfg.MainNetParams, true), wantErr: true},
+		{name: "invalid key", wif: "notakey", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			decodedWIF, scope, err := parseWIF(tc.wif, waddrmgr.KeyScopeBIP0084, &chaincfg.RegressionNetParams)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("(%v), expected an error", tc.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("(%v), unexpected error (%v)", tc.name, err)
+			}
+			if scope != tc.expectedScope {
+				t.Errorf("(%v), expected scope (%v), got (%v)", tc.name, tc.expectedScope, scope)
+			}
+			if !decodedWIF.PrivKey.Key.Equals(&privKey.Key) {
+				t.Errorf("(%v), decoded the wrong key", tc.name)
+			}
+		})
+	}
+}
+
+func TestDecodeWatchOnlyTx(t *testing.T) {
+	asset := &Asset{chainParams: &chaincfg.RegressionNetParams}
+	pkScript := func(seed byte) (string, []byte) {
+		addr, err := ltcutil.NewAddressWitnessPubKeyHash(bytes.Repeat([]byte{seed}, 20), asset.chainParams)
+		if err != nil {
+			t.Fatal(err)
+		}
+		script, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return addr.String(), script
+	}
+	watchedAddr, watchedScript := pkScript(1)
+	_, walletScript := pkScript(2)
+	_, externalScript := pkScript(3)
+
+	watchedPrevOut := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 0}
+	walletPrevOut := wire.OutPoint{Hash: chainhash.Hash{2}, Index: 0}
+	externalPrevOut := wire.OutPoint{Hash: chainhash.Hash{3}, Index: 0}
+
+	mtx := wire.NewMsgTx(wire.TxVersion)
+	mtx.AddTxIn(wire.NewTxIn(&watchedPrevOut, nil, nil))
+	mtx.AddTxIn(wire.NewTxIn(&walletPrevOut, nil, nil))
+	mtx.AddTxIn(wire.NewTxIn(&externalPrevOut, nil, nil))
+	mtx.AddTxOut(wire.NewTxOut(100, watchedScript))
+	mtx.AddTxOut(wire.NewTxOut(200, walletScript))
+	mtx.AddTxOut(wire.NewTxOut(300, externalScript))
+
+	watchedOutput := func(prevOut wire.OutPoint) *sharedW.TxOutput {
+		if prevOut == watchedPrevOut {
+			return &sharedW.TxOutput{Amount: 1000, WatchOnly: true}
+		}
+		return nil
+	}
+	walletInputs := []wallet.TransactionSummaryInput{{Index: 1, PreviousAccount: 0, PreviousAmount: 2000}}
+	walletOutputs := []wallet.TransactionSummaryOutput{{Index: 1, Account: 0}}
+
+	inputs, totalInputs := asset.decodeTxInputs(mtx, walletInputs, watchedOutput)
+	outputs, totalOutputs := asset.decodeTxOutputs(mtx, walletOutputs, map[string]bool{watchedAddr: true})
+
+	if totalInputs != 3000 {
+		t.Errorf("expected the wallet inputs total (3000), got (%v)", totalInputs)
+	}
+	if totalOutputs != 300 {
+		t.Errorf("expected the wallet outputs total (300), got (%v)", totalOutputs)
+	}
+
+	tests := []struct {
+		name              string
+		account           int32
+		watchOnly         bool
+		expectedAccount   int32
+		expectedWatchOnly bool
+	}{
+		{name: "watched input", account: inputs[0].AccountNumber, watchOnly: inputs[0].WatchOnly,
+			expectedAccount: ImportedAccountNumber, expectedWatchOnly: true},
+		{name: "wallet input", account: inputs[1].AccountNumber, watchOnly: inputs[1].WatchOnly},
+		{name: "external input", account: inputs[2].AccountNumber, watchOnly: inputs[2].WatchOnly,
+			expectedAccount: -1},
+		{name: "watched output", account: outputs[0].AccountNumber, watchOnly: outputs[0].WatchOnly,
+			expectedAccount: ImportedAccountNumber, expectedWatchOnly: true},
+		{name: "wallet output", account: outputs[1].AccountNumber, watchOnly: outputs[1].WatchOnly},
+		{name: "external output", account: outputs[2].AccountNumber, watchOnly: outputs[2].WatchOnly,
+			expectedAccount: -1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.account != tc.expectedAccount {
+				t.Errorf("(%v), expected account (%v), got (%v)", tc.name, tc.expectedAccount, tc.account)
+			}
+			if tc.watchOnly != tc.expectedWatchOnly {
+				t.Errorf("(%v), expected watch only (%v), got (%v)", tc.name, tc.expectedWatchOnly, tc.watchOnly)
+			}
+		})
+	}
+}
+
+// testDescriptorWallet creates a watch only wallet of the provided descriptor
+// whose databases are created in a temporary directory.
+func testDescriptorWallet(t *testing.T, descriptor string) (*Asset, error) {
+	t.Helper()
+	dir := t.TempDir()
+	db, err := storm.Open(filepath.Join(dir, "wallets.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Init(&sharedW.Wallet{}); err != nil {
+		t.Fatal(err)
+	}
+
+	params := &sharedW.InitParams{
+		RootDir:  dir,
+		NetType:  utils.Simulation,
+		DB:       db,
+		DbDriver: "bdb",
+		LogDir:   dir,
+		Events:   eventbus.New(),
+	}
+	wallet, err := CreateWatchOnlyWalletFromDescriptor("watch only", descriptor, params)
+	if err != nil {
+		return nil, err
+	}
+	asset := wallet.(*Asset)
+	t.Cleanup(asset.Shutdown)
+	return asset, nil
+}
+
+// testDescriptorKeys returns the descriptor keys of n account keys derived
+// from distinct seeds.
+func testDescriptorKeys(t *testing.T, n int) []*sharedW.DescriptorKey {
+	t.Helper()
+	keys := make([]*sharedW.DescriptorKey, 0, n)
+	for i := 0; i < n; i++ {
+		seed := bytes.Repeat([]byte{byte(i + 1)}, hdkeychain.RecommendedSeedLen)
+		key, err := hdkeychain.NewMaster(seed, utils.LTCSimnetParams)
+		if err == nil {
+			key, err = key.Neuter()
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, &sharedW.DescriptorKey{XPub: key.String()})
+	}
+	return keys
+}
+
+func TestCreateWatchOnlyWalletFromDescriptor(t *testing.T) {
+	keys := testDescriptorKeys(t, 3)
+	descriptor := func(descType string, threshold int, keys ...*sharedW.DescriptorKey) string {
+		return (&sharedW.Descriptor{Type: descType, Threshold: threshold, Keys: keys}).String()
+	}
+
+	tests := []struct {
+		name      string
+		desc      string
+		multisig  bool
+		threshold int32
+		// watched is the number of addresses watched by the wallet.
+		watched int
+		wantErr bool
+	}{
+		{name: "single key", desc: descriptor(sharedW.DescriptorWPKH, 0, keys[0])},
+		{
+			name:      "sorted multisig",
+			desc:      descriptor(sharedW.DescriptorWSHSortedMulti, 2, keys...),
+			multisig:  true,
+			threshold: 2,
+			watched:   2 * multisigGapLimit,
+		},
+		{
+			name:      "multisig",
+			desc:      descriptor(sharedW.DescriptorWSHMulti, 3, keys...),
+			multisig:  true,
+			threshold: 3,
+			watched:   2 * multisigGapLimit,
+		},
+		{name: "taproot", desc: descriptor(sharedW.DescriptorTR, 0, keys[0]), wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			asset, err := testDescriptorWallet(t, tc.desc)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("(%v), expected an error", tc.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("(%v), unexpected error (%v)", tc.name, err)
+			}
+
+			if got := asset.IsMultisigWallet(); got != tc.multisig {
+				t.Errorf("(%v), expected multisig (%v), got (%v)", tc.name, tc.multisig, got)
+			}
+			if got := len(asset.watchedAddressStrings()); got != tc.watched {
+				t.Errorf("(%v), expected (%d) watched addresses, got (%d)", tc.name, tc.watched, got)
+			}
+			if !tc.multisig {
+				return
+			}
+
+			cfg := asset.MultisigConfig()
+			if cfg.Threshold != tc.threshold || cfg.LocalAccount != -1 || cfg.KeepKeyOrder != (tc.name == "multisig") {
+				t.Errorf("(%v), unexpected multisig config (%+v)", tc.name, cfg)
+			}
+
+			address, err := asset.CurrentMultisigAddress()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !asset.watchedAddressSet()[address] {
+				t.Errorf("(%v), the current multisig address (%v) is not watched", tc.name, address)
+			}
+		})
+	}
+}
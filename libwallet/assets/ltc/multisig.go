@@ -0,0 +1,1180 @@
+package ltc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+	"time"
+
+	"decred.org/dcrwallet/v3/errors"
+	"github.com/asdine/storm"
+	"github.com/asdine/storm/q"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/ltcutil/hdkeychain"
+	"github.com/ltcsuite/ltcd/ltcutil/psbt"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/ltcsuite/ltcwallet/waddrmgr"
+	"github.com/ltcsuite/ltcwallet/wallet/txrules"
+	"github.com/ltcsuite/ltcwallet/walletdb"
+)
+
+const (
+	// multisigAccountName is the name of the wallet account whose keys are
+	// used as the local keys of the multisig.
+	multisigAccountName = "multisig"
+
+	// multisigGapLimit is the number of addresses per branch that are watched
+	// ahead of the next unused address so that payments to them are tracked.
+	multisigGapLimit = 20
+
+	// txOverheadVSize is the virtual size of the version, locktime, input and
+	// output counts and the segwit marker and flag of a transaction.
+	txOverheadVSize = 11
+
+	// maxMultisigFeeRate is the highest fee rate in litoshis per kvB of the
+	// PSBTs that are signed, as is litecoind's default maxfeerate. It keeps
+	// a PSBT from spending the multisig funds as fee.
+	maxMultisigFeeRate = 10_000_000
+)
+
+// multisigPath is the derivation path of a multisig address and its witness
+// script.
+type multisigPath struct {
+	branch uint32
+	index  uint32
+	script []byte
+	// derivations are the key origins and derivation paths of the keys of
+	// the script, in the order of the keys in the script.
+	derivations []*psbt.Bip32Derivation
+}
+
+// SetupMultisig completes the setup of a wallet created by
+// CreateNewMultisigWallet as an m-of-n P2WSH multisig where m is the provided
+// threshold and n is the number of cosigners plus the local key. The cosigner
+// keys are extended public keys, optionally with their key origin as returned
+// by MultisigXpub. The local key is the extended public key of the dedicated
+// "multisig" account which is created if it doesn't exist. Payments made to
+// the multisig since the provided birthday are tracked.
+func (asset *Asset) SetupMultisig(threshold int32, cosignerXpubs []string, privPass string, birthday time.Time) error {
+	const op errors.Op = "ltc.SetupMultisig"
+
+	if !asset.WalletOpened() {
+		return utils.ErrLTCNotInitialized
+	}
+
+	if asset.IsWatchingOnlyWallet() {
+		return errors.E(op, utils.ErrWalletIsWatchOnly)
+	}
+
+	if !asset.IsMultisigWallet() {
+		return errors.E(op, utils.ErrInvalid, "multisig can only be set up on multisig wallets")
+	}
+
+	if asset.IsMultisig() {
+		return errors.E(op, utils.ErrExist)
+	}
+
+	cfg := &sharedW.MultisigConfig{Threshold: threshold}
+	keys := make([]*sharedW.DescriptorKey, 1, len(cosignerXpubs)+1)
+	for _, xpub := range cosignerXpubs {
+		key, err := sharedW.ParseKeyExpression(xpub)
+		if err != nil {
+			return errors.E(op, utils.ErrInvalid, err)
+		}
+
+		extendedKey, err := hdkeychain.NewKeyFromString(key.XPub)
+		if err != nil {
+			return errors.E(op, utils.ErrInvalid, err)
+		}
+		if extendedKey.IsPrivate() {
+			return errors.E(op, utils.ErrInvalid, "cosigner keys must be extended public keys")
+		}
+		cfg.CosignerXpubs = append(cfg.CosignerXpubs, key.XPub)
+		keys = append(keys, key)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return errors.E(op, utils.ErrInvalid, err)
+	}
+
+	err := asset.UnlockWallet(privPass)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	defer asset.LockWallet()
+
+	account, err := asset.Internal().LTC.AccountNumber(asset.keyScope(), multisigAccountName)
+	if err != nil {
+		account, err = asset.Internal().LTC.NextAccount(asset.keyScope(), multisigAccountName)
+		if err != nil {
+			return errors.E(op, err)
+		}
+	}
+
+	keys[0], err = asset.accountDescriptorKey(account)
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	cfg.LocalAccount = int32(account)
+	cfg.LocalXpub = keys[0].XPub
+	if err := cfg.Validate(); err != nil {
+		return errors.E(op, utils.ErrInvalid, err)
+	}
+
+	desc := &sharedW.Descriptor{
+		Type:      sharedW.DescriptorWSHSortedMulti,
+		Threshold: int(threshold),
+		Keys:      keys,
+	}
+	cfg.Descriptor = desc.String()
+
+	addrs, err := asset.watchMultisigAddresses(cfg, multisigGapLimit)
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	asset.SaveMultisigConfig(cfg)
+
+	if !asset.IsSynced() {
+		// The addresses are tracked from the wallet's birthday on the next
+		// sync.
+		return nil
+	}
+
+	bs, err := asset.importBlockStamp(birthday)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return asset.rescanBlocks(bs.Height, addrs)
+}
+
+// watchMultisig tracks the payments made to the multisig of the provided
+// descriptor without holding any of its keys. The first key of the
+// descriptor is used as the local key of the multisig configuration.
+func (asset *Asset) watchMultisig(desc *sharedW.Descriptor) error {
+	const op errors.Op = "ltc.watchMultisig"
+
+	cfg := &sharedW.MultisigConfig{
+		Threshold:    int32(desc.Threshold),
+		LocalAccount: -1,
+		LocalXpub:    desc.Keys[0].XPub,
+		KeepKeyOrder: desc.Type == sharedW.DescriptorWSHMulti,
+		Descriptor:   desc.String(),
+	}
+	for i, key := range desc.Keys {
+		extendedKey, err := hdkeychain.NewKeyFromString(key.XPub)
+		if err != nil {
+			return errors.E(op, utils.ErrInvalid, err)
+		}
+		if extendedKey.IsPrivate() {
+			return errors.E(op, utils.ErrInvalid, "private keys are not allowed in watch only descriptors")
+		}
+		if i > 0 {
+			cfg.CosignerXpubs = append(cfg.CosignerXpubs, key.XPub)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return errors.E(op, utils.ErrInvalid, err)
+	}
+
+	if _, err := asset.watchMultisigAddresses(cfg, multisigGapLimit); err != nil {
+		return errors.E(op, err)
+	}
+
+	asset.SaveMultisigConfig(cfg)
+	return nil
+}
+
+// MultisigXpub returns the key expression that should be shared with the
+// cosigners as the local key of the multisig. It is the extended public key
+// of the multisig account prefixed with its key origin so that the cosigners
+// and their signing devices know where it was derived from.
+func (asset *Asset) MultisigXpub() (string, error) {
+	if !asset.WalletOpened() {
+		return "", utils.ErrLTCNotInitialized
+	}
+
+	cfg := asset.MultisigConfig()
+	if cfg != nil && cfg.LocalAccount < 0 {
+		return cfg.LocalXpub, nil
+	}
+
+	var account uint32
+	if cfg != nil {
+		account = uint32(cfg.LocalAccount)
+	} else {
+		var err error
+		account, err = asset.Internal().LTC.AccountNumber(asset.keyScope(), multisigAccountName)
+		if err != nil {
+			return "", errors.E(utils.ErrNotExist, "the multisig account has not been created")
+		}
+	}
+
+	key, err := asset.accountDescriptorKey(account)
+	if err != nil {
+		return "", err
+	}
+	return key.String(), nil
+}
+
+// CreateMultisigAccount creates the account whose extended public key is
+// the local key of the multisig. It is a no-op if the account exists.
+func (asset *Asset) CreateMultisigAccount(privPass string) error {
+	if _, err := asset.MultisigXpub(); err == nil {
+		return nil
+	}
+
+	_, err := asset.CreateNewAccount(multisigAccountName, privPass)
+	return err
+}
+
+// RestoreMultisig sets up the multisig of a multisig wallet restored from a
+// backup whose configuration has been imported. The multisig account is
+// created again, unless the wallet was recreated as a watch only wallet, and
+// the addresses watched by the backed up wallet are watched again for the
+// wallet's rescan to find the payments made to the multisig.
+func (asset *Asset) RestoreMultisig(privPass string) error {
+	const op errors.Op = "ltc.RestoreMultisig"
+
+	cfg := asset.MultisigConfig()
+	switch {
+	case cfg == nil && asset.IsWatchingOnlyWallet():
+		return nil
+	case cfg == nil:
+		// The multisig is set up once the keys of the cosigners are known.
+		if err := asset.CreateMultisigAccount(privPass); err != nil {
+			return errors.E(op, err)
+		}
+		return nil
+	case asset.IsWatchingOnlyWallet():
+		// The local keys are not restored without the seed, the multisig
+		// is watched.
+		cfg.LocalAccount = -1
+	case cfg.LocalAccount >= 0:
+		if err := asset.restoreMultisigAccount(cfg, privPass); err != nil {
+			return errors.E(op, err)
+		}
+	}
+
+	// The change addresses used before the backup are skipped once the
+	// rescan finds them.
+	upTo := cfg.ImportedIndex
+	cfg.ImportedIndex, cfg.InternalIndex = 0, 0
+	if _, err := asset.watchMultisigAddresses(cfg, upTo); err != nil {
+		return errors.E(op, err)
+	}
+
+	asset.SaveMultisigConfig(cfg)
+	return nil
+}
+
+// restoreMultisigAccount creates the accounts of a restored wallet up to the
+// multisig account so that it gets its number back and checks that its key is
+// the local key of the multisig. The accounts created before it are named
+// after their number, their names are not backed up.
+func (asset *Asset) restoreMultisigAccount(cfg *sharedW.MultisigConfig, privPass string) error {
+	err := asset.UnlockWallet(privPass)
+	if err != nil {
+		return err
+	}
+	defer asset.LockWallet()
+
+	scope := asset.keyScope()
+	for {
+		if _, err := asset.Internal().LTC.AccountName(scope, uint32(cfg.LocalAccount)); err == nil {
+			break
+		}
+
+		account, err := asset.Internal().LTC.NextAccount(scope, multisigAccountName)
+		if err != nil {
+			return err
+		}
+		if account == uint32(cfg.LocalAccount) {
+			break
+		}
+
+		err = asset.Internal().LTC.RenameAccount(scope, account, fmt.Sprintf("account %d", account))
+		if err != nil {
+			return err
+		}
+	}
+
+	key, err := asset.accountDescriptorKey(uint32(cfg.LocalAccount))
+	if err != nil {
+		return err
+	}
+	if key.XPub != cfg.LocalXpub {
+		return errors.E(utils.ErrInvalid, "the multisig local key is not derived from the wallet seed")
+	}
+	return nil
+}
+
+// CurrentMultisigAddress returns the first unused receive address of the
+// multisig.
+func (asset *Asset) CurrentMultisigAddress() (string, error) {
+	const op errors.Op = "ltc.CurrentMultisigAddress"
+
+	cfg := asset.MultisigConfig()
+	if cfg == nil {
+		return "", errors.E(op, utils.ErrNotExist)
+	}
+
+	used, err := asset.usedAddresses()
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+
+	for {
+		path, err := asset.multisigScript(cfg, 0, cfg.ExternalIndex)
+		if err != nil {
+			return "", errors.E(op, err)
+		}
+
+		addr, err := asset.multisigAddress(path.script)
+		if err != nil {
+			return "", errors.E(op, err)
+		}
+
+		if !used[addr.String()] {
+			if err := asset.extendMultisigScripts(cfg, cfg.ExternalIndex); err != nil {
+				return "", errors.E(op, err)
+			}
+			asset.SaveMultisigConfig(cfg)
+			return addr.String(), nil
+		}
+
+		cfg.ExternalIndex++
+	}
+}
+
+// NextMultisigAddress moves past the current receive address of the multisig
+// and returns the next unused one.
+func (asset *Asset) NextMultisigAddress() (string, error) {
+	cfg := asset.MultisigConfig()
+	if cfg == nil {
+		return "", errors.E("ltc.NextMultisigAddress", utils.ErrNotExist)
+	}
+
+	cfg.ExternalIndex++
+	asset.SaveMultisigConfig(cfg)
+	return asset.CurrentMultisigAddress()
+}
+
+// MultisigUnspentOutputs returns the outputs paying to the multisig that
+// are not spent by any of the transactions indexed by the wallet.
+func (asset *Asset) MultisigUnspentOutputs() ([]*sharedW.UnspentOutput, error) {
+	const op errors.Op = "ltc.MultisigUnspentOutputs"
+
+	cfg := asset.MultisigConfig()
+	if cfg == nil {
+		return nil, errors.E(op, utils.ErrNotExist)
+	}
+
+	paths, err := asset.multisigPaths(cfg, cfg.ImportedIndex)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	// The multisig addresses are watched addresses whose payments are only
+	// held by the wallet data db.
+	txs, err := asset.indexedTransactions()
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	bestHeight := asset.GetBestBlockHeight()
+	utxos := make([]*sharedW.UnspentOutput, 0)
+	sharedW.ForEachUnspentWatchOnlyOutput(txs, func(tx *sharedW.Transaction, output *sharedW.TxOutput) {
+		path, ok := paths[output.Address]
+		if !ok {
+			return
+		}
+
+		var confirmations int32
+		if tx.BlockHeight > 0 {
+			confirmations = bestHeight - tx.BlockHeight + 1
+		}
+
+		utxos = append(utxos, &sharedW.UnspentOutput{
+			TxID:          tx.Hash,
+			Vout:          uint32(output.Index),
+			Address:       output.Address,
+			ScriptPubKey:  hex.EncodeToString(payToWitnessScriptHash(path.script)),
+			Amount:        asset.ToAmount(output.Amount),
+			Confirmations: confirmations,
+			Spendable:     true,
+			ReceiveTime:   time.Unix(tx.Timestamp, 0),
+		})
+	})
+	return utxos, nil
+}
+
+// MultisigBalance returns the spendable balance of the multisig.
+func (asset *Asset) MultisigBalance() (sharedW.AssetAmount, error) {
+	utxos, err := asset.MultisigUnspentOutputs()
+	if err != nil {
+		return nil, err
+	}
+
+	var balance int64
+	for _, utxo := range utxos {
+		balance += utxo.Amount.ToInt()
+	}
+	return asset.ToAmount(balance), nil
+}
+
+// CreateMultisigPSBT creates an unsigned PSBT that spends from the multisig
+// to the provided address. If sendMax is true the whole multisig balance
+// less the fee is sent and amount is ignored. The PSBT is returned base64
+// encoded.
+func (asset *Asset) CreateMultisigPSBT(toAddress string, amount int64, sendMax bool) (string, error) {
+	const op errors.Op = "ltc.CreateMultisigPSBT"
+
+	cfg := asset.MultisigConfig()
+	if cfg == nil {
+		return "", errors.E(op, utils.ErrNotExist)
+	}
+
+	dest, err := decodeAddress(toAddress, asset.chainParams)
+	if err != nil {
+		return "", errors.E(op, utils.ErrInvalidAddress, err)
+	}
+
+	destScript, err := txscript.PayToAddrScript(dest)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+
+	if !sendMax && amount <= 0 {
+		return "", errors.E(op, utils.ErrInvalid, "invalid amount")
+	}
+
+	utxos, err := asset.MultisigUnspentOutputs()
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+
+	// Spend the largest outputs first to keep the number of inputs low.
+	sort.Slice(utxos, func(i, j int) bool {
+		return utxos[i].Amount.ToInt() > utxos[j].Amount.ToInt()
+	})
+
+	paths, err := asset.multisigPaths(cfg, cfg.ImportedIndex)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+
+	changePath, err := asset.unusedMultisigChange(cfg)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+	changeScript := payToWitnessScriptHash(changePath.script)
+
+	feeRate := asset.GetUserFeeRate().ToInt()
+	inputVSize := multisigInputVSize(int(cfg.Threshold), len(cfg.Xpubs()))
+	outputsVSize := int64(wire.NewTxOut(0, destScript).SerializeSize())
+	if !sendMax {
+		outputsVSize += int64(wire.NewTxOut(0, changeScript).SerializeSize())
+	}
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	inputs := make([]*sharedW.UnspentOutput, 0)
+	var total, fee int64
+	for _, utxo := range utxos {
+		outPoint, err := parseOutPoint(utxo)
+		if err != nil {
+			return "", errors.E(op, err)
+		}
+
+		tx.AddTxIn(wire.NewTxIn(outPoint, nil, nil))
+		inputs = append(inputs, utxo)
+		total += utxo.Amount.ToInt()
+
+		vsize := txOverheadVSize + int64(len(inputs))*inputVSize + outputsVSize
+		fee = vsize * feeRate / 1000
+		if !sendMax && total >= amount+fee {
+			break
+		}
+	}
+
+	if sendMax {
+		amount = total - fee
+	}
+
+	if len(inputs) == 0 || amount <= 0 || total < amount+fee {
+		return "", errors.E(op, utils.ErrInsufficientBalance)
+	}
+
+	destOutput := wire.NewTxOut(amount, destScript)
+	if txrules.IsDustOutput(destOutput, txrules.DefaultRelayFeePerKb) {
+		return "", errors.E(op, utils.ErrInvalid, "the amount is too small")
+	}
+	tx.AddTxOut(destOutput)
+
+	changeIndex := -1
+	if change := total - amount - fee; change > 0 {
+		changeOutput := wire.NewTxOut(change, changeScript)
+		// A dust change is left to the miners as part of the fee.
+		if !txrules.IsDustOutput(changeOutput, txrules.DefaultRelayFeePerKb) {
+			tx.AddTxOut(changeOutput)
+			changeIndex = len(tx.TxOut) - 1
+		}
+	}
+
+	// To discourage fee sniping, LockTime is explicitly set in the raw tx.
+	tx.LockTime = uint32(asset.GetBestBlockHeight())
+
+	packet, err := psbt.NewFromUnsignedTx(tx)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+
+	for i, utxo := range inputs {
+		pkScript, err := hex.DecodeString(utxo.ScriptPubKey)
+		if err != nil {
+			return "", errors.E(op, err)
+		}
+
+		packet.Inputs[i].WitnessUtxo = wire.NewTxOut(utxo.Amount.ToInt(), pkScript)
+		packet.Inputs[i].WitnessScript = paths[utxo.Address].script
+		packet.Inputs[i].SighashType = txscript.SigHashAll
+		packet.Inputs[i].Bip32Derivation = paths[utxo.Address].derivations
+	}
+
+	if changeIndex >= 0 {
+		packet.Outputs[changeIndex].WitnessScript = changePath.script
+		packet.Outputs[changeIndex].Bip32Derivation = changePath.derivations
+
+		cfg.InternalIndex++
+		if err := asset.extendMultisigScripts(cfg, cfg.InternalIndex); err != nil {
+			return "", errors.E(op, err)
+		}
+		asset.SaveMultisigConfig(cfg)
+	}
+
+	return packet.B64Encode()
+}
+
+// SignMultisigPSBT adds the signatures of the local key to the inputs of the
+// provided base64 encoded PSBT. The PSBT is only signed if all its inputs
+// spend from the multisig, its change outputs pay back to the multisig and
+// its fee rate is not above maxMultisigFeeRate, as reported by
+// MultisigPSBTInfo which should be shown to the user beforehand. The updated
+// PSBT is returned base64 encoded.
+func (asset *Asset) SignMultisigPSBT(b64PSBT, privPass string) (string, error) {
+	const op errors.Op = "ltc.SignMultisigPSBT"
+
+	cfg := asset.MultisigConfig()
+	if cfg == nil {
+		return "", errors.E(op, utils.ErrNotExist)
+	}
+
+	if cfg.LocalAccount < 0 {
+		return "", errors.E(op, utils.ErrWalletIsWatchOnly)
+	}
+
+	packet, err := psbt.NewFromRawBytes(strings.NewReader(strings.TrimSpace(b64PSBT)), true)
+	if err != nil {
+		return "", errors.E(op, utils.ErrInvalid, err)
+	}
+
+	paths, err := asset.multisigPaths(cfg, cfg.ImportedIndex+multisigGapLimit)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+
+	if _, err := checkMultisigPSBT(packet, paths, int(cfg.Threshold), len(cfg.Xpubs())); err != nil {
+		return "", errors.E(op, utils.ErrInvalid, err)
+	}
+
+	err = asset.UnlockWallet(privPass)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+	defer asset.LockWallet()
+
+	signed, err := signMultisigInputs(packet, paths, func(path *multisigPath) (*btcec.PrivateKey, error) {
+		return asset.multisigPrivKey(cfg, path)
+	})
+	if err != nil {
+		return "", errors.E(op, utils.ErrInvalid, err)
+	}
+
+	if signed == 0 {
+		return "", errors.E(op, utils.ErrInvalid, "the PSBT does not spend from this multisig")
+	}
+
+	return packet.B64Encode()
+}
+
+// signMultisigInputs signs the inputs of the packet that spend from the
+// provided multisig paths with the keys returned by privKey. The number of
+// signed inputs is returned.
+func signMultisigInputs(packet *psbt.Packet, paths map[string]*multisigPath,
+	privKey func(*multisigPath) (*btcec.PrivateKey, error)) (int, error) {
+	byScript := make(map[string]*multisigPath, len(paths))
+	for _, path := range paths {
+		byScript[hex.EncodeToString(path.script)] = path
+	}
+
+	for i, input := range packet.Inputs {
+		if input.WitnessUtxo == nil {
+			return 0, fmt.Errorf("input %d has no witness utxo", i)
+		}
+	}
+	sigHashes := txscript.NewTxSigHashes(packet.UnsignedTx)
+
+	updater, err := psbt.NewUpdater(packet)
+	if err != nil {
+		return 0, err
+	}
+
+	var signed int
+	for i, input := range packet.Inputs {
+		path, ok := byScript[hex.EncodeToString(input.WitnessScript)]
+		if !ok {
+			continue
+		}
+
+		key, err := privKey(path)
+		if err != nil {
+			return 0, err
+		}
+
+		sig, err := txscript.RawTxInWitnessSignature(packet.UnsignedTx, sigHashes, i,
+			input.WitnessUtxo.Value, path.script, txscript.SigHashAll, key)
+		if err != nil {
+			return 0, err
+		}
+
+		_, err = updater.Sign(i, sig, key.PubKey().SerializeCompressed(), nil, path.script)
+		if err != nil {
+			return 0, err
+		}
+		signed++
+	}
+	return signed, nil
+}
+
+// multisigPSBTSummary is the fee and change of a multisig PSBT.
+type multisigPSBTSummary struct {
+	fee int64
+	// feeRate is the fee per kvB of the estimated signed size.
+	feeRate int64
+	// change holds the indexes of the outputs paying to the multisig.
+	change map[int]bool
+}
+
+// checkMultisigPSBT checks that all the inputs of the packet spend from the
+// provided multisig paths, that the outputs with a witness script pay to the
+// multisig and that the fee rate is not above maxMultisigFeeRate. m and n are
+// the threshold and number of keys of the multisig used to estimate the
+// signed size of the transaction.
+func checkMultisigPSBT(packet *psbt.Packet, paths map[string]*multisigPath, m, n int) (*multisigPSBTSummary, error) {
+	byScript := make(map[string]*multisigPath, len(paths))
+	byPkScript := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		byScript[hex.EncodeToString(path.script)] = path
+		byPkScript[hex.EncodeToString(payToWitnessScriptHash(path.script))] = true
+	}
+
+	var totalIn, totalOut int64
+	for i, input := range packet.Inputs {
+		if input.WitnessUtxo == nil {
+			return nil, fmt.Errorf("input %d has no witness utxo", i)
+		}
+
+		path, ok := byScript[hex.EncodeToString(input.WitnessScript)]
+		if !ok {
+			return nil, fmt.Errorf("input %d does not spend from this multisig", i)
+		}
+
+		if !bytes.Equal(input.WitnessUtxo.PkScript, payToWitnessScriptHash(path.script)) {
+			return nil, fmt.Errorf("the witness script of input %d does not match its output script", i)
+		}
+		totalIn += input.WitnessUtxo.Value
+	}
+
+	summary := &multisigPSBTSummary{change: make(map[int]bool)}
+	vsize := txOverheadVSize + int64(len(packet.Inputs))*multisigInputVSize(m, n)
+	for i, txOut := range packet.UnsignedTx.TxOut {
+		isChange := byPkScript[hex.EncodeToString(txOut.PkScript)]
+		witnessScript := packet.Outputs[i].WitnessScript
+		if len(witnessScript) > 0 && (!isChange || !bytes.Equal(payToWitnessScriptHash(witnessScript), txOut.PkScript)) {
+			return nil, fmt.Errorf("output %d is marked as change but does not pay to this multisig", i)
+		}
+
+		summary.change[i] = isChange
+		totalOut += txOut.Value
+		vsize += int64(txOut.SerializeSize())
+	}
+
+	summary.fee = totalIn - totalOut
+	if summary.fee < 0 {
+		return nil, fmt.Errorf("the outputs spend more than the inputs")
+	}
+
+	summary.feeRate = summary.fee * 1000 / vsize
+	if summary.feeRate > maxMultisigFeeRate {
+		return nil, fmt.Errorf("the fee rate of %d lit/kvB is too high", summary.feeRate)
+	}
+	return summary, nil
+}
+
+// CombineMultisigPSBTs merges the signatures of the provided base64 encoded
+// copies of the same PSBT. Only as many signatures as the threshold are kept
+// per input. The combined PSBT is returned base64 encoded.
+func (asset *Asset) CombineMultisigPSBTs(b64PSBTs []string) (string, error) {
+	const op errors.Op = "ltc.CombineMultisigPSBTs"
+
+	cfg := asset.MultisigConfig()
+	if cfg == nil {
+		return "", errors.E(op, utils.ErrNotExist)
+	}
+
+	if len(b64PSBTs) == 0 {
+		return "", errors.E(op, utils.ErrInvalid, "no PSBT provided")
+	}
+
+	packets := make([]*psbt.Packet, 0, len(b64PSBTs))
+	for _, b64PSBT := range b64PSBTs {
+		packet, err := psbt.NewFromRawBytes(strings.NewReader(strings.TrimSpace(b64PSBT)), true)
+		if err != nil {
+			return "", errors.E(op, utils.ErrInvalid, err)
+		}
+		packets = append(packets, packet)
+	}
+
+	combined, err := combineMultisigPackets(packets, int(cfg.Threshold))
+	if err != nil {
+		return "", errors.E(op, utils.ErrInvalid, err)
+	}
+	return combined.B64Encode()
+}
+
+// combineMultisigPackets merges the partial signatures of the provided
+// packets into the first one, keeping at most threshold signatures per
+// input.
+func combineMultisigPackets(packets []*psbt.Packet, threshold int) (*psbt.Packet, error) {
+	combined := packets[0]
+	for _, packet := range packets[1:] {
+		if packet.UnsignedTx.TxHash() != combined.UnsignedTx.TxHash() {
+			return nil, fmt.Errorf("the PSBTs spend different transactions")
+		}
+
+		for i, input := range packet.Inputs {
+			for _, sig := range input.PartialSigs {
+				if len(combined.Inputs[i].PartialSigs) >= threshold {
+					break
+				}
+				if !hasPartialSig(combined.Inputs[i].PartialSigs, sig.PubKey) {
+					combined.Inputs[i].PartialSigs = append(combined.Inputs[i].PartialSigs, sig)
+				}
+			}
+		}
+	}
+	return combined, nil
+}
+
+// MultisigPSBTInfo decodes the provided base64 encoded PSBT and returns a
+// summary of what it pays, the change it sends back to the multisig, its fee
+// and how many signatures it has. It fails for the PSBTs that
+// SignMultisigPSBT refuses to sign.
+func (asset *Asset) MultisigPSBTInfo(b64PSBT string) (*sharedW.MultisigPSBTInfo, error) {
+	const op errors.Op = "ltc.MultisigPSBTInfo"
+
+	cfg := asset.MultisigConfig()
+	if cfg == nil {
+		return nil, errors.E(op, utils.ErrNotExist)
+	}
+
+	packet, err := psbt.NewFromRawBytes(strings.NewReader(strings.TrimSpace(b64PSBT)), true)
+	if err != nil {
+		return nil, errors.E(op, utils.ErrInvalid, err)
+	}
+
+	paths, err := asset.multisigPaths(cfg, cfg.ImportedIndex+multisigGapLimit)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	summary, err := checkMultisigPSBT(packet, paths, int(cfg.Threshold), len(cfg.Xpubs()))
+	if err != nil {
+		return nil, errors.E(op, utils.ErrInvalid, err)
+	}
+
+	info := &sharedW.MultisigPSBTInfo{
+		TxHash:     packet.UnsignedTx.TxHash().String(),
+		Signatures: -1,
+		Threshold:  cfg.Threshold,
+		Fee:        asset.ToAmount(summary.fee),
+		FeeRate:    asset.ToAmount(summary.feeRate),
+		Outputs:    make(map[string]sharedW.AssetAmount),
+		Change:     make(map[string]sharedW.AssetAmount),
+	}
+
+	for _, input := range packet.Inputs {
+		if info.Signatures == -1 || int32(len(input.PartialSigs)) < info.Signatures {
+			info.Signatures = int32(len(input.PartialSigs))
+		}
+	}
+
+	for i, txOut := range packet.UnsignedTx.TxOut {
+		address := hex.EncodeToString(txOut.PkScript)
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(txOut.PkScript, asset.chainParams)
+		if err == nil && len(addrs) > 0 {
+			address = addrs[0].String()
+		}
+
+		if summary.change[i] {
+			info.Change[address] = asset.ToAmount(txOut.Value)
+		} else {
+			info.Outputs[address] = asset.ToAmount(txOut.Value)
+		}
+	}
+
+	return info, nil
+}
+
+// BroadcastMultisigPSBT finalizes the provided base64 encoded PSBT and
+// publishes the resulting transaction. It fails if any of the inputs does
+// not have enough signatures. The hash of the published transaction is
+// returned.
+func (asset *Asset) BroadcastMultisigPSBT(b64PSBT string) (string, error) {
+	const op errors.Op = "ltc.BroadcastMultisigPSBT"
+
+	if !asset.WalletOpened() {
+		return "", utils.ErrLTCNotInitialized
+	}
+
+	packet, err := psbt.NewFromRawBytes(strings.NewReader(strings.TrimSpace(b64PSBT)), true)
+	if err != nil {
+		return "", errors.E(op, utils.ErrInvalid, err)
+	}
+
+	if err := psbt.MaybeFinalizeAll(packet); err != nil {
+		return "", errors.E(op, utils.ErrInvalid, err)
+	}
+
+	tx, err := psbt.Extract(packet)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+
+	if err := asset.Internal().LTC.PublishTransaction(tx, ""); err != nil {
+		return "", errors.E(op, utils.TranslateError(err))
+	}
+	return tx.TxHash().String(), nil
+}
+
+// multisigScript returns the witness script of the multisig address at the
+// provided branch and index. Unless the key order is kept, the keys are
+// sorted as described in BIP67 so that every cosigner derives the same
+// script.
+func (asset *Asset) multisigScript(cfg *sharedW.MultisigConfig, branch, index uint32) (*multisigPath, error) {
+	origins := multisigKeyOrigins(cfg)
+	xpubs := cfg.Xpubs()
+	pubKeys := make([]*ltcutil.AddressPubKey, 0, len(xpubs))
+	derivations := make(map[string]*psbt.Bip32Derivation, len(xpubs))
+	for _, xpub := range xpubs {
+		key, err := hdkeychain.NewKeyFromString(xpub)
+		if err != nil {
+			return nil, err
+		}
+
+		branchKey, err := key.Derive(branch)
+		if err != nil {
+			return nil, err
+		}
+
+		childKey, err := branchKey.Derive(index)
+		if err != nil {
+			return nil, err
+		}
+
+		pubKey, err := childKey.ECPubKey()
+		if err != nil {
+			return nil, err
+		}
+
+		addrPubKey, err := ltcutil.NewAddressPubKey(pubKey.SerializeCompressed(), asset.chainParams)
+		if err != nil {
+			return nil, err
+		}
+		pubKeys = append(pubKeys, addrPubKey)
+
+		fingerprint, originPath, err := multisigKeyOrigin(key, origins[xpub])
+		if err != nil {
+			return nil, err
+		}
+		derivations[string(addrPubKey.ScriptAddress())] = &psbt.Bip32Derivation{
+			PubKey:               addrPubKey.ScriptAddress(),
+			MasterKeyFingerprint: fingerprint,
+			Bip32Path:            append(originPath, branch, index),
+		}
+	}
+
+	if !cfg.KeepKeyOrder {
+		sort.Slice(pubKeys, func(i, j int) bool {
+			return bytes.Compare(pubKeys[i].ScriptAddress(), pubKeys[j].ScriptAddress()) < 0
+		})
+	}
+
+	script, err := txscript.MultiSigScript(pubKeys, int(cfg.Threshold))
+	if err != nil {
+		return nil, err
+	}
+
+	path := &multisigPath{branch: branch, index: index, script: script}
+	for _, pubKey := range pubKeys {
+		path.derivations = append(path.derivations, derivations[string(pubKey.ScriptAddress())])
+	}
+	return path, nil
+}
+
+// multisigKeyOrigins returns the keys of the multisig descriptor whose origin
+// is known keyed by extended public key.
+func multisigKeyOrigins(cfg *sharedW.MultisigConfig) map[string]*sharedW.DescriptorKey {
+	origins := make(map[string]*sharedW.DescriptorKey)
+	desc, err := sharedW.ParseDescriptor(cfg.Descriptor)
+	if err != nil {
+		return origins
+	}
+
+	for _, key := range desc.Keys {
+		if key.Fingerprint != 0 || len(key.OriginPath) > 0 {
+			origins[key.XPub] = key
+		}
+	}
+	return origins
+}
+
+// multisigKeyOrigin returns the master key fingerprint, in the byte order of
+// PSBT key derivations, and the origin path of the provided multisig key. A
+// key whose origin is unknown is its own master key, as for descriptor keys
+// without key origin.
+func multisigKeyOrigin(key *hdkeychain.ExtendedKey, origin *sharedW.DescriptorKey) (uint32, []uint32, error) {
+	if origin != nil {
+		originPath := make([]uint32, len(origin.OriginPath), len(origin.OriginPath)+2)
+		copy(originPath, origin.OriginPath)
+		return bits.ReverseBytes32(origin.Fingerprint), originPath, nil
+	}
+
+	pubKey, err := key.ECPubKey()
+	if err != nil {
+		return 0, nil, err
+	}
+	return binary.LittleEndian.Uint32(ltcutil.Hash160(pubKey.SerializeCompressed())[:4]), nil, nil
+}
+
+// unusedMultisigChange returns the path of the first unused change address
+// of the multisig from the change index on. The change index is reset when
+// the wallet is restored from a backup.
+func (asset *Asset) unusedMultisigChange(cfg *sharedW.MultisigConfig) (*multisigPath, error) {
+	used, err := asset.usedAddresses()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		path, err := asset.multisigScript(cfg, 1, cfg.InternalIndex)
+		if err != nil {
+			return nil, err
+		}
+
+		addr, err := asset.multisigAddress(path.script)
+		if err != nil {
+			return nil, err
+		}
+
+		if !used[addr.String()] {
+			return path, nil
+		}
+
+		cfg.InternalIndex++
+	}
+}
+
+// multisigAddress returns the P2WSH address of the provided witness script.
+func (asset *Asset) multisigAddress(script []byte) (ltcutil.Address, error) {
+	scriptHash := sha256.Sum256(script)
+	return ltcutil.NewAddressWitnessScriptHash(scriptHash[:], asset.chainParams)
+}
+
+// multisigPaths returns the derivation paths of the multisig addresses of
+// both branches below the provided index keyed by address.
+func (asset *Asset) multisigPaths(cfg *sharedW.MultisigConfig, upTo uint32) (map[string]*multisigPath, error) {
+	paths := make(map[string]*multisigPath, 2*upTo)
+	for _, branch := range []uint32{0, 1} {
+		for index := uint32(0); index < upTo; index++ {
+			path, err := asset.multisigScript(cfg, branch, index)
+			if err != nil {
+				return nil, err
+			}
+
+			addr, err := asset.multisigAddress(path.script)
+			if err != nil {
+				return nil, err
+			}
+			paths[addr.String()] = path
+		}
+	}
+	return paths, nil
+}
+
+// extendMultisigScripts watches the multisig addresses up to the gap limit
+// past the provided index if they are not watched yet.
+func (asset *Asset) extendMultisigScripts(cfg *sharedW.MultisigConfig, index uint32) error {
+	if index+multisigGapLimit <= cfg.ImportedIndex {
+		return nil
+	}
+
+	addrs, err := asset.watchMultisigAddresses(cfg, index+multisigGapLimit)
+	if err != nil {
+		return err
+	}
+
+	if asset.chainClient != nil && asset.IsSynced() {
+		return asset.chainSource().NotifyReceived(addrs)
+	}
+	return nil
+}
+
+// watchMultisigAddresses adds the multisig addresses of both branches up to
+// the provided index to the watched addresses for the wallet to track the
+// payments made to them. ltcwallet cannot import witness scripts, so they
+// are tracked like the plain watched addresses, which are not added twice.
+// The addresses from the imported index up to the provided index are
+// returned.
+func (asset *Asset) watchMultisigAddresses(cfg *sharedW.MultisigConfig, upTo uint32) ([]ltcutil.Address, error) {
+	watched := asset.watchedAddressStrings()
+	isWatched := make(map[string]bool, len(watched))
+	for _, addr := range watched {
+		isWatched[addr] = true
+	}
+
+	addrs := make([]ltcutil.Address, 0)
+	for _, branch := range []uint32{0, 1} {
+		for index := cfg.ImportedIndex; index < upTo; index++ {
+			path, err := asset.multisigScript(cfg, branch, index)
+			if err != nil {
+				return nil, err
+			}
+
+			addr, err := asset.multisigAddress(path.script)
+			if err != nil {
+				return nil, err
+			}
+
+			if !isWatched[addr.String()] {
+				watched = append(watched, addr.String())
+			}
+			addrs = append(addrs, addr)
+		}
+	}
+
+	asset.SaveUserConfigValue(sharedW.WatchedAddressesConfigKey, watched)
+	cfg.ImportedIndex = upTo
+	return addrs, nil
+}
+
+// indexedTransactions returns all the transactions indexed by the wallet
+// data db.
+func (asset *Asset) indexedTransactions() ([]*sharedW.Transaction, error) {
+	var txs []*sharedW.Transaction
+	err := asset.GetWalletDataDb().Find(q.True(), &txs)
+	if err != nil && err != storm.ErrNotFound {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// usedAddresses returns the addresses paid by the transactions indexed by
+// the wallet data db.
+func (asset *Asset) usedAddresses() (map[string]bool, error) {
+	txs, err := asset.indexedTransactions()
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]bool)
+	for _, tx := range txs {
+		for _, output := range tx.Outputs {
+			used[output.Address] = true
+		}
+	}
+	return used, nil
+}
+
+// multisigPrivKey returns the private key of the local key at the provided
+// multisig path. The wallet must be unlocked.
+func (asset *Asset) multisigPrivKey(cfg *sharedW.MultisigConfig, path *multisigPath) (*btcec.PrivateKey, error) {
+	manager, err := asset.Internal().LTC.Manager.FetchScopedKeyManager(asset.keyScope())
+	if err != nil {
+		return nil, err
+	}
+
+	var privKey *btcec.PrivateKey
+	err = walletdb.View(asset.Internal().LTC.Database(), func(dbtx walletdb.ReadTx) error {
+		ns := dbtx.ReadBucket(wAddrMgrBkt)
+		addr, err := manager.DeriveFromKeyPath(ns, waddrmgr.DerivationPath{
+			InternalAccount: uint32(cfg.LocalAccount),
+			Account:         uint32(cfg.LocalAccount),
+			Branch:          path.branch,
+			Index:           path.index,
+		})
+		if err != nil {
+			return err
+		}
+
+		pubKeyAddr, ok := addr.(waddrmgr.ManagedPubKeyAddress)
+		if !ok {
+			return fmt.Errorf("unexpected address type %T", addr)
+		}
+
+		privKey, err = pubKeyAddr.PrivKey()
+		return err
+	})
+	return privKey, err
+}
+
+// multisigInputVSize returns the virtual size of an input spending an m-of-n
+// P2WSH multisig output.
+func multisigInputVSize(m, n int) int64 {
+	// outpoint + empty script sig length + sequence.
+	const nonWitnessSize = 32 + 4 + 1 + 4
+	// items count + the empty item consumed by the OP_CHECKMULTISIG bug + m
+	// signatures + the witness script: OP_m, n 33 byte pushes, OP_n and
+	// OP_CHECKMULTISIG.
+	scriptSize := 3 + n*34
+	witnessSize := 1 + 1 + m*(1+72) + wire.VarIntSerializeSize(uint64(scriptSize)) + scriptSize
+	return int64(nonWitnessSize + (witnessSize+3)/4)
+}
+
+// payToWitnessScriptHash returns the P2WSH output script of the provided
+// witness script.
+func payToWitnessScriptHash(script []byte) []byte {
+	scriptHash := sha256.Sum256(script)
+	pkScript, _ := txscript.NewScriptBuilder().AddOp(txscript.OP_0).AddData(scriptHash[:]).Script()
+	return pkScript
+}
+
+func hasPartialSig(sigs []*psbt.PartialSig, pubKey []byte) bool {
+	for _, sig := range sigs {
+		if bytes.Equal(sig.PubKey, pubKey) {
+			return true
+		}
+	}
+	return false
+}
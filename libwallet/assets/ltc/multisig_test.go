@@ -0,0 +1,591 @@
+package ltc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/ltcutil/hdkeychain"
+	"github.com/ltcsuite/ltcd/ltcutil/psbt"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/ltcsuite/ltcwallet/wallet"
+)
+
+// testMultisigKeys returns n account keys derived from distinct seeds.
+func testMultisigKeys(t *testing.T, n int) []*hdkeychain.ExtendedKey {
+	t.Helper()
+	keys := make([]*hdkeychain.ExtendedKey, 0, n)
+	for i := 0; i < n; i++ {
+		seed := bytes.Repeat([]byte{byte(i + 1)}, hdkeychain.RecommendedSeedLen)
+		key, err := hdkeychain.NewMaster(seed, &chaincfg.RegressionNetParams)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func testMultisigConfig(t *testing.T, keys []*hdkeychain.ExtendedKey, threshold int32, keepKeyOrder bool) *sharedW.MultisigConfig {
+	t.Helper()
+	cfg := &sharedW.MultisigConfig{Threshold: threshold, KeepKeyOrder: keepKeyOrder}
+	for i, key := range keys {
+		pubKey, err := key.Neuter()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			cfg.LocalXpub = pubKey.String()
+		} else {
+			cfg.CosignerXpubs = append(cfg.CosignerXpubs, pubKey.String())
+		}
+	}
+	return cfg
+}
+
+func TestMultisigScript(t *testing.T) {
+	asset := &Asset{chainParams: &chaincfg.RegressionNetParams}
+	keys := testMultisigKeys(t, 3)
+	reversed := []*hdkeychain.ExtendedKey{keys[2], keys[1], keys[0]}
+
+	script := func(keys []*hdkeychain.ExtendedKey, threshold int32, keepKeyOrder bool, index uint32) []byte {
+		path, err := asset.multisigScript(testMultisigConfig(t, keys, threshold, keepKeyOrder), 0, index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return path.script
+	}
+
+	tests := []struct {
+		name  string
+		a, b  []byte
+		equal bool
+	}{
+		{
+			name:  "sorted keys ignore the key order",
+			a:     script(keys, 2, false, 0),
+			b:     script(reversed, 2, false, 0),
+			equal: true,
+		},
+		{
+			name: "kept key order",
+			a:    script(keys, 2, true, 0),
+			b:    script(reversed, 2, true, 0),
+		},
+		{
+			name: "threshold",
+			a:    script(keys, 2, false, 0),
+			b:    script(keys, 3, false, 0),
+		},
+		{
+			name: "index",
+			a:    script(keys, 2, false, 0),
+			b:    script(keys, 2, false, 1),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := bytes.Equal(tc.a, tc.b); got != tc.equal {
+				t.Errorf("(%v), expected equal scripts (%v), got (%v)", tc.name, tc.equal, got)
+			}
+		})
+	}
+
+	class, _, required, err := txscript.ExtractPkScriptAddrs(script(keys, 2, false, 0), asset.chainParams)
+	if err != nil || class != txscript.MultiSigTy || required != 2 {
+		t.Errorf("expected a 2 of 3 multisig script, got (%v) requiring (%d): %v", class, required, err)
+	}
+
+	addr, err := asset.multisigAddress(script(keys, 2, false, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript := payToWitnessScriptHash(script(keys, 2, false, 0))
+	if class := txscript.GetScriptClass(pkScript); class != txscript.WitnessV0ScriptHashTy {
+		t.Errorf("expected a P2WSH output script, got (%v)", class)
+	}
+	if expected, _ := txscript.PayToAddrScript(addr); !bytes.Equal(expected, pkScript) {
+		t.Errorf("expected the output script of (%v), got (%x)", addr, pkScript)
+	}
+}
+
+func TestMultisigScriptDerivations(t *testing.T) {
+	asset := &Asset{chainParams: &chaincfg.RegressionNetParams}
+	keys := testMultisigKeys(t, 3)
+	cfg := testMultisigConfig(t, keys, 2, false)
+
+	// The origin of the local key is known, the cosigner keys are their own
+	// master keys.
+	originPath := []uint32{84 + hdkeychain.HardenedKeyStart, 1 + hdkeychain.HardenedKeyStart, hdkeychain.HardenedKeyStart}
+	desc := &sharedW.Descriptor{Type: sharedW.DescriptorWSHSortedMulti, Threshold: 2}
+	for i, xpub := range cfg.Xpubs() {
+		key := &sharedW.DescriptorKey{XPub: xpub}
+		if i == 0 {
+			key.Fingerprint, key.OriginPath = 0x01020304, originPath
+		}
+		desc.Keys = append(desc.Keys, key)
+	}
+	cfg.Descriptor = desc.String()
+
+	expected := make(map[string]*psbt.Bip32Derivation)
+	for i, key := range keys {
+		childKey, err := key.Derive(1)
+		if err == nil {
+			childKey, err = childKey.Derive(5)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubKey, err := childKey.ECPubKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		derivation := &psbt.Bip32Derivation{PubKey: pubKey.SerializeCompressed(), Bip32Path: []uint32{1, 5}}
+		if i == 0 {
+			derivation.MasterKeyFingerprint = 0x04030201
+			derivation.Bip32Path = append(originPath, 1, 5)
+		} else {
+			masterKey, err := key.ECPubKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			derivation.MasterKeyFingerprint = binary.LittleEndian.Uint32(ltcutil.Hash160(masterKey.SerializeCompressed())[:4])
+		}
+		expected[string(derivation.PubKey)] = derivation
+	}
+
+	path, err := asset.multisigScript(cfg, 1, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(path.script, asset.chainParams)
+	if err != nil || len(addrs) != len(path.derivations) {
+		t.Fatalf("expected (%d) key derivations, got (%d): %v", len(addrs), len(path.derivations), err)
+	}
+	for i, derivation := range path.derivations {
+		if !bytes.Equal(derivation.PubKey, addrs[i].ScriptAddress()) {
+			t.Errorf("the derivation (%d) is not the derivation of the key (%d) of the script", i, i)
+		}
+		if !reflect.DeepEqual(derivation, expected[string(derivation.PubKey)]) {
+			t.Errorf("expected (%+v), got (%+v)", expected[string(derivation.PubKey)], derivation)
+		}
+	}
+}
+
+func TestMultisigSignAndCombine(t *testing.T) {
+	asset := &Asset{chainParams: &chaincfg.RegressionNetParams}
+	keys := testMultisigKeys(t, 3)
+	cfg := testMultisigConfig(t, keys, 2, false)
+
+	path, err := asset.multisigScript(cfg, 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := map[string]*multisigPath{"address": path}
+	prevOut := wire.NewTxOut(100000, payToWitnessScriptHash(path.script))
+
+	newPacket := func(t *testing.T, value int64) *psbt.Packet {
+		t.Helper()
+		tx := wire.NewMsgTx(wire.TxVersion)
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+		tx.AddTxOut(wire.NewTxOut(value, prevOut.PkScript))
+		packet, err := psbt.NewFromUnsignedTx(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		packet.Inputs[0].WitnessUtxo = prevOut
+		packet.Inputs[0].WitnessScript = path.script
+		packet.Inputs[0].SighashType = txscript.SigHashAll
+		return packet
+	}
+
+	signWith := func(signer int) func(*multisigPath) (*btcec.PrivateKey, error) {
+		return func(path *multisigPath) (*btcec.PrivateKey, error) {
+			branchKey, err := keys[signer].Derive(path.branch)
+			if err != nil {
+				return nil, err
+			}
+			childKey, err := branchKey.Derive(path.index)
+			if err != nil {
+				return nil, err
+			}
+			return childKey.ECPrivKey()
+		}
+	}
+
+	tests := []struct {
+		name       string
+		signers    []int
+		values     []int64
+		signatures int
+		combineErr bool
+		final      bool
+	}{
+		{name: "single signature", signers: []int{0}, values: []int64{90000}, signatures: 1},
+		{name: "threshold met", signers: []int{0, 2}, values: []int64{90000, 90000}, signatures: 2, final: true},
+		{name: "extra signatures", signers: []int{0, 1, 2}, values: []int64{90000, 90000, 90000}, signatures: 2, final: true},
+		{name: "duplicate signatures", signers: []int{1, 1}, values: []int64{90000, 90000}, signatures: 1},
+		{name: "different transactions", signers: []int{0, 1}, values: []int64{90000, 80000}, combineErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			packets := make([]*psbt.Packet, 0, len(tc.signers))
+			for i, signer := range tc.signers {
+				packet := newPacket(t, tc.values[i])
+				signed, err := signMultisigInputs(packet, paths, signWith(signer))
+				if err != nil || signed != 1 {
+					t.Fatalf("(%v), expected one signed input, got (%d): %v", tc.name, signed, err)
+				}
+				packets = append(packets, packet)
+			}
+
+			combined, err := combineMultisigPackets(packets, int(cfg.Threshold))
+			if (err != nil) != tc.combineErr {
+				t.Fatalf("(%v), expected combine error (%v), got (%v)", tc.name, tc.combineErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if got := len(combined.Inputs[0].PartialSigs); got != tc.signatures {
+				t.Errorf("(%v), expected (%d) signatures, got (%d)", tc.name, tc.signatures, got)
+			}
+
+			err = psbt.MaybeFinalizeAll(combined)
+			if (err == nil) != tc.final {
+				t.Fatalf("(%v), expected finalized (%v), got (%v)", tc.name, tc.final, err)
+			}
+			if err != nil {
+				return
+			}
+
+			tx, err := psbt.Extract(combined)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vm, err := txscript.NewEngine(prevOut.PkScript, tx, 0, txscript.StandardVerifyFlags, nil,
+				txscript.NewTxSigHashes(tx), prevOut.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := vm.Execute(); err != nil {
+				t.Errorf("(%v), expected a valid spend, got (%v)", tc.name, err)
+			}
+		})
+	}
+
+	// Inputs that don't spend from the multisig are left unsigned.
+	packet := newPacket(t, 90000)
+	signed, err := signMultisigInputs(packet, map[string]*multisigPath{}, signWith(0))
+	if err != nil || signed != 0 {
+		t.Errorf("expected no signed input, got (%d): %v", signed, err)
+	}
+}
+
+func TestCheckMultisigPSBT(t *testing.T) {
+	asset := &Asset{chainParams: &chaincfg.RegressionNetParams}
+	keys := testMultisigKeys(t, 3)
+	cfg := testMultisigConfig(t, keys, 2, false)
+
+	derivePath := func(branch, index uint32) *multisigPath {
+		path, err := asset.multisigScript(cfg, branch, index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	inputPath, changePath := derivePath(0, 0), derivePath(1, 0)
+	paths := map[string]*multisigPath{"input": inputPath, "change": changePath}
+
+	// The witness script of a multisig the wallet is not part of.
+	otherCfg := testMultisigConfig(t, testMultisigKeys(t, 4)[1:], 2, false)
+	otherPath, err := asset.multisigScript(otherCfg, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	externalScript := payToWitnessScriptHash(otherPath.script)
+
+	// newPacket spends 100000 from the multisig to an external output and
+	// a change output.
+	newPacket := func(t *testing.T, sendValue, changeValue int64) *psbt.Packet {
+		t.Helper()
+		tx := wire.NewMsgTx(wire.TxVersion)
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+		tx.AddTxOut(wire.NewTxOut(sendValue, externalScript))
+		tx.AddTxOut(wire.NewTxOut(changeValue, payToWitnessScriptHash(changePath.script)))
+		packet, err := psbt.NewFromUnsignedTx(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		packet.Inputs[0].WitnessUtxo = wire.NewTxOut(100000, payToWitnessScriptHash(inputPath.script))
+		packet.Inputs[0].WitnessScript = inputPath.script
+		packet.Outputs[1].WitnessScript = changePath.script
+		return packet
+	}
+
+	tests := []struct {
+		name        string
+		packet      func(t *testing.T) *psbt.Packet
+		expectedFee int64
+		wantErr     bool
+	}{
+		{
+			name:        "valid spend",
+			packet:      func(t *testing.T) *psbt.Packet { return newPacket(t, 60000, 39000) },
+			expectedFee: 1000,
+		},
+		{
+			name: "missing witness utxo",
+			packet: func(t *testing.T) *psbt.Packet {
+				packet := newPacket(t, 60000, 39000)
+				packet.Inputs[0].WitnessUtxo = nil
+				return packet
+			},
+			wantErr: true,
+		},
+		{
+			name: "foreign input",
+			packet: func(t *testing.T) *psbt.Packet {
+				packet := newPacket(t, 60000, 39000)
+				packet.Inputs[0].WitnessUtxo.PkScript = externalScript
+				packet.Inputs[0].WitnessScript = otherPath.script
+				return packet
+			},
+			wantErr: true,
+		},
+		{
+			name: "witness script not matching the output script",
+			packet: func(t *testing.T) *psbt.Packet {
+				packet := newPacket(t, 60000, 39000)
+				packet.Inputs[0].WitnessUtxo.PkScript = externalScript
+				return packet
+			},
+			wantErr: true,
+		},
+		{
+			name: "fake change output",
+			packet: func(t *testing.T) *psbt.Packet {
+				packet := newPacket(t, 60000, 39000)
+				packet.Outputs[0].WitnessScript = changePath.script
+				return packet
+			},
+			wantErr: true,
+		},
+		{
+			name:    "outputs above the inputs",
+			packet:  func(t *testing.T) *psbt.Packet { return newPacket(t, 60000, 41000) },
+			wantErr: true,
+		},
+		{
+			name: "fee rate too high",
+			packet: func(t *testing.T) *psbt.Packet {
+				packet := newPacket(t, 60000, 39000)
+				packet.Inputs[0].WitnessUtxo.Value = 10000000
+				return packet
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			summary, err := checkMultisigPSBT(tc.packet(t), paths, int(cfg.Threshold), len(cfg.Xpubs()))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("(%v), expected an error", tc.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("(%v), unexpected error (%v)", tc.name, err)
+			}
+			if summary.fee != tc.expectedFee {
+				t.Errorf("(%v), expected fee (%v), got (%v)", tc.name, tc.expectedFee, summary.fee)
+			}
+			if summary.change[0] || !summary.change[1] {
+				t.Errorf("(%v), expected only the second output as change, got (%v)", tc.name, summary.change)
+			}
+		})
+	}
+}
+
+// testMultisigWallet returns a watch only wallet of a 2 of 3 multisig.
+func testMultisigWallet(t *testing.T) *Asset {
+	t.Helper()
+	keys := testDescriptorKeys(t, 3)
+	desc := &sharedW.Descriptor{Type: sharedW.DescriptorWSHSortedMulti, Threshold: 2, Keys: keys}
+	asset, err := testDescriptorWallet(t, desc.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return asset
+}
+
+func TestWatchMultisigAddresses(t *testing.T) {
+	asset := testMultisigWallet(t)
+	cfg := asset.MultisigConfig()
+
+	// A plain watched address is kept along with the multisig addresses.
+	plainAddr, err := ltcutil.NewAddressWitnessPubKeyHash(bytes.Repeat([]byte{1}, 20), asset.chainParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	watched := append(asset.watchedAddressStrings(), plainAddr.String())
+	asset.SaveUserConfigValue(sharedW.WatchedAddressesConfigKey, watched)
+
+	tests := []struct {
+		name string
+		// importedIndex is the imported index the addresses are watched from.
+		importedIndex uint32
+		upTo          uint32
+		// expectedAddrs is the number of addresses returned.
+		expectedAddrs int
+		// expectedWatched is the number of addresses watched afterwards.
+		expectedWatched int
+	}{
+		{
+			name:            "already watched",
+			importedIndex:   0,
+			upTo:            multisigGapLimit,
+			expectedAddrs:   2 * multisigGapLimit,
+			expectedWatched: 2*multisigGapLimit + 1,
+		},
+		{
+			name:            "extended",
+			importedIndex:   multisigGapLimit,
+			upTo:            multisigGapLimit + 5,
+			expectedAddrs:   10,
+			expectedWatched: 2*multisigGapLimit + 11,
+		},
+		{
+			name:            "watched again from the start",
+			importedIndex:   0,
+			upTo:            multisigGapLimit + 5,
+			expectedAddrs:   2*multisigGapLimit + 10,
+			expectedWatched: 2*multisigGapLimit + 11,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg.ImportedIndex = tc.importedIndex
+			addrs, err := asset.watchMultisigAddresses(cfg, tc.upTo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(addrs) != tc.expectedAddrs {
+				t.Errorf("(%v), expected (%d) addresses, got (%d)", tc.name, tc.expectedAddrs, len(addrs))
+			}
+			if cfg.ImportedIndex != tc.upTo {
+				t.Errorf("(%v), expected imported index (%d), got (%d)", tc.name, tc.upTo, cfg.ImportedIndex)
+			}
+
+			watched := asset.watchedAddressStrings()
+			if len(watched) != tc.expectedWatched || len(asset.watchedAddressSet()) != len(watched) {
+				t.Errorf("(%v), expected (%d) distinct watched addresses, got (%v)", tc.name, tc.expectedWatched, watched)
+			}
+			if !asset.watchedAddressSet()[plainAddr.String()] {
+				t.Errorf("(%v), the plain watched address is no longer watched", tc.name)
+			}
+		})
+	}
+}
+
+func TestMultisigTxIndex(t *testing.T) {
+	asset := testMultisigWallet(t)
+
+	receiveAddr, err := asset.CurrentMultisigAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := decodeAddress(receiveAddr, asset.chainParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary := func(tx *wire.MsgTx) wallet.TransactionSummary {
+		var buf bytes.Buffer
+		if err := tx.Serialize(&buf); err != nil {
+			t.Fatal(err)
+		}
+		hash := tx.TxHash()
+		return wallet.TransactionSummary{Hash: &hash, Transaction: buf.Bytes()}
+	}
+
+	// The multisig is paid in a block.
+	const height = 100
+	fundingTx := wire.NewMsgTx(wire.TxVersion)
+	fundingTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+	fundingTx.AddTxOut(wire.NewTxOut(100000, pkScript))
+	if err := asset.saveTransactions(height, []wallet.TransactionSummary{summary(fundingTx)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := asset.GetWalletDataDb().SaveLastIndexPoint(height); err != nil {
+		t.Fatal(err)
+	}
+
+	nextAddr, err := asset.CurrentMultisigAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nextAddr == receiveAddr {
+		t.Errorf("expected the current multisig address to move past the paid address (%v)", receiveAddr)
+	}
+
+	utxos, err := asset.MultisigUnspentOutputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 1 || utxos[0].Address != receiveAddr || utxos[0].Amount.ToInt() != 100000 {
+		t.Fatalf("expected the multisig payment as the only unspent output, got (%+v)", utxos)
+	}
+
+	// A reorg marks the payment as unmined.
+	err = asset.indexDetachedBlocks(&wallet.TransactionNotifications{DetachedBlocks: []*chainhash.Hash{{2}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := new(sharedW.Transaction)
+	if err := asset.GetWalletDataDb().FindOne("Hash", fundingTx.TxHash().String(), tx); err != nil {
+		t.Fatal(err)
+	}
+	if tx.BlockHeight != sharedW.UnminedTxHeight {
+		t.Errorf("expected the detached tx to be unmined, got height (%d)", tx.BlockHeight)
+	}
+	if lastIndexed, _ := asset.GetWalletDataDb().LastIndexPoint(); lastIndexed != height-1 {
+		t.Errorf("expected the index point at the fork (%d), got (%d)", height-1, lastIndexed)
+	}
+
+	// The multisig output is spent by an unmined tx.
+	fundingHash := fundingTx.TxHash()
+	spendTx := wire.NewMsgTx(wire.TxVersion)
+	spendTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&fundingHash, 0), nil, nil))
+	spendTx.AddTxOut(wire.NewTxOut(90000, pkScript))
+	err = asset.indexUnminedTransaction(asset.decodeTransactionWithTxSummary(sharedW.UnminedTxHeight, summary(spendTx)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	utxos, err = asset.MultisigUnspentOutputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 1 || utxos[0].TxID != spendTx.TxHash().String() {
+		t.Errorf("expected the output of the spending tx as the only unspent output, got (%+v)", utxos)
+	}
+}
@@ -3,9 +3,8 @@ package ltc
 import (
 	"sync/atomic"
 
-	"decred.org/dcrwallet/v3/errors"
 	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
-	"github.com/crypto-power/cryptopower/libwallet/utils"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
 )
 
 func (asset *Asset) listenForTransactions() {
@@ -25,30 +24,35 @@ notificationsLoop:
 				break notificationsLoop
 			}
 
-			txToCache := make([]*sharedW.Transaction, len(n.UnminedTransactions))
-
 			// handle txs hitting the mempool.
-			for i, tx := range n.UnminedTransactions {
+			for _, tx := range n.UnminedTransactions {
 				log.Debugf("(%v) Incoming unmined tx with hash (%v)",
 					asset.GetWalletName(), tx.Hash.String())
 
 				// decodeTxs
-				txToCache[i] = asset.decodeTransactionWithTxSummary(sharedW.UnminedTxHeight, tx)
+				unminedTx := asset.decodeTransactionWithTxSummary(sharedW.UnminedTxHeight, tx)
+				if err := asset.indexUnminedTransaction(unminedTx); err != nil {
+					log.Errorf("(%v) Index unmined tx error: %v", asset.GetWalletName(), err)
+				}
 
 				// publish mempool tx.
-				asset.mempoolTransactionNotification(txToCache[i])
+				asset.mempoolTransactionNotification(unminedTx)
 			}
 
-			if len(n.UnminedTransactions) > 0 {
-				// Since the tx cache receives a fresh update only when a new
-				// block is detected, update cache with the newly received mempool tx(s).
-				asset.txs.mu.Lock()
-				asset.txs.unminedTxs = append(txToCache, asset.txs.unminedTxs...)
-				asset.txs.mu.Unlock()
+			// The txs of the blocks detached by a reorg are unmined until
+			// the blocks attached in their place are indexed.
+			if len(n.DetachedBlocks) > 0 {
+				if err := asset.indexDetachedBlocks(n); err != nil {
+					log.Errorf("(%v) Index detached blocks error: %v", asset.GetWalletName(), err)
+				}
 			}
 
 			// Handle Historical, Connected blocks and newly mined Txs.
 			for _, block := range n.AttachedBlocks {
+				if err := asset.indexAttachedBlock(block); err != nil {
+					log.Errorf("(%v) Index block %d txs error: %v", asset.GetWalletName(), block.Height, err)
+				}
+
 				// When syncing historical data no tx are available.
 				// Txs are reported only when chain is synced and newly mined tx
 				// we discovered in the latest block.
@@ -75,84 +79,23 @@ notificationsLoop:
 	atomic.SwapUint32(&asset.syncData.txlistening, stop)
 }
 
-// AddTxAndBlockNotificationListener registers a set of functions to be invoked
-// when a transaction or block update is processed by the asset. If async is
-// true, the provided callback methods will be called from separate goroutines,
-// allowing notification senders to continue their operation without waiting
-// for the listener to complete processing the notification. This asyncrhonous
-// handling is especially important for cases where the wallet process that
-// sends the notification temporarily prevents access to other wallet features
-// until all notification handlers finish processing the notification. If a
-// notification handler were to try to access such features, it would result
-// in a deadlock.
-func (asset *Asset) AddTxAndBlockNotificationListener(txAndBlockNotificationListener *sharedW.TxAndBlockNotificationListener,
-	uniqueIdentifier string,
-) error {
-	asset.notificationListenersMu.Lock()
-	defer asset.notificationListenersMu.Unlock()
-
-	if _, ok := asset.txAndBlockNotificationListeners[uniqueIdentifier]; ok {
-		return errors.New(utils.ErrListenerAlreadyExist)
-	}
-
-	asset.txAndBlockNotificationListeners[uniqueIdentifier] = &sharedW.TxAndBlockNotificationListener{
-		OnTransaction: func(transaction *sharedW.Transaction) {
-			if txAndBlockNotificationListener.OnTransaction != nil {
-				go txAndBlockNotificationListener.OnTransaction(transaction)
-			}
-		},
-		OnBlockAttached: func(walletID int, blockHeight int32) {
-			if txAndBlockNotificationListener.OnBlockAttached != nil {
-				go txAndBlockNotificationListener.OnBlockAttached(walletID, blockHeight)
-			}
-		},
-		OnTransactionConfirmed: func(walletID int, hash string, blockHeight int32) {
-			if txAndBlockNotificationListener.OnTransactionConfirmed != nil {
-				txAndBlockNotificationListener.OnTransactionConfirmed(walletID, hash, blockHeight)
-			}
-		},
-	}
-	return nil
-}
-
-// RemoveTxAndBlockNotificationListener removes a previously registered
-// transaction and block notification listener.
-func (asset *Asset) RemoveTxAndBlockNotificationListener(uniqueIdentifier string) {
-	asset.notificationListenersMu.Lock()
-	defer asset.notificationListenersMu.Unlock()
-
-	delete(asset.txAndBlockNotificationListeners, uniqueIdentifier)
-}
-
 // mempoolTransactionNotification publishes the txs that hit the mempool for the first time.
 func (asset *Asset) mempoolTransactionNotification(transaction *sharedW.Transaction) {
-	asset.notificationListenersMu.RLock()
-	defer asset.notificationListenersMu.RUnlock()
-
-	for _, txAndBlockNotifcationListener := range asset.txAndBlockNotificationListeners {
-		txAndBlockNotifcationListener.OnTransaction(transaction)
-	}
+	asset.PublishEvent(eventbus.TopicTransaction, transaction)
 }
 
 // publishTransactionConfirmed publishes all the relevant tx identified in a filtered
 // block. A valid list of addresses associated with the current block need to
 // be provided.
 func (asset *Asset) publishTransactionConfirmed(txHash string, blockHeight int32) {
-	asset.notificationListenersMu.RLock()
-	defer asset.notificationListenersMu.RUnlock()
-
-	for _, txAndBlockNotifcationListener := range asset.txAndBlockNotificationListeners {
-		txAndBlockNotifcationListener.OnTransactionConfirmed(asset.ID, txHash, blockHeight)
-	}
+	asset.PublishEvent(eventbus.TopicTransactionConfirmed, &sharedW.TxConfirmation{
+		Hash:        txHash,
+		BlockHeight: blockHeight,
+	})
 }
 
 // publishBlockAttached once the initial sync is complete all the new blocks received
 // are published through this method.
 func (asset *Asset) publishBlockAttached(blockHeight int32) {
-	asset.notificationListenersMu.RLock()
-	defer asset.notificationListenersMu.RUnlock()
-
-	for _, txAndBlockNotifcationListener := range asset.txAndBlockNotificationListeners {
-		txAndBlockNotifcationListener.OnBlockAttached(asset.ID, blockHeight)
-	}
+	asset.PublishEvent(eventbus.TopicBlockAttached, blockHeight)
 }
@@ -0,0 +1,156 @@
+package ltc
+
+import (
+	"context"
+
+	"github.com/asdine/storm/q"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+	w "github.com/ltcsuite/ltcwallet/wallet"
+)
+
+// IndexTransactions saves the transactions mined since the last indexed block,
+// along with those still in the mempool, to the wallet data db. Unmined txs
+// the wallet no longer knows about are removed from the db.
+func (asset *Asset) IndexTransactions() error {
+	if !asset.WalletOpened() {
+		return utils.ErrLTCNotInitialized
+	}
+
+	asset.txIndexMu.Lock()
+	defer asset.txIndexMu.Unlock()
+
+	return asset.indexTransactions()
+}
+
+func (asset *Asset) indexTransactions() error {
+	beginHeight, err := asset.GetWalletDataDb().ReadIndexingStartBlock()
+	if err != nil {
+		log.Errorf("[%d] Get tx indexing start point error: %v", asset.ID, err)
+		return err
+	}
+
+	endHeight := asset.GetBestBlockHeight()
+
+	ctx := context.Background()
+	if asset.syncCtx != nil {
+		ctx = asset.syncCtx
+	}
+
+	log.Infof("[%d] Indexing transactions start height: %d, end height: %d", asset.ID, beginHeight, endHeight)
+
+	// A nil end block includes the unmined txs in the result.
+	startBlock := w.NewBlockIdentifierFromHeight(beginHeight)
+	txResult, err := asset.Internal().LTC.GetTransactions(startBlock, nil, "", ctx.Done())
+	if err != nil {
+		return err
+	}
+
+	for _, block := range txResult.MinedTransactions {
+		if err := asset.saveTransactions(block.Height, block.Transactions); err != nil {
+			return err
+		}
+	}
+
+	unmined := make(map[string]struct{}, len(txResult.UnminedTransactions))
+	for _, transaction := range txResult.UnminedTransactions {
+		unmined[transaction.Hash.String()] = struct{}{}
+	}
+	if err := asset.saveTransactions(sharedW.UnminedTxHeight, txResult.UnminedTransactions); err != nil {
+		return err
+	}
+
+	// Drop the unmined txs that were double spent or abandoned.
+	var savedUnmined []*sharedW.Transaction
+	err = asset.GetWalletDataDb().Find(q.Eq(utils.HeightFilter, sharedW.UnminedTxHeight), &savedUnmined)
+	if err != nil {
+		return err
+	}
+	for _, tx := range savedUnmined {
+		if _, ok := unmined[tx.Hash]; ok {
+			continue
+		}
+		if err := asset.GetWalletDataDb().Delete(tx); err != nil {
+			log.Errorf("[%d] Delete stale unmined tx %s error: %v", asset.ID, tx.Hash, err)
+		}
+	}
+
+	if err := asset.GetWalletDataDb().SaveLastIndexPoint(endHeight); err != nil {
+		log.Errorf("[%d] Set tx index end block height error: %v", asset.ID, err)
+		return err
+	}
+
+	count, err := asset.GetWalletDataDb().Count(utils.TxFilterAll, asset.RequiredConfirmations(), endHeight, &sharedW.Transaction{})
+	if err != nil {
+		log.Errorf("[%d] Post-indexing tx count error :%v", asset.ID, err)
+	} else if count > 0 {
+		log.Infof("[%d] Transaction index finished at %d, %d transaction(s) indexed in total", asset.ID, endHeight, count)
+	}
+	return nil
+}
+
+// indexAttachedBlock saves the transactions of a newly connected block and
+// moves the index point to it. If blocks were connected after the last indexed
+// one without being indexed, the missing range is indexed first.
+func (asset *Asset) indexAttachedBlock(block w.Block) error {
+	asset.txIndexMu.Lock()
+	defer asset.txIndexMu.Unlock()
+
+	lastIndexed, err := asset.GetWalletDataDb().LastIndexPoint()
+	if err != nil {
+		return err
+	}
+	if lastIndexed < block.Height-1 {
+		return asset.indexTransactions()
+	}
+
+	if err := asset.saveTransactions(block.Height, block.Transactions); err != nil {
+		return err
+	}
+	if block.Height > lastIndexed {
+		return asset.GetWalletDataDb().SaveLastIndexPoint(block.Height)
+	}
+	return nil
+}
+
+// indexDetachedBlocks marks the txs of the blocks detached by a reorg as
+// unmined and moves the index point back to the fork, for the blocks attached
+// in their place to be indexed next. The blocks attached by the notification
+// start right above the fork.
+func (asset *Asset) indexDetachedBlocks(n *w.TransactionNotifications) error {
+	asset.txIndexMu.Lock()
+	defer asset.txIndexMu.Unlock()
+
+	lastIndexed, err := asset.GetWalletDataDb().LastIndexPoint()
+	if err != nil {
+		return err
+	}
+	forkHeight := lastIndexed - int32(len(n.DetachedBlocks))
+	if len(n.AttachedBlocks) > 0 {
+		forkHeight = n.AttachedBlocks[0].Height - 1
+	}
+
+	log.Infof("[%d] Reorg of %d block(s), rolling the tx index back to %d", asset.ID, len(n.DetachedBlocks), forkHeight)
+	var detached []*sharedW.Transaction
+	return asset.GetWalletDataDb().RollbackToHeight(&detached, forkHeight, sharedW.UnminedTxHeight)
+}
+
+// indexUnminedTransaction saves a tx that has just hit the mempool.
+func (asset *Asset) indexUnminedTransaction(tx *sharedW.Transaction) error {
+	asset.txIndexMu.Lock()
+	defer asset.txIndexMu.Unlock()
+
+	_, err := asset.GetWalletDataDb().SaveOrUpdate(&sharedW.Transaction{}, tx)
+	return err
+}
+
+func (asset *Asset) saveTransactions(blockHeight int32, txs []w.TransactionSummary) error {
+	for _, transaction := range txs {
+		tx := asset.decodeTransactionWithTxSummary(blockHeight, transaction)
+		if _, err := asset.GetWalletDataDb().SaveOrUpdate(&sharedW.Transaction{}, tx); err != nil {
+			log.Errorf("[%d] Index tx replace tx err : %v", asset.ID, err)
+			return err
+		}
+	}
+	return nil
+}
@@ -2,8 +2,11 @@ package ltc
 
 import (
 	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
 
-	"decred.org/dcrwallet/v3/walletseed"
+	"decred.org/dcrwallet/v3/errors"
 	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
 	"github.com/crypto-power/cryptopower/libwallet/utils"
 	"github.com/ltcsuite/ltcd/chaincfg"
@@ -14,13 +17,6 @@ import (
 
 const (
 	maxAmountLitoshi = ltcutil.MaxSatoshi // MaxSatoshi is the maximum transaction amount allowed in litoshi.
-
-	// TestnetHDPath is the BIP 84 HD path used for deriving addresses on the
-	// test network.
-	TestnetHDPath = "m / 84' / 1' / "
-	// MainnetHDPath is the BIP 84 HD path used for deriving addresses on the
-	// main network.
-	MainnetHDPath = "m / 84' / 0' / "
 )
 
 var wAddrMgrBkt = []byte("waddrmgr")
@@ -35,6 +31,123 @@ func GetScope() waddrmgr.KeyScope {
 	return waddrmgr.KeyScopeBIP0084
 }
 
+// keyScope returns the key scope of the wallet's accounts. It is the default
+// scope returned by GetScope unless the wallet was created from an output
+// descriptor of another script type.
+func (asset *Asset) keyScope() waddrmgr.KeyScope {
+	scope := GetScope()
+	var purpose uint32
+	if err := asset.ReadUserConfigValue(sharedW.AccountScopePurposeConfigKey, &purpose); err == nil && purpose != 0 {
+		scope.Purpose = purpose
+	}
+	return scope
+}
+
+// bip39KeyScopes are the key scopes searched for funds once address discovery
+// of a wallet restored from a BIP39 seed completes, in order of preference.
+var bip39KeyScopes = []waddrmgr.KeyScope{
+	waddrmgr.KeyScopeBIP0084,
+	waddrmgr.KeyScopeBIP0049Plus,
+	waddrmgr.KeyScopeBIP0044,
+}
+
+// selectUsedKeyScope records the BIP44, BIP49 or BIP84 key scopes holding
+// funds in wallets restored from BIP39 seeds and switches the accounts
+// to the scope holding the most funds. Other wallets may have used any of them
+// and address discovery recovers the funds of all the scopes, but only the
+// accounts of one scope are displayed at a time, see SetKeyScopePurpose.
+func (asset *Asset) selectUsedKeyScope() {
+	if !asset.ReadBoolConfigValueForKey(sharedW.BIP39SeedConfigKey, false) {
+		return
+	}
+
+	unspents, err := asset.Internal().LTC.ListUnspent(0, math.MaxInt32, "")
+	if err != nil {
+		log.Errorf("listing unspent outputs failed: %v", err)
+		return
+	}
+
+	balances := make(map[waddrmgr.KeyScope]float64)
+	for _, unspent := range unspents {
+		addr, err := ltcutil.DecodeAddress(unspent.Address, asset.chainParams)
+		if err != nil {
+			continue
+		}
+
+		info, err := asset.Internal().LTC.AddressInfo(addr)
+		if err != nil {
+			continue
+		}
+
+		if pubKeyAddr, ok := info.(waddrmgr.ManagedPubKeyAddress); ok {
+			if scope, _, ok := pubKeyAddr.DerivationInfo(); ok {
+				balances[scope] += unspent.Amount
+			}
+		}
+	}
+
+	used := usedKeyScopes(balances)
+	purposes := make([]uint32, 0, len(used))
+	for _, scope := range used {
+		purposes = append(purposes, scope.Purpose)
+	}
+	asset.SaveUserConfigValue(sharedW.UsedKeyScopePurposesConfigKey, purposes)
+
+	if len(used) > 0 && used[0] != asset.keyScope() {
+		log.Infof("Using the BIP%d accounts of restored wallet %s", used[0].Purpose, asset.GetWalletName())
+		asset.SaveUserConfigValue(sharedW.AccountScopePurposeConfigKey, used[0].Purpose)
+	}
+}
+
+// usedKeyScopes returns the key scopes of bip39KeyScopes holding funds, the
+// scope holding the most funds first. Scopes holding the same funds keep
+// their order of preference.
+func usedKeyScopes(balances map[waddrmgr.KeyScope]float64) []waddrmgr.KeyScope {
+	used := make([]waddrmgr.KeyScope, 0, len(bip39KeyScopes))
+	for _, scope := range bip39KeyScopes {
+		if balances[scope] > 0 {
+			used = append(used, scope)
+		}
+	}
+
+	sort.SliceStable(used, func(i, j int) bool {
+		return balances[used[i]] > balances[used[j]]
+	})
+	return used
+}
+
+// KeyScopePurposes returns the purposes of the key scopes whose accounts can
+// be displayed, the purpose of the displayed accounts first. Wallets restored
+// from BIP39 seeds may hold funds in several scopes, other wallets only have
+// the accounts of one scope.
+func (asset *Asset) KeyScopePurposes() []uint32 {
+	current := asset.keyScope().Purpose
+	purposes := []uint32{current}
+
+	var used []uint32
+	if err := asset.ReadUserConfigValue(sharedW.UsedKeyScopePurposesConfigKey, &used); err != nil {
+		return purposes
+	}
+	for _, purpose := range used {
+		if purpose != current {
+			purposes = append(purposes, purpose)
+		}
+	}
+	return purposes
+}
+
+// SetKeyScopePurpose displays the accounts of the key scope of the provided
+// purpose, one of those returned by KeyScopePurposes.
+func (asset *Asset) SetKeyScopePurpose(purpose uint32) error {
+	for _, p := range asset.KeyScopePurposes() {
+		if p == purpose {
+			asset.SaveUserConfigValue(sharedW.AccountScopePurposeConfigKey, purpose)
+			return nil
+		}
+	}
+	return errors.E(utils.ErrInvalid, fmt.Sprintf("no funds were found in the BIP%d accounts", purpose))
+}
+
 // AmountLTC converts a litoshi amount to a LTC amount.
 func AmountLTC(amount int64) float64 {
 	return ltcutil.Amount(amount).ToBTC()
@@ -55,9 +168,10 @@ func (asset *Asset) ToAmount(v int64) sharedW.AssetAmount {
 	return Amount(ltcutil.Amount(v))
 }
 
-// DeriveAccountXpub derives the xpub for the given account.
-func (asset *Asset) DeriveAccountXpub(seedMnemonic string, account uint32, params *chaincfg.Params) (xpub string, err error) {
-	seed, err := walletseed.DecodeUserInput(seedMnemonic)
+// DeriveAccountXpub derives the xpub for the given account from the seed and
+// its optional passphrase.
+func (asset *Asset) DeriveAccountXpub(seedMnemonic, seedPassphrase string, account uint32, params *chaincfg.Params) (xpub string, err error) {
+	seed, err := sharedW.DecodeSeedMnemonicWithPassphrase(seedMnemonic, seedPassphrase, asset.Type)
 	if err != nil {
 		return "", err
 	}
@@ -74,7 +188,8 @@ func (asset *Asset) DeriveAccountXpub(seedMnemonic string, account uint32, param
 	}
 	defer masterNode.Zero()
 
-	path := []uint32{hardenedKey(GetScope().Purpose), hardenedKey(GetScope().Coin)}
+	scope := asset.keyScope()
+	path := []uint32{hardenedKey(scope.Purpose), hardenedKey(scope.Coin)}
 	path = append(path, hardenedKey(account))
 
 	currentKey := masterNode
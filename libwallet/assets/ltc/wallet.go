@@ -6,7 +6,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"path/filepath"
-	"strings"
+	"sort"
 	"sync"
 	"time"
 
@@ -26,6 +26,7 @@ import (
 	"github.com/ltcsuite/ltcd/ltcutil/gcs"
 	"github.com/ltcsuite/ltcd/wire"
 	ltcwire "github.com/ltcsuite/ltcd/wire"
+	"github.com/ltcsuite/ltcwallet/chain"
 	_ "github.com/ltcsuite/ltcwallet/walletdb/bdb" // bdb init() registers a driver
 )
 
@@ -47,14 +48,17 @@ type Asset struct {
 	cancelSync context.CancelFunc
 	syncCtx    context.Context
 
-	// variables help manage node level tcp connections.
-	dailerCtx    context.Context
-	dailerCancel context.CancelFunc
+	// rpcClient is the full node the wallet syncs from in place of the
+	// neutrino chainClient when it is configured to use RPC sync.
+	rpcClient    chain.Interface
+	bitcoindConn *chain.BitcoindConn
 
-	// This field has been added to cache the expensive call to GetTransactions.
-	// If the best block height hasn't changed there is no need to make another
-	// expensive GetTransactions call.
-	txs txCache
+	// chainService is the neutrino chain service shared with the other
+	// wallets of the network.
+	chainService *sharedChainService
+
+	// txIndexMu serializes the writes of the tx index to the wallet data db.
+	txIndexMu sync.Mutex
 
 	// This fields helps to prevent unnecessary API calls if a new block hasn't
 	// been introduced.
@@ -66,9 +70,8 @@ type Asset struct {
 
 	notificationListenersMu sync.RWMutex
 
-	syncData                        *SyncData
-	txAndBlockNotificationListeners map[string]*sharedW.TxAndBlockNotificationListener
-	blocksRescanProgressListener    *sharedW.BlocksRescanProgressListener
+	syncData                     *SyncData
+	blocksRescanProgressListener *sharedW.BlocksRescanProgressListener
 }
 
 const (
@@ -103,13 +106,17 @@ func CreateNewWallet(pass *sharedW.AuthInfo, params *sharedW.InitParams) (shared
 		return nil, err
 	}
 
+	// The master key fingerprint is the key origin of the output descriptors.
+	if seed, err := w.DecryptSeed(pass.PrivatePass); err == nil {
+		if err := w.SaveMasterKeyFingerprint(seed, ""); err != nil {
+			log.Errorf("saving master key fingerprint failed: %v", err)
+		}
+	}
+
 	ltcWallet := &Asset{
 		Wallet:      w,
 		chainParams: chainParams,
-		syncData: &SyncData{
-			syncProgressListeners: make(map[string]*sharedW.SyncProgressListener),
-		},
-		txAndBlockNotificationListeners: make(map[string]*sharedW.TxAndBlockNotificationListener),
+		syncData:    &SyncData{},
 	}
 
 	if err := ltcWallet.prepareChain(); err != nil {
@@ -121,6 +128,30 @@ func CreateNewWallet(pass *sharedW.AuthInfo, params *sharedW.InitParams) (shared
 	return ltcWallet, nil
 }
 
+// CreateNewMultisigWallet creates a new LTC multisig wallet. The account
+// whose extended public key is the local key of the multisig is created along
+// with the wallet and the multisig is set up by SetupMultisig once the keys
+// of the cosigners are known.
+func CreateNewMultisigWallet(pass *sharedW.AuthInfo, params *sharedW.InitParams) (sharedW.Asset, error) {
+	asset, err := CreateNewWallet(pass, params)
+	if err != nil {
+		return nil, err
+	}
+
+	ltcWallet := asset.(*Asset)
+	ltcWallet.SetBoolConfigValueForKey(sharedW.MultisigWalletConfigKey, true)
+
+	// A multisig wallet without its multisig account is deleted again.
+	if err := ltcWallet.CreateMultisigAccount(pass.PrivatePass); err != nil {
+		if delErr := ltcWallet.DeleteWallet(pass.PrivatePass); delErr != nil {
+			log.Errorf("deleting the multisig wallet failed: %v", delErr)
+		}
+		return nil, err
+	}
+
+	return ltcWallet, nil
+}
+
 func initWalletLoader(chainParams *ltcchaincfg.Params, dbDirPath string) loader.AssetLoader {
 	dirName := ""
 	// testnet datadir takes a special structure to differentiate "testnet4" and "testnet3"
@@ -134,6 +165,7 @@ func initWalletLoader(chainParams *ltcchaincfg.Params, dbDirPath string) loader.
 		DBDirPath:        filepath.Join(dbDirPath, dirName),
 		DefaultDBTimeout: defaultDBTimeout,
 		RecoveryWin:      recoverWindow,
+		Keyscope:         GetScope(),
 	}
 
 	return ltc.NewLoader(conf)
@@ -176,10 +208,7 @@ func CreateWatchOnlyWallet(walletName, extendedPublicKey string, params *sharedW
 	ltcWallet := &Asset{
 		Wallet:      w,
 		chainParams: chainParams,
-		syncData: &SyncData{
-			syncProgressListeners: make(map[string]*sharedW.SyncProgressListener),
-		},
-		txAndBlockNotificationListeners: make(map[string]*sharedW.TxAndBlockNotificationListener),
+		syncData:    &SyncData{},
 	}
 
 	if err := ltcWallet.prepareChain(); err != nil {
@@ -191,6 +220,66 @@ func CreateWatchOnlyWallet(walletName, extendedPublicKey string, params *sharedW
 	return ltcWallet, nil
 }
 
+// CreateWatchOnlyWalletFromDescriptor creates a watch only wallet for the
+// provided output descriptor. Single key descriptors are imported into the
+// key scope of their script type. Multisig descriptors are watched as a
+// multisig and their first key is used as the wallet's default account.
+// Taproot descriptors are not supported.
+func CreateWatchOnlyWalletFromDescriptor(walletName, descriptor string, params *sharedW.InitParams) (sharedW.Asset, error) {
+	desc, err := sharedW.ParseDescriptor(descriptor)
+	if err != nil {
+		return nil, errors.E(utils.ErrInvalid, err)
+	}
+
+	// ltcwallet has no taproot support.
+	if desc.Type == sharedW.DescriptorTR {
+		return nil, errors.E(utils.ErrUnavailable, fmt.Sprintf("%s descriptors are not supported for LTC", desc.Type))
+	}
+
+	chainParams, err := utils.LTCChainParams(params.NetType)
+	if err != nil {
+		return nil, err
+	}
+
+	var purpose uint32
+	if !desc.IsMultisig() && desc.Purpose() != GetScope().Purpose {
+		purpose = desc.Purpose()
+	}
+
+	ldr := initWalletLoader(chainParams, params.RootDir)
+	w, err := sharedW.CreateWatchOnlyWalletWithScope(walletName, desc.Keys[0], purpose,
+		ldr, params, utils.LTCWalletAsset)
+	if err != nil {
+		return nil, err
+	}
+
+	ltcWallet := &Asset{
+		Wallet:      w,
+		chainParams: chainParams,
+		syncData:    &SyncData{},
+	}
+
+	if err := ltcWallet.prepareChain(); err != nil {
+		return nil, err
+	}
+
+	ltcWallet.SetNetworkCancelCallback(ltcWallet.SafelyCancelSync)
+
+	if purpose != 0 {
+		w.SaveUserConfigValue(sharedW.AccountScopePurposeConfigKey, purpose)
+	}
+	w.SaveUserConfigValue(sharedW.WatchOnlyDescriptorConfigKey, desc.String())
+
+	if desc.IsMultisig() {
+		if err := ltcWallet.watchMultisig(desc); err != nil {
+			return nil, err
+		}
+		ltcWallet.SetBoolConfigValueForKey(sharedW.MultisigWalletConfigKey, true)
+	}
+
+	return ltcWallet, nil
+}
+
 // RestoreWallet accepts the seed, wallet pass information and the init parameters.
 // It validates the network type passed by fetching the chain parameters
 // associated with it for the LTC asset. It then generates the LTC loader interface
@@ -210,13 +299,21 @@ func RestoreWallet(seedMnemonic string, pass *sharedW.AuthInfo, params *sharedW.
 		return nil, err
 	}
 
+	// The master key fingerprint is the key origin of the output descriptors.
+	if err := w.SaveMasterKeyFingerprint(seedMnemonic, pass.SeedPassphrase); err != nil {
+		log.Errorf("saving master key fingerprint failed: %v", err)
+	}
+
+	// The accounts of BIP39 seeds are looked up in all the standard key
+	// scopes once address discovery completes.
+	if sharedW.IsBIP39Mnemonic(seedMnemonic) {
+		w.SaveUserConfigValue(sharedW.BIP39SeedConfigKey, true)
+	}
+
 	ltcWallet := &Asset{
 		Wallet:      w,
 		chainParams: chainParams,
-		syncData: &SyncData{
-			syncProgressListeners: make(map[string]*sharedW.SyncProgressListener),
-		},
-		txAndBlockNotificationListeners: make(map[string]*sharedW.TxAndBlockNotificationListener),
+		syncData:    &SyncData{},
 	}
 
 	if err := ltcWallet.prepareChain(); err != nil {
@@ -248,10 +345,7 @@ func LoadExisting(w *sharedW.Wallet, params *sharedW.InitParams) (sharedW.Asset,
 	ltcWallet := &Asset{
 		Wallet:      w,
 		chainParams: chainParams,
-		syncData: &SyncData{
-			syncProgressListeners: make(map[string]*sharedW.SyncProgressListener),
-		},
-		txAndBlockNotificationListeners: make(map[string]*sharedW.TxAndBlockNotificationListener),
+		syncData:    &SyncData{},
 	}
 
 	err = ltcWallet.Prepare(ldr, params)
@@ -286,6 +380,12 @@ func (asset *Asset) SafelyCancelSync() {
 
 	asset.syncData.wg.Wait()
 
+	// The chain service is closed once every wallet of the network released
+	// it. Wallets that never prepared their chain don't hold it.
+	if asset.chainService != nil {
+		asset.chainService.release(asset)
+	}
+
 	// Stop the goroutines left active to manage the wallet functionalities that
 	// don't require activation of sync i.e. wallet rename, password update etc.
 	if asset.WalletOpened() {
@@ -336,6 +436,10 @@ func (asset *Asset) ConnectedPeers() int32 {
 	if !asset.IsConnectedToNetwork() {
 		return -1
 	}
+	if asset.isRPCSync() {
+		// The full node is the wallet's only peer.
+		return 1
+	}
 	return asset.chainClient.CS.ConnectedCount()
 }
 
@@ -346,6 +450,10 @@ func (asset *Asset) IsConnectedToNetwork() bool {
 
 // GetBestBlock returns the best block.
 func (asset *Asset) GetBestBlock() *sharedW.BlockInfo {
+	if asset.isRPCSync() {
+		return asset.rpcBestBlock()
+	}
+
 	block, err := asset.chainClient.CS.BestBlock()
 	if err != nil {
 		log.Error("GetBestBlock hash for LTC failed, Err: ", err)
@@ -370,7 +478,13 @@ func (asset *Asset) GetBestBlockTimeStamp() int64 {
 
 // GetBlockHeight returns the block height for the given block hash.
 func (asset *Asset) GetBlockHeight(hash chainhash.Hash) (int32, error) {
-	height, err := asset.chainClient.GetBlockHeight(&hash)
+	var height int32
+	var err error
+	if asset.isRPCSync() {
+		height, err = asset.rpcBlockHeight(&hash)
+	} else {
+		height, err = asset.chainClient.GetBlockHeight(&hash)
+	}
 	if err != nil {
 		log.Warn("GetBlockHeight for LTC failed, Err: %v", err)
 		return -1, err
@@ -380,7 +494,7 @@ func (asset *Asset) GetBlockHeight(hash chainhash.Hash) (int32, error) {
 
 // GetBlockHash returns the block hash for the given block height.
 func (asset *Asset) GetBlockHash(height int64) (*chainhash.Hash, error) {
-	blockhash, err := asset.chainClient.GetBlockHash(height)
+	blockhash, err := asset.chainSource().GetBlockHash(height)
 	if err != nil {
 		log.Warn("GetBlockHash for LTC failed, Err: %v", err)
 		return nil, err
@@ -476,37 +590,80 @@ func (asset *Asset) VerifyMessage(address, message, signatureBase64 string) (boo
 	}
 }
 
-// RemovePeers removes all peers from the wallet.
+// RemovePeers removes the persistent peers of the wallet.
 func (asset *Asset) RemovePeers() {
-	asset.SaveUserConfigValue(sharedW.SpvPersistentPeerAddressesConfigKey, "")
-	go func() {
-		err := asset.reloadChainService()
-		if err != nil {
-			log.Error(err)
-		}
-	}()
+	cfg := asset.PeerConfig()
+	cfg.Persistent = nil
+	if err := asset.SetPeerConfig(cfg); err != nil {
+		log.Error(err)
+	}
 }
 
-// SetSpecificPeer sets a specific peer to connect to.
+// SetSpecificPeer adds a persistent peer to connect to.
 func (asset *Asset) SetSpecificPeer(address string) {
-	knownAddr := asset.ReadStringConfigValueForKey(sharedW.SpvPersistentPeerAddressesConfigKey, "")
-
-	// Prevent setting same address twice
-	if !strings.Contains(address, ";") && !strings.Contains(knownAddr, address) {
-		if knownAddr == "" {
-			knownAddr = address
-		} else {
-			knownAddr += ";" + address
-		}
+	cfg := asset.PeerConfig()
+	cfg.Persistent = append(cfg.Persistent, address)
+	if err := asset.SetPeerConfig(cfg); err != nil {
+		log.Error(err)
 	}
+}
 
-	asset.SaveUserConfigValue(sharedW.SpvPersistentPeerAddressesConfigKey, knownAddr)
+// SetPeerConfig saves the peer configuration of the wallet and reloads the
+// chain service to apply it.
+func (asset *Asset) SetPeerConfig(cfg *sharedW.PeerConfig) error {
+	if err := asset.SavePeerConfig(cfg); err != nil {
+		return err
+	}
+	// No sync has to be canceled when no wallet is syncing, so the service
+	// is replaced right away and the next sync uses the new peers.
+	if asset.chainService != nil && !asset.chainService.isActive() {
+		return asset.reloadChainService()
+	}
 	go func() {
 		err := asset.reloadChainService()
 		if err != nil {
 			log.Error(err)
 		}
 	}()
+	return nil
+}
+
+// PeerInfoRaw returns the peers the chain service is connected to.
+func (asset *Asset) PeerInfoRaw() ([]sharedW.PeerInfo, error) {
+	if !asset.IsConnectedToNetwork() {
+		return nil, errors.New(utils.ErrNotConnected)
+	}
+	if asset.isRPCSync() {
+		// RPC sync has no peers.
+		return []sharedW.PeerInfo{}, nil
+	}
+
+	peers := asset.chainClient.CS.Peers()
+	infos := make([]sharedW.PeerInfo, 0, len(peers))
+	for _, sp := range peers {
+		stats := sp.StatsSnapshot()
+		info := sharedW.PeerInfo{
+			ID:             stats.ID,
+			Addr:           stats.Addr,
+			Services:       fmt.Sprintf("%08d", uint64(stats.Services)),
+			Version:        stats.Version,
+			SubVer:         stats.UserAgent,
+			StartingHeight: int64(stats.StartingHeight),
+			BestHeight:     int64(stats.LastBlock),
+			LatencyMs:      stats.LastPingMicros / 1000,
+			BytesSent:      stats.BytesSent,
+			BytesReceived:  stats.BytesRecv,
+		}
+		if localAddr := sp.LocalAddr(); localAddr != nil {
+			info.AddrLocal = localAddr.String()
+		}
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+	return infos, nil
 }
 
 // GetExtendedPubKey returns the extended public key of the given account,
@@ -518,17 +675,64 @@ func (asset *Asset) GetExtendedPubKey(account int32) (string, error) {
 		return "", utils.ErrLTCNotInitialized
 	}
 
-	extendedPublicKey, err := loadedAsset.AccountProperties(GetScope(), uint32(account))
+	extendedPublicKey, err := loadedAsset.AccountProperties(asset.keyScope(), uint32(account))
 	if err != nil {
 		return "", err
 	}
 	return extendedPublicKey.AccountPubKey.String(), nil
 }
 
+// GetAccountDescriptor returns the output descriptor of the provided account
+// including its key origin and checksum. Watch only wallets created from a
+// descriptor return that descriptor. The fingerprint of the key origin is
+// 00000000 for wallets created before the fingerprint was recorded.
+func (asset *Asset) GetAccountDescriptor(account int32) (string, error) {
+	if !asset.WalletOpened() {
+		return "", utils.ErrLTCNotInitialized
+	}
+
+	if desc := asset.ReadStringConfigValueForKey(sharedW.WatchOnlyDescriptorConfigKey, ""); desc != "" {
+		return desc, nil
+	}
+
+	key, err := asset.accountDescriptorKey(uint32(account))
+	if err != nil {
+		return "", err
+	}
+
+	desc := &sharedW.Descriptor{
+		Type: sharedW.DescriptorTypeForPurpose(asset.keyScope().Purpose),
+		Keys: []*sharedW.DescriptorKey{key},
+	}
+
+	return desc.String(), nil
+}
+
+// accountDescriptorKey returns the extended public key of the provided
+// account along with its key origin.
+func (asset *Asset) accountDescriptorKey(account uint32) (*sharedW.DescriptorKey, error) {
+	scope := asset.keyScope()
+	props, err := asset.Internal().LTC.AccountProperties(scope, account)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint := props.MasterKeyFingerprint
+	if fingerprint == 0 {
+		fingerprint = asset.MasterKeyFingerprint()
+	}
+
+	return &sharedW.DescriptorKey{
+		Fingerprint: fingerprint,
+		OriginPath:  []uint32{hardenedKey(scope.Purpose), hardenedKey(scope.Coin), hardenedKey(account)},
+		XPub:        props.AccountPubKey.String(),
+	}, nil
+}
+
 // AccountXPubMatches checks if the xpub of the provided account matches the
 // provided xpub.
 func (asset *Asset) AccountXPubMatches(account uint32, xPub string) (bool, error) {
-	acctXPubKey, err := asset.Internal().LTC.AccountProperties(GetScope(), account)
+	acctXPubKey, err := asset.Internal().LTC.AccountProperties(asset.keyScope(), account)
 	if err != nil {
 		return false, err
 	}
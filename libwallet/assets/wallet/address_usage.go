@@ -0,0 +1,162 @@
+package wallet
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SetAddressLabel attaches a user defined label to the provided address.
+// An empty label deletes any label previously set.
+func (wallet *Wallet) SetAddressLabel(address, label string) {
+	key := AddressLabelConfigKeyPrefix + address
+	label = strings.TrimSpace(label)
+	if label == "" {
+		wallet.DeleteUserConfigValueForKey(key)
+		return
+	}
+	wallet.SaveUserConfigValue(key, label)
+}
+
+// AddressLabel returns the label attached to the provided address. An empty
+// string is returned if no label has been set.
+func (wallet *Wallet) AddressLabel(address string) string {
+	return wallet.ReadStringConfigValueForKey(AddressLabelConfigKeyPrefix+address, "")
+}
+
+// PopulateAddressUsage fills in the usage details of the provided addresses
+// i.e. the number of transactions paying to each address, the first and last
+// time the address was seen on chain, its unspent balance and its label.
+func (wallet *Wallet) PopulateAddressUsage(addrs []*AddressUsage, txs []*Transaction,
+	utxos []*UnspentOutput, toAmount func(int64) AssetAmount,
+) {
+	byAddress := make(map[string]*AddressUsage, len(addrs))
+	for _, addr := range addrs {
+		byAddress[addr.Address] = addr
+	}
+
+	for _, tx := range txs {
+		// An address paid more than once in the same tx is only counted once.
+		seen := make(map[string]bool)
+		for _, output := range tx.Outputs {
+			usage, ok := byAddress[output.Address]
+			if !ok || seen[output.Address] {
+				continue
+			}
+			seen[output.Address] = true
+
+			usage.TimesUsed++
+			if usage.FirstSeen == 0 || tx.Timestamp < usage.FirstSeen {
+				usage.FirstSeen = tx.Timestamp
+			}
+			if tx.Timestamp > usage.LastSeen {
+				usage.LastSeen = tx.Timestamp
+			}
+		}
+	}
+
+	balances := make(map[string]int64, len(utxos))
+	for _, utxo := range utxos {
+		balances[utxo.Address] += utxo.Amount.ToInt()
+	}
+
+	for _, addr := range addrs {
+		addr.Balance = toAmount(balances[addr.Address])
+		addr.Label = wallet.AddressLabel(addr.Address)
+	}
+}
+
+// FilterAddressUsage returns the addresses matching the provided address
+// filter. If searchText is not empty, only addresses whose address, label or
+// derivation path contains the search text (case insensitive) are returned.
+func FilterAddressUsage(addrs []*AddressUsage, filter int32, searchText string) []*AddressUsage {
+	searchText = strings.ToLower(strings.TrimSpace(searchText))
+	filtered := make([]*AddressUsage, 0, len(addrs))
+	for _, addr := range addrs {
+		switch filter {
+		case AddressFilterUsed:
+			if addr.TimesUsed == 0 {
+				continue
+			}
+		case AddressFilterUnused:
+			if addr.TimesUsed > 0 {
+				continue
+			}
+		case AddressFilterFunded:
+			if addr.Balance == nil || addr.Balance.ToInt() == 0 {
+				continue
+			}
+		case AddressFilterExternal:
+			if addr.Internal || addr.Imported {
+				continue
+			}
+		case AddressFilterInternal:
+			if !addr.Internal {
+				continue
+			}
+		}
+
+		if searchText != "" &&
+			!strings.Contains(strings.ToLower(addr.Address), searchText) &&
+			!strings.Contains(strings.ToLower(addr.Label), searchText) &&
+			!strings.Contains(strings.ToLower(addr.HDPath), searchText) {
+			continue
+		}
+
+		filtered = append(filtered, addr)
+	}
+	return filtered
+}
+
+// ExportAddressUsageCSV writes the provided addresses to w in the CSV format.
+func ExportAddressUsageCSV(w io.Writer, addrs []*AddressUsage) error {
+	formatTime := func(timestamp int64) string {
+		if timestamp <= 0 {
+			return ""
+		}
+		return time.Unix(timestamp, 0).UTC().Format(time.RFC3339)
+	}
+
+	csvWriter := csv.NewWriter(w)
+	err := csvWriter.Write([]string{"address", "account", "branch", "index", "hd_path",
+		"times_used", "balance", "first_seen", "last_seen", "label"})
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range addrs {
+		branch := "external"
+		switch {
+		case addr.Imported:
+			branch = "imported"
+		case addr.Internal:
+			branch = "internal"
+		}
+
+		balance := ""
+		if addr.Balance != nil {
+			balance = addr.Balance.String()
+		}
+
+		err = csvWriter.Write([]string{
+			addr.Address,
+			strconv.Itoa(int(addr.AccountNumber)),
+			branch,
+			strconv.FormatUint(uint64(addr.Index), 10),
+			addr.HDPath,
+			strconv.Itoa(int(addr.TimesUsed)),
+			balance,
+			formatTime(addr.FirstSeen),
+			formatTime(addr.LastSeen),
+			addr.Label,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
@@ -0,0 +1,135 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// testAmount is a minimal AssetAmount whose coin value is the atom value.
+type testAmount int64
+
+func (a testAmount) ToCoin() float64              { return float64(a) }
+func (a testAmount) String() string               { return strconv.FormatInt(int64(a), 10) }
+func (a testAmount) MulF64(f float64) AssetAmount { return testAmount(float64(a) * f) }
+func (a testAmount) ToInt() int64                 { return int64(a) }
+
+func toTestAmount(v int64) AssetAmount { return testAmount(v) }
+
+func TestPopulateAddressUsage(t *testing.T) {
+	wallet := newTestWallet(t, 1)
+	wallet.SetAddressLabel("addr1", "  savings  ")
+
+	addrs := []*AddressUsage{{Address: "addr1"}, {Address: "addr2"}, {Address: "addr3"}}
+	txs := []*Transaction{
+		{Timestamp: 200, Outputs: []*TxOutput{{Address: "addr1"}, {Address: "addr1"}, {Address: "other"}}},
+		{Timestamp: 100, Outputs: []*TxOutput{{Address: "addr1"}, {Address: "addr2"}}},
+		{Timestamp: 300, Outputs: []*TxOutput{{Address: "addr2"}}},
+	}
+	utxos := []*UnspentOutput{
+		{Address: "addr1", Amount: testAmount(5)},
+		{Address: "addr1", Amount: testAmount(7)},
+		{Address: "other", Amount: testAmount(100)},
+	}
+	wallet.PopulateAddressUsage(addrs, txs, utxos, toTestAmount)
+
+	tests := []struct {
+		address  string
+		expected AddressUsage
+	}{
+		{
+			address: "addr1",
+			expected: AddressUsage{Address: "addr1", TimesUsed: 2, FirstSeen: 100, LastSeen: 200,
+				Balance: testAmount(12), Label: "savings"},
+		},
+		{
+			address: "addr2",
+			expected: AddressUsage{Address: "addr2", TimesUsed: 2, FirstSeen: 100, LastSeen: 300,
+				Balance: testAmount(0)},
+		},
+		{
+			address:  "addr3",
+			expected: AddressUsage{Address: "addr3", Balance: testAmount(0)},
+		},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.address, func(t *testing.T) {
+			if got := *addrs[i]; !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("(%v), expected (%+v), got (%+v)", tc.address, tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestFilterAddressUsage(t *testing.T) {
+	addrs := []*AddressUsage{
+		{Address: "Used1", TimesUsed: 1, Balance: testAmount(10), HDPath: "m/84'/0'/0'/0/0"},
+		{Address: "Used2", TimesUsed: 3, Balance: testAmount(0), Internal: true, HDPath: "m/84'/0'/0'/1/0"},
+		{Address: "unused1", Label: "Rent", HDPath: "m/84'/0'/0'/0/1"},
+		{Address: "imported1", Imported: true, Balance: testAmount(4)},
+	}
+
+	tests := []struct {
+		name       string
+		filter     int32
+		searchText string
+		expected   []string
+	}{
+		{name: "all", filter: AddressFilterAll, expected: []string{"Used1", "Used2", "unused1", "imported1"}},
+		{name: "used", filter: AddressFilterUsed, expected: []string{"Used1", "Used2"}},
+		{name: "unused", filter: AddressFilterUnused, expected: []string{"unused1", "imported1"}},
+		{name: "funded", filter: AddressFilterFunded, expected: []string{"Used1", "imported1"}},
+		{name: "external", filter: AddressFilterExternal, expected: []string{"Used1", "unused1"}},
+		{name: "internal", filter: AddressFilterInternal, expected: []string{"Used2"}},
+		{name: "search address", filter: AddressFilterAll, searchText: "used", expected: []string{"Used1", "Used2", "unused1"}},
+		{name: "search label", filter: AddressFilterAll, searchText: " rent ", expected: []string{"unused1"}},
+		{name: "search path", filter: AddressFilterAll, searchText: "0'/1/", expected: []string{"Used2"}},
+		{name: "filter and search", filter: AddressFilterUsed, searchText: "used1", expected: []string{"Used1"}},
+		{name: "no match", filter: AddressFilterAll, searchText: "none", expected: []string{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filtered := FilterAddressUsage(addrs, tc.filter, tc.searchText)
+			got := make([]string, 0, len(filtered))
+			for _, addr := range filtered {
+				got = append(got, addr.Address)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestExportAddressUsageCSV(t *testing.T) {
+	addrs := []*AddressUsage{
+		{Address: "addr1", AccountNumber: 0, Index: 2, HDPath: "m/84'/0'/0'/0/2", TimesUsed: 1,
+			Balance: testAmount(12), FirstSeen: 1700000000, LastSeen: 1700003600, Label: "a, \"quoted\" label"},
+		{Address: "addr2", AccountNumber: 1, Index: 0, Internal: true},
+		{Address: "addr3", AccountNumber: 2, Imported: true},
+	}
+
+	var buf bytes.Buffer
+	if err := ExportAddressUsageCSV(&buf, addrs); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{
+		{"address", "account", "branch", "index", "hd_path", "times_used", "balance", "first_seen", "last_seen", "label"},
+		{"addr1", "0", "external", "2", "m/84'/0'/0'/0/2", "1", "12", "2023-11-14T22:13:20Z", "2023-11-14T23:13:20Z", "a, \"quoted\" label"},
+		{"addr2", "1", "internal", "0", "", "0", "", "", "", ""},
+		{"addr3", "2", "imported", "0", "", "0", "", "", "", ""},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("expected (%v), got (%v)", expected, records)
+	}
+}
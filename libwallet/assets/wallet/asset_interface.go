@@ -2,8 +2,10 @@ package wallet
 
 import (
 	"context"
+	"time"
 
 	"github.com/crypto-power/cryptopower/libwallet/internal/loader"
+	"github.com/crypto-power/cryptopower/libwallet/migration"
 	"github.com/crypto-power/cryptopower/libwallet/utils"
 )
 
@@ -17,9 +19,19 @@ type Asset interface {
 	CancelSync()
 	IsRescanning() bool
 	RescanBlocks() error
+	RescanBlocksFromHeight(startHeight int32) error
+	RescanBlocksFromTime(t time.Time) error
+	BlockHeightAtTime(t time.Time) (int32, error)
+	GetBirthday() time.Time
+	SetBirthday(birthday time.Time) error
 	ConnectedPeers() int32
 	RemovePeers()
 	SetSpecificPeer(address string)
+	PeerConfig() *PeerConfig
+	SetPeerConfig(cfg *PeerConfig) error
+	PeerInfoRaw() ([]PeerInfo, error)
+	RPCSyncConfig() *RPCSyncConfig
+	SetRPCSyncConfig(cfg *RPCSyncConfig) error
 	GetExtendedPubKey(account int32) (string, error)
 	IsSyncShuttingDown() bool
 
@@ -31,16 +43,22 @@ type Asset interface {
 	GetWalletID() int
 	GetWalletName() string
 	IsWatchingOnlyWallet() bool
+	IsMultisigWallet() bool
 	UnlockWallet(string) error
 	DeleteWallet(privPass string) error
 	RenameWallet(newName string) error
 	DecryptSeed(privatePassphrase string) (string, error)
 	VerifySeedForWallet(seedMnemonic, privpass string) (bool, error)
 	ChangePrivatePassphraseForWallet(oldPrivatePassphrase, newPrivatePassphrase string, privatePassphraseType int32) error
+	ExportBackup() (*WalletBackup, error)
+	ImportBackup(backup *WalletBackup) error
+	MigrateWalletData(listener migration.ProgressListener) error
 
 	RootDir() string
 	DataDir() string
 	GetEncryptedSeed() string
+	HasSeedPassphrase() bool
+	SplitSeed(seedMnemonic string, threshold, count int) ([]string, error)
 	IsConnectedToNetwork() bool
 	NetType() utils.NetworkType
 	ToAmount(v int64) AssetAmount
@@ -83,6 +101,11 @@ type Asset interface {
 	NextAddress(account int32) (string, error)
 	IsAddressValid(address string) bool
 	HaveAddress(address string) bool
+	AccountAddresses(account int32) ([]*AddressUsage, error)
+	SetAddressLabel(address, label string)
+	AddressLabel(address string) string
+	ImportPrivateKey(wif, privPass string, birthday time.Time) (string, error)
+	WatchAddress(pubKeyOrAddress string, birthday time.Time) (string, error)
 
 	SignMessage(passphrase, address, message string) ([]byte, error)
 	VerifyMessage(address, message, signatureBase64 string) (bool, error)
@@ -0,0 +1,314 @@
+package wallet
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Output descriptor script types supported by the BTC and LTC wallets. See
+// BIP380 and the BIPs that follow it for the descriptor language.
+const (
+	DescriptorWPKH           = "wpkh"
+	DescriptorSHWPKH         = "sh(wpkh)"
+	DescriptorTR             = "tr"
+	DescriptorWSHMulti       = "wsh(multi)"
+	DescriptorWSHSortedMulti = "wsh(sortedmulti)"
+)
+
+// hardenedKeyStart is the index of the first hardened child key.
+const hardenedKeyStart = 0x80000000
+
+const (
+	descriptorInputCharset = "0123456789()[],'/*abcdefgh@:$%{}" +
+		"IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~" +
+		"ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+	descriptorChecksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+var descriptorGenerator = [5]uint64{0xf5dee51989, 0xa9fdca3312, 0x1bab10e32d, 0x3706b1677a, 0x644d626ffd}
+
+// DescriptorKey is an extended public key expression of an output
+// descriptor. Only account level keys whose children are derived at
+// /<0;1>/* (or /0/* for the external branch alone) are supported.
+type DescriptorKey struct {
+	// Fingerprint is the fingerprint of the master key the key was derived
+	// from. It is 0 if the key origin is unknown.
+	Fingerprint uint32
+	// OriginPath is the derivation path of the key from the master key.
+	// Hardened indexes are offset by 2^31.
+	OriginPath []uint32
+	XPub       string
+	// ExternalOnly is set for keys whose children are derived at /0/*
+	// rather than at /<0;1>/*.
+	ExternalOnly bool
+}
+
+// Descriptor is a parsed output descriptor.
+type Descriptor struct {
+	Type string
+	// Threshold is the number of required signatures of multisig
+	// descriptors.
+	Threshold int
+	Keys      []*DescriptorKey
+}
+
+// DescriptorChecksum returns the BIP380 checksum of the provided descriptor
+// which must not include a checksum.
+func DescriptorChecksum(desc string) (string, error) {
+	var symbols []uint64
+	var groups []uint64
+	for _, c := range desc {
+		v := strings.IndexRune(descriptorInputCharset, c)
+		if v < 0 {
+			return "", fmt.Errorf("invalid descriptor character %q", c)
+		}
+
+		symbols = append(symbols, uint64(v&31))
+		groups = append(groups, uint64(v>>5))
+		if len(groups) == 3 {
+			symbols = append(symbols, groups[0]*9+groups[1]*3+groups[2])
+			groups = groups[:0]
+		}
+	}
+
+	switch len(groups) {
+	case 1:
+		symbols = append(symbols, groups[0])
+	case 2:
+		symbols = append(symbols, groups[0]*3+groups[1])
+	}
+
+	symbols = append(symbols, make([]uint64, 8)...)
+	chk := uint64(1)
+	for _, v := range symbols {
+		top := chk >> 35
+		chk = (chk&0x7ffffffff)<<5 ^ v
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= descriptorGenerator[i]
+			}
+		}
+	}
+	chk ^= 1
+
+	checksum := make([]byte, 8)
+	for i := range checksum {
+		checksum[i] = descriptorChecksumCharset[(chk>>(5*(7-i)))&31]
+	}
+	return string(checksum), nil
+}
+
+// ParseDescriptor parses the provided output descriptor. The checksum is
+// verified if present. The keys must be extended public keys.
+func ParseDescriptor(desc string) (*Descriptor, error) {
+	desc = strings.TrimSpace(desc)
+	if i := strings.LastIndex(desc, "#"); i >= 0 {
+		checksum, err := DescriptorChecksum(desc[:i])
+		if err != nil {
+			return nil, err
+		}
+		if checksum != desc[i+1:] {
+			return nil, fmt.Errorf("invalid descriptor checksum %q, expected %q", desc[i+1:], checksum)
+		}
+		desc = desc[:i]
+	}
+
+	types := []struct {
+		prefix, typ string
+		multisig    bool
+	}{
+		{"sh(wpkh(", DescriptorSHWPKH, false},
+		{"wpkh(", DescriptorWPKH, false},
+		{"tr(", DescriptorTR, false},
+		{"wsh(sortedmulti(", DescriptorWSHSortedMulti, true},
+		{"wsh(multi(", DescriptorWSHMulti, true},
+	}
+
+	for _, t := range types {
+		if !strings.HasPrefix(desc, t.prefix) {
+			continue
+		}
+
+		closing := strings.Count(t.prefix, "(")
+		if !strings.HasSuffix(desc, strings.Repeat(")", closing)) {
+			return nil, fmt.Errorf("unbalanced descriptor parentheses")
+		}
+		args := strings.Split(desc[len(t.prefix):len(desc)-closing], ",")
+
+		d := &Descriptor{Type: t.typ}
+		if t.multisig {
+			threshold, err := strconv.Atoi(args[0])
+			if err != nil || threshold < 1 || threshold > len(args)-1 {
+				return nil, fmt.Errorf("invalid multisig threshold %q", args[0])
+			}
+			d.Threshold = threshold
+			args = args[1:]
+		} else if len(args) != 1 {
+			// Taproot script paths are not supported.
+			return nil, fmt.Errorf("unsupported %s descriptor arguments", t.typ)
+		}
+
+		for _, arg := range args {
+			key, err := parseDescriptorKey(arg)
+			if err != nil {
+				return nil, err
+			}
+			d.Keys = append(d.Keys, key)
+		}
+		return d, nil
+	}
+
+	return nil, fmt.Errorf("unsupported descriptor %q", desc)
+}
+
+// ParseKeyExpression parses an extended public key shared by a multisig
+// cosigner. The key may be preceded by its key origin and followed by the
+// /<0;1>/* or /0/* derivation of its children.
+func ParseKeyExpression(expr string) (*DescriptorKey, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.Contains(expr[strings.Index(expr, "]")+1:], "/") {
+		expr += "/<0;1>/*"
+	}
+	return parseDescriptorKey(expr)
+}
+
+// parseDescriptorKey parses a key expression of the form
+// [fingerprint/path]xpub/<0;1>/*.
+func parseDescriptorKey(expr string) (*DescriptorKey, error) {
+	key := new(DescriptorKey)
+	if strings.HasPrefix(expr, "[") {
+		end := strings.Index(expr, "]")
+		if end < 0 {
+			return nil, fmt.Errorf("invalid key origin in %q", expr)
+		}
+
+		origin := strings.Split(expr[1:end], "/")
+		fingerprint, err := hex.DecodeString(origin[0])
+		if err != nil || len(fingerprint) != 4 {
+			return nil, fmt.Errorf("invalid key origin fingerprint %q", origin[0])
+		}
+		key.Fingerprint = binary.BigEndian.Uint32(fingerprint)
+
+		for _, step := range origin[1:] {
+			index, err := parseDerivationStep(step)
+			if err != nil {
+				return nil, err
+			}
+			key.OriginPath = append(key.OriginPath, index)
+		}
+		expr = expr[end+1:]
+	}
+
+	switch {
+	case strings.HasSuffix(expr, "/<0;1>/*"):
+		key.XPub = strings.TrimSuffix(expr, "/<0;1>/*")
+		return key, nil
+	case strings.HasSuffix(expr, "/0/*"):
+		key.XPub = strings.TrimSuffix(expr, "/0/*")
+		key.ExternalOnly = true
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported key derivation in %q, expected /<0;1>/* or /0/*", expr)
+}
+
+func parseDerivationStep(step string) (uint32, error) {
+	hardened := strings.HasSuffix(step, "'") || strings.HasSuffix(step, "h") || strings.HasSuffix(step, "H")
+	if hardened {
+		step = step[:len(step)-1]
+	}
+
+	index, err := strconv.ParseUint(step, 10, 32)
+	if err != nil || index >= hardenedKeyStart {
+		return 0, fmt.Errorf("invalid derivation step %q", step)
+	}
+
+	if hardened {
+		index += hardenedKeyStart
+	}
+	return uint32(index), nil
+}
+
+// String returns the key expression with the derivation of its children,
+// /0/* for external only keys or both branches as described in BIP389
+// otherwise.
+func (key *DescriptorKey) String() string {
+	var origin strings.Builder
+	if key.Fingerprint != 0 || len(key.OriginPath) > 0 {
+		origin.WriteString(fmt.Sprintf("[%08x", key.Fingerprint))
+		for _, index := range key.OriginPath {
+			if index >= hardenedKeyStart {
+				origin.WriteString(fmt.Sprintf("/%dh", index-hardenedKeyStart))
+			} else {
+				origin.WriteString(fmt.Sprintf("/%d", index))
+			}
+		}
+		origin.WriteString("]")
+	}
+	if key.ExternalOnly {
+		return origin.String() + key.XPub + "/0/*"
+	}
+	return origin.String() + key.XPub + "/<0;1>/*"
+}
+
+// String returns the descriptor with its checksum.
+func (d *Descriptor) String() string {
+	keys := make([]string, 0, len(d.Keys))
+	for _, key := range d.Keys {
+		keys = append(keys, key.String())
+	}
+
+	var desc string
+	switch d.Type {
+	case DescriptorSHWPKH:
+		desc = "sh(wpkh(" + keys[0] + "))"
+	case DescriptorWSHMulti, DescriptorWSHSortedMulti:
+		fn := strings.TrimSuffix(strings.TrimPrefix(d.Type, "wsh("), ")")
+		desc = fmt.Sprintf("wsh(%s(%d,%s))", fn, d.Threshold, strings.Join(keys, ","))
+	default:
+		desc = d.Type + "(" + keys[0] + ")"
+	}
+
+	// The descriptor is built from supported characters only.
+	checksum, _ := DescriptorChecksum(desc)
+	return desc + "#" + checksum
+}
+
+// IsMultisig returns true if the descriptor is a multisig descriptor.
+func (d *Descriptor) IsMultisig() bool {
+	return d.Type == DescriptorWSHMulti || d.Type == DescriptorWSHSortedMulti
+}
+
+// Purpose returns the BIP43 purpose of the key scope used by the script type
+// of single key descriptors.
+func (d *Descriptor) Purpose() uint32 {
+	switch d.Type {
+	case DescriptorSHWPKH:
+		return 49
+	case DescriptorTR:
+		return 86
+	default:
+		return 84
+	}
+}
+
+// DescriptorTypeForPurpose returns the single key descriptor script type of
+// the key scope of the provided BIP43 purpose.
+func DescriptorTypeForPurpose(purpose uint32) string {
+	switch purpose {
+	case 49:
+		return DescriptorSHWPKH
+	case 86:
+		return DescriptorTR
+	default:
+		return DescriptorWPKH
+	}
+}
+
+// LooksLikeDescriptor returns true if the provided text looks like an output
+// descriptor rather than a bare extended key.
+func LooksLikeDescriptor(text string) bool {
+	return strings.Contains(text, "(")
+}
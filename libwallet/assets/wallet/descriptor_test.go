@@ -0,0 +1,175 @@
+package wallet
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testXpub = "xpub6CUGRUonZSQ4TWtTMmzXdrXDtypWKiKrhko4egpiMZbpiaQL2jkwSB1icqYh2cfDfVxdx4df189oLKnC5fSwqPfgyP3hooxujYzAu3fDVmz"
+
+func TestParseKeyExpression(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		expected *DescriptorKey
+		valid    bool
+	}{
+		{
+			name:     "bare xpub",
+			expr:     " " + testXpub + "\n",
+			expected: &DescriptorKey{XPub: testXpub},
+			valid:    true,
+		},
+		{
+			name: "key origin",
+			expr: "[d34db33f/84h/0h/3']" + testXpub,
+			expected: &DescriptorKey{
+				Fingerprint: 0xd34db33f,
+				OriginPath:  []uint32{84 + hardenedKeyStart, hardenedKeyStart, 3 + hardenedKeyStart},
+				XPub:        testXpub,
+			},
+			valid: true,
+		},
+		{
+			name:     "key origin with children",
+			expr:     "[d34db33f/48h]" + testXpub + "/<0;1>/*",
+			expected: &DescriptorKey{Fingerprint: 0xd34db33f, OriginPath: []uint32{48 + hardenedKeyStart}, XPub: testXpub},
+			valid:    true,
+		},
+		{
+			name:     "external branch only",
+			expr:     testXpub + "/0/*",
+			expected: &DescriptorKey{XPub: testXpub, ExternalOnly: true},
+			valid:    true,
+		},
+		{name: "invalid fingerprint", expr: "[d34d/84h]" + testXpub},
+		{name: "unclosed origin", expr: "[d34db33f/84h" + testXpub},
+		{name: "unsupported children", expr: testXpub + "/1/*"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := ParseKeyExpression(tc.expr)
+			if (err == nil) != tc.valid {
+				t.Fatalf("(%v), expected valid (%v), got (%v)", tc.name, tc.valid, err)
+			}
+			if err == nil && !reflect.DeepEqual(key, tc.expected) {
+				t.Errorf("(%v), expected (%+v), got (%+v)", tc.name, tc.expected, key)
+			}
+		})
+	}
+}
+
+// TestDescriptorChecksum checks DescriptorChecksum against the BIP380 test
+// vectors.
+func TestDescriptorChecksum(t *testing.T) {
+	tests := []struct {
+		name     string
+		desc     string
+		expected string
+		valid    bool
+	}{
+		{name: "valid", desc: "raw(deadbeef)", expected: "89f8spxm", valid: true},
+		{name: "error in payload", desc: "raw(deedbeef)", expected: "89f8spxm"},
+		{name: "invalid character", desc: "raw(Ü)"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			checksum, err := DescriptorChecksum(tc.desc)
+			if tc.expected == "" {
+				if err == nil {
+					t.Fatalf("(%v), expected an error, got (%v)", tc.name, checksum)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("(%v), expected (%v), got (%v)", tc.name, tc.expected, err)
+			}
+			if got := checksum == tc.expected; got != tc.valid {
+				t.Errorf("(%v), expected checksum (%v) valid (%v), got (%v)", tc.name, tc.expected, tc.valid, checksum)
+			}
+		})
+	}
+}
+
+func TestParseDescriptor(t *testing.T) {
+	key := testXpub + "/<0;1>/*"
+	originKey := "[d34db33f/48h/0h/0h/2h]" + key
+	origin := &DescriptorKey{
+		Fingerprint: 0xd34db33f,
+		OriginPath:  []uint32{48 + hardenedKeyStart, hardenedKeyStart, hardenedKeyStart, 2 + hardenedKeyStart},
+		XPub:        testXpub,
+	}
+
+	tests := []struct {
+		name     string
+		desc     string
+		expected *Descriptor
+	}{
+		{
+			name:     "wpkh with checksum",
+			desc:     "wpkh(" + key + ")#acvdhtq4",
+			expected: &Descriptor{Type: DescriptorWPKH, Keys: []*DescriptorKey{{XPub: testXpub}}},
+		},
+		{
+			name:     "sh(wpkh)",
+			desc:     "sh(wpkh(" + key + "))",
+			expected: &Descriptor{Type: DescriptorSHWPKH, Keys: []*DescriptorKey{{XPub: testXpub}}},
+		},
+		{
+			name:     "tr",
+			desc:     " tr(" + key + ")\n",
+			expected: &Descriptor{Type: DescriptorTR, Keys: []*DescriptorKey{{XPub: testXpub}}},
+		},
+		{
+			name:     "external branch only",
+			desc:     "wpkh(" + testXpub + "/0/*)",
+			expected: &Descriptor{Type: DescriptorWPKH, Keys: []*DescriptorKey{{XPub: testXpub, ExternalOnly: true}}},
+		},
+		{
+			name:     "sorted multisig",
+			desc:     "wsh(sortedmulti(2," + originKey + "," + key + "))",
+			expected: &Descriptor{Type: DescriptorWSHSortedMulti, Threshold: 2, Keys: []*DescriptorKey{origin, {XPub: testXpub}}},
+		},
+		{
+			name:     "multisig",
+			desc:     "wsh(multi(1," + key + "," + originKey + "))",
+			expected: &Descriptor{Type: DescriptorWSHMulti, Threshold: 1, Keys: []*DescriptorKey{{XPub: testXpub}, origin}},
+		},
+		{name: "invalid checksum", desc: "wpkh(" + key + ")#acvdhtq5"},
+		{name: "unbalanced parentheses", desc: "sh(wpkh(" + key + ")"},
+		{name: "taproot script path", desc: "tr(" + key + ",{pk(" + key + ")})"},
+		{name: "threshold above the keys", desc: "wsh(sortedmulti(3," + key + "," + originKey + "))"},
+		{name: "zero threshold", desc: "wsh(multi(0," + key + "))"},
+		{name: "unsupported script", desc: "pkh(" + key + ")"},
+		{name: "invalid key", desc: "wpkh(" + testXpub + "/1/*)"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := ParseDescriptor(tc.desc)
+			if (err == nil) != (tc.expected != nil) {
+				t.Fatalf("(%v), expected valid (%v), got (%v)", tc.name, tc.expected != nil, err)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(d, tc.expected) {
+				t.Errorf("(%v), expected (%+v), got (%+v)", tc.name, tc.expected, d)
+			}
+
+			// String returns the parsed descriptor with a valid checksum
+			// that parses back to the same descriptor.
+			desc := strings.SplitN(strings.TrimSpace(tc.desc), "#", 2)[0]
+			if got := strings.SplitN(d.String(), "#", 2)[0]; got != desc {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, desc, got)
+			}
+			reparsed, err := ParseDescriptor(d.String())
+			if err != nil || !reflect.DeepEqual(reparsed, d) {
+				t.Errorf("(%v), expected (%v) to parse back, got (%+v): %v", tc.name, d.String(), reparsed, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
+)
+
+var (
+	syncTopics = []eventbus.Topic{
+		eventbus.TopicSyncStarted,
+		eventbus.TopicSyncProgress,
+		eventbus.TopicPeersChanged,
+		eventbus.TopicSyncCompleted,
+		eventbus.TopicSyncCanceled,
+		eventbus.TopicSyncEndedWithError,
+	}
+
+	txAndBlockTopics = []eventbus.Topic{
+		eventbus.TopicTransaction,
+		eventbus.TopicTransactionConfirmed,
+		eventbus.TopicBlockAttached,
+	}
+)
+
+// Events returns the event bus the wallet publishes its events on.
+func (wallet *Wallet) Events() *eventbus.Bus {
+	return wallet.events
+}
+
+// EventSubscriberID returns the ID of the subscription of kind registered for
+// the wallet by uniqueIdentifier. The same uniqueIdentifier is used by the
+// listeners of every wallet, the ID keeps their subscriptions apart.
+func (wallet *Wallet) EventSubscriberID(kind, uniqueIdentifier string) string {
+	return fmt.Sprintf("%d/%s/%s", wallet.ID, kind, uniqueIdentifier)
+}
+
+// PublishEvent publishes an event of topic about the wallet.
+func (wallet *Wallet) PublishEvent(topic eventbus.Topic, payload interface{}) {
+	wallet.events.Publish(eventbus.Event{
+		Topic:    topic,
+		WalletID: wallet.ID,
+		Asset:    wallet.Type,
+		Payload:  payload,
+	})
+}
+
+// PublishSyncProgress publishes a copy of a sync progress report, which is
+// replayed to the sync progress listeners added before the sync ends.
+func (wallet *Wallet) PublishSyncProgress(report interface{}) {
+	wallet.events.PublishState(eventbus.Event{
+		Topic:    eventbus.TopicSyncProgress,
+		WalletID: wallet.ID,
+		Asset:    wallet.Type,
+		Payload:  copySyncProgressReport(report),
+	})
+}
+
+// PublishPeersChanged publishes the number of peers the wallet is connected
+// to.
+func (wallet *Wallet) PublishPeersChanged(numberOfConnectedPeers int32) {
+	wallet.events.PublishState(eventbus.Event{
+		Topic:    eventbus.TopicPeersChanged,
+		WalletID: wallet.ID,
+		Asset:    wallet.Type,
+		Payload:  numberOfConnectedPeers,
+	})
+}
+
+// ClearSyncProgress stops replaying the last sync progress report once the
+// sync has ended.
+func (wallet *Wallet) ClearSyncProgress() {
+	wallet.events.ClearState(eventbus.TopicSyncProgress, wallet.ID)
+}
+
+// AddSyncProgressListener registers a sync progress listener to the wallet.
+// The progress of a sync in progress is replayed to the new listener.
+func (wallet *Wallet) AddSyncProgressListener(syncProgressListener *SyncProgressListener, uniqueIdentifier string) error {
+	filter := eventbus.Filter{WalletID: wallet.ID, Topics: syncTopics}
+	return wallet.events.Subscribe(wallet.EventSubscriberID("sync", uniqueIdentifier), filter, func(e eventbus.Event) {
+		l := syncProgressListener
+		switch e.Topic {
+		case eventbus.TopicSyncStarted:
+			if l.OnSyncStarted != nil {
+				l.OnSyncStarted()
+			}
+		case eventbus.TopicPeersChanged:
+			if l.OnPeerConnectedOrDisconnected != nil {
+				l.OnPeerConnectedOrDisconnected(e.Payload.(int32))
+			}
+		case eventbus.TopicSyncProgress:
+			switch report := e.Payload.(type) {
+			case *CFiltersFetchProgressReport:
+				if l.OnCFiltersFetchProgress != nil {
+					l.OnCFiltersFetchProgress(report)
+				}
+			case *HeadersFetchProgressReport:
+				if l.OnHeadersFetchProgress != nil {
+					l.OnHeadersFetchProgress(report)
+				}
+			case *AddressDiscoveryProgressReport:
+				if l.OnAddressDiscoveryProgress != nil {
+					l.OnAddressDiscoveryProgress(report)
+				}
+			case *HeadersRescanProgressReport:
+				if l.OnHeadersRescanProgress != nil {
+					l.OnHeadersRescanProgress(report)
+				}
+			}
+		case eventbus.TopicSyncCompleted:
+			if l.OnSyncCompleted != nil {
+				l.OnSyncCompleted()
+			}
+		case eventbus.TopicSyncCanceled:
+			if l.OnSyncCanceled != nil {
+				l.OnSyncCanceled(e.Payload.(bool))
+			}
+		case eventbus.TopicSyncEndedWithError:
+			if l.OnSyncEndedWithError != nil {
+				err, _ := e.Payload.(error)
+				l.OnSyncEndedWithError(err)
+			}
+		}
+	})
+}
+
+// RemoveSyncProgressListener unregisters a sync progress listener from the
+// wallet.
+func (wallet *Wallet) RemoveSyncProgressListener(uniqueIdentifier string) {
+	wallet.events.Unsubscribe(wallet.EventSubscriberID("sync", uniqueIdentifier))
+}
+
+// AddTxAndBlockNotificationListener registers a set of functions to be invoked
+// when a transaction or block update is processed by the wallet. The functions
+// are called from the listener's own goroutine, so the wallet process sending
+// the notification never waits for the listener.
+func (wallet *Wallet) AddTxAndBlockNotificationListener(txAndBlockNotificationListener *TxAndBlockNotificationListener, uniqueIdentifier string) error {
+	filter := eventbus.Filter{WalletID: wallet.ID, Topics: txAndBlockTopics}
+	return wallet.events.Subscribe(wallet.EventSubscriberID("txandblock", uniqueIdentifier), filter, func(e eventbus.Event) {
+		l := txAndBlockNotificationListener
+		switch payload := e.Payload.(type) {
+		case *Transaction:
+			if l.OnTransaction != nil {
+				l.OnTransaction(payload)
+			}
+		case *TxConfirmation:
+			if l.OnTransactionConfirmed != nil {
+				l.OnTransactionConfirmed(e.WalletID, payload.Hash, payload.BlockHeight)
+			}
+		case int32:
+			if l.OnBlockAttached != nil {
+				l.OnBlockAttached(e.WalletID, payload)
+			}
+		}
+	})
+}
+
+// RemoveTxAndBlockNotificationListener unregisters a transaction and block
+// notification listener from the wallet.
+func (wallet *Wallet) RemoveTxAndBlockNotificationListener(uniqueIdentifier string) {
+	wallet.events.Unsubscribe(wallet.EventSubscriberID("txandblock", uniqueIdentifier))
+}
+
+// copySyncProgressReport copies report so that the sync may keep updating its
+// own report while the listeners read the published one.
+func copySyncProgressReport(report interface{}) interface{} {
+	general := func(progress *GeneralSyncProgress) *GeneralSyncProgress {
+		if progress == nil {
+			return nil
+		}
+		progressCopy := *progress
+		return &progressCopy
+	}
+
+	switch r := report.(type) {
+	case *CFiltersFetchProgressReport:
+		reportCopy := *r
+		reportCopy.GeneralSyncProgress = general(r.GeneralSyncProgress)
+		return &reportCopy
+	case *HeadersFetchProgressReport:
+		reportCopy := *r
+		reportCopy.GeneralSyncProgress = general(r.GeneralSyncProgress)
+		if r.StartHeaderHeight != nil {
+			startHeight := *r.StartHeaderHeight
+			reportCopy.StartHeaderHeight = &startHeight
+		}
+		return &reportCopy
+	case *AddressDiscoveryProgressReport:
+		reportCopy := *r
+		reportCopy.GeneralSyncProgress = general(r.GeneralSyncProgress)
+		return &reportCopy
+	case *HeadersRescanProgressReport:
+		reportCopy := *r
+		reportCopy.GeneralSyncProgress = general(r.GeneralSyncProgress)
+		return &reportCopy
+	}
+	return report
+}
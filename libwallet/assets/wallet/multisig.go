@@ -0,0 +1,59 @@
+package wallet
+
+import "fmt"
+
+// MaxMultisigKeys is the maximum number of keys allowed in a multisig script.
+const MaxMultisigKeys = 15
+
+// Validate checks that the threshold can be met by the configured keys.
+func (cfg *MultisigConfig) Validate() error {
+	n := int32(len(cfg.CosignerXpubs) + 1)
+	if n > MaxMultisigKeys {
+		return fmt.Errorf("a multisig can have at most %d keys", MaxMultisigKeys)
+	}
+	if cfg.Threshold < 1 || cfg.Threshold > n {
+		return fmt.Errorf("threshold must be between 1 and %d", n)
+	}
+
+	seen := map[string]bool{cfg.LocalXpub: true}
+	for _, xpub := range cfg.CosignerXpubs {
+		if seen[xpub] {
+			return fmt.Errorf("duplicate extended public key %s", xpub)
+		}
+		seen[xpub] = true
+	}
+	return nil
+}
+
+// Xpubs returns the extended public keys of all the multisig participants,
+// starting with the local key.
+func (cfg *MultisigConfig) Xpubs() []string {
+	return append([]string{cfg.LocalXpub}, cfg.CosignerXpubs...)
+}
+
+// MultisigConfig returns the multisig configuration of the wallet or nil if
+// the wallet is not a multisig wallet.
+func (wallet *Wallet) MultisigConfig() *MultisigConfig {
+	cfg := new(MultisigConfig)
+	if err := wallet.ReadUserConfigValue(MultisigConfigKey, cfg); err != nil {
+		return nil
+	}
+	return cfg
+}
+
+// IsMultisigWallet returns true if the wallet was created as a multisig
+// wallet. Its receive addresses and spends are those of the multisig once it
+// is set up.
+func (wallet *Wallet) IsMultisigWallet() bool {
+	return wallet.ReadBoolConfigValueForKey(MultisigWalletConfigKey, false)
+}
+
+// IsMultisig returns true if a multisig has been set up for the wallet.
+func (wallet *Wallet) IsMultisig() bool {
+	return wallet.MultisigConfig() != nil
+}
+
+// SaveMultisigConfig persists the provided multisig configuration.
+func (wallet *Wallet) SaveMultisigConfig(cfg *MultisigConfig) {
+	wallet.SaveUserConfigValue(MultisigConfigKey, cfg)
+}
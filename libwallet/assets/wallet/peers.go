@@ -0,0 +1,336 @@
+package wallet
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"decred.org/dcrwallet/v3/errors"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+)
+
+// PeerConfig holds the peers a wallet connects to in SPV mode and the peers
+// it refuses to connect to.
+type PeerConfig struct {
+	// Persistent peers are the only peers the wallet connects to when any is
+	// set.
+	Persistent []string `json:"persistent"`
+	// Preferred peers are tried before the peers found through the DNS seeds
+	// and the address manager, which are still used.
+	Preferred []string `json:"preferred"`
+	// Bans are the hosts the wallet does not connect to.
+	Bans []*PeerBan `json:"bans"`
+}
+
+// PeerBan is a host the wallet refuses to connect to.
+type PeerBan struct {
+	// Host is the IP address or host name of the peer, any port is banned.
+	Host   string `json:"host"`
+	Reason string `json:"reason"`
+	// Expiry is the unix timestamp the ban ends at, zero for permanent bans.
+	Expiry int64 `json:"expiry"`
+}
+
+// PeerHost returns the host part of a peer address, which may not have a
+// port.
+func PeerHost(address string) string {
+	address = strings.TrimSpace(address)
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return strings.Trim(address, "[]")
+}
+
+// IsPermanent returns true if the ban never expires.
+func (ban *PeerBan) IsPermanent() bool {
+	return ban.Expiry == 0
+}
+
+// Expired returns true if the ban ended.
+func (ban *PeerBan) Expired() bool {
+	return !ban.IsPermanent() && time.Now().Unix() >= ban.Expiry
+}
+
+// Ban bans the host of address for duration, a zero duration bans it
+// permanently. The host is removed from the persistent and preferred peers.
+func (cfg *PeerConfig) Ban(address, reason string, duration time.Duration) error {
+	host := PeerHost(address)
+	if host == "" {
+		return errors.New(utils.ErrInvalidAddress)
+	}
+
+	ban := &PeerBan{Host: host, Reason: reason}
+	if duration > 0 {
+		ban.Expiry = time.Now().Add(duration).Unix()
+	}
+
+	cfg.Unban(host)
+	cfg.Bans = append(cfg.Bans, ban)
+	cfg.Persistent = removePeerHost(cfg.Persistent, host)
+	cfg.Preferred = removePeerHost(cfg.Preferred, host)
+	return nil
+}
+
+// Unban lifts the ban of the host of address, if any.
+func (cfg *PeerConfig) Unban(address string) {
+	host := PeerHost(address)
+	bans := cfg.Bans[:0]
+	for _, ban := range cfg.Bans {
+		if ban.Host != host {
+			bans = append(bans, ban)
+		}
+	}
+	cfg.Bans = bans
+}
+
+// BanFor returns the active ban of the host of address, or nil if it is not
+// banned.
+func (cfg *PeerConfig) BanFor(address string) *PeerBan {
+	host := PeerHost(address)
+	for _, ban := range cfg.Bans {
+		if ban.Host == host && !ban.Expired() {
+			return ban
+		}
+	}
+	return nil
+}
+
+// ActiveBans returns the bans that have not expired.
+func (cfg *PeerConfig) ActiveBans() []*PeerBan {
+	bans := make([]*PeerBan, 0, len(cfg.Bans))
+	for _, ban := range cfg.Bans {
+		if !ban.Expired() {
+			bans = append(bans, ban)
+		}
+	}
+	return bans
+}
+
+func removePeerHost(addresses []string, host string) []string {
+	kept := addresses[:0]
+	for _, address := range addresses {
+		if PeerHost(address) != host {
+			kept = append(kept, address)
+		}
+	}
+	return kept
+}
+
+// normalize adds the default port to the addresses missing one, drops the
+// duplicates and the expired bans. An error is returned if an address is
+// invalid.
+func (cfg *PeerConfig) normalize(defaultPort string) error {
+	normalizeList := func(addresses []string) ([]string, error) {
+		seen := make(map[string]bool)
+		list := make([]string, 0, len(addresses))
+		for _, address := range addresses {
+			address = strings.TrimSpace(address)
+			if address == "" {
+				continue
+			}
+			peerAddress, err := utils.NormalizeAddress(address, defaultPort)
+			if err != nil {
+				return nil, errors.Errorf("SPV peer address(%s) is invalid: %v", address, err)
+			}
+			if !seen[peerAddress] {
+				seen[peerAddress] = true
+				list = append(list, peerAddress)
+			}
+		}
+		return list, nil
+	}
+
+	var err error
+	if cfg.Persistent, err = normalizeList(cfg.Persistent); err != nil {
+		return err
+	}
+	if cfg.Preferred, err = normalizeList(cfg.Preferred); err != nil {
+		return err
+	}
+	cfg.Bans = cfg.ActiveBans()
+	return nil
+}
+
+func (wallet *Wallet) defaultPeerPort() string {
+	switch wallet.Type {
+	case utils.BTCWalletAsset:
+		return wallet.chainsParams.BTC.DefaultPort
+	case utils.LTCWalletAsset:
+		return wallet.chainsParams.LTC.DefaultPort
+	}
+	return wallet.chainsParams.DCR.DefaultPort
+}
+
+// PeerConfig returns the peer configuration of the wallet. The peers set
+// before peer configurations existed are returned as persistent peers.
+func (wallet *Wallet) PeerConfig() *PeerConfig {
+	cfg := new(PeerConfig)
+	if err := wallet.ReadUserConfigValue(SpvPeerConfigKey, cfg); err == nil &&
+		(cfg.Persistent != nil || cfg.Preferred != nil || cfg.Bans != nil) {
+		cfg.Bans = cfg.ActiveBans()
+		return cfg
+	}
+
+	peerAddresses := wallet.ReadStringConfigValueForKey(SpvPersistentPeerAddressesConfigKey, "")
+	for _, address := range strings.Split(peerAddresses, ";") {
+		if address = strings.TrimSpace(address); address != "" {
+			cfg.Persistent = append(cfg.Persistent, address)
+		}
+	}
+	return cfg
+}
+
+// ValidPeerConfig returns the peer configuration of the wallet with the
+// default port added to the addresses missing one. An error is returned if an
+// address is invalid.
+func (wallet *Wallet) ValidPeerConfig() (*PeerConfig, error) {
+	cfg := wallet.PeerConfig()
+	if err := cfg.normalize(wallet.defaultPeerPort()); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// SavePeerConfig validates and saves the peer configuration of the wallet. It
+// takes effect the next time the wallet connects to the network.
+func (wallet *Wallet) SavePeerConfig(cfg *PeerConfig) error {
+	if cfg == nil {
+		cfg = new(PeerConfig)
+	}
+	if err := cfg.normalize(wallet.defaultPeerPort()); err != nil {
+		return err
+	}
+
+	// Empty lists are saved so that the legacy peers are not used again.
+	if cfg.Persistent == nil {
+		cfg.Persistent = []string{}
+	}
+	if cfg.Preferred == nil {
+		cfg.Preferred = []string{}
+	}
+	wallet.SaveUserConfigValue(SpvPeerConfigKey, cfg)
+	wallet.DeleteUserConfigValueForKey(SpvPersistentPeerAddressesConfigKey)
+	return nil
+}
+
+// PeerStats are the traffic statistics of a connection made by a PeerDialer.
+type PeerStats struct {
+	// Latency is the time it took to establish the connection.
+	Latency       time.Duration
+	BytesSent     uint64
+	BytesReceived uint64
+}
+
+// PeerDialer dials peers through the proxy of a subsystem, refusing to
+// connect to banned hosts, and keeps the traffic statistics of the open
+// connections.
+type PeerDialer struct {
+	dial func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	mtx   sync.Mutex
+	bans  map[string]*PeerBan
+	conns map[string]*peerConn
+}
+
+// NewPeerDialer returns a dialer for the peers subsystem that refuses to
+// connect to the hosts of bans until the bans expire.
+func NewPeerDialer(subsystem utils.ProxySubsystem, bans []*PeerBan) *PeerDialer {
+	d := &PeerDialer{
+		dial:  utils.ProxyDialContext(subsystem),
+		bans:  make(map[string]*PeerBan),
+		conns: make(map[string]*peerConn),
+	}
+	for _, ban := range bans {
+		if !ban.Expired() {
+			d.bans[ban.Host] = ban
+		}
+	}
+	return d
+}
+
+// DialContext connects to addr unless its host is banned. The expiry of the
+// bans is checked at every dial, so a ban ending while the wallet syncs no
+// longer applies.
+func (d *PeerDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host := PeerHost(addr)
+	d.mtx.Lock()
+	ban, banned := d.bans[host]
+	if banned && ban.Expired() {
+		delete(d.bans, host)
+		banned = false
+	}
+	d.mtx.Unlock()
+	if banned {
+		return nil, errors.Errorf("peer %s is banned", addr)
+	}
+
+	start := time.Now()
+	conn, err := d.dial(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := &peerConn{Conn: conn, dialer: d, addr: addr, latency: time.Since(start)}
+	d.mtx.Lock()
+	d.conns[addr] = pc
+	d.mtx.Unlock()
+	return pc, nil
+}
+
+// Dialer returns a dial function for the neutrino chain services whose
+// connections are canceled with ctx.
+func (d *PeerDialer) Dialer(ctx context.Context) utils.Dailer {
+	return func(addr net.Addr) (net.Conn, error) {
+		return d.DialContext(ctx, addr.Network(), addr.String())
+	}
+}
+
+// Stats returns the statistics of the open connection to addr.
+func (d *PeerDialer) Stats(addr string) (PeerStats, bool) {
+	d.mtx.Lock()
+	pc, ok := d.conns[addr]
+	d.mtx.Unlock()
+	if !ok {
+		return PeerStats{}, false
+	}
+	return PeerStats{
+		Latency:       pc.latency,
+		BytesSent:     atomic.LoadUint64(&pc.sent),
+		BytesReceived: atomic.LoadUint64(&pc.received),
+	}, true
+}
+
+// peerConn counts the bytes sent and received over a peer connection.
+type peerConn struct {
+	net.Conn
+	dialer  *PeerDialer
+	addr    string
+	latency time.Duration
+
+	sent     uint64
+	received uint64
+}
+
+func (c *peerConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	atomic.AddUint64(&c.received, uint64(n))
+	return n, err
+}
+
+func (c *peerConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	atomic.AddUint64(&c.sent, uint64(n))
+	return n, err
+}
+
+func (c *peerConn) Close() error {
+	c.dialer.mtx.Lock()
+	if c.dialer.conns[c.addr] == c {
+		delete(c.dialer.conns, c.addr)
+	}
+	c.dialer.mtx.Unlock()
+	return c.Conn.Close()
+}
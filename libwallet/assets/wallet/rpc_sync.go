@@ -0,0 +1,173 @@
+package wallet
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+)
+
+// RPCBackend is the kind of full node a wallet syncs from in RPC sync mode.
+type RPCBackend string
+
+const (
+	DcrdBackend      RPCBackend = "dcrd"
+	BtcdBackend      RPCBackend = "btcd"
+	BitcoindBackend  RPCBackend = "bitcoind"
+	LtcdBackend      RPCBackend = "ltcd"
+	LitecoindBackend RPCBackend = "litecoind"
+	// ElectrumBackend syncs from ElectrumX or Fulcrum servers instead of a
+	// full node.
+	ElectrumBackend RPCBackend = "electrum"
+)
+
+// ElectrumServer is an Electrum server a wallet can sync from.
+type ElectrumServer struct {
+	// Address is the host:port of the server.
+	Address string `json:"address"`
+	// TLS connects to the server's ssl port.
+	TLS bool `json:"tls"`
+	// CertFingerprint is the hex encoded sha256 fingerprint of the server's
+	// TLS certificate. Setting it pins the certificate, which is required
+	// for servers with self-signed certificates.
+	CertFingerprint string `json:"cert_fingerprint"`
+}
+
+// ParseElectrumServer parses a server in the host:port[:s|:t] notation of
+// Electrum clients, where s selects the ssl port and t the plain tcp port,
+// optionally followed by the server's certificate fingerprint.
+func ParseElectrumServer(server string) (*ElectrumServer, error) {
+	fields := strings.Fields(server)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, fmt.Errorf("invalid electrum server %q", server)
+	}
+
+	cfg := &ElectrumServer{Address: fields[0], TLS: true}
+	if i := strings.LastIndex(cfg.Address, ":"); i > 0 {
+		switch cfg.Address[i+1:] {
+		case "s":
+			cfg.Address = cfg.Address[:i]
+		case "t":
+			cfg.Address, cfg.TLS = cfg.Address[:i], false
+		}
+	}
+	if _, _, err := net.SplitHostPort(cfg.Address); err != nil {
+		return nil, fmt.Errorf("invalid electrum server address %q", cfg.Address)
+	}
+	if len(fields) == 2 {
+		if !cfg.TLS {
+			return nil, fmt.Errorf("a certificate fingerprint requires a ssl server: %q", server)
+		}
+		cfg.CertFingerprint = fields[1]
+	}
+	return cfg, nil
+}
+
+// String returns the server in the notation read by ParseElectrumServer.
+func (server ElectrumServer) String() string {
+	if !server.TLS {
+		return server.Address + ":t"
+	}
+	if server.CertFingerprint != "" {
+		return server.Address + ":s " + server.CertFingerprint
+	}
+	return server.Address + ":s"
+}
+
+// RPCSyncConfig holds the connection settings of a wallet that syncs from a
+// personal full node over RPC or from electrum servers instead of SPV.
+type RPCSyncConfig struct {
+	Backend RPCBackend `json:"backend"`
+	// Host is the host:port of the node's RPC server. The network's default
+	// RPC port is used if none is provided.
+	Host string `json:"host"`
+	User string `json:"user"`
+	Pass string `json:"pass"`
+	// Certificate is the PEM encoded TLS certificate of the RPC server. It is
+	// ignored by bitcoind and litecoind which serve RPC without TLS.
+	Certificate string `json:"certificate"`
+	DisableTLS  bool   `json:"disable_tls"`
+	// ZMQBlockHost and ZMQTxHost are the zmqpubrawblock and zmqpubrawtx
+	// endpoints of litecoind, which only delivers notifications over ZMQ.
+	ZMQBlockHost string `json:"zmq_block_host"`
+	ZMQTxHost    string `json:"zmq_tx_host"`
+	// ElectrumServers are the servers used by the electrum backend, in order
+	// of preference.
+	ElectrumServers []ElectrumServer `json:"electrum_servers"`
+	// FallbackToSPV makes the wallet sync over SPV when the node cannot be
+	// reached.
+	FallbackToSPV bool `json:"fallback_to_spv"`
+}
+
+// RPCBackends returns the full node implementations wallets of the asset
+// type can sync from.
+func RPCBackends(assetType utils.AssetType) []RPCBackend {
+	switch assetType {
+	case utils.DCRWalletAsset:
+		return []RPCBackend{DcrdBackend}
+	case utils.BTCWalletAsset:
+		return []RPCBackend{BtcdBackend, BitcoindBackend, ElectrumBackend}
+	case utils.LTCWalletAsset:
+		return []RPCBackend{LtcdBackend, LitecoindBackend, ElectrumBackend}
+	default:
+		return nil
+	}
+}
+
+// Validate checks that the config can be used by a wallet of the asset type.
+func (cfg *RPCSyncConfig) Validate(assetType utils.AssetType) error {
+	supported := false
+	for _, backend := range RPCBackends(assetType) {
+		supported = supported || backend == cfg.Backend
+	}
+	if !supported {
+		return fmt.Errorf("%s wallets cannot sync from %q", assetType, cfg.Backend)
+	}
+
+	if cfg.Backend == ElectrumBackend {
+		if len(cfg.ElectrumServers) == 0 {
+			return fmt.Errorf("at least one electrum server is required")
+		}
+		for _, server := range cfg.ElectrumServers {
+			if _, _, err := net.SplitHostPort(server.Address); err != nil {
+				return fmt.Errorf("invalid electrum server address %q", server.Address)
+			}
+		}
+		return nil
+	}
+
+	if cfg.Host == "" {
+		return fmt.Errorf("the node's RPC host is required")
+	}
+	if cfg.Backend == LitecoindBackend && (cfg.ZMQBlockHost == "" || cfg.ZMQTxHost == "") {
+		return fmt.Errorf("litecoind requires its ZMQ block and tx endpoints")
+	}
+	return nil
+}
+
+// RPCSyncConfig returns the full node or electrum servers the wallet syncs
+// from or nil if the wallet uses SPV sync.
+func (wallet *Wallet) RPCSyncConfig() *RPCSyncConfig {
+	cfg := new(RPCSyncConfig)
+	if err := wallet.ReadUserConfigValue(RPCSyncConfigKey, cfg); err != nil || cfg.Backend == "" {
+		return nil
+	}
+	return cfg
+}
+
+// SetRPCSyncConfig makes the wallet sync from the full node or electrum
+// servers in cfg the next time it connects to the network. A nil cfg switches
+// the wallet back to SPV sync.
+func (wallet *Wallet) SetRPCSyncConfig(cfg *RPCSyncConfig) error {
+	if cfg == nil {
+		wallet.DeleteUserConfigValueForKey(RPCSyncConfigKey)
+		return nil
+	}
+
+	if err := cfg.Validate(wallet.Type); err != nil {
+		return err
+	}
+	wallet.SaveUserConfigValue(RPCSyncConfigKey, cfg)
+	return nil
+}
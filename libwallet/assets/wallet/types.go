@@ -5,6 +5,7 @@ import (
 
 	"github.com/asdine/storm"
 	btchdkeychain "github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
 	"github.com/crypto-power/cryptopower/libwallet/utils"
 	"github.com/decred/dcrd/dcrutil/v4"
 )
@@ -43,6 +44,7 @@ type InitParams struct {
 	DB       *storm.DB
 	DbDriver string
 	LogDir   string
+	Events   *eventbus.Bus
 }
 
 // AuthInfo defines the complete information required to either create a
@@ -51,6 +53,8 @@ type AuthInfo struct {
 	Name            string
 	PrivatePass     string
 	PrivatePassType int32
+	// SeedPassphrase is the optional passphrase of BIP39 seeds.
+	SeedPassphrase string
 }
 
 type BlockInfo struct {
@@ -105,6 +109,11 @@ type Balance struct {
 	LockedByTickets         AssetAmount
 	VotingAuthority         AssetAmount
 	UnConfirmed             AssetAmount
+
+	// BTC and LTC only fields
+	// WatchOnly is the part of Total held by the watched addresses whose
+	// keys the wallet doesn't hold. It is never spendable.
+	WatchOnly AssetAmount
 }
 
 type Account struct {
@@ -218,6 +227,11 @@ type PeerInfo struct {
 	SubVer         string `json:"sub_ver"`
 	StartingHeight int64  `json:"starting_height"`
 	BanScore       int32  `json:"ban_score"`
+	// BestHeight is the height of the best block the peer announced.
+	BestHeight    int64  `json:"best_height"`
+	LatencyMs     int64  `json:"latency_ms"`
+	BytesSent     uint64 `json:"bytes_sent"`
+	BytesReceived uint64 `json:"bytes_received"`
 }
 
 /** begin sync-related types */
@@ -285,6 +299,12 @@ type TxAndBlockNotificationListener struct {
 	OnTransactionConfirmed func(walletID int, hash string, blockHeight int32)
 }
 
+// TxConfirmation is the payload of the transaction confirmed events.
+type TxConfirmation struct {
+	Hash        string
+	BlockHeight int32
+}
+
 type BlocksRescanProgressListener struct {
 	OnBlocksRescanStarted  func(walletID int)
 	OnBlocksRescanProgress func(*HeadersRescanProgressReport)
@@ -332,6 +352,7 @@ type TxInput struct {
 	PreviousOutpoint         string `json:"previous_outpoint"`
 	Amount                   int64  `json:"amount"`
 	AccountNumber            int32  `json:"account_number"`
+	WatchOnly                bool   `json:"watch_only,omitempty"` // (BTC and LTC Field)
 }
 
 type TxOutput struct {
@@ -342,6 +363,7 @@ type TxOutput struct {
 	Address       string `json:"address"`
 	Internal      bool   `json:"internal"`
 	AccountNumber int32  `json:"account_number"`
+	WatchOnly     bool   `json:"watch_only,omitempty"` // (BTC and LTC Field)
 }
 
 // TxInfoFromWallet contains tx data that relates to the querying wallet.
@@ -419,3 +441,84 @@ type UnspentOutput struct {
 	ReceiveTime   time.Time
 	Tree          int8
 }
+
+const (
+	// AddressFilterAll matches every address.
+	AddressFilterAll int32 = iota
+	// AddressFilterUsed matches addresses that have received funds.
+	AddressFilterUsed
+	// AddressFilterUnused matches addresses that have never received funds.
+	AddressFilterUnused
+	// AddressFilterFunded matches addresses with a non-zero balance.
+	AddressFilterFunded
+	// AddressFilterExternal matches receiving (external branch) addresses.
+	AddressFilterExternal
+	// AddressFilterInternal matches change (internal branch) addresses.
+	AddressFilterInternal
+)
+
+// AddressUsage holds the derivation details and the on-chain usage of a
+// single address belonging to a wallet account.
+type AddressUsage struct {
+	Address       string
+	AccountNumber int32
+	// Internal is true for change addresses (branch 1).
+	Internal bool
+	// Imported is true for addresses in the imported account. Imported
+	// addresses have no derivation path.
+	Imported bool
+	Index    uint32
+	HDPath   string
+
+	TimesUsed int32
+	Balance   AssetAmount
+	// FirstSeen and LastSeen hold the unix timestamps of the oldest and the
+	// newest transactions paying to the address. Both are zero if the address
+	// is unused.
+	FirstSeen int64
+	LastSeen  int64
+	Label     string
+}
+
+// MultisigConfig holds the parameters of an m-of-n P2WSH multisig attached to
+// a wallet. The local key is the extended public key of a dedicated wallet
+// account, the other keys belong to the cosigners.
+type MultisigConfig struct {
+	// Threshold is the number of signatures required to spend (m).
+	Threshold int32
+	// LocalAccount is the wallet account whose keys are the local keys. It
+	// is -1 for watch only multisig wallets which hold no local keys.
+	LocalAccount  int32
+	LocalXpub     string
+	CosignerXpubs []string
+	// KeepKeyOrder disables the BIP67 sorting of the keys in the multisig
+	// scripts, as is the case for multi() descriptors.
+	KeepKeyOrder bool
+	// ExternalIndex and InternalIndex hold the next receive and change
+	// address indexes.
+	ExternalIndex uint32
+	InternalIndex uint32
+	// ImportedIndex is the number of addresses per branch whose scripts
+	// have been imported, or watched for LTC wallets, for tracking.
+	ImportedIndex uint32
+	// Descriptor is the output descriptor of the multisig including the
+	// origins of the keys whose origin is known.
+	Descriptor string
+}
+
+// MultisigPSBTInfo summarizes the state of a multisig PSBT.
+type MultisigPSBTInfo struct {
+	TxHash string
+	// Signatures is the least number of signatures on any of the inputs.
+	Signatures int32
+	Threshold  int32
+	Fee        AssetAmount
+	// FeeRate is the fee paid per kvB of the estimated signed size.
+	FeeRate AssetAmount
+	// Outputs maps each output address to the amount it receives. Change
+	// outputs paying back to the multisig are not included.
+	Outputs map[string]AssetAmount
+	// Change maps each change address of the multisig to the amount it
+	// receives.
+	Change map[string]AssetAmount
+}
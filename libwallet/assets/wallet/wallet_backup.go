@@ -0,0 +1,273 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"decred.org/dcrwallet/v3/errors"
+	"github.com/asdine/storm"
+	"github.com/asdine/storm/q"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+	"github.com/kevinburke/nacl"
+	"github.com/kevinburke/nacl/secretbox"
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/crypto/scrypt"
+)
+
+// WalletBackup holds the data needed to recreate a wallet and its
+// preferences from an app backup.
+type WalletBackup struct {
+	Name                  string          `json:"name"`
+	Type                  utils.AssetType `json:"type"`
+	Birthday              time.Time       `json:"birthday"`
+	PrivatePassphraseType int32           `json:"private_passphrase_type"`
+	// EncryptedSeed is the seed encrypted with the wallet's spending
+	// passphrase. It is empty if the seed has already been backed up.
+	EncryptedSeed []byte `json:"encrypted_seed,omitempty"`
+	// EncryptedSeedPassphrase is the optional BIP39 passphrase of the seed,
+	// encrypted with the wallet's spending passphrase.
+	EncryptedSeedPassphrase []byte `json:"encrypted_seed_passphrase,omitempty"`
+	// XPub is the extended public key of the default account. It is used to
+	// recreate a watch only wallet if no encrypted seed is available.
+	XPub string `json:"xpub"`
+	// WatchingOnly is set if the wallet could not spend when backed up.
+	// Other wallets without an encrypted seed lose their spending ability
+	// when they are recreated from the backup.
+	WatchingOnly bool `json:"watching_only"`
+	// UserConfig holds the wallet's user config values keyed without the
+	// wallet ID prefix.
+	UserConfig map[string]json.RawMessage `json:"user_config"`
+	// TxLabels maps the hashes of the labelled transactions to their labels.
+	TxLabels map[string]string `json:"tx_labels,omitempty"`
+}
+
+// ExportBackup returns the wallet's encrypted seed, user config values and
+// transaction labels. The XPub and WatchingOnly fields are left for the asset
+// to fill.
+func (wallet *Wallet) ExportBackup() (*WalletBackup, error) {
+	prefix := strconv.Itoa(wallet.ID)
+	values, err := ReadBucketValues(wallet.db, userConfigBucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	userConfig := make(map[string]json.RawMessage)
+	for key, value := range values {
+		// Keys of wallet 1 are also a prefix of the keys of wallet 12.
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		key = strings.TrimPrefix(key, prefix)
+		if key == "" || unicode.IsDigit(rune(key[0])) {
+			continue
+		}
+		userConfig[key] = value
+	}
+
+	txLabels := make(map[string]string)
+	if wallet.walletDataDB != nil {
+		var txs []Transaction
+		err = wallet.walletDataDB.Find(q.Not(q.Eq("Label", "")), &txs)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range txs {
+			txLabels[tx.Hash] = tx.Label
+		}
+
+		pending, err := wallet.walletDataDB.PendingTxLabels()
+		if err != nil {
+			return nil, err
+		}
+		for hash, label := range pending {
+			txLabels[hash] = label
+		}
+	}
+
+	return &WalletBackup{
+		Name:                    wallet.Name,
+		Type:                    wallet.Type,
+		Birthday:                wallet.GetBirthday(),
+		PrivatePassphraseType:   wallet.PrivatePassphraseType,
+		EncryptedSeed:           wallet.EncryptedSeed,
+		EncryptedSeedPassphrase: wallet.EncryptedSeedPassphrase,
+		UserConfig:              userConfig,
+		TxLabels:                txLabels,
+	}, nil
+}
+
+// ImportBackup applies the preferences, transaction labels and birthday of
+// the provided backup to the wallet. The encrypted seed is kept if the backup
+// holds one so that the seed can still be backed up later.
+func (wallet *Wallet) ImportBackup(backup *WalletBackup) error {
+	for key, value := range backup.UserConfig {
+		if err := wallet.walletConfigSave(false, key, value); err != nil {
+			return err
+		}
+	}
+
+	if wallet.walletDataDB != nil {
+		for hash, label := range backup.TxLabels {
+			if err := wallet.walletDataDB.SaveTxLabel(&Transaction{}, hash, label); err != nil {
+				return err
+			}
+		}
+	}
+
+	wallet.mu.Lock()
+	defer wallet.mu.Unlock()
+	if !backup.Birthday.IsZero() {
+		wallet.Birthday = backup.Birthday
+	}
+	if backup.EncryptedSeed != nil {
+		wallet.EncryptedSeed = backup.EncryptedSeed
+	}
+	if backup.EncryptedSeedPassphrase != nil {
+		wallet.EncryptedSeedPassphrase = backup.EncryptedSeedPassphrase
+	}
+	return utils.TranslateError(wallet.db.Save(wallet))
+}
+
+// DecryptSeed decrypts the backed up encrypted seed using the wallet's
+// spending passphrase.
+func (backup *WalletBackup) DecryptSeed(privatePassphrase string) (string, error) {
+	if backup.EncryptedSeed == nil {
+		return "", errors.New(utils.ErrInvalid)
+	}
+	return decryptWalletSeed([]byte(privatePassphrase), backup.EncryptedSeed)
+}
+
+// DecryptSeedPassphrase decrypts the backed up BIP39 seed passphrase using
+// the wallet's spending passphrase. It returns an empty passphrase if the
+// seed has none.
+func (backup *WalletBackup) DecryptSeedPassphrase(privatePassphrase string) (string, error) {
+	if backup.EncryptedSeedPassphrase == nil {
+		return "", nil
+	}
+	return decryptWalletSeed([]byte(privatePassphrase), backup.EncryptedSeedPassphrase)
+}
+
+// ReadBucketValues returns the raw values of all the keys stored in the
+// provided storm key/value bucket.
+func ReadBucketValues(db *storm.DB, bucket string) (map[string]json.RawMessage, error) {
+	values := make(map[string]json.RawMessage)
+	err := db.Bolt.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			if v == nil {
+				// Nested bucket.
+				return nil
+			}
+			values[string(k)] = append(json.RawMessage(nil), v...)
+			return nil
+		})
+	})
+	return values, err
+}
+
+// backupMagic identifies encrypted app backup archives.
+var backupMagic = [4]byte{'c', 'p', 'b', 'k'}
+
+const (
+	// backupHeaderVersion is the version of the header of encrypted app
+	// backup archives.
+	backupHeaderVersion = 1
+
+	// backupScryptLogN, backupScryptR and backupScryptP are the scrypt
+	// parameters used to encrypt new backups. Archives record theirs in their
+	// header so the parameters can be raised without breaking older archives.
+	backupScryptLogN = 15
+	backupScryptR    = 8
+	backupScryptP    = 1
+)
+
+// backupHeader prefixes encrypted app backup archives. It holds the random
+// salt and the scrypt parameters that derive the archive's key from the
+// backup passphrase.
+type backupHeader struct {
+	Magic   [4]byte
+	Version uint8
+	LogN    uint8
+	R       uint8
+	P       uint8
+	Salt    [32]byte
+}
+
+// key derives the archive's key from passphrase.
+func (header *backupHeader) key(passphrase string) (nacl.Key, error) {
+	if header.Magic != backupMagic {
+		return nil, errors.E(utils.ErrInvalid, "not an app backup")
+	}
+	if header.Version != backupHeaderVersion {
+		return nil, errors.E(utils.ErrInvalid, fmt.Sprintf("unsupported backup header version %d", header.Version))
+	}
+	// Bound the parameters read from the archive so that a crafted archive
+	// can't exhaust the memory.
+	if header.LogN < 10 || header.LogN > 20 || header.R == 0 || header.R > 32 || header.P == 0 || header.P > 16 {
+		return nil, errors.E(utils.ErrInvalid, "invalid backup key derivation parameters")
+	}
+
+	hash, err := scrypt.Key([]byte(passphrase), header.Salt[:], 1<<header.LogN, int(header.R), int(header.P), 32)
+	if err != nil {
+		return nil, err
+	}
+	return nacl.Load(utils.EncodeHex(hash))
+}
+
+// EncryptBackup encrypts the provided backup data with secretbox.EasySeal
+// using a key derived from passphrase and a random salt. The salt and the key
+// derivation parameters are written in a header before the encrypted data.
+func EncryptBackup(passphrase string, data []byte) ([]byte, error) {
+	header := &backupHeader{
+		Magic:   backupMagic,
+		Version: backupHeaderVersion,
+		LogN:    backupScryptLogN,
+		R:       backupScryptR,
+		P:       backupScryptP,
+	}
+	if _, err := rand.Read(header.Salt[:]); err != nil {
+		return nil, err
+	}
+
+	key, err := header.key(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	if err = binary.Write(buf, binary.BigEndian, header); err != nil {
+		return nil, err
+	}
+	buf.Write(secretbox.EasySeal(data, key))
+	return buf.Bytes(), nil
+}
+
+// DecryptBackup decrypts backup data encrypted with EncryptBackup.
+func DecryptBackup(passphrase string, encrypted []byte) ([]byte, error) {
+	header := new(backupHeader)
+	reader := bytes.NewReader(encrypted)
+	if err := binary.Read(reader, binary.BigEndian, header); err != nil {
+		return nil, errors.E(utils.ErrInvalid, "not an app backup")
+	}
+
+	key, err := header.key(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := secretbox.EasyOpen(encrypted[binary.Size(header):], key)
+	if err != nil {
+		return nil, errors.New(utils.ErrInvalidPassphrase)
+	}
+	return data, nil
+}
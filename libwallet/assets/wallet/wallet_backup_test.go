@@ -0,0 +1,201 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/asdine/storm"
+	"github.com/crypto-power/cryptopower/libwallet/assets/wallet/walletdata"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+)
+
+// newTestWallet returns a wallet with the provided ID whose databases are
+// created in a temporary directory.
+func newTestWallet(t *testing.T, id int) *Wallet {
+	t.Helper()
+	dir := t.TempDir()
+	db, err := storm.Open(filepath.Join(dir, "wallets.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	walletDataDB, err := walletdata.Initialize(filepath.Join(dir, walletdata.BTCDBName), &Transaction{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { walletDataDB.Close() })
+
+	wallet := &Wallet{ID: id, Name: "wallet", Type: utils.BTCWalletAsset, db: db, walletDataDB: walletDataDB}
+	if err := db.Save(wallet); err != nil {
+		t.Fatal(err)
+	}
+	return wallet
+}
+
+func TestBackupEncryption(t *testing.T) {
+	data := []byte(`{"version":1}`)
+	tests := []struct {
+		name       string
+		passphrase string
+		decryptBy  string
+		valid      bool
+	}{
+		{name: "same passphrase", passphrase: "backup pass", decryptBy: "backup pass", valid: true},
+		{name: "wrong passphrase", passphrase: "backup pass", decryptBy: "other pass"},
+		{name: "empty passphrase", passphrase: "", decryptBy: "", valid: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			encrypted, err := EncryptBackup(tc.passphrase, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decrypted, err := DecryptBackup(tc.decryptBy, encrypted)
+			if (err == nil) != tc.valid {
+				t.Fatalf("(%v), expected valid (%v), got (%v)", tc.name, tc.valid, err)
+			}
+			if err == nil && string(decrypted) != string(data) {
+				t.Errorf("(%v), expected (%s), got (%s)", tc.name, data, decrypted)
+			}
+		})
+	}
+}
+
+func TestBackupHeader(t *testing.T) {
+	const passphrase = "backup pass"
+	data := []byte(`{"version":1}`)
+	encrypted, err := EncryptBackup(passphrase, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every archive has its own salt.
+	other, err := EncryptBackup(passphrase, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	headerSize := binary.Size(backupHeader{})
+	if bytes.Equal(encrypted[:headerSize], other[:headerSize]) {
+		t.Errorf("expected archives of the same data to have different headers")
+	}
+
+	modified := func(offset int, value byte) []byte {
+		b := append([]byte(nil), encrypted...)
+		b[offset] = value
+		return b
+	}
+
+	tests := []struct {
+		name      string
+		encrypted []byte
+		valid     bool
+	}{
+		{name: "valid", encrypted: encrypted, valid: true},
+		{name: "truncated header", encrypted: encrypted[:headerSize-1]},
+		{name: "no magic", encrypted: modified(0, 'x')},
+		{name: "unsupported version", encrypted: modified(4, backupHeaderVersion+1)},
+		{name: "memory hungry parameters", encrypted: modified(5, 30)},
+		{name: "other parameters", encrypted: modified(6, backupScryptR+1)},
+		{name: "other salt", encrypted: modified(headerSize-1, encrypted[headerSize-1]+1)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := DecryptBackup(passphrase, tc.encrypted)
+			if (err == nil) != tc.valid {
+				t.Errorf("(%v), expected valid (%v), got (%v)", tc.name, tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestWalletBackupRoundTrip(t *testing.T) {
+	const privatePass = "spending pass"
+	source := newTestWallet(t, 1)
+	source.Birthday = time.Unix(1600000000, 0).UTC()
+	source.SaveUserConfigValue(HideBalanceConfigKey, true)
+	source.SaveUserConfigValue(AddressLabelConfigKeyPrefix+"addr", "savings")
+
+	var err error
+	if source.EncryptedSeed, err = encryptWalletSeed([]byte(privatePass), "seed words"); err != nil {
+		t.Fatal(err)
+	}
+	if source.EncryptedSeedPassphrase, err = encryptWalletSeed([]byte(privatePass), "seed passphrase"); err != nil {
+		t.Fatal(err)
+	}
+
+	indexed := &Transaction{Hash: "indexed", Timestamp: 1600000000, Label: "rent"}
+	if _, err = source.walletDataDB.SaveOrUpdate(&Transaction{}, indexed); err != nil {
+		t.Fatal(err)
+	}
+	// Labels of transactions that are not indexed yet are exported too.
+	if err = source.walletDataDB.SaveTxLabel(&Transaction{}, "pending", "salary"); err != nil {
+		t.Fatal(err)
+	}
+
+	backup, err := source.ExportBackup()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The backup is imported into a wallet with another ID whose
+	// transactions are not indexed yet.
+	target := newTestWallet(t, 12)
+	if err = target.ImportBackup(backup); err != nil {
+		t.Fatal(err)
+	}
+
+	var txs []Transaction
+	if err = target.walletDataDB.Find(nil, &txs); err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 0 {
+		t.Errorf("expected no transaction records before indexing, got (%d)", len(txs))
+	}
+	for _, hash := range []string{"indexed", "pending"} {
+		tx := &Transaction{Hash: hash, Timestamp: 1600000600}
+		if _, err = target.walletDataDB.SaveOrUpdate(&Transaction{}, tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	seed, _ := backup.DecryptSeed(privatePass)
+	seedPassphrase, _ := backup.DecryptSeedPassphrase(privatePass)
+
+	tests := []struct {
+		name     string
+		expected interface{}
+		got      interface{}
+	}{
+		{name: "bool config", expected: true, got: target.ReadBoolConfigValueForKey(HideBalanceConfigKey, false)},
+		{name: "address label", expected: "savings", got: target.AddressLabel("addr")},
+		{name: "birthday", expected: source.Birthday, got: target.GetBirthday()},
+		{name: "indexed tx label", expected: "rent", got: txLabel(t, target, "indexed")},
+		{name: "pending tx label", expected: "salary", got: txLabel(t, target, "pending")},
+		{name: "seed", expected: "seed words", got: seed},
+		{name: "seed passphrase", expected: "seed passphrase", got: seedPassphrase},
+		{name: "other wallet config", expected: false, got: newTestWallet(t, 2).ReadBoolConfigValueForKey(HideBalanceConfigKey, false)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got != tc.expected {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.expected, tc.got)
+			}
+		})
+	}
+}
+
+func txLabel(t *testing.T, wallet *Wallet, hash string) string {
+	t.Helper()
+	tx := new(Transaction)
+	if err := wallet.walletDataDB.FindOne("Hash", hash, tx); err != nil {
+		t.Fatal(err)
+	}
+	return tx.Label
+}
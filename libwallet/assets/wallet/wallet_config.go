@@ -30,7 +30,12 @@ const (
 	SyncOnCellularConfigKey             = "always_sync"
 	NetworkModeConfigKey                = "network_mode"
 	SpvPersistentPeerAddressesConfigKey = "spv_peer_addresses"
+	SpvPeerConfigKey                    = "spv_peer_config"
+	RPCSyncConfigKey                    = "rpc_sync_config"
 	UserAgentConfigKey                  = "user_agent"
+	ProxyConfigKey                      = "proxy_config"
+	DevModeConfigKey                    = "dev_mode_config"
+	SyncPolicyConfigKey                 = "sync_policy"
 
 	PoliteiaNotificationConfigKey = "politeia_notification"
 
@@ -38,15 +43,18 @@ const (
 
 	KnownVSPsConfigKey = "known_vsps"
 
-	TicketBuyerVSPHostConfigKey = "tb_vsp_host"
-	TicketBuyerWalletConfigKey  = "tb_wallet_id"
-	TicketBuyerAccountConfigKey = "tb_account_number"
-	TicketBuyerATMConfigKey     = "tb_amount_to_maintain"
+	TicketBuyerVSPHostConfigKey   = "tb_vsp_host"
+	TicketBuyerWalletConfigKey    = "tb_wallet_id"
+	TicketBuyerAccountConfigKey   = "tb_account_number"
+	TicketBuyerATMConfigKey       = "tb_amount_to_maintain"
+	TicketBuyerConfigKey          = "tb_config"
+	TicketBuyerDecisionsConfigKey = "tb_decisions"
 
 	ExchangeSourceDstnTypeConfigKey = "exchange_source_destination_key"
 
 	HideBalanceConfigKey             = "hide_balance"
 	AutoSyncConfigKey                = "autoSync"
+	OpenOnDemandConfigKey            = "open_on_demand"
 	FetchProposalConfigKey           = "fetch_proposals"
 	SeedBackupNotificationConfigKey  = "seed_backup_notification"
 	ProposalNotificationConfigKey    = "proposal_notification_key"
@@ -56,6 +64,17 @@ const (
 	LanguagePreferenceKey            = "app_language"
 	DarkModeConfigKey                = "dark_mode"
 	HideTotalBalanceConfigKey        = "hideTotalUSDBalance"
+	AddressLabelConfigKeyPrefix      = "address_label_"
+	MultisigConfigKey                = "multisig_config"
+	MultisigWalletConfigKey          = "multisig_wallet"
+	MasterKeyFingerprintConfigKey    = "master_key_fingerprint"
+	AccountScopePurposeConfigKey     = "account_scope_purpose"
+	WatchOnlyDescriptorConfigKey     = "watch_only_descriptor"
+	WatchOnlyKeyConfigKey            = "watch_only_key"
+	BIP39SeedConfigKey               = "bip39_seed"
+	UsedKeyScopePurposesConfigKey    = "used_key_scope_purposes"
+	WatchedAddressesConfigKey        = "watched_addresses"
+	BirthdaySetByUserConfigKey       = "birthday_set_by_user"
 
 	PassphraseTypePin  int32 = 0
 	PassphraseTypePass int32 = 1
@@ -2,6 +2,7 @@ package wallet
 
 import (
 	"context"
+	"math"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -13,7 +14,9 @@ import (
 	w "decred.org/dcrwallet/v3/wallet"
 	"github.com/asdine/storm"
 	"github.com/crypto-power/cryptopower/libwallet/assets/wallet/walletdata"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
 	"github.com/crypto-power/cryptopower/libwallet/internal/loader"
+	"github.com/crypto-power/cryptopower/libwallet/migration"
 	"github.com/crypto-power/cryptopower/libwallet/utils"
 )
 
@@ -26,11 +29,15 @@ type Wallet struct {
 	rootDir   string
 	db        *storm.DB
 	logDir    string
+	events    *eventbus.Bus
 
-	EncryptedSeed         []byte
-	IsRestored            bool
-	HasDiscoveredAccounts bool
-	PrivatePassphraseType int32
+	EncryptedSeed []byte
+	// EncryptedSeedPassphrase is the optional BIP39 passphrase of the seed
+	// the wallet was restored from, encrypted like EncryptedSeed.
+	EncryptedSeedPassphrase []byte
+	IsRestored              bool
+	HasDiscoveredAccounts   bool
+	PrivatePassphraseType   int32
 
 	netType      utils.NetworkType
 	chainsParams *utils.ChainsParams
@@ -67,6 +74,7 @@ func (wallet *Wallet) Prepare(loader loader.AssetLoader, params *InitParams) (er
 	wallet.netType = params.NetType
 	wallet.rootDir = params.RootDir
 	wallet.logDir = params.LogDir
+	wallet.events = params.Events
 	return wallet.prepare()
 }
 
@@ -79,6 +87,7 @@ func (wallet *Wallet) Prepare(loader loader.AssetLoader, params *InitParams) (er
 // wallet.rootDir = rootDir
 // wallet.Type = assetType
 // wallet.logDir = params.LogDir
+// wallet.events = params.Events
 func (wallet *Wallet) prepare() (err error) {
 	// Confirms if the correct wallet type and network types were set and passed.
 	// Wallet type should be preset by the caller otherwise an error is returned.
@@ -168,12 +177,33 @@ func (wallet *Wallet) Shutdown() {
 }
 
 func (wallet *Wallet) TargetTimePerBlockMinutes() float64 {
-	if wallet.Type == utils.BTCWalletAsset {
+	switch wallet.Type {
+	case utils.BTCWalletAsset:
 		return wallet.chainsParams.BTC.TargetTimePerBlock.Minutes()
+	case utils.LTCWalletAsset:
+		return wallet.chainsParams.LTC.TargetTimePerBlock.Minutes()
 	}
 	return wallet.chainsParams.DCR.TargetTimePerBlock.Minutes()
 }
 
+// EstimateBlockHeightAtTime estimates the height of the block mined around the
+// provided time using the provided best block and the network's target time
+// per block. Since actual block times drift from the target, the estimate is
+// padded to err on the earlier side. It is never below zero or above the best
+// block height.
+func (wallet *Wallet) EstimateBlockHeightAtTime(t time.Time, bestBlockHeight int32, bestBlockTimestamp int64) int32 {
+	secondsBehindBestBlock := float64(bestBlockTimestamp - t.Unix())
+	if secondsBehindBestBlock <= 0 {
+		return bestBlockHeight
+	}
+
+	blocksBehind := int32(math.Ceil(1.1 * secondsBehindBestBlock / (wallet.TargetTimePerBlockMinutes() * 60)))
+	if blocksBehind >= bestBlockHeight {
+		return 0
+	}
+	return bestBlockHeight - blocksBehind
+}
+
 // WalletCreationTimeInMillis returns the wallet creation time for new
 // wallets. Restored wallets would return an error.
 func (wallet *Wallet) WalletCreationTimeInMillis() (int64, error) {
@@ -253,6 +283,14 @@ func (wallet *Wallet) GetEncryptedSeed() string {
 	return string(wallet.EncryptedSeed)
 }
 
+// HasSeedPassphrase returns true if the wallet was restored from a BIP39 seed
+// with a passphrase.
+func (wallet *Wallet) HasSeedPassphrase() bool {
+	wallet.mu.RLock()
+	defer wallet.mu.RUnlock()
+	return wallet.EncryptedSeedPassphrase != nil
+}
+
 func (wallet *Wallet) GetWalletID() int {
 	wallet.mu.RLock()
 	defer wallet.mu.RUnlock()
@@ -299,18 +337,51 @@ func (wallet *Wallet) GetBirthday() time.Time {
 }
 
 // SetBirthday allows updating the birthday time to a more precise value that is
-// verified by the network.
-func (wallet *Wallet) SetBirthday(birthday time.Time) {
-	if birthday.IsZero() {
-		log.Error("updated birthday time cannot be zero")
-		return
+// verified by the network or to one set by the user. The birthday must not be
+// before the genesis block nor in the future.
+func (wallet *Wallet) SetBirthday(birthday time.Time) error {
+	if birthday.IsZero() || birthday.After(time.Now()) || birthday.Before(wallet.genesisTime()) {
+		return utils.ErrInvalidBirthday
 	}
 
 	wallet.mu.Lock()
+	defer wallet.mu.Unlock()
 	wallet.Birthday = birthday
 	// Triggers db update with the new birthday time.
-	wallet.db.Save(wallet)
-	wallet.mu.Unlock()
+	return wallet.db.Save(wallet)
+}
+
+// genesisTime returns the time the genesis block of the wallet's chain was
+// mined.
+func (wallet *Wallet) genesisTime() time.Time {
+	switch wallet.Type {
+	case utils.BTCWalletAsset:
+		return wallet.chainsParams.BTC.GenesisBlock.Header.Timestamp
+	case utils.LTCWalletAsset:
+		return wallet.chainsParams.LTC.GenesisBlock.Header.Timestamp
+	}
+	return wallet.chainsParams.DCR.GenesisBlock.Header.Timestamp
+}
+
+// SearchBlockHeightAtTime returns the height of the last block mined before t
+// by binary searching the block timestamps returned by blockTimestamp between
+// the genesis block and bestBlockHeight.
+func SearchBlockHeightAtTime(t time.Time, bestBlockHeight int32, blockTimestamp func(height int32) (int64, error)) (int32, error) {
+	target := t.Unix()
+	low, high := int32(0), bestBlockHeight
+	for low < high {
+		mid := low + (high-low+1)/2
+		timestamp, err := blockTimestamp(mid)
+		if err != nil {
+			return 0, err
+		}
+		if timestamp < target {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	return low, nil
 }
 
 func CreateNewWallet(pass *AuthInfo, loader loader.AssetLoader,
@@ -332,6 +403,7 @@ func CreateNewWallet(pass *AuthInfo, loader loader.AssetLoader,
 		dbDriver:      params.DbDriver,
 		rootDir:       params.RootDir,
 		logDir:        params.LogDir,
+		events:        params.Events,
 		CreatedAt:     time.Now(),
 		EncryptedSeed: encryptedSeed,
 
@@ -347,17 +419,17 @@ func CreateNewWallet(pass *AuthInfo, loader loader.AssetLoader,
 		if err != nil {
 			return err
 		}
-		return wallet.createWallet(pass.PrivatePass, seed)
+		return wallet.createWallet(pass.PrivatePass, seed, "")
 	})
 }
 
-func (wallet *Wallet) createWallet(privatePassphrase, seedMnemonic string) error {
+func (wallet *Wallet) createWallet(privatePassphrase, seedMnemonic, seedPassphrase string) error {
 	log.Info("Creating Wallet")
 	if len(seedMnemonic) == 0 {
 		return errors.New(utils.ErrEmptySeed)
 	}
 
-	seed, err := DecodeSeedMnemonic(seedMnemonic, wallet.Type)
+	seed, err := DecodeSeedMnemonicWithPassphrase(seedMnemonic, seedPassphrase, wallet.Type)
 	if err != nil {
 		log.Error(err)
 		return err
@@ -383,6 +455,15 @@ func (wallet *Wallet) createWallet(privatePassphrase, seedMnemonic string) error
 
 func CreateWatchOnlyWallet(walletName, extendedPublicKey string, loader loader.AssetLoader,
 	params *InitParams, assetType utils.AssetType,
+) (*Wallet, error) {
+	return CreateWatchOnlyWalletWithScope(walletName, &DescriptorKey{XPub: extendedPublicKey}, 0, loader, params, assetType)
+}
+
+// CreateWatchOnlyWalletWithScope creates a watch only wallet for the provided
+// account key. The key is imported into the key scope of the provided BIP43
+// purpose or into the loader's default scope if the purpose is 0.
+func CreateWatchOnlyWalletWithScope(walletName string, key *DescriptorKey, purpose uint32,
+	loader loader.AssetLoader, params *InitParams, assetType utils.AssetType,
 ) (*Wallet, error) {
 	wallet := &Wallet{
 		Name:     walletName,
@@ -390,6 +471,7 @@ func CreateWatchOnlyWallet(walletName, extendedPublicKey string, loader loader.A
 		dbDriver: params.DbDriver,
 		rootDir:  params.RootDir,
 		logDir:   params.LogDir,
+		events:   params.Events,
 
 		IsRestored: true,
 		// Setting HasDiscoveredAccounts to false causes address recovery to be
@@ -400,20 +482,56 @@ func CreateWatchOnlyWallet(walletName, extendedPublicKey string, loader loader.A
 		netType:               params.NetType,
 	}
 
-	return wallet.saveNewWallet(func() error {
+	w, err := wallet.saveNewWallet(func() error {
 		err := wallet.prepare()
 		if err != nil {
 			return err
 		}
-		return wallet.createWatchingOnlyWallet(extendedPublicKey)
+		return wallet.createWatchingOnlyWallet(key, purpose)
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The key allows the wallet database to be recreated if it is lost.
+	w.SaveUserConfigValue(WatchOnlyKeyConfigKey, key.String())
+	return w, nil
+}
+
+// RecreateWatchOnlyWallet creates a new watch only wallet database for this
+// wallet from the account key saved when the wallet was created. The current
+// wallet database must have been moved out of the way.
+func (wallet *Wallet) RecreateWatchOnlyWallet() error {
+	if wallet.loader == nil {
+		return errors.New(utils.ErrWalletNotLoaded)
+	}
+
+	keyExpr := wallet.ReadStringConfigValueForKey(WatchOnlyKeyConfigKey, "")
+	if keyExpr == "" {
+		return errors.New(utils.ErrNotExist)
+	}
+
+	key, err := parseDescriptorKey(keyExpr)
+	if err != nil {
+		return err
+	}
+
+	var purpose uint32
+	wallet.ReadUserConfigValue(AccountScopePurposeConfigKey, &purpose)
+
+	if err = wallet.createWatchingOnlyWallet(key, purpose); err != nil {
+		return err
+	}
+	return wallet.loader.UnloadWallet()
 }
 
-func (wallet *Wallet) createWatchingOnlyWallet(extendedPublicKey string) error {
+func (wallet *Wallet) createWatchingOnlyWallet(key *DescriptorKey, purpose uint32) error {
 	params := &loader.WatchOnlyWalletParams{
-		WalletID:       strconv.Itoa(wallet.ID),
-		PubPassphrase:  []byte(w.InsecurePubPassphrase),
-		ExtendedPubKey: extendedPublicKey,
+		WalletID:             strconv.Itoa(wallet.ID),
+		PubPassphrase:        []byte(w.InsecurePubPassphrase),
+		ExtendedPubKey:       key.XPub,
+		KeyScopePurpose:      purpose,
+		MasterKeyFingerprint: key.Fingerprint,
 	}
 
 	ctx, _ := wallet.ShutdownContextWithCancel()
@@ -437,6 +555,7 @@ func RestoreWallet(seedMnemonic string, pass *AuthInfo, loader loader.AssetLoade
 		dbDriver:              params.DbDriver,
 		rootDir:               params.RootDir,
 		logDir:                params.LogDir,
+		events:                params.Events,
 
 		IsRestored:            true,
 		HasDiscoveredAccounts: false,
@@ -445,12 +564,20 @@ func RestoreWallet(seedMnemonic string, pass *AuthInfo, loader loader.AssetLoade
 		netType:               params.NetType,
 	}
 
+	if pass.SeedPassphrase != "" {
+		encryptedSeedPassphrase, err := encryptWalletSeed([]byte(pass.PrivatePass), pass.SeedPassphrase)
+		if err != nil {
+			return nil, err
+		}
+		wallet.EncryptedSeedPassphrase = encryptedSeedPassphrase
+	}
+
 	return wallet.saveNewWallet(func() error {
 		err := wallet.prepare()
 		if err != nil {
 			return err
 		}
-		return wallet.createWallet(pass.PrivatePass, seedMnemonic)
+		return wallet.createWallet(pass.PrivatePass, seedMnemonic, pass.SeedPassphrase)
 	})
 }
 
@@ -571,6 +698,44 @@ func (wallet *Wallet) OpenWallet() error {
 	return nil
 }
 
+// CloseWallet unloads the upstream wallet and closes its database while the
+// wallet data db remains open. The wallet can be reopened with OpenWallet.
+func (wallet *Wallet) CloseWallet() error {
+	if _, loaded := wallet.loader.GetLoadedWallet(); !loaded {
+		return nil
+	}
+	return wallet.loader.UnloadWallet()
+}
+
+// CloseDatabases closes the wallet database and the wallet data db of a
+// wallet that is not in use, such as one that failed to load, so that the
+// wallet can be loaded again.
+func (wallet *Wallet) CloseDatabases() {
+	if wallet.loader != nil {
+		if err := wallet.CloseWallet(); err != nil {
+			log.Errorf("Failed to close wallet: %v", err)
+		}
+	}
+
+	if wallet.walletDataDB != nil {
+		if err := wallet.walletDataDB.Close(); err != nil {
+			log.Errorf("tx db closed with error: %v", err)
+		}
+		wallet.walletDataDB = nil
+	}
+}
+
+// MigrateWalletData upgrades the wallet data db if its version is outdated.
+// The listener is notified of the progress of each migration.
+func (wallet *Wallet) MigrateWalletData(listener migration.ProgressListener) error {
+	if wallet.walletDataDB == nil || !wallet.walletDataDB.NeedsMigration() {
+		return nil
+	}
+
+	log.Infof("Migrating the wallet data db of wallet %s", wallet.Name)
+	return wallet.walletDataDB.Migrate(wallet.Name, listener)
+}
+
 // WalletOpened checks if the upstream loader instance of the asset wallet
 // is loaded (i.e. open).
 func (wallet *Wallet) WalletOpened() bool {
@@ -666,12 +831,26 @@ func (wallet *Wallet) ChangePrivatePassphraseForWallet(oldPrivatePassphrase, new
 		}
 	}
 
+	encryptedSeedPassphrase := wallet.EncryptedSeedPassphrase
+	if encryptedSeedPassphrase != nil {
+		seedPassphrase, err := decryptWalletSeed(oldPassphrase, encryptedSeedPassphrase)
+		if err != nil {
+			return err
+		}
+
+		encryptedSeedPassphrase, err = encryptWalletSeed(newPassphrase, seedPassphrase)
+		if err != nil {
+			return err
+		}
+	}
+
 	err := wallet.changePrivatePassphrase(oldPassphrase, newPassphrase)
 	if err != nil {
 		return utils.TranslateError(err)
 	}
 
 	wallet.EncryptedSeed = encryptedSeed
+	wallet.EncryptedSeedPassphrase = encryptedSeedPassphrase
 	wallet.PrivatePassphraseType = privatePassphraseType
 	err = wallet.db.Save(wallet)
 	if err != nil {
@@ -0,0 +1,84 @@
+package wallet
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSearchBlockHeightAtTime(t *testing.T) {
+	// Block n is mined at 1000 + 100n, block 5 has an earlier timestamp than
+	// block 4 as allowed by the median time rule.
+	timestamps := []int64{1000, 1100, 1200, 1300, 1400, 1350, 1600, 1700}
+	bestBlockHeight := int32(len(timestamps) - 1)
+	errBlock := errors.New("block not found")
+
+	tests := []struct {
+		name     string
+		t        int64
+		best     int32
+		expected int32
+		err      error
+	}{
+		{name: "before genesis", t: 500, best: bestBlockHeight, expected: 0},
+		{name: "at genesis", t: 1000, best: bestBlockHeight, expected: 0},
+		{name: "between blocks", t: 1250, best: bestBlockHeight, expected: 2},
+		{name: "at a block", t: 1300, best: bestBlockHeight, expected: 2},
+		{name: "after the best block", t: 5000, best: bestBlockHeight, expected: bestBlockHeight},
+		{name: "only genesis", t: 5000, best: 0, expected: 0},
+		{name: "unordered timestamps", t: 1550, best: bestBlockHeight, expected: 5},
+		{name: "missing block", t: 1250, best: bestBlockHeight + 10, err: errBlock},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			height, err := SearchBlockHeightAtTime(time.Unix(tc.t, 0), tc.best, func(height int32) (int64, error) {
+				if int(height) >= len(timestamps) {
+					return 0, errBlock
+				}
+				return timestamps[height], nil
+			})
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("(%v), expected error (%v), got (%v)", tc.name, tc.err, err)
+			}
+			if err == nil && height != tc.expected {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.expected, height)
+			}
+		})
+	}
+}
+
+func TestUnspentWatchOnlyAmount(t *testing.T) {
+	received := &Transaction{Hash: "a", Outputs: []*TxOutput{
+		{Index: 0, Amount: 100, WatchOnly: true},
+		{Index: 1, Amount: 50, WatchOnly: true},
+		{Index: 2, Amount: 1000},
+	}}
+	spending := &Transaction{Hash: "b",
+		Inputs:  []*TxInput{{PreviousOutpoint: "a:1", WatchOnly: true}},
+		Outputs: []*TxOutput{{Index: 0, Amount: 40, WatchOnly: true}},
+	}
+	spendingAll := &Transaction{Hash: "c",
+		Inputs: []*TxInput{{PreviousOutpoint: "a:0", WatchOnly: true}, {PreviousOutpoint: "b:0", WatchOnly: true}},
+	}
+
+	tests := []struct {
+		name     string
+		txs      []*Transaction
+		expected int64
+	}{
+		{name: "no txs", expected: 0},
+		{name: "received", txs: []*Transaction{received}, expected: 150},
+		{name: "partly spent", txs: []*Transaction{received, spending}, expected: 140},
+		{name: "spender indexed first", txs: []*Transaction{spending, received}, expected: 140},
+		{name: "all spent", txs: []*Transaction{received, spending, spendingAll}, expected: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := UnspentWatchOnlyAmount(tc.txs); got != tc.expected {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.expected, got)
+			}
+		})
+	}
+}
@@ -2,19 +2,25 @@ package wallet
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"decred.org/dcrwallet/v3/errors"
 	"decred.org/dcrwallet/v3/walletseed"
 	"github.com/asdine/storm"
+	"github.com/btcsuite/btcd/btcutil"
 	btchdkeychain "github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/crypto-power/cryptopower/libwallet/internal/slip39"
 	"github.com/crypto-power/cryptopower/libwallet/utils"
 	dcrhdkeychain "github.com/decred/dcrd/hdkeychain/v3"
 	"github.com/kevinburke/nacl"
 	"github.com/kevinburke/nacl/secretbox"
 	ltchdkeychain "github.com/ltcsuite/ltcd/ltcutil/hdkeychain"
+	"github.com/tyler-smith/go-bip39"
 	"golang.org/x/crypto/scrypt"
 )
 
@@ -122,6 +128,17 @@ func (wallet *Wallet) DecryptSeed(privatePassphrase string) (string, error) {
 	return decryptWalletSeed([]byte(privatePassphrase), wallet.EncryptedSeed)
 }
 
+// DecryptSeedPassphrase decrypts wallet.EncryptedSeedPassphrase using
+// privatePassphrase. It returns an empty passphrase if the wallet's seed has
+// none.
+func (wallet *Wallet) DecryptSeedPassphrase(privatePassphrase string) (string, error) {
+	if wallet.EncryptedSeedPassphrase == nil {
+		return "", nil
+	}
+
+	return decryptWalletSeed([]byte(privatePassphrase), wallet.EncryptedSeedPassphrase)
+}
+
 // VerifySeedForWallet compares seedMnemonic with the decrypted wallet.EncryptedSeed and clears wallet.EncryptedSeed if they match.
 func (wallet *Wallet) VerifySeedForWallet(seedMnemonic, privpass string) (bool, error) {
 	decryptedSeed, err := decryptWalletSeed([]byte(privpass), wallet.EncryptedSeed)
@@ -210,13 +227,147 @@ func VerifySeed(seedMnemonic string, assetType utils.AssetType) bool {
 }
 
 func DecodeSeedMnemonic(seedMnemonic string, assetType utils.AssetType) (hashedSeed []byte, err error) {
+	return DecodeSeedMnemonicWithPassphrase(seedMnemonic, "", assetType)
+}
+
+// DecodeSeedMnemonicWithPassphrase decodes the provided seed mnemonic. Seeds
+// split into SLIP-39 shares are recovered from the shares, one per line, using
+// the optional seed passphrase. BTC and LTC wallets also accept BIP39
+// mnemonics whose seed is derived using the optional seed passphrase, other
+// seeds can't have a passphrase.
+func DecodeSeedMnemonicWithPassphrase(seedMnemonic, seedPassphrase string, assetType utils.AssetType) ([]byte, error) {
 	switch assetType {
 	case utils.BTCWalletAsset, utils.DCRWalletAsset, utils.LTCWalletAsset:
-		hashedSeed, err = walletseed.DecodeUserInput(seedMnemonic)
 	default:
-		err = fmt.Errorf("%v: (%v)", utils.ErrAssetUnknown, assetType)
+		return nil, fmt.Errorf("%v: (%v)", utils.ErrAssetUnknown, assetType)
+	}
+
+	if shares := seedShares(seedMnemonic); len(shares) > 0 {
+		return slip39.Combine(shares, []byte(seedPassphrase))
+	}
+
+	if assetType != utils.DCRWalletAsset && IsBIP39Mnemonic(seedMnemonic) {
+		return bip39.NewSeed(normalizeBIP39Mnemonic(seedMnemonic), seedPassphrase), nil
+	}
+
+	if seedPassphrase != "" {
+		return nil, fmt.Errorf("%v: seed passphrases are only supported by BIP39 seeds and SLIP-39 shares", utils.ErrInvalid)
+	}
+	return walletseed.DecodeUserInput(seedMnemonic)
+}
+
+// SplitSeedMnemonic splits the seed of the provided mnemonic into count
+// SLIP-39 share mnemonics, any threshold of which can restore the wallet. Use
+// Wallet.SplitSeed for the seeds of existing wallets.
+func SplitSeedMnemonic(seedMnemonic string, threshold, count int, assetType utils.AssetType) ([]string, error) {
+	seed, err := DecodeSeedMnemonic(seedMnemonic, assetType)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for i := range seed {
+			seed[i] = 0
+		}
+	}()
+
+	return slip39.Split(seed, threshold, count, nil)
+}
+
+// SplitSeed splits the wallet's seed mnemonic into SLIP-39 shares, see
+// SplitSeedMnemonic. The shares only hold the seed, so the seeds of wallets
+// restored with a BIP39 passphrase are not split.
+func (wallet *Wallet) SplitSeed(seedMnemonic string, threshold, count int) ([]string, error) {
+	if wallet.HasSeedPassphrase() {
+		return nil, fmt.Errorf("%v: seeds with a passphrase can't be split into shares", utils.ErrInvalid)
+	}
+	return SplitSeedMnemonic(seedMnemonic, threshold, count, wallet.Type)
+}
+
+// JoinSeedShares joins the provided SLIP-39 share mnemonics into a seed
+// mnemonic that is accepted in place of a wallet's seed mnemonic.
+func JoinSeedShares(shares []string) string {
+	return strings.Join(shares, "\n")
+}
+
+// seedShares returns the SLIP-39 shares of the provided seed mnemonic or nil
+// if it is not made of shares.
+func seedShares(seedMnemonic string) []string {
+	var shares []string
+	for _, line := range strings.Split(seedMnemonic, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if !slip39.IsValidMnemonic(line) {
+			return nil
+		}
+		shares = append(shares, line)
+	}
+	return shares
+}
+
+// IsSLIP39Share returns true if the provided mnemonic is a SLIP-39 share with
+// a valid checksum.
+func IsSLIP39Share(mnemonic string) bool {
+	return slip39.IsValidMnemonic(mnemonic)
+}
+
+// SLIP39WordList returns the SLIP-39 wordlist.
+func SLIP39WordList() []string {
+	return slip39.WordList()
+}
+
+// IsBIP39Mnemonic returns true if the provided mnemonic is a BIP39 mnemonic
+// of 12, 15, 18, 21 or 24 words from the english wordlist with a valid
+// checksum.
+func IsBIP39Mnemonic(seedMnemonic string) bool {
+	switch len(strings.Fields(seedMnemonic)) {
+	case 12, 15, 18, 21, 24:
+		return bip39.IsMnemonicValid(normalizeBIP39Mnemonic(seedMnemonic))
+	}
+	return false
+}
+
+// BIP39WordList returns the english BIP39 wordlist.
+func BIP39WordList() []string {
+	return bip39.GetWordList()
+}
+
+func normalizeBIP39Mnemonic(seedMnemonic string) string {
+	return strings.ToLower(strings.Join(strings.Fields(seedMnemonic), " "))
+}
+
+// SaveMasterKeyFingerprint computes the BIP32 fingerprint of the master key
+// of the provided seed and saves it for it to be used as the key origin of
+// the wallet's output descriptors. It only applies to BTC and LTC wallets
+// whose master key derivation is the same regardless of the network.
+func (wallet *Wallet) SaveMasterKeyFingerprint(seedMnemonic, seedPassphrase string) error {
+	seed, err := DecodeSeedMnemonicWithPassphrase(seedMnemonic, seedPassphrase, wallet.Type)
+	if err != nil {
+		return err
+	}
+
+	masterKey, err := btchdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	if err != nil {
+		return err
 	}
-	return
+	defer masterKey.Zero()
+
+	pubKey, err := masterKey.ECPubKey()
+	if err != nil {
+		return err
+	}
+
+	fingerprint := binary.BigEndian.Uint32(btcutil.Hash160(pubKey.SerializeCompressed())[:4])
+	wallet.SaveUserConfigValue(MasterKeyFingerprintConfigKey, fingerprint)
+	return nil
+}
+
+// MasterKeyFingerprint returns the fingerprint of the wallet's master key or
+// 0 if it is unknown.
+func (wallet *Wallet) MasterKeyFingerprint() uint32 {
+	var fingerprint uint32
+	wallet.ReadUserConfigValue(MasterKeyFingerprintConfigKey, &fingerprint)
+	return fingerprint
 }
 
 func fileExists(filePath string) (bool, error) {
@@ -253,3 +404,34 @@ func backupFile(fileName string, suffix int) (newName string, err error) {
 
 	return newName, nil
 }
+
+// UnspentWatchOnlyAmount returns the total amount of the watch only outputs of
+// the provided transactions that are not spent by any of them.
+func UnspentWatchOnlyAmount(txs []*Transaction) int64 {
+	var total int64
+	ForEachUnspentWatchOnlyOutput(txs, func(_ *Transaction, output *TxOutput) {
+		total += output.Amount
+	})
+	return total
+}
+
+// ForEachUnspentWatchOnlyOutput calls fn with each watch only output of the
+// provided transactions that is not spent by any of them along with the
+// transaction that created it.
+func ForEachUnspentWatchOnlyOutput(txs []*Transaction, fn func(tx *Transaction, output *TxOutput)) {
+	spent := make(map[string]bool)
+	for _, tx := range txs {
+		for _, input := range tx.Inputs {
+			spent[input.PreviousOutpoint] = true
+		}
+	}
+
+	for _, tx := range txs {
+		for _, output := range tx.Outputs {
+			outpoint := tx.Hash + ":" + strconv.Itoa(int(output.Index))
+			if output.WatchOnly && !spent[outpoint] {
+				fn(tx, output)
+			}
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+)
+
+const testBIP39Mnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// TestDecodeSeedMnemonicWithPassphrase checks the seeds restored with and
+// without a seed passphrase against the BIP39 test vectors.
+func TestDecodeSeedMnemonicWithPassphrase(t *testing.T) {
+	shares, err := SplitSeedMnemonic(testBIP39Mnemonic, 2, 3, utils.BTCWalletAsset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bip39Seed := "5eb00bbddcf069084889a8ab9155568165f5c453ccb85e70811aaed6f6da5fc19a5ac40b389cd370d086206dec8aa6c43daea6690f20ad3d8d48b2d2ce9e38e4"
+
+	tests := []struct {
+		name       string
+		mnemonic   string
+		passphrase string
+		assetType  utils.AssetType
+		expected   string
+		valid      bool
+	}{
+		{
+			name:      "bip39 without passphrase",
+			mnemonic:  testBIP39Mnemonic,
+			assetType: utils.BTCWalletAsset,
+			expected:  bip39Seed,
+			valid:     true,
+		},
+		{
+			name:       "bip39 with passphrase",
+			mnemonic:   " " + strings.ToUpper(testBIP39Mnemonic) + "\n",
+			passphrase: "TREZOR",
+			assetType:  utils.LTCWalletAsset,
+			expected:   "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+			valid:      true,
+		},
+		{
+			name:      "shares without passphrase",
+			mnemonic:  JoinSeedShares([]string{shares[2], shares[0]}),
+			assetType: utils.BTCWalletAsset,
+			expected:  bip39Seed,
+			valid:     true,
+		},
+		{name: "too few shares", mnemonic: shares[1], assetType: utils.BTCWalletAsset},
+		{name: "bip39 for dcr", mnemonic: testBIP39Mnemonic, passphrase: "TREZOR", assetType: utils.DCRWalletAsset},
+		{name: "unknown asset", mnemonic: testBIP39Mnemonic, assetType: utils.AssetType("XYZ")},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			seed, err := DecodeSeedMnemonicWithPassphrase(tc.mnemonic, tc.passphrase, tc.assetType)
+			if (err == nil) != tc.valid {
+				t.Fatalf("(%v), expected valid (%v), got (%v)", tc.name, tc.valid, err)
+			}
+			if err == nil && hex.EncodeToString(seed) != tc.expected {
+				t.Errorf("(%v), expected (%v), got (%x)", tc.name, tc.expected, seed)
+			}
+		})
+	}
+
+	// The passphrase of shares derives another seed instead of failing.
+	seed, err := DecodeSeedMnemonicWithPassphrase(JoinSeedShares(shares[:2]), "TREZOR", utils.BTCWalletAsset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(seed) == bip39Seed {
+		t.Errorf("expected shares with a passphrase to derive another seed, got (%x)", seed)
+	}
+}
+
+func TestSplitSeed(t *testing.T) {
+	const privatePass = "spending pass"
+	withPassphrase := &Wallet{Type: utils.BTCWalletAsset}
+	var err error
+	if withPassphrase.EncryptedSeedPassphrase, err = encryptWalletSeed([]byte(privatePass), "TREZOR"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		wallet *Wallet
+		valid  bool
+	}{
+		{name: "without passphrase", wallet: &Wallet{Type: utils.BTCWalletAsset}, valid: true},
+		{name: "with passphrase", wallet: withPassphrase},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			shares, err := tc.wallet.SplitSeed(testBIP39Mnemonic, 2, 3)
+			if (err == nil) != tc.valid {
+				t.Fatalf("(%v), expected valid (%v), got (%v)", tc.name, tc.valid, err)
+			}
+			if err != nil {
+				return
+			}
+
+			expected, _ := DecodeSeedMnemonic(testBIP39Mnemonic, utils.BTCWalletAsset)
+			seed, err := DecodeSeedMnemonic(JoinSeedShares(shares[1:]), utils.BTCWalletAsset)
+			if err != nil || !bytes.Equal(seed, expected) {
+				t.Errorf("(%v), expected (%x), got (%x): %v", tc.name, expected, seed, err)
+			}
+		})
+	}
+
+	passphrase, err := withPassphrase.DecryptSeedPassphrase(privatePass)
+	if err != nil || passphrase != "TREZOR" {
+		t.Errorf("expected (TREZOR), got (%v): %v", passphrase, err)
+	}
+	if _, err := withPassphrase.DecryptSeedPassphrase("other pass"); err == nil {
+		t.Error("expected the seed passphrase not to decrypt with another passphrase")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/asdine/storm"
+	"github.com/crypto-power/cryptopower/libwallet/migration"
 	bolt "go.etcd.io/bbolt"
 )
 
@@ -17,11 +18,26 @@ const (
 	TxBucketName = "TxIndexInfo"
 	KeyDbVersion = "DbVersion"
 
-	// TxDbVersion is necessary to force re-indexing if changes are made to the structure of data being stored.
-	// Increment this version number if db structure changes such that client apps need to re-index.
+	// TxLabelsBucketName holds the labels of transactions that are not
+	// indexed yet, keyed by transaction hash.
+	TxLabelsBucketName = "TxLabels"
+
+	// TxDbVersion is the version of the structure of the data being stored.
+	// Increment this version number if the db structure changes and add a
+	// migration for the new version to txDBMigrations.
 	TxDbVersion uint32 = 3
+
+	// minMigratableTxDbVersion is the oldest db version that can be migrated
+	// to TxDbVersion. Older dbs are dropped and re-indexed. txDBMigrations
+	// must hold a migration for every version above it, Migrate fails
+	// otherwise.
+	minMigratableTxDbVersion uint32 = 3
 )
 
+// txDBMigrations upgrade wallet data dbs from minMigratableTxDbVersion to
+// TxDbVersion, one version at a time.
+var txDBMigrations = []migration.Migration{}
+
 type DB struct {
 	BTC            *BTCDB
 	LTC            *LTCDB
@@ -64,6 +80,23 @@ func Initialize(dbPath string, txData interface{}) (*DB, error) {
 	}, nil
 }
 
+// NeedsMigration returns true if the db version is older than TxDbVersion.
+// Migrate must be called before the db is used.
+func (db *DB) NeedsMigration() bool {
+	var currentDbVersion uint32
+	err := db.walletDataDB.Get(TxBucketName, KeyDbVersion, &currentDbVersion)
+	if err != nil {
+		return false
+	}
+	return currentDbVersion < TxDbVersion
+}
+
+// Migrate upgrades the db to TxDbVersion. The db file is backed up before the
+// first migration is applied.
+func (db *DB) Migrate(dbName string, listener migration.ProgressListener) error {
+	return migration.Run(db.walletDataDB, dbName, TxBucketName, KeyDbVersion, TxDbVersion, txDBMigrations, listener)
+}
+
 // SetTicketMaturity sets the ticket maturity value required when filterig txs.
 func (db *DB) SetTicketMaturity(val int32) *DB {
 	db.ticketMaturity = val
@@ -116,7 +149,9 @@ func openOrCreateDB(dbPath string) (*storm.DB, error) {
 }
 
 // ensureTxDatabaseVersion checks the version of the existing db against `TxDbVersion`.
-// If there's a difference, the current wallet data db file is deleted and a new one created.
+// Dbs that are too old to be migrated or newer than `TxDbVersion` have their
+// transactions deleted and the tx index reset. Other outdated dbs are left for
+// Migrate to upgrade.
 func ensureTxDatabaseVersion(walletDataDB *storm.DB, _ string, txData interface{}) (*storm.DB, error) {
 	var currentDbVersion uint32
 	err := walletDataDB.Get(TxBucketName, KeyDbVersion, &currentDbVersion)
@@ -125,7 +160,7 @@ func ensureTxDatabaseVersion(walletDataDB *storm.DB, _ string, txData interface{
 		return nil, fmt.Errorf("error checking wallet data database version: %s", err.Error())
 	}
 
-	if currentDbVersion != TxDbVersion {
+	if currentDbVersion < minMigratableTxDbVersion || currentDbVersion > TxDbVersion {
 		if err = walletDataDB.Drop(txData); err != nil {
 			return nil, fmt.Errorf("error deleting outdated wallet data database: %s", err.Error())
 		}
@@ -0,0 +1,24 @@
+package walletdata
+
+import (
+	"testing"
+)
+
+// TestTxDBMigrations checks that the registered migrations upgrade the oldest
+// migratable db to TxDbVersion.
+func TestTxDBMigrations(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.walletDataDB.Set(TxBucketName, KeyDbVersion, minMigratableTxDbVersion); err != nil {
+		t.Fatal(err)
+	}
+
+	if needsMigration := db.NeedsMigration(); needsMigration != (minMigratableTxDbVersion < TxDbVersion) {
+		t.Errorf("expected needs migration (%v), got (%v)", minMigratableTxDbVersion < TxDbVersion, needsMigration)
+	}
+	if err := db.Migrate("test", nil); err != nil {
+		t.Fatalf("expected no error, got (%v)", err)
+	}
+	if db.NeedsMigration() {
+		t.Errorf("expected the db at version (%v) after the migrations", TxDbVersion)
+	}
+}
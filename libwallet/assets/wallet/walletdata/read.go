@@ -3,6 +3,7 @@ package walletdata
 import (
 	"github.com/asdine/storm"
 	"github.com/asdine/storm/q"
+	bolt "go.etcd.io/bbolt"
 )
 
 const MaxReOrgBlocks = 6
@@ -83,3 +84,28 @@ func (db *DB) FindLast(fieldName string, value interface{}, txObj interface{}) e
 func (db *DB) FindAll(fieldName string, value interface{}, txObj interface{}) error {
 	return db.walletDataDB.Find(fieldName, value, txObj)
 }
+
+// PendingTxLabels returns the labels saved by SaveTxLabel for transactions
+// that are not indexed yet.
+func (db *DB) PendingTxLabels() (map[string]string, error) {
+	labels := make(map[string]string)
+	err := db.walletDataDB.Bolt.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(TxLabelsBucketName))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			if v == nil {
+				// Nested storm metadata bucket.
+				return nil
+			}
+			var label string
+			if err := db.walletDataDB.Codec().Unmarshal(v, &label); err != nil {
+				return err
+			}
+			labels[string(k)] = label
+			return nil
+		})
+	})
+	return labels, err
+}
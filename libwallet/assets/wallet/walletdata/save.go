@@ -6,6 +6,7 @@ import (
 
 	"decred.org/dcrwallet/v3/errors"
 	"github.com/asdine/storm"
+	"github.com/asdine/storm/q"
 )
 
 const KeyEndBlock = "EndBlock"
@@ -31,6 +32,14 @@ func (db *DB) SaveOrUpdate(emptyTxPointer, record interface{}) (overwritten bool
 		db.walletDataDB.DeleteStruct(emptyTxPointer)
 	}
 
+	if txlabel == "" && v.Elem().FieldByName("Label").String() == "" {
+		// Labels restored from a backup are kept aside until the
+		// transaction is indexed.
+		if db.walletDataDB.Get(TxLabelsBucketName, txHash, &txlabel) == nil {
+			db.walletDataDB.Delete(TxLabelsBucketName, txHash)
+		}
+	}
+
 	if txlabel != "" {
 		// Must be a transaction we are dealing with so update the Label field value.
 		// Persist the tx labels here since they are not sent via the network.
@@ -42,6 +51,20 @@ func (db *DB) SaveOrUpdate(emptyTxPointer, record interface{}) (overwritten bool
 	return
 }
 
+// SaveTxLabel sets the label of the transaction with the provided hash. The
+// label of a transaction that is not indexed yet is applied by SaveOrUpdate
+// once the transaction is indexed.
+func (db *DB) SaveTxLabel(emptyTxPointer interface{}, txHash, label string) error {
+	err := db.walletDataDB.One("Hash", txHash, emptyTxPointer)
+	if err == storm.ErrNotFound {
+		return db.walletDataDB.Set(TxLabelsBucketName, txHash, label)
+	}
+	if err != nil {
+		return errors.Errorf("error checking if record was already indexed: %s", err.Error())
+	}
+	return db.walletDataDB.UpdateField(emptyTxPointer, "Label", label)
+}
+
 func (db *DB) SaveOrUpdateVspdRecord(emptyTxPointer, record interface{}) (updated bool, err error) {
 	v := reflect.ValueOf(record)
 	txHash := reflect.Indirect(v).FieldByName("Hash").String()
@@ -60,6 +83,11 @@ func (db *DB) SaveOrUpdateVspdRecord(emptyTxPointer, record interface{}) (update
 	return
 }
 
+// Delete removes a saved record from the database.
+func (db *DB) Delete(record interface{}) error {
+	return db.walletDataDB.DeleteStruct(record)
+}
+
 func (db *DB) LastIndexPoint() (int32, error) {
 	var endBlockHeight int32
 	err := db.walletDataDB.Get(TxBucketName, KeyEndBlock, &endBlockHeight)
@@ -78,6 +106,32 @@ func (db *DB) SaveLastIndexPoint(endBlockHeight int32) error {
 	return nil
 }
 
+// RollbackToHeight sets the block height of the transactions mined above the
+// provided height to unminedHeight and moves the last index point back to the
+// height. It is used when the blocks above the height are detached by a reorg,
+// the transactions are indexed again once mined in the new chain. txs must be a
+// pointer to a slice of transaction pointers, it receives the rolled back txs.
+func (db *DB) RollbackToHeight(txs interface{}, height, unminedHeight int32) error {
+	err := db.walletDataDB.Select(q.Gt("BlockHeight", height)).Find(txs)
+	if err != nil && err != storm.ErrNotFound {
+		return err
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(txs))
+	for i := 0; i < v.Len(); i++ {
+		err = db.walletDataDB.UpdateField(v.Index(i).Interface(), "BlockHeight", unminedHeight)
+		if err != nil {
+			return errors.Errorf("error marking detached tx as unmined: %s", err.Error())
+		}
+	}
+
+	lastIndexed, err := db.LastIndexPoint()
+	if err != nil || lastIndexed <= height {
+		return err
+	}
+	return db.SaveLastIndexPoint(height)
+}
+
 func (db *DB) ClearSavedTransactions(emptyTxPointer interface{}) error {
 	err := db.walletDataDB.Drop(emptyTxPointer)
 	if err != nil {
@@ -0,0 +1,84 @@
+package walletdata
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// testTx holds the fields of the wallets' transactions used by the db.
+type testTx struct {
+	Hash        string `storm:"id,unique"`
+	Timestamp   int64  `storm:"index"`
+	BlockHeight int32  `storm:"index"`
+	Label       string
+}
+
+// newTestDB returns a wallet data db created in a temporary directory.
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := Initialize(filepath.Join(t.TempDir(), BTCDBName), &testTx{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestRollbackToHeight(t *testing.T) {
+	const unminedHeight = -1
+	tests := []struct {
+		name        string
+		lastIndexed int32
+		height      int32
+		expected    map[string]int32
+		endBlock    int32
+	}{
+		{
+			name:        "detached blocks",
+			lastIndexed: 12,
+			height:      10,
+			expected:    map[string]int32{"a": 9, "b": 10, "c": unminedHeight, "d": unminedHeight, "e": unminedHeight},
+			endBlock:    10,
+		},
+		{
+			name:        "index behind the fork",
+			lastIndexed: 8,
+			height:      11,
+			expected:    map[string]int32{"a": 9, "b": 10, "c": 11, "d": unminedHeight, "e": unminedHeight},
+			endBlock:    8,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			for hash, height := range map[string]int32{"a": 9, "b": 10, "c": 11, "d": 12, "e": unminedHeight} {
+				tx := &testTx{Hash: hash, Timestamp: 1, BlockHeight: height, Label: "label " + hash}
+				if _, err := db.SaveOrUpdate(&testTx{}, tx); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := db.SaveLastIndexPoint(tc.lastIndexed); err != nil {
+				t.Fatal(err)
+			}
+
+			var detached []*testTx
+			if err := db.RollbackToHeight(&detached, tc.height, unminedHeight); err != nil {
+				t.Fatalf("(%v), expected no error, got (%v)", tc.name, err)
+			}
+
+			for hash, height := range tc.expected {
+				tx := new(testTx)
+				if err := db.FindOne("Hash", hash, tx); err != nil {
+					t.Fatal(err)
+				}
+				if tx.BlockHeight != height || tx.Label != "label "+hash {
+					t.Errorf("(%v), expected tx (%v) at (%v), got (%+v)", tc.name, hash, height, tx)
+				}
+			}
+			if endBlock, _ := db.LastIndexPoint(); endBlock != tc.endBlock {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.endBlock, endBlock)
+			}
+		})
+	}
+}
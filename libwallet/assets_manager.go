@@ -6,11 +6,13 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"time"
 
 	"decred.org/dcrwallet/v3/errors"
 	"github.com/asdine/storm"
 	"github.com/asdine/storm/q"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
 	"github.com/crypto-power/cryptopower/libwallet/ext"
 	"github.com/crypto-power/cryptopower/libwallet/instantswap"
 	"github.com/crypto-power/cryptopower/libwallet/internal/politeia"
@@ -52,6 +54,13 @@ type AssetsManager struct {
 
 	db sharedW.AssetsManagerDB // Interface to manage db access at the ASM.
 
+	// openingWallets waits for the wallets opened in the background.
+	openingWallets sync.WaitGroup
+
+	// badWalletErrs holds the errors that caused wallets to be classified as
+	// bad wallets, keyed by wallet ID.
+	badWalletErrs map[int]error
+
 	shuttingDown chan bool
 	cancelFuncs  []context.CancelFunc
 	chainsParams utils.ChainsParams
@@ -60,6 +69,11 @@ type AssetsManager struct {
 	InstantSwap     *instantswap.InstantSwap
 	ExternalService *ext.Service
 	RateSource      ext.RateSource
+	SyncManager     *SyncManager
+
+	// events is the bus the wallets and the services publish their events on.
+	events *eventbus.Bus
+	opener *walletOpener
 }
 
 // initializeAssetsFields validate the network provided is valid for all assets before proceeding
@@ -83,16 +97,21 @@ func initializeAssetsFields(rootDir, dbDriver, logDir string, netType utils.Netw
 		return nil, errors.Errorf("error initializing LTC parameters: %s", err.Error())
 	}
 
+	events := eventbus.New()
 	params := &sharedW.InitParams{
 		DbDriver: dbDriver,
 		RootDir:  rootDir,
 		NetType:  netType,
 		LogDir:   logDir,
+		Events:   events,
 	}
 
 	mgr := &AssetsManager{
-		params: params,
-		Assets: new(Assets),
+		params:        params,
+		Assets:        new(Assets),
+		badWalletErrs: make(map[int]error),
+		events:        events,
+		opener:        newWalletOpener(events),
 	}
 
 	mgr.Assets.BTC.Wallets = make(map[int]sharedW.Asset)
@@ -152,21 +171,40 @@ func NewAssetsManager(rootDir, logDir, net string) (*AssetsManager, error) {
 		return nil, err
 	}
 
+	if err = migrateAssetsDB(mwDB); err != nil {
+		log.Errorf("Error migrating wallets database: %s", err.Error())
+		return nil, err
+	}
+
+	mgr.params.DB = mwDB
+
+	// The proxy must be in place before any network connection is made.
+	if err = mgr.loadProxy(); err != nil {
+		log.Errorf("Error loading the proxy config: %s", err.Error())
+		return nil, err
+	}
+	if err = mgr.loadDevMode(); err != nil {
+		log.Errorf("Error loading the developer mode config: %s", err.Error())
+		return nil, err
+	}
+
 	politeiaHost := PoliteiaMainnetHost
 	if netType == Testnet {
 		politeiaHost = PoliteiaTestnetHost
 	}
-	politeia, err := politeia.New(politeiaHost, mwDB)
+	// The host is left empty when developer mode has no stand-in for
+	// politeia, which keeps politeia requests from reaching mainnet.
+	politeiaHost, _ = utils.ServiceURL(utils.PoliteiaService, politeiaHost)
+	politeia, err := politeia.New(politeiaHost, mwDB, mgr.events)
 	if err != nil {
 		return nil, err
 	}
 
-	instantSwap, err := instantswap.NewInstantSwap(mwDB)
+	instantSwap, err := instantswap.NewInstantSwap(mwDB, mgr.events)
 	if err != nil {
 		return nil, err
 	}
 
-	mgr.params.DB = mwDB
 	mgr.Politeia = politeia
 	mgr.InstantSwap = instantSwap
 
@@ -184,6 +222,8 @@ func NewAssetsManager(rootDir, logDir, net string) (*AssetsManager, error) {
 
 	log.Infof("Loaded %d wallets", mgr.LoadedWalletsCount())
 
+	mgr.SyncManager = newSyncManager(mgr)
+
 	err = mgr.initRateSource()
 	if err != nil {
 		return nil, err
@@ -199,6 +239,12 @@ func NewAssetsManager(rootDir, logDir, net string) (*AssetsManager, error) {
 	return mgr, nil
 }
 
+// Events returns the bus the wallets and the services of the assets manager
+// publish their events on.
+func (mgr *AssetsManager) Events() *eventbus.Bus {
+	return mgr.events
+}
+
 // initRateSource initializes the user's rate source and starts a loop to
 // refresh the rates.
 func (mgr *AssetsManager) initRateSource() (err error) {
@@ -214,7 +260,7 @@ func (mgr *AssetsManager) initRateSource() (err error) {
 		disabled = mgr.IsPrivacyModeOn()
 	}
 
-	mgr.RateSource, err = ext.NewCommonRateSource(ctx, rateSource)
+	mgr.RateSource, err = ext.NewCommonRateSource(ctx, rateSource, mgr.events)
 	if err != nil {
 		return fmt.Errorf("ext.NewCommonRateSource error: %w", err)
 	}
@@ -272,49 +318,79 @@ func (mgr *AssetsManager) prepareExistingWallets() error {
 		return ok
 	}
 
-	// prepare the wallets loaded from db for use
-	for _, wallet := range wallets {
+	// Load the wallets concurrently so that a slow wallet does not hold back
+	// the others. The results are applied in the wallet ID order.
+	assets := make([]sharedW.Asset, len(wallets))
+	loadErrs := make([]error, len(wallets))
+	workers := make(chan struct{}, maxConcurrentWalletLoads)
+	var wg sync.WaitGroup
+	for i, wallet := range wallets {
 		// preset the network type so as to generate correct folder path
 		wallet.SetNetType(mgr.NetType())
 
 		path := filepath.Join(mgr.params.RootDir, wallet.DataDir())
 		log.Infof("loading properties of wallet=%v at location=%v", wallet.Name, path)
 
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(i int, wallet *sharedW.Wallet) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+
+			switch wallet.Type {
+			case utils.BTCWalletAsset:
+				assets[i], loadErrs[i] = btc.LoadExisting(wallet, mgr.params)
+			case utils.DCRWalletAsset:
+				assets[i], loadErrs[i] = dcr.LoadExisting(wallet, mgr.params)
+			case utils.LTCWalletAsset:
+				assets[i], loadErrs[i] = ltc.LoadExisting(wallet, mgr.params)
+			}
+		}(i, wallet)
+	}
+	wg.Wait()
+
+	// prepare the wallets loaded from db for use
+	for i, wallet := range wallets {
+		path := filepath.Join(mgr.params.RootDir, wallet.DataDir())
+		w, err := assets[i], loadErrs[i]
+
 		switch wallet.Type {
 		case utils.BTCWalletAsset:
-			w, err := btc.LoadExisting(wallet, mgr.params)
 			if err == nil && !isOK(w) {
 				err = fmt.Errorf("missing wallet database file: %v", path)
 				log.Warn(err)
 			}
 			if err != nil {
 				mgr.Assets.BTC.BadWallets[wallet.ID] = wallet
+				mgr.badWalletErrs[wallet.ID] = err
 				log.Warnf("Ignored btc wallet load error for wallet %d (%s)", wallet.ID, wallet.Name)
 			} else {
 				mgr.Assets.BTC.Wallets[wallet.ID] = w
 			}
 
 		case utils.DCRWalletAsset:
-			w, err := dcr.LoadExisting(wallet, mgr.params)
 			if err == nil && !isOK(w) {
 				err = fmt.Errorf("missing wallet database file: %v", path)
 				log.Debug(err)
 			}
 			if err != nil {
 				mgr.Assets.DCR.BadWallets[wallet.ID] = wallet
+				mgr.badWalletErrs[wallet.ID] = err
 				log.Warnf("Ignored dcr wallet load error for wallet %d (%s)", wallet.ID, wallet.Name)
 			} else {
 				mgr.Assets.DCR.Wallets[wallet.ID] = w
 			}
 
 		case utils.LTCWalletAsset:
-			w, err := ltc.LoadExisting(wallet, mgr.params)
 			if err == nil && !isOK(w) {
 				err = fmt.Errorf("missing wallet database file: %v", path)
 				log.Debug(err)
 			}
 			if err != nil {
 				mgr.Assets.LTC.BadWallets[wallet.ID] = wallet
+				mgr.badWalletErrs[wallet.ID] = err
 				log.Warnf("Ignored ltc wallet load error for wallet %d (%s)", wallet.ID, wallet.Name)
 			} else {
 				mgr.Assets.LTC.Wallets[wallet.ID] = w
@@ -323,6 +399,7 @@ func (mgr *AssetsManager) prepareExistingWallets() error {
 		default:
 			// Classify all wallets with missing AssetTypes as DCR badwallets.
 			mgr.Assets.DCR.BadWallets[wallet.ID] = wallet
+			mgr.badWalletErrs[wallet.ID] = utils.ErrAssetUnknown
 		}
 	}
 	return nil
@@ -356,6 +433,13 @@ func (mgr *AssetsManager) Shutdown() {
 		mgr.InstantSwap.StopSync()
 	}
 
+	// Stop scheduling syncs before the wallets syncs are canceled.
+	mgr.SyncManager.stop()
+
+	// Wait for the wallets being opened in the background, the others are
+	// left closed.
+	mgr.openingWallets.Wait()
+
 	for _, wallet := range mgr.AllWallets() {
 		wallet.Shutdown() // Cancels the wallet sync too.
 		wallet.CancelRescan()
@@ -398,7 +482,11 @@ func (mgr *AssetsManager) IsAssetManagerDB() bool {
 	return mgr.db != nil
 }
 
-// OpenWallets opens all wallets in the assets manager.
+// OpenWallets verifies the startup passphrase and opens the wallets in the
+// background, a few at a time. It returns without waiting for the wallets to
+// open; their progress is reported by WalletOpenStatus and the wallet open
+// listeners. Wallets set to open on demand are left closed until
+// EnsureWalletOpened is called for them.
 func (mgr *AssetsManager) OpenWallets(startupPassphrase string) error {
 	for _, wallet := range mgr.AllWallets() {
 		if wallet.IsSyncing() {
@@ -410,19 +498,25 @@ func (mgr *AssetsManager) OpenWallets(startupPassphrase string) error {
 		return err
 	}
 
+	var toOpen []sharedW.Asset
 	for _, wallet := range mgr.AllWallets() {
-		select {
-		case <-mgr.shuttingDown:
-			// If shutdown protocol is detected, exit immediately.
-			return nil
+		walletID := wallet.GetWalletID()
+		switch {
+		case wallet.WalletOpened():
+			mgr.opener.setState(walletID, WalletOpenOpened, nil)
+		case wallet.ReadBoolConfigValueForKey(sharedW.OpenOnDemandConfigKey, false):
+			mgr.opener.setState(walletID, WalletOpenDeferred, nil)
 		default:
-			err := wallet.OpenWallet()
-			if err != nil {
-				return err
-			}
+			mgr.opener.setState(walletID, WalletOpenPending, nil)
+			toOpen = append(toOpen, wallet)
 		}
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	mgr.cancelFuncs = append(mgr.cancelFuncs, cancel)
+	mgr.openingWallets.Add(1)
+	go openWalletsInBackground(ctx, &mgr.openingWallets, toOpen, mgr.openWallet)
+
 	return nil
 }
 
@@ -535,6 +629,7 @@ func (mgr *AssetsManager) DeleteWallet(walletID int, privPass string) error {
 	case utils.LTCWalletAsset:
 		delete(mgr.Assets.LTC.Wallets, walletID)
 	}
+	mgr.opener.forget(walletID)
 
 	return nil
 }
@@ -611,6 +706,7 @@ func (mgr *AssetsManager) DeleteBadWallet(walletID int) error {
 	case utils.LTCWalletAsset:
 		delete(mgr.Assets.LTC.BadWallets, walletID)
 	}
+	delete(mgr.badWalletErrs, walletID)
 
 	return nil
 }
@@ -631,30 +727,33 @@ func (mgr *AssetsManager) RootDirFileSizeInBytes(dataDir string) (int64, error)
 	return size, err
 }
 
-// WalletWithSeed returns the ID of the wallet with the given seed. If a wallet
-// with the given seed does not exist, it returns -1.
-func (mgr *AssetsManager) WalletWithSeed(walletType utils.AssetType, seedMnemonic string) (int, error) {
+// WalletWithSeed returns the ID of the wallet with the given seed and optional
+// seed passphrase. If a wallet with the given seed does not exist, it
+// returns -1.
+func (mgr *AssetsManager) WalletWithSeed(walletType utils.AssetType, seedMnemonic, seedPassphrase string) (int, error) {
 	switch walletType {
 	case utils.BTCWalletAsset:
-		return mgr.BTCWalletWithSeed(seedMnemonic)
+		return mgr.BTCWalletWithSeed(seedMnemonic, seedPassphrase)
 	case utils.DCRWalletAsset:
-		return mgr.DCRWalletWithSeed(seedMnemonic)
+		return mgr.DCRWalletWithSeed(seedMnemonic, seedPassphrase)
 	case utils.LTCWalletAsset:
-		return mgr.LTCWalletWithSeed(seedMnemonic)
+		return mgr.LTCWalletWithSeed(seedMnemonic, seedPassphrase)
 	default:
 		return -1, utils.ErrAssetUnknown
 	}
 }
 
-// RestoreWallet restores a wallet from the given seed.
-func (mgr *AssetsManager) RestoreWallet(walletType utils.AssetType, walletName, seedMnemonic, privatePassphrase string, privatePassphraseType int32) (sharedW.Asset, error) {
+// RestoreWallet restores a wallet from the given seed or SLIP-39 seed shares.
+// The seed passphrase is the optional passphrase of the seed shares and of the
+// BIP39 seeds of BTC and LTC wallets.
+func (mgr *AssetsManager) RestoreWallet(walletType utils.AssetType, walletName, seedMnemonic, seedPassphrase, privatePassphrase string, privatePassphraseType int32) (sharedW.Asset, error) {
 	switch walletType {
 	case utils.BTCWalletAsset:
-		return mgr.RestoreBTCWallet(walletName, seedMnemonic, privatePassphrase, privatePassphraseType)
+		return mgr.RestoreBTCWallet(walletName, seedMnemonic, seedPassphrase, privatePassphrase, privatePassphraseType)
 	case utils.DCRWalletAsset:
-		return mgr.RestoreDCRWallet(walletName, seedMnemonic, privatePassphrase, privatePassphraseType)
+		return mgr.RestoreDCRWallet(walletName, seedMnemonic, seedPassphrase, privatePassphrase, privatePassphraseType)
 	case utils.LTCWalletAsset:
-		return mgr.RestoreLTCWallet(walletName, seedMnemonic, privatePassphrase, privatePassphraseType)
+		return mgr.RestoreLTCWallet(walletName, seedMnemonic, seedPassphrase, privatePassphrase, privatePassphraseType)
 	default:
 		return nil, utils.ErrAssetUnknown
 	}
@@ -744,6 +843,10 @@ func (mgr *AssetsManager) AllAssetTypes() []utils.AssetType {
 // BlockExplorerURLForTx returns a URL for viewing a transaction on the block
 // explorer of the specified asset.
 func (mgr *AssetsManager) BlockExplorerURLForTx(assetType utils.AssetType, txHash string) string {
+	if utils.IsDevMode() {
+		return devBlockExplorerURLForTx(assetType, txHash)
+	}
+
 	var isMainnet bool
 	switch mgr.NetType() {
 	case utils.Mainnet:
@@ -777,6 +880,29 @@ func (mgr *AssetsManager) BlockExplorerURLForTx(assetType utils.AssetType, txHas
 	return ""
 }
 
+// devBlockExplorerURLForTx returns the URL of the transaction on the local
+// block explorer of the asset or an empty string if developer mode has no
+// stand-in for it.
+func devBlockExplorerURLForTx(assetType utils.AssetType, txHash string) string {
+	var service utils.DevService
+	switch assetType {
+	case utils.DCRWalletAsset:
+		service = utils.DCRExplorerService
+	case utils.BTCWalletAsset:
+		service = utils.BTCExplorerService
+	case utils.LTCWalletAsset:
+		service = utils.LTCExplorerService
+	default:
+		return ""
+	}
+
+	txURL, err := utils.ServiceURL(service, "/tx/"+txHash)
+	if err != nil {
+		return ""
+	}
+	return txURL
+}
+
 func (mgr *AssetsManager) LogFile() string {
 	return filepath.Join(mgr.params.LogDir, LogFilename)
 }
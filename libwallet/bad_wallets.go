@@ -0,0 +1,532 @@
+package libwallet
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"decred.org/dcrwallet/v3/errors"
+	"decred.org/dcrwallet/v3/wallet/udb"
+	"decred.org/dcrwallet/v3/wallet/walletdb"
+	btcwaddrmgr "github.com/btcsuite/btcwallet/waddrmgr"
+	btcwallet "github.com/btcsuite/btcwallet/wallet"
+	ltcwaddrmgr "github.com/ltcsuite/ltcwallet/waddrmgr"
+	ltcwallet "github.com/ltcsuite/ltcwallet/wallet"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/crypto-power/cryptopower/libwallet/assets/btc"
+	"github.com/crypto-power/cryptopower/libwallet/assets/dcr"
+	"github.com/crypto-power/cryptopower/libwallet/assets/ltc"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/assets/wallet/walletdata"
+	dcrloader "github.com/crypto-power/cryptopower/libwallet/internal/loader/dcr"
+	"github.com/crypto-power/cryptopower/libwallet/migration"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+)
+
+// BadWalletIssue classifies why a wallet failed to load.
+type BadWalletIssue string
+
+const (
+	// BadWalletIssueNone means no problem was found with the wallet files.
+	BadWalletIssueNone BadWalletIssue = "none"
+	// BadWalletIssueUnknownAsset means the wallet's asset type is not
+	// supported.
+	BadWalletIssueUnknownAsset BadWalletIssue = "unknown_asset"
+	// BadWalletIssueMissingDB means the wallet database does not exist.
+	BadWalletIssueMissingDB BadWalletIssue = "missing_db"
+	// BadWalletIssueCorruptDB means the wallet database cannot be read.
+	BadWalletIssueCorruptDB BadWalletIssue = "corrupt_db"
+	// BadWalletIssueWrongDriver means the wallet database was written by a
+	// driver the asset does not support.
+	BadWalletIssueWrongDriver BadWalletIssue = "wrong_driver"
+	// BadWalletIssueVersionMismatch means the wallet database was written by
+	// a newer version of the wallet software.
+	BadWalletIssueVersionMismatch BadWalletIssue = "version_mismatch"
+	// BadWalletIssueLockedDB means the wallet database is locked by another
+	// process.
+	BadWalletIssueLockedDB BadWalletIssue = "locked_db"
+	// BadWalletIssueWalletData means the wallet data db that indexes the
+	// wallet's transactions cannot be opened.
+	BadWalletIssueWalletData BadWalletIssue = "wallet_data"
+)
+
+// BadWalletRepair is a fix for a bad wallet. Files that are replaced by a fix
+// are kept next to the original with a .bak suffix.
+type BadWalletRepair string
+
+const (
+	// BadWalletRepairNone applies no fix, the wallet is only loaded again.
+	BadWalletRepairNone BadWalletRepair = ""
+	// BadWalletRepairClearStaleLock removes the lock file left behind by a
+	// process that no longer runs.
+	BadWalletRepairClearStaleLock BadWalletRepair = "clear_stale_lock"
+	// BadWalletRepairRebuildWalletData moves the wallet data db aside so that
+	// it is recreated and the wallet's transactions are indexed again.
+	BadWalletRepairRebuildWalletData BadWalletRepair = "rebuild_wallet_data"
+	// BadWalletRepairRecreateWatchOnly moves the wallet database aside and
+	// recreates the watch only wallet from its saved account key.
+	BadWalletRepairRecreateWatchOnly BadWalletRepair = "recreate_watch_only"
+)
+
+// RequiresConfirmation returns true if the fix replaces the wallet with one
+// that may not hold all of its accounts and spending keys, so the user must
+// confirm it.
+func (repair BadWalletRepair) RequiresConfirmation() bool {
+	return repair == BadWalletRepairRecreateWatchOnly
+}
+
+// dbProbeTimeout is how long the diagnosis waits for a database lock.
+const dbProbeTimeout = 2 * time.Second
+
+// BadWalletReport describes why a bad wallet failed to load and the fixes
+// that can be tried before the wallet is deleted.
+type BadWalletReport struct {
+	WalletID   int
+	WalletName string
+	AssetType  utils.AssetType
+	Issue      BadWalletIssue
+	// Details describes the problem found by the diagnosis.
+	Details string
+	// LoadError is the error returned when the wallet was loaded.
+	LoadError string
+	// Repairs lists the fixes that can be applied by RepairBadWallet.
+	Repairs []BadWalletRepair
+	// Applied lists the fix applied by RepairBadWallet.
+	Applied []BadWalletRepair
+	// Repaired is set when the wallet was loaded after the fixes were
+	// applied.
+	Repaired bool
+}
+
+// DiagnoseBadWallet checks the files of the bad wallet with the provided ID
+// and reports why the wallet failed to load. Nothing is modified.
+func (mgr *AssetsManager) DiagnoseBadWallet(walletID int) (*BadWalletReport, error) {
+	wallet := mgr.getbadWallet(walletID)
+	if wallet == nil {
+		return nil, errors.New(utils.ErrNotExist)
+	}
+	return mgr.diagnoseBadWallet(wallet), nil
+}
+
+// RepairBadWallet applies the provided fix, one of those suggested by the
+// diagnosis of the bad wallet with the provided ID, then loads and opens the
+// wallet again. BadWalletRepairNone only loads the wallet again. Fixes that
+// require confirmation are only applied if confirmed is set. If the wallet
+// loads, it is moved to the valid wallets. The returned report describes the
+// fix applied and any problem that remains.
+func (mgr *AssetsManager) RepairBadWallet(walletID int, repair BadWalletRepair, confirmed bool) (*BadWalletReport, error) {
+	const op errors.Op = "mgr.RepairBadWallet"
+
+	wallet := mgr.getbadWallet(walletID)
+	if wallet == nil {
+		return nil, errors.E(op, errors.New(utils.ErrNotExist))
+	}
+
+	report := mgr.diagnoseBadWallet(wallet)
+	if report.Issue == BadWalletIssueUnknownAsset {
+		return report, nil
+	}
+
+	if repair != BadWalletRepairNone {
+		if !report.suggests(repair) {
+			return nil, errors.E(op, utils.ErrInvalid, fmt.Sprintf("%s is not a suggested fix for the wallet", repair))
+		}
+		if repair.RequiresConfirmation() && !confirmed {
+			return nil, errors.E(op, utils.ErrInvalid, fmt.Sprintf("%s must be confirmed", repair))
+		}
+
+		log.Infof("[%d] applying bad wallet repair %s", walletID, repair)
+		if err := mgr.applyRepair(wallet, repair); err != nil {
+			report.Details = fmt.Sprintf("%s failed: %v", repair, err)
+			return report, nil
+		}
+		report.Applied = append(report.Applied, repair)
+	}
+
+	// The databases the bad wallet holds open must be released before the
+	// wallet is loaded again.
+	wallet.CloseDatabases()
+
+	asset, err := mgr.loadBadWallet(wallet)
+	if err != nil {
+		mgr.badWalletErrs[walletID] = err
+		applied := report.Applied
+		report = mgr.diagnoseBadWallet(wallet)
+		report.Applied = applied
+		return report, nil
+	}
+
+	switch wallet.Type {
+	case utils.BTCWalletAsset:
+		delete(mgr.Assets.BTC.BadWallets, walletID)
+		mgr.Assets.BTC.Wallets[walletID] = asset
+	case utils.DCRWalletAsset:
+		delete(mgr.Assets.DCR.BadWallets, walletID)
+		mgr.Assets.DCR.Wallets[walletID] = asset
+	case utils.LTCWalletAsset:
+		delete(mgr.Assets.LTC.BadWallets, walletID)
+		mgr.Assets.LTC.Wallets[walletID] = asset
+	}
+	delete(mgr.badWalletErrs, walletID)
+	mgr.opener.setState(walletID, WalletOpenOpened, nil)
+
+	report.Issue = BadWalletIssueNone
+	report.Details = ""
+	report.Repaired = true
+	return report, nil
+}
+
+// suggests returns true if the provided fix is one of the report's repairs.
+func (report *BadWalletReport) suggests(repair BadWalletRepair) bool {
+	for _, r := range report.Repairs {
+		if r == repair {
+			return true
+		}
+	}
+	return false
+}
+
+// loadBadWallet loads and opens the wallet like prepareExistingWallets and
+// OpenWallets do.
+func (mgr *AssetsManager) loadBadWallet(wallet *sharedW.Wallet) (sharedW.Asset, error) {
+	var asset sharedW.Asset
+	var err error
+	switch wallet.Type {
+	case utils.BTCWalletAsset:
+		asset, err = btc.LoadExisting(wallet, mgr.params)
+	case utils.DCRWalletAsset:
+		asset, err = dcr.LoadExisting(wallet, mgr.params)
+	case utils.LTCWalletAsset:
+		asset, err = ltc.LoadExisting(wallet, mgr.params)
+	default:
+		return nil, utils.ErrAssetUnknown
+	}
+	if err != nil {
+		wallet.CloseDatabases()
+		return nil, err
+	}
+
+	if err = openLoadedWallet(asset, nil); err != nil {
+		// Shutdown releases everything the loaded wallet holds open.
+		asset.Shutdown()
+		wallet.CloseDatabases()
+		return nil, err
+	}
+
+	if mgr.db == nil {
+		mgr.setDBInterface(asset.(sharedW.AssetsManagerDB))
+	}
+	return asset, nil
+}
+
+func openLoadedWallet(asset sharedW.Asset, listener migration.ProgressListener) error {
+	exists, err := asset.(interface{ WalletExists() (bool, error) }).WalletExists()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("missing wallet database file: %v", asset.DataDir())
+	}
+
+	if err = asset.MigrateWalletData(listener); err != nil {
+		return err
+	}
+	return asset.OpenWallet()
+}
+
+func (mgr *AssetsManager) diagnoseBadWallet(wallet *sharedW.Wallet) *BadWalletReport {
+	report := &BadWalletReport{
+		WalletID:   wallet.ID,
+		WalletName: wallet.Name,
+		AssetType:  wallet.Type,
+		Issue:      BadWalletIssueNone,
+	}
+	if err := mgr.badWalletErrs[wallet.ID]; err != nil {
+		report.LoadError = err.Error()
+	}
+
+	dbName := walletDbName(wallet.Type)
+	if dbName == "" {
+		report.Issue = BadWalletIssueUnknownAsset
+		report.Details = fmt.Sprintf("unsupported asset type %q", wallet.Type)
+		return report
+	}
+
+	dbPath := filepath.Join(wallet.DataDir(), dbName)
+	canRecreate := canRecreateWatchOnly(wallet)
+
+	info, err := os.Stat(dbPath)
+	switch {
+	case os.IsNotExist(err):
+		report.Issue = BadWalletIssueMissingDB
+		report.Details = fmt.Sprintf("%s does not exist", dbPath)
+		if canRecreate {
+			report.Repairs = append(report.Repairs, BadWalletRepairRecreateWatchOnly)
+		}
+		return report
+
+	case err != nil:
+		report.Issue = BadWalletIssueCorruptDB
+		report.Details = err.Error()
+		return report
+
+	case info.IsDir() && wallet.Type != utils.DCRWalletAsset:
+		report.Issue = BadWalletIssueWrongDriver
+		report.Details = fmt.Sprintf("%s wallets only support the %s driver", wallet.Type, dcrloader.BdbDriver)
+		return report
+	}
+
+	if info.IsDir() {
+		report.Issue, err = probeBadgerDB(dbPath)
+		if report.Issue == BadWalletIssueLockedDB && staleLockPID(dbPath) > 0 {
+			report.Repairs = append(report.Repairs, BadWalletRepairClearStaleLock)
+		}
+	} else {
+		report.Issue, err = probeBoltDB(dbPath, wallet.Type)
+	}
+	if err != nil {
+		report.Details = err.Error()
+	}
+	if report.Issue == BadWalletIssueCorruptDB && canRecreate {
+		report.Repairs = append(report.Repairs, BadWalletRepairRecreateWatchOnly)
+	}
+	if report.Issue != BadWalletIssueNone {
+		return report
+	}
+
+	// The wallet data db is opened when the wallet is loaded. It remains
+	// open if it was loaded successfully.
+	if wallet.GetWalletDataDb() == nil {
+		walletDataPath := filepath.Join(wallet.DataDir(), walletDataDbName(wallet.Type))
+		if _, err = os.Stat(walletDataPath); err == nil {
+			report.Issue, err = probeBoltDB(walletDataPath, "")
+			if report.Issue != BadWalletIssueNone {
+				report.Details = fmt.Sprintf("wallet data db: %v", err)
+				if report.Issue != BadWalletIssueLockedDB {
+					report.Issue = BadWalletIssueWalletData
+					report.Repairs = append(report.Repairs, BadWalletRepairRebuildWalletData)
+				}
+			}
+		}
+	}
+	return report
+}
+
+func (mgr *AssetsManager) applyRepair(wallet *sharedW.Wallet, repair BadWalletRepair) error {
+	switch repair {
+	case BadWalletRepairClearStaleLock:
+		dbPath := filepath.Join(wallet.DataDir(), walletDbName(wallet.Type))
+		return os.Remove(filepath.Join(dbPath, badgerLockFile))
+
+	case BadWalletRepairRebuildWalletData:
+		if db := wallet.GetWalletDataDb(); db != nil {
+			return nil
+		}
+		walletDataPath := filepath.Join(wallet.DataDir(), walletDataDbName(wallet.Type))
+		return moveAside(walletDataPath)
+
+	case BadWalletRepairRecreateWatchOnly:
+		dbPath := filepath.Join(wallet.DataDir(), walletDbName(wallet.Type))
+		if _, err := os.Stat(dbPath); err == nil {
+			if err = moveAside(dbPath); err != nil {
+				return err
+			}
+		}
+		if err := os.MkdirAll(wallet.DataDir(), utils.UserFilePerm); err != nil {
+			return err
+		}
+		if err := wallet.RecreateWatchOnlyWallet(); err != nil {
+			return err
+		}
+		// Addresses must be discovered again by the next sync.
+		wallet.IsRestored = true
+		wallet.HasDiscoveredAccounts = false
+		return mgr.params.DB.Save(wallet)
+
+	default:
+		return fmt.Errorf("unknown repair %q", repair)
+	}
+}
+
+// canRecreateWatchOnly returns true if the wallet is a single key watch only
+// wallet whose account key was saved when it was created.
+func canRecreateWatchOnly(wallet *sharedW.Wallet) bool {
+	if wallet.ReadStringConfigValueForKey(sharedW.WatchOnlyKeyConfigKey, "") == "" {
+		return false
+	}
+	desc := wallet.ReadStringConfigValueForKey(sharedW.WatchOnlyDescriptorConfigKey, "")
+	if desc == "" {
+		return true
+	}
+	parsed, err := sharedW.ParseDescriptor(desc)
+	return err == nil && !parsed.IsMultisig()
+}
+
+func walletDbName(assetType utils.AssetType) string {
+	switch assetType {
+	case utils.BTCWalletAsset:
+		return btcwallet.WalletDBName
+	case utils.DCRWalletAsset:
+		return dcrloader.WalletDbName
+	case utils.LTCWalletAsset:
+		return ltcwallet.WalletDBName
+	default:
+		return ""
+	}
+}
+
+func walletDataDbName(assetType utils.AssetType) string {
+	switch assetType {
+	case utils.BTCWalletAsset:
+		return walletdata.BTCDBName
+	case utils.DCRWalletAsset:
+		return walletdata.DCRDbName
+	case utils.LTCWalletAsset:
+		return walletdata.LTCDBName
+	default:
+		return ""
+	}
+}
+
+// moveAside renames the file or directory at path so that a new one can be
+// created in its place without losing the old one.
+func moveAside(path string) error {
+	return os.Rename(path, fmt.Sprintf("%s.%d.bak", path, time.Now().Unix()))
+}
+
+// probeBoltDB opens the bolt database at dbPath read only and checks its
+// consistency. If assetType is set, the wallet version saved in the database
+// is checked too.
+func probeBoltDB(dbPath string, assetType utils.AssetType) (BadWalletIssue, error) {
+	db, err := bolt.Open(dbPath, 0o600, &bolt.Options{Timeout: dbProbeTimeout, ReadOnly: true})
+	switch {
+	case err == bolt.ErrTimeout:
+		return BadWalletIssueLockedDB, errors.New("the database is in use by another process")
+	case err == bolt.ErrVersionMismatch:
+		return BadWalletIssueVersionMismatch, err
+	case err != nil:
+		return BadWalletIssueCorruptDB, err
+	}
+	defer db.Close()
+
+	err = db.View(func(tx *bolt.Tx) error {
+		for err := range tx.Check() {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return BadWalletIssueCorruptDB, err
+	}
+
+	var version, latestVersion uint32
+	err = db.View(func(tx *bolt.Tx) error {
+		switch assetType {
+		case utils.DCRWalletAsset:
+			version, err = boltVersion(tx, binary.BigEndian, "meta", "ver")
+			latestVersion = udb.DBVersion
+		case utils.BTCWalletAsset:
+			version, err = boltVersion(tx, binary.LittleEndian, "waddrmgr", "main", "mgrver")
+			latestVersion = btcwaddrmgr.LatestMgrVersion
+		case utils.LTCWalletAsset:
+			version, err = boltVersion(tx, binary.LittleEndian, "waddrmgr", "main", "mgrver")
+			latestVersion = ltcwaddrmgr.LatestMgrVersion
+		}
+		return err
+	})
+	if err != nil {
+		return BadWalletIssueCorruptDB, err
+	}
+	if version > latestVersion {
+		return BadWalletIssueVersionMismatch, fmt.Errorf("wallet database version %d is newer than the supported version %d",
+			version, latestVersion)
+	}
+	return BadWalletIssueNone, nil
+}
+
+// boltVersion reads the version saved at the provided bucket path and key.
+func boltVersion(tx *bolt.Tx, order binary.ByteOrder, path ...string) (uint32, error) {
+	bucket := tx.Bucket([]byte(path[0]))
+	for _, name := range path[1 : len(path)-1] {
+		if bucket == nil {
+			break
+		}
+		bucket = bucket.Bucket([]byte(name))
+	}
+	if bucket == nil {
+		return 0, fmt.Errorf("missing %s bucket", strings.Join(path[:len(path)-1], "/"))
+	}
+
+	value := bucket.Get([]byte(path[len(path)-1]))
+	if len(value) != 4 {
+		return 0, fmt.Errorf("invalid wallet version of length %d", len(value))
+	}
+	return order.Uint32(value), nil
+}
+
+// badgerLockFile is the file in which badger records the ID of the process
+// holding the database lock.
+const badgerLockFile = "LOCK"
+
+// probeBadgerDB opens the badger wallet database at dbPath and checks the
+// wallet version saved in it.
+func probeBadgerDB(dbPath string) (BadWalletIssue, error) {
+	db, err := walletdb.Open(dcrloader.BadgerDriver, dbPath)
+	if err != nil {
+		if strings.Contains(err.Error(), "directory lock") {
+			return BadWalletIssueLockedDB, errors.New("the database is in use by another process")
+		}
+		return BadWalletIssueCorruptDB, err
+	}
+	defer db.Close()
+
+	var version uint32
+	err = walletdb.View(context.Background(), db, func(tx walletdb.ReadTx) error {
+		meta := tx.ReadBucket([]byte("meta"))
+		if meta == nil {
+			return errors.New("missing meta bucket")
+		}
+		value := meta.Get([]byte("ver"))
+		if len(value) != 4 {
+			return fmt.Errorf("invalid wallet version of length %d", len(value))
+		}
+		version = binary.BigEndian.Uint32(value)
+		return nil
+	})
+	if err != nil {
+		return BadWalletIssueCorruptDB, err
+	}
+	if version > udb.DBVersion {
+		return BadWalletIssueVersionMismatch, fmt.Errorf("wallet database version %d is newer than the supported version %d",
+			version, udb.DBVersion)
+	}
+	return BadWalletIssueNone, nil
+}
+
+// staleLockPID returns the ID of the process recorded in the lock file of the
+// badger database at dbPath if that process is no longer running, otherwise
+// 0. It is only called once opening the database failed with a lock error.
+// Badger does not record the process ID on windows, where the lock file is
+// deleted when the process holding it exits.
+func staleLockPID(dbPath string) int {
+	content, err := os.ReadFile(filepath.Join(dbPath, badgerLockFile))
+	if err != nil {
+		return 0
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil || pid <= 0 || pid == os.Getpid() {
+		return 0
+	}
+
+	if processRunning(pid) {
+		return 0
+	}
+	return pid
+}
@@ -0,0 +1,224 @@
+package libwallet
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"decred.org/dcrwallet/v3/wallet/udb"
+	"decred.org/dcrwallet/v3/wallet/walletdb"
+	btcwaddrmgr "github.com/btcsuite/btcwallet/waddrmgr"
+	bolt "go.etcd.io/bbolt"
+
+	dcrloader "github.com/crypto-power/cryptopower/libwallet/internal/loader/dcr"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+)
+
+// newTestBoltDB creates a bolt database holding the value at the provided
+// bucket path and key. No value is written if path is empty.
+func newTestBoltDB(t *testing.T, value []byte, path ...string) string {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "wallet.db")
+	db, err := bolt.Open(dbPath, 0o600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if len(path) == 0 {
+		return dbPath
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(path[0]))
+		for _, name := range path[1 : len(path)-1] {
+			if err != nil {
+				return err
+			}
+			bucket, err = bucket.CreateBucketIfNotExists([]byte(name))
+		}
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(path[len(path)-1]), value)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dbPath
+}
+
+func version(order binary.ByteOrder, v uint32) []byte {
+	b := make([]byte, 4)
+	order.PutUint32(b, v)
+	return b
+}
+
+func TestProbeBoltDB(t *testing.T) {
+	btcPath := []string{"waddrmgr", "main", "mgrver"}
+	garbage := filepath.Join(t.TempDir(), "garbage.db")
+	if err := os.WriteFile(garbage, []byte("not a bolt database"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		dbPath    string
+		assetType utils.AssetType
+		expected  BadWalletIssue
+	}{
+		{
+			name:      "btc wallet",
+			dbPath:    newTestBoltDB(t, version(binary.LittleEndian, btcwaddrmgr.LatestMgrVersion), btcPath...),
+			assetType: utils.BTCWalletAsset,
+			expected:  BadWalletIssueNone,
+		},
+		{
+			name:      "newer btc wallet",
+			dbPath:    newTestBoltDB(t, version(binary.LittleEndian, btcwaddrmgr.LatestMgrVersion+1), btcPath...),
+			assetType: utils.BTCWalletAsset,
+			expected:  BadWalletIssueVersionMismatch,
+		},
+		{
+			name:      "dcr wallet",
+			dbPath:    newTestBoltDB(t, version(binary.BigEndian, udb.DBVersion), "meta", "ver"),
+			assetType: utils.DCRWalletAsset,
+			expected:  BadWalletIssueNone,
+		},
+		{
+			name:      "invalid version",
+			dbPath:    newTestBoltDB(t, []byte{1}, "meta", "ver"),
+			assetType: utils.DCRWalletAsset,
+			expected:  BadWalletIssueCorruptDB,
+		},
+		{
+			name:      "missing version bucket",
+			dbPath:    newTestBoltDB(t, nil),
+			assetType: utils.LTCWalletAsset,
+			expected:  BadWalletIssueCorruptDB,
+		},
+		{name: "wallet data db", dbPath: newTestBoltDB(t, nil), expected: BadWalletIssueNone},
+		{name: "not a bolt database", dbPath: garbage, assetType: utils.BTCWalletAsset, expected: BadWalletIssueCorruptDB},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			issue, err := probeBoltDB(tc.dbPath, tc.assetType)
+			if issue != tc.expected {
+				t.Errorf("(%v), expected (%v), got (%v): %v", tc.name, tc.expected, issue, err)
+			}
+		})
+	}
+
+	// A database opened for writing by another process is locked.
+	locked := newTestBoltDB(t, nil)
+	db, err := bolt.Open(locked, 0o600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if issue, err := probeBoltDB(locked, ""); issue != BadWalletIssueLockedDB {
+		t.Errorf("(locked), expected (%v), got (%v): %v", BadWalletIssueLockedDB, issue, err)
+	}
+}
+
+func TestProbeBadgerDB(t *testing.T) {
+	newBadgerDB := func(value []byte) string {
+		dbPath := filepath.Join(t.TempDir(), "wallet.db")
+		db, err := walletdb.Create(dcrloader.BadgerDriver, dbPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+
+		if value == nil {
+			return dbPath
+		}
+		err = walletdb.Update(context.Background(), db, func(tx walletdb.ReadWriteTx) error {
+			meta, err := tx.CreateTopLevelBucket([]byte("meta"))
+			if err != nil {
+				return err
+			}
+			return meta.Put([]byte("ver"), value)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dbPath
+	}
+
+	tests := []struct {
+		name     string
+		dbPath   string
+		expected BadWalletIssue
+	}{
+		{name: "dcr wallet", dbPath: newBadgerDB(version(binary.BigEndian, udb.DBVersion)), expected: BadWalletIssueNone},
+		{name: "newer dcr wallet", dbPath: newBadgerDB(version(binary.BigEndian, udb.DBVersion+1)), expected: BadWalletIssueVersionMismatch},
+		{name: "missing meta bucket", dbPath: newBadgerDB(nil), expected: BadWalletIssueCorruptDB},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			issue, err := probeBadgerDB(tc.dbPath)
+			if issue != tc.expected {
+				t.Errorf("(%v), expected (%v), got (%v): %v", tc.name, tc.expected, issue, err)
+			}
+		})
+	}
+}
+
+func TestStaleLockPID(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected int
+	}{
+		{name: "this process", content: strconv.Itoa(os.Getpid())},
+		{name: "invalid pid", content: "not a pid"},
+		{name: "no lock file"},
+		{name: "exited process", content: "4194304\n", expected: 4194304},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dbPath := t.TempDir()
+			if tc.content != "" {
+				if err := os.WriteFile(filepath.Join(dbPath, badgerLockFile), []byte(tc.content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := staleLockPID(dbPath); got != tc.expected {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestBadWalletRepairSelection(t *testing.T) {
+	report := &BadWalletReport{Repairs: []BadWalletRepair{BadWalletRepairRebuildWalletData, BadWalletRepairRecreateWatchOnly}}
+
+	tests := []struct {
+		name         string
+		repair       BadWalletRepair
+		suggested    bool
+		confirmation bool
+	}{
+		{name: "reload", repair: BadWalletRepairNone},
+		{name: "rebuild wallet data", repair: BadWalletRepairRebuildWalletData, suggested: true},
+		{name: "recreate watch only", repair: BadWalletRepairRecreateWatchOnly, suggested: true, confirmation: true},
+		{name: "not suggested", repair: BadWalletRepairClearStaleLock},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := report.suggests(tc.repair); got != tc.suggested {
+				t.Errorf("(%v), expected suggested (%v), got (%v)", tc.name, tc.suggested, got)
+			}
+			if got := tc.repair.RequiresConfirmation(); got != tc.confirmation {
+				t.Errorf("(%v), expected confirmation (%v), got (%v)", tc.name, tc.confirmation, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package badgerdb
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"decred.org/dcrwallet/v3/errors"
+	"github.com/dgraph-io/badger"
+)
+
+const (
+	// valueLogGCInterval is how often the value log garbage collection runs
+	// while a database is open.
+	valueLogGCInterval = 10 * time.Minute
+
+	// valueLogGCDiscardRatio is the fraction of a value log file that must be
+	// discardable for the file to be rewritten.
+	valueLogGCDiscardRatio = 0.5
+)
+
+var (
+	openDBsMtx sync.Mutex
+	// openDBs holds the open databases keyed by their cleaned path.
+	openDBs = make(map[string]*db)
+)
+
+func registerDB(dbPath string, d *db) {
+	openDBsMtx.Lock()
+	openDBs[filepath.Clean(dbPath)] = d
+	openDBsMtx.Unlock()
+}
+
+func unregisterDB(d *db) {
+	openDBsMtx.Lock()
+	defer openDBsMtx.Unlock()
+	for path, openDB := range openDBs {
+		if openDB == d {
+			delete(openDBs, path)
+		}
+	}
+}
+
+// runValueLogGC periodically reclaims the space of the value log entries that
+// were deleted or overwritten until quit is closed.
+func (db *db) runValueLogGC() {
+	defer db.gcWG.Done()
+	ticker := time.NewTicker(valueLogGCInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-db.quit:
+			return
+		case <-ticker.C:
+			// Each successful run rewrites a single file, keep going until
+			// there is nothing left to rewrite.
+			for db.DB.RunValueLogGC(valueLogGCDiscardRatio) == nil {
+			}
+		}
+	}
+}
+
+// compact flattens the LSM tree into a single level and rewrites every value
+// log file with discardable entries.
+func (db *db) compact() error {
+	db.closeMtx.RLock()
+	defer db.closeMtx.RUnlock()
+
+	if db.isClosed() {
+		return errors.E(errors.Invalid, "database is closed")
+	}
+
+	if err := db.DB.Flatten(1); err != nil {
+		return convertErr(err)
+	}
+
+	for {
+		err := db.DB.RunValueLogGC(valueLogGCDiscardRatio)
+		if err == badger.ErrNoRewrite {
+			return nil
+		}
+		if err != nil {
+			return convertErr(err)
+		}
+	}
+}
+
+// Compact compacts the open badger database at dbPath while it remains in use.
+// An error with code NotExist is returned if no badger database is open at
+// dbPath.
+func Compact(dbPath string) error {
+	openDBsMtx.Lock()
+	d, ok := openDBs[filepath.Clean(dbPath)]
+	openDBsMtx.Unlock()
+	if !ok {
+		return errors.E(errors.NotExist, "no open badger database at "+dbPath)
+	}
+	return d.compact()
+}
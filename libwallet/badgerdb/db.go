@@ -9,11 +9,15 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 
 	"decred.org/dcrwallet/v3/errors"
 	"decred.org/dcrwallet/v3/wallet/walletdb"
 	"github.com/dgraph-io/badger"
 	"github.com/dgraph-io/badger/options"
+	bolt "go.etcd.io/bbolt"
 )
 
 // convertErr wraps a driver-specific error with an error code.
@@ -47,14 +51,14 @@ type transaction struct {
 }
 
 func (tx *transaction) ReadBucket(key []byte) walletdb.ReadBucket {
-	if tx.db.closed {
+	if tx.db.isClosed() {
 		return nil
 	}
 	return tx.ReadWriteBucket(key)
 }
 
 func (tx *transaction) ReadWriteBucket(key []byte) walletdb.ReadWriteBucket {
-	if tx.db.closed {
+	if tx.db.isClosed() {
 		return nil
 	}
 
@@ -71,7 +75,7 @@ func (tx *transaction) ReadWriteBucket(key []byte) walletdb.ReadWriteBucket {
 }
 
 func (tx *transaction) CreateTopLevelBucket(key []byte) (walletdb.ReadWriteBucket, error) {
-	if tx.db.closed {
+	if tx.db.isClosed() {
 		return nil, errors.E(errors.Invalid)
 	}
 
@@ -84,7 +88,7 @@ func (tx *transaction) CreateTopLevelBucket(key []byte) (walletdb.ReadWriteBucke
 }
 
 func (tx *transaction) DeleteTopLevelBucket(key []byte) error {
-	if tx.db.closed {
+	if tx.db.isClosed() {
 		return errors.E(errors.Invalid)
 	}
 
@@ -125,7 +129,7 @@ func (tx *transaction) DeleteTopLevelBucket(key []byte) error {
 //
 // This function is part of the walletdb.Tx interface implementation.
 func (tx *transaction) Commit() error {
-	if tx.db.closed {
+	if tx.db.isClosed() {
 		return errors.E(errors.Invalid)
 	}
 
@@ -141,7 +145,7 @@ func (tx *transaction) Commit() error {
 //
 // This function is part of the walletdb.Tx interface implementation.
 func (tx *transaction) Rollback() error {
-	if tx.db.closed || tx.isDiscarded {
+	if tx.db.isClosed() || tx.isDiscarded {
 		return errors.E(errors.Invalid)
 	}
 
@@ -158,7 +162,7 @@ var _ walletdb.ReadWriteBucket = (*Bucket)(nil)
 //
 // This function is part of the walletdb.ReadWriteBucket interface implementation.
 func (b *Bucket) NestedReadWriteBucket(key []byte) walletdb.ReadWriteBucket {
-	if b.dbTransaction.db.closed {
+	if b.dbTransaction.db.isClosed() {
 		return nil
 	}
 
@@ -184,7 +188,7 @@ func (b *Bucket) NestedReadWriteBucket(key []byte) walletdb.ReadWriteBucket {
 }
 
 func (b *Bucket) NestedReadBucket(key []byte) walletdb.ReadBucket {
-	if b.dbTransaction.db.closed {
+	if b.dbTransaction.db.isClosed() {
 		return nil
 	}
 	return b.NestedReadWriteBucket(key)
@@ -196,7 +200,7 @@ func (b *Bucket) NestedReadBucket(key []byte) walletdb.ReadBucket {
 //
 // This function is part of the walletdb.Bucket interface implementation.
 func (b *Bucket) CreateBucket(key []byte) (walletdb.ReadWriteBucket, error) {
-	if b.dbTransaction.db.closed {
+	if b.dbTransaction.db.isClosed() {
 		return nil, errors.E(errors.Invalid)
 	}
 	bucket, err := b.bucket(key, true)
@@ -212,7 +216,7 @@ func (b *Bucket) CreateBucket(key []byte) (walletdb.ReadWriteBucket, error) {
 //
 // This function is part of the walletdb.Bucket interface implementation.
 func (b *Bucket) CreateBucketIfNotExists(key []byte) (walletdb.ReadWriteBucket, error) {
-	if b.dbTransaction.db.closed {
+	if b.dbTransaction.db.isClosed() {
 		return nil, errors.E(errors.Invalid)
 	}
 	bucket, err := b.bucket(key, false)
@@ -230,7 +234,7 @@ func (b *Bucket) DeleteNestedBucket(key []byte) error {
 		return errors.E(errors.Invalid)
 	}
 
-	if b.dbTransaction.db.closed {
+	if b.dbTransaction.db.isClosed() {
 		return errors.E(errors.Invalid)
 	}
 
@@ -247,7 +251,7 @@ func (b *Bucket) DeleteNestedBucket(key []byte) error {
 //
 // This function is part of the walletdb.Bucket interface implementation.
 func (b *Bucket) ForEach(fn func(k, v []byte) error) error {
-	if b.dbTransaction.db.closed {
+	if b.dbTransaction.db.isClosed() {
 		return errors.E(errors.Invalid)
 	}
 
@@ -259,7 +263,7 @@ func (b *Bucket) ForEach(fn func(k, v []byte) error) error {
 //
 // This function is part of the walletdb.Bucket interface implementation.
 func (b *Bucket) Put(key, value []byte) error {
-	if b.dbTransaction.db.closed {
+	if b.dbTransaction.db.isClosed() {
 		return errors.E(errors.Invalid)
 	}
 
@@ -275,7 +279,7 @@ func (b *Bucket) Put(key, value []byte) error {
 //
 // This function is part of the walletdb.Bucket interface implementation.
 func (b *Bucket) Get(key []byte) []byte {
-	if b.dbTransaction.db.closed {
+	if b.dbTransaction.db.isClosed() {
 		return nil
 	}
 
@@ -294,7 +298,7 @@ func (b *Bucket) KeyN() int {
 //
 // This function is part of the walletdb.Bucket interface implementation.
 func (b *Bucket) Delete(key []byte) error {
-	if b.dbTransaction.db.closed {
+	if b.dbTransaction.db.isClosed() {
 		return errors.E(errors.Invalid)
 	}
 
@@ -302,7 +306,7 @@ func (b *Bucket) Delete(key []byte) error {
 }
 
 func (b *Bucket) ReadCursor() walletdb.ReadCursor {
-	if b.dbTransaction.db.closed {
+	if b.dbTransaction.db.isClosed() {
 		return nil
 	}
 
@@ -328,7 +332,7 @@ func (b *Bucket) ReadCursor() walletdb.ReadCursor {
 //
 // This function is part of the walletdb.Bucket interface implementation.
 func (b *Bucket) ReadWriteCursor() walletdb.ReadWriteCursor {
-	if b.dbTransaction.db.closed {
+	if b.dbTransaction.db.isClosed() {
 		return nil
 	}
 	return b.badgerCursor()
@@ -339,7 +343,7 @@ func (b *Bucket) ReadWriteCursor() walletdb.ReadWriteCursor {
 //
 // This function is part of the walletdb.Cursor interface implementation.
 func (c *Cursor) Delete() error {
-	if c.dbTransaction.db.closed {
+	if c.dbTransaction.db.isClosed() {
 		return errors.E(errors.Invalid)
 	}
 
@@ -358,7 +362,7 @@ func (c *Cursor) Delete() error {
 //
 // This function is part of the walletdb.Cursor interface implementation.
 func (c *Cursor) First() (key, value []byte) {
-	if c.dbTransaction.db.closed {
+	if c.dbTransaction.db.isClosed() {
 		return nil, nil
 	}
 
@@ -396,7 +400,7 @@ func (c *Cursor) First() (key, value []byte) {
 //
 // This function is part of the walletdb.Cursor interface implementation.
 func (c *Cursor) Last() (key, value []byte) {
-	if c.dbTransaction.db.closed {
+	if c.dbTransaction.db.isClosed() {
 		return nil, nil
 	}
 
@@ -435,7 +439,7 @@ func (c *Cursor) Last() (key, value []byte) {
 //
 // This function is part of the walletdb.Cursor interface implementation.
 func (c *Cursor) Next() (key, value []byte) {
-	if c.dbTransaction.db.closed {
+	if c.dbTransaction.db.isClosed() {
 		return nil, nil
 	}
 
@@ -476,7 +480,7 @@ func (c *Cursor) Next() (key, value []byte) {
 //
 // This function is part of the walletdb.Cursor interface implementation.
 func (c *Cursor) Prev() (key, value []byte) {
-	if c.dbTransaction.db.closed {
+	if c.dbTransaction.db.isClosed() {
 		return nil, nil
 	}
 
@@ -524,7 +528,7 @@ func (c *Cursor) Prev() (key, value []byte) {
 //
 // This function is part of the walletdb.Cursor interface implementation.
 func (c *Cursor) Seek(seek []byte) (key, value []byte) {
-	if c.dbTransaction.db.closed {
+	if c.dbTransaction.db.isClosed() {
 		return nil, nil
 	}
 
@@ -565,7 +569,7 @@ func (c *Cursor) Seek(seek []byte) (key, value []byte) {
 //
 // This function is part of the walletdb.Cursor interface implementation.
 func (c *Cursor) Close() {
-	if c.dbTransaction.db.closed {
+	if c.dbTransaction.db.isClosed() {
 		return
 	}
 
@@ -577,14 +581,26 @@ func (c *Cursor) Close() {
 // transactions which are obtained through the specific Namespace.
 type db struct {
 	*badger.DB
-	closed bool
+	path   string
+	closed uint32 // atomic
+	quit   chan struct{}
+
+	// closeMtx is held by Close and, for their whole duration, by the
+	// compaction and copy of the database so that it isn't closed under them.
+	closeMtx sync.RWMutex
+	// gcWG waits for the value log garbage collection goroutine.
+	gcWG sync.WaitGroup
+}
+
+func (db *db) isClosed() bool {
+	return atomic.LoadUint32(&db.closed) == 1
 }
 
 // Enforce db implements the walletdb.DB interface.
 var _ walletdb.DB = (*db)(nil)
 
 func (db *db) beginTx(writable bool) (*transaction, error) {
-	if db.closed {
+	if db.isClosed() {
 		return nil, errors.E(errors.Invalid)
 	}
 
@@ -604,20 +620,147 @@ func (db *db) BeginReadWriteTx() (walletdb.ReadWriteTx, error) {
 // Copy writes a copy of the database to the provided writer.  This call will
 // start a read-only transaction to perform all operations.
 //
+// The copy is written in the bolt file format used by the bdb walletdb driver
+// so that it can be opened by either driver once restored.
+//
 // This function is part of the walletdb.DB interface implementation.
-func (db *db) Copy(_ io.Writer) error {
-	return errors.E(errors.Invalid, "method not implemented")
+func (db *db) Copy(w io.Writer) error {
+	db.closeMtx.RLock()
+	defer db.closeMtx.RUnlock()
+
+	if db.isClosed() {
+		return errors.E(errors.Invalid)
+	}
+
+	// The bolt file is staged next to the database rather than in the
+	// system temp directory, which may be too small or shared.
+	tmpFile, err := os.CreateTemp(filepath.Dir(db.path), "badgerdb-copy-*.db")
+	if err != nil {
+		return errors.E(errors.IO, err)
+	}
+	tmpPath := tmpFile.Name()
+	tmpFile.Close()
+	defer os.Remove(tmpPath)
+
+	boltDB, err := bolt.Open(tmpPath, 0o600, nil)
+	if err != nil {
+		return errors.E(errors.IO, err)
+	}
+
+	// The badger read transaction provides a consistent snapshot of the
+	// database while it remains open for writes.
+	err = db.DB.View(func(txn *badger.Txn) error {
+		tx := &transaction{badgerTx: txn, db: db}
+		return boltDB.Update(func(boltTx *bolt.Tx) error {
+			for _, key := range topLevelBuckets(txn) {
+				dst, err := boltTx.CreateBucket(key)
+				if err != nil {
+					return err
+				}
+				if err = copyBucket(tx.ReadBucket(key), dst); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if cerr := boltDB.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return convertErr(err)
+	}
+
+	file, err := os.Open(tmpPath)
+	if err != nil {
+		return errors.E(errors.IO, err)
+	}
+	defer file.Close()
+
+	if _, err = io.Copy(w, file); err != nil {
+		return errors.E(errors.IO, err)
+	}
+	return nil
+}
+
+// TopLevelBuckets returns the keys of the top level buckets of the open
+// badger database wdb, which walletdb transactions cannot enumerate.
+func TopLevelBuckets(wdb walletdb.DB) ([][]byte, error) {
+	d, ok := wdb.(*db)
+	if !ok {
+		return nil, errors.E(errors.Invalid, "not a badger database")
+	}
+	if d.isClosed() {
+		return nil, errors.E(errors.Invalid)
+	}
+
+	var keys [][]byte
+	err := d.DB.View(func(txn *badger.Txn) error {
+		keys = topLevelBuckets(txn)
+		return nil
+	})
+	return keys, convertErr(err)
+}
+
+// topLevelBuckets returns the keys of the buckets that are not nested in
+// another bucket.
+func topLevelBuckets(txn *badger.Txn) [][]byte {
+	opts := badger.DefaultIteratorOptions
+	it := txn.NewIterator(opts)
+	defer it.Close()
+
+	var keys [][]byte
+	for it.Rewind(); it.Valid(); it.Next() {
+		item := it.Item()
+		if item.UserMeta() != metaBucket {
+			continue
+		}
+		val, err := item.ValueCopy(nil)
+		if err != nil || len(val) == 0 {
+			continue
+		}
+		// The prefix length of a top level bucket is the length of its key.
+		if int(val[0]) == len(item.Key()) {
+			keys = append(keys, item.KeyCopy(nil))
+		}
+	}
+	return keys
+}
+
+// copyBucket recursively copies the key/value pairs and nested buckets of src
+// to dst.
+func copyBucket(src walletdb.ReadBucket, dst *bolt.Bucket) error {
+	if src == nil {
+		return nil
+	}
+	return src.ForEach(func(k, v []byte) error {
+		k = append([]byte(nil), k...)
+		if v == nil {
+			nested, err := dst.CreateBucket(k)
+			if err != nil {
+				return err
+			}
+			return copyBucket(src.NestedReadBucket(k), nested)
+		}
+		return dst.Put(k, append([]byte(nil), v...))
+	})
 }
 
 // Close cleanly shuts down the database and syncs all data.
 //
 // This function is part of the walletdb.DB interface implementation.
 func (db *db) Close() error {
-	if db.closed {
+	db.closeMtx.Lock()
+	defer db.closeMtx.Unlock()
+
+	if db.isClosed() {
 		return errors.E(errors.Invalid, "database is already closed")
 	}
 
-	db.closed = true // setting this to true to pause all operations that will happen while db is closing
+	atomic.StoreUint32(&db.closed, 1) // pause all operations that will happen while db is closing
+	close(db.quit)
+	unregisterDB(db)
+	db.gcWG.Wait()
 
 	err := db.DB.Close()
 	if err != nil {
@@ -656,11 +799,15 @@ func openDB(dbPath string, create bool) (walletdb.DB, error) {
 		WithNumLevelZeroTablesStall(2)
 
 	d := &db{
-		closed: false,
+		path: dbPath,
+		quit: make(chan struct{}),
 	}
 	badgerDB, err := badger.Open(opts)
 	if err == nil {
 		d.DB = badgerDB
+		registerDB(dbPath, d)
+		d.gcWG.Add(1)
+		go d.runValueLogGC()
 	}
 
 	return d, convertErr(err)
@@ -0,0 +1,199 @@
+package badgerdb
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"decred.org/dcrwallet/v3/errors"
+	_ "decred.org/dcrwallet/v3/wallet/drivers/bdb" // driver loaded during init
+	"decred.org/dcrwallet/v3/wallet/walletdb"
+	bolt "go.etcd.io/bbolt"
+)
+
+// newTestDB creates a badger database holding the provided top level
+// buckets, each with a value, an empty value and a nested bucket.
+func newTestDB(t *testing.T, buckets ...string) (walletdb.DB, string) {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "wallet.db")
+	db, err := walletdb.Create(dbType, dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = walletdb.Update(context.Background(), db, func(tx walletdb.ReadWriteTx) error {
+		for _, name := range buckets {
+			bucket, err := tx.CreateTopLevelBucket([]byte(name))
+			if err != nil {
+				return err
+			}
+			if err = bucket.Put([]byte("key"), []byte(name)); err != nil {
+				return err
+			}
+			if err = bucket.Put([]byte("empty"), []byte{}); err != nil {
+				return err
+			}
+			nested, err := bucket.CreateBucket([]byte("nested"))
+			if err != nil {
+				return err
+			}
+			if err = nested.Put([]byte("nested key"), []byte("nested value")); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, dbPath
+}
+
+// dumpBuckets returns the key/value pairs of the provided top level buckets
+// and of their nested buckets keyed by their path.
+func dumpBuckets(t *testing.T, db walletdb.DB, buckets [][]byte) map[string]string {
+	t.Helper()
+	var dumpBucket func(b walletdb.ReadBucket, prefix string, kv map[string]string) error
+	dumpBucket = func(b walletdb.ReadBucket, prefix string, kv map[string]string) error {
+		return b.ForEach(func(k, v []byte) error {
+			if v == nil {
+				return dumpBucket(b.NestedReadBucket(k), prefix+string(k)+"/", kv)
+			}
+			kv[prefix+string(k)] = string(v)
+			return nil
+		})
+	}
+
+	kv := make(map[string]string)
+	err := walletdb.View(context.Background(), db, func(tx walletdb.ReadTx) error {
+		for _, name := range buckets {
+			if err := dumpBucket(tx.ReadBucket(name), string(name)+"/", kv); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return kv
+}
+
+func TestCopy(t *testing.T) {
+	db, dbPath := newTestDB(t, "waddrmgr", "wtxmgr", "meta")
+	defer db.Close()
+
+	buckets, err := TopLevelBuckets(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := dumpBuckets(t, db, buckets)
+	if len(expected) != 9 {
+		t.Fatalf("expected (9) values in the database, got (%v)", expected)
+	}
+
+	var buf bytes.Buffer
+	if err := db.Copy(&buf); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+
+	// The staged bolt file is removed once copied.
+	entries, err := os.ReadDir(filepath.Dir(dbPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the database next to the copied database, got (%d) entries", len(entries))
+	}
+
+	copyPath := filepath.Join(t.TempDir(), "wallet.db")
+	if err := os.WriteFile(copyPath, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The top level buckets of a bolt file are only listed by bolt.
+	boltDB, err := bolt.Open(copyPath, 0o600, &bolt.Options{ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var copiedBuckets [][]byte
+	err = boltDB.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			copiedBuckets = append(copiedBuckets, append([]byte(nil), name...))
+			return nil
+		})
+	})
+	boltDB.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(copiedBuckets) != len(buckets) {
+		t.Errorf("expected (%d) top level buckets, got (%d)", len(buckets), len(copiedBuckets))
+	}
+
+	copied, err := walletdb.Open("bdb", copyPath)
+	if err != nil {
+		t.Fatalf("opening the copy with the bdb driver: %v", err)
+	}
+	defer copied.Close()
+
+	if got := dumpBuckets(t, copied, copiedBuckets); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected (%v), got (%v)", expected, got)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	db, dbPath := newTestDB(t, "waddrmgr")
+
+	// Overwritten and deleted values leave discardable entries.
+	for i := 0; i < 3; i++ {
+		err := walletdb.Update(context.Background(), db, func(tx walletdb.ReadWriteTx) error {
+			bucket := tx.ReadWriteBucket([]byte("waddrmgr"))
+			for n := 0; n < 100; n++ {
+				key := []byte("key " + strconv.Itoa(n))
+				if err := bucket.Put(key, bytes.Repeat([]byte{byte(i)}, 1024)); err != nil {
+					return err
+				}
+				if n%2 == 0 {
+					if err := bucket.Delete(key); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := dumpBuckets(t, db, [][]byte{[]byte("waddrmgr")})
+
+	if err := Compact(dbPath); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+
+	// The database remains in use once compacted.
+	if got := dumpBuckets(t, db, [][]byte{[]byte("waddrmgr")}); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected (%v), got (%v)", expected, got)
+	}
+	err := walletdb.Update(context.Background(), db, func(tx walletdb.ReadWriteTx) error {
+		return tx.ReadWriteBucket([]byte("waddrmgr")).Put([]byte("after"), []byte("compact"))
+	})
+	if err != nil {
+		t.Fatalf("writing to the compacted database: %v", err)
+	}
+
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Compact(dbPath); !errors.Is(err, errors.NotExist) {
+		t.Errorf("expected (%v) compacting a closed database, got (%v)", errors.NotExist, err)
+	}
+	if err := db.Close(); !errors.Is(err, errors.Invalid) {
+		t.Errorf("expected (%v) closing the database twice, got (%v)", errors.Invalid, err)
+	}
+}
@@ -44,6 +44,29 @@ func (mgr *AssetsManager) CreateNewBTCWallet(walletName, privatePassphrase strin
 	return wallet, nil
 }
 
+// CreateNewBTCMultisigWallet creates a new BTC multisig wallet and returns it.
+// The multisig is set up once the keys of the cosigners are known.
+func (mgr *AssetsManager) CreateNewBTCMultisigWallet(walletName, privatePassphrase string, privatePassphraseType int32) (sharedW.Asset, error) {
+	pass := &sharedW.AuthInfo{
+		Name:            walletName,
+		PrivatePass:     privatePassphrase,
+		PrivatePassType: privatePassphraseType,
+	}
+	wallet, err := btc.CreateNewMultisigWallet(pass, mgr.params)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr.Assets.BTC.Wallets[wallet.GetWalletID()] = wallet
+
+	// extract the db interface if it hasn't been set already.
+	if mgr.db == nil && wallet != nil {
+		mgr.setDBInterface(wallet.(sharedW.AssetsManagerDB))
+	}
+
+	return wallet, nil
+}
+
 // CreateNewBTCWatchOnlyWallet creates a new BTC watch only wallet and returns it.
 func (mgr *AssetsManager) CreateNewBTCWatchOnlyWallet(walletName, extendedPublicKey string) (sharedW.Asset, error) {
 	wallet, err := btc.CreateWatchOnlyWallet(walletName, extendedPublicKey, mgr.params)
@@ -61,12 +84,32 @@ func (mgr *AssetsManager) CreateNewBTCWatchOnlyWallet(walletName, extendedPublic
 	return wallet, nil
 }
 
-// RestoreBTCWallet restores a BTC wallet from a seed and returns it.
-func (mgr *AssetsManager) RestoreBTCWallet(walletName, seedMnemonic, privatePassphrase string, privatePassphraseType int32) (sharedW.Asset, error) {
+// CreateNewBTCWatchOnlyWalletFromDescriptor creates a new BTC watch only wallet
+// from an output descriptor and returns it.
+func (mgr *AssetsManager) CreateNewBTCWatchOnlyWalletFromDescriptor(walletName, descriptor string) (sharedW.Asset, error) {
+	wallet, err := btc.CreateWatchOnlyWalletFromDescriptor(walletName, descriptor, mgr.params)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr.Assets.BTC.Wallets[wallet.GetWalletID()] = wallet
+
+	// extract the db interface if it hasn't been set already.
+	if mgr.db == nil && wallet != nil {
+		mgr.setDBInterface(wallet.(sharedW.AssetsManagerDB))
+	}
+
+	return wallet, nil
+}
+
+// RestoreBTCWallet restores a BTC wallet from a seed and returns it. The seed
+// passphrase is the optional passphrase of BIP39 seeds.
+func (mgr *AssetsManager) RestoreBTCWallet(walletName, seedMnemonic, seedPassphrase, privatePassphrase string, privatePassphraseType int32) (sharedW.Asset, error) {
 	pass := &sharedW.AuthInfo{
 		Name:            walletName,
 		PrivatePass:     privatePassphrase,
 		PrivatePassType: privatePassphraseType,
+		SeedPassphrase:  seedPassphrase,
 	}
 	wallet, err := btc.RestoreWallet(seedMnemonic, pass, mgr.params)
 	if err != nil {
@@ -114,9 +157,9 @@ func (mgr *AssetsManager) BTCWalletWithXPub(xpub string) (int, error) {
 }
 
 // BTCWalletWithSeed returns the ID of the BTC wallet that was created or restored
-// using the same seed as the one provided. Returns -1 if no wallet uses the
-// provided seed.
-func (mgr *AssetsManager) BTCWalletWithSeed(seedMnemonic string) (int, error) {
+// using the same seed and seed passphrase as the ones provided. Returns -1 if
+// no wallet uses the provided seed.
+func (mgr *AssetsManager) BTCWalletWithSeed(seedMnemonic, seedPassphrase string) (int, error) {
 	if len(seedMnemonic) == 0 {
 		return -1, errors.New(utils.ErrEmptySeed)
 	}
@@ -140,7 +183,7 @@ func (mgr *AssetsManager) BTCWalletWithSeed(seedMnemonic string) (int, error) {
 			if accs.AccountNumber == waddrmgr.ImportedAddrAccount {
 				continue
 			}
-			xpub, err := asset.DeriveAccountXpub(seedMnemonic,
+			xpub, err := asset.DeriveAccountXpub(seedMnemonic, seedPassphrase,
 				accs.AccountNumber, wallet.Internal().BTC.ChainParams())
 			if err != nil {
 				return -1, err
@@ -158,12 +201,14 @@ func (mgr *AssetsManager) BTCWalletWithSeed(seedMnemonic string) (int, error) {
 	return -1, nil
 }
 
-// RestoreLTCWallet restores a LTC wallet from a seed and returns it.
-func (mgr *AssetsManager) RestoreLTCWallet(walletName, seedMnemonic, privatePassphrase string, privatePassphraseType int32) (sharedW.Asset, error) {
+// RestoreLTCWallet restores a LTC wallet from a seed and returns it. The seed
+// passphrase is the optional passphrase of BIP39 seeds.
+func (mgr *AssetsManager) RestoreLTCWallet(walletName, seedMnemonic, seedPassphrase, privatePassphrase string, privatePassphraseType int32) (sharedW.Asset, error) {
 	pass := &sharedW.AuthInfo{
 		Name:            walletName,
 		PrivatePass:     privatePassphrase,
 		PrivatePassType: privatePassphraseType,
+		SeedPassphrase:  seedPassphrase,
 	}
 	wallet, err := ltc.RestoreWallet(seedMnemonic, pass, mgr.params)
 	if err != nil {
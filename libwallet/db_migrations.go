@@ -0,0 +1,42 @@
+package libwallet
+
+import (
+	"github.com/asdine/storm"
+
+	"github.com/crypto-power/cryptopower/libwallet/migration"
+)
+
+const (
+	assetsDbVersionField = "db-version"
+
+	// baseAssetsDbVersion is the version of assets manager dbs created before
+	// the db was versioned.
+	baseAssetsDbVersion uint32 = 1
+
+	// assetsDbVersion is the current version of the assets manager db.
+	// Increment it along with a migration for the new version in
+	// assetsDBMigrations.
+	assetsDbVersion uint32 = 1
+)
+
+// assetsDBMigrations upgrade the assets manager db from baseAssetsDbVersion,
+// one version at a time.
+var assetsDBMigrations = []migration.Migration{}
+
+// migrateAssetsDB upgrades the assets manager db to the latest version. The
+// db file is backed up before the first migration is applied.
+func migrateAssetsDB(db *storm.DB) error {
+	var version uint32
+	err := db.Get(walletsMetadataBucketName, assetsDbVersionField, &version)
+	if err == storm.ErrNotFound {
+		err = db.Set(walletsMetadataBucketName, assetsDbVersionField, baseAssetsDbVersion)
+	}
+	if err != nil {
+		return err
+	}
+
+	return migration.Run(db, walletsDbName, walletsMetadataBucketName, assetsDbVersionField,
+		assetsDbVersion, assetsDBMigrations, func(progress *migration.Progress) {
+			log.Infof("Migrating %s (%d/%d): %s", progress.DBName, progress.Step, progress.Total, progress.Description)
+		})
+}
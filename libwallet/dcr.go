@@ -4,7 +4,6 @@ import (
 	"context"
 
 	"decred.org/dcrwallet/v3/errors"
-	"decred.org/dcrwallet/v3/walletseed"
 
 	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/dcrd/hdkeychain/v3"
@@ -63,12 +62,14 @@ func (mgr *AssetsManager) CreateNewDCRWatchOnlyWallet(walletName, extendedPublic
 	return wallet, nil
 }
 
-// RestoreDCRWallet restores a DCR wallet from a seed and returns it.
-func (mgr *AssetsManager) RestoreDCRWallet(walletName, seedMnemonic, privatePassphrase string, privatePassphraseType int32) (sharedW.Asset, error) {
+// RestoreDCRWallet restores a DCR wallet from a seed and returns it. The seed
+// passphrase is the optional passphrase of SLIP-39 seed shares.
+func (mgr *AssetsManager) RestoreDCRWallet(walletName, seedMnemonic, seedPassphrase, privatePassphrase string, privatePassphraseType int32) (sharedW.Asset, error) {
 	pass := &sharedW.AuthInfo{
 		Name:            walletName,
 		PrivatePass:     privatePassphrase,
 		PrivatePassType: privatePassphraseType,
+		SeedPassphrase:  seedPassphrase,
 	}
 	wallet, err := dcr.RestoreWallet(seedMnemonic, pass, mgr.params)
 	if err != nil {
@@ -116,14 +117,14 @@ func (mgr *AssetsManager) DCRWalletWithXPub(xpub string) (int, error) {
 }
 
 // DCRWalletWithSeed returns the ID of the DCR wallet that was created or restored
-// using the same seed as the one provided. Returns -1 if no wallet uses the
-// provided seed.
-func (mgr *AssetsManager) DCRWalletWithSeed(seedMnemonic string) (int, error) {
+// using the same seed and seed shares passphrase as the ones provided. Returns
+// -1 if no wallet uses the provided seed.
+func (mgr *AssetsManager) DCRWalletWithSeed(seedMnemonic, seedPassphrase string) (int, error) {
 	if len(seedMnemonic) == 0 {
 		return -1, errors.New(utils.ErrEmptySeed)
 	}
 
-	newSeedLegacyXPUb, newSeedSLIP0044XPUb, err := deriveBIP44AccountXPubsForDCR(seedMnemonic,
+	newSeedLegacyXPUb, newSeedSLIP0044XPUb, err := deriveBIP44AccountXPubsForDCR(seedMnemonic, seedPassphrase,
 		dcr.DefaultAccountNum, mgr.chainsParams.DCR)
 	if err != nil {
 		return -1, err
@@ -155,8 +156,8 @@ func (mgr *AssetsManager) DCRWalletWithSeed(seedMnemonic string) (int, error) {
 
 // deriveBIP44AccountXPubForDCR derives and returns the legacy and SLIP0044 account
 // xpubs using the BIP44 HD path for accounts: m/44'/<coin type>'/<account>'.
-func deriveBIP44AccountXPubsForDCR(seedMnemonic string, account uint32, params *chaincfg.Params) (string, string, error) {
-	seed, err := walletseed.DecodeUserInput(seedMnemonic)
+func deriveBIP44AccountXPubsForDCR(seedMnemonic, seedPassphrase string, account uint32, params *chaincfg.Params) (string, string, error) {
+	seed, err := sharedW.DecodeSeedMnemonicWithPassphrase(seedMnemonic, seedPassphrase, utils.DCRWalletAsset)
 	if err != nil {
 		return "", "", err
 	}
@@ -0,0 +1,92 @@
+package libwallet
+
+import (
+	"decred.org/dcrwallet/v3/errors"
+	"github.com/asdine/storm"
+
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+)
+
+// IsDevModeSupported returns true if developer mode can be used on the
+// network of the assets manager. Real networks are never redirected to
+// stand-ins.
+func (mgr *AssetsManager) IsDevModeSupported() bool {
+	netType := mgr.NetType()
+	return netType == utils.Simulation || netType == utils.Regression
+}
+
+// loadDevMode applies the saved developer mode config. Like the proxy, it
+// must be in place before any external service is contacted.
+func (mgr *AssetsManager) loadDevMode() error {
+	if !mgr.IsDevModeSupported() {
+		return nil
+	}
+
+	cfg := new(utils.DevModeConfig)
+	err := mgr.params.DB.Get(walletsMetadataBucketName, sharedW.DevModeConfigKey, cfg)
+	if err == storm.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return utils.SetDevMode(cfg)
+}
+
+// SetDevMode saves cfg and points the external services of the app at the
+// stand-ins it lists. A nil cfg or one that is not enabled turns developer
+// mode off. The politeia host is only read on startup, so changes to it
+// apply after a restart.
+func (mgr *AssetsManager) SetDevMode(cfg *utils.DevModeConfig) error {
+	const op errors.Op = "mgr.SetDevMode"
+
+	if !mgr.IsDevModeSupported() {
+		return errors.E(op, utils.ErrDevModeNotSupported)
+	}
+	if err := utils.SetDevMode(cfg); err != nil {
+		return errors.E(op, err)
+	}
+
+	var err error
+	if cfg == nil {
+		err = mgr.params.DB.Delete(walletsMetadataBucketName, sharedW.DevModeConfigKey)
+		if err == storm.ErrNotFound {
+			err = nil
+		}
+	} else {
+		err = mgr.params.DB.Set(walletsMetadataBucketName, sharedW.DevModeConfigKey, cfg)
+	}
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	if mgr.RateSource != nil {
+		go mgr.RateSource.Reconnect()
+	}
+	return nil
+}
+
+// DevModeConfig returns the saved developer mode config. Unlike
+// utils.CurrentDevMode, the stand-ins of a disabled config are kept so they
+// can be edited and turned back on.
+func (mgr *AssetsManager) DevModeConfig() *utils.DevModeConfig {
+	cfg := new(utils.DevModeConfig)
+	err := mgr.params.DB.Get(walletsMetadataBucketName, sharedW.DevModeConfigKey, cfg)
+	if err != nil {
+		if err != storm.ErrNotFound {
+			log.Errorf("error reading the developer mode config: %v", err)
+		}
+		return &utils.DevModeConfig{ServiceURLs: make(map[utils.DevService]string)}
+	}
+	if cfg.ServiceURLs == nil {
+		cfg.ServiceURLs = make(map[utils.DevService]string)
+	}
+	return cfg
+}
+
+// IsDevMode returns true if the external services of the app are pointed at
+// local stand-ins.
+func (mgr *AssetsManager) IsDevMode() bool {
+	return utils.IsDevMode()
+}
@@ -0,0 +1,204 @@
+// Package eventbus delivers the events published by the wallets and the
+// services of libwallet to their subscribers. Every subscriber has its own
+// queue and goroutine, so a slow subscriber never holds back the publisher or
+// the other subscribers.
+package eventbus
+
+import (
+	"sort"
+	"sync"
+
+	"decred.org/dcrwallet/v3/errors"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+)
+
+// maxQueuedEvents is the number of events waiting for a subscriber after which
+// the subscriber is reported as lagging. Events are not dropped, only the
+// waiting state events are coalesced.
+const maxQueuedEvents = 512
+
+// Event is a notification published on the bus.
+type Event struct {
+	Topic Topic
+	// WalletID is the wallet the event is about. It is 0 for the events that
+	// are not about a single wallet.
+	WalletID int
+	// Asset is the asset type of the wallet or the service that published
+	// the event, if any.
+	Asset utils.AssetType
+	// Payload is the data of the event. The type of the payload is documented
+	// on each topic.
+	Payload interface{}
+
+	// state is true for the events kept by the bus and replayed to the new
+	// subscribers.
+	state bool
+	seq   uint64
+}
+
+// Filter selects the events delivered to a subscriber. The zero value of a
+// field matches every event.
+type Filter struct {
+	Topics   []Topic
+	WalletID int
+	Asset    utils.AssetType
+}
+
+func (f *Filter) matches(e *Event) bool {
+	if f.WalletID != 0 && f.WalletID != e.WalletID {
+		return false
+	}
+	if f.Asset != "" && f.Asset != e.Asset {
+		return false
+	}
+	if len(f.Topics) == 0 {
+		return true
+	}
+	for _, topic := range f.Topics {
+		if topic == e.Topic {
+			return true
+		}
+	}
+	return false
+}
+
+type stateKey struct {
+	topic    Topic
+	walletID int
+}
+
+// Bus is an asynchronous publish/subscribe event bus. A Bus must be created
+// with New.
+type Bus struct {
+	mtx         sync.RWMutex
+	seq         uint64
+	subscribers map[string]*subscriber
+	states      map[stateKey]Event
+}
+
+// New returns an empty event bus.
+func New() *Bus {
+	return &Bus{
+		subscribers: make(map[string]*subscriber),
+		states:      make(map[stateKey]Event),
+	}
+}
+
+// Subscribe registers handler to be called with the events matching filter.
+// The last state events matching filter are replayed to handler first. The
+// handler of a subscriber is called from a single goroutine, in the order the
+// events were published.
+func (b *Bus) Subscribe(id string, filter Filter, handler func(Event)) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if _, ok := b.subscribers[id]; ok {
+		return errors.New(utils.ErrListenerAlreadyExist)
+	}
+
+	sub := newSubscriber(id, filter, handler)
+	b.subscribers[id] = sub
+
+	states := make([]Event, 0)
+	for _, e := range b.states {
+		if filter.matches(&e) {
+			states = append(states, e)
+		}
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].seq < states[j].seq
+	})
+	for _, e := range states {
+		sub.push(e)
+	}
+
+	go sub.run()
+	return nil
+}
+
+// Unsubscribe removes the subscriber with id. The events waiting for the
+// subscriber are dropped. Unsubscribe does not wait for the handler: a handler
+// call in progress, or one for an event taken from the queue just before, may
+// still complete after Unsubscribe returns, but no other call starts. This
+// allows a handler to unsubscribe itself.
+func (b *Bus) Unsubscribe(id string) {
+	b.mtx.Lock()
+	sub, ok := b.subscribers[id]
+	delete(b.subscribers, id)
+	b.mtx.Unlock()
+
+	if ok {
+		sub.stop()
+	}
+}
+
+// IsSubscribed returns true if a subscriber with id is registered.
+func (b *Bus) IsSubscribed(id string) bool {
+	b.mtx.RLock()
+	defer b.mtx.RUnlock()
+
+	_, ok := b.subscribers[id]
+	return ok
+}
+
+// Publish delivers e to the matching subscribers.
+func (b *Bus) Publish(e Event) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.publish(e)
+}
+
+// PublishState delivers e to the matching subscribers and keeps it as the
+// last state of its topic and wallet, which is replayed to new subscribers.
+func (b *Bus) PublishState(e Event) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	e.state = true
+	b.publish(e)
+}
+
+// ClearState forgets the last state event of topic for the wallet with
+// walletID.
+func (b *Bus) ClearState(topic Topic, walletID int) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	delete(b.states, stateKey{topic, walletID})
+}
+
+// LastState returns the last state event of topic for the wallet with
+// walletID.
+func (b *Bus) LastState(topic Topic, walletID int) (Event, bool) {
+	b.mtx.RLock()
+	defer b.mtx.RUnlock()
+
+	e, ok := b.states[stateKey{topic, walletID}]
+	return e, ok
+}
+
+// publish must be called with b.mtx held.
+func (b *Bus) publish(e Event) {
+	b.seq++
+	e.seq = b.seq
+	if e.state {
+		b.states[stateKey{e.Topic, e.WalletID}] = e
+	}
+
+	for _, sub := range b.subscribers {
+		if sub.filter.matches(&e) {
+			sub.push(e)
+		}
+	}
+}
+
+// Handle returns an event handler calling handler with the payload of the
+// events carrying a payload of type T. Other events are ignored.
+func Handle[T any](handler func(e Event, payload T)) func(Event) {
+	return func(e Event) {
+		if payload, ok := e.Payload.(T); ok {
+			handler(e, payload)
+		}
+	}
+}
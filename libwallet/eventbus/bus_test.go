@@ -0,0 +1,256 @@
+package eventbus
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+)
+
+const waitTimeout = 5 * time.Second
+
+// collect returns a handler sending the payloads of the events it receives to
+// the returned channel.
+func collect() (func(Event), chan interface{}) {
+	payloads := make(chan interface{}, 4*maxQueuedEvents)
+	return func(e Event) { payloads <- e.Payload }, payloads
+}
+
+// receive waits for n payloads on payloads.
+func receive(t *testing.T, payloads chan interface{}, n int) []interface{} {
+	t.Helper()
+	received := make([]interface{}, 0, n)
+	for len(received) < n {
+		select {
+		case payload := <-payloads:
+			received = append(received, payload)
+		case <-time.After(waitTimeout):
+			t.Fatalf("expected (%d) events, got (%d)", n, len(received))
+		}
+	}
+	return received
+}
+
+// expectNone checks that no payload is received on payloads for a while.
+func expectNone(t *testing.T, payloads chan interface{}) {
+	t.Helper()
+	select {
+	case payload := <-payloads:
+		t.Fatalf("expected no event, got (%v)", payload)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	event := Event{Topic: TopicTransaction, WalletID: 1, Asset: utils.BTCWalletAsset}
+	tests := []struct {
+		name   string
+		filter Filter
+		match  bool
+	}{
+		{name: "zero filter", filter: Filter{}, match: true},
+		{name: "same wallet", filter: Filter{WalletID: 1}, match: true},
+		{name: "other wallet", filter: Filter{WalletID: 2}, match: false},
+		{name: "same asset", filter: Filter{Asset: utils.BTCWalletAsset}, match: true},
+		{name: "other asset", filter: Filter{Asset: utils.DCRWalletAsset}, match: false},
+		{name: "listed topic", filter: Filter{Topics: []Topic{TopicBlockAttached, TopicTransaction}}, match: true},
+		{name: "unlisted topic", filter: Filter{Topics: []Topic{TopicBlockAttached}}, match: false},
+		{name: "all fields", filter: Filter{Topics: []Topic{TopicTransaction}, WalletID: 1, Asset: utils.BTCWalletAsset}, match: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if match := tc.filter.matches(&event); match != tc.match {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.match, match)
+			}
+		})
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	tests := []struct {
+		name     string
+		states   []Event
+		filter   Filter
+		expected []interface{}
+	}{
+		{
+			name: "no state",
+		},
+		{
+			name: "states replayed in publish order",
+			states: []Event{
+				{Topic: TopicSyncProgress, WalletID: 1, Payload: 1},
+				{Topic: TopicPeersChanged, WalletID: 1, Payload: 2},
+				{Topic: TopicSyncProgress, WalletID: 2, Payload: 3},
+				{Topic: TopicSyncProgress, WalletID: 1, Payload: 4},
+			},
+			expected: []interface{}{2, 3, 4},
+		},
+		{
+			name: "filtered states",
+			states: []Event{
+				{Topic: TopicSyncProgress, WalletID: 1, Payload: 1},
+				{Topic: TopicSyncProgress, WalletID: 2, Payload: 2},
+			},
+			filter:   Filter{WalletID: 2},
+			expected: []interface{}{2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bus := New()
+			for _, e := range tc.states {
+				bus.PublishState(e)
+			}
+
+			handler, payloads := collect()
+			if err := bus.Subscribe("sub", tc.filter, handler); err != nil {
+				t.Fatalf("(%v), expected (%v), got (%v)", tc.name, nil, err)
+			}
+			defer bus.Unsubscribe("sub")
+
+			replayed := receive(t, payloads, len(tc.expected))
+			if len(tc.expected) > 0 && !reflect.DeepEqual(replayed, tc.expected) {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.expected, replayed)
+			}
+			expectNone(t, payloads)
+
+			err := bus.Subscribe("sub", tc.filter, handler)
+			if err == nil || err.Error() != utils.ErrListenerAlreadyExist {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, utils.ErrListenerAlreadyExist, err)
+			}
+		})
+	}
+}
+
+func TestEventsAreNotDropped(t *testing.T) {
+	bus := New()
+	release := make(chan struct{})
+	handler, payloads := collect()
+	err := bus.Subscribe("slow", Filter{}, func(e Event) {
+		<-release
+		handler(e)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bus.Unsubscribe("slow")
+
+	n := 2 * maxQueuedEvents
+	expected := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		topic := []Topic{TopicTransaction, TopicTransactionConfirmed, TopicBlockAttached}[i%3]
+		bus.Publish(Event{Topic: topic, WalletID: 1, Payload: i})
+		expected = append(expected, i)
+	}
+	close(release)
+
+	if received := receive(t, payloads, n); !reflect.DeepEqual(received, expected) {
+		t.Errorf("expected (%d) events in publish order, got (%v)", n, received)
+	}
+}
+
+func TestStateEventsAreCoalesced(t *testing.T) {
+	bus := New()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler, payloads := collect()
+	err := bus.Subscribe("slow", Filter{}, func(e Event) {
+		if e.Payload == 0 {
+			close(started)
+			<-release
+		}
+		handler(e)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bus.Unsubscribe("slow")
+
+	bus.PublishState(Event{Topic: TopicSyncProgress, WalletID: 1, Payload: 0})
+	<-started
+	for i := 1; i <= 10; i++ {
+		bus.PublishState(Event{Topic: TopicSyncProgress, WalletID: 1, Payload: i})
+	}
+	bus.Publish(Event{Topic: TopicSyncCompleted, WalletID: 1, Payload: "completed"})
+	close(release)
+
+	expected := []interface{}{0, 10, "completed"}
+	if received := receive(t, payloads, len(expected)); !reflect.DeepEqual(received, expected) {
+		t.Errorf("expected (%v), got (%v)", expected, received)
+	}
+	expectNone(t, payloads)
+
+	e, ok := bus.LastState(TopicSyncProgress, 1)
+	if !ok || e.Payload != 10 {
+		t.Errorf("expected last state (%v), got (%v, %v)", 10, e.Payload, ok)
+	}
+	bus.ClearState(TopicSyncProgress, 1)
+	if _, ok := bus.LastState(TopicSyncProgress, 1); ok {
+		t.Errorf("expected no last state after ClearState")
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	bus := New()
+	handler, payloads := collect()
+	if err := bus.Subscribe("sub", Filter{}, handler); err != nil {
+		t.Fatal(err)
+	}
+
+	bus.Publish(Event{Topic: TopicBlockAttached, Payload: 1})
+	receive(t, payloads, 1)
+
+	bus.Unsubscribe("sub")
+	if bus.IsSubscribed("sub") {
+		t.Errorf("expected (%v), got (%v)", false, true)
+	}
+	bus.Publish(Event{Topic: TopicBlockAttached, Payload: 2})
+	expectNone(t, payloads)
+
+	// A handler may unsubscribe itself.
+	done := make(chan struct{})
+	err := bus.Subscribe("self", Filter{}, func(e Event) {
+		bus.Unsubscribe("self")
+		close(done)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish(Event{Topic: TopicBlockAttached})
+	select {
+	case <-done:
+	case <-time.After(waitTimeout):
+		t.Fatal("handler unsubscribing itself did not return")
+	}
+	if bus.IsSubscribed("self") {
+		t.Errorf("expected (%v), got (%v)", false, true)
+	}
+}
+
+func TestHandle(t *testing.T) {
+	errSync := errors.New("sync failed")
+	tests := []struct {
+		name    string
+		payload interface{}
+		handled bool
+	}{
+		{name: "matching payload", payload: errSync, handled: true},
+		{name: "other payload", payload: 1, handled: false},
+		{name: "no payload", payload: nil, handled: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var handled error
+			Handle(func(_ Event, err error) { handled = err })(Event{Payload: tc.payload})
+			if (handled != nil) != tc.handled {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.handled, handled != nil)
+			}
+		})
+	}
+}
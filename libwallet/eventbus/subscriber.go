@@ -0,0 +1,98 @@
+package eventbus
+
+import "sync"
+
+// subscriber queues the events of a single subscription and passes them to its
+// handler from a dedicated goroutine.
+type subscriber struct {
+	id      string
+	filter  Filter
+	handler func(Event)
+
+	mtx     sync.Mutex
+	queue   []Event
+	lagging bool
+
+	// signal has a pending value while events are waiting in the queue.
+	signal chan struct{}
+	quit   chan struct{}
+	once   sync.Once
+}
+
+func newSubscriber(id string, filter Filter, handler func(Event)) *subscriber {
+	return &subscriber{
+		id:      id,
+		filter:  filter,
+		handler: handler,
+		signal:  make(chan struct{}, 1),
+		quit:    make(chan struct{}),
+	}
+}
+
+// push queues e without waiting for the subscriber. A state event replaces
+// the state event of the same topic and wallet still waiting in the queue,
+// the other events are never dropped.
+func (s *subscriber) push(e Event) {
+	s.mtx.Lock()
+	queued := false
+	if e.state {
+		for i := range s.queue {
+			if s.queue[i].state && s.queue[i].Topic == e.Topic && s.queue[i].WalletID == e.WalletID {
+				s.queue[i] = e
+				queued = true
+				break
+			}
+		}
+	}
+	if !queued {
+		s.queue = append(s.queue, e)
+		if len(s.queue) > maxQueuedEvents && !s.lagging {
+			s.lagging = true
+			log.Warnf("Subscriber %s is not keeping up, %d events are waiting", s.id, len(s.queue))
+		}
+	}
+	s.mtx.Unlock()
+
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (s *subscriber) run() {
+	for {
+		select {
+		case <-s.quit:
+			return
+		case <-s.signal:
+		}
+
+		for {
+			s.mtx.Lock()
+			if len(s.queue) == 0 {
+				if s.lagging {
+					log.Debugf("Subscriber %s caught up", s.id)
+					s.lagging = false
+				}
+				s.mtx.Unlock()
+				break
+			}
+			e := s.queue[0]
+			s.queue = s.queue[1:]
+			s.mtx.Unlock()
+
+			select {
+			case <-s.quit:
+				return
+			default:
+			}
+			s.handler(e)
+		}
+	}
+}
+
+func (s *subscriber) stop() {
+	s.once.Do(func() {
+		close(s.quit)
+	})
+}
@@ -0,0 +1,78 @@
+package eventbus
+
+// Topic identifies a kind of event.
+type Topic string
+
+// Wallet sync topics. Their events are published with the ID of the syncing
+// wallet.
+const (
+	// TopicSyncStarted has no payload.
+	TopicSyncStarted Topic = "sync.started"
+	// TopicSyncProgress is a state event with a payload of type
+	// *wallet.CFiltersFetchProgressReport, *wallet.HeadersFetchProgressReport,
+	// *wallet.AddressDiscoveryProgressReport or
+	// *wallet.HeadersRescanProgressReport.
+	TopicSyncProgress Topic = "sync.progress"
+	// TopicPeersChanged is a state event with a payload of type int32, the
+	// number of connected peers.
+	TopicPeersChanged Topic = "sync.peers"
+	// TopicSyncCompleted has no payload.
+	TopicSyncCompleted Topic = "sync.completed"
+	// TopicSyncCanceled has a payload of type bool, true if the sync is
+	// restarted.
+	TopicSyncCanceled Topic = "sync.canceled"
+	// TopicSyncEndedWithError has a payload of type error.
+	TopicSyncEndedWithError Topic = "sync.error"
+)
+
+// Wallet transaction and block topics. Their events are published with the ID
+// of the wallet.
+const (
+	// TopicTransaction has a payload of type *wallet.Transaction.
+	TopicTransaction Topic = "tx.new"
+	// TopicTransactionConfirmed has a payload of type
+	// *wallet.TxConfirmation.
+	TopicTransactionConfirmed Topic = "tx.confirmed"
+	// TopicBlockAttached has a payload of type int32, the height of the
+	// block.
+	TopicBlockAttached Topic = "block.attached"
+)
+
+// DCR account mixer topics. Their events are published with the ID of the
+// mixing wallet.
+const (
+	// TopicMixerStarted has no payload.
+	TopicMixerStarted Topic = "mixer.started"
+	// TopicMixerEnded has no payload.
+	TopicMixerEnded Topic = "mixer.ended"
+)
+
+// Assets manager topics.
+const (
+	// TopicSyncStatus is a state event with a payload of type
+	// *libwallet.SyncStatus, the merged sync status of all the wallets.
+	TopicSyncStatus Topic = "manager.syncstatus"
+	// TopicWalletSynced has no payload. Its events are published with the ID
+	// of the synced wallet.
+	TopicWalletSynced Topic = "manager.walletsynced"
+	// TopicWalletOpenState is a state event with a payload of type
+	// *libwallet.WalletOpenStatus. Its events are published with the ID of
+	// the wallet.
+	TopicWalletOpenState Topic = "manager.walletopen"
+)
+
+// Service topics.
+const (
+	// TopicOrdersSynced has no payload.
+	TopicOrdersSynced Topic = "instantswap.synced"
+	// TopicOrderCreated has a payload of type *instantswap.Order.
+	TopicOrderCreated Topic = "instantswap.ordercreated"
+	// TopicOrderSchedulerStarted has no payload.
+	TopicOrderSchedulerStarted Topic = "instantswap.schedulerstarted"
+	// TopicOrderSchedulerEnded has no payload.
+	TopicOrderSchedulerEnded Topic = "instantswap.schedulerended"
+	// TopicProposalSync has a payload of type *politeia.ProposalSyncEvent.
+	TopicProposalSync Topic = "politeia.sync"
+	// TopicRatesUpdated has no payload.
+	TopicRatesUpdated Topic = "rates.updated"
+)
@@ -18,6 +18,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
 	"github.com/crypto-power/cryptopower/libwallet/utils"
 	"github.com/crypto-power/cryptopower/ui/values"
 )
@@ -113,6 +114,7 @@ type RateSource interface {
 	GetTicker(market string) *Ticker
 	ToggleStatus(disable bool)
 	ToggleSource(newSource string) error
+	Reconnect()
 	AddRateListener(listener *RateListener, uniqueID string) error
 	RemoveRateListener(uniqueID string)
 }
@@ -148,8 +150,7 @@ type CommonRateSource struct {
 	// wsProcessor is used to process websocket messages.
 	wsProcessor WebsocketProcessor
 
-	rateListenersMtx sync.RWMutex
-	rateListeners    map[string]*RateListener
+	events *eventbus.Bus
 }
 
 // Name is the string associated with the rate source for display.
@@ -239,6 +240,14 @@ func (cs *CommonRateSource) ToggleSource(newSource string) error {
 	return nil
 }
 
+// Reconnect closes the rates websocket and refreshes the rates, which opens a
+// new websocket connection. This method takes some time to refresh the rates
+// and should be executed a a goroutine.
+func (cs *CommonRateSource) Reconnect() {
+	cs.resetWs(nil)
+	cs.Refresh(true)
+}
+
 // resetWs resets the rate source's websocket connect and related data.
 // processor is optional.
 func (cs *CommonRateSource) resetWs(processor WebsocketProcessor) {
@@ -263,22 +272,14 @@ func (cs *CommonRateSource) AddRateListener(listener *RateListener, uniqueID str
 		return fmt.Errorf("invalid RateListener")
 	}
 
-	cs.rateListenersMtx.Lock()
-	defer cs.rateListenersMtx.Unlock()
-
-	_, ok := cs.rateListeners[uniqueID]
-	if ok {
-		return errors.New(utils.ErrListenerAlreadyExist)
-	}
-
-	cs.rateListeners[uniqueID] = listener
-	return nil
+	filter := eventbus.Filter{Topics: []eventbus.Topic{eventbus.TopicRatesUpdated}}
+	return cs.events.Subscribe("rates/"+uniqueID, filter, func(eventbus.Event) {
+		listener.OnRateUpdated()
+	})
 }
 
 func (cs *CommonRateSource) RemoveRateListener(uniqueID string) {
-	cs.rateListenersMtx.Lock()
-	defer cs.rateListenersMtx.Unlock()
-	delete(cs.rateListeners, uniqueID)
+	cs.events.Unsubscribe("rates/" + uniqueID)
 }
 
 // Log the error along with the token and an additional passed identifier.
@@ -474,11 +475,7 @@ func (cs *CommonRateSource) copyRates() map[string]*Ticker {
 }
 
 func (cs *CommonRateSource) notifyRateListeners() {
-	cs.rateListenersMtx.RLock()
-	defer cs.rateListenersMtx.RUnlock()
-	for _, l := range cs.rateListeners {
-		l.OnRateUpdated()
-	}
+	cs.events.Publish(eventbus.Event{Topic: eventbus.TopicRatesUpdated})
 }
 
 // Refresh refreshes all expired rates and reconnects the rates websocket if it
@@ -531,6 +528,11 @@ func (cs *CommonRateSource) Refresh(force bool) {
 	cs.tickers = tickers
 	cs.mtx.Unlock()
 
+	// Stand-ins of the rate source only serve the http ticker endpoints.
+	if utils.IsDevMode() {
+		return
+	}
+
 	// Check if the websocket connection is still on.
 	if cs.wsListening() {
 		return
@@ -618,7 +620,7 @@ func (cs *CommonRateSource) GetTicker(market string) *Ticker {
 }
 
 // Used to initialize a rate source.
-func NewCommonRateSource(ctx context.Context, source string) (*CommonRateSource, error) {
+func NewCommonRateSource(ctx context.Context, source string, events *eventbus.Bus) (*CommonRateSource, error) {
 	if source != binance && source != bittrex && source != none {
 		return nil, fmt.Errorf("New rate source %s is not supported", source)
 	}
@@ -640,7 +642,7 @@ func NewCommonRateSource(ctx context.Context, source string) (*CommonRateSource,
 		tickers:       make(map[string]*Ticker),
 		getTicker:     getTickerFunc,
 		wsProcessor:   wsProcessor,
-		rateListeners: make(map[string]*RateListener),
+		events:        events,
 		sourceChanged: make(chan *struct{}),
 	}
 	s.cond = sync.NewCond(&s.mtx)
@@ -663,13 +665,18 @@ func binanceGetTicker(market string) (*Ticker, error) {
 		return nil, fmt.Errorf("Market %s not supported", market)
 	}
 
+	priceURL, err := utils.ServiceURL(utils.RateSourceService, fmt.Sprintf(binanceURLs.price, market))
+	if err != nil {
+		return nil, fmt.Errorf("%s failed to fetch ticker for %s: %w", binance, market, err)
+	}
+
 	reqCfg := &utils.ReqConfig{
-		HTTPURL: fmt.Sprintf(binanceURLs.price, market),
+		HTTPURL: priceURL,
 		Method:  "GET",
 	}
 
 	resp := new(BinanceTickerResponse)
-	_, err := utils.HTTPRequest(reqCfg, &resp)
+	_, err = utils.HTTPRequest(reqCfg, &resp)
 	if err != nil {
 		return nil, fmt.Errorf("%s failed to fetch ticker for %s: %w", binance, market, err)
 	}
@@ -686,14 +693,23 @@ func binanceGetTicker(market string) (*Ticker, error) {
 }
 
 func bittrexGetTicker(market string) (*Ticker, error) {
+	priceURL, err := utils.ServiceURL(utils.RateSourceService, fmt.Sprintf(bittrexURLs.price, market))
+	if err != nil {
+		return nil, fmt.Errorf("%s failed to fetch ticker for %s: %w", bittrex, market, err)
+	}
+	statsURL, err := utils.ServiceURL(utils.RateSourceService, fmt.Sprintf(bittrexURLs.stats, market))
+	if err != nil {
+		return nil, fmt.Errorf("%s failed to fetch ticker for %s: %w", bittrex, market, err)
+	}
+
 	reqCfg := &utils.ReqConfig{
-		HTTPURL: fmt.Sprintf(bittrexURLs.price, market),
+		HTTPURL: priceURL,
 		Method:  "GET",
 	}
 
 	// Fetch current rate.
 	resp := new(BittrexTickerResponse)
-	_, err := utils.HTTPRequest(reqCfg, &resp)
+	_, err = utils.HTTPRequest(reqCfg, &resp)
 	if err != nil {
 		return nil, fmt.Errorf("%s failed to fetch ticker for %s: %w", bittrex, market, err)
 	}
@@ -704,7 +720,7 @@ func bittrexGetTicker(market string) (*Ticker, error) {
 	}
 
 	// Fetch percentage change.
-	reqCfg.HTTPURL = fmt.Sprintf(bittrexURLs.stats, market)
+	reqCfg.HTTPURL = statsURL
 	res := new(BittrexMarketSummaryResponse)
 	_, err = utils.HTTPRequest(reqCfg, &res)
 	if err != nil {
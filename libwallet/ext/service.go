@@ -61,6 +61,16 @@ func setBackend(backend, net, rawURL string) string {
 		return rawURL
 	}
 
+	// In developer mode dcrdata requests go to its stand-in. Other backends
+	// have none, so their requests are left without a host and fail.
+	if utils.IsDevMode() {
+		if backend != DcrData {
+			return ""
+		}
+		devURL, _ := utils.ServiceURL(utils.DcrdataService, rawURL)
+		return devURL
+	}
+
 	// Prepend URL scheme and authority to the URL.
 	if authority, ok := backendURL[net][backend]; ok {
 		rawURL = fmt.Sprintf("%s%s", authority, rawURL)
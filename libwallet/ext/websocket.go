@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/crypto-power/cryptopower/libwallet/utils"
 	"github.com/gorilla/websocket"
 )
 
@@ -104,8 +105,9 @@ func (client *socketClient) Close() {
 // Constructor for a socketClient, but returned as a websocketFeed.
 func newSocketConnection(cfg *socketConfig) (websocketFeed, error) {
 	dialer := &websocket.Dialer{
-		Proxy:            http.ProxyFromEnvironment, // Same as DefaultDialer.
-		HandshakeTimeout: 10 * time.Second,          // DefaultDialer is 45 seconds.
+		Proxy:            utils.ProxyFromEnvironment, // Same as DefaultDialer unless proxied.
+		NetDialContext:   utils.ProxyDialContext(utils.WebsocketSubsystem),
+		HandshakeTimeout: 10 * time.Second, // DefaultDialer is 45 seconds.
 	}
 
 	conn, resp, err := dialer.Dial(cfg.address, cfg.headers)
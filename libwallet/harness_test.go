@@ -0,0 +1,441 @@
+//go:build harness
+
+// The simnet harness runs the wallets of every asset end to end against
+// local full nodes. It needs dcrd, btcd and ltcd in PATH and is excluded from
+// normal test runs:
+//
+//	go test -tags harness -run TestSimnetHarness -v ./libwallet
+//
+// Assets whose node binary is missing are skipped. The assets manager runs
+// in developer mode without stand-ins, so no external service is contacted.
+
+package libwallet_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/crypto-power/cryptopower/libwallet"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/ltcsuite/ltcd/ltcutil"
+)
+
+const (
+	harnessPassphrase = "harness-passphrase"
+	harnessRPCUser    = "harness"
+	harnessRPCPass    = "harness"
+
+	// harnessSendAmount is the amount, in atoms, sent between wallets.
+	harnessSendAmount = 1e8
+
+	harnessTimeout = 3 * time.Minute
+)
+
+// harnessAsset describes how to run the full node of an asset on simnet.
+type harnessAsset struct {
+	assetType utils.AssetType
+	node      string
+	// minedBlocks is the number of blocks mined to the sender. Enough for
+	// the coinbases to mature and, on BTC and LTC, for segwit to activate,
+	// which takes 300 blocks on simnet.
+	minedBlocks int
+	// nodeArgs returns the node specific arguments, if any.
+	nodeArgs func(dir string) []string
+	// burnAddr returns a simnet address nobody can spend from. The node
+	// mines to it until the wallets are synced.
+	burnAddr func(hash160 []byte) (string, error)
+}
+
+var harnessAssets = []*harnessAsset{
+	{
+		assetType:   utils.DCRWalletAsset,
+		node:        "dcrd",
+		minedBlocks: 40,
+		nodeArgs: func(dir string) []string {
+			return []string{"--appdata=" + dir}
+		},
+		burnAddr: func(hash160 []byte) (string, error) {
+			addr, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(hash160, utils.DCRSimnetParams)
+			if err != nil {
+				return "", err
+			}
+			return addr.String(), nil
+		},
+	},
+	{
+		assetType:   utils.BTCWalletAsset,
+		node:        "btcd",
+		minedBlocks: 320,
+		nodeArgs: func(dir string) []string {
+			return []string{"--datadir=" + filepath.Join(dir, "data"), "--logdir=" + filepath.Join(dir, "logs")}
+		},
+		burnAddr: func(hash160 []byte) (string, error) {
+			addr, err := btcutil.NewAddressPubKeyHash(hash160, utils.BTCSimnetParams)
+			if err != nil {
+				return "", err
+			}
+			return addr.String(), nil
+		},
+	},
+	{
+		assetType:   utils.LTCWalletAsset,
+		node:        "ltcd",
+		minedBlocks: 320,
+		nodeArgs: func(dir string) []string {
+			return []string{"--datadir=" + filepath.Join(dir, "data"), "--logdir=" + filepath.Join(dir, "logs")}
+		},
+		burnAddr: func(hash160 []byte) (string, error) {
+			addr, err := ltcutil.NewAddressPubKeyHash(hash160, utils.LTCSimnetParams)
+			if err != nil {
+				return "", err
+			}
+			return addr.String(), nil
+		},
+	},
+}
+
+func TestSimnetHarness(t *testing.T) {
+	rootDir := t.TempDir()
+	mgr, err := libwallet.NewAssetsManager(rootDir, rootDir, string(utils.Simulation))
+	if err != nil {
+		t.Fatalf("NewAssetsManager error: %v", err)
+	}
+	defer mgr.Shutdown()
+
+	if err := mgr.SetDevMode(&utils.DevModeConfig{Enabled: true}); err != nil {
+		t.Fatalf("SetDevMode error: %v", err)
+	}
+
+	for _, asset := range harnessAssets {
+		asset := asset
+		t.Run(asset.assetType.ToStringLower(), func(t *testing.T) {
+			if _, err := exec.LookPath(asset.node); err != nil {
+				t.Skipf("%s not found in PATH", asset.node)
+			}
+			runAssetHarness(t, mgr, asset)
+		})
+	}
+}
+
+// runAssetHarness creates a wallet, mines coins to it, sends some of them to
+// a second wallet and restores the first wallet from its seed.
+func runAssetHarness(t *testing.T, mgr *libwallet.AssetsManager, asset *harnessAsset) {
+	// Create.
+	// Wallet names are unique across assets.
+	prefix := asset.assetType.ToStringLower() + "-"
+	sender := createHarnessWallet(t, mgr, asset.assetType, prefix+"sender")
+	receiver := createHarnessWallet(t, mgr, asset.assetType, prefix+"receiver")
+
+	// Sync. The wallets are only synced once the chain has a recent block
+	// and the BTC and LTC wallets only hand out addresses once they are
+	// connected to the chain, so the node first mines a block to a burn
+	// address and mines to the sender after a restart.
+	node := startHarnessNode(t, asset)
+	node.generate(t, 1)
+	syncHarnessWallet(t, sender, node)
+	syncHarnessWallet(t, receiver, node)
+
+	miningAddr, err := sender.CurrentAddress(0)
+	if err != nil {
+		t.Fatalf("CurrentAddress error: %v", err)
+	}
+	receiveAddr, err := receiver.CurrentAddress(0)
+	if err != nil {
+		t.Fatalf("CurrentAddress error: %v", err)
+	}
+	node.restart(t, miningAddr)
+	waitFor(t, "the wallets to reconnect", func() bool {
+		return sender.ConnectedPeers() > 0 && receiver.ConnectedPeers() > 0
+	})
+	node.mine(t, asset.minedBlocks, sender, receiver)
+	waitFor(t, "mined coins to be spendable", func() bool {
+		return spendableBalance(t, sender) > harnessSendAmount
+	})
+
+	// Send.
+	if err := sender.NewUnsignedTx(0, nil); err != nil {
+		t.Fatalf("NewUnsignedTx error: %v", err)
+	}
+	if err := sender.AddSendDestination(receiveAddr, harnessSendAmount, false); err != nil {
+		t.Fatalf("AddSendDestination error: %v", err)
+	}
+	if _, err := sender.Broadcast(harnessPassphrase, ""); err != nil {
+		t.Fatalf("Broadcast error: %v", err)
+	}
+
+	// Receive. The coins are spendable once they have the confirmations the
+	// wallet requires.
+	node.mine(t, int(receiver.RequiredConfirmations()), sender, receiver)
+	waitFor(t, "the sent coins to be received", func() bool {
+		return spendableBalance(t, receiver) == harnessSendAmount
+	})
+
+	// Restore.
+	seed, err := sender.DecryptSeed(harnessPassphrase)
+	if err != nil {
+		t.Fatalf("DecryptSeed error: %v", err)
+	}
+	restored, err := mgr.RestoreWallet(asset.assetType, prefix+"restored", seed, "", harnessPassphrase, sharedW.PassphraseTypePass)
+	if err != nil {
+		t.Fatalf("RestoreWallet error: %v", err)
+	}
+	syncHarnessWallet(t, restored, node)
+	waitFor(t, "the restored wallet to find the sender's coins", func() bool {
+		return restored.ContainsDiscoveredAccounts() && totalBalance(t, restored) == totalBalance(t, sender)
+	})
+}
+
+func createHarnessWallet(t *testing.T, mgr *libwallet.AssetsManager, assetType utils.AssetType, name string) sharedW.Asset {
+	var wallet sharedW.Asset
+	var err error
+	switch assetType {
+	case utils.DCRWalletAsset:
+		wallet, err = mgr.CreateNewDCRWallet(name, harnessPassphrase, sharedW.PassphraseTypePass)
+	case utils.BTCWalletAsset:
+		wallet, err = mgr.CreateNewBTCWallet(name, harnessPassphrase, sharedW.PassphraseTypePass)
+	case utils.LTCWalletAsset:
+		wallet, err = mgr.CreateNewLTCWallet(name, harnessPassphrase, sharedW.PassphraseTypePass)
+	default:
+		err = utils.ErrAssetUnknown
+	}
+	if err != nil {
+		t.Fatalf("error creating %s wallet: %v", assetType, err)
+	}
+	return wallet
+}
+
+// syncHarnessWallet syncs wallet with the node only and waits until it has
+// caught up with the node's best block.
+func syncHarnessWallet(t *testing.T, wallet sharedW.Asset, node *harnessNode) {
+	wallet.SetSpecificPeer(node.p2pAddr)
+	if err := wallet.SpvSync(); err != nil {
+		t.Fatalf("SpvSync error: %v", err)
+	}
+	node.waitForSync(t, wallet)
+}
+
+func spendableBalance(t *testing.T, wallet sharedW.Asset) int64 {
+	balance, err := wallet.GetAccountBalance(0)
+	if err != nil {
+		t.Fatalf("GetAccountBalance error: %v", err)
+	}
+	return balance.Spendable.ToInt()
+}
+
+func totalBalance(t *testing.T, wallet sharedW.Asset) int64 {
+	balance, err := wallet.GetWalletBalance()
+	if err != nil {
+		t.Fatalf("GetWalletBalance error: %v", err)
+	}
+	return balance.Total.ToInt()
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	deadline := time.Now().Add(harnessTimeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// harnessNode is a full node running on simnet.
+type harnessNode struct {
+	asset   *harnessAsset
+	dir     string
+	p2pAddr string
+	rpcAddr string
+	cmd     *exec.Cmd
+	logFile *os.File
+}
+
+// startHarnessNode starts the simnet node of the asset. The node is stopped
+// when the test ends.
+func startHarnessNode(t *testing.T, asset *harnessAsset) *harnessNode {
+	node := &harnessNode{
+		asset:   asset,
+		dir:     t.TempDir(),
+		p2pAddr: freeLocalAddr(t),
+		rpcAddr: freeLocalAddr(t),
+	}
+	t.Cleanup(node.stop)
+
+	hash160 := make([]byte, 20)
+	if _, err := rand.Read(hash160); err != nil {
+		t.Fatal(err)
+	}
+	burnAddr, err := asset.burnAddr(hash160)
+	if err != nil {
+		t.Fatalf("error creating the burn address: %v", err)
+	}
+	node.start(t, burnAddr)
+	return node
+}
+
+// start runs the node, mining to miningAddr.
+func (node *harnessNode) start(t *testing.T, miningAddr string) {
+	args := append([]string{
+		"--simnet",
+		"--listen=" + node.p2pAddr,
+		"--rpclisten=" + node.rpcAddr,
+		"--rpcuser=" + harnessRPCUser,
+		"--rpcpass=" + harnessRPCPass,
+		"--notls",
+		"--miningaddr=" + miningAddr,
+	}, node.asset.nodeArgs(node.dir)...)
+
+	logFile, err := os.OpenFile(filepath.Join(node.dir, node.asset.node+".log"),
+		os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node.logFile = logFile
+	node.cmd = exec.Command(node.asset.node, args...)
+	node.cmd.Stdout = logFile
+	node.cmd.Stderr = logFile
+	if err := node.cmd.Start(); err != nil {
+		t.Fatalf("error starting %s: %v", node.asset.node, err)
+	}
+
+	waitFor(t, node.asset.node+" to start", func() bool {
+		return node.call("getblockcount", nil) == nil
+	})
+}
+
+// restart restarts the node on the same chain, mining to miningAddr. The
+// wallets reconnect to it as it is their persistent peer.
+func (node *harnessNode) restart(t *testing.T, miningAddr string) {
+	node.stop()
+	node.start(t, miningAddr)
+}
+
+func (node *harnessNode) stop() {
+	if node.cmd == nil {
+		return
+	}
+	_ = node.cmd.Process.Signal(os.Interrupt)
+	done := make(chan error, 1)
+	go func() { done <- node.cmd.Wait() }()
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		_ = node.cmd.Process.Kill()
+		<-done
+	}
+	node.logFile.Close()
+	node.cmd = nil
+}
+
+// waitForSync waits until wallet has caught up with the best block of the
+// node.
+func (node *harnessNode) waitForSync(t *testing.T, wallet sharedW.Asset) {
+	waitFor(t, wallet.GetWalletName()+" to sync", func() bool {
+		return wallet.IsSynced() && wallet.GetBestBlockHeight() >= node.bestHeight(t)
+	})
+}
+
+// mine mines n blocks in small batches, letting the wallets catch up after
+// each batch. Wallets syncing from a single peer miss blocks that are mined
+// faster than they can process them.
+func (node *harnessNode) mine(t *testing.T, n int, wallets ...sharedW.Asset) {
+	const batchSize = 10
+	for n > 0 {
+		batch := batchSize
+		if n < batch {
+			batch = n
+		}
+		node.generate(t, batch)
+		for _, wallet := range wallets {
+			node.waitForSync(t, wallet)
+		}
+		n -= batch
+	}
+}
+
+// generate mines n blocks.
+func (node *harnessNode) generate(t *testing.T, n int) {
+	if err := node.call("generate", nil, n); err != nil {
+		t.Fatalf("generate error: %v", err)
+	}
+}
+
+func (node *harnessNode) bestHeight(t *testing.T) int32 {
+	var height int64
+	if err := node.call("getblockcount", &height); err != nil {
+		t.Fatalf("getblockcount error: %v", err)
+	}
+	return int32(height)
+}
+
+// call makes a JSON-RPC request to the node and decodes its result into
+// result, if not nil.
+func (node *harnessNode) call(method string, result interface{}, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	reqBody, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "1.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, "http://"+node.rpcAddr, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(harnessRPCUser, harnessRPCPass)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var rpcResp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return fmt.Errorf("%s: %s", method, resp.Status)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("%s: %s (%d)", method, rpcResp.Error.Message, rpcResp.Error.Code)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(rpcResp.Result, result)
+}
+
+// freeLocalAddr returns a local address with a port that is free to listen
+// on.
+func freeLocalAddr(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return "127.0.0.1:" + strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+}
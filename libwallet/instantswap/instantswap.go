@@ -3,12 +3,12 @@ package instantswap
 import (
 	"context"
 	"fmt"
-	"sync"
 	"time"
 
 	"decred.org/dcrwallet/v3/errors"
 	"github.com/asdine/storm"
 	"github.com/asdine/storm/q"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
 	"github.com/crypto-power/instantswap/instantswap"
 
 	// Initialize exchange servers.
@@ -29,7 +29,7 @@ const (
 		"RN5HO3QyH9PMXZy7n3CUQhF40cYWY2zg==a44e77479feb30c28481c020bce2a3b3"
 )
 
-func NewInstantSwap(db *storm.DB) (*InstantSwap, error) {
+func NewInstantSwap(db *storm.DB, events *eventbus.Bus) (*InstantSwap, error) {
 	if err := db.Init(&Order{}); err != nil {
 		log.Errorf("Error initializing instantSwap database: %s", err.Error())
 		return nil, err
@@ -41,11 +41,9 @@ func NewInstantSwap(db *storm.DB) (*InstantSwap, error) {
 	ctx := context.TODO()
 
 	return &InstantSwap{
-		db:  db,
-		ctx: ctx,
-
-		notificationListenersMu: &sync.RWMutex{},
-		notificationListeners:   make(map[string]*OrderNotificationListener),
+		db:     db,
+		ctx:    ctx,
+		events: events,
 	}, nil
 }
 
@@ -6,6 +6,7 @@ import (
 
 	"decred.org/dcrwallet/v3/errors"
 	"github.com/asdine/storm"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
 	"github.com/crypto-power/instantswap/instantswap"
 )
 
@@ -182,66 +183,54 @@ func (instantSwap *InstantSwap) checkForUpdates(exchangeObject instantswap.IDExc
 }
 
 func (instantSwap *InstantSwap) publishSynced() {
-	instantSwap.notificationListenersMu.Lock()
-	defer instantSwap.notificationListenersMu.Unlock()
-
-	for _, notificationListener := range instantSwap.notificationListeners {
-		if notificationListener.OnExchangeOrdersSynced != nil {
-			notificationListener.OnExchangeOrdersSynced()
-		}
-	}
+	instantSwap.events.Publish(eventbus.Event{Topic: eventbus.TopicOrdersSynced})
 }
 
 func (instantSwap *InstantSwap) publishOrderCreated(order *Order) {
-	instantSwap.notificationListenersMu.Lock()
-	defer instantSwap.notificationListenersMu.Unlock()
-
-	for _, notificationListener := range instantSwap.notificationListeners {
-		if notificationListener.OnOrderCreated != nil {
-			notificationListener.OnOrderCreated(order)
-		}
-	}
+	instantSwap.events.Publish(eventbus.Event{Topic: eventbus.TopicOrderCreated, Payload: order})
 }
 
 func (instantSwap *InstantSwap) PublishOrderSchedulerStarted() {
-	instantSwap.notificationListenersMu.Lock()
-	defer instantSwap.notificationListenersMu.Unlock()
-
-	for _, notificationListener := range instantSwap.notificationListeners {
-		if notificationListener.OnOrderSchedulerStarted != nil {
-			notificationListener.OnOrderSchedulerStarted()
-		}
-	}
+	instantSwap.events.Publish(eventbus.Event{Topic: eventbus.TopicOrderSchedulerStarted})
 }
 
 func (instantSwap *InstantSwap) PublishOrderSchedulerEnded() {
-	instantSwap.notificationListenersMu.Lock()
-	defer instantSwap.notificationListenersMu.Unlock()
-
-	for _, notificationListener := range instantSwap.notificationListeners {
-		if notificationListener.OnOrderSchedulerEnded != nil {
-			notificationListener.OnOrderSchedulerEnded()
-		}
-	}
+	instantSwap.events.Publish(eventbus.Event{Topic: eventbus.TopicOrderSchedulerEnded})
 }
 
 func (instantSwap *InstantSwap) AddNotificationListener(notificationListener *OrderNotificationListener, uniqueIdentifier string) error {
-	instantSwap.notificationListenersMu.Lock()
-	defer instantSwap.notificationListenersMu.Unlock()
-
-	if _, ok := instantSwap.notificationListeners[uniqueIdentifier]; ok {
-		return errors.New(ErrListenerAlreadyExist)
+	filter := eventbus.Filter{
+		Topics: []eventbus.Topic{
+			eventbus.TopicOrdersSynced,
+			eventbus.TopicOrderCreated,
+			eventbus.TopicOrderSchedulerStarted,
+			eventbus.TopicOrderSchedulerEnded,
+		},
 	}
-
-	instantSwap.notificationListeners[uniqueIdentifier] = notificationListener
-	return nil
+	return instantSwap.events.Subscribe("instantswap/"+uniqueIdentifier, filter, func(e eventbus.Event) {
+		switch e.Topic {
+		case eventbus.TopicOrdersSynced:
+			if notificationListener.OnExchangeOrdersSynced != nil {
+				notificationListener.OnExchangeOrdersSynced()
+			}
+		case eventbus.TopicOrderCreated:
+			if notificationListener.OnOrderCreated != nil {
+				notificationListener.OnOrderCreated(e.Payload.(*Order))
+			}
+		case eventbus.TopicOrderSchedulerStarted:
+			if notificationListener.OnOrderSchedulerStarted != nil {
+				notificationListener.OnOrderSchedulerStarted()
+			}
+		case eventbus.TopicOrderSchedulerEnded:
+			if notificationListener.OnOrderSchedulerEnded != nil {
+				notificationListener.OnOrderSchedulerEnded()
+			}
+		}
+	})
 }
 
 func (instantSwap *InstantSwap) RemoveNotificationListener(uniqueIdentifier string) {
-	instantSwap.notificationListenersMu.Lock()
-	defer instantSwap.notificationListenersMu.Unlock()
-
-	delete(instantSwap.notificationListeners, uniqueIdentifier)
+	instantSwap.events.Unsubscribe("instantswap/" + uniqueIdentifier)
 }
 
 func (instantSwap *InstantSwap) GetLastSyncedTimeStamp() int64 {
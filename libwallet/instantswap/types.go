@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/asdine/storm"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
 	"github.com/crypto-power/instantswap/instantswap"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -60,8 +61,7 @@ type InstantSwap struct {
 	CancelOrderSchedulerMu sync.RWMutex
 	SchedulerStartTime     time.Time
 
-	notificationListenersMu *sync.RWMutex // Pointer required to avoid copying literal values.
-	notificationListeners   map[string]*OrderNotificationListener
+	events *eventbus.Bus
 }
 
 type OrderNotificationListener struct {
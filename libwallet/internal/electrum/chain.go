@@ -0,0 +1,1046 @@
+package electrum
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// chunkSize is the number of headers fetched and cached at once, the
+	// most servers return per request.
+	chunkSize = 2016
+
+	// cachedChunks is the number of header chunks kept in memory.
+	cachedChunks = 8
+
+	// reorgDepth is the depth below which headers are no longer expected to
+	// change and are cached in chunks.
+	reorgDepth = 6
+
+	// recentBlocks is the number of blocks below the tip that are tracked to
+	// detect reorgs. The transactions mined deeper are no longer cached.
+	recentBlocks = 100
+
+	// maxRequests is the number of requests made concurrently when many
+	// addresses are subscribed or looked up at once.
+	maxRequests = 16
+
+	// reconnectInterval is the interval between attempts to reconnect to the
+	// servers after the connection is lost.
+	reconnectInterval = 10 * time.Second
+)
+
+// Tx is a transaction decoded by the chain's TxDecoder.
+type Tx struct {
+	Hash chainhash.Hash
+	// Inputs are the hashes of the transactions the transaction spends from.
+	Inputs []chainhash.Hash
+	// Msg is the chain's decoded transaction.
+	Msg interface{}
+}
+
+// TxDecoder decodes a serialized transaction.
+type TxDecoder func(raw []byte) (*Tx, error)
+
+// Notifier receives the chain events of a Chain.
+type Notifier interface {
+	BlockConnected(header *BlockHeader)
+	BlockDisconnected(header *BlockHeader)
+	// RelevantTx is called with the transactions of the watched scripts.
+	// The block is nil for unmined transactions.
+	RelevantTx(tx *Tx, block *BlockHeader)
+}
+
+// ChainConfig is the configuration of a Chain.
+type ChainConfig struct {
+	Servers  []ServerConfig
+	Dial     DialFunc
+	Params   *ChainParams
+	DecodeTx TxDecoder
+	Notifier Notifier
+}
+
+// headerChunk is a run of consecutive cached block headers.
+type headerChunk struct {
+	start   int32
+	headers []*BlockHeader
+	heights map[chainhash.Hash]int32
+}
+
+// history is the cached history of a script hash and the status it was
+// fetched at.
+type history struct {
+	status string
+	items  []*HistoryItem
+}
+
+// Chain follows the chain of Electrum servers and finds the transactions of
+// the watched scripts from their history, which the servers index. Blocks
+// are never downloaded. The headers served are checked to build a chain
+// with valid proof of work from a checkpoint and the mined transactions are
+// checked to be in their block with merkle proofs.
+type Chain struct {
+	cfg ChainConfig
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	stopped  uint32
+	notifyOn uint32
+
+	// deliverMtx serializes the delivery of relevant transactions.
+	deliverMtx sync.Mutex
+
+	mtx    sync.RWMutex
+	client *Client
+	tip    *BlockHeader
+	recent map[int32]*BlockHeader
+	chunks []*headerChunk
+	// trusted are the hashes of the blocks known to be in the verified
+	// chain, the checkpoints and the blocks around the verified chunks.
+	trusted   map[int32]chainhash.Hash
+	watched   map[string]struct{}
+	statuses  map[string]string
+	histories map[string]*history
+	txs       map[chainhash.Hash]*Tx
+	delivered map[chainhash.Hash]int32
+}
+
+// NewChain returns a Chain that is not connected yet.
+func NewChain(cfg ChainConfig) *Chain {
+	c := &Chain{
+		cfg:       cfg,
+		recent:    make(map[int32]*BlockHeader),
+		trusted:   make(map[int32]chainhash.Hash),
+		watched:   make(map[string]struct{}),
+		statuses:  make(map[string]string),
+		histories: make(map[string]*history),
+		txs:       make(map[chainhash.Hash]*Tx),
+		delivered: make(map[chainhash.Hash]int32),
+	}
+	for _, checkpoint := range cfg.Params.Checkpoints {
+		c.trusted[checkpoint.Height] = checkpoint.Hash
+	}
+	return c
+}
+
+// Start connects to the first reachable server and verifies its chain.
+func (c *Chain) Start() error {
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	if err := c.connect(); err != nil {
+		c.cancel()
+		return err
+	}
+
+	c.wg.Add(1)
+	go c.run()
+	return nil
+}
+
+// Stop disconnects from the server.
+func (c *Chain) Stop() {
+	if !atomic.CompareAndSwapUint32(&c.stopped, 0, 1) {
+		return
+	}
+
+	c.cancel()
+	c.mtx.RLock()
+	c.client.Close()
+	c.mtx.RUnlock()
+}
+
+// WaitForShutdown blocks until the chain is stopped.
+func (c *Chain) WaitForShutdown() {
+	c.wg.Wait()
+}
+
+// Done returns a channel that is closed when the chain is stopped.
+func (c *Chain) Done() <-chan struct{} {
+	return c.ctx.Done()
+}
+
+// connect connects to a server, tracks its tip and resubscribes the watched
+// script hashes.
+func (c *Chain) connect() error {
+	client, err := Connect(c.ctx, c.cfg.Servers, c.cfg.Dial)
+	if err != nil {
+		return err
+	}
+
+	tip, err := client.SubscribeHeaders(c.ctx)
+	if err != nil {
+		client.Close()
+		return err
+	}
+
+	c.mtx.Lock()
+	reconnected := c.client != nil
+	c.client = client
+	c.histories = make(map[string]*history)
+	scriptHashes := make([]string, 0, len(c.watched))
+	for scriptHash := range c.watched {
+		scriptHashes = append(scriptHashes, scriptHash)
+	}
+	c.mtx.Unlock()
+
+	if !reconnected {
+		if err := c.verifyChain(tip.Height); err != nil {
+			client.Close()
+			return err
+		}
+		return nil
+	}
+
+	// Blocks and transactions missed while disconnected are caught up.
+	if err := c.handleTip(tip); err != nil {
+		return err
+	}
+	if err := c.subscribe(scriptHashes); err != nil {
+		return err
+	}
+	return c.deliver(c.activeScriptHashes(scriptHashes), 0, false)
+}
+
+// verifyChain verifies the server's headers from the highest checkpoint to
+// the tip and tracks the blocks near the tip.
+func (c *Chain) verifyChain(tipHeight int32) error {
+	var checkpoint *Checkpoint
+	for i := range c.cfg.Params.Checkpoints {
+		cp := &c.cfg.Params.Checkpoints[i]
+		if cp.Height <= tipHeight && (checkpoint == nil || cp.Height > checkpoint.Height) {
+			checkpoint = cp
+		}
+	}
+	if checkpoint == nil {
+		return errors.New("no checkpoint below the electrum server's tip")
+	}
+
+	log.Infof("Verifying electrum block headers from block %d to %d", checkpoint.Height, tipHeight)
+
+	var prev *BlockHeader
+	for start := checkpoint.Height - checkpoint.Height%chunkSize; start <= tipHeight; start += chunkSize {
+		count := tipHeight - start + 1
+		if count > chunkSize {
+			count = chunkSize
+		}
+		headers, err := c.fetchHeaders(start, count)
+		if err != nil {
+			return err
+		}
+		if err := c.verifyChunk(prev, headers, nil); err != nil {
+			return err
+		}
+		prev = headers[len(headers)-1]
+
+		c.mtx.Lock()
+		for _, header := range headers {
+			if header.Height > tipHeight-recentBlocks {
+				c.recent[header.Height] = header
+			}
+		}
+		c.mtx.Unlock()
+	}
+
+	c.mtx.Lock()
+	c.tip = prev
+	c.mtx.Unlock()
+	return nil
+}
+
+// fetchHeaders fetches count headers from the start height.
+func (c *Chain) fetchHeaders(start, count int32) ([]*BlockHeader, error) {
+	c.mtx.RLock()
+	client := c.client
+	c.mtx.RUnlock()
+
+	headersHex, err := client.BlockHeaders(c.ctx, start, count)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := ParseHeaders(headersHex, start)
+	if err != nil {
+		return nil, err
+	}
+	if int32(len(headers)) != count {
+		return nil, fmt.Errorf("electrum server returned %d headers from block %d, expected %d",
+			len(headers), start, count)
+	}
+	return headers, nil
+}
+
+// fetchHeader fetches the header at the height.
+func (c *Chain) fetchHeader(height int32) (*BlockHeader, error) {
+	c.mtx.RLock()
+	client := c.client
+	c.mtx.RUnlock()
+
+	headerHex, err := client.BlockHeader(c.ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := ParseHeaders(headerHex, height)
+	if err != nil {
+		return nil, err
+	}
+	if len(headers) != 1 {
+		return nil, errors.New("invalid electrum block header")
+	}
+	return headers[0], nil
+}
+
+// verifyChunk checks the consecutive headers, that connect to prev and next
+// if they are not nil, and that they are anchored to a trusted block. The
+// blocks around the headers are then trusted, which anchors the adjacent
+// chunks.
+func (c *Chain) verifyChunk(prev *BlockHeader, headers []*BlockHeader, next *BlockHeader) error {
+	params := c.cfg.Params
+	if err := params.checkHeaders(prev, headers); err != nil {
+		return err
+	}
+	first, last := headers[0], headers[len(headers)-1]
+	if next != nil {
+		if err := params.checkConnects(last, next); err != nil {
+			return err
+		}
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	anchored := prev != nil || next != nil
+	if hash, ok := c.trusted[first.Height-1]; ok {
+		if hash != first.PrevBlock {
+			return fmt.Errorf("block %d does not connect to the verified chain", first.Height)
+		}
+		anchored = true
+	}
+	for _, header := range headers {
+		if hash, ok := c.trusted[header.Height]; ok {
+			if hash != header.Hash {
+				return fmt.Errorf("block %d is not in the verified chain", header.Height)
+			}
+			anchored = true
+		}
+	}
+	if !anchored {
+		return fmt.Errorf("blocks %d to %d are not anchored to the verified chain", first.Height, last.Height)
+	}
+
+	if first.Height > 0 {
+		c.trusted[first.Height-1] = first.PrevBlock
+	}
+	c.trusted[last.Height] = last.Hash
+	return nil
+}
+
+// run handles the notifications of the server and reconnects when the
+// connection is lost.
+func (c *Chain) run() {
+	defer c.wg.Done()
+
+	for {
+		c.mtx.RLock()
+		client := c.client
+		c.mtx.RUnlock()
+
+		select {
+		case <-c.ctx.Done():
+			return
+
+		case header := <-client.Headers():
+			if err := c.handleTip(header); err != nil {
+				log.Errorf("Unable to process electrum block %d: %v", header.Height, err)
+			}
+
+		case status := <-client.ScriptHashes():
+			c.mtx.Lock()
+			c.statuses[status.ScriptHash] = status.Status
+			c.mtx.Unlock()
+			if err := c.deliver([]string{status.ScriptHash}, 0, false); err != nil {
+				log.Errorf("Unable to process electrum address history: %v", err)
+			}
+
+		case <-client.Done():
+			log.Warnf("Lost connection to electrum server %s", client.Server().Address)
+			c.reconnect()
+		}
+	}
+}
+
+func (c *Chain) reconnect() {
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(reconnectInterval):
+		}
+
+		err := c.connect()
+		if err == nil {
+			return
+		}
+		log.Warnf("Unable to reconnect to electrum servers: %v", err)
+	}
+}
+
+// handleTip connects the new tip and the blocks leading to it, disconnecting
+// the blocks of the tracked chain it does not build on.
+func (c *Chain) handleTip(tip *Header) error {
+	headers, err := ParseHeaders(tip.Hex, tip.Height)
+	if err != nil {
+		return err
+	}
+	if len(headers) != 1 {
+		return errors.New("invalid electrum block header")
+	}
+	header := headers[0]
+
+	c.mtx.RLock()
+	prevTip := c.tip
+	c.mtx.RUnlock()
+
+	if header.Hash == prevTip.Hash {
+		return nil
+	}
+
+	// Walk back from the new tip to the most recent tracked block it builds
+	// on, fetching the blocks in between.
+	attached := []*BlockHeader{header}
+	var fork *BlockHeader
+	for height := tip.Height - 1; ; height-- {
+		if height < 0 || tip.Height-height > chunkSize {
+			return fmt.Errorf("electrum server's block %d does not build on the verified chain", tip.Height)
+		}
+
+		c.mtx.RLock()
+		known, ok := c.recent[height]
+		c.mtx.RUnlock()
+		deep := !ok && height <= prevTip.Height
+		if deep {
+			// Deeper than the tracked blocks, the verified headers must not
+			// change.
+			if known, err = c.Header(height); err != nil {
+				return err
+			}
+		}
+		if known != nil && known.Hash == attached[0].PrevBlock {
+			fork = known
+			break
+		}
+		if deep {
+			return fmt.Errorf("electrum server reorganized block %d, deeper than tracked", height)
+		}
+
+		header, err := c.fetchHeader(height)
+		if err != nil {
+			return err
+		}
+		attached = append([]*BlockHeader{header}, attached...)
+	}
+	if err := c.cfg.Params.checkHeaders(fork, attached); err != nil {
+		return err
+	}
+
+	var detached []*BlockHeader
+	c.mtx.Lock()
+	for height := prevTip.Height; height > fork.Height; height-- {
+		if header, ok := c.recent[height]; ok {
+			detached = append(detached, header)
+			delete(c.recent, height)
+		}
+	}
+	for _, header := range attached {
+		c.recent[header.Height] = header
+	}
+	for height := range c.recent {
+		if height <= tip.Height-recentBlocks {
+			delete(c.recent, height)
+		}
+	}
+	c.tip = header
+
+	// Cached headers and transactions above the fork are no longer valid.
+	chunks := c.chunks[:0]
+	for _, chunk := range c.chunks {
+		if chunk.start+int32(len(chunk.headers)) <= fork.Height+1 {
+			chunks = append(chunks, chunk)
+		}
+	}
+	c.chunks = chunks
+	for height := range c.trusted {
+		if height > fork.Height {
+			delete(c.trusted, height)
+		}
+	}
+	for _, header := range attached {
+		// The last blocks of the chunks anchor the chunks once they are
+		// deep enough to be cached.
+		if header.Height%chunkSize == chunkSize-1 {
+			c.trusted[header.Height] = header.Hash
+		}
+	}
+	for txHash, height := range c.delivered {
+		if height > fork.Height {
+			delete(c.delivered, txHash)
+		}
+	}
+	c.evictTxs(tip.Height - recentBlocks)
+	c.mtx.Unlock()
+
+	if atomic.LoadUint32(&c.notifyOn) == 0 {
+		return nil
+	}
+	for _, header := range detached {
+		c.cfg.Notifier.BlockDisconnected(header)
+	}
+	for _, header := range attached {
+		c.cfg.Notifier.BlockConnected(header)
+	}
+	return nil
+}
+
+// evictTxs forgets the transactions mined at or below the final height. They
+// are no longer expected to be reorganized and the wallet has them. The
+// cached transactions that were not delivered are dropped too. The mutex
+// must be held.
+func (c *Chain) evictTxs(finalHeight int32) {
+	for txHash, height := range c.delivered {
+		if height > 0 && height <= finalHeight {
+			delete(c.delivered, txHash)
+		}
+	}
+	for txHash := range c.txs {
+		if _, ok := c.delivered[txHash]; !ok {
+			delete(c.txs, txHash)
+		}
+	}
+}
+
+// Header returns the verified header of the main chain block at the height.
+func (c *Chain) Header(height int32) (*BlockHeader, error) {
+	c.mtx.RLock()
+	tipHeight := c.tip.Height
+	header, ok := c.recent[height]
+	if !ok {
+		for _, chunk := range c.chunks {
+			if height >= chunk.start && height < chunk.start+int32(len(chunk.headers)) {
+				header, ok = chunk.headers[height-chunk.start], true
+				break
+			}
+		}
+	}
+	c.mtx.RUnlock()
+
+	switch {
+	case ok:
+		return header, nil
+	case height < 0 || height > tipHeight-reorgDepth:
+		return nil, fmt.Errorf("block height %d out of range", height)
+	}
+
+	start := height - height%chunkSize
+	chunk, err := c.verifiedChunk(start, tipHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mtx.Lock()
+	c.chunks = append(c.chunks, chunk)
+	if len(c.chunks) > cachedChunks {
+		c.chunks = c.chunks[1:]
+	}
+	c.mtx.Unlock()
+
+	return chunk.headers[height-start], nil
+}
+
+// verifiedChunk fetches the chunk of headers from the start height. The chunk
+// must connect to the verified chain, so the chunks between it and the
+// nearest trusted block above are verified first.
+func (c *Chain) verifiedChunk(start, tipHeight int32) (*headerChunk, error) {
+	// Headers above the reorg depth are not cached.
+	lastHeight := tipHeight - reorgDepth
+	count := func(start int32) int32 {
+		if start+chunkSize-1 > lastHeight {
+			return lastHeight - start + 1
+		}
+		return chunkSize
+	}
+
+	c.mtx.RLock()
+	anchor := int32(-1)
+	for height := range c.trusted {
+		if height >= start-1 && height <= lastHeight && (anchor < 0 || height < anchor) {
+			anchor = height
+		}
+	}
+	c.mtx.RUnlock()
+	if anchor < 0 {
+		return nil, fmt.Errorf("block %d is not anchored to the verified chain", start)
+	}
+
+	// Walk down from the chunk of the anchor, each verified chunk anchoring
+	// the one below.
+	var next *BlockHeader
+	for s := anchor - anchor%chunkSize; s > start; s -= chunkSize {
+		headers, err := c.fetchHeaders(s, count(s))
+		if err != nil {
+			return nil, err
+		}
+		if err := c.verifyChunk(nil, headers, next); err != nil {
+			return nil, err
+		}
+		next = headers[0]
+	}
+
+	headers, err := c.fetchHeaders(start, count(start))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.verifyChunk(nil, headers, next); err != nil {
+		return nil, err
+	}
+
+	chunk := &headerChunk{
+		start:   start,
+		headers: headers,
+		heights: make(map[chainhash.Hash]int32, len(headers)),
+	}
+	for _, header := range headers {
+		chunk.heights[header.Hash] = header.Height
+	}
+	return chunk, nil
+}
+
+// BlockHeight returns the height of a cached block.
+func (c *Chain) BlockHeight(hash *chainhash.Hash) (int32, bool) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	for height, header := range c.recent {
+		if header.Hash == *hash {
+			return height, true
+		}
+	}
+	for _, chunk := range c.chunks {
+		if height, ok := chunk.heights[*hash]; ok {
+			return height, true
+		}
+	}
+	return -1, false
+}
+
+// Tip returns the header of the server's verified tip.
+func (c *Chain) Tip() *BlockHeader {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return c.tip
+}
+
+// IsCurrent returns true if the chain is connected to a server.
+func (c *Chain) IsCurrent() bool {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	select {
+	case <-c.client.Done():
+		return false
+	default:
+		return c.tip.Height > 0
+	}
+}
+
+// Broadcast publishes the serialized transaction through the server.
+func (c *Chain) Broadcast(rawTx []byte) (*chainhash.Hash, error) {
+	c.mtx.RLock()
+	client := c.client
+	c.mtx.RUnlock()
+
+	txHash, err := client.Broadcast(c.ctx, hex.EncodeToString(rawTx))
+	if err != nil {
+		return nil, err
+	}
+	return chainhash.NewHashFromStr(txHash)
+}
+
+// NotifyBlocks starts the delivery of block notifications.
+func (c *Chain) NotifyBlocks() {
+	atomic.StoreUint32(&c.notifyOn, 1)
+}
+
+// NotifyReceived watches the output scripts and delivers their existing
+// transactions.
+func (c *Chain) NotifyReceived(pkScripts [][]byte) error {
+	scriptHashes, err := c.watch(pkScripts)
+	if err != nil {
+		return err
+	}
+	return c.deliver(c.activeScriptHashes(scriptHashes), 0, false)
+}
+
+// Rescan watches the output scripts and delivers their transactions from the
+// start block. Electrum servers index script histories, so blocks are not
+// scanned and the whole history is delivered if the start block is unknown.
+func (c *Chain) Rescan(startHash *chainhash.Hash, pkScripts [][]byte) error {
+	scriptHashes, err := c.watch(pkScripts)
+	if err != nil {
+		return err
+	}
+
+	startHeight, _ := c.BlockHeight(startHash)
+	if startHeight < 0 {
+		startHeight = 0
+	}
+	return c.deliver(c.activeScriptHashes(scriptHashes), startHeight, true)
+}
+
+// BlockTxs returns the index of the first of the heights that has
+// transactions of the output scripts, and the transactions in the order
+// they spend from each other. The index is -1 if no block has any.
+func (c *Chain) BlockTxs(heights []int32, pkScripts [][]byte) (int, []*Tx, error) {
+	batchIndex := make(map[int32]int, len(heights))
+	for i, height := range heights {
+		batchIndex[height] = i
+	}
+
+	scriptHashes, err := c.watch(pkScripts)
+	if err != nil {
+		return -1, nil, err
+	}
+	histories, err := c.fetchHistories(c.activeScriptHashes(scriptHashes))
+	if err != nil {
+		return -1, nil, err
+	}
+
+	index := -1
+	txHashes := make(map[string]struct{})
+	for _, items := range histories {
+		for _, item := range items {
+			i, ok := batchIndex[item.Height]
+			if !ok || (index >= 0 && i > index) {
+				continue
+			}
+			if i < index || index < 0 {
+				index = i
+				txHashes = make(map[string]struct{})
+			}
+			txHashes[item.TxHash] = struct{}{}
+		}
+	}
+	if index < 0 {
+		return -1, nil, nil
+	}
+
+	header, err := c.Header(heights[index])
+	if err != nil {
+		return -1, nil, err
+	}
+	txs := make([]*Tx, 0, len(txHashes))
+	for txHash := range txHashes {
+		tx, err := c.minedTransaction(txHash, header)
+		if err != nil {
+			return -1, nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return index, sortByDependency(txs), nil
+}
+
+// watch subscribes the output scripts that are not yet watched and returns
+// the script hashes of all the scripts.
+func (c *Chain) watch(pkScripts [][]byte) ([]string, error) {
+	scriptHashes := make([]string, 0, len(pkScripts))
+	var unwatched []string
+	c.mtx.Lock()
+	for _, pkScript := range pkScripts {
+		scriptHash := ScriptHash(pkScript)
+		scriptHashes = append(scriptHashes, scriptHash)
+		if _, ok := c.watched[scriptHash]; !ok {
+			c.watched[scriptHash] = struct{}{}
+			unwatched = append(unwatched, scriptHash)
+		}
+	}
+	c.mtx.Unlock()
+
+	return scriptHashes, c.subscribe(unwatched)
+}
+
+// subscribe subscribes the script hashes and records their statuses.
+func (c *Chain) subscribe(scriptHashes []string) error {
+	c.mtx.RLock()
+	client := c.client
+	c.mtx.RUnlock()
+
+	g, ctx := errgroup.WithContext(c.ctx)
+	g.SetLimit(maxRequests)
+	for _, scriptHash := range scriptHashes {
+		scriptHash := scriptHash
+		g.Go(func() error {
+			status, err := client.SubscribeScriptHash(ctx, scriptHash)
+			if err != nil {
+				return err
+			}
+			c.mtx.Lock()
+			c.statuses[scriptHash] = status
+			c.mtx.Unlock()
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// activeScriptHashes returns the script hashes that have a history.
+func (c *Chain) activeScriptHashes(scriptHashes []string) []string {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	active := make([]string, 0, len(scriptHashes))
+	for _, scriptHash := range scriptHashes {
+		if c.statuses[scriptHash] != "" {
+			active = append(active, scriptHash)
+		}
+	}
+	return active
+}
+
+// fetchHistories returns the histories of the script hashes. Histories are
+// only fetched again when their status changes.
+func (c *Chain) fetchHistories(scriptHashes []string) (map[string][]*HistoryItem, error) {
+	c.mtx.RLock()
+	client := c.client
+	c.mtx.RUnlock()
+
+	var mtx sync.Mutex
+	histories := make(map[string][]*HistoryItem, len(scriptHashes))
+	g, ctx := errgroup.WithContext(c.ctx)
+	g.SetLimit(maxRequests)
+	for _, scriptHash := range scriptHashes {
+		scriptHash := scriptHash
+		g.Go(func() error {
+			c.mtx.RLock()
+			status, cached := c.statuses[scriptHash], c.histories[scriptHash]
+			c.mtx.RUnlock()
+
+			items := []*HistoryItem(nil)
+			if cached != nil && cached.status == status {
+				items = cached.items
+			} else {
+				var err error
+				if items, err = client.ScriptHashHistory(ctx, scriptHash); err != nil {
+					return err
+				}
+				c.mtx.Lock()
+				c.histories[scriptHash] = &history{status: status, items: items}
+				c.mtx.Unlock()
+			}
+
+			mtx.Lock()
+			histories[scriptHash] = items
+			mtx.Unlock()
+			return nil
+		})
+	}
+	return histories, g.Wait()
+}
+
+// transaction returns the transaction with the hash, checking that the
+// server returned the requested transaction.
+func (c *Chain) transaction(txHash string) (*Tx, error) {
+	hash, err := chainhash.NewHashFromStr(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mtx.RLock()
+	client, tx := c.client, c.txs[*hash]
+	c.mtx.RUnlock()
+	if tx != nil {
+		return tx, nil
+	}
+
+	txHex, err := client.Transaction(c.ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	txBytes, err := hex.DecodeString(txHex)
+	if err != nil {
+		return nil, err
+	}
+	tx, err = c.cfg.DecodeTx(txBytes)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Hash != *hash {
+		return nil, fmt.Errorf("electrum server returned transaction %s for %s", tx.Hash, hash)
+	}
+
+	c.mtx.Lock()
+	c.txs[*hash] = tx
+	c.mtx.Unlock()
+	return tx, nil
+}
+
+// minedTransaction returns the transaction with the hash, checking its merkle
+// proof against the block it is mined in.
+func (c *Chain) minedTransaction(txHash string, block *BlockHeader) (*Tx, error) {
+	tx, err := c.transaction(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mtx.RLock()
+	client := c.client
+	c.mtx.RUnlock()
+
+	proof, err := client.TransactionMerkle(c.ctx, txHash, block.Height)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMerkleProof(&tx.Hash, proof, block); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// deliver notifies the wallet of the transactions of the script hashes mined
+// from the start height or unmined. Unless forced, transactions that were
+// already delivered at the same height are skipped.
+func (c *Chain) deliver(scriptHashes []string, startHeight int32, force bool) error {
+	c.deliverMtx.Lock()
+	defer c.deliverMtx.Unlock()
+
+	// The transactions mined deeper than the tracked blocks are no longer
+	// remembered as delivered, they are skipped if they were in the
+	// previous history of the script hash.
+	c.mtx.RLock()
+	finalHeight := c.tip.Height - recentBlocks
+	previous := make(map[string]int32)
+	for _, scriptHash := range scriptHashes {
+		if cached := c.histories[scriptHash]; cached != nil {
+			for _, item := range cached.items {
+				previous[item.TxHash] = item.Height
+			}
+		}
+	}
+	c.mtx.RUnlock()
+
+	histories, err := c.fetchHistories(scriptHashes)
+	if err != nil {
+		return err
+	}
+
+	// Unmined transactions are reported at height 0 or -1.
+	heights := make(map[string]int32)
+	for _, items := range histories {
+		for _, item := range items {
+			height := item.Height
+			if height < 0 {
+				height = 0
+			}
+			if height == 0 || height >= startHeight {
+				heights[item.TxHash] = height
+			}
+		}
+	}
+
+	hashesByHeight := make(map[int32][]string)
+	for txHash, height := range heights {
+		if !force && height > 0 && height <= finalHeight {
+			if previousHeight, ok := previous[txHash]; ok && previousHeight == height {
+				continue
+			}
+		}
+
+		hash, err := chainhash.NewHashFromStr(txHash)
+		if err != nil {
+			return err
+		}
+		c.mtx.RLock()
+		deliveredHeight, delivered := c.delivered[*hash]
+		c.mtx.RUnlock()
+		if force || !delivered || deliveredHeight != height {
+			hashesByHeight[height] = append(hashesByHeight[height], txHash)
+		}
+	}
+
+	// Mined transactions are delivered in chain order, unmined ones last.
+	blockHeights := make([]int32, 0, len(hashesByHeight))
+	for height := range hashesByHeight {
+		blockHeights = append(blockHeights, height)
+	}
+	sort.Slice(blockHeights, func(i, j int) bool {
+		if blockHeights[i] == 0 || blockHeights[j] == 0 {
+			return blockHeights[j] == 0 && blockHeights[i] != 0
+		}
+		return blockHeights[i] < blockHeights[j]
+	})
+
+	for _, height := range blockHeights {
+		var block *BlockHeader
+		if height > 0 {
+			if block, err = c.Header(height); err != nil {
+				return err
+			}
+		}
+
+		txs := make([]*Tx, 0, len(hashesByHeight[height]))
+		for _, txHash := range hashesByHeight[height] {
+			var tx *Tx
+			if block != nil {
+				tx, err = c.minedTransaction(txHash, block)
+			} else {
+				tx, err = c.transaction(txHash)
+			}
+			if err != nil {
+				return err
+			}
+			txs = append(txs, tx)
+		}
+
+		for _, tx := range sortByDependency(txs) {
+			c.cfg.Notifier.RelevantTx(tx, block)
+
+			c.mtx.Lock()
+			c.delivered[tx.Hash] = height
+			c.mtx.Unlock()
+		}
+	}
+	return nil
+}
+
+// sortByDependency orders the transactions so that transactions come after
+// the transactions they spend from.
+func sortByDependency(txs []*Tx) []*Tx {
+	pending := make(map[chainhash.Hash]*Tx, len(txs))
+	for _, tx := range txs {
+		pending[tx.Hash] = tx
+	}
+
+	sorted := make([]*Tx, 0, len(txs))
+	var add func(tx *Tx)
+	add = func(tx *Tx) {
+		if _, ok := pending[tx.Hash]; !ok {
+			return
+		}
+		delete(pending, tx.Hash)
+		for _, input := range tx.Inputs {
+			if parent, ok := pending[input]; ok {
+				add(parent)
+			}
+		}
+		sorted = append(sorted, tx)
+	}
+	for _, tx := range txs {
+		add(tx)
+	}
+	return sorted
+}
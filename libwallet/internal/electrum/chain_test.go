@@ -0,0 +1,300 @@
+package electrum
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// testTx is a transaction of the fake chain. Its hash is the double sha256
+// of its bytes.
+var testTx = []byte("relevant transaction")
+
+func decodeTestTx(raw []byte) (*Tx, error) {
+	return &Tx{Hash: chainhash.DoubleHashH(raw), Msg: raw}, nil
+}
+
+// recordingNotifier records the transactions the chain delivers.
+type recordingNotifier struct {
+	mtx sync.Mutex
+	txs map[chainhash.Hash]int32
+}
+
+func (n *recordingNotifier) BlockConnected(*BlockHeader)    {}
+func (n *recordingNotifier) BlockDisconnected(*BlockHeader) {}
+func (n *recordingNotifier) RelevantTx(tx *Tx, block *BlockHeader) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	height := int32(0)
+	if block != nil {
+		height = block.Height
+	}
+	n.txs[tx.Hash] = height
+}
+
+// testChainServer serves a chain of headers with testTx mined in txBlock,
+// the only transaction of the watched script.
+type testChainServer struct {
+	mtx     sync.Mutex
+	headers []*BlockHeader
+	txBlock int32
+	// servedTx is the transaction served for the hash of testTx.
+	servedTx []byte
+	// merkle is the merkle branch served for testTx.
+	merkle []string
+}
+
+func (s *testChainServer) handle(method string, params []json.RawMessage) (interface{}, *RPCError) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var args []int32
+	switch method {
+	case "server.version":
+		return []string{"fake", protocolVersion}, nil
+
+	case "blockchain.headers.subscribe":
+		tip := s.headers[len(s.headers)-1]
+		return &Header{Height: tip.Height, Hex: hex.EncodeToString(tip.Raw)}, nil
+
+	case "blockchain.block.header":
+		var height int32
+		json.Unmarshal(params[0], &height)
+		return hex.EncodeToString(s.headers[height].Raw), nil
+
+	case "blockchain.block.headers":
+		for _, param := range params {
+			var arg int32
+			json.Unmarshal(param, &arg)
+			args = append(args, arg)
+		}
+		var sb strings.Builder
+		count := int32(0)
+		for height := args[0]; height < args[0]+args[1] && int(height) < len(s.headers); height++ {
+			sb.WriteString(hex.EncodeToString(s.headers[height].Raw))
+			count++
+		}
+		return map[string]interface{}{"count": count, "hex": sb.String()}, nil
+
+	case "blockchain.scripthash.subscribe":
+		return "status", nil
+
+	case "blockchain.scripthash.get_history":
+		return []*HistoryItem{{Height: s.txBlock, TxHash: chainhash.DoubleHashH(testTx).String()}}, nil
+
+	case "blockchain.transaction.get":
+		return hex.EncodeToString(s.servedTx), nil
+
+	case "blockchain.transaction.get_merkle":
+		return &MerkleProof{BlockHeight: s.txBlock, Merkle: s.merkle}, nil
+	}
+	return nil, &RPCError{Code: 1, Message: "unknown method " + method}
+}
+
+// mineTestChain returns n headers with testTx and other mined in the block at
+// txBlock.
+func mineTestChain(n int, txBlock int32, other chainhash.Hash) []*BlockHeader {
+	txHash := chainhash.DoubleHashH(testTx)
+	merkleRoot := chainhash.DoubleHashH(append(append([]byte{}, txHash[:]...), other[:]...))
+
+	headers := make([]*BlockHeader, 0, n)
+	var prev *BlockHeader
+	for i := 0; i < n; i++ {
+		root := chainhash.Hash{byte(i), byte(i >> 8)}
+		if int32(i) == txBlock {
+			root = merkleRoot
+		}
+		prev = mineHeader(prev, root, testBits, true)
+		headers = append(headers, prev)
+	}
+	return headers
+}
+
+func startTestChain(t *testing.T, server *testChainServer, checkpoints []Checkpoint) (*Chain, *recordingNotifier, error) {
+	t.Helper()
+	dial, _ := dialFakeServer(server.handle)
+	params := testParams()
+	params.Checkpoints = checkpoints
+	notifier := &recordingNotifier{txs: make(map[chainhash.Hash]int32)}
+	chain := NewChain(ChainConfig{
+		Servers:  []ServerConfig{{Address: "fake:50001"}},
+		Dial:     dial,
+		Params:   params,
+		DecodeTx: decodeTestTx,
+		Notifier: notifier,
+	})
+	return chain, notifier, chain.Start()
+}
+
+func TestChainDelivery(t *testing.T) {
+	other := chainhash.Hash{0xaa}
+	headers := mineTestChain(30, 20, other)
+	genesis := []Checkpoint{{Height: 0, Hash: headers[0].Hash}}
+	txHash := chainhash.DoubleHashH(testTx)
+
+	tests := []struct {
+		name        string
+		servedTx    []byte
+		merkle      []string
+		checkpoints []Checkpoint
+		startErr    bool
+		delivered   bool
+	}{
+		{
+			name:        "verified transaction",
+			servedTx:    testTx,
+			merkle:      []string{other.String()},
+			checkpoints: genesis,
+			delivered:   true,
+		},
+		{
+			name:        "other transaction served",
+			servedTx:    []byte("other transaction"),
+			merkle:      []string{other.String()},
+			checkpoints: genesis,
+		},
+		{
+			name:        "invalid merkle proof",
+			servedTx:    testTx,
+			merkle:      []string{chainhash.Hash{0xbb}.String()},
+			checkpoints: genesis,
+		},
+		{
+			name:        "checkpoint not in the served chain",
+			servedTx:    testTx,
+			checkpoints: []Checkpoint{{Height: 10, Hash: chainhash.Hash{0xcc}}},
+			startErr:    true,
+		},
+		{
+			name:     "no checkpoint",
+			servedTx: testTx,
+			startErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := &testChainServer{headers: headers, txBlock: 20, servedTx: tc.servedTx, merkle: tc.merkle}
+			chain, notifier, err := startTestChain(t, server, tc.checkpoints)
+			if (err != nil) != tc.startErr {
+				t.Fatalf("(%v), expected start error (%v), got (%v)", tc.name, tc.startErr, err)
+			}
+			if err != nil {
+				return
+			}
+			defer chain.Stop()
+
+			if tip := chain.Tip(); tip.Hash != headers[29].Hash {
+				t.Errorf("(%v), expected tip (%v), got (%v)", tc.name, headers[29].Hash, tip.Hash)
+			}
+
+			err = chain.NotifyReceived([][]byte{{0x51}})
+			if (err == nil) != tc.delivered {
+				t.Errorf("(%v), expected delivered (%v), got (%v)", tc.name, tc.delivered, err)
+			}
+			notifier.mtx.Lock()
+			height, delivered := notifier.txs[txHash]
+			notifier.mtx.Unlock()
+			if delivered != tc.delivered || (delivered && height != 20) {
+				t.Errorf("(%v), expected delivered (%v) in block 20, got (%v) in block (%d)",
+					tc.name, tc.delivered, delivered, height)
+			}
+		})
+	}
+}
+
+func TestChainDeepHeaders(t *testing.T) {
+	headers := mineTestChain(2*chunkSize+100, 10, chainhash.Hash{})
+	checkpoint := headers[2*chunkSize+50]
+
+	tests := []struct {
+		name   string
+		tamper int32
+		valid  bool
+	}{
+		{name: "verified down from the checkpoint", tamper: -1, valid: true},
+		{name: "tampered header below the checkpoint", tamper: chunkSize + 10},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			served := append([]*BlockHeader{}, headers...)
+			if tc.tamper >= 0 {
+				served[tc.tamper] = mineHeader(served[tc.tamper-1], chainhash.Hash{0xdd}, testBits, true)
+			}
+			server := &testChainServer{headers: served}
+			chain, _, err := startTestChain(t, server, []Checkpoint{{Height: checkpoint.Height, Hash: checkpoint.Hash}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer chain.Stop()
+
+			header, err := chain.Header(100)
+			if (err == nil) != tc.valid {
+				t.Fatalf("(%v), expected valid (%v), got (%v)", tc.name, tc.valid, err)
+			}
+			if err == nil && header.Hash != headers[100].Hash {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, headers[100].Hash, header.Hash)
+			}
+		})
+	}
+}
+
+func TestChainEvictsDeepTransactions(t *testing.T) {
+	other := chainhash.Hash{0xaa}
+	headers := mineTestChain(30+recentBlocks, 20, other)
+	server := &testChainServer{headers: headers[:30], txBlock: 20, servedTx: testTx, merkle: []string{other.String()}}
+	dial, servers := dialFakeServer(server.handle)
+	params := testParams()
+	params.Checkpoints = []Checkpoint{{Height: 0, Hash: headers[0].Hash}}
+	notifier := &recordingNotifier{txs: make(map[chainhash.Hash]int32)}
+	chain := NewChain(ChainConfig{
+		Servers:  []ServerConfig{{Address: "fake:50001"}},
+		Dial:     dial,
+		Params:   params,
+		DecodeTx: decodeTestTx,
+		Notifier: notifier,
+	})
+	if err := chain.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	if err := chain.NotifyReceived([][]byte{{0x51}}); err != nil {
+		t.Fatal(err)
+	}
+
+	txHash := chainhash.DoubleHashH(testTx)
+	cached := func() bool {
+		chain.mtx.RLock()
+		defer chain.mtx.RUnlock()
+		_, delivered := chain.delivered[txHash]
+		_, cached := chain.txs[txHash]
+		return delivered || cached
+	}
+	if !cached() {
+		t.Fatal("expected the delivered transaction to be cached")
+	}
+
+	// Extend the chain until the transaction's block is final.
+	fakeServer := <-servers
+	server.mtx.Lock()
+	server.headers = headers
+	server.mtx.Unlock()
+	tip := headers[len(headers)-1]
+	fakeServer.notify("blockchain.headers.subscribe", &Header{Height: tip.Height, Hex: hex.EncodeToString(tip.Raw)})
+	deadline := time.Now().Add(testTimeout)
+	for chain.Tip().Height != tip.Height {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected tip (%d), got (%d)", tip.Height, chain.Tip().Height)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if cached() {
+		t.Error("expected the transaction of a final block to be evicted")
+	}
+}
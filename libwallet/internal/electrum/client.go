@@ -0,0 +1,466 @@
+// Package electrum implements a client for the Electrum protocol served by
+// ElectrumX and Fulcrum servers.
+package electrum
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// protocolVersion is the Electrum protocol version the client speaks.
+	protocolVersion = "1.4"
+
+	// pingInterval is how often the connection is pinged. Servers drop
+	// connections that are idle for a few minutes.
+	pingInterval = time.Minute
+
+	// requestTimeout bounds how long a request waits for its response.
+	requestTimeout = 30 * time.Second
+
+	// notificationBuffer is the number of notifications of each kind that
+	// are buffered before the reader blocks.
+	notificationBuffer = 128
+)
+
+// ErrDisconnected is returned by requests made after the connection to the
+// server is lost.
+var ErrDisconnected = errors.New("electrum server disconnected")
+
+// DialFunc dials the tcp connection to a server.
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// ServerConfig is an Electrum server the client can connect to.
+type ServerConfig struct {
+	// Address is the host:port of the server.
+	Address string `json:"address"`
+	// TLS connects to the server's ssl port.
+	TLS bool `json:"tls"`
+	// CertFingerprint is the hex encoded sha256 hash of the server's DER
+	// encoded TLS certificate. When it is set the certificate is pinned,
+	// which allows servers with self-signed certificates.
+	CertFingerprint string `json:"cert_fingerprint"`
+}
+
+// RPCError is an error returned by the server in response to a request.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("electrum error %d: %s", e.Code, e.Message)
+}
+
+type request struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// message is either a response to a request or a notification.
+type message struct {
+	ID     *uint64         `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// Header is a block header notified by the server.
+type Header struct {
+	Height int32  `json:"height"`
+	Hex    string `json:"hex"`
+}
+
+// ScriptHashStatus is the status of a subscribed script hash. The status
+// changes whenever a transaction touching the script is seen or mined.
+type ScriptHashStatus struct {
+	ScriptHash string
+	Status     string
+}
+
+// HistoryItem is a transaction in the history of a script hash. Height is 0
+// for mempool transactions and -1 for mempool transactions with unconfirmed
+// inputs.
+type HistoryItem struct {
+	Height int32  `json:"height"`
+	TxHash string `json:"tx_hash"`
+}
+
+// Client is a connection to an Electrum server.
+type Client struct {
+	conn   net.Conn
+	server ServerConfig
+
+	nextID    uint64 // atomic
+	writeMtx  sync.Mutex
+	pendingMu sync.Mutex
+	pending   map[uint64]chan *message
+
+	headers      chan *Header
+	scriptHashes chan *ScriptHashStatus
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Connect connects to the first of the servers that accepts the connection
+// and negotiates the protocol version.
+func Connect(ctx context.Context, servers []ServerConfig, dial DialFunc) (*Client, error) {
+	if len(servers) == 0 {
+		return nil, errors.New("no electrum servers")
+	}
+
+	var errs []string
+	for _, server := range servers {
+		client, err := connect(ctx, server, dial)
+		if err == nil {
+			log.Infof("Connected to electrum server %s", server.Address)
+			return client, nil
+		}
+		log.Warnf("Unable to connect to electrum server %s: %v", server.Address, err)
+		errs = append(errs, fmt.Sprintf("%s: %v", server.Address, err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, fmt.Errorf("unable to connect to an electrum server: %s", strings.Join(errs, "; "))
+}
+
+func connect(ctx context.Context, server ServerConfig, dial DialFunc) (*Client, error) {
+	conn, err := dial(ctx, "tcp", server.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	if server.TLS {
+		tlsConn, err := tlsHandshake(ctx, conn, server)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	c := &Client{
+		conn:         conn,
+		server:       server,
+		pending:      make(map[uint64]chan *message),
+		headers:      make(chan *Header, notificationBuffer),
+		scriptHashes: make(chan *ScriptHashStatus, notificationBuffer),
+		done:         make(chan struct{}),
+	}
+	go c.read()
+
+	var versions []string
+	if err := c.call(ctx, "server.version", &versions, "cryptopower", protocolVersion); err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	go c.ping()
+	return c, nil
+}
+
+// tlsHandshake secures conn. The server's certificate is verified against its
+// pinned fingerprint if there is one and against the system roots otherwise.
+func tlsHandshake(ctx context.Context, conn net.Conn, server ServerConfig) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(server.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &tls.Config{
+		ServerName: host,
+		MinVersion: tls.VersionTLS12,
+	}
+	if server.CertFingerprint != "" {
+		pin, err := hex.DecodeString(strings.ReplaceAll(server.CertFingerprint, ":", ""))
+		if err != nil || len(pin) != sha256.Size {
+			return nil, errors.New("invalid certificate fingerprint")
+		}
+		// The pinned certificate replaces the chain verification.
+		cfg.InsecureSkipVerify = true
+		cfg.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return errors.New("no server certificate")
+			}
+			fingerprint := sha256.Sum256(rawCerts[0])
+			if !bytes.Equal(fingerprint[:], pin) {
+				return fmt.Errorf("server certificate fingerprint %x does not match the pinned fingerprint",
+					fingerprint)
+			}
+			return nil
+		}
+	}
+
+	tlsConn := tls.Client(conn, cfg)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+// Server returns the server the client is connected to.
+func (c *Client) Server() ServerConfig {
+	return c.server
+}
+
+// Done is closed when the connection to the server is lost or closed.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Headers delivers the chain tips notified after SubscribeHeaders is called.
+func (c *Client) Headers() <-chan *Header {
+	return c.headers
+}
+
+// ScriptHashes delivers the status changes of subscribed script hashes.
+func (c *Client) ScriptHashes() <-chan *ScriptHashStatus {
+	return c.scriptHashes
+}
+
+// Close closes the connection to the server.
+func (c *Client) Close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+func (c *Client) read() {
+	defer c.Close()
+
+	reader := bufio.NewReader(c.conn)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			select {
+			case <-c.done:
+			default:
+				log.Debugf("Electrum server %s disconnected: %v", c.server.Address, err)
+			}
+			return
+		}
+
+		msg := new(message)
+		if err := json.Unmarshal(line, msg); err != nil {
+			log.Errorf("Invalid message from electrum server %s: %v", c.server.Address, err)
+			continue
+		}
+
+		if msg.ID != nil {
+			c.pendingMu.Lock()
+			respChan, ok := c.pending[*msg.ID]
+			delete(c.pending, *msg.ID)
+			c.pendingMu.Unlock()
+			if ok {
+				respChan <- msg
+			}
+			continue
+		}
+
+		c.notify(msg)
+	}
+}
+
+func (c *Client) notify(msg *message) {
+	switch msg.Method {
+	case "blockchain.headers.subscribe":
+		var params []*Header
+		if err := json.Unmarshal(msg.Params, &params); err != nil || len(params) == 0 {
+			log.Errorf("Invalid header notification: %v", err)
+			return
+		}
+		select {
+		case c.headers <- params[0]:
+		case <-c.done:
+		}
+
+	case "blockchain.scripthash.subscribe":
+		var params []*string
+		if err := json.Unmarshal(msg.Params, &params); err != nil || len(params) != 2 || params[0] == nil {
+			log.Errorf("Invalid script hash notification: %v", err)
+			return
+		}
+		status := &ScriptHashStatus{ScriptHash: *params[0]}
+		if params[1] != nil {
+			status.Status = *params[1]
+		}
+		select {
+		case c.scriptHashes <- status:
+		case <-c.done:
+		}
+
+	default:
+		log.Debugf("Unhandled electrum notification %q", msg.Method)
+	}
+}
+
+func (c *Client) ping() {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.call(context.Background(), "server.ping", nil); err != nil {
+				log.Debugf("Electrum server %s ping failed: %v", c.server.Address, err)
+				c.Close()
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// call sends a request and decodes its result into result, which may be nil.
+func (c *Client) call(ctx context.Context, method string, result interface{}, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	req := &request{
+		JSONRPC: "2.0",
+		ID:      atomic.AddUint64(&c.nextID, 1),
+		Method:  method,
+		Params:  params,
+	}
+	b, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	respChan := make(chan *message, 1)
+	c.pendingMu.Lock()
+	c.pending[req.ID] = respChan
+	c.pendingMu.Unlock()
+	defer func() {
+		c.pendingMu.Lock()
+		delete(c.pending, req.ID)
+		c.pendingMu.Unlock()
+	}()
+
+	c.writeMtx.Lock()
+	_, err = c.conn.Write(append(b, '\n'))
+	c.writeMtx.Unlock()
+	if err != nil {
+		c.Close()
+		return ErrDisconnected
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	select {
+	case resp := <-respChan:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(resp.Result, result)
+	case <-c.done:
+		return ErrDisconnected
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SubscribeHeaders returns the current chain tip. New tips are delivered on
+// the Headers channel.
+func (c *Client) SubscribeHeaders(ctx context.Context) (*Header, error) {
+	header := new(Header)
+	return header, c.call(ctx, "blockchain.headers.subscribe", header)
+}
+
+// BlockHeader returns the hex encoded header of the block at the height.
+func (c *Client) BlockHeader(ctx context.Context, height int32) (string, error) {
+	var header string
+	return header, c.call(ctx, "blockchain.block.header", &header, height)
+}
+
+// BlockHeaders returns up to count concatenated hex encoded headers starting
+// at the start height. Servers return at most 2016 headers at once.
+func (c *Client) BlockHeaders(ctx context.Context, start, count int32) (string, error) {
+	var result struct {
+		Count int32  `json:"count"`
+		Hex   string `json:"hex"`
+	}
+	return result.Hex, c.call(ctx, "blockchain.block.headers", &result, start, count)
+}
+
+// SubscribeScriptHash returns the status of the script hash. Status changes
+// are delivered on the ScriptHashes channel. An empty status means the
+// script has no history.
+func (c *Client) SubscribeScriptHash(ctx context.Context, scriptHash string) (string, error) {
+	var status *string
+	if err := c.call(ctx, "blockchain.scripthash.subscribe", &status, scriptHash); err != nil {
+		return "", err
+	}
+	if status == nil {
+		return "", nil
+	}
+	return *status, nil
+}
+
+// ScriptHashHistory returns the confirmed and mempool transactions that pay
+// to or spend from the script hash.
+func (c *Client) ScriptHashHistory(ctx context.Context, scriptHash string) ([]*HistoryItem, error) {
+	var history []*HistoryItem
+	return history, c.call(ctx, "blockchain.scripthash.get_history", &history, scriptHash)
+}
+
+// Transaction returns the hex encoded transaction with the hash.
+func (c *Client) Transaction(ctx context.Context, txHash string) (string, error) {
+	var tx string
+	return tx, c.call(ctx, "blockchain.transaction.get", &tx, txHash, false)
+}
+
+// MerkleProof is the merkle branch linking a transaction to the merkle root
+// of its block.
+type MerkleProof struct {
+	BlockHeight int32    `json:"block_height"`
+	Merkle      []string `json:"merkle"`
+	Pos         uint32   `json:"pos"`
+}
+
+// TransactionMerkle returns the merkle proof of the transaction mined in the
+// block at the height.
+func (c *Client) TransactionMerkle(ctx context.Context, txHash string, height int32) (*MerkleProof, error) {
+	proof := new(MerkleProof)
+	return proof, c.call(ctx, "blockchain.transaction.get_merkle", proof, txHash, height)
+}
+
+// Broadcast publishes the hex encoded transaction and returns its hash.
+func (c *Client) Broadcast(ctx context.Context, txHex string) (string, error) {
+	var txHash string
+	return txHash, c.call(ctx, "blockchain.transaction.broadcast", &txHash, txHex)
+}
+
+// ScriptHash returns the script hash that identifies the output script in
+// the Electrum protocol, the byte reversed sha256 hash of the script.
+func ScriptHash(pkScript []byte) string {
+	hash := sha256.Sum256(pkScript)
+	for i, j := 0, len(hash)-1; i < j; i, j = i+1, j-1 {
+		hash[i], hash[j] = hash[j], hash[i]
+	}
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,234 @@
+package electrum
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testTimeout = 5 * time.Second
+
+// handlerFunc answers a request of the fake server.
+type handlerFunc func(method string, params []json.RawMessage) (interface{}, *RPCError)
+
+// fakeServer is an Electrum server answering requests with its handler.
+type fakeServer struct {
+	conn    net.Conn
+	handler handlerFunc
+
+	writeMtx sync.Mutex
+}
+
+// dialFakeServer returns a dialer connecting to fake servers answering with
+// the handler, and the channel the servers are sent on once connected.
+func dialFakeServer(handler handlerFunc) (DialFunc, chan *fakeServer) {
+	servers := make(chan *fakeServer, 8)
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		clientConn, serverConn := net.Pipe()
+		server := &fakeServer{conn: serverConn, handler: handler}
+		go server.serve()
+		servers <- server
+		return clientConn, nil
+	}
+	return dial, servers
+}
+
+func (s *fakeServer) serve() {
+	reader := bufio.NewReader(s.conn)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+		var req struct {
+			ID     uint64            `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(line, &req); err != nil {
+			return
+		}
+
+		result, rpcErr := s.handler(req.Method, req.Params)
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		if rpcErr != nil {
+			resp["error"] = rpcErr
+		} else {
+			resp["result"] = result
+		}
+		s.send(resp)
+	}
+}
+
+// notify sends a notification to the client.
+func (s *fakeServer) notify(method string, params ...interface{}) {
+	s.send(map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+func (s *fakeServer) send(msg interface{}) {
+	b, _ := json.Marshal(msg)
+	s.writeMtx.Lock()
+	defer s.writeMtx.Unlock()
+	s.conn.Write(append(b, '\n'))
+}
+
+func TestScriptHash(t *testing.T) {
+	tests := []struct {
+		name       string
+		pkScript   string
+		scriptHash string
+	}{
+		{
+			// The example of the Electrum protocol documentation.
+			name:       "p2pkh",
+			pkScript:   "76a91462e907b15cbf27d5425399ebf6f0fb50ebb88f1888ac",
+			scriptHash: "8b01df4e368ea28f8dc0423bcf7a4923e3a12d307c875e47a0cfbf90b5c39161",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if scriptHash := ScriptHash(mustDecodeHex(t, tc.pkScript)); scriptHash != tc.scriptHash {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.scriptHash, scriptHash)
+			}
+		})
+	}
+}
+
+func TestClientRequests(t *testing.T) {
+	proof := &MerkleProof{BlockHeight: 10, Merkle: []string{"aa"}, Pos: 1}
+	dial, _ := dialFakeServer(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		switch method {
+		case "server.version":
+			return []string{"fake", protocolVersion}, nil
+		case "blockchain.headers.subscribe":
+			return &Header{Height: 10, Hex: "00"}, nil
+		case "blockchain.scripthash.subscribe":
+			if string(params[0]) == `"unused"` {
+				return nil, nil
+			}
+			return "status", nil
+		case "blockchain.scripthash.get_history":
+			return []*HistoryItem{{Height: 10, TxHash: "tx1"}, {Height: 0, TxHash: "tx2"}}, nil
+		case "blockchain.transaction.get":
+			return "raw", nil
+		case "blockchain.transaction.get_merkle":
+			return proof, nil
+		}
+		return nil, &RPCError{Code: 1, Message: "unknown method"}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	client, err := Connect(ctx, []ServerConfig{{Address: "fake:50001"}}, dial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	tests := []struct {
+		name     string
+		call     func() (interface{}, error)
+		expected interface{}
+		err      error
+	}{
+		{
+			name:     "headers subscribe",
+			call:     func() (interface{}, error) { return client.SubscribeHeaders(ctx) },
+			expected: &Header{Height: 10, Hex: "00"},
+		},
+		{
+			name:     "script hash status",
+			call:     func() (interface{}, error) { return client.SubscribeScriptHash(ctx, "used") },
+			expected: "status",
+		},
+		{
+			name:     "null script hash status",
+			call:     func() (interface{}, error) { return client.SubscribeScriptHash(ctx, "unused") },
+			expected: "",
+		},
+		{
+			name:     "history",
+			call:     func() (interface{}, error) { return client.ScriptHashHistory(ctx, "used") },
+			expected: []*HistoryItem{{Height: 10, TxHash: "tx1"}, {Height: 0, TxHash: "tx2"}},
+		},
+		{
+			name:     "transaction",
+			call:     func() (interface{}, error) { return client.Transaction(ctx, "tx1") },
+			expected: "raw",
+		},
+		{
+			name:     "merkle proof",
+			call:     func() (interface{}, error) { return client.TransactionMerkle(ctx, "tx1", 10) },
+			expected: proof,
+		},
+		{
+			name:     "rpc error",
+			call:     func() (interface{}, error) { return client.Broadcast(ctx, "raw") },
+			expected: "",
+			err:      &RPCError{Code: 1, Message: "unknown method"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.call()
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("(%v), expected error (%v), got (%v)", tc.name, tc.err, err)
+			}
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestClientNotifications(t *testing.T) {
+	dial, servers := dialFakeServer(func(method string, params []json.RawMessage) (interface{}, *RPCError) {
+		return []string{"fake", protocolVersion}, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	client, err := Connect(ctx, []ServerConfig{{Address: "fake:50001"}}, dial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := <-servers
+
+	server.notify("blockchain.headers.subscribe", &Header{Height: 11, Hex: "01"})
+	select {
+	case header := <-client.Headers():
+		if expected := (&Header{Height: 11, Hex: "01"}); !reflect.DeepEqual(header, expected) {
+			t.Errorf("expected (%v), got (%v)", expected, header)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("header notification not received")
+	}
+
+	server.notify("blockchain.scripthash.subscribe", "hash", nil)
+	select {
+	case status := <-client.ScriptHashes():
+		if expected := (&ScriptHashStatus{ScriptHash: "hash"}); !reflect.DeepEqual(status, expected) {
+			t.Errorf("expected (%v), got (%v)", expected, status)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("script hash notification not received")
+	}
+
+	server.conn.Close()
+	select {
+	case <-client.Done():
+	case <-time.After(testTimeout):
+		t.Fatal("disconnection not detected")
+	}
+	if _, err := client.SubscribeHeaders(ctx); !errors.Is(err, ErrDisconnected) {
+		t.Errorf("expected (%v), got (%v)", ErrDisconnected, err)
+	}
+}
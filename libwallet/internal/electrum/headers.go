@@ -0,0 +1,174 @@
+package electrum
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// BlockHeaderSize is the size of a serialized block header.
+const BlockHeaderSize = 80
+
+// BlockHeader is a block header of a bitcoin derived chain.
+type BlockHeader struct {
+	Height     int32
+	Hash       chainhash.Hash
+	PrevBlock  chainhash.Hash
+	MerkleRoot chainhash.Hash
+	Timestamp  time.Time
+	Bits       uint32
+	// Raw is the serialized header.
+	Raw []byte
+}
+
+// Checkpoint is a block trusted to be in the main chain.
+type Checkpoint struct {
+	Height int32
+	Hash   chainhash.Hash
+}
+
+// ChainParams are the consensus rules the headers served by the servers are
+// checked against.
+type ChainParams struct {
+	// Checkpoints are the trusted blocks the header chain is verified from.
+	// It must at least have the genesis block.
+	Checkpoints []Checkpoint
+	// PowLimit is the highest target a block can have.
+	PowLimit *big.Int
+	// ReduceMinDifficulty allows blocks of the minimum difficulty, so the
+	// difficulty transitions are not checked.
+	ReduceMinDifficulty bool
+	// RetargetInterval is the number of blocks between difficulty changes.
+	RetargetInterval int32
+	// RetargetAdjustmentFactor bounds the change of the difficulty at each
+	// retarget.
+	RetargetAdjustmentFactor int64
+	// PowHash returns the hash of the serialized header that must not be
+	// above the header's target.
+	PowHash func(header []byte) chainhash.Hash
+}
+
+// ParseHeaders parses the concatenated hex encoded block headers starting at
+// the height.
+func ParseHeaders(headersHex string, height int32) ([]*BlockHeader, error) {
+	headersBytes, err := hex.DecodeString(headersHex)
+	if err != nil {
+		return nil, err
+	}
+	if len(headersBytes) == 0 || len(headersBytes)%BlockHeaderSize != 0 {
+		return nil, errors.New("invalid electrum block headers")
+	}
+
+	headers := make([]*BlockHeader, 0, len(headersBytes)/BlockHeaderSize)
+	for i := 0; i < len(headersBytes); i += BlockHeaderSize {
+		headers = append(headers, parseHeader(headersBytes[i:i+BlockHeaderSize], height+int32(len(headers))))
+	}
+	return headers, nil
+}
+
+func parseHeader(raw []byte, height int32) *BlockHeader {
+	header := &BlockHeader{
+		Height:    height,
+		Hash:      chainhash.DoubleHashH(raw),
+		Timestamp: time.Unix(int64(binary.LittleEndian.Uint32(raw[68:72])), 0),
+		Bits:      binary.LittleEndian.Uint32(raw[72:76]),
+		Raw:       raw,
+	}
+	copy(header.PrevBlock[:], raw[4:36])
+	copy(header.MerkleRoot[:], raw[36:68])
+	return header
+}
+
+// checkProofOfWork checks that the header's hash is not above its target and
+// that the target is not above the proof of work limit.
+func (params *ChainParams) checkProofOfWork(header *BlockHeader) error {
+	target := blockchain.CompactToBig(header.Bits)
+	if target.Sign() <= 0 || target.Cmp(params.PowLimit) > 0 {
+		return fmt.Errorf("block %d has an invalid target %08x", header.Height, header.Bits)
+	}
+	powHash := params.PowHash(header.Raw)
+	if blockchain.HashToBig(&powHash).Cmp(target) > 0 {
+		return fmt.Errorf("block %d does not meet its target", header.Height)
+	}
+	return nil
+}
+
+// checkConnects checks that the header builds on the previous header and that
+// the difficulty only changes at retargets, by at most the adjustment
+// factor.
+func (params *ChainParams) checkConnects(prev, header *BlockHeader) error {
+	if header.Height != prev.Height+1 || header.PrevBlock != prev.Hash {
+		return fmt.Errorf("block %d does not connect to block %d", header.Height, prev.Height)
+	}
+	if params.ReduceMinDifficulty || header.Bits == prev.Bits {
+		return nil
+	}
+	if header.Height%params.RetargetInterval != 0 {
+		return fmt.Errorf("block %d changes the difficulty between retargets", header.Height)
+	}
+
+	factor := big.NewInt(params.RetargetAdjustmentFactor)
+	prevTarget := blockchain.CompactToBig(prev.Bits)
+	target := blockchain.CompactToBig(header.Bits)
+	maxTarget := new(big.Int).Mul(prevTarget, factor)
+	// Targets are truncated to their compact form after the adjustment.
+	minTarget := blockchain.CompactToBig(blockchain.BigToCompact(new(big.Int).Div(prevTarget, factor)))
+	if target.Cmp(maxTarget) > 0 || target.Cmp(minTarget) < 0 {
+		return fmt.Errorf("block %d changes the difficulty too much", header.Height)
+	}
+	return nil
+}
+
+// checkHeaders checks the proof of work of the consecutive headers and that
+// they connect to each other and to prev, if it is not nil.
+func (params *ChainParams) checkHeaders(prev *BlockHeader, headers []*BlockHeader) error {
+	for _, header := range headers {
+		if err := params.checkProofOfWork(header); err != nil {
+			return err
+		}
+		if prev != nil {
+			if err := params.checkConnects(prev, header); err != nil {
+				return err
+			}
+		}
+		prev = header
+	}
+	return nil
+}
+
+// checkMerkleProof checks that the proof links the transaction to the merkle
+// root of the header.
+func checkMerkleProof(txHash *chainhash.Hash, proof *MerkleProof, header *BlockHeader) error {
+	if proof.BlockHeight != header.Height || len(proof.Merkle) > 32 ||
+		uint64(proof.Pos) >= uint64(1)<<len(proof.Merkle) {
+		return fmt.Errorf("invalid merkle proof of transaction %s", txHash)
+	}
+
+	root := *txHash
+	var buf [chainhash.HashSize * 2]byte
+	for i, branchHex := range proof.Merkle {
+		branch, err := chainhash.NewHashFromStr(branchHex)
+		if err != nil {
+			return err
+		}
+		if proof.Pos>>i&1 == 1 {
+			copy(buf[:chainhash.HashSize], branch[:])
+			copy(buf[chainhash.HashSize:], root[:])
+		} else {
+			copy(buf[:chainhash.HashSize], root[:])
+			copy(buf[chainhash.HashSize:], branch[:])
+		}
+		root = chainhash.DoubleHashH(buf[:])
+	}
+
+	if root != header.MerkleRoot {
+		return fmt.Errorf("transaction %s is not in block %d", txHash, header.Height)
+	}
+	return nil
+}
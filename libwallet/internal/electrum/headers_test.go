@@ -0,0 +1,239 @@
+package electrum
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// testBits is the regtest proof of work limit, which half of the hashes meet.
+const testBits = 0x207fffff
+
+func testParams() *ChainParams {
+	return &ChainParams{
+		PowLimit:                 blockchain.CompactToBig(testBits),
+		RetargetInterval:         chunkSize,
+		RetargetAdjustmentFactor: 4,
+		PowHash:                  chainhash.DoubleHashH,
+	}
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// mineHeader returns a header building on prev, which may be nil, that meets
+// its target if valid is true and does not otherwise.
+func mineHeader(prev *BlockHeader, merkleRoot chainhash.Hash, bits uint32, valid bool) *BlockHeader {
+	raw := make([]byte, BlockHeaderSize)
+	binary.LittleEndian.PutUint32(raw[0:4], 1)
+	height, timestamp := int32(0), uint32(1600000000)
+	if prev != nil {
+		copy(raw[4:36], prev.Hash[:])
+		height, timestamp = prev.Height+1, uint32(prev.Timestamp.Unix())+600
+	}
+	copy(raw[36:68], merkleRoot[:])
+	binary.LittleEndian.PutUint32(raw[68:72], timestamp)
+	binary.LittleEndian.PutUint32(raw[72:76], bits)
+
+	target := blockchain.CompactToBig(bits)
+	for nonce := uint32(0); ; nonce++ {
+		binary.LittleEndian.PutUint32(raw[76:80], nonce)
+		hash := chainhash.DoubleHashH(raw)
+		if (blockchain.HashToBig(&hash).Cmp(target) <= 0) == valid {
+			return parseHeader(raw, height)
+		}
+	}
+}
+
+// mineChain returns n headers from the genesis block.
+func mineChain(n int) []*BlockHeader {
+	headers := make([]*BlockHeader, 0, n)
+	var prev *BlockHeader
+	for i := 0; i < n; i++ {
+		prev = mineHeader(prev, chainhash.Hash{byte(i)}, testBits, true)
+		headers = append(headers, prev)
+	}
+	return headers
+}
+
+func TestParseHeaders(t *testing.T) {
+	chain := mineChain(2)
+	headersHex := hex.EncodeToString(append(append([]byte{}, chain[0].Raw...), chain[1].Raw...))
+
+	headers, err := ParseHeaders(headersHex, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, header := range headers {
+		if header.Height != chain[i].Height || header.Hash != chain[i].Hash || header.Bits != testBits {
+			t.Errorf("expected block (%d %v), got (%d %v)", chain[i].Height, chain[i].Hash, header.Height, header.Hash)
+		}
+	}
+	if headers[1].PrevBlock != headers[0].Hash {
+		t.Errorf("expected (%v), got (%v)", headers[0].Hash, headers[1].PrevBlock)
+	}
+
+	if _, err := ParseHeaders(headersHex[:len(headersHex)-2], 0); err == nil {
+		t.Error("expected an error parsing truncated headers")
+	}
+}
+
+func TestCheckHeaders(t *testing.T) {
+	chain := mineChain(6)
+	other := mineHeader(chain[1], chainhash.Hash{0xff}, testBits, true)
+
+	retargetParams := testParams()
+	retargetParams.RetargetInterval = 4
+	minDifficultyParams := testParams()
+	minDifficultyParams.ReduceMinDifficulty = true
+
+	tests := []struct {
+		name    string
+		params  *ChainParams
+		prev    *BlockHeader
+		headers []*BlockHeader
+		valid   bool
+	}{
+		{
+			name:    "valid chain",
+			params:  testParams(),
+			headers: chain,
+			valid:   true,
+		},
+		{
+			name:    "connects to prev",
+			params:  testParams(),
+			prev:    chain[2],
+			headers: chain[3:],
+			valid:   true,
+		},
+		{
+			name:    "does not connect to prev",
+			params:  testParams(),
+			prev:    chain[1],
+			headers: chain[3:],
+		},
+		{
+			name:    "does not connect",
+			params:  testParams(),
+			headers: []*BlockHeader{chain[0], chain[1], chain[2], other},
+		},
+		{
+			name:    "proof of work not met",
+			params:  testParams(),
+			headers: []*BlockHeader{chain[0], mineHeader(chain[0], chainhash.Hash{}, testBits, false)},
+		},
+		{
+			name:    "target above the limit",
+			params:  testParams(),
+			headers: []*BlockHeader{chain[0], mineHeader(chain[0], chainhash.Hash{}, 0x2100ffff, true)},
+		},
+		{
+			name:    "difficulty change between retargets",
+			params:  testParams(),
+			headers: []*BlockHeader{chain[0], mineHeader(chain[0], chainhash.Hash{}, 0x203fffff, true)},
+		},
+		{
+			name:    "difficulty change with min difficulty blocks",
+			params:  minDifficultyParams,
+			headers: []*BlockHeader{chain[0], mineHeader(chain[0], chainhash.Hash{}, 0x203fffff, true)},
+			valid:   true,
+		},
+		{
+			name:    "difficulty change at retarget",
+			params:  retargetParams,
+			prev:    chain[3],
+			headers: []*BlockHeader{mineHeader(chain[3], chainhash.Hash{}, 0x203fffff, true)},
+			valid:   true,
+		},
+		{
+			name:    "difficulty change above the adjustment factor",
+			params:  retargetParams,
+			prev:    chain[3],
+			headers: []*BlockHeader{mineHeader(chain[3], chainhash.Hash{}, 0x1f7fffff, true)},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.params.checkHeaders(tc.prev, tc.headers)
+			if (err == nil) != tc.valid {
+				t.Errorf("(%v), expected valid (%v), got (%v)", tc.name, tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestCheckMerkleProof(t *testing.T) {
+	txHashes := []chainhash.Hash{{1}, {2}, {3}}
+	pair := func(a, b chainhash.Hash) chainhash.Hash {
+		return chainhash.DoubleHashH(append(append([]byte{}, a[:]...), b[:]...))
+	}
+	left, right := pair(txHashes[0], txHashes[1]), pair(txHashes[2], txHashes[2])
+	header := mineHeader(nil, pair(left, right), testBits, true)
+	header.Height = 10
+
+	tests := []struct {
+		name   string
+		txHash chainhash.Hash
+		proof  *MerkleProof
+		valid  bool
+	}{
+		{
+			name:   "first transaction",
+			txHash: txHashes[0],
+			proof:  &MerkleProof{BlockHeight: 10, Merkle: []string{txHashes[1].String(), right.String()}, Pos: 0},
+			valid:  true,
+		},
+		{
+			name:   "second transaction",
+			txHash: txHashes[1],
+			proof:  &MerkleProof{BlockHeight: 10, Merkle: []string{txHashes[0].String(), right.String()}, Pos: 1},
+			valid:  true,
+		},
+		{
+			name:   "duplicated last transaction",
+			txHash: txHashes[2],
+			proof:  &MerkleProof{BlockHeight: 10, Merkle: []string{txHashes[2].String(), left.String()}, Pos: 2},
+			valid:  true,
+		},
+		{
+			name:   "wrong position",
+			txHash: txHashes[0],
+			proof:  &MerkleProof{BlockHeight: 10, Merkle: []string{txHashes[1].String(), right.String()}, Pos: 1},
+		},
+		{
+			name:   "position out of range",
+			txHash: txHashes[0],
+			proof:  &MerkleProof{BlockHeight: 10, Merkle: []string{txHashes[1].String(), right.String()}, Pos: 4},
+		},
+		{
+			name:   "other block",
+			txHash: txHashes[0],
+			proof:  &MerkleProof{BlockHeight: 11, Merkle: []string{txHashes[1].String(), right.String()}, Pos: 0},
+		},
+		{
+			name:   "transaction not in block",
+			txHash: chainhash.Hash{4},
+			proof:  &MerkleProof{BlockHeight: 10, Merkle: []string{txHashes[1].String(), right.String()}, Pos: 0},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkMerkleProof(&tc.txHash, tc.proof, header)
+			if (err == nil) != tc.valid {
+				t.Errorf("(%v), expected valid (%v), got (%v)", tc.name, tc.valid, err)
+			}
+		})
+	}
+}
@@ -38,6 +38,12 @@ type WatchOnlyWalletParams struct {
 	WalletID       string
 	ExtendedPubKey string
 	PubPassphrase  []byte
+	// KeyScopePurpose is the BIP43 purpose of the key scope the extended
+	// public key is imported into. The loader's default scope is used if 0.
+	KeyScopePurpose uint32
+	// MasterKeyFingerprint is the fingerprint of the master key the extended
+	// public key was derived from, if known.
+	MasterKeyFingerprint uint32
 }
 
 type CreateWalletParams struct {
@@ -0,0 +1,487 @@
+package dcr
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"sort"
+
+	"decred.org/dcrwallet/v3/errors"
+	"decred.org/dcrwallet/v3/wallet/walletdb"
+	"github.com/crypto-power/cryptopower/libwallet/badgerdb"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// BdbDriver is the walletdb driver that stores the wallet in a single bolt
+	// file.
+	BdbDriver = "bdb"
+	// BadgerDriver is the walletdb driver that stores the wallet in a badger
+	// directory.
+	BadgerDriver = "badgerdb"
+
+	// copyBatchSize is the number of key/value pairs written per transaction
+	// while copying a wallet database. Badger rejects transactions that grow
+	// too big.
+	copyBatchSize = 1000
+
+	// migratingSuffix is appended to the path of the database a migration
+	// copies the wallet into until it is swapped in.
+	migratingSuffix = ".migrating"
+	// revertingSuffix is appended to the path of the migrated database while
+	// the database kept by the migration is restored.
+	revertingSuffix = ".reverting"
+)
+
+// knownTopLevelBuckets lists the top level buckets dcrwallet creates. A
+// database holding other buckets was written by an unsupported dcrwallet
+// version and is not migrated.
+var knownTopLevelBuckets = [][]byte{
+	[]byte("waddrmgr"),
+	[]byte("wtxmgr"),
+	[]byte("wstakemgr"),
+	[]byte("meta"),
+	[]byte("agendaprefs"),
+	[]byte("ticketsagendaprefs"),
+	[]byte("tspendpolicy"),
+	[]byte("treasurypolicy"),
+	[]byte("vsptspendpolicy"),
+	[]byte("vsptreasurypolicy"),
+	[]byte("vsp"),
+	[]byte("vsphost"),
+	[]byte("vsppubkey"),
+}
+
+// DatabaseDriver returns the driver of the wallet database at dbPath. Badger
+// databases are directories while bdb databases are single files.
+func DatabaseDriver(dbPath string) (string, error) {
+	info, err := os.Stat(dbPath)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return BadgerDriver, nil
+	}
+	return BdbDriver, nil
+}
+
+// DriverBackupPath returns the path the database at dbPath is kept at after
+// it has been migrated from the provided driver.
+func DriverBackupPath(dbPath, driver string) string {
+	return dbPath + "." + driver + ".bak"
+}
+
+// PendingDriverBackup returns the path of the database kept after the last
+// driver migration of the database at dbPath and whether one exists.
+func PendingDriverBackup(dbPath string) (string, bool) {
+	for _, driver := range []string{BdbDriver, BadgerDriver} {
+		backupPath := DriverBackupPath(dbPath, driver)
+		if _, err := os.Stat(backupPath); err == nil {
+			return backupPath, true
+		}
+	}
+	return "", false
+}
+
+// MigrateDatabaseDriver copies every bucket and key of the closed wallet
+// database at dbPath into a new database of the provided driver, checks the
+// copy and swaps it in. The old database is kept at DriverBackupPath until
+// it is removed or restored.
+func MigrateDatabaseDriver(dbPath, driver string) error {
+	const op errors.Op = "loader.MigrateDatabaseDriver"
+
+	currentDriver, err := DatabaseDriver(dbPath)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if currentDriver == driver {
+		return errors.E(op, errors.Invalid, "database already uses the "+driver+" driver")
+	}
+	if _, pending := PendingDriverBackup(dbPath); pending {
+		return errors.E(op, errors.Exist, "a previous driver migration is not confirmed")
+	}
+
+	// Leftovers of an interrupted migration are discarded.
+	tmpPath := dbPath + migratingSuffix
+	if err = os.RemoveAll(tmpPath); err != nil {
+		return errors.E(op, err)
+	}
+
+	buckets, err := topLevelBuckets(currentDriver, dbPath)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	for _, key := range buckets {
+		if !isKnownTopLevelBucket(key) {
+			return errors.E(op, errors.Invalid, errors.Errorf("unknown wallet database bucket %q", key))
+		}
+	}
+
+	err = copyDatabase(currentDriver, dbPath, driver, tmpPath, buckets)
+	if err == nil {
+		err = verifyCopy(currentDriver, dbPath, driver, tmpPath, buckets)
+	}
+	if err != nil {
+		os.RemoveAll(tmpPath)
+		return errors.E(op, err)
+	}
+
+	backupPath := DriverBackupPath(dbPath, currentDriver)
+	if err = os.Rename(dbPath, backupPath); err != nil {
+		os.RemoveAll(tmpPath)
+		return errors.E(op, err)
+	}
+	if err = os.Rename(tmpPath, dbPath); err != nil {
+		// Put the original database back in place.
+		if rerr := os.Rename(backupPath, dbPath); rerr != nil {
+			log.Errorf("unable to restore wallet db from %q: %v", backupPath, rerr)
+		}
+		os.RemoveAll(tmpPath)
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RestoreDriverBackup replaces the closed wallet database at dbPath with the
+// database kept by its last driver migration.
+func RestoreDriverBackup(dbPath string) error {
+	const op errors.Op = "loader.RestoreDriverBackup"
+
+	backupPath, ok := PendingDriverBackup(dbPath)
+	if !ok {
+		return errors.E(op, errors.NotExist, "no database backup to restore")
+	}
+
+	tmpPath := dbPath + revertingSuffix
+	if err := os.RemoveAll(tmpPath); err != nil {
+		return errors.E(op, err)
+	}
+	if err := os.Rename(dbPath, tmpPath); err != nil {
+		return errors.E(op, err)
+	}
+	if err := os.Rename(backupPath, dbPath); err != nil {
+		if rerr := os.Rename(tmpPath, dbPath); rerr != nil {
+			log.Errorf("unable to restore wallet db from %q: %v", tmpPath, rerr)
+		}
+		return errors.E(op, err)
+	}
+	if err := os.RemoveAll(tmpPath); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RecoverDriverMigration completes the driver migration or restore of the
+// closed database at dbPath if it was interrupted between its renames, which
+// may leave no database at dbPath. Migrations whose copy was swapped out are
+// finished and interrupted restores put the kept database back in place.
+// Leftovers of interrupted copies are removed.
+func RecoverDriverMigration(dbPath string) error {
+	const op errors.Op = "loader.RecoverDriverMigration"
+
+	exists := func(path string) bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+
+	migratingPath, revertingPath := dbPath+migratingSuffix, dbPath+revertingSuffix
+	backupPath, pending := PendingDriverBackup(dbPath)
+	if !exists(dbPath) {
+		var err error
+		switch {
+		case exists(revertingPath) && pending:
+			log.Infof("Finishing the interrupted restore of wallet db %q", backupPath)
+			err = os.Rename(backupPath, dbPath)
+		case exists(revertingPath):
+			log.Infof("Restoring wallet db %q", revertingPath)
+			err = os.Rename(revertingPath, dbPath)
+		case exists(migratingPath) && pending:
+			// The copy was verified before the original database was
+			// moved to its backup path.
+			log.Infof("Finishing the interrupted migration of wallet db %q", dbPath)
+			err = os.Rename(migratingPath, dbPath)
+		case pending:
+			log.Infof("Restoring wallet db %q", backupPath)
+			err = os.Rename(backupPath, dbPath)
+		default:
+			return nil
+		}
+		if err != nil {
+			return errors.E(op, err)
+		}
+	}
+
+	for _, path := range []string{migratingPath, revertingPath} {
+		if err := os.RemoveAll(path); err != nil {
+			return errors.E(op, err)
+		}
+	}
+	return nil
+}
+
+// RemoveDriverBackup deletes the database kept by the last driver migration
+// of the database at dbPath.
+func RemoveDriverBackup(dbPath string) error {
+	backupPath, ok := PendingDriverBackup(dbPath)
+	if !ok {
+		return nil
+	}
+	return os.RemoveAll(backupPath)
+}
+
+// dbCopier writes key/value pairs into a walletdb database, committing every
+// copyBatchSize writes.
+type dbCopier struct {
+	db   walletdb.DB
+	tx   walletdb.ReadWriteTx
+	puts int
+}
+
+// bucket returns the bucket at path in the current transaction, creating the
+// bucket and its parents if needed.
+func (c *dbCopier) bucket(path [][]byte) (walletdb.ReadWriteBucket, error) {
+	if c.tx == nil {
+		tx, err := c.db.BeginReadWriteTx()
+		if err != nil {
+			return nil, err
+		}
+		c.tx = tx
+	}
+
+	var err error
+	bucket := c.tx.ReadWriteBucket(path[0])
+	if bucket == nil {
+		bucket, err = c.tx.CreateTopLevelBucket(path[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, key := range path[1:] {
+		bucket, err = bucket.CreateBucketIfNotExists(key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return bucket, nil
+}
+
+func (c *dbCopier) put(path [][]byte, key, value []byte) error {
+	bucket, err := c.bucket(path)
+	if err != nil {
+		return err
+	}
+	if err = bucket.Put(key, value); err != nil {
+		return err
+	}
+
+	c.puts++
+	if c.puts < copyBatchSize {
+		return nil
+	}
+	return c.commit()
+}
+
+func (c *dbCopier) commit() error {
+	if c.tx == nil {
+		return nil
+	}
+	err := c.tx.Commit()
+	c.tx = nil
+	c.puts = 0
+	return err
+}
+
+func (c *dbCopier) rollback() {
+	if c.tx != nil {
+		_ = c.tx.Rollback()
+		c.tx = nil
+	}
+}
+
+// topLevelBuckets returns the sorted keys of the top level buckets of the
+// closed database of the provided driver at dbPath. walletdb transactions
+// cannot enumerate top level buckets so they are read from the bolt file of
+// bdb databases and through badgerdb for badger databases.
+func topLevelBuckets(driver, dbPath string) ([][]byte, error) {
+	var keys [][]byte
+	switch driver {
+	case BdbDriver:
+		db, err := bolt.Open(dbPath, 0o600, &bolt.Options{ReadOnly: true})
+		if err != nil {
+			return nil, err
+		}
+		err = db.View(func(tx *bolt.Tx) error {
+			return tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+				keys = append(keys, append([]byte(nil), name...))
+				return nil
+			})
+		})
+		if cerr := db.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, err
+		}
+	case BadgerDriver:
+		db, err := walletdb.Open(driver, dbPath)
+		if err != nil {
+			return nil, err
+		}
+		keys, err = badgerdb.TopLevelBuckets(db)
+		if cerr := db.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.E(errors.Invalid, "unknown database driver "+driver)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+	return keys, nil
+}
+
+func isKnownTopLevelBucket(key []byte) bool {
+	for _, known := range knownTopLevelBuckets {
+		if bytes.Equal(key, known) {
+			return true
+		}
+	}
+	return false
+}
+
+// copyDatabase creates a database of dstDriver at dstPath and copies the
+// provided top level buckets of the database at srcPath into it from a single
+// read transaction of the source.
+func copyDatabase(srcDriver, srcPath, dstDriver, dstPath string, buckets [][]byte) error {
+	src, err := walletdb.Open(srcDriver, srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := walletdb.Create(dstDriver, dstPath)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	copier := &dbCopier{db: dst}
+	err = walletdb.View(context.Background(), src, func(tx walletdb.ReadTx) error {
+		for _, key := range buckets {
+			bucket := tx.ReadBucket(key)
+			if bucket == nil {
+				return errors.Errorf("bucket %s cannot be read", key)
+			}
+			if err := copyBucket(copier, [][]byte{key}, bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		copier.rollback()
+		return err
+	}
+	return copier.commit()
+}
+
+func copyBucket(copier *dbCopier, path [][]byte, src walletdb.ReadBucket) error {
+	// Empty buckets are copied too.
+	if _, err := copier.bucket(path); err != nil {
+		return err
+	}
+
+	return src.ForEach(func(k, v []byte) error {
+		k = append([]byte(nil), k...)
+		if v == nil {
+			nested := src.NestedReadBucket(k)
+			if nested == nil {
+				return errors.Errorf("key %x holds neither a value nor a bucket", k)
+			}
+			nestedPath := append(append([][]byte(nil), path...), k)
+			return copyBucket(copier, nestedPath, nested)
+		}
+		return copier.put(path, k, append([]byte{}, v...))
+	})
+}
+
+// verifyCopy checks that the database copied to dstPath holds the provided
+// top level buckets of the database at srcPath, and no other, with the same
+// nested buckets and key/value pairs.
+func verifyCopy(srcDriver, srcPath, dstDriver, dstPath string, buckets [][]byte) error {
+	dstBuckets, err := topLevelBuckets(dstDriver, dstPath)
+	if err != nil {
+		return err
+	}
+	if len(dstBuckets) != len(buckets) {
+		return errors.Errorf("copied %d of %d buckets", len(dstBuckets), len(buckets))
+	}
+	for i, key := range buckets {
+		if !bytes.Equal(key, dstBuckets[i]) {
+			return errors.Errorf("bucket %s was not copied", key)
+		}
+	}
+
+	src, err := walletdb.Open(srcDriver, srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := walletdb.Open(dstDriver, dstPath)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	return walletdb.View(context.Background(), src, func(srcTx walletdb.ReadTx) error {
+		return walletdb.View(context.Background(), dst, func(dstTx walletdb.ReadTx) error {
+			for _, key := range buckets {
+				srcBucket, dstBucket := srcTx.ReadBucket(key), dstTx.ReadBucket(key)
+				if srcBucket == nil || dstBucket == nil {
+					return errors.Errorf("bucket %s was not copied", key)
+				}
+				if err := compareBuckets(srcBucket, dstBucket); err != nil {
+					return errors.Errorf("bucket %s: %v", key, err)
+				}
+			}
+			return nil
+		})
+	})
+}
+
+func compareBuckets(src, dst walletdb.ReadBucket) error {
+	var srcCount int
+	err := src.ForEach(func(k, v []byte) error {
+		srcCount++
+		if v == nil {
+			nested, dstNested := src.NestedReadBucket(k), dst.NestedReadBucket(k)
+			if nested == nil || dstNested == nil {
+				return errors.Errorf("nested bucket %x was not copied", k)
+			}
+			return compareBuckets(nested, dstNested)
+		}
+		if dst.NestedReadBucket(k) != nil || !bytes.Equal(v, dst.Get(k)) {
+			return errors.Errorf("value of key %x does not match", k)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var dstCount int
+	err = dst.ForEach(func(_, _ []byte) error {
+		dstCount++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if srcCount != dstCount {
+		return errors.Errorf("copied %d of %d keys", dstCount, srcCount)
+	}
+	return nil
+}
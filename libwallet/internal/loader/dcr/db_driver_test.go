@@ -0,0 +1,205 @@
+package dcr
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"decred.org/dcrwallet/v3/errors"
+	"decred.org/dcrwallet/v3/wallet/walletdb"
+)
+
+// newTestWalletDB creates a wallet database of the provided driver holding
+// the provided top level buckets, each with a value, an empty value and a
+// nested bucket.
+func newTestWalletDB(t *testing.T, driver string, buckets ...string) string {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "wallet.db")
+	db, err := walletdb.Create(driver, dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = walletdb.Update(context.Background(), db, func(tx walletdb.ReadWriteTx) error {
+		for _, name := range buckets {
+			bucket, err := tx.CreateTopLevelBucket([]byte(name))
+			if err != nil {
+				return err
+			}
+			if err = bucket.Put([]byte("key"), []byte(name)); err != nil {
+				return err
+			}
+			if err = bucket.Put([]byte("empty"), []byte{}); err != nil {
+				return err
+			}
+			nested, err := bucket.CreateBucket([]byte("nested"))
+			if err != nil {
+				return err
+			}
+			if err = nested.Put([]byte("nested key"), []byte("nested value")); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dbPath
+}
+
+func TestMigrateDatabaseDriver(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		buckets  []string
+		expected errors.Kind
+	}{
+		{name: "bdb to badger", from: BdbDriver, to: BadgerDriver, buckets: []string{"waddrmgr", "wtxmgr", "vsp"}},
+		{name: "badger to bdb", from: BadgerDriver, to: BdbDriver, buckets: []string{"waddrmgr", "meta"}},
+		{name: "unknown bucket", from: BdbDriver, to: BadgerDriver, buckets: []string{"waddrmgr", "unknown"}, expected: errors.Invalid},
+		{name: "same driver", from: BdbDriver, to: BdbDriver, buckets: []string{"waddrmgr"}, expected: errors.Invalid},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dbPath := newTestWalletDB(t, tc.from, tc.buckets...)
+
+			err := MigrateDatabaseDriver(dbPath, tc.to)
+			if tc.expected != 0 {
+				if !errors.Is(err, tc.expected) {
+					t.Fatalf("(%v), expected (%v), got (%v)", tc.name, tc.expected, err)
+				}
+				if driver, _ := DatabaseDriver(dbPath); driver != tc.from {
+					t.Errorf("(%v), expected the database to keep the (%v) driver, got (%v)", tc.name, tc.from, driver)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("(%v), expected no error, got (%v)", tc.name, err)
+			}
+
+			if driver, _ := DatabaseDriver(dbPath); driver != tc.to {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.to, driver)
+			}
+			backupPath, pending := PendingDriverBackup(dbPath)
+			if !pending || backupPath != DriverBackupPath(dbPath, tc.from) {
+				t.Errorf("(%v), expected the backup at (%v), got (%v)", tc.name, DriverBackupPath(dbPath, tc.from), backupPath)
+			}
+			if err := verifyCopy(tc.from, backupPath, tc.to, dbPath, sortedKeys(tc.buckets)); err != nil {
+				t.Errorf("(%v), expected an identical copy, got (%v)", tc.name, err)
+			}
+
+			if err := RestoreDriverBackup(dbPath); err != nil {
+				t.Fatal(err)
+			}
+			if driver, _ := DatabaseDriver(dbPath); driver != tc.from {
+				t.Errorf("(%v), expected the restored (%v) database, got (%v)", tc.name, tc.from, driver)
+			}
+		})
+	}
+}
+
+// sortedKeys returns the names as keys in the order of topLevelBuckets.
+func sortedKeys(names []string) [][]byte {
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+	keys := make([][]byte, 0, len(sorted))
+	for _, name := range sorted {
+		keys = append(keys, []byte(name))
+	}
+	return keys
+}
+
+func TestRecoverDriverMigration(t *testing.T) {
+	// Each setup leaves the database of a bdb wallet migrated to badger in
+	// the state of an interrupted step.
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, dbPath string)
+		expected string
+		pending  bool
+	}{
+		{
+			name:     "completed migration",
+			setup:    func(t *testing.T, dbPath string) {},
+			expected: BadgerDriver,
+			pending:  true,
+		},
+		{
+			name: "copy not swapped in",
+			setup: func(t *testing.T, dbPath string) {
+				rename(t, dbPath, dbPath+migratingSuffix)
+			},
+			expected: BadgerDriver,
+			pending:  true,
+		},
+		{
+			name: "backup not restored",
+			setup: func(t *testing.T, dbPath string) {
+				rename(t, dbPath, dbPath+revertingSuffix)
+			},
+			expected: BdbDriver,
+		},
+		{
+			name: "copy lost",
+			setup: func(t *testing.T, dbPath string) {
+				if err := os.RemoveAll(dbPath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			expected: BdbDriver,
+		},
+		{
+			name: "leftover copy",
+			setup: func(t *testing.T, dbPath string) {
+				if err := os.Mkdir(dbPath+migratingSuffix, 0o700); err != nil {
+					t.Fatal(err)
+				}
+			},
+			expected: BadgerDriver,
+			pending:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dbPath := newTestWalletDB(t, BdbDriver, "waddrmgr")
+			if err := MigrateDatabaseDriver(dbPath, BadgerDriver); err != nil {
+				t.Fatal(err)
+			}
+			tc.setup(t, dbPath)
+
+			if err := RecoverDriverMigration(dbPath); err != nil {
+				t.Fatalf("(%v), expected no error, got (%v)", tc.name, err)
+			}
+			if driver, err := DatabaseDriver(dbPath); driver != tc.expected {
+				t.Errorf("(%v), expected (%v), got (%v): %v", tc.name, tc.expected, driver, err)
+			}
+			if _, pending := PendingDriverBackup(dbPath); pending != tc.pending {
+				t.Errorf("(%v), expected pending backup (%v), got (%v)", tc.name, tc.pending, pending)
+			}
+			for _, path := range []string{dbPath + migratingSuffix, dbPath + revertingSuffix} {
+				if _, err := os.Stat(path); !os.IsNotExist(err) {
+					t.Errorf("(%v), expected (%v) to be removed, got (%v)", tc.name, path, err)
+				}
+			}
+		})
+	}
+
+	// Nothing is recovered without a database.
+	dbPath := filepath.Join(t.TempDir(), "wallet.db")
+	if err := RecoverDriverMigration(dbPath); err != nil {
+		t.Errorf("expected no error, got (%v)", err)
+	}
+}
+
+func rename(t *testing.T, from, to string) {
+	t.Helper()
+	if err := os.Rename(from, to); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -21,7 +21,8 @@ import (
 	_ "decred.org/dcrwallet/v3/wallet/drivers/bdb" // driver loaded during init
 )
 
-const walletDbName = "wallet.db"
+// WalletDbName is the name of the wallet database in the wallet directory.
+const WalletDbName = "wallet.db"
 
 var log = loader.Log
 
@@ -135,7 +136,7 @@ func (l *dcrLoader) CreateWatchingOnlyWallet(ctx context.Context, params *loader
 		return nil, errors.E(op, errors.Exist, "wallet already loaded")
 	}
 
-	dbPath, err := l.CreateDirPath(params.WalletID, walletDbName, utils.DCRWalletAsset)
+	dbPath, err := l.CreateDirPath(params.WalletID, WalletDbName, utils.DCRWalletAsset)
 	if err != nil {
 		return nil, errors.E(op, err)
 	}
@@ -193,7 +194,7 @@ func (l *dcrLoader) CreateNewWallet(ctx context.Context, params *loader.CreateWa
 		return nil, errors.E(op, errors.Exist, "wallet already opened")
 	}
 
-	dbPath, err := l.CreateDirPath(params.WalletID, walletDbName, utils.DCRWalletAsset)
+	dbPath, err := l.CreateDirPath(params.WalletID, WalletDbName, utils.DCRWalletAsset)
 	if err != nil {
 		return nil, errors.E(op, err)
 	}
@@ -254,13 +255,25 @@ func (l *dcrLoader) OpenExistingWallet(ctx context.Context, walletID string, pub
 	var err error
 
 	// Open the database using the boltdb backend.
-	dbPath, _, err := l.FileExists(walletID, walletDbName, utils.DCRWalletAsset)
+	dbPath, _, err := l.FileExists(walletID, WalletDbName, utils.DCRWalletAsset)
 	if err != nil {
 		log.Warnf("unable to open wallet db at %q: %v", dbPath, err)
 		return nil, errors.E(op, err)
 	}
 
-	db, err := wallet.OpenDB(l.DbDriver, dbPath)
+	if err = RecoverDriverMigration(dbPath); err != nil {
+		log.Warnf("unable to recover wallet db at %q: %v", dbPath, err)
+		return nil, errors.E(op, err)
+	}
+
+	// The wallet may have been migrated to a driver other than the one used
+	// to create new wallets.
+	driver, err := DatabaseDriver(dbPath)
+	if err != nil {
+		driver = l.DbDriver
+	}
+
+	db, err := wallet.OpenDB(driver, dbPath)
 	if err != nil {
 		log.Errorf("Failed to open database: %v", err)
 		return nil, errors.E(op, err)
@@ -318,7 +331,19 @@ func (l *dcrLoader) WalletExists(walletID string) (bool, error) {
 	l.mu.RLock()
 
 	const op errors.Op = "loader.WalletExists"
-	_, exists, err := l.FileExists(walletID, walletDbName, utils.DCRWalletAsset)
+	dbPath, exists, err := l.FileExists(walletID, WalletDbName, utils.DCRWalletAsset)
+	if err != nil {
+		return false, errors.E(op, err)
+	}
+	if exists {
+		return true, nil
+	}
+
+	// The database is missing if a driver migration was interrupted.
+	if err = RecoverDriverMigration(dbPath); err != nil {
+		return false, errors.E(op, err)
+	}
+	_, exists, err = l.FileExists(walletID, WalletDbName, utils.DCRWalletAsset)
 	if err != nil {
 		return false, errors.E(op, err)
 	}
@@ -164,10 +164,19 @@ func (l *ltcLoader) CreateWatchingOnlyWallet(_ context.Context, params *loader.W
 	// ImportAccountWithScope imports an account into the newly created watch-only wallet
 	// using the supported scope. The first parameter "default" will be the imported account's
 	// name, It doesn't matter what the account name use to be on a previous wallet.
-	//  Since the MasterFingerPrint is not provided when inputing the extended
+	//  If the MasterFingerPrint is not provided when inputing the extended
 	// public key, 0 is set instead.
-	addrSchema := waddrmgr.ScopeAddrMap[l.keyscope]
-	_, err = wal.ImportAccountWithScope("default", extendedKety, 0, l.keyscope, addrSchema)
+	keyScope := l.keyscope
+	if params.KeyScopePurpose != 0 {
+		keyScope.Purpose = params.KeyScopePurpose
+	}
+
+	addrSchema, ok := waddrmgr.ScopeAddrMap[keyScope]
+	if !ok {
+		return nil, fmt.Errorf("unsupported key scope %v", keyScope)
+	}
+
+	_, err = wal.ImportAccountWithScope("default", extendedKety, params.MasterKeyFingerprint, keyScope, addrSchema)
 	if err != nil {
 		return nil, err
 	}
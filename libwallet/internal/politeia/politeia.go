@@ -9,6 +9,7 @@ import (
 	"decred.org/dcrwallet/v3/errors"
 	"github.com/asdine/storm"
 	"github.com/asdine/storm/q"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
 )
 
 const (
@@ -28,9 +29,7 @@ type Politeia struct {
 	ctx        context.Context
 	cancelSync context.CancelFunc
 	client     *politeiaClient
-
-	syncCallbacksMtx *sync.RWMutex // Pointer required to avoid copying literal values.
-	syncCallbacks    map[string]proposalSyncCallback
+	events     *eventbus.Bus
 }
 
 const (
@@ -42,20 +41,18 @@ const (
 	ProposalCategoryAbandoned
 )
 
-func New(host string, db *storm.DB) (*Politeia, error) {
+func New(host string, db *storm.DB, events *eventbus.Bus) (*Politeia, error) {
 	if err := db.Init(&Proposal{}); err != nil {
 		log.Errorf("Error initializing politeia database: %s", err.Error())
 		return nil, err
 	}
 
 	return &Politeia{
-		host: host,
-		db:   db,
-
-		mu:               &sync.RWMutex{},
-		syncCallbacksMtx: &sync.RWMutex{},
+		host:   host,
+		db:     db,
+		events: events,
 
-		syncCallbacks: make(map[string]proposalSyncCallback),
+		mu: &sync.RWMutex{},
 	}, nil
 }
 
@@ -14,6 +14,7 @@ import (
 	"decred.org/dcrwallet/v3/wallet"
 	"decred.org/dcrwallet/v3/wallet/udb"
 	"github.com/asdine/storm"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
 	"github.com/crypto-power/cryptopower/libwallet/utils"
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/txscript/v4/stdaddr"
@@ -577,58 +578,38 @@ func (p *Politeia) CastVotes(ctx context.Context, wallet *wallet.Wallet, eligibl
 }
 
 func (p *Politeia) AddSyncCallback(syncCallback proposalSyncCallback, uniqueIdentifier string) error {
-	p.syncCallbacksMtx.Lock()
-	defer p.syncCallbacksMtx.Unlock()
-
-	if _, ok := p.syncCallbacks[uniqueIdentifier]; ok {
-		return errors.New(ErrListenerAlreadyExist)
-	}
-
-	p.syncCallbacks[uniqueIdentifier] = syncCallback
-	return nil
+	filter := eventbus.Filter{Topics: []eventbus.Topic{eventbus.TopicProposalSync}}
+	return p.events.Subscribe("politeia/"+uniqueIdentifier, filter, eventbus.Handle(func(_ eventbus.Event, e *ProposalSyncEvent) {
+		syncCallback(e.ProposalName, e.Status)
+	}))
 }
 
 func (p *Politeia) RemoveSyncCallback(uniqueIdentifier string) {
-	p.syncCallbacksMtx.Lock()
-	defer p.syncCallbacksMtx.Unlock()
+	p.events.Unsubscribe("politeia/" + uniqueIdentifier)
+}
 
-	delete(p.syncCallbacks, uniqueIdentifier)
+func (p *Politeia) publishSyncEvent(proposalName string, status utils.ProposalStatus) {
+	p.events.Publish(eventbus.Event{
+		Topic:   eventbus.TopicProposalSync,
+		Asset:   utils.DCRWalletAsset,
+		Payload: &ProposalSyncEvent{ProposalName: proposalName, Status: status},
+	})
 }
 
 func (p *Politeia) publishSynced() {
-	p.syncCallbacksMtx.Lock()
-	defer p.syncCallbacksMtx.Unlock()
-
-	for _, syncCallback := range p.syncCallbacks {
-		syncCallback("", utils.ProposalStatusSynced)
-	}
+	p.publishSyncEvent("", utils.ProposalStatusSynced)
 }
 
 func (p *Politeia) publishNewProposal(proposal *Proposal) {
-	p.syncCallbacksMtx.Lock()
-	defer p.syncCallbacksMtx.Unlock()
-
-	for _, syncCallback := range p.syncCallbacks {
-		syncCallback(proposal.Name, utils.ProposalStatusNewProposal)
-	}
+	p.publishSyncEvent(proposal.Name, utils.ProposalStatusNewProposal)
 }
 
 func (p *Politeia) publishVoteStarted(proposal *Proposal) {
-	p.syncCallbacksMtx.Lock()
-	defer p.syncCallbacksMtx.Unlock()
-
-	for _, syncCallback := range p.syncCallbacks {
-		syncCallback(proposal.Name, utils.ProposalStatusVoteStarted)
-	}
+	p.publishSyncEvent(proposal.Name, utils.ProposalStatusVoteStarted)
 }
 
 func (p *Politeia) publishVoteFinished(proposal *Proposal) {
-	p.syncCallbacksMtx.Lock()
-	defer p.syncCallbacksMtx.Unlock()
-
-	for _, syncCallback := range p.syncCallbacks {
-		syncCallback(proposal.Name, utils.ProposalStatusVoteFinished)
-	}
+	p.publishSyncEvent(proposal.Name, utils.ProposalStatusVoteFinished)
 }
 
 func getVotesCount(options []www.VoteOptionResult) (int32, int32) {
@@ -53,3 +53,10 @@ type ProposalVote struct {
 }
 
 type proposalSyncCallback func(propName string, status utils.ProposalStatus)
+
+// ProposalSyncEvent is the payload of the proposal sync events.
+type ProposalSyncEvent struct {
+	// ProposalName is empty for the events not about a single proposal.
+	ProposalName string
+	Status       utils.ProposalStatus
+}
@@ -0,0 +1,513 @@
+// Package slip39 implements the SLIP-0039 Shamir's secret-sharing scheme for
+// splitting a master secret into mnemonic shares.
+//
+// https://github.com/satoshilabs/slips/blob/master/slip-0039.md
+package slip39
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// MaxShareCount is the maximum number of shares of a group.
+	MaxShareCount = 16
+
+	// MinSecretLength is the minimum length of a master secret in bytes.
+	MinSecretLength = 16
+
+	radixBits          = 10
+	idLengthBits       = 15
+	checksumWords      = 3
+	metadataWords      = 4 + checksumWords
+	minMnemonicWords   = metadataWords + (MinSecretLength*8+radixBits-1)/radixBits
+	baseIterationCount = 10000
+	roundCount         = 4
+	secretIndex        = 255
+	digestIndex        = 254
+	digestLength       = 4
+
+	// iterationExponent is the exponent of the number of PBKDF2 iterations
+	// used to encrypt the master secret of new shares.
+	iterationExponent = 1
+)
+
+var (
+	// ErrInvalidMnemonic is returned for mnemonics that are not SLIP-0039
+	// shares.
+	ErrInvalidMnemonic = errors.New("invalid share mnemonic")
+
+	// ErrInsufficientShares is returned if the shares don't meet the group
+	// or member thresholds.
+	ErrInsufficientShares = errors.New("insufficient shares")
+
+	// ErrInvalidDigest is returned if the recovered secret doesn't match its
+	// digest, which happens when shares of different secrets are combined.
+	ErrInvalidDigest = errors.New("invalid shared secret digest")
+
+	// ErrInvalidPassphrase is returned for passphrases with characters
+	// other than printable ASCII characters.
+	ErrInvalidPassphrase = errors.New("the passphrase must only contain printable ASCII characters")
+)
+
+var (
+	expTable  [255]byte
+	logTable  [256]byte
+	wordIndex = make(map[string]int, len(wordList))
+)
+
+func init() {
+	poly := 1
+	for i := 0; i < 255; i++ {
+		expTable[i] = byte(poly)
+		logTable[poly] = byte(i)
+		// Multiply poly by x + 1 and reduce by x^8 + x^4 + x^3 + x + 1.
+		poly = (poly << 1) ^ poly
+		if poly&0x100 != 0 {
+			poly ^= 0x11b
+		}
+	}
+
+	for i, word := range wordList {
+		wordIndex[word] = i
+	}
+}
+
+// share is a decoded share mnemonic.
+type share struct {
+	identifier        uint16
+	extendable        bool
+	iterationExponent int
+	groupIndex        int
+	groupThreshold    int
+	groupCount        int
+	memberIndex       int
+	memberThreshold   int
+	value             []byte
+}
+
+type point struct {
+	x     byte
+	value []byte
+}
+
+// Split splits the master secret into count shares of a single group, any
+// threshold of which can recover the secret. The secret is encrypted with the
+// passphrase which is required to recover it.
+func Split(secret []byte, threshold, count int, passphrase []byte) ([]string, error) {
+	if len(secret) < MinSecretLength || len(secret)%2 != 0 {
+		return nil, fmt.Errorf("master secret must be at least %d bytes and of even length", MinSecretLength)
+	}
+	if threshold < 1 || threshold > count || count > MaxShareCount {
+		return nil, fmt.Errorf("invalid %d of %d shares", threshold, count)
+	}
+	if threshold == 1 && count > 1 {
+		return nil, fmt.Errorf("a threshold of 1 requires a single share")
+	}
+	if !isPrintableASCII(passphrase) {
+		return nil, ErrInvalidPassphrase
+	}
+
+	var id [2]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+	identifier := binary.BigEndian.Uint16(id[:]) & (1<<idLengthBits - 1)
+
+	encrypted := encrypt(secret, passphrase, iterationExponent, identifier, false)
+
+	// The single group holds the encrypted secret itself.
+	points, err := splitSecret(threshold, count, encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	mnemonics := make([]string, 0, len(points))
+	for _, p := range points {
+		s := &share{
+			identifier:        identifier,
+			iterationExponent: iterationExponent,
+			groupThreshold:    1,
+			groupCount:        1,
+			memberIndex:       int(p.x),
+			memberThreshold:   threshold,
+			value:             p.value,
+		}
+		mnemonics = append(mnemonics, s.mnemonic())
+	}
+	return mnemonics, nil
+}
+
+// Combine recovers the master secret from the provided share mnemonics using
+// the passphrase the secret was encrypted with. Shares of multiple groups are
+// supported.
+func Combine(mnemonics []string, passphrase []byte) ([]byte, error) {
+	if len(mnemonics) == 0 {
+		return nil, ErrInsufficientShares
+	}
+	if !isPrintableASCII(passphrase) {
+		return nil, ErrInvalidPassphrase
+	}
+
+	var first *share
+	groups := make(map[int][]*share)
+	for _, mnemonic := range mnemonics {
+		s, err := decode(mnemonic)
+		if err != nil {
+			return nil, err
+		}
+
+		if first == nil {
+			first = s
+		} else if s.identifier != first.identifier || s.extendable != first.extendable ||
+			s.iterationExponent != first.iterationExponent || s.groupThreshold != first.groupThreshold ||
+			s.groupCount != first.groupCount || len(s.value) != len(first.value) {
+			return nil, fmt.Errorf("%w: shares belong to different secrets", ErrInvalidMnemonic)
+		}
+		groups[s.groupIndex] = append(groups[s.groupIndex], s)
+	}
+
+	if len(groups) < first.groupThreshold {
+		return nil, fmt.Errorf("%w: %d of %d groups", ErrInsufficientShares, len(groups), first.groupThreshold)
+	}
+	if len(groups) > first.groupThreshold {
+		return nil, fmt.Errorf("%w: shares of %d groups provided, expected %d", ErrInvalidMnemonic, len(groups), first.groupThreshold)
+	}
+
+	groupPoints := make([]point, 0, len(groups))
+	for groupIndex, shares := range groups {
+		threshold := shares[0].memberThreshold
+		points := make([]point, 0, len(shares))
+		seen := make(map[int][]byte)
+		for _, s := range shares {
+			if s.memberThreshold != threshold {
+				return nil, fmt.Errorf("%w: mismatched member thresholds", ErrInvalidMnemonic)
+			}
+			if value, ok := seen[s.memberIndex]; ok {
+				// The same share may be entered twice but two distinct
+				// shares can't have the same index.
+				if !bytes.Equal(value, s.value) {
+					return nil, fmt.Errorf("%w: duplicate member index %d in group %d", ErrInvalidMnemonic, s.memberIndex+1, groupIndex+1)
+				}
+				continue
+			}
+			seen[s.memberIndex] = s.value
+			points = append(points, point{x: byte(s.memberIndex), value: s.value})
+		}
+
+		if len(points) < threshold {
+			return nil, fmt.Errorf("%w: %d of %d shares of group %d", ErrInsufficientShares, len(points), threshold, groupIndex+1)
+		}
+
+		secret, err := recoverSecret(threshold, points[:threshold])
+		if err != nil {
+			return nil, err
+		}
+		groupPoints = append(groupPoints, point{x: byte(groupIndex), value: secret})
+	}
+
+	encrypted, err := recoverSecret(first.groupThreshold, groupPoints[:first.groupThreshold])
+	if err != nil {
+		return nil, err
+	}
+
+	return decrypt(encrypted, passphrase, first.iterationExponent, first.identifier, first.extendable), nil
+}
+
+// IsValidMnemonic returns true if the provided mnemonic is a SLIP-0039 share
+// with a valid checksum.
+func IsValidMnemonic(mnemonic string) bool {
+	_, err := decode(mnemonic)
+	return err == nil
+}
+
+// WordList returns the SLIP-0039 wordlist.
+func WordList() []string {
+	return wordList
+}
+
+func (s *share) mnemonic() string {
+	ext := 0
+	if s.extendable {
+		ext = 1
+	}
+	idExp := int(s.identifier)<<5 | ext<<4 | s.iterationExponent
+	params := s.groupIndex<<16 | (s.groupThreshold-1)<<12 | (s.groupCount-1)<<8 |
+		s.memberIndex<<4 | (s.memberThreshold - 1)
+
+	data := []int{idExp >> 10, idExp & 1023, params >> 10, params & 1023}
+
+	valueWords := (len(s.value)*8 + radixBits - 1) / radixBits
+	value := new(big.Int).SetBytes(s.value)
+	for i := valueWords - 1; i >= 0; i-- {
+		word := new(big.Int).Rsh(value, uint(i*radixBits))
+		data = append(data, int(word.Uint64()&1023))
+	}
+	data = append(data, createChecksum(data, s.extendable)...)
+
+	words := make([]string, 0, len(data))
+	for _, index := range data {
+		words = append(words, wordList[index])
+	}
+	return strings.Join(words, " ")
+}
+
+func decode(mnemonic string) (*share, error) {
+	fields := strings.Fields(strings.ToLower(mnemonic))
+	if len(fields) < minMnemonicWords {
+		return nil, fmt.Errorf("%w: too few words", ErrInvalidMnemonic)
+	}
+
+	data := make([]int, 0, len(fields))
+	for _, word := range fields {
+		index, ok := wordIndex[word]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown word %q", ErrInvalidMnemonic, word)
+		}
+		data = append(data, index)
+	}
+
+	idExp := data[0]<<10 | data[1]
+	extendable := (idExp>>4)&1 == 1
+	if !verifyChecksum(data, extendable) {
+		return nil, fmt.Errorf("%w: invalid checksum", ErrInvalidMnemonic)
+	}
+
+	paddingBits := ((len(data) - metadataWords) * radixBits) % 16
+	if paddingBits > 8 {
+		return nil, fmt.Errorf("%w: invalid length", ErrInvalidMnemonic)
+	}
+
+	params := data[2]<<10 | data[3]
+	s := &share{
+		identifier:        uint16(idExp >> 5),
+		extendable:        extendable,
+		iterationExponent: idExp & 15,
+		groupIndex:        params >> 16,
+		groupThreshold:    (params>>12)&15 + 1,
+		groupCount:        (params>>8)&15 + 1,
+		memberIndex:       (params >> 4) & 15,
+		memberThreshold:   params&15 + 1,
+	}
+	if s.groupThreshold > s.groupCount {
+		return nil, fmt.Errorf("%w: group threshold exceeds the group count", ErrInvalidMnemonic)
+	}
+
+	value := new(big.Int)
+	for _, index := range data[4 : len(data)-checksumWords] {
+		value.Lsh(value, radixBits)
+		value.Or(value, big.NewInt(int64(index)))
+	}
+
+	valueBits := (len(data)-metadataWords)*radixBits - paddingBits
+	if value.BitLen() > valueBits {
+		return nil, fmt.Errorf("%w: invalid padding", ErrInvalidMnemonic)
+	}
+	s.value = value.FillBytes(make([]byte, valueBits/8))
+	return s, nil
+}
+
+// isPrintableASCII returns true if the passphrase only contains the ASCII
+// characters 32 to 126 as required by SLIP-0039.
+func isPrintableASCII(passphrase []byte) bool {
+	for _, c := range passphrase {
+		if c < 32 || c > 126 {
+			return false
+		}
+	}
+	return true
+}
+
+func customizationString(extendable bool) []int {
+	cs := "shamir"
+	if extendable {
+		cs = "shamir_extendable"
+	}
+	values := make([]int, 0, len(cs))
+	for _, c := range cs {
+		values = append(values, int(c))
+	}
+	return values
+}
+
+func polymod(values []int) int {
+	gen := [10]int{
+		0xe0e040, 0x1c1c080, 0x3838100, 0x7070200, 0xe0e0009,
+		0x1c0c2412, 0x38086c24, 0x3090fc48, 0x21b1f890, 0x3f3f120,
+	}
+	chk := 1
+	for _, v := range values {
+		b := chk >> 20
+		chk = (chk&0xfffff)<<10 ^ v
+		for i := 0; i < 10; i++ {
+			if (b>>i)&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func createChecksum(data []int, extendable bool) []int {
+	values := append(customizationString(extendable), data...)
+	values = append(values, make([]int, checksumWords)...)
+	chk := polymod(values) ^ 1
+	return []int{(chk >> 20) & 1023, (chk >> 10) & 1023, chk & 1023}
+}
+
+func verifyChecksum(data []int, extendable bool) bool {
+	return polymod(append(customizationString(extendable), data...)) == 1
+}
+
+// roundFunction is the Feistel round function of the master secret
+// encryption.
+func roundFunction(i int, passphrase []byte, exponent int, salt, r []byte) []byte {
+	password := append([]byte{byte(i)}, passphrase...)
+	iterations := (baseIterationCount << exponent) / roundCount
+	return pbkdf2.Key(password, append(append([]byte{}, salt...), r...), iterations, len(r), sha256.New)
+}
+
+func salt(identifier uint16, extendable bool) []byte {
+	if extendable {
+		return nil
+	}
+	return append([]byte("shamir"), byte(identifier>>8), byte(identifier))
+}
+
+func encrypt(secret, passphrase []byte, exponent int, identifier uint16, extendable bool) []byte {
+	half := len(secret) / 2
+	l, r := append([]byte{}, secret[:half]...), append([]byte{}, secret[half:]...)
+	s := salt(identifier, extendable)
+	for i := 0; i < roundCount; i++ {
+		l, r = r, xor(l, roundFunction(i, passphrase, exponent, s, r))
+	}
+	return append(r, l...)
+}
+
+func decrypt(encrypted, passphrase []byte, exponent int, identifier uint16, extendable bool) []byte {
+	half := len(encrypted) / 2
+	l, r := append([]byte{}, encrypted[:half]...), append([]byte{}, encrypted[half:]...)
+	s := salt(identifier, extendable)
+	for i := roundCount - 1; i >= 0; i-- {
+		l, r = r, xor(l, roundFunction(i, passphrase, exponent, s, r))
+	}
+	return append(r, l...)
+}
+
+func xor(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
+
+func digest(randomPart, secret []byte) []byte {
+	mac := hmac.New(sha256.New, randomPart)
+	mac.Write(secret)
+	return mac.Sum(nil)[:digestLength]
+}
+
+func splitSecret(threshold, count int, secret []byte) ([]point, error) {
+	if threshold == 1 {
+		points := make([]point, 0, count)
+		for i := 0; i < count; i++ {
+			points = append(points, point{x: byte(i), value: secret})
+		}
+		return points, nil
+	}
+
+	randomCount := threshold - 2
+	points := make([]point, 0, count)
+	for i := 0; i < randomCount; i++ {
+		value := make([]byte, len(secret))
+		if _, err := rand.Read(value); err != nil {
+			return nil, err
+		}
+		points = append(points, point{x: byte(i), value: value})
+	}
+
+	randomPart := make([]byte, len(secret)-digestLength)
+	if _, err := rand.Read(randomPart); err != nil {
+		return nil, err
+	}
+
+	base := append([]point{}, points...)
+	base = append(base,
+		point{x: digestIndex, value: append(digest(randomPart, secret), randomPart...)},
+		point{x: secretIndex, value: secret},
+	)
+
+	for i := randomCount; i < count; i++ {
+		value, err := interpolate(base, byte(i))
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point{x: byte(i), value: value})
+	}
+	return points, nil
+}
+
+func recoverSecret(threshold int, points []point) ([]byte, error) {
+	if threshold == 1 {
+		return points[0].value, nil
+	}
+
+	secret, err := interpolate(points, secretIndex)
+	if err != nil {
+		return nil, err
+	}
+	digestPoint, err := interpolate(points, digestIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(digestPoint[:digestLength], digest(digestPoint[digestLength:], secret)) {
+		return nil, ErrInvalidDigest
+	}
+	return secret, nil
+}
+
+// interpolate returns the value at x of the polynomial over GF(256) passing
+// through the provided points.
+func interpolate(points []point, x byte) ([]byte, error) {
+	for _, p := range points {
+		if p.x == x {
+			return p.value, nil
+		}
+	}
+
+	logProd := 0
+	for _, p := range points {
+		logProd += int(logTable[p.x^x])
+	}
+
+	result := make([]byte, len(points[0].value))
+	for _, p := range points {
+		if len(p.value) != len(result) {
+			return nil, fmt.Errorf("%w: mismatched share lengths", ErrInvalidMnemonic)
+		}
+
+		logBasis := logProd - int(logTable[p.x^x])
+		for _, other := range points {
+			logBasis -= int(logTable[p.x^other.x])
+		}
+		logBasis = ((logBasis % 255) + 255) % 255
+
+		for i, v := range p.value {
+			if v != 0 {
+				result[i] ^= expTable[(int(logTable[v])+logBasis)%255]
+			}
+		}
+	}
+	return result, nil
+}
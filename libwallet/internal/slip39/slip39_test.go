@@ -0,0 +1,173 @@
+package slip39
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// testPassphrase is the passphrase of the official test vectors.
+const testPassphrase = "TREZOR"
+
+// TestVectors checks Combine against the official SLIP-0039 test vectors
+// from https://github.com/trezor/python-shamir-mnemonic/blob/master/vectors.json.
+// Each vector holds a description, the share mnemonics, the master secret,
+// which is empty if the shares are invalid, and the BIP32 master key of the
+// secret.
+func TestVectors(t *testing.T) {
+	data, err := os.ReadFile("testdata/vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var vectors [][]json.RawMessage
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range vectors {
+		var name, secretHex, xprv string
+		var mnemonics []string
+		for _, field := range []struct {
+			raw json.RawMessage
+			val interface{}
+		}{{v[0], &name}, {v[1], &mnemonics}, {v[2], &secretHex}, {v[3], &xprv}} {
+			if err := json.Unmarshal(field.raw, field.val); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		t.Run(name, func(t *testing.T) {
+			secret, err := Combine(mnemonics, []byte(testPassphrase))
+			if secretHex == "" {
+				if err == nil {
+					t.Fatalf("(%v), expected an error, got (%x)", name, secret)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("(%v), expected (%v), got (%v)", name, secretHex, err)
+			}
+			if got := hex.EncodeToString(secret); got != secretHex {
+				t.Fatalf("(%v), expected (%v), got (%v)", name, secretHex, got)
+			}
+
+			key, err := hdkeychain.NewMaster(secret, &chaincfg.MainNetParams)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := key.String(); got != xprv {
+				t.Errorf("(%v), expected (%v), got (%v)", name, xprv, got)
+			}
+		})
+	}
+}
+
+func TestSplitCombine(t *testing.T) {
+	secret := bytes.Repeat([]byte{0x5a}, 32)
+	tests := []struct {
+		name       string
+		threshold  int
+		count      int
+		shares     []int
+		passphrase string
+		splitErr   bool
+		combineErr error
+	}{
+		{name: "single share", threshold: 1, count: 1, shares: []int{0}},
+		{name: "threshold shares", threshold: 3, count: 5, shares: []int{4, 0, 2}, passphrase: "pass"},
+		{name: "more than threshold shares", threshold: 2, count: 3, shares: []int{0, 1, 2}},
+		{name: "identical duplicate share", threshold: 2, count: 3, shares: []int{1, 1, 2}},
+		{name: "insufficient shares", threshold: 3, count: 5, shares: []int{0, 1}, combineErr: ErrInsufficientShares},
+		{name: "insufficient distinct shares", threshold: 2, count: 3, shares: []int{1, 1}, combineErr: ErrInsufficientShares},
+		{name: "too many shares", threshold: 2, count: MaxShareCount + 1, splitErr: true},
+		{name: "threshold of one", threshold: 1, count: 2, splitErr: true},
+		{name: "non printable passphrase", threshold: 1, count: 1, passphrase: "pass\n", splitErr: true},
+		{name: "non ascii passphrase", threshold: 1, count: 1, passphrase: "päss", splitErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mnemonics, err := Split(secret, tc.threshold, tc.count, []byte(tc.passphrase))
+			if (err != nil) != tc.splitErr {
+				t.Fatalf("(%v), expected split error (%v), got (%v)", tc.name, tc.splitErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			for _, mnemonic := range mnemonics {
+				if !IsValidMnemonic(mnemonic) {
+					t.Fatalf("(%v), expected a valid mnemonic, got (%v)", tc.name, mnemonic)
+				}
+			}
+
+			selected := make([]string, 0, len(tc.shares))
+			for _, i := range tc.shares {
+				selected = append(selected, mnemonics[i])
+			}
+
+			got, err := Combine(selected, []byte(tc.passphrase))
+			if !errors.Is(err, tc.combineErr) {
+				t.Fatalf("(%v), expected (%v), got (%v)", tc.name, tc.combineErr, err)
+			}
+			if err == nil && !bytes.Equal(got, secret) {
+				t.Errorf("(%v), expected (%x), got (%x)", tc.name, secret, got)
+			}
+		})
+	}
+}
+
+func TestCombineRejects(t *testing.T) {
+	secret := bytes.Repeat([]byte{0xa5}, 16)
+	mnemonics, err := Split(secret, 2, 3, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := decode(mnemonics[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// conflicting is another share with the member index of the first share.
+	conflicting := *s
+	conflicting.value = append([]byte{}, s.value...)
+	conflicting.value[0] ^= 1
+
+	// groups are shares of a 1 of 3 groups secret, two of which hold a
+	// single 1 of 1 share.
+	groups := make([]string, 0, 2)
+	for i := 0; i < 2; i++ {
+		g := *s
+		g.groupIndex, g.groupThreshold, g.groupCount = i, 1, 3
+		g.memberIndex, g.memberThreshold = 0, 1
+		groups = append(groups, g.mnemonic())
+	}
+
+	tests := []struct {
+		name       string
+		mnemonics  []string
+		passphrase string
+		expected   error
+	}{
+		{name: "conflicting member index", mnemonics: []string{mnemonics[0], conflicting.mnemonic(), mnemonics[1]}, expected: ErrInvalidMnemonic},
+		{name: "more groups than the threshold", mnemonics: groups, expected: ErrInvalidMnemonic},
+		{name: "non printable passphrase", mnemonics: mnemonics[:2], passphrase: "\x7f", expected: ErrInvalidPassphrase},
+		{name: "no shares", expected: ErrInsufficientShares},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Combine(tc.mnemonics, []byte(tc.passphrase))
+			if !errors.Is(err, tc.expected) {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !windows
+// +build !windows
+
+package libwallet
+
+import (
+	"os"
+	"syscall"
+)
+
+// processRunning returns true if a process with the provided ID is running.
+// Signal 0 only checks that the process exists and can be signalled.
+func processRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = process.Signal(syscall.Signal(0))
+	// EPERM means the process exists but belongs to another user.
+	return err == nil || err == syscall.EPERM
+}
@@ -0,0 +1,29 @@
+//go:build windows
+// +build windows
+
+package libwallet
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// stillActive is the exit code reported for processes that have not exited.
+const stillActive = 259
+
+// processRunning returns true if a process with the provided ID is running.
+// os.Process.Signal is not implemented on windows, so the exit code of the
+// process is queried instead.
+func processRunning(pid int) bool {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		// Access is denied to the processes of other users.
+		return err == windows.ERROR_ACCESS_DENIED
+	}
+	defer windows.CloseHandle(handle)
+
+	var exitCode uint32
+	if err := windows.GetExitCodeProcess(handle, &exitCode); err != nil {
+		return true
+	}
+	return exitCode == stillActive
+}
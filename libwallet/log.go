@@ -9,6 +9,8 @@ import (
 	"os"
 
 	"decred.org/dcrwallet/v3/errors"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
+	"github.com/crypto-power/cryptopower/libwallet/internal/electrum"
 	"github.com/crypto-power/cryptopower/libwallet/internal/loader"
 	"github.com/crypto-power/cryptopower/libwallet/internal/politeia"
 	"github.com/crypto-power/cryptopower/libwallet/internal/vsp"
@@ -47,6 +49,7 @@ var (
 
 	vspcLog     = backendLog.Logger("VSPC")
 	politeiaLog = backendLog.Logger("POLT")
+	electrumLog = backendLog.Logger("ELCM")
 )
 
 var log = slog.Disabled
@@ -56,6 +59,7 @@ var subsystemLoggers = map[string]slog.Logger{
 	"DLWL": log,
 	"VSPC": vspcLog,
 	"POLT": politeiaLog,
+	"ELCM": electrumLog,
 }
 
 // initLogRotator initializes the logging rotater to write logs to logFile and
@@ -75,8 +79,10 @@ func initLogRotator(logFile string) error {
 func UseLogger(logger slog.Logger) {
 	log = logger
 	loader.UseLogger(logger)
+	eventbus.UseLogger(logger)
 	vsp.UseLogger(vspcLog)
 	politeia.UseLogger(politeiaLog)
+	electrum.UseLogger(electrumLog)
 }
 
 // RegisterLogger should be called before logRotator is initialized.
@@ -43,6 +43,29 @@ func (mgr *AssetsManager) CreateNewLTCWallet(walletName, privatePassphrase strin
 	return wallet, nil
 }
 
+// CreateNewLTCMultisigWallet creates a new LTC multisig wallet and returns it.
+// The multisig is set up once the keys of the cosigners are known.
+func (mgr *AssetsManager) CreateNewLTCMultisigWallet(walletName, privatePassphrase string, privatePassphraseType int32) (sharedW.Asset, error) {
+	pass := &sharedW.AuthInfo{
+		Name:            walletName,
+		PrivatePass:     privatePassphrase,
+		PrivatePassType: privatePassphraseType,
+	}
+	wallet, err := ltc.CreateNewMultisigWallet(pass, mgr.params)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr.Assets.LTC.Wallets[wallet.GetWalletID()] = wallet
+
+	// extract the db interface if it hasn't been set already.
+	if mgr.db == nil && wallet != nil {
+		mgr.setDBInterface(wallet.(sharedW.AssetsManagerDB))
+	}
+
+	return wallet, nil
+}
+
 // CreateNewBTCWatchOnlyWallet creates a new BTC watch only wallet and returns it.
 func (mgr *AssetsManager) CreateNewLTCWatchOnlyWallet(walletName, extendedPublicKey string) (sharedW.Asset, error) {
 	wallet, err := ltc.CreateWatchOnlyWallet(walletName, extendedPublicKey, mgr.params)
@@ -60,10 +83,28 @@ func (mgr *AssetsManager) CreateNewLTCWatchOnlyWallet(walletName, extendedPublic
 	return wallet, nil
 }
 
+// CreateNewLTCWatchOnlyWalletFromDescriptor creates a new LTC watch only wallet
+// from an output descriptor and returns it.
+func (mgr *AssetsManager) CreateNewLTCWatchOnlyWalletFromDescriptor(walletName, descriptor string) (sharedW.Asset, error) {
+	wallet, err := ltc.CreateWatchOnlyWalletFromDescriptor(walletName, descriptor, mgr.params)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr.Assets.LTC.Wallets[wallet.GetWalletID()] = wallet
+
+	// extract the db interface if it hasn't been set already.
+	if mgr.db == nil && wallet != nil {
+		mgr.setDBInterface(wallet.(sharedW.AssetsManagerDB))
+	}
+
+	return wallet, nil
+}
+
 // LTCWalletWithSeed returns the ID of the LTC wallet that was created or restored
-// using the same seed as the one provided. Returns -1 if no wallet uses the
-// provided seed.
-func (mgr *AssetsManager) LTCWalletWithSeed(seedMnemonic string) (int, error) {
+// using the same seed and seed passphrase as the ones provided. Returns -1 if
+// no wallet uses the provided seed.
+func (mgr *AssetsManager) LTCWalletWithSeed(seedMnemonic, seedPassphrase string) (int, error) {
 	if len(seedMnemonic) == 0 {
 		return -1, errors.New(utils.ErrEmptySeed)
 	}
@@ -87,7 +128,7 @@ func (mgr *AssetsManager) LTCWalletWithSeed(seedMnemonic string) (int, error) {
 			if accs.AccountNumber == waddrmgr.ImportedAddrAccount {
 				continue
 			}
-			xpub, err := asset.DeriveAccountXpub(seedMnemonic,
+			xpub, err := asset.DeriveAccountXpub(seedMnemonic, seedPassphrase,
 				accs.AccountNumber, wallet.Internal().LTC.ChainParams())
 			if err != nil {
 				return -1, err
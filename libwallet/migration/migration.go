@@ -0,0 +1,128 @@
+// Package migration applies ordered, versioned upgrades to storm databases.
+package migration
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/asdine/storm"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Migration upgrades a database from the previous version to Version.
+type Migration struct {
+	Version     uint32
+	Description string
+	// Migrate applies the upgrade. All changes must be made through the
+	// provided node so that they are committed together with the new version.
+	Migrate func(tx storm.Node) error
+}
+
+// Progress describes the migration currently being applied.
+type Progress struct {
+	// DBName identifies the database being migrated.
+	DBName      string
+	Description string
+	// Step is the position of the migration among the Total pending ones.
+	Step  int
+	Total int
+}
+
+// ProgressListener is notified before each migration is applied.
+type ProgressListener func(progress *Progress)
+
+// Pending returns the migrations newer than currentVersion ordered by
+// version.
+func Pending(migrations []Migration, currentVersion uint32) []Migration {
+	pending := make([]Migration, 0, len(migrations))
+	for _, m := range migrations {
+		if m.Version > currentVersion {
+			pending = append(pending, m)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Version < pending[j].Version
+	})
+	return pending
+}
+
+// Run upgrades the database to version by applying the migrations newer than
+// the version saved in bucket under key. Every version up to version must have
+// a migration, an error is returned before any change is made otherwise. A copy
+// of the database file is taken before the first migration. Each migration is
+// committed together with its version, so an interrupted run resumes from the
+// first migration that was not committed.
+func Run(db *storm.DB, dbName, bucket, key string, version uint32, migrations []Migration, listener ProgressListener) error {
+	var currentVersion uint32
+	err := db.Get(bucket, key, &currentVersion)
+	if err != nil && err != storm.ErrNotFound {
+		return fmt.Errorf("error reading %s version: %v", dbName, err)
+	}
+	if currentVersion > version {
+		return fmt.Errorf("%s version %d is newer than the supported version %d", dbName, currentVersion, version)
+	}
+
+	pending := Pending(migrations, currentVersion)
+	for i, m := range pending {
+		if m.Version != currentVersion+uint32(i)+1 || m.Version > version {
+			return fmt.Errorf("unexpected migration of %s to version %d", dbName, m.Version)
+		}
+	}
+	if latest := currentVersion + uint32(len(pending)); latest != version {
+		return fmt.Errorf("no migration upgrades %s from version %d to %d", dbName, latest, version)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err = backup(db, currentVersion); err != nil {
+		return fmt.Errorf("error backing up %s: %v", dbName, err)
+	}
+
+	for i, m := range pending {
+		if listener != nil {
+			listener(&Progress{
+				DBName:      dbName,
+				Description: m.Description,
+				Step:        i + 1,
+				Total:       len(pending),
+			})
+		}
+
+		if err = apply(db, bucket, key, m); err != nil {
+			return fmt.Errorf("error migrating %s to version %d: %v", dbName, m.Version, err)
+		}
+	}
+	return nil
+}
+
+func apply(db *storm.DB, bucket, key string, m Migration) error {
+	tx, err := db.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = m.Migrate(tx); err != nil {
+		return err
+	}
+
+	if err = tx.Set(bucket, key, m.Version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// backup copies the database file next to it. An existing backup of the same
+// version is kept since it was taken before an earlier interrupted run.
+func backup(db *storm.DB, version uint32) error {
+	backupPath := fmt.Sprintf("%s.v%d.bak", db.Bolt.Path(), version)
+	if _, err := os.Stat(backupPath); err == nil {
+		return nil
+	}
+
+	return db.Bolt.View(func(tx *bolt.Tx) error {
+		return tx.CopyFile(backupPath, 0o600)
+	})
+}
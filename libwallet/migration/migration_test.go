@@ -0,0 +1,106 @@
+package migration
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/asdine/storm"
+)
+
+const (
+	testBucket = "metadata"
+	testKey    = "version"
+)
+
+// newTestDB returns a storm db at the provided version created in a temporary
+// directory.
+func newTestDB(t *testing.T, version uint32) *storm.DB {
+	t.Helper()
+	db, err := storm.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.Set(testBucket, testKey, version); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestRun(t *testing.T) {
+	errMigration := errors.New("migration failed")
+
+	tests := []struct {
+		name     string
+		current  uint32
+		version  uint32
+		versions []uint32
+		failing  uint32
+		applied  []uint32
+		expected uint32
+		valid    bool
+		backup   bool
+	}{
+		{name: "up to date", current: 3, version: 3, versions: []uint32{2, 3}, expected: 3, valid: true},
+		{name: "pending migrations", current: 1, version: 3, versions: []uint32{3, 2}, applied: []uint32{2, 3}, expected: 3, valid: true, backup: true},
+		{name: "missing last migration", current: 1, version: 4, versions: []uint32{2, 3}, expected: 1},
+		{name: "missing migration", current: 1, version: 3, versions: []uint32{3}, expected: 1},
+		{name: "no migrations", current: 2, version: 3, expected: 2},
+		{name: "migration above the version", current: 1, version: 2, versions: []uint32{2, 3}, expected: 1},
+		{name: "newer db", current: 4, version: 3, versions: []uint32{2, 3}, expected: 4},
+		{name: "failing migration", current: 1, version: 3, versions: []uint32{2, 3}, failing: 3, applied: []uint32{2}, expected: 2, backup: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t, tc.current)
+
+			var applied []uint32
+			migrations := make([]Migration, 0, len(tc.versions))
+			for _, version := range tc.versions {
+				version := version
+				migrations = append(migrations, Migration{
+					Version: version,
+					Migrate: func(tx storm.Node) error {
+						if version == tc.failing {
+							return errMigration
+						}
+						applied = append(applied, version)
+						return nil
+					},
+				})
+			}
+
+			var steps int
+			err := Run(db, "test", testBucket, testKey, tc.version, migrations, func(progress *Progress) {
+				steps++
+				if progress.Step != steps {
+					t.Errorf("(%v), expected step (%v), got (%v)", tc.name, steps, progress.Step)
+				}
+			})
+			if (err == nil) != tc.valid {
+				t.Fatalf("(%v), expected valid (%v), got (%v)", tc.name, tc.valid, err)
+			}
+			if !reflect.DeepEqual(applied, tc.applied) {
+				t.Errorf("(%v), expected migrations (%v), got (%v)", tc.name, tc.applied, applied)
+			}
+
+			var version uint32
+			if err := db.Get(testBucket, testKey, &version); err != nil {
+				t.Fatal(err)
+			}
+			if version != tc.expected {
+				t.Errorf("(%v), expected version (%v), got (%v)", tc.name, tc.expected, version)
+			}
+
+			_, err = os.Stat(filepath.Join(filepath.Dir(db.Bolt.Path()), "test.db.v1.bak"))
+			if backup := err == nil; backup != tc.backup {
+				t.Errorf("(%v), expected backup (%v), got (%v)", tc.name, tc.backup, backup)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package libwallet
+
+import (
+	"decred.org/dcrwallet/v3/errors"
+	"github.com/asdine/storm"
+
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+)
+
+// loadProxy applies the saved proxy config. It must run before any network
+// connection is made.
+func (mgr *AssetsManager) loadProxy() error {
+	cfg := new(utils.ProxyConfig)
+	err := mgr.params.DB.Get(walletsMetadataBucketName, sharedW.ProxyConfigKey, cfg)
+	if err == storm.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return utils.SetProxy(cfg)
+}
+
+// SetProxy saves cfg and routes every network connection of the app through
+// it. A nil cfg or one without an address removes the proxy. Wallets that
+// are connected to the network are disconnected so that their peers are
+// reconnected through the new route on the next sync.
+func (mgr *AssetsManager) SetProxy(cfg *utils.ProxyConfig) error {
+	const op errors.Op = "mgr.SetProxy"
+
+	if err := utils.SetProxy(cfg); err != nil {
+		return errors.E(op, err)
+	}
+
+	var err error
+	if cfg == nil || cfg.Address == "" {
+		err = mgr.params.DB.Delete(walletsMetadataBucketName, sharedW.ProxyConfigKey)
+		if err == storm.ErrNotFound {
+			err = nil
+		}
+	} else {
+		err = mgr.params.DB.Set(walletsMetadataBucketName, sharedW.ProxyConfigKey, cfg)
+	}
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	for _, wallet := range mgr.AllWallets() {
+		if wallet.IsSyncing() || wallet.IsSynced() {
+			wallet.CancelSync()
+		}
+	}
+
+	if mgr.RateSource != nil {
+		go mgr.RateSource.Reconnect()
+	}
+	return nil
+}
+
+// ProxyConfig returns the proxy config in use or nil if network connections
+// are not proxied.
+func (mgr *AssetsManager) ProxyConfig() *utils.ProxyConfig {
+	return utils.CurrentProxy()
+}
+
+// IsProxied returns true if the app's network traffic is routed through a
+// proxy.
+func (mgr *AssetsManager) IsProxied() bool {
+	return utils.IsProxied()
+}
@@ -0,0 +1,683 @@
+package libwallet
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"decred.org/dcrwallet/v3/errors"
+	"github.com/asdine/storm"
+
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+)
+
+const (
+	// syncManagerListenerID identifies the sync progress listener the sync
+	// manager adds to every wallet.
+	syncManagerListenerID = "sync_manager"
+
+	// retryBaseDelay is the wait before the first retry of a failed sync, it
+	// doubles after every failed retry up to retryMaxDelay.
+	retryBaseDelay = 10 * time.Second
+	retryMaxDelay  = 10 * time.Minute
+)
+
+// SyncPolicy controls how the sync manager schedules the wallets syncs.
+type SyncPolicy struct {
+	// MaxConcurrent is the number of wallets that sync at the same time, zero
+	// means no limit.
+	MaxConcurrent int `json:"max_concurrent"`
+	// Sequential syncs the wallets one after the other, which uses less
+	// memory on low-end devices. It overrides MaxConcurrent.
+	Sequential bool `json:"sequential"`
+	// RetryFailed restarts the syncs that ended with an error after a delay
+	// that grows with every failed attempt.
+	RetryFailed bool `json:"retry_failed"`
+	// MaxRetries is the number of retries before a sync is marked failed,
+	// zero means no limit.
+	MaxRetries int `json:"max_retries"`
+}
+
+// DefaultSyncPolicy returns the policy used until the user sets one.
+func DefaultSyncPolicy() *SyncPolicy {
+	return &SyncPolicy{
+		RetryFailed: true,
+		MaxRetries:  5,
+	}
+}
+
+// concurrency returns the number of wallets allowed to sync at the same time,
+// zero means no limit.
+func (policy *SyncPolicy) concurrency() int {
+	if policy.Sequential {
+		return 1
+	}
+	if policy.MaxConcurrent < 0 {
+		return 0
+	}
+	return policy.MaxConcurrent
+}
+
+// WalletSyncState is the scheduling state of a wallet in the sync manager.
+type WalletSyncState string
+
+const (
+	// WalletSyncIdle is the state of the wallets that are not syncing.
+	WalletSyncIdle WalletSyncState = "idle"
+	// WalletSyncQueued is the state of the wallets waiting for a free sync
+	// slot.
+	WalletSyncQueued WalletSyncState = "queued"
+	// WalletSyncSyncing is the state of the wallets that are syncing.
+	WalletSyncSyncing WalletSyncState = "syncing"
+	// WalletSyncSynced is the state of the wallets that are synced.
+	WalletSyncSynced WalletSyncState = "synced"
+	// WalletSyncRetrying is the state of the wallets waiting to retry a
+	// failed sync.
+	WalletSyncRetrying WalletSyncState = "retrying"
+	// WalletSyncFailed is the state of the wallets whose sync failed and will
+	// not be retried.
+	WalletSyncFailed WalletSyncState = "failed"
+)
+
+// WalletSyncStatus is the sync status of a single wallet.
+type WalletSyncStatus struct {
+	WalletID   int
+	WalletName string
+	AssetType  utils.AssetType
+	State      WalletSyncState
+	// Stage is the sync stage of syncing wallets.
+	Stage utils.SyncStage
+	// Progress is the sync progress of the wallet in percent.
+	Progress             int32
+	TimeRemainingSeconds int64
+	// HeadersToFetch is the number of block headers left to fetch in the
+	// headers fetch stage.
+	HeadersToFetch int32
+	// Err is the error the last sync ended with.
+	Err       error
+	Retries   int
+	NextRetry time.Time
+}
+
+// IsActive returns true if the wallet is syncing or scheduled to sync.
+func (status *WalletSyncStatus) IsActive() bool {
+	switch status.State {
+	case WalletSyncQueued, WalletSyncSyncing, WalletSyncRetrying:
+		return true
+	}
+	return false
+}
+
+// SyncStatus is the sync status of all the wallets.
+type SyncStatus struct {
+	// Wallets holds the status of every wallet sorted by wallet ID.
+	Wallets []*WalletSyncStatus
+	// Progress is the merged sync progress, in percent, of the wallets that
+	// are syncing, scheduled to sync or synced.
+	Progress int32
+	// TimeRemainingSeconds estimates the time left for the syncing and the
+	// queued wallets to be synced.
+	TimeRemainingSeconds int64
+
+	Syncing int
+	Queued  int
+	Synced  int
+	Failed  int
+}
+
+// IsSyncing returns true if a wallet is syncing or scheduled to sync.
+func (status *SyncStatus) IsSyncing() bool {
+	for _, wallet := range status.Wallets {
+		if wallet.IsActive() {
+			return true
+		}
+	}
+	return false
+}
+
+// Wallet returns the status of the wallet with walletID or nil if the wallet
+// is unknown.
+func (status *SyncStatus) Wallet(walletID int) *WalletSyncStatus {
+	for _, wallet := range status.Wallets {
+		if wallet.WalletID == walletID {
+			return wallet
+		}
+	}
+	return nil
+}
+
+// SyncStatusListener is notified of the changes of the merged sync status.
+type SyncStatusListener struct {
+	OnSyncStatusChanged func(status *SyncStatus)
+	OnWalletSynced      func(walletID int)
+}
+
+type walletSync struct {
+	asset  sharedW.Asset
+	status WalletSyncStatus
+	// paused is set when the user stops the sync so that the cancellation is
+	// not retried.
+	paused     bool
+	retryTimer *time.Timer
+}
+
+// SyncManager follows the sync of every wallet, merges their progress and
+// schedules their syncs according to the sync policy.
+type SyncManager struct {
+	mgr *AssetsManager
+
+	mtx     sync.Mutex
+	policy  *SyncPolicy
+	wallets map[int]*walletSync
+	queue   []int
+	stopped bool
+}
+
+func newSyncManager(mgr *AssetsManager) *SyncManager {
+	m := &SyncManager{
+		mgr:     mgr,
+		policy:  DefaultSyncPolicy(),
+		wallets: make(map[int]*walletSync),
+	}
+
+	policy := new(SyncPolicy)
+	err := mgr.params.DB.Get(walletsMetadataBucketName, sharedW.SyncPolicyConfigKey, policy)
+	if err == nil {
+		m.policy = policy
+	} else if err != storm.ErrNotFound {
+		log.Errorf("Error reading the sync policy: %v", err)
+	}
+
+	m.track()
+	return m
+}
+
+// track adds the wallets the sync manager does not follow yet and drops the
+// deleted wallets. The wallets are called without holding m.mtx since their
+// callbacks lock it.
+func (m *SyncManager) track() {
+	wallets := m.mgr.AllWallets()
+
+	m.mtx.Lock()
+	existing := make(map[int]bool, len(wallets))
+	var added []sharedW.Asset
+	for _, asset := range wallets {
+		walletID := asset.GetWalletID()
+		existing[walletID] = true
+		if ws, ok := m.wallets[walletID]; ok {
+			ws.status.WalletName = asset.GetWalletName()
+		} else {
+			added = append(added, asset)
+		}
+	}
+	for walletID, ws := range m.wallets {
+		if !existing[walletID] {
+			if ws.retryTimer != nil {
+				ws.retryTimer.Stop()
+			}
+			delete(m.wallets, walletID)
+			m.queue = removeWalletID(m.queue, walletID)
+		}
+	}
+	m.mtx.Unlock()
+
+	for _, asset := range added {
+		walletID := asset.GetWalletID()
+		ws := &walletSync{
+			asset: asset,
+			status: WalletSyncStatus{
+				WalletID:   walletID,
+				WalletName: asset.GetWalletName(),
+				AssetType:  asset.GetAssetType(),
+				State:      WalletSyncIdle,
+			},
+		}
+		if asset.IsSynced() {
+			ws.status.State = WalletSyncSynced
+			ws.status.Progress = 100
+		} else if asset.IsSyncing() {
+			ws.status.State = WalletSyncSyncing
+		}
+
+		m.mtx.Lock()
+		_, ok := m.wallets[walletID]
+		if !ok {
+			m.wallets[walletID] = ws
+		}
+		m.mtx.Unlock()
+		if ok {
+			continue
+		}
+
+		err := asset.AddSyncProgressListener(m.syncProgressListener(walletID), syncManagerListenerID)
+		if err != nil && err.Error() != utils.ErrListenerAlreadyExist {
+			log.Errorf("[%d] Error adding the sync manager listener: %v", walletID, err)
+		}
+	}
+}
+
+func (m *SyncManager) syncProgressListener(walletID int) *sharedW.SyncProgressListener {
+	progress := func(stage utils.SyncStage, general *sharedW.GeneralSyncProgress, headersToFetch int32) {
+		m.update(walletID, func(ws *walletSync) {
+			if ws.status.State == WalletSyncSynced {
+				// Progress reports of the new blocks received once synced.
+				return
+			}
+			ws.status.State = WalletSyncSyncing
+			ws.status.Stage = stage
+			ws.status.HeadersToFetch = headersToFetch
+			if general != nil {
+				ws.status.Progress = general.TotalSyncProgress
+				ws.status.TimeRemainingSeconds = general.TotalTimeRemainingSeconds
+			}
+		})
+	}
+
+	return &sharedW.SyncProgressListener{
+		OnSyncStarted: func() {
+			m.update(walletID, func(ws *walletSync) {
+				m.queue = removeWalletID(m.queue, walletID)
+				ws.paused = false
+				ws.status.State = WalletSyncSyncing
+				ws.status.Err = nil
+			})
+		},
+		OnCFiltersFetchProgress: func(report *sharedW.CFiltersFetchProgressReport) {
+			progress(utils.CFiltersFetchSyncStage, report.GeneralSyncProgress, 0)
+		},
+		OnHeadersFetchProgress: func(report *sharedW.HeadersFetchProgressReport) {
+			progress(utils.HeadersFetchSyncStage, report.GeneralSyncProgress, report.TotalHeadersToFetch)
+		},
+		OnAddressDiscoveryProgress: func(report *sharedW.AddressDiscoveryProgressReport) {
+			progress(utils.AddressDiscoverySyncStage, report.GeneralSyncProgress, 0)
+		},
+		OnHeadersRescanProgress: func(report *sharedW.HeadersRescanProgressReport) {
+			progress(utils.HeadersRescanSyncStage, report.GeneralSyncProgress, 0)
+		},
+		OnSyncCompleted:      func() { m.syncCompleted(walletID) },
+		OnSyncCanceled:       func(willRestart bool) { m.syncCanceled(walletID, willRestart) },
+		OnSyncEndedWithError: func(err error) { m.syncFailed(walletID, err) },
+	}
+}
+
+// update applies fn to the wallet with walletID and notifies the listeners.
+// The wallet callbacks run with the wallet locks held, fn must not call the
+// wallet.
+func (m *SyncManager) update(walletID int, fn func(ws *walletSync)) {
+	m.mtx.Lock()
+	ws, ok := m.wallets[walletID]
+	if !ok {
+		m.mtx.Unlock()
+		return
+	}
+	fn(ws)
+	m.mtx.Unlock()
+
+	m.notify()
+}
+
+func (m *SyncManager) syncCompleted(walletID int) {
+	var wasSynced bool
+	m.update(walletID, func(ws *walletSync) {
+		wasSynced = ws.status.State == WalletSyncSynced
+		ws.status.State = WalletSyncSynced
+		ws.status.Stage = utils.InvalidSyncStage
+		ws.status.Progress = 100
+		ws.status.TimeRemainingSeconds = 0
+		ws.status.HeadersToFetch = 0
+		ws.status.Err = nil
+		ws.status.Retries = 0
+	})
+
+	if wasSynced {
+		return
+	}
+
+	m.mgr.events.Publish(eventbus.Event{Topic: eventbus.TopicWalletSynced, WalletID: walletID})
+	go m.dispatch()
+}
+
+func (m *SyncManager) syncCanceled(walletID int, willRestart bool) {
+	if willRestart {
+		return
+	}
+
+	m.update(walletID, func(ws *walletSync) {
+		// A cancellation following a failure keeps the wallet in its
+		// retrying or failed state.
+		if ws.status.State == WalletSyncSyncing || ws.status.State == WalletSyncSynced {
+			ws.status.State = WalletSyncIdle
+			ws.status.Stage = utils.InvalidSyncStage
+			ws.status.Progress = 0
+			ws.status.TimeRemainingSeconds = 0
+		}
+	})
+	go m.dispatch()
+}
+
+func (m *SyncManager) syncFailed(walletID int, err error) {
+	m.update(walletID, func(ws *walletSync) {
+		ws.status.Err = err
+		ws.status.Stage = utils.InvalidSyncStage
+		ws.status.TimeRemainingSeconds = 0
+
+		if ws.paused || m.stopped || !m.policy.RetryFailed ||
+			(m.policy.MaxRetries > 0 && ws.status.Retries >= m.policy.MaxRetries) {
+			ws.status.State = WalletSyncFailed
+			return
+		}
+
+		delay := retryBaseDelay << uint(ws.status.Retries)
+		if delay <= 0 || delay > retryMaxDelay {
+			delay = retryMaxDelay
+		}
+		ws.status.Retries++
+		ws.status.State = WalletSyncRetrying
+		ws.status.NextRetry = time.Now().Add(delay)
+		ws.retryTimer = time.AfterFunc(delay, func() { m.retry(walletID) })
+	})
+	go m.dispatch()
+}
+
+func (m *SyncManager) retry(walletID int) {
+	m.update(walletID, func(ws *walletSync) {
+		ws.retryTimer = nil
+		if ws.status.State == WalletSyncRetrying && !m.stopped {
+			m.enqueue(ws)
+		}
+	})
+	m.dispatch()
+}
+
+// enqueue schedules the sync of ws. The caller must hold m.mtx.
+func (m *SyncManager) enqueue(ws *walletSync) {
+	ws.paused = false
+	ws.status.State = WalletSyncQueued
+	ws.status.NextRetry = time.Time{}
+	m.queue = append(removeWalletID(m.queue, ws.status.WalletID), ws.status.WalletID)
+}
+
+// dispatch starts the queued syncs allowed by the sync policy.
+func (m *SyncManager) dispatch() {
+	m.mtx.Lock()
+	if m.stopped {
+		m.mtx.Unlock()
+		return
+	}
+
+	syncing := 0
+	for _, ws := range m.wallets {
+		if ws.status.State == WalletSyncSyncing {
+			syncing++
+		}
+	}
+
+	limit := m.policy.concurrency()
+	var toStart []*walletSync
+	for len(m.queue) > 0 && (limit == 0 || syncing < limit) {
+		walletID := m.queue[0]
+		m.queue = m.queue[1:]
+		ws, ok := m.wallets[walletID]
+		if !ok || ws.status.State != WalletSyncQueued {
+			continue
+		}
+		ws.status.State = WalletSyncSyncing
+		toStart = append(toStart, ws)
+		syncing++
+	}
+	m.mtx.Unlock()
+
+	if len(toStart) == 0 {
+		return
+	}
+	m.notify()
+
+	for _, ws := range toStart {
+		go func(ws *walletSync) {
+			err := ws.asset.SpvSync()
+			if err != nil && err.Error() != utils.ErrSyncAlreadyInProgress {
+				walletID := ws.asset.GetWalletID()
+				log.Errorf("[%d] Error starting sync: %v", walletID, err)
+				m.syncFailed(walletID, err)
+			}
+		}(ws)
+	}
+}
+
+// Start schedules the sync of the wallet with walletID. The sync starts right
+// away unless the sync policy limits the number of concurrent syncs.
+func (m *SyncManager) Start(walletID int) error {
+	m.track()
+
+	m.mtx.Lock()
+	ws, ok := m.wallets[walletID]
+	if !ok {
+		m.mtx.Unlock()
+		return errors.New(utils.ErrNotExist)
+	}
+	switch ws.status.State {
+	case WalletSyncSyncing, WalletSyncSynced, WalletSyncQueued:
+	default:
+		if ws.retryTimer != nil {
+			ws.retryTimer.Stop()
+			ws.retryTimer = nil
+		}
+		ws.status.Retries = 0
+		ws.status.Err = nil
+		m.enqueue(ws)
+	}
+	m.mtx.Unlock()
+
+	m.notify()
+	m.dispatch()
+	return nil
+}
+
+// StartAll schedules the sync of every wallet that can sync without being
+// unlocked first.
+func (m *SyncManager) StartAll() {
+	for _, asset := range m.mgr.AllWallets() {
+		if !asset.WalletOpened() {
+			continue
+		}
+		if !asset.ContainsDiscoveredAccounts() && asset.IsLocked() && !asset.IsWatchingOnlyWallet() {
+			// The account discovery needs the wallet to be unlocked.
+			continue
+		}
+		if err := m.Start(asset.GetWalletID()); err != nil {
+			log.Errorf("[%d] Error scheduling sync: %v", asset.GetWalletID(), err)
+		}
+	}
+}
+
+// Pause stops the sync of the wallet with walletID and removes it from the
+// sync queue.
+func (m *SyncManager) Pause(walletID int) {
+	m.mtx.Lock()
+	ws, ok := m.wallets[walletID]
+	if !ok {
+		m.mtx.Unlock()
+		return
+	}
+	ws.paused = true
+	if ws.retryTimer != nil {
+		ws.retryTimer.Stop()
+		ws.retryTimer = nil
+	}
+	m.queue = removeWalletID(m.queue, walletID)
+	if ws.status.State == WalletSyncQueued || ws.status.State == WalletSyncRetrying {
+		ws.status.State = WalletSyncIdle
+	}
+	asset := ws.asset
+	m.mtx.Unlock()
+
+	// The wallet notifies the cancellation through the sync manager listener,
+	// m.mtx must not be held.
+	if asset.IsConnectedToNetwork() {
+		asset.CancelSync()
+	}
+	m.notify()
+}
+
+// PauseAll stops the sync of every wallet and empties the sync queue.
+func (m *SyncManager) PauseAll() {
+	m.mtx.Lock()
+	walletIDs := make([]int, 0, len(m.wallets))
+	for walletID := range m.wallets {
+		walletIDs = append(walletIDs, walletID)
+	}
+	m.mtx.Unlock()
+
+	for _, walletID := range walletIDs {
+		m.Pause(walletID)
+	}
+}
+
+// Policy returns the sync policy in use.
+func (m *SyncManager) Policy() *SyncPolicy {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	policy := *m.policy
+	return &policy
+}
+
+// SetPolicy saves policy and applies it to the queued syncs. The syncs
+// already running are not stopped.
+func (m *SyncManager) SetPolicy(policy *SyncPolicy) error {
+	const op errors.Op = "syncManager.SetPolicy"
+
+	if policy == nil {
+		policy = DefaultSyncPolicy()
+	}
+	if policy.MaxConcurrent < 0 || policy.MaxRetries < 0 {
+		return errors.E(op, utils.ErrInvalid)
+	}
+
+	if err := m.mgr.params.DB.Set(walletsMetadataBucketName, sharedW.SyncPolicyConfigKey, policy); err != nil {
+		return errors.E(op, err)
+	}
+
+	m.mtx.Lock()
+	policyCopy := *policy
+	m.policy = &policyCopy
+	m.mtx.Unlock()
+
+	m.dispatch()
+	return nil
+}
+
+// Status returns the sync status of every wallet and their merged progress.
+func (m *SyncManager) Status() *SyncStatus {
+	m.track()
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.status()
+}
+
+// status builds the merged sync status. The caller must hold m.mtx.
+func (m *SyncManager) status() *SyncStatus {
+	status := &SyncStatus{Wallets: make([]*WalletSyncStatus, 0, len(m.wallets))}
+
+	var progressSum int64
+	var counted int
+	for _, ws := range m.wallets {
+		walletStatus := ws.status
+		status.Wallets = append(status.Wallets, &walletStatus)
+
+		switch walletStatus.State {
+		case WalletSyncSyncing:
+			status.Syncing++
+			if walletStatus.TimeRemainingSeconds > status.TimeRemainingSeconds {
+				status.TimeRemainingSeconds = walletStatus.TimeRemainingSeconds
+			}
+		case WalletSyncQueued, WalletSyncRetrying:
+			status.Queued++
+		case WalletSyncSynced:
+			status.Synced++
+		case WalletSyncFailed:
+			status.Failed++
+			continue
+		default:
+			continue
+		}
+		progressSum += int64(walletStatus.Progress)
+		counted++
+	}
+
+	if counted > 0 {
+		status.Progress = int32(progressSum / int64(counted))
+	}
+
+	// The queued wallets sync after the running ones, every batch is assumed
+	// to take as long as the slowest running sync.
+	if limit := m.policy.concurrency(); limit > 0 && status.Queued > 0 {
+		batches := (status.Queued + limit - 1) / limit
+		status.TimeRemainingSeconds += int64(batches) * status.TimeRemainingSeconds
+	}
+
+	sort.Slice(status.Wallets, func(i, j int) bool {
+		return status.Wallets[i].WalletID < status.Wallets[j].WalletID
+	})
+	return status
+}
+
+// AddSyncStatusListener registers a listener notified of every change of the
+// merged sync status. The current status is passed to the new listener first.
+func (m *SyncManager) AddSyncStatusListener(listener *SyncStatusListener, uniqueIdentifier string) error {
+	filter := eventbus.Filter{Topics: []eventbus.Topic{eventbus.TopicSyncStatus, eventbus.TopicWalletSynced}}
+	return m.mgr.events.Subscribe("syncstatus/"+uniqueIdentifier, filter, func(e eventbus.Event) {
+		switch e.Topic {
+		case eventbus.TopicSyncStatus:
+			if listener.OnSyncStatusChanged != nil {
+				listener.OnSyncStatusChanged(e.Payload.(*SyncStatus))
+			}
+		case eventbus.TopicWalletSynced:
+			if listener.OnWalletSynced != nil {
+				listener.OnWalletSynced(e.WalletID)
+			}
+		}
+	})
+}
+
+// RemoveSyncStatusListener unregisters a sync status listener.
+func (m *SyncManager) RemoveSyncStatusListener(uniqueIdentifier string) {
+	m.mgr.events.Unsubscribe("syncstatus/" + uniqueIdentifier)
+}
+
+// notify publishes the merged sync status. The status is published while
+// holding m.mtx so that concurrent changes are published in order.
+func (m *SyncManager) notify() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.mgr.events.PublishState(eventbus.Event{Topic: eventbus.TopicSyncStatus, Payload: m.status()})
+}
+
+// stop cancels the pending retries and empties the sync queue.
+func (m *SyncManager) stop() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.stopped = true
+	m.queue = nil
+	for _, ws := range m.wallets {
+		if ws.retryTimer != nil {
+			ws.retryTimer.Stop()
+			ws.retryTimer = nil
+		}
+	}
+}
+
+func removeWalletID(walletIDs []int, walletID int) []int {
+	kept := walletIDs[:0]
+	for _, id := range walletIDs {
+		if id != walletID {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
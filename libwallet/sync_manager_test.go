@@ -0,0 +1,299 @@
+package libwallet
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
+)
+
+const waitTimeout = 5 * time.Second
+
+// stubAsset is the wallet stand-in of the sync manager tests. Only the
+// methods called by the scheduling are implemented, it reports the syncs the
+// sync manager starts on started.
+type stubAsset struct {
+	sharedW.Asset
+
+	walletID int
+	started  chan int
+}
+
+func (asset *stubAsset) GetWalletID() int { return asset.walletID }
+
+func (asset *stubAsset) SpvSync() error {
+	asset.started <- asset.walletID
+	return nil
+}
+
+// newTestSyncManager returns a sync manager following walletIDs with policy,
+// the wallets report the syncs started on the returned channel.
+func newTestSyncManager(policy *SyncPolicy, walletIDs ...int) (*SyncManager, chan int) {
+	started := make(chan int, len(walletIDs))
+	m := &SyncManager{
+		mgr:     &AssetsManager{events: eventbus.New()},
+		policy:  policy,
+		wallets: make(map[int]*walletSync),
+	}
+	for _, walletID := range walletIDs {
+		m.wallets[walletID] = &walletSync{
+			asset:  &stubAsset{walletID: walletID, started: started},
+			status: WalletSyncStatus{WalletID: walletID, State: WalletSyncIdle},
+		}
+	}
+	return m, started
+}
+
+// receiveStarted waits for n syncs to be started.
+func receiveStarted(t *testing.T, started chan int, n int) []int {
+	t.Helper()
+	walletIDs := make([]int, 0, n)
+	for len(walletIDs) < n {
+		select {
+		case walletID := <-started:
+			walletIDs = append(walletIDs, walletID)
+		case <-time.After(waitTimeout):
+			t.Fatalf("expected (%d) syncs started, got (%d)", n, len(walletIDs))
+		}
+	}
+	return walletIDs
+}
+
+func TestSyncPolicyConcurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   SyncPolicy
+		expected int
+	}{
+		{name: "default", policy: *DefaultSyncPolicy(), expected: 0},
+		{name: "limited", policy: SyncPolicy{MaxConcurrent: 2}, expected: 2},
+		{name: "negative", policy: SyncPolicy{MaxConcurrent: -1}, expected: 0},
+		{name: "sequential", policy: SyncPolicy{MaxConcurrent: 3, Sequential: true}, expected: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.policy.concurrency(); got != tc.expected {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestSyncManagerDispatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *SyncPolicy
+		queued  []int
+		syncing []int
+		// expected is the sorted IDs of the wallets started by dispatch.
+		expected []int
+		// queue is the sync queue left once dispatched.
+		queue []int
+	}{
+		{
+			name:     "unlimited",
+			policy:   &SyncPolicy{},
+			queued:   []int{1, 2, 3},
+			expected: []int{1, 2, 3},
+			queue:    []int{},
+		},
+		{
+			name:     "sequential",
+			policy:   &SyncPolicy{Sequential: true},
+			queued:   []int{2, 1, 3},
+			expected: []int{2},
+			queue:    []int{1, 3},
+		},
+		{
+			name:     "limited",
+			policy:   &SyncPolicy{MaxConcurrent: 2},
+			queued:   []int{3, 1, 2},
+			expected: []int{1, 3},
+			queue:    []int{2},
+		},
+		{
+			name:     "slots taken",
+			policy:   &SyncPolicy{MaxConcurrent: 2},
+			queued:   []int{1, 2},
+			syncing:  []int{3, 4},
+			expected: []int{},
+			queue:    []int{1, 2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m, started := newTestSyncManager(tc.policy, 1, 2, 3, 4)
+			for _, walletID := range tc.syncing {
+				m.wallets[walletID].status.State = WalletSyncSyncing
+			}
+			for _, walletID := range tc.queued {
+				m.enqueue(m.wallets[walletID])
+			}
+
+			m.dispatch()
+
+			got := receiveStarted(t, started, len(tc.expected))
+			sort.Ints(got)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("(%v), expected started (%v), got (%v)", tc.name, tc.expected, got)
+			}
+			if !reflect.DeepEqual(append([]int{}, m.queue...), tc.queue) {
+				t.Errorf("(%v), expected queue (%v), got (%v)", tc.name, tc.queue, m.queue)
+			}
+			for _, walletID := range tc.expected {
+				if state := m.wallets[walletID].status.State; state != WalletSyncSyncing {
+					t.Errorf("(%v), expected wallet (%d) (%v), got (%v)", tc.name, walletID, WalletSyncSyncing, state)
+				}
+			}
+		})
+	}
+}
+
+func TestSyncManagerDispatchNext(t *testing.T) {
+	m, started := newTestSyncManager(&SyncPolicy{Sequential: true}, 1, 2)
+	m.enqueue(m.wallets[1])
+	m.enqueue(m.wallets[2])
+
+	m.dispatch()
+	if got := receiveStarted(t, started, 1); got[0] != 1 {
+		t.Fatalf("expected wallet (1) started first, got (%d)", got[0])
+	}
+
+	// The queued wallet starts once the running sync completes.
+	m.syncCompleted(1)
+	if got := receiveStarted(t, started, 1); got[0] != 2 {
+		t.Fatalf("expected wallet (2) started next, got (%d)", got[0])
+	}
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if state := m.wallets[1].status.State; state != WalletSyncSynced {
+		t.Errorf("expected wallet (1) (%v), got (%v)", WalletSyncSynced, state)
+	}
+}
+
+func TestSyncManagerSyncFailed(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        *SyncPolicy
+		retries       int
+		paused        bool
+		expectedState WalletSyncState
+		// expectedDelay is the delay before the retry of retrying wallets.
+		expectedDelay time.Duration
+	}{
+		{
+			name:          "first retry",
+			policy:        DefaultSyncPolicy(),
+			expectedState: WalletSyncRetrying,
+			expectedDelay: retryBaseDelay,
+		},
+		{
+			name:          "backoff",
+			policy:        DefaultSyncPolicy(),
+			retries:       3,
+			expectedState: WalletSyncRetrying,
+			expectedDelay: 8 * retryBaseDelay,
+		},
+		{
+			name:          "max delay",
+			policy:        &SyncPolicy{RetryFailed: true},
+			retries:       20,
+			expectedState: WalletSyncRetrying,
+			expectedDelay: retryMaxDelay,
+		},
+		{
+			name:          "max retries",
+			policy:        DefaultSyncPolicy(),
+			retries:       5,
+			expectedState: WalletSyncFailed,
+		},
+		{
+			name:          "retry disabled",
+			policy:        &SyncPolicy{},
+			expectedState: WalletSyncFailed,
+		},
+		{
+			name:          "paused",
+			policy:        DefaultSyncPolicy(),
+			paused:        true,
+			expectedState: WalletSyncFailed,
+		},
+	}
+
+	syncErr := errors.New("sync failed")
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m, _ := newTestSyncManager(tc.policy, 1)
+			ws := m.wallets[1]
+			ws.status.State = WalletSyncSyncing
+			ws.status.Retries = tc.retries
+			ws.paused = tc.paused
+
+			before := time.Now()
+			m.syncFailed(1, syncErr)
+			m.stop()
+
+			m.mtx.Lock()
+			defer m.mtx.Unlock()
+			if ws.status.State != tc.expectedState {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.expectedState, ws.status.State)
+			}
+			if ws.status.Err != syncErr {
+				t.Errorf("(%v), expected error (%v), got (%v)", tc.name, syncErr, ws.status.Err)
+			}
+			if tc.expectedState != WalletSyncRetrying {
+				return
+			}
+			if ws.status.Retries != tc.retries+1 {
+				t.Errorf("(%v), expected (%v) retries, got (%v)", tc.name, tc.retries+1, ws.status.Retries)
+			}
+			delay := ws.status.NextRetry.Sub(before)
+			if delay < tc.expectedDelay || delay > tc.expectedDelay+time.Minute {
+				t.Errorf("(%v), expected a (%v) delay, got (%v)", tc.name, tc.expectedDelay, delay)
+			}
+		})
+	}
+}
+
+func TestSyncManagerStatus(t *testing.T) {
+	m, _ := newTestSyncManager(&SyncPolicy{MaxConcurrent: 1}, 1, 2, 3, 4, 5)
+	set := func(walletID int, state WalletSyncState, progress int32, remaining int64) {
+		m.wallets[walletID].status.State = state
+		m.wallets[walletID].status.Progress = progress
+		m.wallets[walletID].status.TimeRemainingSeconds = remaining
+	}
+	set(1, WalletSyncSyncing, 40, 100)
+	set(2, WalletSyncSynced, 100, 0)
+	set(3, WalletSyncQueued, 0, 0)
+	set(4, WalletSyncFailed, 20, 0)
+	set(5, WalletSyncIdle, 0, 0)
+
+	status := m.status()
+
+	// The failed and idle wallets are left out of the merged progress.
+	if status.Progress != (40+100+0)/3 {
+		t.Errorf("expected progress (%v), got (%v)", (40+100+0)/3, status.Progress)
+	}
+	// The queued wallet syncs in one more batch after the running one.
+	if status.TimeRemainingSeconds != 200 {
+		t.Errorf("expected (%v) seconds remaining, got (%v)", 200, status.TimeRemainingSeconds)
+	}
+	counts := []int{status.Syncing, status.Queued, status.Synced, status.Failed}
+	if !reflect.DeepEqual(counts, []int{1, 1, 1, 1}) {
+		t.Errorf("expected syncing, queued, synced and failed counts (%v), got (%v)", []int{1, 1, 1, 1}, counts)
+	}
+	for i, wallet := range status.Wallets {
+		if wallet.WalletID != i+1 {
+			t.Errorf("expected wallet (%d) at (%d), got (%d)", i+1, i, wallet.WalletID)
+		}
+	}
+	if !status.IsSyncing() {
+		t.Errorf("expected the status to be syncing")
+	}
+}
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// DevService identifies an external service that developer mode can point at
+// a local stand-in.
+type DevService string
+
+const (
+	DcrdataService     DevService = "dcrdata"
+	PoliteiaService    DevService = "politeia"
+	VSPListService     DevService = "vsp-list"
+	BTCFeeRateService  DevService = "btc-fee-rate"
+	LTCFeeRateService  DevService = "ltc-fee-rate"
+	RateSourceService  DevService = "rate-source"
+	DCRExplorerService DevService = "dcr-explorer"
+	BTCExplorerService DevService = "btc-explorer"
+	LTCExplorerService DevService = "ltc-explorer"
+)
+
+// DevServices lists every service developer mode can redirect.
+var DevServices = []DevService{
+	DcrdataService,
+	PoliteiaService,
+	VSPListService,
+	BTCFeeRateService,
+	LTCFeeRateService,
+	RateSourceService,
+	DCRExplorerService,
+	BTCExplorerService,
+	LTCExplorerService,
+}
+
+// DevModeConfig points the external services of the app at local stand-ins.
+// A stand-in must serve the same API as the service it replaces; only the
+// scheme and host of the requests, and an optional path prefix, change.
+type DevModeConfig struct {
+	Enabled bool `json:"enabled"`
+	// ServiceURLs maps services to the base URL of their stand-in, e.g.
+	// http://127.0.0.1:7777 for a local dcrdata. While developer mode is
+	// enabled, services without a stand-in are not contacted at all.
+	ServiceURLs map[DevService]string `json:"service_urls"`
+}
+
+var (
+	devModeMtx sync.RWMutex
+	devModeCfg *DevModeConfig
+)
+
+// SetDevMode applies cfg. A nil cfg or one that is not enabled turns
+// developer mode off.
+func SetDevMode(cfg *DevModeConfig) error {
+	if cfg != nil && !cfg.Enabled {
+		cfg = nil
+	}
+	if cfg != nil {
+		urls := make(map[DevService]string, len(cfg.ServiceURLs))
+		for service, rawURL := range cfg.ServiceURLs {
+			rawURL = strings.TrimSpace(rawURL)
+			if rawURL == "" {
+				continue
+			}
+			if _, err := parseStandInURL(rawURL); err != nil {
+				return err
+			}
+			urls[service] = rawURL
+		}
+		cfg = &DevModeConfig{Enabled: true, ServiceURLs: urls}
+	}
+
+	devModeMtx.Lock()
+	devModeCfg = cfg
+	devModeMtx.Unlock()
+	return nil
+}
+
+// CurrentDevMode returns a copy of the developer mode config in use or nil if
+// developer mode is off.
+func CurrentDevMode() *DevModeConfig {
+	devModeMtx.RLock()
+	defer devModeMtx.RUnlock()
+	if devModeCfg == nil {
+		return nil
+	}
+	urls := make(map[DevService]string, len(devModeCfg.ServiceURLs))
+	for service, rawURL := range devModeCfg.ServiceURLs {
+		urls[service] = rawURL
+	}
+	return &DevModeConfig{Enabled: true, ServiceURLs: urls}
+}
+
+// IsDevMode returns true if developer mode is on.
+func IsDevMode() bool {
+	devModeMtx.RLock()
+	defer devModeMtx.RUnlock()
+	return devModeCfg != nil
+}
+
+// ServiceURL returns the URL rawURL of service must be requested at. rawURL
+// is returned unchanged when developer mode is off. Otherwise its scheme and
+// host are replaced with the ones of the service's stand-in and the path of
+// the stand-in is prepended to its path. ErrDevServiceNotSet is returned if
+// the service has no stand-in.
+func ServiceURL(service DevService, rawURL string) (string, error) {
+	devModeMtx.RLock()
+	if devModeCfg == nil {
+		devModeMtx.RUnlock()
+		return rawURL, nil
+	}
+	standIn := devModeCfg.ServiceURLs[service]
+	devModeMtx.RUnlock()
+
+	if standIn == "" {
+		return "", ErrDevServiceNotSet
+	}
+	base, err := parseStandInURL(standIn)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	u.Scheme = base.Scheme
+	u.Host = base.Host
+	u.User = base.User
+	u.Path = strings.TrimSuffix(base.Path, "/") + "/" + strings.TrimPrefix(u.Path, "/")
+	u.RawPath = ""
+	return u.String(), nil
+}
+
+func parseStandInURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, ErrInvalidDevServiceURL
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return u, nil
+	default:
+		return nil, ErrInvalidDevServiceURL
+	}
+}
@@ -58,6 +58,18 @@ var (
 	ErrUnsupporttedIPV6Address = errors.New("IPv6 addresses unsupportted by the current network")
 	ErrNetConnectionTimeout    = errors.New("Timeout on network connection")
 	ErrPeerConnectionRejected  = errors.New("Peer connection rejected")
+
+	ErrInvalidProxyAddress    = errors.New("invalid proxy address")
+	ErrInvalidProxyDNSServer  = errors.New("invalid proxy dns server address")
+	ErrProxyCannotResolve     = errors.New("the proxy cannot resolve host names and no dns server is set")
+	ErrOnionRequiresProxy     = errors.New("onion addresses can only be reached through a proxy")
+	ErrNodeRequiresDirectHost = errors.New("only nodes on a private network can be synced from while a proxy is in use")
+
+	ErrInvalidBirthday = errors.New("birthday must be between the genesis block time and now")
+
+	ErrDevModeNotSupported  = errors.New("developer mode is only available on simnet and regnet")
+	ErrInvalidDevServiceURL = errors.New("stand-in URL must be an absolute http or https URL")
+	ErrDevServiceNotSet     = errors.New("no stand-in is set for this service in developer mode")
 )
 
 // todo, should update this method to translate more error kinds.
@@ -66,19 +66,6 @@ var (
 	activeAPIs map[string]*Client
 )
 
-// DialerFunc returns a customized dialer function that is make it easier to
-// control node level tcp connections especially after a shutdown. It also
-// includes a timeout value preventing a connection waiting forever for a
-// response to be returned.
-func DialerFunc(ctx context.Context) Dailer {
-	d := &net.Dialer{
-		Timeout: defaultHTTPClientTimeout,
-	}
-	return func(addr net.Addr) (net.Conn, error) {
-		return d.DialContext(ctx, addr.Network(), addr.String())
-	}
-}
-
 func init() {
 	netC = monitorNetwork{}
 
@@ -87,21 +74,45 @@ func init() {
 	activeAPIs = make(map[string]*Client)
 }
 
-// newClient configures and returns a new client
-func newClient() (c *Client) {
+// newClient configures and returns a new client for the provided host. With
+// stream isolation, each host's requests use their own proxy circuit.
+func newClient(host string) (c *Client) {
 	// Initialize context use to cancel all pending requests when shutdown request is made.
 	ctx, cancel := context.WithCancel(context.Background())
 
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = ProxyFromEnvironment
+	transport.DialContext = ProxyDialContext(HTTPSubsystem + ProxySubsystem(":"+host))
+
 	return &Client{
 		context:    ctx,
 		cancelFunc: cancel,
 		HTTPClient: &http.Client{
 			Timeout:   defaultHTTPClientTimeout,
-			Transport: http.DefaultTransport.(*http.Transport).Clone(),
+			Transport: transport,
 		},
 	}
 }
 
+// resetHTTPClients drops the cached clients and their idle connections, and
+// the idle connections of the default transport.
+func resetHTTPClients() {
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport.CloseIdleConnections()
+	}
+
+	apiMtx.Lock()
+	defer apiMtx.Unlock()
+	if activeAPIs == nil {
+		// The clients were shutdown.
+		return
+	}
+	for _, c := range activeAPIs {
+		c.HTTPClient.CloseIdleConnections()
+	}
+	activeAPIs = make(map[string]*Client)
+}
+
 // ShutdownHTTPClients shutdowns any active connection by cancelling the context.
 func ShutdownHTTPClients() {
 	apiMtx.Lock()
@@ -207,7 +218,7 @@ func HTTPRequest(reqConfig *ReqConfig, respObj interface{}) (*http.Response, err
 	apiMtx.Lock()
 	client, ok := activeAPIs[urlPath.Host]
 	if !ok {
-		client = newClient()
+		client = newClient(urlPath.Host)
 	}
 	apiMtx.Unlock()
 
@@ -248,8 +259,19 @@ func IsOnline() bool {
 		return netC.isConnected
 	}
 
-	// DNS lookup failed if err != nil.
-	_, err := net.LookupHost(addressToLookUp)
+	var err error
+	if proxy := CurrentProxy(); proxy != nil {
+		// DNS lookups would bypass the proxy, check that the proxy is
+		// reachable instead.
+		var conn net.Conn
+		conn, err = net.DialTimeout("tcp", proxy.Address, defaultHTTPClientTimeout)
+		if err == nil {
+			conn.Close()
+		}
+	} else {
+		// DNS lookup failed if err != nil.
+		_, err = net.LookupHost(addressToLookUp)
+	}
 
 	// if err == nil, the internet link is up.
 	netC.isConnected = err == nil
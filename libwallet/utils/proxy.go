@@ -0,0 +1,304 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/btcsuite/btcd/connmgr"
+	"github.com/decred/go-socks/socks"
+)
+
+// ProxySubsystem identifies the part of the app a connection is made for.
+// When stream isolation is enabled, the connections of each subsystem are
+// sent over their own Tor circuits.
+type ProxySubsystem string
+
+const (
+	HTTPSubsystem      ProxySubsystem = "http"
+	WebsocketSubsystem ProxySubsystem = "websocket"
+	VSPSubsystem       ProxySubsystem = "vsp"
+	MixerSubsystem     ProxySubsystem = "mixer"
+	DCRPeersSubsystem  ProxySubsystem = "dcr-peers"
+	BTCPeersSubsystem  ProxySubsystem = "btc-peers"
+	LTCPeersSubsystem  ProxySubsystem = "ltc-peers"
+	DNSSubsystem       ProxySubsystem = "dns"
+
+	onionSuffix = ".onion"
+)
+
+// ProxyConfig is the SOCKS5 proxy every network connection of the app is
+// routed through.
+type ProxyConfig struct {
+	// Address is the host:port of the SOCKS5 proxy, e.g. 127.0.0.1:9050 for
+	// a local Tor daemon.
+	Address  string `json:"address"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// StreamIsolation makes the connections of each subsystem use different
+	// proxy credentials, which Tor uses to keep them on separate circuits.
+	// The configured credentials are ignored when it is set.
+	StreamIsolation bool `json:"stream_isolation"`
+	// DNSServer is the host:port of the DNS server queried over tcp through
+	// proxies that do not resolve names themselves, unlike Tor. Names are not
+	// resolved through such proxies if it is empty.
+	DNSServer string `json:"dns_server,omitempty"`
+}
+
+var (
+	proxyMtx sync.RWMutex
+	proxyCfg *ProxyConfig
+
+	// isolationPassword is combined with the subsystem name to build the
+	// stream isolation credentials. It changes every time the app starts so
+	// circuits are not reused across sessions.
+	isolationPassword string
+
+	defaultTransportOnce sync.Once
+)
+
+func init() {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err == nil {
+		isolationPassword = hex.EncodeToString(b[:])
+	}
+}
+
+// proxyDefaultTransport routes the default http transport through the proxy
+// in use. The libraries that make requests with http.Get or a client without
+// a transport, like the exchanges', use the default transport. It is only
+// changed once a proxy is set, its dial function follows later proxy changes.
+func proxyDefaultTransport() {
+	defaultTransportOnce.Do(func() {
+		if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+			transport.Proxy = ProxyFromEnvironment
+			transport.DialContext = ProxyDialContext(HTTPSubsystem)
+		}
+	})
+}
+
+// SetProxy routes every new network connection through the SOCKS5 proxy in
+// cfg. A nil cfg or one without an address disables the proxy. Idle http
+// connections are dropped so that no request reuses a connection made
+// through the previous route.
+func SetProxy(cfg *ProxyConfig) error {
+	if cfg != nil && cfg.Address == "" {
+		cfg = nil
+	}
+	if cfg != nil {
+		host, port, err := net.SplitHostPort(cfg.Address)
+		if err != nil || host == "" || port == "" {
+			return ErrInvalidProxyAddress
+		}
+		if cfg.DNSServer != "" {
+			host, port, err = net.SplitHostPort(cfg.DNSServer)
+			if err != nil || host == "" || port == "" {
+				return ErrInvalidProxyDNSServer
+			}
+		}
+		cfg = &ProxyConfig{
+			Address:         cfg.Address,
+			Username:        cfg.Username,
+			Password:        cfg.Password,
+			StreamIsolation: cfg.StreamIsolation,
+			DNSServer:       cfg.DNSServer,
+		}
+	}
+
+	proxyMtx.Lock()
+	proxyCfg = cfg
+	proxyMtx.Unlock()
+
+	if cfg != nil {
+		proxyDefaultTransport()
+	}
+
+	resetHTTPClients()
+	return nil
+}
+
+// CurrentProxy returns a copy of the proxy config in use or nil if network
+// connections are not proxied.
+func CurrentProxy() *ProxyConfig {
+	proxyMtx.RLock()
+	defer proxyMtx.RUnlock()
+	if proxyCfg == nil {
+		return nil
+	}
+	cfg := *proxyCfg
+	return &cfg
+}
+
+// IsProxied returns true if network connections are routed through a proxy.
+func IsProxied() bool {
+	proxyMtx.RLock()
+	defer proxyMtx.RUnlock()
+	return proxyCfg != nil
+}
+
+// IsOnionHost returns true if addr is a Tor onion service address. addr may
+// include a port.
+func IsOnionHost(addr string) bool {
+	host := addr
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		host = h
+	}
+	return strings.HasSuffix(strings.ToLower(host), onionSuffix)
+}
+
+// socksProxy returns the SOCKS5 dialer used for the connections of the
+// subsystem or nil if connections are not proxied.
+func socksProxy(subsystem ProxySubsystem) *socks.Proxy {
+	proxyMtx.RLock()
+	defer proxyMtx.RUnlock()
+	if proxyCfg == nil {
+		return nil
+	}
+
+	proxy := &socks.Proxy{
+		Addr:     proxyCfg.Address,
+		Username: proxyCfg.Username,
+		Password: proxyCfg.Password,
+	}
+	if proxyCfg.StreamIsolation {
+		proxy.Username = string(subsystem)
+		proxy.Password = isolationPassword
+	}
+	return proxy
+}
+
+// ProxyDialContext returns a dial function for the connections of the
+// subsystem. The proxy in use is looked up on every dial so that long lived
+// clients follow proxy changes.
+func ProxyDialContext(subsystem ProxySubsystem) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		ctx, cancel := context.WithTimeout(ctx, defaultHTTPClientTimeout)
+		defer cancel()
+
+		proxy := socksProxy(subsystem)
+		if proxy == nil {
+			if IsOnionHost(addr) {
+				return nil, ErrOnionRequiresProxy
+			}
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		}
+		// SOCKS5 only carries tcp streams, neutrino's onion addresses
+		// included.
+		return proxy.DialContext(ctx, "tcp", addr)
+	}
+}
+
+// NodeDialContext returns a dial function for the connections of the
+// subsystem to a node the wallet syncs from. Nodes on private hosts are
+// dialed directly and the others through the proxy in use, if any.
+func NodeDialContext(subsystem ProxySubsystem) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	proxyDial := ProxyDialContext(subsystem)
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if !IsPrivateHost(addr) {
+			return proxyDial(ctx, network, addr)
+		}
+		ctx, cancel := context.WithTimeout(ctx, defaultHTTPClientTimeout)
+		defer cancel()
+		var d net.Dialer
+		return d.DialContext(ctx, network, addr)
+	}
+}
+
+// CheckDirectNodeHosts is used for the node clients that cannot dial through
+// the proxy. It returns ErrNodeRequiresDirectHost if connections are proxied
+// and any of hosts is not private, so that they are not made outside the
+// proxy. hosts may be host:port addresses or URLs such as ZMQ endpoints,
+// empty hosts are ignored.
+func CheckDirectNodeHosts(hosts ...string) error {
+	if !IsProxied() {
+		return nil
+	}
+	for _, host := range hosts {
+		if host == "" {
+			continue
+		}
+		if u, err := url.Parse(host); err == nil && u.Host != "" {
+			host = u.Host
+		}
+		if !IsPrivateHost(host) {
+			return ErrNodeRequiresDirectHost
+		}
+	}
+	return nil
+}
+
+// IsPrivateHost returns true if addr is localhost or a loopback, private
+// network or link local IP address. addr may include a port. Other host
+// names are not resolved and are not private.
+func IsPrivateHost(addr string) bool {
+	host := addr
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		host = h
+	}
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast())
+}
+
+// ProxyLookupIP resolves host through the proxy when connections are
+// proxied so that DNS queries do not leak. Tor resolves the host with its
+// RESOLVE extension. Other SOCKS5 proxies don't support it, the host is
+// then resolved by the DNS server of the proxy config over a tcp connection
+// made through the proxy. ErrProxyCannotResolve is returned if no DNS server
+// is configured.
+func ProxyLookupIP(host string) ([]net.IP, error) {
+	proxy := CurrentProxy()
+	if proxy == nil {
+		return net.LookupIP(host)
+	}
+	if IsOnionHost(host) {
+		return nil, errors.New("onion addresses cannot be resolved")
+	}
+
+	ips, err := connmgr.TorLookupIP(host, proxy.Address)
+	if err == nil {
+		return ips, nil
+	}
+	if proxy.DNSServer == "" {
+		return nil, fmt.Errorf("%w: %v", ErrProxyCannotResolve, err)
+	}
+
+	dial := ProxyDialContext(DNSSubsystem)
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dial(ctx, "tcp", proxy.DNSServer)
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultHTTPClientTimeout)
+	defer cancel()
+	addrs, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	ips = make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		ips = append(ips, addr.IP)
+	}
+	return ips, nil
+}
+
+// ProxyFromEnvironment is used as the Proxy of http transports. The proxy
+// settings of the environment are ignored while connections are routed
+// through the app's SOCKS5 proxy.
+func ProxyFromEnvironment(req *http.Request) (*url.URL, error) {
+	if IsProxied() {
+		return nil, nil
+	}
+	return http.ProxyFromEnvironment(req)
+}
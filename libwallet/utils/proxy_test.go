@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsPrivateHost(t *testing.T) {
+	tests := []struct {
+		name     string
+		addr     string
+		expected bool
+	}{
+		{name: "localhost", addr: "localhost:8332", expected: true},
+		{name: "loopback", addr: "127.0.0.1", expected: true},
+		{name: "ipv6 loopback", addr: "[::1]:9109", expected: true},
+		{name: "private network", addr: "192.168.1.20:9109", expected: true},
+		{name: "private ipv6 network", addr: "[fd00::1]:9109", expected: true},
+		{name: "link local", addr: "169.254.10.1:9109", expected: true},
+		{name: "public ip", addr: "8.8.8.8:9109"},
+		{name: "host name", addr: "node.example.com:9109"},
+		{name: "onion", addr: "abcdefghijklmnop.onion:9109"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsPrivateHost(tc.addr); got != tc.expected {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestCheckDirectNodeHosts(t *testing.T) {
+	defer func() {
+		_ = SetProxy(nil)
+	}()
+
+	tests := []struct {
+		name     string
+		proxy    *ProxyConfig
+		hosts    []string
+		expected error
+	}{
+		{name: "no proxy", hosts: []string{"8.8.8.8:8332"}},
+		{name: "private hosts", proxy: &ProxyConfig{Address: "127.0.0.1:9050"}, hosts: []string{"127.0.0.1:8332", "tcp://192.168.1.2:28332", ""}},
+		{name: "public host", proxy: &ProxyConfig{Address: "127.0.0.1:9050"}, hosts: []string{"127.0.0.1:8332", "8.8.8.8:8332"}, expected: ErrNodeRequiresDirectHost},
+		{name: "public zmq endpoint", proxy: &ProxyConfig{Address: "127.0.0.1:9050"}, hosts: []string{"tcp://node.example.com:28332"}, expected: ErrNodeRequiresDirectHost},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := SetProxy(tc.proxy); err != nil {
+				t.Fatal(err)
+			}
+			if err := CheckDirectNodeHosts(tc.hosts...); !errors.Is(err, tc.expected) {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestSetProxyDNSServer(t *testing.T) {
+	defer SetProxy(nil)
+
+	tests := []struct {
+		name      string
+		dnsServer string
+		expected  error
+	}{
+		{name: "no dns server"},
+		{name: "dns server", dnsServer: "9.9.9.9:53"},
+		{name: "missing port", dnsServer: "9.9.9.9", expected: ErrInvalidProxyDNSServer},
+		{name: "missing host", dnsServer: ":53", expected: ErrInvalidProxyDNSServer},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := SetProxy(&ProxyConfig{Address: "127.0.0.1:9050", DNSServer: tc.dnsServer})
+			if !errors.Is(err, tc.expected) {
+				t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestProxyLookupIPWithoutDNSServer(t *testing.T) {
+	defer SetProxy(nil)
+
+	// Nothing listens on port 1 so the Tor lookup fails and, without a dns
+	// server, the host must not be resolved outside the proxy.
+	if err := SetProxy(&ProxyConfig{Address: "127.0.0.1:1"}); err != nil {
+		t.Fatalf("SetProxy: %v", err)
+	}
+	if _, err := ProxyLookupIP("localhost"); !errors.Is(err, ErrProxyCannotResolve) {
+		t.Errorf("expected (%v), got (%v)", ErrProxyCannotResolve, err)
+	}
+}
@@ -0,0 +1,313 @@
+package libwallet
+
+import (
+	"context"
+	"sync"
+
+	"decred.org/dcrwallet/v3/errors"
+
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
+	"github.com/crypto-power/cryptopower/libwallet/migration"
+	"github.com/crypto-power/cryptopower/libwallet/utils"
+)
+
+// maxConcurrentWalletLoads is the number of wallets loaded or opened at the
+// same time on startup.
+const maxConcurrentWalletLoads = 4
+
+// WalletOpenState is the startup state of a wallet.
+type WalletOpenState string
+
+const (
+	// WalletOpenPending is the state of the wallets waiting for a free
+	// worker to open them.
+	WalletOpenPending WalletOpenState = "pending"
+	// WalletOpenOpening is the state of the wallets being opened.
+	WalletOpenOpening WalletOpenState = "opening"
+	// WalletOpenOpened is the state of the wallets that are ready for use.
+	WalletOpenOpened WalletOpenState = "opened"
+	// WalletOpenFailed is the state of the wallets that could not be opened.
+	WalletOpenFailed WalletOpenState = "failed"
+	// WalletOpenDeferred is the state of the wallets that are only opened
+	// when first accessed.
+	WalletOpenDeferred WalletOpenState = "deferred"
+)
+
+// WalletOpenStatus is the startup status of a single wallet.
+type WalletOpenStatus struct {
+	WalletID int
+	State    WalletOpenState
+	// Migration is the wallet data migration being applied while the wallet
+	// is opening, if any.
+	Migration *migration.Progress
+	// Err is the error the last attempt to open the wallet failed with.
+	Err error
+}
+
+// IsLoading returns true if the wallet is waiting to be opened or is being
+// opened.
+func (status *WalletOpenStatus) IsLoading() bool {
+	return status.State == WalletOpenPending || status.State == WalletOpenOpening
+}
+
+// WalletOpenListener is notified when the startup state of a wallet changes.
+type WalletOpenListener struct {
+	OnWalletOpenStateChanged func(status *WalletOpenStatus)
+}
+
+type walletOpen struct {
+	status WalletOpenStatus
+	// done is closed once the wallet being opened is opened or failed to
+	// open.
+	done chan struct{}
+}
+
+// walletOpener follows the wallets opened in the background after startup.
+type walletOpener struct {
+	mtx     sync.Mutex
+	wallets map[int]*walletOpen
+	events  *eventbus.Bus
+}
+
+func newWalletOpener(events *eventbus.Bus) *walletOpener {
+	return &walletOpener{
+		wallets: make(map[int]*walletOpen),
+		events:  events,
+	}
+}
+
+// setState sets the state of the wallet with walletID and notifies the
+// listeners.
+func (o *walletOpener) setState(walletID int, state WalletOpenState, err error) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	wo := o.wallet(walletID)
+	wo.status.State = state
+	wo.status.Migration = nil
+	wo.status.Err = err
+	o.notify(wo.status)
+}
+
+// migrating records the wallet data migration applied to the wallet with
+// walletID while it is opening and notifies the listeners.
+func (o *walletOpener) migrating(walletID int, progress *migration.Progress) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	wo := o.wallet(walletID)
+	if wo.status.State != WalletOpenOpening {
+		return
+	}
+	wo.status.Migration = progress
+	o.notify(wo.status)
+}
+
+// begin marks the wallet with walletID as opening. If the wallet is already
+// being opened, begin returns false with the channel closed once it is done.
+func (o *walletOpener) begin(walletID int) (bool, chan struct{}) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	wo := o.wallet(walletID)
+	if wo.status.State == WalletOpenOpening {
+		return false, wo.done
+	}
+	wo.status.State = WalletOpenOpening
+	wo.status.Migration = nil
+	wo.status.Err = nil
+	wo.done = make(chan struct{})
+	o.notify(wo.status)
+	return true, wo.done
+}
+
+// finish records the result of opening the wallet with walletID and releases
+// the callers waiting for it.
+func (o *walletOpener) finish(walletID int, err error) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	wo := o.wallet(walletID)
+	wo.status.State = WalletOpenOpened
+	if err != nil {
+		wo.status.State = WalletOpenFailed
+	}
+	wo.status.Migration = nil
+	wo.status.Err = err
+	if wo.done != nil {
+		close(wo.done)
+		wo.done = nil
+	}
+	o.notify(wo.status)
+}
+
+// wallet returns the entry of the wallet with walletID, adding it if missing.
+// The caller must hold o.mtx.
+func (o *walletOpener) wallet(walletID int) *walletOpen {
+	wo, ok := o.wallets[walletID]
+	if !ok {
+		wo = &walletOpen{status: WalletOpenStatus{WalletID: walletID}}
+		o.wallets[walletID] = wo
+	}
+	return wo
+}
+
+// notify publishes status. The caller must hold o.mtx so that the changes of
+// a wallet state are published in order.
+func (o *walletOpener) notify(status WalletOpenStatus) {
+	o.events.PublishState(eventbus.Event{
+		Topic:    eventbus.TopicWalletOpenState,
+		WalletID: status.WalletID,
+		Payload:  &status,
+	})
+}
+
+func (o *walletOpener) status(walletID int) (WalletOpenStatus, bool) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	wo, ok := o.wallets[walletID]
+	if !ok {
+		return WalletOpenStatus{}, false
+	}
+	return wo.status, true
+}
+
+func (o *walletOpener) forget(walletID int) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	delete(o.wallets, walletID)
+	o.events.ClearState(eventbus.TopicWalletOpenState, walletID)
+}
+
+// openWalletsInBackground opens wallets with at most maxConcurrentWalletLoads
+// of them opening at the same time, so that a slow wallet does not hold back
+// the others. The wallets not opened yet are skipped once ctx is canceled.
+// wg is done once every wallet being opened is opened.
+func openWalletsInBackground(ctx context.Context, wg *sync.WaitGroup, wallets []sharedW.Asset, open func(sharedW.Asset) error) {
+	defer wg.Done()
+
+	workers := make(chan struct{}, maxConcurrentWalletLoads)
+	for _, wallet := range wallets {
+		select {
+		case <-ctx.Done():
+			// If shutdown protocol is detected, exit immediately.
+			return
+		case workers <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(wallet sharedW.Asset) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+			if err := open(wallet); err != nil {
+				log.Errorf("[%d] Error opening wallet: %v", wallet.GetWalletID(), err)
+			}
+		}(wallet)
+	}
+}
+
+// openWallet migrates the data of wallet if needed and opens it. The
+// progress of the migration is reported by the wallet's open status. If the
+// wallet is already being opened, openWallet waits for it to be done.
+func (mgr *AssetsManager) openWallet(wallet sharedW.Asset) error {
+	walletID := wallet.GetWalletID()
+	started, done := mgr.opener.begin(walletID)
+	if !started {
+		<-done
+		if status, ok := mgr.opener.status(walletID); ok {
+			return status.Err
+		}
+		return nil
+	}
+
+	var err error
+	if !wallet.WalletOpened() {
+		err = wallet.MigrateWalletData(func(progress *migration.Progress) {
+			mgr.opener.migrating(walletID, progress)
+		})
+		if err == nil {
+			err = wallet.OpenWallet()
+		}
+	}
+	mgr.opener.finish(walletID, err)
+	return err
+}
+
+// EnsureWalletOpened opens the wallet with walletID if it is not opened yet,
+// such as a wallet only opened when first accessed, and waits for a wallet
+// being opened in the background to be ready.
+func (mgr *AssetsManager) EnsureWalletOpened(walletID int) error {
+	wallet := mgr.WalletWithID(walletID)
+	if wallet == nil {
+		return errors.New(utils.ErrNotExist)
+	}
+	if wallet.WalletOpened() {
+		status, ok := mgr.opener.status(walletID)
+		switch {
+		case ok && status.State == WalletOpenOpening:
+			// Wait for the wallet data migration or the open to finish.
+			return mgr.openWallet(wallet)
+		case ok && status.State != WalletOpenOpened:
+			mgr.opener.setState(walletID, WalletOpenOpened, nil)
+		}
+		return nil
+	}
+	return mgr.openWallet(wallet)
+}
+
+// WalletOpenStatus returns the startup status of the wallet with walletID.
+func (mgr *AssetsManager) WalletOpenStatus(walletID int) *WalletOpenStatus {
+	if status, ok := mgr.opener.status(walletID); ok {
+		return &status
+	}
+
+	status := &WalletOpenStatus{WalletID: walletID, State: WalletOpenDeferred}
+	if wallet := mgr.WalletWithID(walletID); wallet != nil && wallet.WalletOpened() {
+		status.State = WalletOpenOpened
+	}
+	return status
+}
+
+// SetOpenOnDemand sets whether the wallet with walletID is opened on startup
+// or only when first accessed.
+func (mgr *AssetsManager) SetOpenOnDemand(walletID int, onDemand bool) error {
+	wallet := mgr.WalletWithID(walletID)
+	if wallet == nil {
+		return errors.New(utils.ErrNotExist)
+	}
+	wallet.SetBoolConfigValueForKey(sharedW.OpenOnDemandConfigKey, onDemand)
+	return nil
+}
+
+// IsOpenOnDemand returns true if the wallet with walletID is only opened when
+// first accessed.
+func (mgr *AssetsManager) IsOpenOnDemand(walletID int) bool {
+	wallet := mgr.WalletWithID(walletID)
+	if wallet == nil {
+		return false
+	}
+	return wallet.ReadBoolConfigValueForKey(sharedW.OpenOnDemandConfigKey, false)
+}
+
+// AddWalletOpenListener registers a listener notified when the startup state
+// of a wallet changes. The current state of the wallets opened in the
+// background is passed to the new listener first.
+func (mgr *AssetsManager) AddWalletOpenListener(listener *WalletOpenListener, uniqueIdentifier string) error {
+	filter := eventbus.Filter{Topics: []eventbus.Topic{eventbus.TopicWalletOpenState}}
+	return mgr.events.Subscribe("walletopen/"+uniqueIdentifier, filter, eventbus.Handle(func(_ eventbus.Event, status *WalletOpenStatus) {
+		if listener.OnWalletOpenStateChanged != nil {
+			listener.OnWalletOpenStateChanged(status)
+		}
+	}))
+}
+
+// RemoveWalletOpenListener removes a previously registered wallet open
+// listener.
+func (mgr *AssetsManager) RemoveWalletOpenListener(uniqueIdentifier string) {
+	mgr.events.Unsubscribe("walletopen/" + uniqueIdentifier)
+}
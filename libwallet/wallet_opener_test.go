@@ -0,0 +1,202 @@
+package libwallet
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/libwallet/eventbus"
+	"github.com/crypto-power/cryptopower/libwallet/migration"
+)
+
+// openerAsset is the wallet stand-in of the wallet opener tests. Its data
+// migration reports progress and blocks until release is closed.
+type openerAsset struct {
+	sharedW.Asset
+
+	walletID  int
+	migrating chan struct{}
+	release   chan struct{}
+	openErr   error
+	opens     int32
+	opened    int32
+}
+
+func newOpenerAsset(walletID int, openErr error) *openerAsset {
+	return &openerAsset{
+		walletID:  walletID,
+		migrating: make(chan struct{}),
+		release:   make(chan struct{}),
+		openErr:   openErr,
+	}
+}
+
+func (asset *openerAsset) GetWalletID() int { return asset.walletID }
+
+func (asset *openerAsset) WalletOpened() bool { return atomic.LoadInt32(&asset.opened) == 1 }
+
+func (asset *openerAsset) MigrateWalletData(listener migration.ProgressListener) error {
+	listener(&migration.Progress{DBName: "walletdata", Step: 1, Total: 1})
+	close(asset.migrating)
+	<-asset.release
+	return nil
+}
+
+func (asset *openerAsset) OpenWallet() error {
+	atomic.AddInt32(&asset.opens, 1)
+	if asset.openErr != nil {
+		return asset.openErr
+	}
+	atomic.StoreInt32(&asset.opened, 1)
+	return nil
+}
+
+func waitClosed(t *testing.T, ch chan struct{}) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(waitTimeout):
+		t.Fatal("timed out waiting for the wallet")
+	}
+}
+
+func TestOpenWalletsInBackground(t *testing.T) {
+	const walletsCount = maxConcurrentWalletLoads + 3
+
+	var running, maxRunning int32
+	var opened sync.Map
+	release := make(chan struct{})
+	started := make(chan int, walletsCount)
+	open := func(wallet sharedW.Asset) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+		started <- wallet.GetWalletID()
+		<-release
+		opened.Store(wallet.GetWalletID(), true)
+		atomic.AddInt32(&running, -1)
+		return nil
+	}
+
+	wallets := make([]sharedW.Asset, 0, walletsCount)
+	for walletID := 1; walletID <= walletsCount; walletID++ {
+		wallets = append(wallets, &stubAsset{walletID: walletID})
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go openWalletsInBackground(context.Background(), &wg, wallets, open)
+
+	receiveStarted(t, started, maxConcurrentWalletLoads)
+	select {
+	case walletID := <-started:
+		t.Fatalf("wallet (%d) started opening while every worker is busy", walletID)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	wg.Wait()
+
+	for walletID := 1; walletID <= walletsCount; walletID++ {
+		if _, ok := opened.Load(walletID); !ok {
+			t.Errorf("wallet (%d) was not opened", walletID)
+		}
+	}
+	if maxRunning != maxConcurrentWalletLoads {
+		t.Errorf("expected (%d) wallets opening at most, got (%d)", maxConcurrentWalletLoads, maxRunning)
+	}
+}
+
+func TestOpenWalletsInBackgroundCanceled(t *testing.T) {
+	const walletsCount = maxConcurrentWalletLoads + 3
+
+	var opened int32
+	release := make(chan struct{})
+	started := make(chan int, walletsCount)
+	open := func(wallet sharedW.Asset) error {
+		started <- wallet.GetWalletID()
+		<-release
+		atomic.AddInt32(&opened, 1)
+		return nil
+	}
+
+	wallets := make([]sharedW.Asset, 0, walletsCount)
+	for walletID := 1; walletID <= walletsCount; walletID++ {
+		wallets = append(wallets, &stubAsset{walletID: walletID})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go openWalletsInBackground(ctx, &wg, wallets, open)
+
+	receiveStarted(t, started, maxConcurrentWalletLoads)
+	cancel()
+	close(release)
+
+	// The wallets being opened are waited for, the others are skipped.
+	wg.Wait()
+	if opened != maxConcurrentWalletLoads {
+		t.Errorf("expected (%d) wallets opened, got (%d)", maxConcurrentWalletLoads, opened)
+	}
+}
+
+func TestOpenWallet(t *testing.T) {
+	errOpen := errors.New("open failed")
+	tests := []struct {
+		name     string
+		openErr  error
+		expected WalletOpenState
+	}{
+		{name: "opened", expected: WalletOpenOpened},
+		{name: "failed", openErr: errOpen, expected: WalletOpenFailed},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mgr := &AssetsManager{opener: newWalletOpener(eventbus.New())}
+			asset := newOpenerAsset(1, tc.openErr)
+
+			// The wallet is opened on demand by two callers at once.
+			errs := make(chan error, 2)
+			go func() { errs <- mgr.openWallet(asset) }()
+			waitClosed(t, asset.migrating)
+			go func() { errs <- mgr.openWallet(asset) }()
+
+			status, _ := mgr.opener.status(asset.walletID)
+			if status.State != WalletOpenOpening || status.Migration == nil {
+				t.Fatalf("expected the migration progress while opening, got (%+v)", status)
+			}
+
+			// Let the second caller wait for the wallet being opened.
+			time.Sleep(50 * time.Millisecond)
+			close(asset.release)
+			for i := 0; i < 2; i++ {
+				select {
+				case err := <-errs:
+					if !errors.Is(err, tc.openErr) {
+						t.Errorf("(%v), expected (%v), got (%v)", tc.name, tc.openErr, err)
+					}
+				case <-time.After(waitTimeout):
+					t.Fatal("timed out waiting for the wallet to open")
+				}
+			}
+
+			if opens := atomic.LoadInt32(&asset.opens); opens != 1 {
+				t.Errorf("(%v), expected the wallet opened once, got (%d)", tc.name, opens)
+			}
+			status, _ = mgr.opener.status(asset.walletID)
+			if status.State != tc.expected || status.Migration != nil || !errors.Is(status.Err, tc.openErr) {
+				t.Errorf("(%v), expected (%v), got (%+v)", tc.name, tc.expected, status)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package cryptomaterial
+
+import (
+	"image/color"
+	"math"
+
+	"gioui.org/layout"
+	"gioui.org/text"
+	"gioui.org/unit"
+
+	"github.com/crypto-power/cryptopower/ui/values"
+)
+
+type BarChartItem struct {
+	Label string
+	Value float64
+}
+
+// BarChart draws a vertical bar for each item, scaled against the item with
+// the largest absolute value. Negative values are drawn with NegativeColor.
+type BarChart struct {
+	t *Theme
+
+	Items         []BarChartItem
+	Height        unit.Dp
+	Color         color.NRGBA
+	NegativeColor color.NRGBA
+	// FormatValue formats the value shown above each bar. No value is shown
+	// if it is nil.
+	FormatValue func(float64) string
+}
+
+func (t *Theme) BarChart(items []BarChartItem) *BarChart {
+	return &BarChart{
+		t: t,
+
+		Items:         items,
+		Height:        values.MarginPadding120,
+		Color:         t.Color.Turquoise300,
+		NegativeColor: t.Color.Danger,
+	}
+}
+
+func (bc *BarChart) Layout(gtx C) D {
+	if len(bc.Items) == 0 {
+		return D{}
+	}
+
+	var maxValue float64
+	for _, item := range bc.Items {
+		maxValue = math.Max(maxValue, math.Abs(item.Value))
+	}
+
+	bars := make([]layout.FlexChild, 0, len(bc.Items))
+	for _, item := range bc.Items {
+		item := item
+		bars = append(bars, layout.Flexed(1/float32(len(bc.Items)), func(gtx C) D {
+			return layout.Inset{Left: values.MarginPadding2, Right: values.MarginPadding2}.Layout(gtx, func(gtx C) D {
+				return bc.layoutBar(gtx, item, maxValue)
+			})
+		}))
+	}
+	return layout.Flex{Axis: layout.Horizontal, Alignment: layout.End}.Layout(gtx, bars...)
+}
+
+func (bc *BarChart) layoutBar(gtx C, item BarChartItem, maxValue float64) D {
+	gtx.Constraints.Min.X = gtx.Constraints.Max.X
+	return layout.Flex{Axis: layout.Vertical, Alignment: layout.Middle}.Layout(gtx,
+		layout.Rigid(func(gtx C) D {
+			if bc.FormatValue == nil {
+				return D{}
+			}
+			lbl := bc.t.Label(values.TextSize10, bc.FormatValue(item.Value))
+			lbl.Alignment = text.Middle
+			lbl.MaxLines = 1
+			return lbl.Layout(gtx)
+		}),
+		layout.Rigid(func(gtx C) D {
+			// The bar is bottom aligned in an area of the chart's height.
+			height := gtx.Dp(bc.Height)
+			barHeight := 0
+			if maxValue > 0 {
+				barHeight = int(float64(height) * math.Abs(item.Value) / maxValue)
+			}
+			barColor := bc.Color
+			if item.Value < 0 {
+				barColor = bc.NegativeColor
+			}
+
+			return LinearLayout{
+				Width:     MatchParent,
+				Height:    height,
+				Direction: layout.S,
+			}.Layout2(gtx, func(gtx C) D {
+				return LinearLayout{
+					Width:      MatchParent,
+					Height:     barHeight,
+					Background: barColor,
+					Border:     Border{Radius: Radius(2)},
+				}.Layout(gtx)
+			})
+		}),
+		layout.Rigid(func(gtx C) D {
+			lbl := bc.t.Label(values.TextSize10, item.Label)
+			lbl.Color = bc.t.Color.GrayText2
+			lbl.Alignment = text.Middle
+			lbl.MaxLines = 1
+			return lbl.Layout(gtx)
+		}),
+	)
+}
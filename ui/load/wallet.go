@@ -6,9 +6,7 @@ import (
 	"sort"
 
 	"github.com/crypto-power/cryptopower/libwallet"
-	"github.com/crypto-power/cryptopower/libwallet/assets/btc"
 	"github.com/crypto-power/cryptopower/libwallet/assets/dcr"
-	"github.com/crypto-power/cryptopower/libwallet/assets/ltc"
 	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
 	"github.com/crypto-power/cryptopower/libwallet/utils"
 )
@@ -64,6 +62,9 @@ func (wl *WalletLoad) TotalWalletsBalance() (sharedW.AssetAmount, error) {
 	}
 
 	for _, w := range wallets {
+		if !w.WalletOpened() {
+			continue
+		}
 		accountsResult, err := w.GetAccountsRaw()
 		if err != nil {
 			return wl.nilAmount(), err
@@ -132,29 +133,6 @@ func (wl *WalletLoad) DCRHDPrefix() string {
 	}
 }
 
-func (wl *WalletLoad) BTCHDPrefix() string {
-	switch wl.AssetsManager.NetType() {
-	case utils.Testnet:
-		return btc.TestnetHDPath
-	case utils.Mainnet:
-		return btc.MainnetHDPath
-	default:
-		return ""
-	}
-}
-
-// LTC HDPrefix returns the HD path prefix for the Litecoin wallet network.
-func (wl *WalletLoad) LTCHDPrefix() string {
-	switch wl.AssetsManager.NetType() {
-	case utils.Testnet:
-		return ltc.TestnetHDPath
-	case utils.Mainnet:
-		return ltc.MainnetHDPath
-	default:
-		return ""
-	}
-}
-
 func (wl *WalletLoad) WalletDirectory() string {
 	return wl.SelectedWallet.Wallet.DataDir()
 }
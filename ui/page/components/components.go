@@ -809,7 +809,8 @@ func calculateTotalAssetsBalance(l *load.Load) (map[libutils.AssetType]int64, er
 	assetsTotalBalance := make(map[libutils.AssetType]int64)
 
 	for _, wal := range wallets {
-		if wal.IsWatchingOnlyWallet() {
+		// Wallets still opening in the background have no balance yet.
+		if wal.IsWatchingOnlyWallet() || !wal.WalletOpened() {
 			continue
 		}
 
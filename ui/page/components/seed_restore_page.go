@@ -26,6 +26,10 @@ const (
 	numberOfSeeds     = 32
 )
 
+// seedWordCounts are the number of words of the supported seeds. BTC and LTC
+// wallets can also be restored from BIP39 seeds.
+var seedWordCounts = []int{numberOfSeeds + 1, 24, 18, 12}
+
 type seedEditors struct {
 	focusIndex int
 	editors    []cryptomaterial.RestoreEditor
@@ -75,6 +79,13 @@ type SeedRestore struct {
 	selectedSeedEditor       int // stores the current focus index of seed editors
 
 	walletType libutils.AssetType
+
+	wordCount            int
+	wordCounts           []int
+	restoreShares        bool
+	wordCountSelector    *cryptomaterial.SegmentedControl
+	seedPassphraseEditor cryptomaterial.Editor
+	seedSharesEditor     cryptomaterial.Editor
 }
 
 func NewSeedRestorePage(l *load.Load, walletName string, walletType libutils.AssetType, onRestoreComplete func()) *SeedRestore {
@@ -86,7 +97,28 @@ func NewSeedRestorePage(l *load.Load, walletName string, walletType libutils.Ass
 		openPopupIndex:  -1,
 		walletName:      walletName,
 		walletType:      walletType,
+		wordCount:       numberOfSeeds + 1,
+	}
+
+	pg.wordCounts = seedWordCounts[:1]
+	if walletType == libutils.BTCWalletAsset || walletType == libutils.LTCWalletAsset {
+		pg.wordCounts = seedWordCounts
+	}
+
+	// Wallets of all the assets can be restored from SLIP-39 seed shares,
+	// the last option of the selector.
+	titles := make([]string, 0, len(pg.wordCounts)+1)
+	for _, count := range pg.wordCounts {
+		titles = append(titles, values.StringF(values.StrSeedWordCount, count))
 	}
+	titles = append(titles, values.String(values.StrShamirShares))
+	pg.wordCountSelector = l.Theme.SegmentedControl(titles)
+
+	pg.seedPassphraseEditor = l.Theme.EditorPassword(new(widget.Editor), values.String(values.StrSeedPassphrase))
+	pg.seedPassphraseEditor.Editor.SingleLine = true
+
+	pg.seedSharesEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrSeedSharesHint))
+	pg.seedSharesEditor.Editor.SingleLine = false
 
 	pg.optionsMenuCard = cryptomaterial.Card{Color: pg.Theme.Color.Surface}
 	pg.optionsMenuCard.Radius = cryptomaterial.Radius(8)
@@ -133,6 +165,36 @@ func (pg *SeedRestore) SetParentNav(window app.WindowNavigator) {
 	pg.window = window
 }
 
+// activeEditors returns the editors of the words of the selected seed length.
+func (pg *SeedRestore) activeEditors() []cryptomaterial.RestoreEditor {
+	return pg.seedEditors.editors[:pg.wordCount]
+}
+
+// lastSeedIndex returns the index of the last word of the selected seed length.
+func (pg *SeedRestore) lastSeedIndex() int {
+	return pg.wordCount - 1
+}
+
+// isBIP39 returns true if the selected seed length is one of a BIP39 seed.
+func (pg *SeedRestore) isBIP39() bool {
+	return !pg.restoreShares && pg.wordCount != numberOfSeeds+1
+}
+
+// acceptsSeedPassphrase returns true if the selected seed type may have a
+// passphrase.
+func (pg *SeedRestore) acceptsSeedPassphrase() bool {
+	return pg.restoreShares || pg.isBIP39()
+}
+
+// seedPassphrase returns the entered seed passphrase if the selected seed type
+// accepts one.
+func (pg *SeedRestore) seedPassphrase() string {
+	if !pg.acceptsSeedPassphrase() {
+		return ""
+	}
+	return pg.seedPassphraseEditor.Editor.Text()
+}
+
 func (pg *SeedRestore) setEditorFocus() {
 	pg.seedEditors.focusIndex = -1
 	pg.seedEditors.editors[0].Edit.Editor.Focus()
@@ -169,7 +231,14 @@ func (pg *SeedRestore) restore(gtx C) D {
 				Border:      cryptomaterial.Border{Radius: cryptomaterial.Radius(14)},
 				Padding:     layout.UniformInset(values.MarginPadding15),
 			}.Layout(gtx,
-				layout.Rigid(pg.seedEditorViewDesktop),
+				layout.Rigid(pg.wordCountLayout),
+				layout.Rigid(func(gtx C) D {
+					if pg.restoreShares {
+						return pg.seedSharesEditor.Layout(gtx)
+					}
+					return pg.seedEditorViewDesktop(gtx)
+				}),
+				layout.Rigid(pg.seedPassphraseLayout),
 				layout.Rigid(pg.resetSeedFields.Layout),
 			)
 		}),
@@ -200,11 +269,16 @@ func (pg *SeedRestore) restoreMobile(gtx C) D {
 								Bottom: values.MarginPadding10,
 							}.Layout(gtx, pg.Theme.Body1(values.String(values.StrEnterSeedPhrase)).Layout)
 						}),
+						layout.Rigid(pg.wordCountLayout),
 						layout.Rigid(func(gtx C) D {
 							return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
 								layout.Flexed(1, func(gtx C) D {
+									if pg.restoreShares {
+										return pg.seedSharesEditor.Layout(gtx)
+									}
 									return pg.seedEditorViewMobile(gtx)
 								}),
+								layout.Rigid(pg.seedPassphraseLayout),
 								layout.Rigid(func(gtx C) D {
 									return pg.resetSeedFields.Layout(gtx)
 								}),
@@ -230,6 +304,23 @@ func (pg *SeedRestore) restoreButtonSection(gtx C) D {
 	})
 }
 
+func (pg *SeedRestore) wordCountLayout(gtx C) D {
+	return layout.Inset{Bottom: values.MarginPadding10}.Layout(gtx, pg.wordCountSelector.Layout)
+}
+
+func (pg *SeedRestore) seedPassphraseLayout(gtx C) D {
+	if !pg.acceptsSeedPassphrase() {
+		return D{}
+	}
+	return layout.Inset{Top: values.MarginPadding5, Bottom: values.MarginPadding10}.Layout(gtx, pg.seedPassphraseEditor.Layout)
+}
+
+// columnLength returns the number of seed editors displayed in the provided
+// column of the five desktop columns.
+func (pg *SeedRestore) columnLength(column int) int {
+	return (pg.wordCount - column + 4) / 5
+}
+
 func (pg *SeedRestore) seedEditorViewDesktop(gtx C) D {
 	inset := layout.Inset{
 		Right: values.MarginPadding5,
@@ -237,26 +328,26 @@ func (pg *SeedRestore) seedEditorViewDesktop(gtx C) D {
 	return layout.Flex{}.Layout(gtx,
 		layout.Flexed(1, func(gtx C) D {
 			return inset.Layout(gtx, func(gtx C) D {
-				return pg.inputsGroup(gtx, pg.seedList, 7, 0)
+				return pg.inputsGroup(gtx, pg.seedList, pg.columnLength(0), 0)
 			})
 		}),
 		layout.Flexed(1, func(gtx C) D {
 			return inset.Layout(gtx, func(gtx C) D {
-				return pg.inputsGroup(gtx, pg.seedList, 7, 1)
+				return pg.inputsGroup(gtx, pg.seedList, pg.columnLength(1), 1)
 			})
 		}),
 		layout.Flexed(1, func(gtx C) D {
 			return inset.Layout(gtx, func(gtx C) D {
-				return pg.inputsGroup(gtx, pg.seedList, 7, 2)
+				return pg.inputsGroup(gtx, pg.seedList, pg.columnLength(2), 2)
 			})
 		}),
 		layout.Flexed(1, func(gtx C) D {
 			return inset.Layout(gtx, func(gtx C) D {
-				return pg.inputsGroup(gtx, pg.seedList, 6, 3)
+				return pg.inputsGroup(gtx, pg.seedList, pg.columnLength(3), 3)
 			})
 		}),
 		layout.Flexed(1, func(gtx C) D {
-			return pg.inputsGroup(gtx, pg.seedList, 6, 4)
+			return pg.inputsGroup(gtx, pg.seedList, pg.columnLength(4), 4)
 		}),
 	)
 }
@@ -268,7 +359,7 @@ func (pg *SeedRestore) seedEditorViewMobile(gtx layout.Context) layout.Dimension
 	return layout.Flex{}.Layout(gtx,
 		layout.Flexed(1, func(gtx C) D {
 			return inset.Layout(gtx, func(gtx C) D {
-				return pg.inputsGroupMobile(gtx, pg.seedList, 33, 0)
+				return pg.inputsGroupMobile(gtx, pg.seedList, pg.wordCount, 0)
 			})
 		}),
 	)
@@ -316,11 +407,11 @@ func (pg *SeedRestore) onSuggestionSeedsClicked() {
 				pg.seedEditors.editors[index].Edit.Editor.SetText(b.text)
 				pg.seedEditors.editors[index].Edit.Editor.MoveCaret(len(b.text), 0)
 				pg.seedClicked = true
-				if index != numberOfSeeds {
+				if index != pg.lastSeedIndex() {
 					pg.seedEditors.editors[index+1].Edit.Editor.Focus()
 				}
 
-				if index == numberOfSeeds {
+				if index == pg.lastSeedIndex() {
 					pg.isLastEditor = true
 				}
 			}
@@ -351,12 +442,12 @@ func (pg *SeedRestore) editorSeedsEventsHandler() {
 			pg.openPopupIndex = i
 		}
 
-		if i != numberOfSeeds {
+		if i != pg.lastSeedIndex() {
 			pg.isLastEditor = false
 		}
 	}
 
-	for i := 0; i < len(pg.seedEditors.editors); i++ {
+	for i := 0; i < pg.wordCount; i++ {
 		editor := &pg.seedEditors.editors[i]
 		text := editor.Edit.Editor.Text()
 
@@ -375,12 +466,12 @@ func (pg *SeedRestore) editorSeedsEventsHandler() {
 				}
 
 				//  Handles Enter and Return keyboard events.
-				if i != numberOfSeeds {
+				if i != pg.lastSeedIndex() {
 					pg.seedEditors.editors[i+1].Edit.Editor.Focus()
 					pg.selected = 0
 				}
 
-				if i == numberOfSeeds {
+				if i == pg.lastSeedIndex() {
 					pg.selected = 0
 					pg.isLastEditor = true
 				}
@@ -458,6 +549,9 @@ func (pg SeedRestore) suggestionSeeds(text string) []string {
 }
 
 func (pg *SeedRestore) updateSeedResetBtn() bool {
+	if pg.restoreShares {
+		return pg.seedSharesEditor.Editor.Text() != ""
+	}
 	for _, editor := range pg.seedEditors.editors {
 		return editor.Edit.Editor.Text() != ""
 	}
@@ -465,10 +559,24 @@ func (pg *SeedRestore) updateSeedResetBtn() bool {
 }
 
 func (pg *SeedRestore) validateSeeds() (bool, string) {
+	if pg.restoreShares {
+		var shares []string
+		for _, line := range strings.Split(pg.seedSharesEditor.Editor.Text(), "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if !sharedW.IsSLIP39Share(line) {
+				return false, ""
+			}
+			shares = append(shares, line)
+		}
+		return len(shares) > 0, sharedW.JoinSeedShares(shares)
+	}
+
 	seedPhrase := ""
 	allSuggesString := strings.Join(pg.allSuggestions, " ")
 
-	for i, editor := range pg.seedEditors.editors {
+	for i, editor := range pg.activeEditors() {
 		if editor.Edit.Editor.Text() == "" || !strings.Contains(allSuggesString, editor.Edit.Editor.Text()) {
 			pg.seedEditors.editors[i].Edit.HintColor = pg.Theme.Color.Danger
 			return false, ""
@@ -494,7 +602,7 @@ func (pg *SeedRestore) verifySeeds() bool {
 
 	// Compare seed with existing wallets seed. On positive match abort import
 	// to prevent duplicate wallet. walletWithSameSeed >= 0 if there is a match.
-	walletWithSameSeed, err := pg.WL.AssetsManager.WalletWithSeed(pg.walletType, pg.seedPhrase)
+	walletWithSameSeed, err := pg.WL.AssetsManager.WalletWithSeed(pg.walletType, pg.seedPhrase, pg.seedPassphrase())
 	if err != nil {
 		log.Error(err)
 		return false
@@ -513,6 +621,8 @@ func (pg *SeedRestore) resetSeeds() {
 	for i := 0; i < len(pg.seedEditors.editors); i++ {
 		pg.seedEditors.editors[i].Edit.Editor.SetText("")
 	}
+	pg.seedSharesEditor.Editor.SetText("")
+	pg.seedPassphraseEditor.Editor.SetText("")
 }
 
 // switchSeedEditors sets focus on the next seed phrase after moving the
@@ -566,7 +676,7 @@ func (pg *SeedRestore) HandleUserInteractions() {
 			ShowWalletInfoTip(true).
 			SetParent(pg).
 			SetPositiveButtonCallback(func(walletName, password string, m *modal.CreatePasswordModal) bool {
-				_, err := pg.WL.AssetsManager.RestoreWallet(pg.walletType, pg.walletName, pg.seedPhrase, password, sharedW.PassphraseTypePass)
+				_, err := pg.WL.AssetsManager.RestoreWallet(pg.walletType, pg.walletName, pg.seedPhrase, pg.seedPassphrase(), password, sharedW.PassphraseTypePass)
 				if err != nil {
 					errString := err.Error()
 					if err.Error() == libutils.ErrExist {
@@ -592,6 +702,22 @@ func (pg *SeedRestore) HandleUserInteractions() {
 		pg.window.ShowModal(walletPasswordModal)
 	}
 
+	if pg.wordCountSelector.Changed() {
+		selected := pg.wordCountSelector.SelectedIndex()
+		pg.restoreShares = selected == len(pg.wordCounts)
+		if !pg.restoreShares {
+			pg.wordCount = pg.wordCounts[selected]
+		}
+		pg.allSuggestions = dcr.PGPWordList()
+		if pg.isBIP39() {
+			pg.allSuggestions = sharedW.BIP39WordList()
+		}
+		pg.resetSeeds()
+		pg.openPopupIndex = -1
+		pg.isLastEditor = false
+		pg.setEditorFocus()
+	}
+
 	for pg.resetSeedFields.Clicked() {
 		pg.resetSeeds()
 		pg.seedEditors.focusIndex = -1
@@ -641,7 +767,7 @@ func (pg *SeedRestore) HandleKeyPress(evt *key.Event) {
 		if len(pg.suggestions) > 0 {
 			pg.seedClicked = true
 		}
-		switchSeedEditors(pg.seedEditors.editors, 1)
+		switchSeedEditors(pg.activeEditors(), 1)
 	}
 
 	// If seed suggestion list is opened and tab key is pressed select
@@ -654,7 +780,7 @@ func (pg *SeedRestore) HandleKeyPress(evt *key.Event) {
 	}
 
 	if evt.Name == key.NameTab && evt.Modifiers == key.ModShift && evt.State == key.Press && pg.openPopupIndex == -1 {
-		switchSeedEditors(pg.seedEditors.editors, -1)
+		switchSeedEditors(pg.activeEditors(), -1)
 	}
 
 	if evt.Name == key.NameDownArrow && evt.State == key.Press {
@@ -668,7 +794,7 @@ func (pg *SeedRestore) HandleKeyPress(evt *key.Event) {
 		if len(pg.suggestions) > 0 {
 			pg.seedClicked = true
 		}
-		switchSeedEditors(pg.seedEditors.editors, 5)
+		switchSeedEditors(pg.activeEditors(), 5)
 	}
 
 	if evt.Name == key.NameUpArrow && evt.State == key.Press {
@@ -679,21 +805,21 @@ func (pg *SeedRestore) HandleKeyPress(evt *key.Event) {
 			}
 			return
 		}
-		switchSeedEditors(pg.seedEditors.editors, -5)
+		switchSeedEditors(pg.activeEditors(), -5)
 	}
 
 	if evt.Name == key.NameLeftArrow && evt.State == key.Press && pg.openPopupIndex == -1 {
 		if len(pg.suggestions) > 0 {
 			pg.seedClicked = true
 		}
-		switchSeedEditors(pg.seedEditors.editors, -1)
+		switchSeedEditors(pg.activeEditors(), -1)
 	}
 
 	if evt.Name == key.NameRightArrow && evt.State == key.Press && pg.openPopupIndex == -1 {
 		if len(pg.suggestions) > 0 {
 			pg.seedClicked = true
 		}
-		switchSeedEditors(pg.seedEditors.editors, 1)
+		switchSeedEditors(pg.activeEditors(), 1)
 	}
 
 	if (evt.Name == key.NameReturn || evt.Name == key.NameEnter) && pg.openPopupIndex != -1 && evt.State == key.Press && len(pg.suggestions) != 0 {
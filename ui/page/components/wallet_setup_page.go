@@ -51,6 +51,7 @@ type CreateWallet struct {
 	passwordEditor        cryptomaterial.Editor
 	confirmPasswordEditor cryptomaterial.Editor
 	watchOnlyCheckBox     cryptomaterial.CheckBoxStyle
+	multisigCheckBox      cryptomaterial.CheckBoxStyle
 	materialLoader        material.LoaderStyle
 
 	continueBtn cryptomaterial.Button
@@ -82,6 +83,7 @@ func NewCreateWallet(l *load.Load, walletCreationSuccessCallback func(), assetTy
 		restoreBtn:           l.Theme.Button(values.String(values.StrRestore)),
 		importBtn:            l.Theme.Button(values.String(values.StrImport)),
 		watchOnlyCheckBox:    l.Theme.CheckBox(new(widget.Bool), values.String(values.StrImportWatchingOnlyWallet)),
+		multisigCheckBox:     l.Theme.CheckBox(new(widget.Bool), values.String(values.StrMultisigWallet)),
 		selectedWalletAction: -1,
 		assetTypeError:       l.Theme.Body1(""),
 
@@ -334,6 +336,13 @@ func (pg *CreateWallet) walletOptions(gtx C) D {
 
 func (pg *CreateWallet) createNewWallet(gtx C) D {
 	return layout.Flex{Axis: layout.Vertical, Spacing: layout.SpaceBetween}.Layout(gtx,
+		layout.Rigid(func(gtx C) D {
+			// Multisig wallets are only supported for BTC and LTC.
+			if ast := pg.assetTypeSelector.SelectedAssetType(); ast == nil || *ast == libutils.DCRWalletAsset {
+				return D{}
+			}
+			return pg.multisigCheckBox.Layout(gtx)
+		}),
 		layout.Rigid(func(gtx C) D {
 			return layout.Inset{
 				Top:    values.MarginPadding14,
@@ -384,12 +393,16 @@ func (pg *CreateWallet) restoreWallet(gtx C) D {
 						if !pg.watchOnlyCheckBox.CheckBox.Value {
 							return D{}
 						}
+						label := values.String(values.StrExtendedPubKeyOrDescriptor)
+						if ast := pg.assetTypeSelector.SelectedAssetType(); ast != nil && *ast == libutils.DCRWalletAsset {
+							label = values.String(values.StrExtendedPubKey)
+						}
 						return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
 							layout.Rigid(func(gtx C) D {
 								return layout.Inset{
 									Top:    values.MarginPadding10,
 									Bottom: values.MarginPadding8,
-								}.Layout(gtx, pg.Theme.Label(values.TextSize16, values.String(values.StrExtendedPubKey)).Layout)
+								}.Layout(gtx, pg.Theme.Label(values.TextSize16, label).Layout)
 							}),
 							layout.Rigid(pg.watchOnlyWalletHex.Layout),
 						)
@@ -477,7 +490,11 @@ func (pg *CreateWallet) HandleUserInteractions() {
 				wal.SetBoolConfigValueForKey(sharedW.AccountMixerConfigSet, true)
 
 			case libutils.BTCWalletAsset:
-				_, err := pg.WL.AssetsManager.CreateNewBTCWallet(pg.walletName.Editor.Text(), pg.passwordEditor.Editor.Text(), sharedW.PassphraseTypePass)
+				createWallet := pg.WL.AssetsManager.CreateNewBTCWallet
+				if pg.multisigCheckBox.CheckBox.Value {
+					createWallet = pg.WL.AssetsManager.CreateNewBTCMultisigWallet
+				}
+				_, err := createWallet(pg.walletName.Editor.Text(), pg.passwordEditor.Editor.Text(), sharedW.PassphraseTypePass)
 				if err != nil {
 					if err.Error() == libutils.ErrExist {
 						pg.walletName.SetError(values.StringF(values.StrWalletExist, pg.walletName.Editor.Text()))
@@ -490,7 +507,11 @@ func (pg *CreateWallet) HandleUserInteractions() {
 				}
 
 			case libutils.LTCWalletAsset:
-				_, err := pg.WL.AssetsManager.CreateNewLTCWallet(pg.walletName.Editor.Text(), pg.passwordEditor.Editor.Text(), sharedW.PassphraseTypePass)
+				createWallet := pg.WL.AssetsManager.CreateNewLTCWallet
+				if pg.multisigCheckBox.CheckBox.Value {
+					createWallet = pg.WL.AssetsManager.CreateNewLTCMultisigWallet
+				}
+				_, err := createWallet(pg.walletName.Editor.Text(), pg.passwordEditor.Editor.Text(), sharedW.PassphraseTypePass)
 				if err != nil {
 					if err.Error() == libutils.ErrExist {
 						pg.walletName.SetError(values.StringF(values.StrWalletExist, pg.walletName.Editor.Text()))
@@ -532,6 +553,10 @@ func (pg *CreateWallet) HandleUserInteractions() {
 					err = errors.New(values.String(values.StrXpubWalletExist))
 				}
 			case libutils.BTCWalletAsset:
+				if sharedW.LooksLikeDescriptor(pg.watchOnlyWalletHex.Editor.Text()) {
+					_, err = pg.WL.AssetsManager.CreateNewBTCWatchOnlyWalletFromDescriptor(pg.walletName.Editor.Text(), pg.watchOnlyWalletHex.Editor.Text())
+					break
+				}
 				var walletWithXPub int
 				walletWithXPub, err = pg.WL.AssetsManager.BTCWalletWithXPub(pg.watchOnlyWalletHex.Editor.Text())
 				if walletWithXPub == -1 {
@@ -540,6 +565,10 @@ func (pg *CreateWallet) HandleUserInteractions() {
 					err = errors.New(values.String(values.StrXpubWalletExist))
 				}
 			case libutils.LTCWalletAsset:
+				if sharedW.LooksLikeDescriptor(pg.watchOnlyWalletHex.Editor.Text()) {
+					_, err = pg.WL.AssetsManager.CreateNewLTCWatchOnlyWalletFromDescriptor(pg.walletName.Editor.Text(), pg.watchOnlyWalletHex.Editor.Text())
+					break
+				}
 				var walletWithXPub int
 				walletWithXPub, err = pg.WL.AssetsManager.LTCWalletWithXPub(pg.watchOnlyWalletHex.Editor.Text())
 				if walletWithXPub == -1 {
@@ -3,6 +3,7 @@ package info
 import (
 	"image/color"
 	"strings"
+	"sync"
 
 	"gioui.org/font"
 	"gioui.org/layout"
@@ -44,6 +45,9 @@ type WalletInfo struct {
 
 	rescanUpdate *sharedW.HeadersRescanProgressReport
 
+	syncStatusMtx sync.Mutex
+	syncStatus    libwallet.WalletSyncStatus
+
 	container *widget.List
 
 	transactions       []*sharedW.Transaction
@@ -67,19 +71,6 @@ type WalletInfo struct {
 	isStatusConnected bool
 }
 
-type progressInfo struct {
-	remainingSyncTime    string
-	headersToFetchOrScan int32
-	stepFetchProgress    int32
-	syncProgress         int
-}
-
-// SyncProgressInfo is made independent of the walletInfo struct so that once
-// set with a value, it always persists till unset. This will help address the
-// progress bar issue where, changing UI pages alters the progress on the sync
-// status progress percentage.
-var syncProgressInfo = map[sharedW.Asset]progressInfo{}
-
 func NewInfoPage(l *load.Load) *WalletInfo {
 	pg := &WalletInfo{
 		Load:             l,
@@ -372,56 +363,21 @@ func (pg *WalletInfo) HandleUserInteractions() {
 // every set interval. Other sync updates that affect the UI but occur outside
 // of an active sync requires a display refresh.
 func (pg *WalletInfo) listenForNotifications() {
-	updateSyncProgress := func(progress progressInfo) {
-		// Update sync progress fields which will be displayed
-		// when the next UI invalidation occurs.
-
-		previousProgress := pg.fetchSyncProgress()
-		// headers to fetch cannot be less than the previously fetched.
-		// Page refresh only needed if there is new data to update the UI.
-		if progress.headersToFetchOrScan >= previousProgress.headersToFetchOrScan {
-			currentAsset := pg.WL.SelectedWallet.Wallet
-			// set the new progress against the associated asset.
-			syncProgressInfo[currentAsset] = progress
-
-			// We only care about sync state changes here, to
-			// refresh the window display.
-			pg.ParentWindow().Reload()
-		}
-	}
-
-	syncProgressListener := &sharedW.SyncProgressListener{
-		OnHeadersFetchProgress: func(t *sharedW.HeadersFetchProgressReport) {
-			progress := progressInfo{}
-			progress.stepFetchProgress = t.HeadersFetchProgress
-			progress.headersToFetchOrScan = t.TotalHeadersToFetch
-			progress.syncProgress = int(t.TotalSyncProgress)
-			progress.remainingSyncTime = components.TimeFormat(int(t.TotalTimeRemainingSeconds), true)
-			updateSyncProgress(progress)
-		},
-		OnAddressDiscoveryProgress: func(t *sharedW.AddressDiscoveryProgressReport) {
-			progress := progressInfo{}
-			progress.syncProgress = int(t.TotalSyncProgress)
-			progress.remainingSyncTime = components.TimeFormat(int(t.TotalTimeRemainingSeconds), true)
-			progress.stepFetchProgress = t.AddressDiscoveryProgress
-			updateSyncProgress(progress)
-		},
-		OnHeadersRescanProgress: func(t *sharedW.HeadersRescanProgressReport) {
-			progress := progressInfo{}
-			progress.headersToFetchOrScan = t.TotalHeadersToScan
-			progress.syncProgress = int(t.TotalSyncProgress)
-			progress.remainingSyncTime = components.TimeFormat(int(t.TotalTimeRemainingSeconds), true)
-			progress.stepFetchProgress = t.RescanProgress
-			updateSyncProgress(progress)
-		},
-		OnSyncCompleted: func() {
+	walletID := pg.wallet.GetWalletID()
+	pg.setSyncStatus(pg.assetsManager.SyncManager.Status().Wallet(walletID))
+
+	syncStatusListener := &libwallet.SyncStatusListener{
+		OnSyncStatusChanged: func(status *libwallet.SyncStatus) {
+			// Update sync progress fields which will be displayed
+			// when the next UI invalidation occurs.
+			pg.setSyncStatus(status.Wallet(walletID))
 			pg.ParentWindow().Reload()
 		},
 	}
 
-	err := pg.WL.SelectedWallet.Wallet.AddSyncProgressListener(syncProgressListener, InfoID)
+	err := pg.assetsManager.SyncManager.AddSyncStatusListener(syncStatusListener, InfoID)
 	if err != nil {
-		log.Errorf("Error adding sync progress listener: %v", err)
+		log.Errorf("Error adding sync status listener: %v", err)
 		return
 	}
 
@@ -534,7 +490,7 @@ func (pg *WalletInfo) loadStakes() {
 // components unless they'll be recreated in the OnNavigatedTo() method.
 // Part of the load.Page interface.
 func (pg *WalletInfo) OnNavigatedFrom() {
-	pg.WL.SelectedWallet.Wallet.RemoveSyncProgressListener(InfoID)
+	pg.assetsManager.SyncManager.RemoveSyncStatusListener(InfoID)
 	pg.WL.SelectedWallet.Wallet.RemoveTxAndBlockNotificationListener(InfoID)
 	pg.WL.SelectedWallet.Wallet.SetBlocksRescanProgressListener(nil)
 }
@@ -6,6 +6,7 @@ import (
 
 	"gioui.org/layout"
 
+	"github.com/crypto-power/cryptopower/libwallet"
 	libutils "github.com/crypto-power/cryptopower/libwallet/utils"
 	"github.com/crypto-power/cryptopower/ui/cryptomaterial"
 	"github.com/crypto-power/cryptopower/ui/page/components"
@@ -92,6 +93,16 @@ func (pg *WalletInfo) syncBoxTitleRow(gtx C) D {
 					})
 				}),
 				layout.Rigid(statusLabel.Layout),
+				layout.Rigid(func(gtx C) D {
+					if !pg.WL.AssetsManager.IsProxied() {
+						return D{}
+					}
+					return layout.Inset{Left: values.MarginPadding4}.Layout(gtx, func(gtx C) D {
+						proxied := pg.Theme.Label(values.TextSize14, "("+values.String(values.StrViaProxy)+")")
+						proxied.Color = pg.Theme.Color.GrayText2
+						return proxied.Layout(gtx)
+					})
+				}),
 				layout.Rigid(func(gtx C) D {
 					if pg.WL.SelectedWallet.Wallet.IsConnectedToNetwork() {
 						return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
@@ -193,9 +204,9 @@ func (pg *WalletInfo) syncContent(gtx C, uniform layout.Inset) D {
 							if !isInprogress || (isRescanning && (isBtcORLtcAsset)) {
 								return D{}
 							}
-							pgrss := pg.fetchSyncProgress()
+							syncStatus := pg.walletSyncStatus()
 							blockHeightFetchedText := values.StringF(values.StrBlockHeaderFetchedCount, bestBlock.Height,
-								pgrss.headersToFetchOrScan)
+								syncStatus.HeadersToFetch)
 							blockHeightFetched := pg.Theme.Body1(blockHeightFetchedText)
 							return layout.Inset{Bottom: values.MarginPadding8}.Layout(gtx, blockHeightFetched.Layout)
 						}),
@@ -204,8 +215,16 @@ func (pg *WalletInfo) syncContent(gtx C, uniform layout.Inset) D {
 							w := pg.WL.SelectedWallet.Wallet
 							daysBehind := components.TimeFormat(int(currentSeconds-w.GetBestBlockTimeStamp()), true)
 
+							syncStatus := pg.walletSyncStatus()
 							syncProgress := values.String(values.StrWalletNotSynced)
-							if pg.WL.SelectedWallet.Wallet.IsSyncing() {
+							if syncStatus.State == libwallet.WalletSyncQueued {
+								syncProgress = values.String(values.StrSyncQueued)
+							} else if syncStatus.State == libwallet.WalletSyncRetrying {
+								retryIn := components.TimeFormat(int(time.Until(syncStatus.NextRetry).Seconds()), true)
+								syncProgress = values.StringF(values.StrSyncRetrying, retryIn)
+							} else if syncStatus.State == libwallet.WalletSyncFailed {
+								syncProgress = values.String(values.StrSyncFailed)
+							} else if pg.WL.SelectedWallet.Wallet.IsSyncing() {
 								syncProgress = values.StringF(values.StrSyncingProgressStat, daysBehind)
 							} else if pg.WL.SelectedWallet.Wallet.IsRescanning() {
 								syncProgress = values.String(values.StrRescanningBlocks)
@@ -260,9 +279,9 @@ func (pg *WalletInfo) progressBarRow(gtx C) D {
 // progressStatusRow lays out the progress status when the wallet is syncing.
 func (pg *WalletInfo) progressStatusDetails() (int, string) {
 	timeLeftLabel := ""
-	pgrss := pg.fetchSyncProgress()
-	timeLeft := pgrss.remainingSyncTime
-	progress := pgrss.syncProgress
+	syncStatus := pg.walletSyncStatus()
+	timeLeft := components.TimeFormat(int(syncStatus.TimeRemainingSeconds), true)
+	progress := int(syncStatus.Progress)
 
 	wallet := pg.WL.SelectedWallet.Wallet
 	walletIsRescanning := wallet.IsRescanning()
@@ -324,20 +343,20 @@ func (pg *WalletInfo) rescanDetailsLayout(gtx C, inset layout.Inset) D {
 	})
 }
 
-func (pg *WalletInfo) fetchSyncProgress() progressInfo {
-	pgrss, ok := syncProgressInfo[pg.WL.SelectedWallet.Wallet]
-	if !ok {
-		pgrss = progressInfo{}
+// setSyncStatus keeps the sync status of the wallet for the next UI
+// invalidation. It is called from the sync manager's goroutines.
+func (pg *WalletInfo) setSyncStatus(status *libwallet.WalletSyncStatus) {
+	if status == nil {
+		return
 	}
-	// remove the unnecessary sync progress data if already synced.
-	pg.deleteSyncProgress()
-	return pgrss
+
+	pg.syncStatusMtx.Lock()
+	pg.syncStatus = *status
+	pg.syncStatusMtx.Unlock()
 }
 
-// deleteSyncProgress removes the map entry after the data persisted is no longer necessary.
-func (pg *WalletInfo) deleteSyncProgress() {
-	wal := pg.WL.SelectedWallet.Wallet
-	if wal.IsSynced() {
-		delete(syncProgressInfo, wal)
-	}
+func (pg *WalletInfo) walletSyncStatus() libwallet.WalletSyncStatus {
+	pg.syncStatusMtx.Lock()
+	defer pg.syncStatusMtx.Unlock()
+	return pg.syncStatus
 }
@@ -3,6 +3,7 @@ package root
 import (
 	"context"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"gioui.org/layout"
 
 	"github.com/crypto-power/cryptopower/app"
+	"github.com/crypto-power/cryptopower/libwallet"
 	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
 	libutils "github.com/crypto-power/cryptopower/libwallet/utils"
 	"github.com/crypto-power/cryptopower/ui/cryptomaterial"
@@ -55,6 +57,11 @@ type HomePage struct {
 	showNavigationFunc showNavigationFunc
 	startSpvSync       uint32
 
+	// autoSyncedMtx guards autoSynced, the wallets auto sync was started for
+	// since the page was displayed.
+	autoSyncedMtx sync.Mutex
+	autoSynced    map[int]bool
+
 	totalBalanceUSD string
 }
 
@@ -106,8 +113,12 @@ func NewHomePage(l *load.Load) *HomePage {
 
 	// init shared page functions
 	toggleSync := func(unlock load.NeedUnlockRestore) {
-		if hp.WL.SelectedWallet.Wallet.IsConnectedToNetwork() {
-			go hp.WL.SelectedWallet.Wallet.CancelSync()
+		wallet := hp.WL.SelectedWallet.Wallet
+		syncStatus := hp.WL.AssetsManager.SyncManager.Status().Wallet(wallet.GetWalletID())
+		if wallet.IsConnectedToNetwork() || (syncStatus != nil && syncStatus.IsActive()) {
+			// Stopping a queued or retrying sync removes it from the sync
+			// manager queue.
+			go hp.WL.AssetsManager.SyncManager.Pause(wallet.GetWalletID())
 			unlock(false)
 		} else {
 			hp.startSyncing(hp.WL.SelectedWallet.Wallet, unlock)
@@ -148,25 +159,26 @@ func (hp *HomePage) OnNavigatedTo() {
 		hp.Display(NewOverviewPage(hp.Load, hp.showNavigationFunc))
 	}
 
+	hp.autoSyncedMtx.Lock()
+	hp.autoSynced = make(map[int]bool)
+	hp.autoSyncedMtx.Unlock()
+
+	// Wallets still opening in the background start syncing once opened.
+	hp.listenForWalletOpens()
+
 	// Initiate the auto sync for all the DCR wallets with set autosync.
 	for _, wallet := range hp.WL.SortedWalletList(libutils.DCRWalletAsset) {
-		if wallet.ReadBoolConfigValueForKey(sharedW.AutoSyncConfigKey, false) {
-			hp.startSyncing(wallet, func(isUnlock bool) {})
-		}
+		hp.autoSync(wallet)
 	}
 
 	// Initiate the auto sync for all the BTC wallets with set autosync.
 	for _, wallet := range hp.WL.SortedWalletList(libutils.BTCWalletAsset) {
-		if wallet.ReadBoolConfigValueForKey(sharedW.AutoSyncConfigKey, false) {
-			hp.startSyncing(wallet, func(isUnlock bool) {})
-		}
+		hp.autoSync(wallet)
 	}
 
 	// Initiate the auto sync for all the LTC wallets with set autosync.
 	for _, wallet := range hp.WL.SortedWalletList(libutils.LTCWalletAsset) {
-		if wallet.ReadBoolConfigValueForKey(sharedW.AutoSyncConfigKey, false) {
-			hp.startSyncing(wallet, func(isUnlock bool) {})
-		}
+		hp.autoSync(wallet)
 	}
 
 	hp.isBalanceHidden = hp.WL.AssetsManager.IsTotalBalanceVisible()
@@ -349,6 +361,7 @@ func (hp *HomePage) OnNavigatedFrom() {
 		activeTab.OnNavigatedFrom()
 	}
 
+	hp.WL.AssetsManager.RemoveWalletOpenListener(HomePageID)
 	hp.ctxCancel()
 }
 
@@ -691,6 +704,42 @@ func (hp *HomePage) notificationSettingsLayout(gtx C) D {
 	)
 }
 
+// autoSync starts syncing wallet if it is opened and has autosync set. The
+// sync is only started once for every wallet, whether the wallet was found
+// opened or reported opened by the wallet open listener.
+func (hp *HomePage) autoSync(wallet sharedW.Asset) {
+	if !wallet.WalletOpened() || !wallet.ReadBoolConfigValueForKey(sharedW.AutoSyncConfigKey, false) {
+		return
+	}
+
+	hp.autoSyncedMtx.Lock()
+	started := hp.autoSynced[wallet.GetWalletID()]
+	hp.autoSynced[wallet.GetWalletID()] = true
+	hp.autoSyncedMtx.Unlock()
+
+	if !started {
+		hp.startSyncing(wallet, func(isUnlock bool) {})
+	}
+}
+
+func (hp *HomePage) listenForWalletOpens() {
+	walletOpenListener := &libwallet.WalletOpenListener{
+		OnWalletOpenStateChanged: func(status *libwallet.WalletOpenStatus) {
+			if status.State != libwallet.WalletOpenOpened {
+				return
+			}
+			if wallet := hp.WL.AssetsManager.WalletWithID(status.WalletID); wallet != nil {
+				hp.autoSync(wallet)
+			}
+		},
+	}
+
+	err := hp.WL.AssetsManager.AddWalletOpenListener(walletOpenListener, HomePageID)
+	if err != nil {
+		log.Errorf("Error adding wallet open listener: %v", err)
+	}
+}
+
 func (hp *HomePage) startSyncing(wallet sharedW.Asset, unlock load.NeedUnlockRestore) {
 	// Watchonly wallets do not have any password neither need one.
 	if !wallet.ContainsDiscoveredAccounts() && wallet.IsLocked() && !wallet.IsWatchingOnlyWallet() {
@@ -702,7 +751,7 @@ func (hp *HomePage) startSyncing(wallet sharedW.Asset, unlock load.NeedUnlockRes
 	if hp.isConnected.Load() {
 		// once network connection has been established proceed to
 		// start the wallet sync.
-		if err := wallet.SpvSync(); err != nil {
+		if err := hp.WL.AssetsManager.SyncManager.Start(wallet.GetWalletID()); err != nil {
 			log.Debugf("Error starting sync: %v", err)
 		}
 	}
@@ -729,7 +778,7 @@ func (hp *HomePage) startSyncing(wallet sharedW.Asset, unlock load.NeedUnlockRes
 					log.Info("Internet connection has been established")
 					// once network connection has been established proceed to
 					// start the wallet sync.
-					if err := wallet.SpvSync(); err != nil {
+					if err := hp.WL.AssetsManager.SyncManager.Start(wallet.GetWalletID()); err != nil {
 						log.Debugf("Error starting sync: %v", err)
 					}
 
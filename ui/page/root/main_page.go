@@ -12,6 +12,7 @@ import (
 	"gioui.org/widget/material"
 
 	"github.com/crypto-power/cryptopower/app"
+	"github.com/crypto-power/cryptopower/libwallet"
 	"github.com/crypto-power/cryptopower/libwallet/assets/dcr"
 	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
 	libutils "github.com/crypto-power/cryptopower/libwallet/utils"
@@ -628,15 +629,19 @@ func initializeBeepNotification(n string) {
 // listenForNotifications starts a goroutine to watch for notifications
 // and update the UI accordingly.
 func (mp *MainPage) listenForNotifications() {
-	syncProgressListener := &sharedW.SyncProgressListener{
-		OnSyncCompleted: func() {
+	selectedWalletID := mp.selectedWallet.GetWalletID()
+	syncStatusListener := &libwallet.SyncStatusListener{
+		OnWalletSynced: func(walletID int) {
+			if walletID != selectedWalletID {
+				return
+			}
 			mp.updateBalance()
 			mp.ParentWindow().Reload()
 		},
 	}
-	err := mp.selectedWallet.AddSyncProgressListener(syncProgressListener, MainPageID)
+	err := mp.WL.AssetsManager.SyncManager.AddSyncStatusListener(syncStatusListener, MainPageID)
 	if err != nil {
-		log.Errorf("Error adding sync progress listener: %v", err)
+		log.Errorf("Error adding sync status listener: %v", err)
 		return
 	}
 
@@ -693,7 +698,7 @@ func (mp *MainPage) listenForNotifications() {
 }
 
 func (mp *MainPage) stopNtfnListeners() {
-	mp.selectedWallet.RemoveSyncProgressListener(MainPageID)
+	mp.WL.AssetsManager.SyncManager.RemoveSyncStatusListener(MainPageID)
 	mp.selectedWallet.RemoveTxAndBlockNotificationListener(MainPageID)
 	mp.WL.AssetsManager.Politeia.RemoveSyncCallback(MainPageID)
 }
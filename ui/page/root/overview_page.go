@@ -1106,6 +1106,20 @@ func (pg *OverviewPage) listenForMixerNotifications() {
 		log.Error("RateSource.AddRateListener error: %v", err)
 	}
 
+	// Load the activity of the wallets opened in the background after startup.
+	walletOpenListener := &libwallet.WalletOpenListener{
+		OnWalletOpenStateChanged: func(status *libwallet.WalletOpenStatus) {
+			if status.State == libwallet.WalletOpenOpened {
+				pg.loadTransactions()
+				pg.ParentWindow().Reload()
+			}
+		},
+	}
+	err = pg.WL.AssetsManager.AddWalletOpenListener(walletOpenListener, OverviewPageID)
+	if err != nil {
+		log.Errorf("Error adding wallet open listener: %v", err)
+	}
+
 	pg.sortedMixerSlideKeys = make([]int, 0)
 	pg.mixerSliderData = make(map[int]*mixerData)
 	for _, wal := range wallets {
@@ -1142,6 +1156,7 @@ func (pg *OverviewPage) stopNtfnListeners() {
 		wal.RemoveTxAndBlockNotificationListener(OverviewPageID)
 	}
 	pg.WL.AssetsManager.RateSource.RemoveRateListener(OverviewPageID)
+	pg.WL.AssetsManager.RemoveWalletOpenListener(OverviewPageID)
 }
 
 func (pg *OverviewPage) setUnMixedBalance(id int) {
@@ -1223,6 +1238,9 @@ func (pg *OverviewPage) loadTransactions() {
 	transactions := make([]*multiWalletTx, 0)
 	wal := pg.WL.AllSortedWalletList()
 	for _, w := range wal {
+		if !w.WalletOpened() {
+			continue
+		}
 		txs, err := w.GetTransactionsRaw(0, 3, libutils.TxFilterAllTx, true)
 		if err != nil {
 			log.Errorf("error loading transactions: %v", err)
@@ -1250,6 +1268,9 @@ func (pg *OverviewPage) loadStakes() {
 	stakes := make([]*multiWalletTx, 0)
 	wal := pg.WL.AssetsManager.AllDCRWallets()
 	for _, w := range wal {
+		if !w.WalletOpened() {
+			continue
+		}
 		txs, err := w.GetTransactionsRaw(0, 6, libutils.TxFilterStaking, true)
 		if err != nil {
 			log.Errorf("error loading staking activities: %v", err)
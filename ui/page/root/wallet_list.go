@@ -1,15 +1,19 @@
 package root
 
 import (
+	"strings"
+
 	"gioui.org/font"
 	"gioui.org/layout"
 
+	"github.com/crypto-power/cryptopower/libwallet"
 	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
 	libutils "github.com/crypto-power/cryptopower/libwallet/utils"
 	"github.com/crypto-power/cryptopower/ui/cryptomaterial"
 	"github.com/crypto-power/cryptopower/ui/load"
 	"github.com/crypto-power/cryptopower/ui/modal"
 	"github.com/crypto-power/cryptopower/ui/page/components"
+	"github.com/crypto-power/cryptopower/ui/preference"
 	"github.com/crypto-power/cryptopower/ui/utils"
 	"github.com/crypto-power/cryptopower/ui/values"
 )
@@ -24,14 +28,19 @@ func (pg *WalletSelectorPage) loadWallets() {
 	walletsList := make(map[libutils.AssetType][]*load.WalletItem)
 
 	for _, wal := range wallets {
-		balance, err := wal.GetWalletBalance()
-		if err != nil {
-			log.Errorf("wallet (%v) balance was ignored : %v", wal.GetWalletName(), err)
-		}
-
 		listItem := &load.WalletItem{
 			Wallet:       wal,
-			TotalBalance: balance.Total,
+			TotalBalance: wal.ToAmount(0),
+		}
+
+		// Wallets still opening in the background have no balance yet.
+		if wal.WalletOpened() {
+			balance, err := wal.GetWalletBalance()
+			if err != nil {
+				log.Errorf("wallet (%v) balance was ignored : %v", wal.GetWalletName(), err)
+			} else {
+				listItem.TotalBalance = balance.Total
+			}
 		}
 
 		walletsList[wal.GetAssetType()] = append(walletsList[wal.GetAssetType()], listItem)
@@ -59,6 +68,8 @@ func (pg *WalletSelectorPage) loadBadWallets() {
 			}
 			listItem.deleteBtn.Color = pg.Theme.Color.Danger
 			listItem.deleteBtn.Inset = layout.Inset{}
+			listItem.repairBtn = pg.Theme.OutlineButton(values.String(values.StrRepair))
+			listItem.repairBtn.Inset = layout.Inset{}
 			pg.badWalletsList[assetType] = append(pg.badWalletsList[assetType], listItem)
 		}
 	}
@@ -91,6 +102,139 @@ func (pg *WalletSelectorPage) deleteBadWallet(badWalletID int) {
 	pg.ParentWindow().ShowModal(warningModal)
 }
 
+// diagnoseBadWallet shows why the bad wallet failed to load and the fixes
+// that can be applied before the user decides to delete it.
+func (pg *WalletSelectorPage) diagnoseBadWallet(badWalletID int) {
+	go func() {
+		report, err := pg.WL.AssetsManager.DiagnoseBadWallet(badWalletID)
+		if err != nil {
+			errorModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+			pg.ParentWindow().ShowModal(errorModal)
+			return
+		}
+
+		reportModal := modal.NewCustomModal(pg.Load).
+			Title(values.String(values.StrWalletDiagnosis)).
+			Body(badWalletReportText(report)).
+			SetNegativeButtonText(values.String(values.StrCancel)).
+			PositiveButtonStyle(pg.Theme.Color.Primary, pg.Theme.Color.Surface).
+			SetPositiveButtonText(values.String(values.StrRepair)).
+			SetPositiveButtonCallback(func(_ bool, _ *modal.InfoModal) bool {
+				pg.selectBadWalletRepair(report)
+				return true
+			})
+		pg.ParentWindow().ShowModal(reportModal)
+	}()
+}
+
+// selectBadWalletRepair lets the user pick the fix to apply if the diagnosis
+// suggests several.
+func (pg *WalletSelectorPage) selectBadWalletRepair(report *libwallet.BadWalletReport) {
+	switch len(report.Repairs) {
+	case 0:
+		go pg.repairBadWallet(report.WalletID, libwallet.BadWalletRepairNone, false)
+		return
+	case 1:
+		pg.confirmBadWalletRepair(report.WalletID, report.Repairs[0])
+		return
+	}
+
+	items := make([]preference.ItemPreference, 0, len(report.Repairs))
+	for _, repair := range report.Repairs {
+		items = append(items, preference.ItemPreference{
+			Key:   string(repair),
+			Value: values.String(badWalletRepairs[repair]),
+		})
+	}
+	repairModal := preference.NewListPreference(pg.Load, "", string(report.Repairs[0]), items).
+		Title(values.StrSelectRepair).
+		IsWallet(true).
+		UpdateValues(func(val string) {
+			pg.confirmBadWalletRepair(report.WalletID, libwallet.BadWalletRepair(val))
+		})
+	pg.ParentWindow().ShowModal(repairModal)
+}
+
+// confirmBadWalletRepair asks the user to confirm fixes that replace the
+// wallet before they are applied.
+func (pg *WalletSelectorPage) confirmBadWalletRepair(badWalletID int, repair libwallet.BadWalletRepair) {
+	if !repair.RequiresConfirmation() {
+		go pg.repairBadWallet(badWalletID, repair, false)
+		return
+	}
+
+	confirmModal := modal.NewCustomModal(pg.Load).
+		Title(values.String(badWalletRepairs[repair])).
+		Body(values.String(values.StrConfirmRecreateWatchOnly)).
+		SetNegativeButtonText(values.String(values.StrCancel)).
+		PositiveButtonStyle(pg.Theme.Color.Surface, pg.Theme.Color.Danger).
+		SetPositiveButtonText(values.String(values.StrRepair)).
+		SetPositiveButtonCallback(func(_ bool, _ *modal.InfoModal) bool {
+			go pg.repairBadWallet(badWalletID, repair, true)
+			return true
+		})
+	pg.ParentWindow().ShowModal(confirmModal)
+}
+
+func (pg *WalletSelectorPage) repairBadWallet(badWalletID int, repair libwallet.BadWalletRepair, confirmed bool) {
+	report, err := pg.WL.AssetsManager.RepairBadWallet(badWalletID, repair, confirmed)
+	switch {
+	case err != nil:
+		errorModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+		pg.ParentWindow().ShowModal(errorModal)
+	case report.Repaired:
+		infoModal := modal.NewSuccessModal(pg.Load, values.String(values.StrBadWalletRepaired), modal.DefaultClickFunc())
+		pg.ParentWindow().ShowModal(infoModal)
+	default:
+		msg := values.StringF(values.StrBadWalletNotRepaired, badWalletReportText(report))
+		errorModal := modal.NewErrorModal(pg.Load, msg, modal.DefaultClickFunc())
+		pg.ParentWindow().ShowModal(errorModal)
+	}
+
+	pg.loadWallets()
+	pg.loadBadWallets()
+	pg.ParentWindow().Reload()
+}
+
+// badWalletRepairs maps the fixes of bad wallets to their description.
+var badWalletRepairs = map[libwallet.BadWalletRepair]string{
+	libwallet.BadWalletRepairClearStaleLock:    values.StrRepairClearStaleLock,
+	libwallet.BadWalletRepairRebuildWalletData: values.StrRepairRebuildWalletData,
+	libwallet.BadWalletRepairRecreateWatchOnly: values.StrRepairRecreateWatchOnly,
+}
+
+// badWalletReportText describes the problem found with a bad wallet and the
+// fixes that can be applied.
+func badWalletReportText(report *libwallet.BadWalletReport) string {
+	issues := map[libwallet.BadWalletIssue]string{
+		libwallet.BadWalletIssueNone:            values.StrIssueNone,
+		libwallet.BadWalletIssueUnknownAsset:    values.StrIssueUnknownAsset,
+		libwallet.BadWalletIssueMissingDB:       values.StrIssueMissingDB,
+		libwallet.BadWalletIssueCorruptDB:       values.StrIssueCorruptDB,
+		libwallet.BadWalletIssueWrongDriver:     values.StrIssueWrongDriver,
+		libwallet.BadWalletIssueVersionMismatch: values.StrIssueVersionMismatch,
+		libwallet.BadWalletIssueLockedDB:        values.StrIssueLockedDB,
+		libwallet.BadWalletIssueWalletData:      values.StrIssueWalletData,
+	}
+	lines := []string{values.String(issues[report.Issue])}
+	if report.Details != "" {
+		lines = append(lines, report.Details)
+	} else if report.LoadError != "" {
+		lines = append(lines, report.LoadError)
+	}
+
+	if len(report.Repairs) == 0 {
+		lines = append(lines, "", values.String(values.StrNoBadWalletRepairs))
+		return strings.Join(lines, "\n")
+	}
+
+	lines = append(lines, "", values.String(values.StrBadWalletRepairs))
+	for _, repair := range report.Repairs {
+		lines = append(lines, "- "+values.String(badWalletRepairs[repair]))
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (pg *WalletSelectorPage) syncStatusIcon(gtx C, wallet sharedW.Asset) D {
 	var (
 		syncStatusIcon *cryptomaterial.Image
@@ -119,6 +263,28 @@ func (pg *WalletSelectorPage) syncStatusIcon(gtx C, wallet sharedW.Asset) D {
 	)
 }
 
+// openStatusLabel shows the startup state of a wallet that is not opened yet.
+func (pg *WalletSelectorPage) openStatusLabel(gtx C, status *libwallet.WalletOpenStatus) D {
+	var lbl cryptomaterial.Label
+	switch {
+	case status.Migration != nil:
+		progress := status.Migration
+		lbl = pg.Theme.Label(values.TextSize16, values.StringF(values.StrMigratingDB, progress.DBName,
+			progress.Step, progress.Total, progress.Description))
+		lbl.Color = pg.Theme.Color.GrayText2
+	case status.IsLoading():
+		lbl = pg.Theme.Label(values.TextSize16, values.String(values.StrLoading))
+		lbl.Color = pg.Theme.Color.GrayText2
+	case status.State == libwallet.WalletOpenFailed:
+		lbl = pg.Theme.Label(values.TextSize16, values.String(values.StrWalletOpenFailed))
+		lbl.Color = pg.Theme.Color.Danger
+	default:
+		lbl = pg.Theme.Label(values.TextSize16, values.String(values.StrOpensWhenSelected))
+		lbl.Color = pg.Theme.Color.GrayText2
+	}
+	return lbl.Layout(gtx)
+}
+
 func (pg *WalletSelectorPage) walletListLayout(gtx C, assetType libutils.AssetType) D {
 	walletSections := []func(gtx C) D{}
 	if len(pg.walletsList[assetType]) > 0 {
@@ -185,7 +351,14 @@ func (pg *WalletSelectorPage) badWalletsWrapper(gtx C, badWalletsList []*badWall
 					return layout.Flex{}.Layout(gtx,
 						layout.Rigid(pg.Theme.Body2(badWallet.Name).Layout),
 						layout.Flexed(1, func(gtx C) D {
-							return layout.E.Layout(gtx, badWallet.deleteBtn.Layout)
+							return layout.E.Layout(gtx, func(gtx C) D {
+								return layout.Flex{}.Layout(gtx,
+									layout.Rigid(func(gtx C) D {
+										return layout.Inset{Right: values.MarginPadding16}.Layout(gtx, badWallet.repairBtn.Layout)
+									}),
+									layout.Rigid(badWallet.deleteBtn.Layout),
+								)
+							})
 						}),
 					)
 				}),
@@ -289,6 +462,10 @@ func (pg *WalletSelectorPage) walletWrapper(gtx C, item *load.WalletItem) D {
 						Alignment: layout.Middle,
 					}.Layout(gtx,
 						layout.Rigid(func(gtx C) D {
+							status := pg.WL.AssetsManager.WalletOpenStatus(item.Wallet.GetWalletID())
+							if status.State != libwallet.WalletOpenOpened {
+								return pg.openStatusLabel(gtx, status)
+							}
 							return pg.syncStatusIcon(gtx, item.Wallet)
 						}),
 						layout.Rigid(func(gtx C) D {
@@ -318,6 +495,9 @@ func (pg *WalletSelectorPage) walletWrapper(gtx C, item *load.WalletItem) D {
 			)
 		}),
 		layout.Flexed(1, func(gtx C) D {
+			if !item.Wallet.WalletOpened() {
+				return D{}
+			}
 			return layout.E.Layout(gtx, func(gtx C) D {
 				return layout.Flex{
 					Axis:      layout.Vertical,
@@ -341,25 +521,64 @@ func (pg *WalletSelectorPage) walletWrapper(gtx C, item *load.WalletItem) D {
 
 // start sync listener
 func (pg *WalletSelectorPage) listenForSyncProgressNotifications() {
-	syncListener := &sharedW.SyncProgressListener{
-		OnSyncCompleted: func() {
+	pg.setWalletsSyncStatus(pg.WL.AssetsManager.SyncManager.Status())
+
+	syncStatusListener := &libwallet.SyncStatusListener{
+		OnSyncStatusChanged: func(status *libwallet.SyncStatus) {
+			pg.setWalletsSyncStatus(status)
 			pg.ParentWindow().Reload()
 		},
 	}
 
-	allWallets := pg.WL.AllSortedWalletList()
-	for _, w := range allWallets {
-		err := w.AddSyncProgressListener(syncListener, WalletSelectorPageID)
-		if err != nil {
-			log.Errorf("Error adding sync progress listener: %v", err)
-			return
-		}
+	err := pg.WL.AssetsManager.SyncManager.AddSyncStatusListener(syncStatusListener, WalletSelectorPageID)
+	if err != nil {
+		log.Errorf("Error adding sync status listener: %v", err)
+	}
+
+	walletOpenListener := &libwallet.WalletOpenListener{
+		OnWalletOpenStateChanged: func(status *libwallet.WalletOpenStatus) {
+			if status.State == libwallet.WalletOpenOpened {
+				// Load the balance of the newly opened wallet.
+				pg.loadWallets()
+			}
+			pg.ParentWindow().Reload()
+		},
+	}
+
+	err = pg.WL.AssetsManager.AddWalletOpenListener(walletOpenListener, WalletSelectorPageID)
+	if err != nil {
+		log.Errorf("Error adding wallet open listener: %v", err)
 	}
 }
 
 func (pg *WalletSelectorPage) stopSyncProgressListeners() {
-	allWallets := pg.WL.AllSortedWalletList()
-	for _, w := range allWallets {
-		w.RemoveSyncProgressListener(WalletSelectorPageID)
+	pg.WL.AssetsManager.SyncManager.RemoveSyncStatusListener(WalletSelectorPageID)
+	pg.WL.AssetsManager.RemoveWalletOpenListener(WalletSelectorPageID)
+}
+
+// openSelectedWallet displays the main page of wallet, opening the wallet
+// first if it is not opened yet.
+func (pg *WalletSelectorPage) openSelectedWallet(wallet *load.WalletItem) {
+	if !wallet.Wallet.WalletOpened() {
+		go func() {
+			if err := pg.WL.AssetsManager.EnsureWalletOpened(wallet.Wallet.GetWalletID()); err != nil {
+				errorModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+				pg.ParentWindow().ShowModal(errorModal)
+				return
+			}
+			pg.displayWallet(wallet)
+		}()
+		return
+	}
+	pg.displayWallet(wallet)
+}
+
+func (pg *WalletSelectorPage) displayWallet(wallet *load.WalletItem) {
+	pg.WL.SelectedWallet = wallet
+	pg.showNavigationFunc(true)
+
+	callback := func() {
+		pg.ParentNavigator().CloseCurrentPage()
 	}
+	pg.ParentNavigator().Display(NewMainPage(pg.Load, callback))
 }
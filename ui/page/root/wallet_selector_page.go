@@ -1,6 +1,7 @@
 package root
 
 import (
+	"fmt"
 	"sync"
 
 	"gioui.org/font"
@@ -8,6 +9,7 @@ import (
 	"gioui.org/widget"
 
 	"github.com/crypto-power/cryptopower/app"
+	"github.com/crypto-power/cryptopower/libwallet"
 	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
 	libutils "github.com/crypto-power/cryptopower/libwallet/utils"
 	"github.com/crypto-power/cryptopower/ui/cryptomaterial"
@@ -27,6 +29,7 @@ type (
 type badWalletListItem struct {
 	*sharedW.Wallet
 	deleteBtn cryptomaterial.Button
+	repairBtn cryptomaterial.Button
 }
 
 type walletIndexTuple struct {
@@ -61,6 +64,10 @@ type WalletSelectorPage struct {
 	assetsTotalUSDBalance map[libutils.AssetType]float64
 	assetRate             map[libutils.AssetType]float64
 
+	syncStatusMtx sync.Mutex
+	syncStatus    *libwallet.SyncStatus
+	syncAllBtn    cryptomaterial.Button
+
 	showNavigationFunc showNavigationFunc
 }
 
@@ -79,8 +86,9 @@ func NewWalletSelectorPage(l *load.Load) *WalletSelectorPage {
 				Alignment: layout.Middle,
 			},
 		},
-		Load:      l,
-		shadowBox: l.Theme.Shadow(),
+		Load:       l,
+		shadowBox:  l.Theme.Shadow(),
+		syncAllBtn: l.Theme.OutlineButton(values.String(values.StrSyncAll)),
 	}
 
 	pg.assetCollapsibles = make(map[libutils.AssetType]*cryptomaterial.Collapsible)
@@ -170,13 +178,7 @@ func (pg *WalletSelectorPage) HandleUserInteractions() {
 			return
 		}
 
-		pg.WL.SelectedWallet = wallets[tuple.Index]
-		pg.showNavigationFunc(true)
-
-		callback := func() {
-			pg.ParentNavigator().CloseCurrentPage()
-		}
-		pg.ParentNavigator().Display(NewMainPage(pg.Load, callback))
+		pg.openSelectedWallet(wallets[tuple.Index])
 	}
 
 	for _, walletsOfType := range pg.badWalletsList {
@@ -185,6 +187,17 @@ func (pg *WalletSelectorPage) HandleUserInteractions() {
 				pg.deleteBadWallet(badWallet.Wallet.ID)
 				pg.ParentWindow().Reload()
 			}
+			if badWallet.repairBtn.Clicked() {
+				pg.diagnoseBadWallet(badWallet.Wallet.ID)
+			}
+		}
+	}
+
+	if pg.syncAllBtn.Clicked() {
+		if pg.walletsSyncStatus().IsSyncing() {
+			go pg.WL.AssetsManager.SyncManager.PauseAll()
+		} else {
+			go pg.WL.AssetsManager.SyncManager.StartAll()
 		}
 	}
 
@@ -236,6 +249,7 @@ func (pg *WalletSelectorPage) pageContentLayout(gtx C) D {
 	}
 
 	pageContent := []func(gtx C) D{
+		pg.syncSummaryLayout,
 		assetDropdown,
 	}
 
@@ -431,3 +445,76 @@ func (pg *WalletSelectorPage) layoutAddMoreRowSection(clk *cryptomaterial.Clicka
 		})
 	}
 }
+
+// syncSummaryLayout lays out the merged sync progress of all the wallets and
+// the button that starts or pauses their syncs.
+func (pg *WalletSelectorPage) syncSummaryLayout(gtx C) D {
+	status := pg.walletsSyncStatus()
+	if len(status.Wallets) == 0 {
+		return D{}
+	}
+
+	if status.IsSyncing() {
+		pg.syncAllBtn.Text = values.String(values.StrPauseAll)
+	} else {
+		pg.syncAllBtn.Text = values.String(values.StrSyncAll)
+	}
+
+	return layout.Inset{Top: values.MarginPadding15}.Layout(gtx, func(gtx C) D {
+		return pg.Theme.Card().Layout(gtx, func(gtx C) D {
+			gtx.Constraints.Min.X = gtx.Constraints.Max.X
+			return layout.UniformInset(values.MarginPadding16).Layout(gtx, func(gtx C) D {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(func(gtx C) D {
+						return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+							layout.Flexed(1, func(gtx C) D {
+								synced := values.StringF(values.StrWalletsSynced, status.Synced, len(status.Wallets))
+								return pg.Theme.Label(values.TextSize16, synced).Layout(gtx)
+							}),
+							layout.Rigid(pg.syncAllBtn.Layout),
+						)
+					}),
+					layout.Rigid(func(gtx C) D {
+						if !status.IsSyncing() {
+							return D{}
+						}
+						return layout.Inset{Top: values.MarginPadding10}.Layout(gtx, func(gtx C) D {
+							p := pg.Theme.ProgressBar(int(status.Progress))
+							p.Height = values.MarginPadding16
+							p.Radius = cryptomaterial.Radius(4)
+							p.Color = pg.Theme.Color.Success
+							p.TrackColor = pg.Theme.Color.Gray2
+
+							progress := fmt.Sprintf("%d%%", status.Progress)
+							if status.TimeRemainingSeconds > 0 {
+								timeLeft := components.TimeFormat(int(status.TimeRemainingSeconds), true)
+								progress += " - " + values.StringF(values.StrTimeLeft, timeLeft)
+							}
+							progressLabel := pg.Theme.Label(values.TextSize14, progress)
+							progressLabel.Color = pg.Theme.Color.Text
+							return p.TextLayout(gtx, progressLabel.Layout)
+						})
+					}),
+				)
+			})
+		})
+	})
+}
+
+// setWalletsSyncStatus keeps the merged sync status for the next UI
+// invalidation. It is called from the sync manager's goroutines.
+func (pg *WalletSelectorPage) setWalletsSyncStatus(status *libwallet.SyncStatus) {
+	pg.syncStatusMtx.Lock()
+	pg.syncStatus = status
+	pg.syncStatusMtx.Unlock()
+}
+
+func (pg *WalletSelectorPage) walletsSyncStatus() *libwallet.SyncStatus {
+	pg.syncStatusMtx.Lock()
+	defer pg.syncStatusMtx.Unlock()
+
+	if pg.syncStatus == nil {
+		return &libwallet.SyncStatus{}
+	}
+	return pg.syncStatus
+}
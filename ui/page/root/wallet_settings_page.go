@@ -3,6 +3,7 @@ package root
 import (
 	"strconv"
 	"strings"
+	"time"
 
 	"gioui.org/font"
 	"gioui.org/layout"
@@ -18,12 +19,17 @@ import (
 	"github.com/crypto-power/cryptopower/ui/page/components"
 	"github.com/crypto-power/cryptopower/ui/page/security"
 	s "github.com/crypto-power/cryptopower/ui/page/settings"
+	"github.com/crypto-power/cryptopower/ui/preference"
 	"github.com/crypto-power/cryptopower/ui/utils"
 	"github.com/crypto-power/cryptopower/ui/values"
 )
 
 const WalletSettingsPageID = "WalletSettings"
 
+// birthdayLayout is the date format expected in the birthday and rescan date
+// inputs.
+const birthdayLayout = "2006-01-02"
+
 type clickableRowData struct {
 	clickable *cryptomaterial.Clickable
 	labelText string
@@ -54,17 +60,40 @@ type WalletSettingsPage struct {
 	changeWalletName, addAccount, deleteWallet *cryptomaterial.Clickable
 	verifyMessage, validateAddr, signMessage   *cryptomaterial.Clickable
 	updateConnectToPeer, setGapLimit           *cryptomaterial.Clickable
+	addressExplorer, importKey, multisig       *cryptomaterial.Clickable
+	dbDriver, fullNode, peers, birthday        *cryptomaterial.Clickable
+	accountScope                               *cryptomaterial.Clickable
 
 	backButton cryptomaterial.IconButton
 	infoButton cryptomaterial.IconButton
 
 	spendUnconfirmed  *cryptomaterial.Switch
 	spendUnmixedFunds *cryptomaterial.Switch
+	openOnDemand      *cryptomaterial.Switch
 	connectToPeer     *cryptomaterial.Switch
 
 	walletCallbackFunc func()
 
-	peerAddr string
+	peerAddr         string
+	dbDriverLabel    string
+	keyScopePurposes []uint32
+}
+
+// dbDriverMigrator is implemented by wallets whose database can be moved to
+// another walletdb driver.
+type dbDriverMigrator interface {
+	DatabaseDriver() (string, error)
+	MigrateDatabaseDriver(driver string) error
+	HasPendingDatabaseDriverMigration() bool
+	ConfirmDatabaseDriverMigration() error
+	RevertDatabaseDriverMigration() error
+}
+
+// keyScopeSelector is implemented by wallets whose accounts may be those of
+// several key scopes.
+type keyScopeSelector interface {
+	KeyScopePurposes() []uint32
+	SetKeyScopePurpose(purpose uint32) error
 }
 
 func NewWalletSettingsPage(l *load.Load, walletCallbackFunc func()) *WalletSettingsPage {
@@ -74,6 +103,7 @@ func NewWalletSettingsPage(l *load.Load, walletCallbackFunc func()) *WalletSetti
 		wallet:              l.WL.SelectedWallet.Wallet,
 		changePass:          l.Theme.NewClickable(false),
 		rescan:              l.Theme.NewClickable(false),
+		birthday:            l.Theme.NewClickable(false),
 		setGapLimit:         l.Theme.NewClickable(false),
 		changeAccount:       l.Theme.NewClickable(false),
 		checklog:            l.Theme.NewClickable(false),
@@ -85,9 +115,17 @@ func NewWalletSettingsPage(l *load.Load, walletCallbackFunc func()) *WalletSetti
 		validateAddr:        l.Theme.NewClickable(false),
 		signMessage:         l.Theme.NewClickable(false),
 		updateConnectToPeer: l.Theme.NewClickable(false),
+		addressExplorer:     l.Theme.NewClickable(false),
+		importKey:           l.Theme.NewClickable(false),
+		multisig:            l.Theme.NewClickable(false),
+		dbDriver:            l.Theme.NewClickable(false),
+		fullNode:            l.Theme.NewClickable(false),
+		peers:               l.Theme.NewClickable(false),
+		accountScope:        l.Theme.NewClickable(false),
 
 		spendUnconfirmed:  l.Theme.Switch(),
 		spendUnmixedFunds: l.Theme.Switch(),
+		openOnDemand:      l.Theme.Switch(),
 		connectToPeer:     l.Theme.Switch(),
 
 		pageContainer: &widget.List{
@@ -109,8 +147,10 @@ func NewWalletSettingsPage(l *load.Load, walletCallbackFunc func()) *WalletSetti
 func (pg *WalletSettingsPage) OnNavigatedTo() {
 	pg.spendUnconfirmed.SetChecked(pg.readBool(sharedW.SpendUnconfirmedConfigKey))
 	pg.spendUnmixedFunds.SetChecked(pg.readBool(sharedW.SpendUnmixedFundsKey))
+	pg.openOnDemand.SetChecked(pg.WL.AssetsManager.IsOpenOnDemand(pg.wallet.GetWalletID()))
 
 	pg.loadPeerAddress()
+	pg.loadDBDriver()
 
 	pg.loadWalletAccount()
 }
@@ -125,7 +165,7 @@ func (pg *WalletSettingsPage) isPrivacyModeOn() bool {
 
 func (pg *WalletSettingsPage) loadPeerAddress() {
 	if !pg.isPrivacyModeOn() {
-		pg.peerAddr = pg.WL.SelectedWallet.Wallet.ReadStringConfigValueForKey(sharedW.SpvPersistentPeerAddressesConfigKey, "")
+		pg.peerAddr = strings.Join(pg.wallet.PeerConfig().Persistent, ", ")
 		pg.connectToPeer.SetChecked(false)
 		if pg.peerAddr != "" {
 			pg.connectToPeer.SetChecked(true)
@@ -133,6 +173,28 @@ func (pg *WalletSettingsPage) loadPeerAddress() {
 	}
 }
 
+// peersLabel returns the number of peers the wallet is connected to.
+func (pg *WalletSettingsPage) peersLabel() string {
+	if !pg.wallet.IsConnectedToNetwork() {
+		return values.String(values.StrNotConnected)
+	}
+	return strconv.Itoa(int(pg.wallet.ConnectedPeers()))
+}
+
+func (pg *WalletSettingsPage) loadDBDriver() {
+	migrator, ok := pg.wallet.(dbDriverMigrator)
+	if !ok {
+		return
+	}
+
+	driver, err := migrator.DatabaseDriver()
+	if err != nil {
+		log.Errorf("error reading wallet database driver: %v", err)
+		return
+	}
+	pg.dbDriverLabel = driver
+}
+
 func (pg *WalletSettingsPage) loadWalletAccount() {
 	walletAccounts := make([]*accountData, 0)
 	accounts, err := pg.wallet.GetAccountsRaw()
@@ -152,6 +214,10 @@ func (pg *WalletSettingsPage) loadWalletAccount() {
 	}
 
 	pg.accounts = walletAccounts
+
+	if selector, ok := pg.wallet.(keyScopeSelector); ok {
+		pg.keyScopePurposes = selector.KeyScopePurposes()
+	}
 }
 
 // Layout draws the page UI components into the provided layout context
@@ -208,11 +274,14 @@ func (pg *WalletSettingsPage) generalSection() layout.Widget {
 				}
 				return D{}
 			}),
+			layout.Rigid(func(gtx C) D {
+				return pg.subSection(gtx, values.String(values.StrOpenOnlyWhenSelected), pg.openOnDemand.Layout)
+			}),
 			layout.Rigid(func(gtx C) D {
 				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
 					layout.Rigid(pg.subSectionSwitch(values.String(values.StrConnectToSpecificPeer), pg.connectToPeer)),
 					layout.Rigid(func(gtx C) D {
-						if pg.WL.SelectedWallet.Wallet.ReadStringConfigValueForKey(sharedW.SpvPersistentPeerAddressesConfigKey, "") == "" && pg.isPrivacyModeOn() {
+						if pg.peerAddr == "" && pg.isPrivacyModeOn() {
 							return D{}
 						}
 
@@ -225,6 +294,27 @@ func (pg *WalletSettingsPage) generalSection() layout.Widget {
 					}),
 				)
 			}),
+			layout.Rigid(func(gtx C) D {
+				return pg.clickableRow(gtx, clickableRowData{
+					title:     values.String(values.StrPeers),
+					clickable: pg.peers,
+					labelText: pg.peersLabel(),
+				})
+			}),
+			layout.Rigid(func(gtx C) D {
+				label := values.String(values.StrSPVSync)
+				if cfg := pg.wallet.RPCSyncConfig(); cfg != nil {
+					label = cfg.Host
+					if cfg.Backend == sharedW.ElectrumBackend && len(cfg.ElectrumServers) > 0 {
+						label = cfg.ElectrumServers[0].Address
+					}
+				}
+				return pg.clickableRow(gtx, clickableRowData{
+					title:     values.String(values.StrFullNode),
+					clickable: pg.fullNode,
+					labelText: label,
+				})
+			}),
 		)
 
 	}
@@ -235,9 +325,25 @@ func (pg *WalletSettingsPage) generalSection() layout.Widget {
 
 func (pg *WalletSettingsPage) account() layout.Widget {
 	dim := func(gtx C) D {
-		return pg.accountsList.Layout(gtx, len(pg.accounts), func(gtx C, a int) D {
-			return pg.subSection(gtx, pg.accounts[a].Name, pg.Theme.Icons.ChevronRight.Layout24dp)
-		})
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx C) D {
+				// Only wallets with funds in several key scopes can
+				// display the accounts of another scope.
+				if len(pg.keyScopePurposes) < 2 {
+					return D{}
+				}
+				return pg.clickableRow(gtx, clickableRowData{
+					title:     values.String(values.StrAccountScope),
+					clickable: pg.accountScope,
+					labelText: values.StringF(values.StrAccountScopeBIP, pg.keyScopePurposes[0]),
+				})
+			}),
+			layout.Rigid(func(gtx C) D {
+				return pg.accountsList.Layout(gtx, len(pg.accounts), func(gtx C, a int) D {
+					return pg.subSection(gtx, pg.accounts[a].Name, pg.Theme.Icons.ChevronRight.Layout24dp)
+				})
+			}),
+		)
 	}
 	return func(gtx C) D {
 		return pg.pageSections(gtx, values.String(values.StrAccount), dim)
@@ -248,12 +354,29 @@ func (pg *WalletSettingsPage) debug() layout.Widget {
 	dim := func(gtx C) D {
 		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
 			layout.Rigid(pg.sectionContent(pg.rescan, values.String(values.StrRescanBlockchain))),
+			layout.Rigid(func(gtx C) D {
+				return pg.clickableRow(gtx, clickableRowData{
+					title:     values.String(values.StrWalletBirthday),
+					clickable: pg.birthday,
+					labelText: pg.wallet.GetBirthday().Format(birthdayLayout),
+				})
+			}),
 			layout.Rigid(func(gtx C) D {
 				if pg.wallet.GetAssetType() == libutils.DCRWalletAsset {
 					return pg.sectionDimension(gtx, pg.setGapLimit, values.String(values.StrSetGapLimit))
 				}
 				return D{}
 			}),
+			layout.Rigid(func(gtx C) D {
+				if pg.dbDriverLabel == "" {
+					return D{}
+				}
+				return pg.clickableRow(gtx, clickableRowData{
+					title:     values.String(values.StrDatabaseDriver),
+					clickable: pg.dbDriver,
+					labelText: pg.dbDriverLabel,
+				})
+			}),
 			layout.Rigid(pg.sectionContent(pg.checklog, values.String(values.StrCheckWalletLog))),
 			layout.Rigid(pg.sectionContent(pg.checkStats, values.String(values.StrCheckStatistics))),
 		)
@@ -269,6 +392,14 @@ func (pg *WalletSettingsPage) securityTools() layout.Widget {
 			layout.Rigid(pg.sectionContent(pg.verifyMessage, values.String(values.StrVerifyMessage))),
 			layout.Rigid(pg.sectionContent(pg.validateAddr, values.String(values.StrValidateMsg))),
 			layout.Rigid(pg.sectionContent(pg.signMessage, values.String(values.StrSignMessage))),
+			layout.Rigid(pg.sectionContent(pg.addressExplorer, values.String(values.StrAddressExplorer))),
+			layout.Rigid(pg.sectionContent(pg.importKey, values.String(values.StrImportKey))),
+			layout.Rigid(func(gtx C) D {
+				if !pg.wallet.IsMultisigWallet() {
+					return D{}
+				}
+				return pg.sectionDimension(gtx, pg.multisig, values.String(values.StrMultisig))
+			}),
 		)
 	}
 	return func(gtx C) D {
@@ -522,6 +653,11 @@ func (pg *WalletSettingsPage) showSPVPeerDialog() {
 				tim.SetLoading(false)
 				return false
 			}
+			if libutils.IsOnionHost(ipAddress) && !pg.WL.AssetsManager.IsProxied() {
+				tim.SetError(values.String(values.StrOnionPeerRequiresProxy))
+				tim.SetLoading(false)
+				return false
+			}
 			if ipAddress != "" {
 				pg.WL.SelectedWallet.Wallet.SetSpecificPeer(ipAddress)
 				pg.loadPeerAddress()
@@ -532,8 +668,7 @@ func (pg *WalletSettingsPage) showSPVPeerDialog() {
 		SetPositiveButtonText(values.String(values.StrConfirm)).
 		SetNegativeButtonText(values.String(values.StrCancel)).
 		SetNegativeButtonCallback(func() {
-			pg.peerAddr = pg.WL.SelectedWallet.Wallet.ReadStringConfigValueForKey(sharedW.SpvPersistentPeerAddressesConfigKey, "")
-			pg.connectToPeer.SetChecked(pg.peerAddr != "")
+			pg.loadPeerAddress()
 		})
 	pg.ParentWindow().ShowModal(textModal)
 }
@@ -553,6 +688,103 @@ func (pg *WalletSettingsPage) clickableRow(gtx C, row clickableRowData) D {
 	})
 }
 
+// accountScopeModal lets the user pick the key scope whose accounts are
+// displayed.
+func (pg *WalletSettingsPage) accountScopeModal() {
+	selector, ok := pg.wallet.(keyScopeSelector)
+	if !ok || len(pg.keyScopePurposes) == 0 {
+		return
+	}
+
+	items := make([]preference.ItemPreference, 0, len(pg.keyScopePurposes))
+	for _, purpose := range pg.keyScopePurposes {
+		items = append(items, preference.ItemPreference{
+			Key:   strconv.FormatUint(uint64(purpose), 10),
+			Value: values.StringF(values.StrAccountScopeBIP, purpose),
+		})
+	}
+
+	current := strconv.FormatUint(uint64(pg.keyScopePurposes[0]), 10)
+	scopeModal := preference.NewListPreference(pg.Load, "", current, items).
+		Title(values.StrAccountScope).
+		IsWallet(true).
+		UpdateValues(func(val string) {
+			purpose, err := strconv.ParseUint(val, 10, 32)
+			if err == nil {
+				err = selector.SetKeyScopePurpose(uint32(purpose))
+			}
+			if err != nil {
+				errModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+				pg.ParentWindow().ShowModal(errModal)
+				return
+			}
+			pg.loadWalletAccount()
+		})
+	pg.ParentWindow().ShowModal(scopeModal)
+}
+
+// dbDriverModal offers to confirm or revert the last database driver
+// migration if one is pending, otherwise to switch to the other driver.
+func (pg *WalletSettingsPage) dbDriverModal() {
+	migrator, ok := pg.wallet.(dbDriverMigrator)
+	if !ok {
+		return
+	}
+
+	if migrator.HasPendingDatabaseDriverMigration() {
+		confirmModal := modal.NewCustomModal(pg.Load).
+			Title(values.String(values.StrConfirmDatabaseDriver)).
+			Body(values.StringF(values.StrConfirmDatabaseDriverInfo, pg.dbDriverLabel)).
+			SetNegativeButtonText(values.String(values.StrRevert)).
+			SetNegativeButtonCallback(func() {
+				pg.runDBDriverTask(migrator.RevertDatabaseDriverMigration, "")
+			}).
+			PositiveButtonStyle(pg.Theme.Color.Primary, pg.Theme.Color.Surface).
+			SetPositiveButtonText(values.String(values.StrKeep)).
+			SetPositiveButtonCallback(func(_ bool, _ *modal.InfoModal) bool {
+				pg.runDBDriverTask(migrator.ConfirmDatabaseDriverMigration, "")
+				return true
+			})
+		pg.ParentWindow().ShowModal(confirmModal)
+		return
+	}
+
+	targetDriver := dcr.BadgerDriver
+	if pg.dbDriverLabel == dcr.BadgerDriver {
+		targetDriver = dcr.BdbDriver
+	}
+
+	switchModal := modal.NewCustomModal(pg.Load).
+		Title(values.String(values.StrSwitchDatabaseDriver)).
+		Body(values.StringF(values.StrSwitchDatabaseDriverInfo, targetDriver)).
+		SetNegativeButtonText(values.String(values.StrCancel)).
+		PositiveButtonStyle(pg.Theme.Color.Primary, pg.Theme.Color.Surface).
+		SetPositiveButtonText(values.String(values.StrSwitch)).
+		SetPositiveButtonCallback(func(_ bool, _ *modal.InfoModal) bool {
+			pg.runDBDriverTask(func() error {
+				return migrator.MigrateDatabaseDriver(targetDriver)
+			}, values.StringF(values.StrDatabaseDriverSwitched, targetDriver))
+			return true
+		})
+	pg.ParentWindow().ShowModal(switchModal)
+}
+
+// runDBDriverTask runs a database driver task in the background, then shows
+// successMsg or the task's error.
+func (pg *WalletSettingsPage) runDBDriverTask(task func() error, successMsg string) {
+	go func() {
+		if err := task(); err != nil {
+			errModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+			pg.ParentWindow().ShowModal(errModal)
+		} else if successMsg != "" {
+			info := modal.NewSuccessModal(pg.Load, successMsg, modal.DefaultClickFunc())
+			pg.ParentWindow().ShowModal(info)
+		}
+		pg.loadDBDriver()
+		pg.ParentWindow().Reload()
+	}()
+}
+
 func (pg *WalletSettingsPage) showWarningModalDialog(title, msg string) {
 	warningModal := modal.NewCustomModal(pg.Load).
 		Title(title).
@@ -587,29 +819,11 @@ func (pg *WalletSettingsPage) HandleUserInteractions() {
 	}
 
 	if pg.rescan.Clicked() {
-		go func() {
-			info := modal.NewCustomModal(pg.Load).
-				Title(values.String(values.StrRescanBlockchain)).
-				Body(values.String(values.StrRescanInfo)).
-				SetNegativeButtonText(values.String(values.StrCancel)).
-				PositiveButtonStyle(pg.Theme.Color.Primary, pg.Theme.Color.Surface).
-				SetPositiveButtonText(values.String(values.StrRescan)).
-				SetPositiveButtonCallback(func(_ bool, im *modal.InfoModal) bool {
-					err := pg.WL.SelectedWallet.Wallet.RescanBlocks()
-					if err != nil {
-						errorModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
-						pg.ParentWindow().ShowModal(errorModal)
-						im.Dismiss()
-
-						return false
-					}
-
-					im.Dismiss()
-					return true
-				})
+		pg.rescanFromDateModal()
+	}
 
-			pg.ParentWindow().ShowModal(info)
-		}()
+	if pg.birthday.Clicked() {
+		pg.birthdayModal()
 	}
 
 	for pg.setGapLimit.Clicked() {
@@ -639,6 +853,14 @@ func (pg *WalletSettingsPage) HandleUserInteractions() {
 		pg.WL.SelectedWallet.Wallet.SaveUserConfigValue(sharedW.SpendUnconfirmedConfigKey, pg.spendUnconfirmed.IsChecked())
 	}
 
+	if pg.openOnDemand.Changed() {
+		err := pg.WL.AssetsManager.SetOpenOnDemand(pg.wallet.GetWalletID(), pg.openOnDemand.IsChecked())
+		if err != nil {
+			errorModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+			pg.ParentWindow().ShowModal(errorModal)
+		}
+	}
+
 	if pg.spendUnmixedFunds.Changed() {
 		if pg.spendUnmixedFunds.IsChecked() {
 			textModal := modal.NewTextInputModal(pg.Load).
@@ -694,10 +916,38 @@ func (pg *WalletSettingsPage) HandleUserInteractions() {
 		pg.ParentNavigator().Display(security.NewSignMessagePage(pg.Load))
 	}
 
+	if pg.addressExplorer.Clicked() {
+		pg.ParentNavigator().Display(s.NewAddressExplorerPage(pg.Load))
+	}
+
+	if pg.importKey.Clicked() {
+		pg.ParentNavigator().Display(s.NewImportKeyPage(pg.Load))
+	}
+
+	if pg.multisig.Clicked() {
+		pg.ParentNavigator().Display(s.NewMultisigPage(pg.Load, pg.wallet))
+	}
+
+	if pg.peers.Clicked() {
+		pg.ParentNavigator().Display(s.NewPeersPage(pg.Load))
+	}
+
+	if pg.fullNode.Clicked() {
+		pg.ParentNavigator().Display(s.NewFullNodePage(pg.Load))
+	}
+
 	if pg.checklog.Clicked() {
 		pg.ParentNavigator().Display(s.NewLogPage(pg.Load, pg.wallet.LogFile(), values.String(values.StrWalletLog)))
 	}
 
+	if pg.dbDriver.Clicked() {
+		pg.dbDriverModal()
+	}
+
+	if pg.accountScope.Clicked() {
+		pg.accountScopeModal()
+	}
+
 	if pg.checkStats.Clicked() {
 		pg.ParentNavigator().Display(s.NewStatPage(pg.Load))
 	}
@@ -781,6 +1031,96 @@ func (pg *WalletSettingsPage) gapLimitModal() {
 	pg.ParentWindow().ShowModal(textModal)
 }
 
+// rescanFromDateModal asks for the date the rescan starts from, the wallet
+// birthday by default, and shows the blocks that will be rescanned before the
+// rescan starts.
+func (pg *WalletSettingsPage) rescanFromDateModal() {
+	textModal := modal.NewTextInputModal(pg.Load).
+		Hint(values.String(values.StrRescanFromDate)).
+		SetText(pg.wallet.GetBirthday().Format(birthdayLayout)).
+		PositiveButtonStyle(pg.Load.Theme.Color.Primary, pg.Load.Theme.Color.InvText).
+		SetPositiveButtonCallback(func(date string, tm *modal.TextInputModal) bool {
+			from, err := time.Parse(birthdayLayout, strings.TrimSpace(date))
+			if err != nil || from.After(time.Now()) {
+				tm.SetError(values.String(values.StrInvalidBirthday))
+				tm.SetLoading(false)
+				return false
+			}
+
+			startHeight, err := pg.wallet.BlockHeightAtTime(from)
+			if err != nil {
+				tm.SetError(err.Error())
+				tm.SetLoading(false)
+				return false
+			}
+			tm.Dismiss()
+
+			pg.confirmRescanModal(startHeight)
+			return true
+		})
+	textModal.Title(values.String(values.StrRescanBlockchain)).
+		SetPositiveButtonText(values.String(values.StrNext)).
+		SetNegativeButtonText(values.String(values.StrCancel))
+	pg.ParentWindow().ShowModal(textModal)
+}
+
+func (pg *WalletSettingsPage) confirmRescanModal(startHeight int32) {
+	rescanRange := values.StringF(values.StrRescanRange, startHeight, pg.wallet.GetBestBlockHeight())
+	info := modal.NewCustomModal(pg.Load).
+		Title(values.String(values.StrRescanBlockchain)).
+		Body(rescanRange+" "+values.String(values.StrRescanInfo)).
+		SetNegativeButtonText(values.String(values.StrCancel)).
+		PositiveButtonStyle(pg.Theme.Color.Primary, pg.Theme.Color.Surface).
+		SetPositiveButtonText(values.String(values.StrRescan)).
+		SetPositiveButtonCallback(func(_ bool, im *modal.InfoModal) bool {
+			err := pg.wallet.RescanBlocksFromHeight(startHeight)
+			if err != nil {
+				errorModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+				pg.ParentWindow().ShowModal(errorModal)
+				im.Dismiss()
+
+				return false
+			}
+
+			im.Dismiss()
+			return true
+		})
+
+	pg.ParentWindow().ShowModal(info)
+}
+
+// birthdayModal lets the user set the wallet birthday, the date rescans
+// start from by default.
+func (pg *WalletSettingsPage) birthdayModal() {
+	textModal := modal.NewTextInputModal(pg.Load).
+		Hint(values.String(values.StrBirthdayHint)).
+		SetText(pg.wallet.GetBirthday().Format(birthdayLayout)).
+		PositiveButtonStyle(pg.Load.Theme.Color.Primary, pg.Load.Theme.Color.InvText).
+		SetPositiveButtonCallback(func(date string, tm *modal.TextInputModal) bool {
+			birthday, err := time.Parse(birthdayLayout, strings.TrimSpace(date))
+			if err != nil {
+				tm.SetError(values.String(values.StrInvalidBirthday))
+				tm.SetLoading(false)
+				return false
+			}
+
+			if err = pg.wallet.SetBirthday(birthday); err != nil {
+				tm.SetError(err.Error())
+				tm.SetLoading(false)
+				return false
+			}
+			tm.Dismiss()
+
+			info := modal.NewSuccessModal(pg.Load, values.String(values.StrBirthdayUpdated), modal.DefaultClickFunc())
+			pg.ParentWindow().ShowModal(info)
+			return true
+		})
+	textModal.Title(values.String(values.StrWalletBirthday)).
+		SetPositiveButtonText(values.String(values.StrSave)).
+		SetNegativeButtonText(values.String(values.StrCancel))
+	pg.ParentWindow().ShowModal(textModal)
+}
+
 // OnNavigatedFrom is called when the page is about to be removed from
 // the displayed window. This method should ideally be used to disable
 // features that are irrelevant when the page is NOT displayed.
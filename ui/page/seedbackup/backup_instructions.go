@@ -33,10 +33,11 @@ type BackupInstructionsPage struct {
 
 	wallet sharedW.Asset
 
-	backButton  cryptomaterial.IconButton
-	viewSeedBtn cryptomaterial.Button
-	checkBoxes  []cryptomaterial.CheckBoxStyle
-	infoList    *layout.List
+	backButton      cryptomaterial.IconButton
+	viewSeedBtn     cryptomaterial.Button
+	shamirBackupBtn cryptomaterial.Button
+	checkBoxes      []cryptomaterial.CheckBoxStyle
+	infoList        *layout.List
 
 	redirectCallback Redirectfunc
 }
@@ -47,12 +48,14 @@ func NewBackupInstructionsPage(l *load.Load, wallet sharedW.Asset, redirect Redi
 		GenericPageModal: app.NewGenericPageModal(BackupInstructionsPageID),
 		wallet:           wallet,
 
-		viewSeedBtn: l.Theme.Button(values.String(values.StrViewSeedPhrase)),
+		viewSeedBtn:     l.Theme.Button(values.String(values.StrViewSeedPhrase)),
+		shamirBackupBtn: l.Theme.OutlineButton(values.String(values.StrShamirBackup)),
 
 		redirectCallback: redirect,
 	}
 
 	bi.viewSeedBtn.Font.Weight = font.Medium
+	bi.shamirBackupBtn.Font.Weight = font.Medium
 
 	bi.backButton, _ = components.SubpageHeaderButtons(l)
 	bi.backButton.Icon = l.Theme.Icons.ContentClear
@@ -93,6 +96,12 @@ func (pg *BackupInstructionsPage) HandleUserInteractions() {
 			pg.ParentNavigator().Display(NewSaveSeedPage(pg.Load, pg.wallet, pg.redirectCallback))
 		}
 	}
+
+	for pg.shamirBackupBtn.Clicked() {
+		if pg.verifyCheckBoxes() {
+			pg.ParentNavigator().Display(NewShamirBackupPage(pg.Load, pg.wallet, pg.redirectCallback))
+		}
+	}
 }
 
 func promptToExit(load *load.Load, window app.WindowNavigator, redirect Redirectfunc) {
@@ -129,13 +138,23 @@ func (pg *BackupInstructionsPage) Layout(gtx layout.Context) layout.Dimensions {
 			promptToExit(pg.Load, pg.ParentWindow(), pg.redirectCallback)
 		},
 		Body: func(gtx C) D {
-			return pg.infoList.Layout(gtx, len(pg.checkBoxes), func(gtx C, i int) D {
+			return pg.infoList.Layout(gtx, len(pg.checkBoxes)+1, func(gtx C, i int) D {
+				if i == len(pg.checkBoxes) {
+					// The shamir backup is an alternative to writing down
+					// the seed phrase. The shares don't hold the BIP39
+					// passphrase of the seed.
+					if pg.wallet.HasSeedPassphrase() {
+						return D{}
+					}
+					return pg.shamirBackupBtn.Layout(gtx)
+				}
 				return layout.Inset{Bottom: values.MarginPadding20}.Layout(gtx, pg.checkBoxes[i].Layout)
 			})
 		},
 	}
 
 	pg.viewSeedBtn.SetEnabled(pg.verifyCheckBoxes())
+	pg.shamirBackupBtn.SetEnabled(pg.verifyCheckBoxes())
 
 	layout := func(gtx C) D {
 		return sp.Layout(pg.ParentWindow(), gtx)
@@ -0,0 +1,305 @@
+package seedbackup
+
+import (
+	"strconv"
+	"strings"
+
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/widget"
+
+	"github.com/crypto-power/cryptopower/app"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/ui/cryptomaterial"
+	"github.com/crypto-power/cryptopower/ui/load"
+	"github.com/crypto-power/cryptopower/ui/modal"
+	"github.com/crypto-power/cryptopower/ui/page/components"
+	"github.com/crypto-power/cryptopower/ui/values"
+)
+
+const ShamirBackupPageID = "shamir_backup"
+
+// Stages of the shamir backup. Every share is recorded then verified before
+// the next share is displayed.
+const (
+	shamirSetupStage = iota
+	shamirRecordStage
+	shamirVerifyStage
+)
+
+// ShamirBackupPage splits the wallet seed into SLIP-39 shares and walks the
+// user through recording and verifying each of them.
+type ShamirBackupPage struct {
+	*load.Load
+	// GenericPageModal defines methods such as ID() and OnAttachedToNavigator()
+	// that helps this Page satisfy the app.Page interface. It also defines
+	// helper methods for accessing the PageNavigator that displayed this page
+	// and the root WindowNavigator.
+	*app.GenericPageModal
+
+	wallet sharedW.Asset
+
+	backButton      cryptomaterial.IconButton
+	actionButton    cryptomaterial.Button
+	thresholdEditor cryptomaterial.Editor
+	countEditor     cryptomaterial.Editor
+	shareEditor     cryptomaterial.Editor
+	wordList        *widget.List
+
+	seed     string
+	password string
+	shares   []string
+	stage    int
+	current  int
+
+	redirectCallback Redirectfunc
+}
+
+func NewShamirBackupPage(l *load.Load, wallet sharedW.Asset, redirect Redirectfunc) *ShamirBackupPage {
+	pg := &ShamirBackupPage{
+		Load:             l,
+		GenericPageModal: app.NewGenericPageModal(ShamirBackupPageID),
+		wallet:           wallet,
+		actionButton:     l.Theme.Button(values.String(values.StrCreateShares)),
+		wordList: &widget.List{
+			List: layout.List{Axis: layout.Vertical},
+		},
+
+		redirectCallback: redirect,
+	}
+
+	pg.backButton, _ = components.SubpageHeaderButtons(l)
+	pg.backButton.Icon = l.Theme.Icons.ContentClear
+
+	pg.actionButton.Font.Weight = font.Medium
+
+	pg.thresholdEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrShareThreshold))
+	pg.thresholdEditor.Editor.SingleLine = true
+	pg.thresholdEditor.Editor.SetText("2")
+
+	pg.countEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrShareCount))
+	pg.countEditor.Editor.SingleLine = true
+	pg.countEditor.Editor.SetText("3")
+
+	pg.shareEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrEnterWalletSeed))
+	pg.shareEditor.Editor.SingleLine = false
+
+	return pg
+}
+
+// OnNavigatedTo is called when the page is about to be displayed and
+// may be used to initialize page features that are only relevant when
+// the page is displayed.
+// Part of the load.Page interface.
+func (pg *ShamirBackupPage) OnNavigatedTo() {
+	if pg.seed != "" {
+		return
+	}
+
+	passwordModal := modal.NewCreatePasswordModal(pg.Load).
+		EnableName(false).
+		EnableConfirmPassword(false).
+		Title(values.String(values.StrConfirmShowSeed)).
+		SetPositiveButtonCallback(func(_, password string, m *modal.CreatePasswordModal) bool {
+			seed, err := pg.wallet.DecryptSeed(password)
+			if err != nil {
+				m.SetLoading(false)
+				m.SetError(err.Error())
+				return false
+			}
+
+			m.Dismiss()
+			pg.seed = seed
+			pg.password = password
+			return true
+		}).
+		SetNegativeButtonCallback(func() {
+			pg.redirectCallback(pg.Load, pg.ParentWindow())
+		}).
+		SetCancelable(false)
+	pg.ParentWindow().ShowModal(passwordModal)
+}
+
+// HandleUserInteractions is called just before Layout() to determine
+// if any user interaction recently occurred on the page and may be
+// used to update the page's UI components shortly before they are
+// displayed.
+// Part of the load.Page interface.
+func (pg *ShamirBackupPage) HandleUserInteractions() {
+	for pg.actionButton.Clicked() {
+		switch pg.stage {
+		case shamirSetupStage:
+			pg.createShares()
+		case shamirRecordStage:
+			pg.shareEditor.Editor.SetText("")
+			pg.shareEditor.SetError("")
+			pg.setStage(shamirVerifyStage)
+		case shamirVerifyStage:
+			pg.verifyShare()
+		}
+	}
+}
+
+func (pg *ShamirBackupPage) setStage(stage int) {
+	pg.stage = stage
+	switch stage {
+	case shamirRecordStage:
+		pg.actionButton.Text = values.StringF(values.StrWroteShare, pg.current+1)
+	case shamirVerifyStage:
+		pg.actionButton.Text = values.String(values.StrVerify)
+	}
+}
+
+func (pg *ShamirBackupPage) createShares() {
+	pg.countEditor.SetError("")
+	threshold, err1 := strconv.Atoi(strings.TrimSpace(pg.thresholdEditor.Editor.Text()))
+	count, err2 := strconv.Atoi(strings.TrimSpace(pg.countEditor.Editor.Text()))
+	if err1 != nil || err2 != nil {
+		pg.countEditor.SetError(values.String(values.StrInvalidShares))
+		return
+	}
+
+	shares, err := pg.wallet.SplitSeed(pg.seed, threshold, count)
+	if err != nil {
+		pg.countEditor.SetError(values.String(values.StrInvalidShares))
+		return
+	}
+
+	pg.shares = shares
+	pg.current = 0
+	pg.setStage(shamirRecordStage)
+}
+
+func (pg *ShamirBackupPage) verifyShare() {
+	entered := strings.Join(strings.Fields(strings.ToLower(pg.shareEditor.Editor.Text())), " ")
+	if entered != pg.shares[pg.current] {
+		pg.shareEditor.SetError(values.String(values.StrShareMismatch))
+		return
+	}
+
+	if pg.current < len(pg.shares)-1 {
+		pg.current++
+		pg.setStage(shamirRecordStage)
+		return
+	}
+
+	// All the shares are recorded, the seed no longer needs to be stored.
+	_, err := pg.wallet.VerifySeedForWallet(pg.seed, pg.password)
+	if err != nil {
+		errModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+		pg.ParentWindow().ShowModal(errModal)
+		return
+	}
+	pg.ParentNavigator().Display(NewBackupSuccessPage(pg.Load, pg.redirectCallback))
+}
+
+// OnNavigatedFrom is called when the page is about to be removed from
+// the displayed window. This method should ideally be used to disable
+// features that are irrelevant when the page is NOT displayed.
+// NOTE: The page may be re-displayed on the app's window, in which case
+// OnNavigatedTo() will be called again. This method should not destroy UI
+// components unless they'll be recreated in the OnNavigatedTo() method.
+// Part of the load.Page interface.
+func (pg *ShamirBackupPage) OnNavigatedFrom() {}
+
+// Layout draws the page UI components into the provided layout context
+// to be eventually drawn on screen.
+// Part of the load.Page interface.
+func (pg *ShamirBackupPage) Layout(gtx C) D {
+	subTitle := ""
+	if pg.stage != shamirSetupStage {
+		subTitle = values.StringF(values.StrShareOfShares, pg.current+1, len(pg.shares))
+	}
+
+	sp := components.SubPage{
+		Load:       pg.Load,
+		Title:      values.String(values.StrShamirBackup),
+		SubTitle:   subTitle,
+		BackButton: pg.backButton,
+		Back: func() {
+			promptToExit(pg.Load, pg.ParentWindow(), pg.redirectCallback)
+		},
+		Body: func(gtx C) D {
+			switch pg.stage {
+			case shamirRecordStage:
+				return pg.shareWordsLayout(gtx)
+			case shamirVerifyStage:
+				return pg.verifyLayout(gtx)
+			default:
+				return pg.setupLayout(gtx)
+			}
+		},
+	}
+
+	pg.actionButton.SetEnabled(pg.seed != "")
+
+	layout := func(gtx C) D {
+		return sp.Layout(pg.ParentWindow(), gtx)
+	}
+	isMobile := pg.Load.GetCurrentAppWidth() <= gtx.Dp(values.StartMobileView)
+	return container(gtx, isMobile, *pg.Theme, layout, "", pg.actionButton, true)
+}
+
+func (pg *ShamirBackupPage) setupLayout(gtx C) D {
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D {
+			label := pg.Theme.Label(values.TextSize16, values.String(values.StrShamirBackupNote))
+			label.Color = pg.Theme.Color.GrayText1
+			return layout.Inset{Bottom: values.MarginPadding16}.Layout(gtx, label.Layout)
+		}),
+		layout.Rigid(pg.thresholdEditor.Layout),
+		layout.Rigid(func(gtx C) D {
+			return layout.Inset{Top: values.MarginPadding16}.Layout(gtx, pg.countEditor.Layout)
+		}),
+	)
+}
+
+func (pg *ShamirBackupPage) shareWordsLayout(gtx C) D {
+	words := strings.Fields(pg.shares[pg.current])
+	columns := 3
+	if pg.Load.GetCurrentAppWidth() <= gtx.Dp(values.StartMobileView) {
+		columns = 2
+	}
+	rows := (len(words) + columns - 1) / columns
+
+	return cryptomaterial.LinearLayout{
+		Width:       cryptomaterial.MatchParent,
+		Height:      cryptomaterial.WrapContent,
+		Orientation: layout.Vertical,
+		Background:  pg.Theme.Color.Surface,
+		Border:      cryptomaterial.Border{Radius: cryptomaterial.Radius(8)},
+		// bottom margin accounts for action button's height + components.UniformPadding bottom margin 24dp + 16dp
+		Margin:  layout.Inset{Top: values.MarginPadding16, Bottom: values.MarginPadding120},
+		Padding: layout.Inset{Top: values.MarginPadding16, Right: values.MarginPadding16, Bottom: values.MarginPadding8, Left: values.MarginPadding16},
+	}.Layout(gtx,
+		layout.Rigid(func(gtx C) D {
+			return pg.Theme.List(pg.wordList).Layout(gtx, rows, func(gtx C, row int) D {
+				itemWidth := gtx.Constraints.Max.X / columns
+				items := make([]layout.FlexChild, 0, columns)
+				for column := 0; column < columns; column++ {
+					index := column*rows + row
+					if index >= len(words) {
+						break
+					}
+					items = append(items, layout.Rigid(func(gtx C) D {
+						return seedItem(pg.Theme, gtx, itemWidth, index+1, words[index])
+					}))
+				}
+				return layout.Inset{Top: values.MarginPadding8}.Layout(gtx, func(gtx C) D {
+					return layout.Flex{}.Layout(gtx, items...)
+				})
+			})
+		}),
+	)
+}
+
+func (pg *ShamirBackupPage) verifyLayout(gtx C) D {
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D {
+			label := pg.Theme.Label(values.TextSize16, values.StringF(values.StrEnterShareToVerify, pg.current+1))
+			label.Color = pg.Theme.Color.GrayText1
+			return layout.Inset{Bottom: values.MarginPadding16}.Layout(gtx, label.Layout)
+		}),
+		layout.Rigid(pg.shareEditor.Layout),
+	)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/crypto-power/cryptopower/ui/load"
 	"github.com/crypto-power/cryptopower/ui/modal"
 	"github.com/crypto-power/cryptopower/ui/page/components"
+	"github.com/crypto-power/cryptopower/ui/page/settings"
 	"github.com/crypto-power/cryptopower/ui/utils"
 	"github.com/crypto-power/cryptopower/ui/values"
 )
@@ -89,6 +90,12 @@ type authoredTxData struct {
 	sendAmountUSD       string
 }
 
+// multisigPSBTCreator is implemented by the multisig wallets which spend
+// through PSBTs signed by the cosigners.
+type multisigPSBTCreator interface {
+	CreateMultisigPSBT(toAddress string, amount int64, sendMax bool) (string, error)
+}
+
 type selectedUTXOsInfo struct {
 	sourceAccount    *sharedW.Account
 	selectedUTXOs    []*sharedW.UnspentOutput
@@ -371,6 +378,14 @@ func (pg *Page) constructTx() {
 		return
 	}
 
+	// Multisig wallets spend through a PSBT that is created once the
+	// destination and amount are confirmed.
+	if pg.selectedWallet.IsMultisigWallet() {
+		pg.destinationAddress = destinationAddress
+		pg.sendAmount = pg.selectedWallet.ToAmount(amountAtom).String()
+		return
+	}
+
 	sourceAccount := pg.sourceAccountSelector.SelectedAccount()
 	selectedUTXOs := make([]*sharedW.UnspentOutput, 0)
 	if sourceAccount == pg.selectedUTXOs.sourceAccount {
@@ -529,7 +544,9 @@ func (pg *Page) HandleUserInteractions() {
 	}
 
 	if pg.nextButton.Clicked() {
-		if pg.selectedWallet.IsUnsignedTxExist() {
+		if pg.selectedWallet.IsMultisigWallet() {
+			pg.createMultisigPSBT()
+		} else if pg.selectedWallet.IsUnsignedTxExist() {
 			pg.confirmTxModal = newSendConfirmModal(pg.Load, pg.authoredTxData, *pg.selectedWallet)
 			pg.confirmTxModal.exchangeRateSet = pg.exchangeRate != -1 && pg.usdExchangeSet
 			pg.confirmTxModal.txLabel = pg.txLabelInputEditor.Editor.Text()
@@ -585,6 +602,38 @@ func (pg *Page) HandleUserInteractions() {
 	}
 }
 
+// createMultisigPSBT creates the PSBT that sends the entered amount from the
+// multisig and opens the multisig page for it to be signed and shared with
+// the cosigners.
+func (pg *Page) createMultisigPSBT() {
+	wallet, ok := pg.selectedWallet.Asset.(multisigPSBTCreator)
+	if !ok {
+		return
+	}
+
+	amountAtom, sendMax, err := pg.amount.validAmount()
+	if err != nil {
+		pg.amountValidationError(err.Error())
+		return
+	}
+
+	b64PSBT, err := wallet.CreateMultisigPSBT(pg.destinationAddress, amountAtom, sendMax)
+	if err != nil {
+		pg.amountValidationError(err.Error())
+		return
+	}
+
+	multisigPage := settings.NewMultisigPage(pg.Load, pg.selectedWallet.Asset)
+	multisigPage.ShowPSBT(b64PSBT)
+
+	pg.resetFields()
+	pg.clearEstimates()
+	if pg.isModalLayout {
+		pg.modalLayout.Dismiss()
+	}
+	pg.ParentNavigator().Display(multisigPage)
+}
+
 // Handle is like HandleUserInteractions but Handle is called if this page is
 // displayed as a modal while HandleUserInteractions is called if this page
 // is displayed as a full page. Either Handle or HandleUserInteractions will
@@ -47,6 +47,7 @@ type AcctDetailsPage struct {
 	lockedByTickets  string
 	votingAuthority  string
 	immatureStakeGen string
+	watchOnly        string
 	hdPath           string
 	keys             string
 	extendedKey      string
@@ -97,6 +98,9 @@ func (pg *AcctDetailsPage) OnNavigatedTo() {
 	pg.lockedByTickets = balance.LockedByTickets.String()
 	pg.votingAuthority = balance.VotingAuthority.String()
 	pg.immatureStakeGen = balance.ImmatureStakeGeneration.String()
+	if balance.WatchOnly != nil && balance.WatchOnly.ToInt() > 0 {
+		pg.watchOnly = balance.WatchOnly.String()
+	}
 
 	pg.hdPath = pg.WL.DCRHDPrefix() + strconv.Itoa(int(pg.account.Number)) + "'"
 
@@ -236,6 +240,12 @@ func (pg *AcctDetailsPage) accountBalanceLayout(gtx C) D {
 			layout.Rigid(func(gtx C) D {
 				return pg.acctBalLayout(gtx, values.String(values.StrLabelSpendable), pg.spendable, false)
 			}),
+			layout.Rigid(func(gtx C) D {
+				if pg.watchOnly == "" {
+					return D{}
+				}
+				return pg.acctBalLayout(gtx, values.String(values.StrWatchOnly), pg.watchOnly, false)
+			}),
 			layout.Rigid(func(gtx C) D {
 				if pg.stakingBalance == 0 {
 					return D{}
@@ -0,0 +1,377 @@
+package settings
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"gioui.org/font"
+	"gioui.org/io/clipboard"
+	"gioui.org/layout"
+	"gioui.org/widget"
+
+	"github.com/crypto-power/cryptopower/app"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/ui/cryptomaterial"
+	"github.com/crypto-power/cryptopower/ui/load"
+	"github.com/crypto-power/cryptopower/ui/modal"
+	"github.com/crypto-power/cryptopower/ui/page/components"
+	"github.com/crypto-power/cryptopower/ui/values"
+)
+
+const AddressExplorerPageID = "AddressExplorer"
+
+type addressItem struct {
+	*sharedW.AddressUsage
+
+	copy  *cryptomaterial.Clickable
+	label *cryptomaterial.Clickable
+}
+
+// AddressExplorerPage lists the addresses derived by each account of the
+// selected wallet together with their usage.
+type AddressExplorerPage struct {
+	*load.Load
+	// GenericPageModal defines methods such as ID() and OnAttachedToNavigator()
+	// that helps this Page satisfy the app.Page interface. It also defines
+	// helper methods for accessing the PageNavigator that displayed this page
+	// and the root WindowNavigator.
+	*app.GenericPageModal
+
+	wallet   sharedW.Asset
+	accounts []*sharedW.Account
+
+	addresses []*sharedW.AddressUsage
+	items     []*addressItem
+	isLoading bool
+	copyText  string
+
+	accountDropDown *cryptomaterial.DropDown
+	filterDropDown  *cryptomaterial.DropDown
+	searchEditor    cryptomaterial.Editor
+	exportBtn       cryptomaterial.Button
+
+	scrollbarList *widget.List
+	backButton    cryptomaterial.IconButton
+}
+
+func NewAddressExplorerPage(l *load.Load) *AddressExplorerPage {
+	pg := &AddressExplorerPage{
+		Load:             l,
+		GenericPageModal: app.NewGenericPageModal(AddressExplorerPageID),
+		wallet:           l.WL.SelectedWallet.Wallet,
+		scrollbarList: &widget.List{
+			List: layout.List{Axis: layout.Vertical},
+		},
+	}
+
+	pg.backButton, _ = components.SubpageHeaderButtons(l)
+
+	pg.searchEditor = l.Theme.IconEditor(new(widget.Editor), values.String(values.StrSearch), l.Theme.Icons.SearchIcon, true)
+	pg.searchEditor.Editor.SingleLine, pg.searchEditor.Editor.Submit, pg.searchEditor.Bordered = true, true, false
+
+	pg.exportBtn = l.Theme.OutlineButton(values.String(values.StrExportCSV))
+	pg.exportBtn.Font.Weight = font.Medium
+
+	// The filter items must be in the same order as the sharedW.AddressFilter*
+	// constants as the selected index is used as the filter.
+	pg.filterDropDown = l.Theme.DropDown([]cryptomaterial.DropDownItem{
+		{Text: values.String(values.StrAll)},
+		{Text: values.String(values.StrUsed)},
+		{Text: values.String(values.StrUnused)},
+		{Text: values.String(values.StrFunded)},
+		{Text: values.String(values.StrExternal)},
+		{Text: values.String(values.StrInternal)},
+	}, values.AddressFilterDropdownGroup, 0)
+
+	return pg
+}
+
+// OnNavigatedTo is called when the page is about to be displayed and
+// may be used to initialize page features that are only relevant when
+// the page is displayed.
+// Part of the load.Page interface.
+func (pg *AddressExplorerPage) OnNavigatedTo() {
+	accounts, err := pg.wallet.GetAccountsRaw()
+	if err != nil {
+		log.Errorf("Error getting wallet accounts: %v", err)
+		return
+	}
+
+	pg.accounts = accounts.Accounts
+	items := make([]cryptomaterial.DropDownItem, 0, len(pg.accounts))
+	for _, acct := range pg.accounts {
+		items = append(items, cryptomaterial.DropDownItem{Text: acct.Name})
+	}
+	pg.accountDropDown = pg.Theme.DropDown(items, values.AddressAccountDropdownGroup, 0)
+
+	pg.loadAddresses()
+}
+
+// loadAddresses fetches the addresses of the selected account in the
+// background since deriving them could take a while for busy accounts.
+func (pg *AddressExplorerPage) loadAddresses() {
+	if len(pg.accounts) == 0 || pg.isLoading {
+		return
+	}
+
+	pg.isLoading = true
+	account := pg.accounts[pg.accountDropDown.SelectedIndex()].Number
+	go func() {
+		addresses, err := pg.wallet.AccountAddresses(account)
+		pg.isLoading = false
+		if err != nil {
+			log.Errorf("Error getting account addresses: %v", err)
+			errModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+			pg.ParentWindow().ShowModal(errModal)
+			return
+		}
+
+		pg.addresses = addresses
+		pg.filterAddresses()
+		pg.ParentWindow().Reload()
+	}()
+}
+
+func (pg *AddressExplorerPage) filterAddresses() {
+	filter := int32(pg.filterDropDown.SelectedIndex())
+	filtered := sharedW.FilterAddressUsage(pg.addresses, filter, pg.searchEditor.Editor.Text())
+
+	items := make([]*addressItem, 0, len(filtered))
+	for _, addr := range filtered {
+		items = append(items, &addressItem{
+			AddressUsage: addr,
+			copy:         pg.Theme.NewClickable(false),
+			label:        pg.Theme.NewClickable(false),
+		})
+	}
+	pg.items = items
+}
+
+// Layout draws the page UI components into the provided C
+// to be eventually drawn on screen.
+// Part of the load.Page interface.
+func (pg *AddressExplorerPage) Layout(gtx C) D {
+	if pg.copyText != "" {
+		clipboard.WriteOp{Text: pg.copyText}.Add(gtx.Ops)
+		pg.copyText = ""
+		pg.Toast.Notify(values.String(values.StrCopied))
+	}
+
+	body := func(gtx C) D {
+		sp := components.SubPage{
+			Load:       pg.Load,
+			Title:      values.String(values.StrAddressExplorer),
+			BackButton: pg.backButton,
+			Back: func() {
+				pg.ParentNavigator().CloseCurrentPage()
+			},
+			Body: pg.layoutContent,
+		}
+		return sp.Layout(pg.ParentWindow(), gtx)
+	}
+
+	if pg.Load.GetCurrentAppWidth() <= gtx.Dp(values.StartMobileView) {
+		return components.UniformMobile(gtx, false, false, body)
+	}
+	return body(gtx)
+}
+
+func (pg *AddressExplorerPage) layoutContent(gtx C) D {
+	return layout.Stack{}.Layout(gtx,
+		layout.Expanded(func(gtx C) D {
+			return layout.Inset{Top: values.MarginPadding60}.Layout(gtx, func(gtx C) D {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(func(gtx C) D {
+						return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+							layout.Flexed(1, pg.searchEditor.Layout),
+							layout.Rigid(func(gtx C) D {
+								return layout.Inset{Left: values.MarginPadding10}.Layout(gtx, pg.exportBtn.Layout)
+							}),
+						)
+					}),
+					layout.Flexed(1, func(gtx C) D {
+						return layout.Inset{Top: values.MarginPadding10}.Layout(gtx, pg.layoutAddresses)
+					}),
+				)
+			})
+		}),
+		layout.Expanded(func(gtx C) D {
+			if pg.accountDropDown == nil {
+				return D{}
+			}
+			return pg.accountDropDown.Layout(gtx, 0, false)
+		}),
+		layout.Expanded(func(gtx C) D {
+			return pg.filterDropDown.Layout(gtx, 0, true)
+		}),
+	)
+}
+
+func (pg *AddressExplorerPage) layoutAddresses(gtx C) D {
+	if pg.isLoading || len(pg.items) == 0 {
+		text := values.String(values.StrNoAddresses)
+		if pg.isLoading {
+			text = values.String(values.StrLoading)
+		}
+		lbl := pg.Theme.Body1(text)
+		lbl.Color = pg.Theme.Color.GrayText3
+		return layout.Center.Layout(gtx, lbl.Layout)
+	}
+
+	return pg.Theme.List(pg.scrollbarList).Layout(gtx, len(pg.items), func(gtx C, i int) D {
+		return layout.Inset{Bottom: values.MarginPadding4, Right: values.MarginPadding2}.Layout(gtx, func(gtx C) D {
+			card := pg.Theme.Card()
+			card.Color = pg.Theme.Color.Surface
+			return card.Layout(gtx, func(gtx C) D {
+				return layout.UniformInset(values.MarginPadding16).Layout(gtx, func(gtx C) D {
+					return pg.layoutAddressItem(gtx, pg.items[i])
+				})
+			})
+		})
+	})
+}
+
+func (pg *AddressExplorerPage) layoutAddressItem(gtx C, item *addressItem) D {
+	formatTime := func(timestamp int64) string {
+		if timestamp <= 0 {
+			return "-"
+		}
+		return time.Unix(timestamp, 0).Format("2006-01-02 15:04")
+	}
+
+	row := func(title, value string) layout.Widget {
+		return func(gtx C) D {
+			l := pg.Theme.Body2(title)
+			l.Color = pg.Theme.Color.GrayText2
+			r := pg.Theme.Body2(value)
+			return layout.Inset{Top: values.MarginPadding4}.Layout(gtx, func(gtx C) D {
+				return components.EndToEndRow(gtx, l.Layout, r.Layout)
+			})
+		}
+	}
+
+	hdPath := item.HDPath
+	if item.Imported {
+		hdPath = values.String(values.StrImported)
+	}
+
+	label := item.Label
+	if label == "" {
+		label = "-"
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D {
+			return item.copy.Layout(gtx, func(gtx C) D {
+				lbl := pg.Theme.Body1(item.Address)
+				lbl.Color = pg.Theme.Color.Primary
+				return lbl.Layout(gtx)
+			})
+		}),
+		layout.Rigid(row(values.String(values.StrHDPath), hdPath)),
+		layout.Rigid(row(values.String(values.StrTimesUsed), strconv.Itoa(int(item.TimesUsed)))),
+		layout.Rigid(row(values.String(values.StrBalance), item.Balance.String())),
+		layout.Rigid(row(values.String(values.StrFirstSeen), formatTime(item.FirstSeen))),
+		layout.Rigid(row(values.String(values.StrLastSeen), formatTime(item.LastSeen))),
+		layout.Rigid(func(gtx C) D {
+			return item.label.Layout(gtx, row(values.String(values.StrAddressLabel), label))
+		}),
+	)
+}
+
+// HandleUserInteractions is called just before Layout() to determine
+// if any user interaction recently occurred on the page and may be
+// used to update the page's UI components shortly before they are
+// displayed.
+// Part of the load.Page interface.
+func (pg *AddressExplorerPage) HandleUserInteractions() {
+	if pg.accountDropDown != nil && pg.accountDropDown.Changed() {
+		pg.addresses, pg.items = nil, nil
+		pg.loadAddresses()
+	}
+
+	if pg.filterDropDown.Changed() {
+		pg.filterAddresses()
+	}
+
+	for _, e := range pg.searchEditor.Editor.Events() {
+		if _, ok := e.(widget.ChangeEvent); ok {
+			pg.filterAddresses()
+		}
+	}
+
+	for _, item := range pg.items {
+		if item.copy.Clicked() {
+			pg.copyText = item.Address
+		}
+
+		if item.label.Clicked() {
+			pg.showLabelModal(item.AddressUsage)
+		}
+	}
+
+	if pg.exportBtn.Clicked() {
+		pg.exportAddresses()
+	}
+}
+
+func (pg *AddressExplorerPage) showLabelModal(addr *sharedW.AddressUsage) {
+	textModal := modal.NewTextInputModal(pg.Load).
+		Hint(values.String(values.StrAddressLabel)).
+		PositiveButtonStyle(pg.Load.Theme.Color.Primary, pg.Load.Theme.Color.InvText).
+		SetPositiveButtonCallback(func(label string, tm *modal.TextInputModal) bool {
+			pg.wallet.SetAddressLabel(addr.Address, label)
+			addr.Label = pg.wallet.AddressLabel(addr.Address)
+			tm.Dismiss()
+			return true
+		})
+	textModal.Title(values.String(values.StrSetAddressLabel)).
+		SetPositiveButtonText(values.String(values.StrSave))
+	pg.ParentWindow().ShowModal(textModal)
+}
+
+// exportAddresses writes the currently listed addresses to a CSV file in the
+// wallet's data directory.
+func (pg *AddressExplorerPage) exportAddresses() {
+	if len(pg.accounts) == 0 {
+		return
+	}
+
+	account := pg.accounts[pg.accountDropDown.SelectedIndex()]
+	fileName := fmt.Sprintf("addresses_%s_%d.csv", account.Name, time.Now().Unix())
+	filePath := filepath.Join(pg.wallet.DataDir(), fileName)
+
+	err := func() error {
+		file, err := os.Create(filePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		addresses := make([]*sharedW.AddressUsage, 0, len(pg.items))
+		for _, item := range pg.items {
+			addresses = append(addresses, item.AddressUsage)
+		}
+		return sharedW.ExportAddressUsageCSV(file, addresses)
+	}()
+	if err != nil {
+		errModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+		pg.ParentWindow().ShowModal(errModal)
+		return
+	}
+
+	info := modal.NewSuccessModal(pg.Load, values.StringF(values.StrAddressesExported, filePath), modal.DefaultClickFunc())
+	pg.ParentWindow().ShowModal(info)
+}
+
+// OnNavigatedFrom is called when the page is about to be removed from
+// the displayed window. This method should ideally be used to disable
+// features that are irrelevant when the page is NOT displayed.
+// NOTE: The page may be re-displayed on the app's window, in which case
+// OnNavigatedTo() will be called again. This method should not destroy UI
+// components unless they'll be recreated in the OnNavigatedTo() method.
+// Part of the load.Page interface.
+func (pg *AddressExplorerPage) OnNavigatedFrom() {}
@@ -0,0 +1,166 @@
+package settings
+
+import (
+	"strings"
+
+	"github.com/crypto-power/cryptopower/libwallet"
+	"github.com/crypto-power/cryptopower/ui/modal"
+	"github.com/crypto-power/cryptopower/ui/utils"
+	"github.com/crypto-power/cryptopower/ui/values"
+)
+
+// exportAppBackup asks for the passphrase that encrypts the app backup and
+// writes the backup to the app's data directory.
+func (pg *SettingPage) exportAppBackup() {
+	passphraseModal := modal.NewCreatePasswordModal(pg.Load).
+		Title(values.String(values.StrExportAppBackup)).
+		EnableName(false).
+		PasswordHint(values.String(values.StrBackupPassphrase)).
+		SetPositiveButtonCallback(func(_, passphrase string, m *modal.CreatePasswordModal) bool {
+			if !utils.StringNotEmpty(passphrase) {
+				m.SetError(values.String(values.StrErrPassEmpty))
+				m.SetLoading(false)
+				return false
+			}
+
+			filePath, err := pg.WL.AssetsManager.ExportAppBackup(passphrase)
+			if err != nil {
+				m.SetError(err.Error())
+				m.SetLoading(false)
+				return false
+			}
+			m.Dismiss()
+
+			info := modal.NewSuccessModal(pg.Load, values.StringF(values.StrAppBackupExported, filePath), modal.DefaultClickFunc())
+			pg.ParentWindow().ShowModal(info)
+			return true
+		})
+	pg.ParentWindow().ShowModal(passphraseModal)
+}
+
+// importAppBackup asks for the path and passphrase of an app backup and the
+// spending passphrases of the wallets whose seed it holds before importing it.
+func (pg *SettingPage) importAppBackup() {
+	pathModal := modal.NewTextInputModal(pg.Load).
+		Hint(values.String(values.StrBackupFilePath)).
+		PositiveButtonStyle(pg.Load.Theme.Color.Primary, pg.Load.Theme.Color.InvText).
+		SetPositiveButtonCallback(func(filePath string, tm *modal.TextInputModal) bool {
+			filePath = strings.TrimSpace(filePath)
+			if filePath == "" {
+				tm.SetError(values.String(values.StrBackupFilePath))
+				return false
+			}
+			tm.Dismiss()
+			pg.openAppBackup(filePath)
+			return true
+		})
+	pathModal.Title(values.String(values.StrImportAppBackup)).
+		SetPositiveButtonText(values.String(values.StrNext))
+	pg.ParentWindow().ShowModal(pathModal)
+}
+
+func (pg *SettingPage) openAppBackup(filePath string) {
+	passphraseModal := modal.NewCreatePasswordModal(pg.Load).
+		Title(values.String(values.StrImportAppBackup)).
+		EnableName(false).
+		EnableConfirmPassword(false).
+		PasswordHint(values.String(values.StrBackupPassphrase)).
+		SetPositiveButtonCallback(func(_, passphrase string, m *modal.CreatePasswordModal) bool {
+			backup, err := pg.WL.AssetsManager.OpenAppBackup(filePath, passphrase)
+			if err != nil {
+				m.SetError(err.Error())
+				m.SetLoading(false)
+				return false
+			}
+			m.Dismiss()
+
+			pg.confirmDowngradedWallets(backup)
+			return true
+		})
+	pg.ParentWindow().ShowModal(passphraseModal)
+}
+
+// confirmDowngradedWallets lists the spending wallets that will be recreated
+// as watch only wallets because the backup holds no seed for them before the
+// import continues.
+func (pg *SettingPage) confirmDowngradedWallets(backup *libwallet.AppBackup) {
+	downgraded := backup.DowngradedWallets()
+	if len(downgraded) == 0 {
+		pg.requestWalletPassphrases(backup, 0, make(map[string]string))
+		return
+	}
+
+	names := make([]string, 0, len(downgraded))
+	for _, wallet := range downgraded {
+		names = append(names, wallet.Name)
+	}
+	confirmModal := modal.NewCustomModal(pg.Load).
+		Title(values.String(values.StrImportAppBackup)).
+		Body(values.StringF(values.StrWatchOnlyImport, strings.Join(names, ", "))).
+		SetNegativeButtonText(values.String(values.StrCancel)).
+		SetPositiveButtonText(values.String(values.StrNext)).
+		SetPositiveButtonCallback(func(_ bool, _ *modal.InfoModal) bool {
+			pg.requestWalletPassphrases(backup, 0, make(map[string]string))
+			return true
+		})
+	pg.ParentWindow().ShowModal(confirmModal)
+}
+
+// requestWalletPassphrases asks for the spending passphrase of each wallet
+// restored from its seed, one wallet at a time, then imports the backup.
+func (pg *SettingPage) requestWalletPassphrases(backup *libwallet.AppBackup, index int, passphrases map[string]string) {
+	wallets := backup.WalletsWithSeed()
+	if index >= len(wallets) {
+		pg.finishAppBackupImport(backup, passphrases)
+		return
+	}
+
+	wallet := wallets[index]
+	passphraseModal := modal.NewCreatePasswordModal(pg.Load).
+		Title(values.StringF(values.StrWalletSpendingPassphrase, wallet.Name)).
+		EnableName(false).
+		EnableConfirmPassword(false).
+		SetPositiveButtonCallback(func(_, passphrase string, m *modal.CreatePasswordModal) bool {
+			if _, err := wallet.DecryptSeed(passphrase); err != nil {
+				m.SetError(err.Error())
+				m.SetLoading(false)
+				return false
+			}
+			m.Dismiss()
+
+			passphrases[wallet.Name] = passphrase
+			pg.requestWalletPassphrases(backup, index+1, passphrases)
+			return true
+		})
+	pg.ParentWindow().ShowModal(passphraseModal)
+}
+
+// finishAppBackupImport imports the backup and reports the outcome of the
+// import of each wallet.
+func (pg *SettingPage) finishAppBackupImport(backup *libwallet.AppBackup, passphrases map[string]string) {
+	results, err := pg.WL.AssetsManager.ImportAppBackup(backup, passphrases)
+	pg.updateSettingOptions()
+
+	lines := make([]string, 0, len(results)+1)
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			lines = append(lines, values.StringF(values.StrWalletImportFailed, result.Name, result.Err))
+		case result.Skipped:
+			lines = append(lines, values.StringF(values.StrWalletImportSkipped, result.Name))
+		case result.Downgraded:
+			lines = append(lines, values.StringF(values.StrWalletImportDowngraded, result.Name))
+		default:
+			lines = append(lines, values.StringF(values.StrWalletImported, result.Name))
+		}
+	}
+	if err != nil {
+		lines = append(lines, err.Error())
+	}
+
+	reportModal := modal.NewCustomModal(pg.Load).
+		Title(values.String(values.StrAppBackupImported)).
+		Body(strings.Join(lines, "\n")).
+		SetPositiveButtonText(values.String(values.StrOk))
+	pg.ParentWindow().ShowModal(reportModal)
+}
@@ -2,7 +2,6 @@ package settings
 
 import (
 	"fmt"
-	"strconv"
 
 	"gioui.org/io/clipboard"
 	"gioui.org/layout"
@@ -42,6 +41,8 @@ type BTCAcctDetailsPage struct {
 	keys                    string
 	extendedKey             string
 	extendedKeyClickable    *cryptomaterial.Clickable
+	descriptor              string
+	descriptorClickable     *cryptomaterial.Clickable
 	showExtendedKeyButton   *cryptomaterial.Clickable
 	isHiddenExtendedxPubkey bool
 	infoButton              cryptomaterial.IconButton
@@ -62,6 +63,7 @@ func NewAcctBTCDetailsPage(l *load.Load, account *sharedW.Account) *BTCAcctDetai
 		backButton:              l.Theme.IconButton(l.Theme.Icons.NavigationArrowBack),
 		renameAccount:           l.Theme.NewClickable(false),
 		extendedKeyClickable:    l.Theme.NewClickable(true),
+		descriptorClickable:     l.Theme.NewClickable(true),
 		showExtendedKeyButton:   l.Theme.NewClickable(false),
 		isHiddenExtendedxPubkey: true,
 	}
@@ -78,7 +80,16 @@ func NewAcctBTCDetailsPage(l *load.Load, account *sharedW.Account) *BTCAcctDetai
 func (pg *BTCAcctDetailsPage) OnNavigatedTo() {
 	pg.totalBalance = pg.account.Balance.Total.String()
 
-	pg.hdPath = pg.WL.BTCHDPrefix() + strconv.Itoa(int(pg.account.AccountNumber)) + "'"
+	if w, ok := pg.wallet.(interface {
+		HDPathForAccount(accountNumber int32) (string, error)
+	}); ok {
+		hdPath, err := w.HDPathForAccount(pg.account.Number)
+		if err != nil {
+			log.Errorf("Error getting the account HD path: %v", err)
+		} else {
+			pg.hdPath = hdPath + "'"
+		}
+	}
 
 	ext := pg.account.ExternalKeyCount
 	internal := pg.account.InternalKeyCount
@@ -111,6 +122,12 @@ func (pg *BTCAcctDetailsPage) Layout(gtx C) D {
 		func(gtx C) D {
 			return layout.Inset{Bottom: m}.Layout(gtx, pg.extendedPubkey)
 		},
+		func(gtx C) D {
+			if pg.descriptor == "" || pg.isHiddenExtendedxPubkey {
+				return D{}
+			}
+			return layout.Inset{Bottom: m}.Layout(gtx, pg.outputDescriptor)
+		},
 	}
 	if pg.Load.GetCurrentAppWidth() <= gtx.Dp(values.StartMobileView) {
 		return pg.layoutMobile(gtx, widgets)
@@ -170,6 +187,31 @@ func (pg *BTCAcctDetailsPage) extendedPubkey(gtx C) D {
 	})
 }
 
+// outputDescriptor displays the output descriptor of the account which can be
+// used to import the account into other wallets as watch-only.
+func (pg *BTCAcctDetailsPage) outputDescriptor(gtx C) D {
+	return pg.pageSections(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx C) D {
+				lbl := pg.theme.Label(values.TextSize14, values.String(values.StrOutputDescriptor))
+				lbl.Color = pg.theme.Color.GrayText2
+				return lbl.Layout(gtx)
+			}),
+			layout.Rigid(func(gtx C) D {
+				if pg.descriptorClickable.Clicked() {
+					clipboard.WriteOp{Text: pg.descriptor}.Add(gtx.Ops)
+					pg.Toast.Notify(values.String(values.StrDescriptorCopied))
+				}
+				lbl := pg.theme.Label(values.TextSize14, pg.descriptor)
+				lbl.Color = pg.theme.Color.Primary
+				return layout.Inset{Top: values.MarginPadding5}.Layout(gtx, func(gtx C) D {
+					return pg.descriptorClickable.Layout(gtx, lbl.Layout)
+				})
+			}),
+		)
+	})
+}
+
 func (pg *BTCAcctDetailsPage) layoutDesktop(gtx layout.Context, widgets []func(gtx C) D) layout.Dimensions {
 	body := func(gtx C) D {
 		sp := components.SubPage{
@@ -405,6 +447,16 @@ func (pg *BTCAcctDetailsPage) loadExtendedPubKey() {
 		pg.Toast.NotifyError(err.Error())
 	}
 	pg.extendedKey = xpub
+
+	if w, ok := pg.WL.SelectedWallet.Wallet.(interface {
+		GetAccountDescriptor(account int32) (string, error)
+	}); ok {
+		descriptor, err := w.GetAccountDescriptor(pg.account.Number)
+		if err != nil {
+			log.Errorf("Error getting the account descriptor: %v", err)
+		}
+		pg.descriptor = descriptor
+	}
 }
 
 // OnNavigatedFrom is called when the page is about to be removed from
@@ -0,0 +1,118 @@
+package settings
+
+import (
+	"strings"
+
+	"gioui.org/layout"
+
+	libutils "github.com/crypto-power/cryptopower/libwallet/utils"
+	"github.com/crypto-power/cryptopower/ui/cryptomaterial"
+	"github.com/crypto-power/cryptopower/ui/modal"
+	"github.com/crypto-power/cryptopower/ui/values"
+)
+
+// devServiceTitles maps the services developer mode can redirect to the
+// title of their row.
+var devServiceTitles = map[libutils.DevService]string{
+	libutils.DcrdataService:     values.StrDevDcrdata,
+	libutils.PoliteiaService:    values.StrDevPoliteia,
+	libutils.VSPListService:     values.StrDevVSPList,
+	libutils.BTCFeeRateService:  values.StrDevBTCFeeRate,
+	libutils.LTCFeeRateService:  values.StrDevLTCFeeRate,
+	libutils.RateSourceService:  values.StrDevRateSource,
+	libutils.DCRExplorerService: values.StrDevDCRExplorer,
+	libutils.BTCExplorerService: values.StrDevBTCExplorer,
+	libutils.LTCExplorerService: values.StrDevLTCExplorer,
+}
+
+func (pg *SettingPage) initDevModeWidgets() {
+	pg.devMode = pg.Theme.Switch()
+	pg.devServices = make(map[libutils.DevService]*cryptomaterial.Clickable, len(libutils.DevServices))
+	for _, service := range libutils.DevServices {
+		pg.devServices[service] = pg.Theme.NewClickable(false)
+	}
+}
+
+// developer lays out the developer mode section. It is only shown on simnet
+// and regnet, the networks developer mode is available on.
+func (pg *SettingPage) developer() layout.Widget {
+	return func(gtx C) D {
+		if !pg.WL.AssetsManager.IsDevModeSupported() {
+			return D{}
+		}
+		return pg.wrapSection(gtx, values.String(values.StrDeveloperMode), func(gtx C) D {
+			cfg := pg.WL.AssetsManager.DevModeConfig()
+			rows := []layout.FlexChild{
+				layout.Rigid(func(gtx C) D {
+					return pg.subSectionSwitch(gtx, values.String(values.StrDeveloperMode), pg.devMode)
+				}),
+			}
+			for _, service := range libutils.DevServices {
+				service := service
+				rows = append(rows, layout.Rigid(func(gtx C) D {
+					label := values.String(values.StrNoStandIn)
+					if standIn := cfg.ServiceURLs[service]; standIn != "" {
+						label = standIn
+					}
+					serviceRow := row{
+						title:     values.String(devServiceTitles[service]),
+						clickable: pg.devServices[service],
+						label:     pg.Theme.Body2(label),
+					}
+					return pg.clickableRow(gtx, serviceRow)
+				}))
+			}
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx, rows...)
+		})
+	}
+}
+
+func (pg *SettingPage) handleDevModeEvents() {
+	if pg.devMode.Changed() {
+		cfg := pg.WL.AssetsManager.DevModeConfig()
+		cfg.Enabled = pg.devMode.IsChecked()
+		if err := pg.WL.AssetsManager.SetDevMode(cfg); err != nil {
+			errModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+			pg.ParentWindow().ShowModal(errModal)
+			pg.devMode.SetChecked(!cfg.Enabled)
+		}
+	}
+
+	for service, clickable := range pg.devServices {
+		if clickable.Clicked() {
+			pg.devServiceModal(service)
+		}
+	}
+}
+
+// devServiceModal asks for the URL of the local stand-in of service. An
+// empty URL removes the stand-in, which keeps the service from being
+// contacted while developer mode is on.
+func (pg *SettingPage) devServiceModal(service libutils.DevService) {
+	cfg := pg.WL.AssetsManager.DevModeConfig()
+	textModal := modal.NewTextInputModal(pg.Load).
+		Hint(values.String(values.StrStandInURL)).
+		PositiveButtonStyle(pg.Load.Theme.Color.Primary, pg.Load.Theme.Color.InvText).
+		SetPositiveButtonCallback(func(standIn string, tm *modal.TextInputModal) bool {
+			if standIn = strings.TrimSpace(standIn); standIn != "" {
+				cfg.ServiceURLs[service] = standIn
+			} else {
+				delete(cfg.ServiceURLs, service)
+			}
+
+			if err := pg.WL.AssetsManager.SetDevMode(cfg); err != nil {
+				tm.SetError(err.Error())
+				tm.SetLoading(false)
+				return false
+			}
+			tm.Dismiss()
+
+			pg.showNoticeSuccess(values.String(values.StrDeveloperModeUpdated))
+			return true
+		})
+	textModal.SetText(cfg.ServiceURLs[service])
+	textModal.Title(values.String(devServiceTitles[service])).
+		SetPositiveButtonText(values.String(values.StrSave)).
+		SetNegativeButtonText(values.String(values.StrCancel))
+	pg.ParentWindow().ShowModal(textModal)
+}
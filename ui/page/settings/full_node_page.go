@@ -0,0 +1,312 @@
+package settings
+
+import (
+	"strings"
+
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/widget"
+
+	"github.com/crypto-power/cryptopower/app"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/ui/cryptomaterial"
+	"github.com/crypto-power/cryptopower/ui/load"
+	"github.com/crypto-power/cryptopower/ui/modal"
+	"github.com/crypto-power/cryptopower/ui/page/components"
+	"github.com/crypto-power/cryptopower/ui/values"
+)
+
+const FullNodePageID = "FullNode"
+
+// FullNodePage sets the full node or electrum servers the selected wallet
+// syncs from instead of SPV.
+type FullNodePage struct {
+	*load.Load
+	// GenericPageModal defines methods such as ID() and OnAttachedToNavigator()
+	// that helps this Page satisfy the app.Page interface. It also defines
+	// helper methods for accessing the PageNavigator that displayed this page
+	// and the root WindowNavigator.
+	*app.GenericPageModal
+
+	wallet   sharedW.Asset
+	backends []sharedW.RPCBackend
+	backend  *widget.Enum
+
+	hostEditor     cryptomaterial.Editor
+	userEditor     cryptomaterial.Editor
+	passEditor     cryptomaterial.Editor
+	certEditor     cryptomaterial.Editor
+	zmqBlockEditor cryptomaterial.Editor
+	zmqTxEditor    cryptomaterial.Editor
+	electrumEditor cryptomaterial.Editor
+	disableTLS     cryptomaterial.CheckBoxStyle
+	fallbackToSPV  cryptomaterial.CheckBoxStyle
+
+	saveBtn    cryptomaterial.Button
+	removeBtn  cryptomaterial.Button
+	backButton cryptomaterial.IconButton
+}
+
+func NewFullNodePage(l *load.Load) *FullNodePage {
+	wallet := l.WL.SelectedWallet.Wallet
+	pg := &FullNodePage{
+		Load:             l,
+		GenericPageModal: app.NewGenericPageModal(FullNodePageID),
+		wallet:           wallet,
+		backends:         sharedW.RPCBackends(wallet.GetAssetType()),
+		backend:          new(widget.Enum),
+	}
+
+	pg.backButton, _ = components.SubpageHeaderButtons(l)
+
+	newEditor := func(hint string) cryptomaterial.Editor {
+		editor := l.Theme.Editor(new(widget.Editor), hint)
+		editor.Editor.SingleLine = true
+		return editor
+	}
+	pg.hostEditor = newEditor(values.String(values.StrRPCHost))
+	pg.userEditor = newEditor(values.String(values.StrRPCUser))
+	pg.passEditor = l.Theme.EditorPassword(new(widget.Editor), values.String(values.StrRPCPassword))
+	pg.passEditor.Editor.SingleLine = true
+	pg.certEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrRPCCertificate))
+	pg.zmqBlockEditor = newEditor(values.String(values.StrZMQBlockHost))
+	pg.zmqTxEditor = newEditor(values.String(values.StrZMQTxHost))
+	pg.electrumEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrElectrumServers))
+
+	pg.disableTLS = l.Theme.CheckBox(new(widget.Bool), values.String(values.StrDisableTLS))
+	pg.fallbackToSPV = l.Theme.CheckBox(new(widget.Bool), values.String(values.StrFallbackToSPV))
+
+	pg.saveBtn = l.Theme.Button(values.String(values.StrSave))
+	pg.saveBtn.Font.Weight = font.Medium
+
+	pg.removeBtn = l.Theme.OutlineButton(values.String(values.StrRemove))
+	pg.removeBtn.Font.Weight = font.Medium
+
+	return pg
+}
+
+// OnNavigatedTo is called when the page is about to be displayed and
+// may be used to initialize page features that are only relevant when
+// the page is displayed.
+// Part of the load.Page interface.
+func (pg *FullNodePage) OnNavigatedTo() {
+	cfg := pg.wallet.RPCSyncConfig()
+	if cfg == nil {
+		cfg = &sharedW.RPCSyncConfig{FallbackToSPV: true}
+		if len(pg.backends) > 0 {
+			cfg.Backend = pg.backends[0]
+		}
+	}
+
+	pg.backend.Value = string(cfg.Backend)
+	pg.hostEditor.Editor.SetText(cfg.Host)
+	pg.userEditor.Editor.SetText(cfg.User)
+	pg.passEditor.Editor.SetText(cfg.Pass)
+	pg.certEditor.Editor.SetText(cfg.Certificate)
+	pg.zmqBlockEditor.Editor.SetText(cfg.ZMQBlockHost)
+	pg.zmqTxEditor.Editor.SetText(cfg.ZMQTxHost)
+	servers := make([]string, 0, len(cfg.ElectrumServers))
+	for _, server := range cfg.ElectrumServers {
+		servers = append(servers, server.String())
+	}
+	pg.electrumEditor.Editor.SetText(strings.Join(servers, "\n"))
+	pg.disableTLS.CheckBox.Value = cfg.DisableTLS
+	pg.fallbackToSPV.CheckBox.Value = cfg.FallbackToSPV
+}
+
+// isElectrum returns true if the electrum backend is selected.
+func (pg *FullNodePage) isElectrum() bool {
+	return sharedW.RPCBackend(pg.backend.Value) == sharedW.ElectrumBackend
+}
+
+// usesTLS returns true if the selected backend serves RPC over TLS.
+// bitcoind and litecoind serve RPC over plain http and notify over ZMQ.
+func (pg *FullNodePage) usesTLS() bool {
+	backend := sharedW.RPCBackend(pg.backend.Value)
+	return backend != sharedW.BitcoindBackend && backend != sharedW.LitecoindBackend
+}
+
+// Layout draws the page UI components into the provided C
+// to be eventually drawn on screen.
+// Part of the load.Page interface.
+func (pg *FullNodePage) Layout(gtx C) D {
+	body := func(gtx C) D {
+		sp := components.SubPage{
+			Load:       pg.Load,
+			Title:      values.String(values.StrFullNode),
+			BackButton: pg.backButton,
+			Back: func() {
+				pg.ParentNavigator().CloseCurrentPage()
+			},
+			Body: func(gtx C) D {
+				return layout.Inset{Top: values.MarginPadding5}.Layout(gtx, pg.layoutContent)
+			},
+		}
+		return sp.Layout(pg.ParentWindow(), gtx)
+	}
+
+	if pg.Load.GetCurrentAppWidth() <= gtx.Dp(values.StartMobileView) {
+		return components.UniformMobile(gtx, false, false, body)
+	}
+	return body(gtx)
+}
+
+func (pg *FullNodePage) layoutContent(gtx C) D {
+	field := func(w layout.Widget) layout.FlexChild {
+		return layout.Rigid(func(gtx C) D {
+			return layout.Inset{Top: values.MarginPadding15}.Layout(gtx, w)
+		})
+	}
+
+	backends := make([]layout.FlexChild, 0, len(pg.backends))
+	for _, backend := range pg.backends {
+		radioBtn := pg.Theme.RadioButton(pg.backend, string(backend), string(backend),
+			pg.Theme.Color.DeepBlue, pg.Theme.Color.Primary)
+		backends = append(backends, layout.Rigid(func(gtx C) D {
+			return layout.Inset{Right: values.MarginPadding20}.Layout(gtx, radioBtn.Layout)
+		}))
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(func(gtx C) D {
+			desc := pg.Theme.Caption(values.String(values.StrFullNodeNote))
+			desc.Color = pg.Theme.Color.GrayText2
+			return layout.Inset{Bottom: values.MarginPadding5}.Layout(gtx, desc.Layout)
+		}),
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{Axis: layout.Horizontal}.Layout(gtx, backends...)
+		}),
+	}
+	switch {
+	case pg.isElectrum():
+		children = append(children, field(pg.electrumEditor.Layout))
+	case pg.usesTLS():
+		children = append(children,
+			field(pg.hostEditor.Layout),
+			field(pg.userEditor.Layout),
+			field(pg.passEditor.Layout),
+			field(pg.disableTLS.Layout),
+			field(func(gtx C) D {
+				if pg.disableTLS.CheckBox.Value {
+					return D{}
+				}
+				return pg.certEditor.Layout(gtx)
+			}))
+	default:
+		children = append(children,
+			field(pg.hostEditor.Layout),
+			field(pg.userEditor.Layout),
+			field(pg.passEditor.Layout),
+			field(pg.zmqBlockEditor.Layout),
+			field(pg.zmqTxEditor.Layout))
+	}
+	children = append(children,
+		field(pg.fallbackToSPV.Layout),
+		layout.Rigid(func(gtx C) D {
+			return layout.E.Layout(gtx, func(gtx C) D {
+				return layout.Inset{Top: values.MarginPadding15}.Layout(gtx, func(gtx C) D {
+					return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+						layout.Rigid(func(gtx C) D {
+							if pg.wallet.RPCSyncConfig() == nil {
+								return D{}
+							}
+							return layout.Inset{Right: values.MarginPadding10}.Layout(gtx, pg.removeBtn.Layout)
+						}),
+						layout.Rigid(pg.saveBtn.Layout),
+					)
+				})
+			})
+		}))
+
+	return pg.Theme.Card().Layout(gtx, func(gtx C) D {
+		return layout.UniformInset(values.MarginPadding15).Layout(gtx, func(gtx C) D {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+		})
+	})
+}
+
+// HandleUserInteractions is called just before Layout() to determine
+// if any user interaction recently occurred on the page and may be
+// used to update the page's UI components shortly before they are
+// displayed.
+// Part of the load.Page interface.
+func (pg *FullNodePage) HandleUserInteractions() {
+	if pg.isElectrum() {
+		pg.saveBtn.SetEnabled(strings.TrimSpace(pg.electrumEditor.Editor.Text()) != "")
+	} else {
+		pg.saveBtn.SetEnabled(strings.TrimSpace(pg.hostEditor.Editor.Text()) != "")
+	}
+
+	if pg.saveBtn.Clicked() {
+		servers, err := pg.electrumServers()
+		if err != nil {
+			errModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+			pg.ParentWindow().ShowModal(errModal)
+			return
+		}
+
+		pg.saveConfig(&sharedW.RPCSyncConfig{
+			Backend:         sharedW.RPCBackend(pg.backend.Value),
+			Host:            strings.TrimSpace(pg.hostEditor.Editor.Text()),
+			User:            strings.TrimSpace(pg.userEditor.Editor.Text()),
+			Pass:            pg.passEditor.Editor.Text(),
+			Certificate:     strings.TrimSpace(pg.certEditor.Editor.Text()),
+			DisableTLS:      pg.disableTLS.CheckBox.Value,
+			ZMQBlockHost:    strings.TrimSpace(pg.zmqBlockEditor.Editor.Text()),
+			ZMQTxHost:       strings.TrimSpace(pg.zmqTxEditor.Editor.Text()),
+			ElectrumServers: servers,
+			FallbackToSPV:   pg.fallbackToSPV.CheckBox.Value,
+		})
+	}
+
+	if pg.removeBtn.Clicked() {
+		pg.saveConfig(nil)
+	}
+}
+
+// electrumServers parses the servers entered one per line.
+func (pg *FullNodePage) electrumServers() ([]sharedW.ElectrumServer, error) {
+	if !pg.isElectrum() {
+		return nil, nil
+	}
+
+	var servers []sharedW.ElectrumServer
+	for _, line := range strings.Split(pg.electrumEditor.Editor.Text(), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		server, err := sharedW.ParseElectrumServer(line)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, *server)
+	}
+	return servers, nil
+}
+
+// saveConfig saves cfg and disconnects the wallet if it is connected so that
+// it syncs from the new source when it reconnects.
+func (pg *FullNodePage) saveConfig(cfg *sharedW.RPCSyncConfig) {
+	if err := pg.wallet.SetRPCSyncConfig(cfg); err != nil {
+		errModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+		pg.ParentWindow().ShowModal(errModal)
+		return
+	}
+
+	if pg.wallet.IsSyncing() || pg.wallet.IsSynced() {
+		go pg.wallet.CancelSync()
+	}
+
+	info := modal.NewSuccessModal(pg.Load, values.String(values.StrFullNodeSaved), modal.DefaultClickFunc())
+	pg.ParentWindow().ShowModal(info)
+	pg.OnNavigatedTo()
+}
+
+// OnNavigatedFrom is called when the page is about to be removed from
+// the displayed window. This method should ideally be used to disable
+// features that are irrelevant when the page is NOT displayed.
+// NOTE: The page may be re-displayed on the app's window, in which case
+// OnNavigatedTo() will be called again. This method should not destroy UI
+// components unless they'll be recreated in the OnNavigatedTo() method.
+// Part of the load.Page interface.
+func (pg *FullNodePage) OnNavigatedFrom() {}
@@ -0,0 +1,223 @@
+package settings
+
+import (
+	"strings"
+	"time"
+
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/widget"
+
+	"github.com/crypto-power/cryptopower/app"
+	libutils "github.com/crypto-power/cryptopower/libwallet/utils"
+	"github.com/crypto-power/cryptopower/ui/cryptomaterial"
+	"github.com/crypto-power/cryptopower/ui/load"
+	"github.com/crypto-power/cryptopower/ui/modal"
+	"github.com/crypto-power/cryptopower/ui/page/components"
+	"github.com/crypto-power/cryptopower/ui/utils"
+	"github.com/crypto-power/cryptopower/ui/values"
+)
+
+const ImportKeyPageID = "ImportKey"
+
+// birthdayLayout is the date format expected in the birthday editor.
+const birthdayLayout = "2006-01-02"
+
+// ImportKeyPage imports individual private keys into the imported account or
+// watches individual addresses given their public keys.
+type ImportKeyPage struct {
+	*load.Load
+	// GenericPageModal defines methods such as ID() and OnAttachedToNavigator()
+	// that helps this Page satisfy the app.Page interface. It also defines
+	// helper methods for accessing the PageNavigator that displayed this page
+	// and the root WindowNavigator.
+	*app.GenericPageModal
+
+	keyEditor      cryptomaterial.Editor
+	birthdayEditor cryptomaterial.Editor
+	importBtn      cryptomaterial.Button
+	watchBtn       cryptomaterial.Button
+	backButton     cryptomaterial.IconButton
+}
+
+func NewImportKeyPage(l *load.Load) *ImportKeyPage {
+	pg := &ImportKeyPage{
+		Load:             l,
+		GenericPageModal: app.NewGenericPageModal(ImportKeyPageID),
+	}
+
+	pg.backButton, _ = components.SubpageHeaderButtons(l)
+
+	pg.keyEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrKeyHint))
+	pg.keyEditor.Editor.SingleLine = true
+
+	pg.birthdayEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrBirthdayHint))
+	pg.birthdayEditor.Editor.SingleLine = true
+
+	pg.importBtn = l.Theme.Button(values.String(values.StrImportPrivateKey))
+	pg.importBtn.Font.Weight = font.Medium
+
+	pg.watchBtn = l.Theme.OutlineButton(values.String(values.StrWatchAddress))
+	pg.watchBtn.Font.Weight = font.Medium
+
+	return pg
+}
+
+// OnNavigatedTo is called when the page is about to be displayed and
+// may be used to initialize page features that are only relevant when
+// the page is displayed.
+// Part of the load.Page interface.
+func (pg *ImportKeyPage) OnNavigatedTo() {
+	pg.keyEditor.Editor.Focus()
+}
+
+// Layout draws the page UI components into the provided C
+// to be eventually drawn on screen.
+// Part of the load.Page interface.
+func (pg *ImportKeyPage) Layout(gtx C) D {
+	body := func(gtx C) D {
+		sp := components.SubPage{
+			Load:       pg.Load,
+			Title:      values.String(values.StrImportKey),
+			BackButton: pg.backButton,
+			Back: func() {
+				pg.ParentNavigator().CloseCurrentPage()
+			},
+			Body: func(gtx C) D {
+				return layout.Inset{Top: values.MarginPadding5}.Layout(gtx, pg.layoutContent)
+			},
+		}
+		return sp.Layout(pg.ParentWindow(), gtx)
+	}
+
+	if pg.Load.GetCurrentAppWidth() <= gtx.Dp(values.StartMobileView) {
+		return components.UniformMobile(gtx, false, false, body)
+	}
+	return body(gtx)
+}
+
+func (pg *ImportKeyPage) layoutContent(gtx C) D {
+	return pg.Theme.Card().Layout(gtx, func(gtx C) D {
+		return layout.UniformInset(values.MarginPadding15).Layout(gtx, func(gtx C) D {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(func(gtx C) D {
+					note := values.String(values.StrImportKeyNote)
+					if pg.WL.SelectedWallet.Wallet.GetAssetType() == libutils.DCRWalletAsset {
+						note = values.String(values.StrImportKeyNoteDCR)
+					}
+					desc := pg.Theme.Caption(note)
+					desc.Color = pg.Theme.Color.GrayText2
+					return layout.Inset{Bottom: values.MarginPadding20}.Layout(gtx, desc.Layout)
+				}),
+				layout.Rigid(pg.keyEditor.Layout),
+				layout.Rigid(func(gtx C) D {
+					return layout.Inset{Top: values.MarginPadding15}.Layout(gtx, pg.birthdayEditor.Layout)
+				}),
+				layout.Rigid(func(gtx C) D {
+					return layout.E.Layout(gtx, func(gtx C) D {
+						return layout.Inset{Top: values.MarginPadding15}.Layout(gtx, func(gtx C) D {
+							return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+								layout.Rigid(func(gtx C) D {
+									return layout.Inset{Right: values.MarginPadding10}.Layout(gtx, pg.watchBtn.Layout)
+								}),
+								layout.Rigid(pg.importBtn.Layout),
+							)
+						})
+					})
+				}),
+			)
+		})
+	})
+}
+
+// HandleUserInteractions is called just before Layout() to determine
+// if any user interaction recently occurred on the page and may be
+// used to update the page's UI components shortly before they are
+// displayed.
+// Part of the load.Page interface.
+func (pg *ImportKeyPage) HandleUserInteractions() {
+	canSubmit := utils.EditorsNotEmpty(pg.keyEditor.Editor, pg.birthdayEditor.Editor)
+	pg.importBtn.SetEnabled(canSubmit && !pg.WL.SelectedWallet.Wallet.IsWatchingOnlyWallet())
+	pg.watchBtn.SetEnabled(canSubmit)
+
+	if pg.importBtn.Clicked() {
+		pg.importPrivateKey()
+	}
+
+	if pg.watchBtn.Clicked() {
+		pg.watchAddress()
+	}
+}
+
+// birthday parses the birthday editor. A validation error is displayed on
+// the editor if the birthday is invalid.
+func (pg *ImportKeyPage) birthday() (time.Time, bool) {
+	pg.birthdayEditor.SetError("")
+	birthday, err := time.Parse(birthdayLayout, strings.TrimSpace(pg.birthdayEditor.Editor.Text()))
+	if err != nil || birthday.After(time.Now()) {
+		pg.birthdayEditor.SetError(values.String(values.StrInvalidBirthday))
+		return time.Time{}, false
+	}
+	return birthday, true
+}
+
+func (pg *ImportKeyPage) importPrivateKey() {
+	birthday, ok := pg.birthday()
+	if !ok {
+		return
+	}
+
+	passwordModal := modal.NewCreatePasswordModal(pg.Load).
+		EnableName(false).
+		EnableConfirmPassword(false).
+		Title(values.String(values.StrImportPrivateKey)).
+		PasswordHint(values.String(values.StrSpendingPassword)).
+		SetPositiveButtonCallback(func(_, password string, pm *modal.CreatePasswordModal) bool {
+			wif := strings.TrimSpace(pg.keyEditor.Editor.Text())
+			address, err := pg.WL.SelectedWallet.Wallet.ImportPrivateKey(wif, password, birthday)
+			if err != nil {
+				pm.SetError(err.Error())
+				pm.SetLoading(false)
+				return false
+			}
+
+			pm.Dismiss()
+			pg.keyImported(address)
+			return true
+		})
+	pg.ParentWindow().ShowModal(passwordModal)
+}
+
+func (pg *ImportKeyPage) watchAddress() {
+	birthday, ok := pg.birthday()
+	if !ok {
+		return
+	}
+
+	pubKeyOrAddress := strings.TrimSpace(pg.keyEditor.Editor.Text())
+	address, err := pg.WL.SelectedWallet.Wallet.WatchAddress(pubKeyOrAddress, birthday)
+	if err != nil {
+		errModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+		pg.ParentWindow().ShowModal(errModal)
+		return
+	}
+
+	pg.keyImported(address)
+}
+
+func (pg *ImportKeyPage) keyImported(address string) {
+	pg.keyEditor.Editor.SetText("")
+	pg.birthdayEditor.Editor.SetText("")
+
+	info := modal.NewSuccessModal(pg.Load, values.StringF(values.StrKeyImported, address), modal.DefaultClickFunc())
+	pg.ParentWindow().ShowModal(info)
+}
+
+// OnNavigatedFrom is called when the page is about to be removed from
+// the displayed window. This method should ideally be used to disable
+// features that are irrelevant when the page is NOT displayed.
+// NOTE: The page may be re-displayed on the app's window, in which case
+// OnNavigatedTo() will be called again. This method should not destroy UI
+// components unless they'll be recreated in the OnNavigatedTo() method.
+// Part of the load.Page interface.
+func (pg *ImportKeyPage) OnNavigatedFrom() {}
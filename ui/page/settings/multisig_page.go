@@ -0,0 +1,561 @@
+package settings
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"gioui.org/font"
+	"gioui.org/io/clipboard"
+	"gioui.org/layout"
+	"gioui.org/widget"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/crypto-power/cryptopower/app"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	"github.com/crypto-power/cryptopower/ui/cryptomaterial"
+	"github.com/crypto-power/cryptopower/ui/load"
+	"github.com/crypto-power/cryptopower/ui/modal"
+	"github.com/crypto-power/cryptopower/ui/page/components"
+	"github.com/crypto-power/cryptopower/ui/utils"
+	"github.com/crypto-power/cryptopower/ui/values"
+)
+
+const MultisigPageID = "Multisig"
+
+// multisigWallet is implemented by the wallets that support m-of-n multisig
+// i.e. BTC and LTC wallets.
+type multisigWallet interface {
+	sharedW.Asset
+
+	IsMultisig() bool
+	MultisigConfig() *sharedW.MultisigConfig
+	MultisigXpub() (string, error)
+	CreateMultisigAccount(privPass string) error
+	SetupMultisig(threshold int32, cosignerXpubs []string, privPass string, birthday time.Time) error
+	CurrentMultisigAddress() (string, error)
+	MultisigBalance() (sharedW.AssetAmount, error)
+	CreateMultisigPSBT(toAddress string, amount int64, sendMax bool) (string, error)
+	SignMultisigPSBT(b64PSBT, privPass string) (string, error)
+	CombineMultisigPSBTs(b64PSBTs []string) (string, error)
+	MultisigPSBTInfo(b64PSBT string) (*sharedW.MultisigPSBTInfo, error)
+	BroadcastMultisigPSBT(b64PSBT string) (string, error)
+}
+
+// MultisigPage sets up a multisig wallet as an m-of-n multisig and creates,
+// signs and broadcasts the PSBTs that spend from it.
+type MultisigPage struct {
+	*load.Load
+	// GenericPageModal defines methods such as ID() and OnAttachedToNavigator()
+	// that helps this Page satisfy the app.Page interface. It also defines
+	// helper methods for accessing the PageNavigator that displayed this page
+	// and the root WindowNavigator.
+	*app.GenericPageModal
+
+	wallet multisigWallet
+
+	localXpub string
+	address   string
+	balance   string
+	psbtInfo  *sharedW.MultisigPSBTInfo
+	psbtErr   string
+	copyText  string
+
+	copyXpub       *cryptomaterial.Clickable
+	copyAddress    *cryptomaterial.Clickable
+	copyDescriptor *cryptomaterial.Clickable
+
+	createKeyBtn    cryptomaterial.Button
+	thresholdEditor cryptomaterial.Editor
+	cosignersEditor cryptomaterial.Editor
+	birthdayEditor  cryptomaterial.Editor
+	setupBtn        cryptomaterial.Button
+
+	destinationEditor cryptomaterial.Editor
+	amountEditor      cryptomaterial.Editor
+	createPSBTBtn     cryptomaterial.Button
+
+	psbtEditor   cryptomaterial.Editor
+	signBtn      cryptomaterial.Button
+	combineBtn   cryptomaterial.Button
+	broadcastBtn cryptomaterial.Button
+
+	scrollbarList *widget.List
+	backButton    cryptomaterial.IconButton
+}
+
+// NewMultisigPage returns the multisig page of the provided multisig wallet.
+func NewMultisigPage(l *load.Load, wallet sharedW.Asset) *MultisigPage {
+	pg := &MultisigPage{
+		Load:             l,
+		GenericPageModal: app.NewGenericPageModal(MultisigPageID),
+		copyXpub:         l.Theme.NewClickable(true),
+		copyAddress:      l.Theme.NewClickable(true),
+		copyDescriptor:   l.Theme.NewClickable(true),
+		scrollbarList: &widget.List{
+			List: layout.List{Axis: layout.Vertical},
+		},
+	}
+	pg.wallet, _ = wallet.(multisigWallet)
+
+	pg.backButton, _ = components.SubpageHeaderButtons(l)
+
+	singleLineEditor := func(hint string) cryptomaterial.Editor {
+		editor := l.Theme.Editor(new(widget.Editor), hint)
+		editor.Editor.SingleLine = true
+		return editor
+	}
+
+	pg.thresholdEditor = singleLineEditor(values.String(values.StrRequiredSignatures))
+	pg.cosignersEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrCosignerKeys))
+	pg.birthdayEditor = singleLineEditor(values.String(values.StrBirthdayHint))
+	pg.destinationEditor = singleLineEditor(values.String(values.StrDestAddr))
+	pg.amountEditor = singleLineEditor(values.String(values.StrAmount))
+	pg.psbtEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrPSBTHint))
+
+	button := func(text string, outline bool) cryptomaterial.Button {
+		btn := l.Theme.Button(text)
+		if outline {
+			btn = l.Theme.OutlineButton(text)
+		}
+		btn.Font.Weight = font.Medium
+		return btn
+	}
+
+	pg.createKeyBtn = button(values.String(values.StrCreateMultisigKey), false)
+	pg.setupBtn = button(values.String(values.StrSetupMultisig), false)
+	pg.createPSBTBtn = button(values.String(values.StrCreatePSBT), false)
+	pg.signBtn = button(values.String(values.StrSignPSBT), true)
+	pg.combineBtn = button(values.String(values.StrCombinePSBTs), true)
+	pg.broadcastBtn = button(values.String(values.StrBroadcast), false)
+
+	return pg
+}
+
+// OnNavigatedTo is called when the page is about to be displayed and
+// may be used to initialize page features that are only relevant when
+// the page is displayed.
+// Part of the load.Page interface.
+func (pg *MultisigPage) OnNavigatedTo() {
+	pg.refresh()
+}
+
+// refresh reloads the multisig key, address and balance of the wallet.
+func (pg *MultisigPage) refresh() {
+	if pg.wallet == nil {
+		return
+	}
+
+	pg.localXpub, _ = pg.wallet.MultisigXpub()
+	if !pg.wallet.IsMultisig() {
+		return
+	}
+
+	pg.address, _ = pg.wallet.CurrentMultisigAddress()
+	pg.balance = "-"
+	if balance, err := pg.wallet.MultisigBalance(); err == nil {
+		pg.balance = balance.String()
+	}
+}
+
+// Layout draws the page UI components into the provided C
+// to be eventually drawn on screen.
+// Part of the load.Page interface.
+func (pg *MultisigPage) Layout(gtx C) D {
+	if pg.copyText != "" {
+		clipboard.WriteOp{Text: pg.copyText}.Add(gtx.Ops)
+		pg.copyText = ""
+		pg.Toast.Notify(values.String(values.StrCopied))
+	}
+
+	body := func(gtx C) D {
+		sp := components.SubPage{
+			Load:       pg.Load,
+			Title:      values.String(values.StrMultisig),
+			BackButton: pg.backButton,
+			Back: func() {
+				pg.ParentNavigator().CloseCurrentPage()
+			},
+			Body: func(gtx C) D {
+				return pg.Theme.List(pg.scrollbarList).Layout(gtx, 1, func(gtx C, _ int) D {
+					if pg.wallet != nil && pg.wallet.IsMultisig() {
+						return pg.layoutMultisig(gtx)
+					}
+					return pg.layoutSetup(gtx)
+				})
+			},
+		}
+		return sp.Layout(pg.ParentWindow(), gtx)
+	}
+
+	if pg.Load.GetCurrentAppWidth() <= gtx.Dp(values.StartMobileView) {
+		return components.UniformMobile(gtx, false, false, body)
+	}
+	return body(gtx)
+}
+
+func (pg *MultisigPage) card(gtx C, widgets ...layout.Widget) D {
+	children := make([]layout.FlexChild, 0, len(widgets))
+	for _, w := range widgets {
+		w := w
+		children = append(children, layout.Rigid(func(gtx C) D {
+			return layout.Inset{Bottom: values.MarginPadding15}.Layout(gtx, w)
+		}))
+	}
+
+	return layout.Inset{Top: values.MarginPadding5, Bottom: values.MarginPadding10}.Layout(gtx, func(gtx C) D {
+		return pg.Theme.Card().Layout(gtx, func(gtx C) D {
+			return layout.UniformInset(values.MarginPadding15).Layout(gtx, func(gtx C) D {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+			})
+		})
+	})
+}
+
+func (pg *MultisigPage) caption(text string) layout.Widget {
+	return func(gtx C) D {
+		lbl := pg.Theme.Caption(text)
+		lbl.Color = pg.Theme.Color.GrayText2
+		return lbl.Layout(gtx)
+	}
+}
+
+func (pg *MultisigPage) copyable(clickable *cryptomaterial.Clickable, text string) layout.Widget {
+	return func(gtx C) D {
+		return clickable.Layout(gtx, func(gtx C) D {
+			lbl := pg.Theme.Body2(text)
+			lbl.Color = pg.Theme.Color.Primary
+			return lbl.Layout(gtx)
+		})
+	}
+}
+
+func (pg *MultisigPage) layoutSetup(gtx C) D {
+	xpub := func(gtx C) D {
+		if pg.localXpub == "" {
+			return pg.createKeyBtn.Layout(gtx)
+		}
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(pg.caption(values.String(values.StrMultisigKey))),
+			layout.Rigid(pg.copyable(pg.copyXpub, pg.localXpub)),
+		)
+	}
+
+	return pg.card(gtx,
+		pg.caption(values.String(values.StrMultisigNote)),
+		xpub,
+		pg.thresholdEditor.Layout,
+		pg.cosignersEditor.Layout,
+		pg.birthdayEditor.Layout,
+		func(gtx C) D {
+			return layout.E.Layout(gtx, pg.setupBtn.Layout)
+		},
+	)
+}
+
+func (pg *MultisigPage) layoutMultisig(gtx C) D {
+	cfg := pg.wallet.MultisigConfig()
+	policy := values.StringF(values.StrMultisigPolicy, cfg.Threshold, len(cfg.Xpubs()))
+
+	info := func(gtx C) D {
+		if pg.psbtErr != "" {
+			lbl := pg.Theme.Body2(pg.psbtErr)
+			lbl.Color = pg.Theme.Color.Danger
+			return lbl.Layout(gtx)
+		}
+		if pg.psbtInfo == nil {
+			return D{}
+		}
+
+		row := func(title, value string) layout.FlexChild {
+			return layout.Rigid(func(gtx C) D {
+				l := pg.Theme.Body2(title)
+				l.Color = pg.Theme.Color.GrayText2
+				r := pg.Theme.Body2(value)
+				return layout.Inset{Top: values.MarginPadding4}.Layout(gtx, func(gtx C) D {
+					return components.EndToEndRow(gtx, l.Layout, r.Layout)
+				})
+			})
+		}
+
+		rows := []layout.FlexChild{
+			row(values.String(values.StrTransactionID), pg.psbtInfo.TxHash),
+			row(values.String(values.StrFee), pg.psbtInfo.Fee.String()),
+			row(values.String(values.StrPSBTFeeRate), pg.psbtInfo.FeeRate.String()),
+			row(values.String(values.StrMultisig), values.StringF(values.StrPSBTSignatures, pg.psbtInfo.Signatures, pg.psbtInfo.Threshold)),
+		}
+		for address, amount := range pg.psbtInfo.Outputs {
+			rows = append(rows, row(address, amount.String()))
+		}
+		for address, amount := range pg.psbtInfo.Change {
+			rows = append(rows, row(values.String(values.StrMultisigChange)+" "+address, amount.String()))
+		}
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, rows...)
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D {
+			return pg.card(gtx,
+				pg.caption(policy),
+				func(gtx C) D {
+					l := pg.Theme.Body2(values.String(values.StrBalance))
+					l.Color = pg.Theme.Color.GrayText2
+					return components.EndToEndRow(gtx, l.Layout, pg.Theme.Body2(pg.balance).Layout)
+				},
+				pg.caption(values.String(values.StrMultisigAddress)),
+				pg.copyable(pg.copyAddress, pg.address),
+				pg.caption(values.String(values.StrMultisigKey)),
+				pg.copyable(pg.copyXpub, pg.localXpub),
+				pg.caption(values.String(values.StrMultisigDescriptor)),
+				pg.copyable(pg.copyDescriptor, cfg.Descriptor),
+			)
+		}),
+		layout.Rigid(func(gtx C) D {
+			return pg.card(gtx,
+				pg.destinationEditor.Layout,
+				pg.amountEditor.Layout,
+				func(gtx C) D {
+					return layout.E.Layout(gtx, pg.createPSBTBtn.Layout)
+				},
+			)
+		}),
+		layout.Rigid(func(gtx C) D {
+			return pg.card(gtx,
+				pg.psbtEditor.Layout,
+				info,
+				func(gtx C) D {
+					return layout.E.Layout(gtx, func(gtx C) D {
+						return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+							layout.Rigid(func(gtx C) D {
+								return layout.Inset{Right: values.MarginPadding10}.Layout(gtx, pg.signBtn.Layout)
+							}),
+							layout.Rigid(func(gtx C) D {
+								return layout.Inset{Right: values.MarginPadding10}.Layout(gtx, pg.combineBtn.Layout)
+							}),
+							layout.Rigid(pg.broadcastBtn.Layout),
+						)
+					})
+				},
+			)
+		}),
+	)
+}
+
+// HandleUserInteractions is called just before Layout() to determine
+// if any user interaction recently occurred on the page and may be
+// used to update the page's UI components shortly before they are
+// displayed.
+// Part of the load.Page interface.
+func (pg *MultisigPage) HandleUserInteractions() {
+	if pg.wallet == nil {
+		return
+	}
+
+	if pg.copyXpub.Clicked() {
+		pg.copyText = pg.localXpub
+	}
+
+	if pg.copyAddress.Clicked() {
+		pg.copyText = pg.address
+	}
+
+	if pg.copyDescriptor.Clicked() {
+		pg.copyText = pg.wallet.MultisigConfig().Descriptor
+	}
+
+	if pg.createKeyBtn.Clicked() {
+		pg.withPassword(values.String(values.StrCreateMultisigKey), func(password string) error {
+			return pg.wallet.CreateMultisigAccount(password)
+		})
+	}
+
+	pg.setupBtn.SetEnabled(pg.localXpub != "" &&
+		utils.EditorsNotEmpty(pg.thresholdEditor.Editor, pg.cosignersEditor.Editor, pg.birthdayEditor.Editor))
+	if pg.setupBtn.Clicked() {
+		pg.setupMultisig()
+	}
+
+	pg.createPSBTBtn.SetEnabled(utils.EditorsNotEmpty(pg.destinationEditor.Editor, pg.amountEditor.Editor))
+	if pg.createPSBTBtn.Clicked() {
+		pg.createPSBT()
+	}
+
+	for _, e := range pg.psbtEditor.Editor.Events() {
+		if _, ok := e.(widget.ChangeEvent); ok {
+			pg.updatePSBTInfo()
+		}
+	}
+
+	hasPSBT := utils.EditorsNotEmpty(pg.psbtEditor.Editor)
+	pg.signBtn.SetEnabled(pg.psbtInfo != nil && !pg.wallet.IsWatchingOnlyWallet())
+	pg.combineBtn.SetEnabled(len(pg.psbts()) > 1)
+	pg.broadcastBtn.SetEnabled(hasPSBT)
+
+	if pg.signBtn.Clicked() {
+		pg.confirmSign()
+	}
+
+	if pg.combineBtn.Clicked() {
+		combined, err := pg.wallet.CombineMultisigPSBTs(pg.psbts())
+		if err != nil {
+			pg.showError(err)
+		} else {
+			pg.ShowPSBT(combined)
+		}
+	}
+
+	if pg.broadcastBtn.Clicked() {
+		txHash, err := pg.wallet.BroadcastMultisigPSBT(pg.psbts()[0])
+		if err != nil {
+			pg.showError(err)
+			return
+		}
+
+		pg.psbtEditor.Editor.SetText("")
+		pg.updatePSBTInfo()
+		pg.refresh()
+		info := modal.NewSuccessModal(pg.Load, values.StringF(values.StrTxBroadcasted, txHash), modal.DefaultClickFunc())
+		pg.ParentWindow().ShowModal(info)
+	}
+}
+
+// psbts returns the PSBTs entered in the PSBT editor.
+func (pg *MultisigPage) psbts() []string {
+	return strings.Fields(pg.psbtEditor.Editor.Text())
+}
+
+// ShowPSBT displays the provided PSBT and copies it to the clipboard for it
+// to be shared with the cosigners.
+func (pg *MultisigPage) ShowPSBT(b64PSBT string) {
+	pg.psbtEditor.Editor.SetText(b64PSBT)
+	pg.updatePSBTInfo()
+	pg.copyText = b64PSBT
+}
+
+// updatePSBTInfo decodes the first PSBT entered in the PSBT editor. PSBTs
+// that cannot be signed are reported instead.
+func (pg *MultisigPage) updatePSBTInfo() {
+	pg.psbtInfo, pg.psbtErr = nil, ""
+	psbts := pg.psbts()
+	if len(psbts) == 0 {
+		return
+	}
+
+	info, err := pg.wallet.MultisigPSBTInfo(psbts[0])
+	if err != nil {
+		pg.psbtErr = err.Error()
+		return
+	}
+	pg.psbtInfo = info
+}
+
+// confirmSign shows what the PSBT pays and its fee for the user to confirm
+// before it is signed.
+func (pg *MultisigPage) confirmSign() {
+	info := pg.psbtInfo
+	outputs := make([]string, 0, len(info.Outputs))
+	for address, amount := range info.Outputs {
+		outputs = append(outputs, address+": "+amount.String())
+	}
+
+	confirmModal := modal.NewCustomModal(pg.Load).
+		Title(values.String(values.StrSignPSBT)).
+		Body(values.StringF(values.StrConfirmSignPSBT, strings.Join(outputs, ", "), info.Fee.String(), info.FeeRate.String())).
+		SetNegativeButtonText(values.String(values.StrCancel)).
+		SetPositiveButtonText(values.String(values.StrSignPSBT)).
+		SetPositiveButtonCallback(func(_ bool, _ *modal.InfoModal) bool {
+			pg.withPassword(values.String(values.StrSignPSBT), func(password string) error {
+				signed, err := pg.wallet.SignMultisigPSBT(pg.psbts()[0], password)
+				if err == nil {
+					pg.ShowPSBT(signed)
+				}
+				return err
+			})
+			return true
+		})
+	pg.ParentWindow().ShowModal(confirmModal)
+}
+
+func (pg *MultisigPage) setupMultisig() {
+	pg.thresholdEditor.SetError("")
+	threshold, err := strconv.Atoi(strings.TrimSpace(pg.thresholdEditor.Editor.Text()))
+	if err != nil {
+		pg.thresholdEditor.SetError(values.String(values.StrInvalidThreshold))
+		return
+	}
+
+	pg.birthdayEditor.SetError("")
+	birthday, err := time.Parse(birthdayLayout, strings.TrimSpace(pg.birthdayEditor.Editor.Text()))
+	if err != nil || birthday.After(time.Now()) {
+		pg.birthdayEditor.SetError(values.String(values.StrInvalidBirthday))
+		return
+	}
+
+	cosigners := strings.Fields(pg.cosignersEditor.Editor.Text())
+	pg.withPassword(values.String(values.StrSetupMultisig), func(password string) error {
+		return pg.wallet.SetupMultisig(int32(threshold), cosigners, password, birthday)
+	})
+}
+
+func (pg *MultisigPage) createPSBT() {
+	pg.amountEditor.SetError("")
+	amount, err := strconv.ParseFloat(strings.TrimSpace(pg.amountEditor.Editor.Text()), 64)
+	if err != nil || amount <= 0 {
+		pg.amountEditor.SetError(values.String(values.StrInvalidAmount))
+		return
+	}
+
+	// BTC and LTC amounts have the same number of decimal places.
+	atoms, err := btcutil.NewAmount(amount)
+	if err != nil {
+		pg.amountEditor.SetError(values.String(values.StrInvalidAmount))
+		return
+	}
+
+	destination := strings.TrimSpace(pg.destinationEditor.Editor.Text())
+	b64PSBT, err := pg.wallet.CreateMultisigPSBT(destination, int64(atoms), false)
+	if err != nil {
+		pg.showError(err)
+		return
+	}
+
+	pg.destinationEditor.Editor.SetText("")
+	pg.amountEditor.Editor.SetText("")
+	pg.ShowPSBT(b64PSBT)
+}
+
+// withPassword requests the spending password and runs the provided action
+// with it. The page is refreshed once the action succeeds.
+func (pg *MultisigPage) withPassword(title string, action func(password string) error) {
+	passwordModal := modal.NewCreatePasswordModal(pg.Load).
+		EnableName(false).
+		EnableConfirmPassword(false).
+		Title(title).
+		PasswordHint(values.String(values.StrSpendingPassword)).
+		SetPositiveButtonCallback(func(_, password string, pm *modal.CreatePasswordModal) bool {
+			if err := action(password); err != nil {
+				pm.SetError(err.Error())
+				pm.SetLoading(false)
+				return false
+			}
+
+			pm.Dismiss()
+			pg.refresh()
+			return true
+		})
+	pg.ParentWindow().ShowModal(passwordModal)
+}
+
+func (pg *MultisigPage) showError(err error) {
+	errModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+	pg.ParentWindow().ShowModal(errModal)
+}
+
+// OnNavigatedFrom is called when the page is about to be removed from
+// the displayed window. This method should ideally be used to disable
+// features that are irrelevant when the page is NOT displayed.
+// NOTE: The page may be re-displayed on the app's window, in which case
+// OnNavigatedTo() will be called again. This method should not destroy UI
+// components unless they'll be recreated in the OnNavigatedTo() method.
+// Part of the load.Page interface.
+func (pg *MultisigPage) OnNavigatedFrom() {}
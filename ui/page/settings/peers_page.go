@@ -0,0 +1,517 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/widget"
+
+	"github.com/crypto-power/cryptopower/app"
+	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
+	libutils "github.com/crypto-power/cryptopower/libwallet/utils"
+	"github.com/crypto-power/cryptopower/ui/cryptomaterial"
+	"github.com/crypto-power/cryptopower/ui/load"
+	"github.com/crypto-power/cryptopower/ui/modal"
+	"github.com/crypto-power/cryptopower/ui/page/components"
+	"github.com/crypto-power/cryptopower/ui/values"
+)
+
+const PeersPageID = "Peers"
+
+// peersRefreshInterval is how often the connected peers are refreshed.
+const peersRefreshInterval = 3 * time.Second
+
+const (
+	banOneDay    = "day"
+	banOneWeek   = "week"
+	banPermanent = "permanent"
+)
+
+// peerRow is a configured peer with the button removing it.
+type peerRow struct {
+	address string
+	remove  cryptomaterial.Button
+}
+
+// banRow is a banned peer with the button lifting the ban.
+type banRow struct {
+	*sharedW.PeerBan
+	unban cryptomaterial.Button
+}
+
+// PeersPage manages the persistent, preferred and banned peers of the selected
+// wallet and shows the peers it is connected to.
+type PeersPage struct {
+	*load.Load
+	// GenericPageModal defines methods such as ID() and OnAttachedToNavigator()
+	// that helps this Page satisfy the app.Page interface. It also defines
+	// helper methods for accessing the PageNavigator that displayed this page
+	// and the root WindowNavigator.
+	*app.GenericPageModal
+
+	ctx       context.Context // page context
+	ctxCancel context.CancelFunc
+
+	wallet sharedW.Asset
+	cfg    *sharedW.PeerConfig
+
+	persistent []*peerRow
+	preferred  []*peerRow
+	bans       []*banRow
+
+	peersMtx sync.Mutex
+	peers    []sharedW.PeerInfo
+	// banPeerBtns prefill the ban form with a connected peer, keyed by its
+	// address.
+	banPeerBtns map[string]cryptomaterial.Button
+
+	persistentEditor cryptomaterial.Editor
+	preferredEditor  cryptomaterial.Editor
+	banAddrEditor    cryptomaterial.Editor
+	banReasonEditor  cryptomaterial.Editor
+	banDuration      *widget.Enum
+
+	addPersistentBtn cryptomaterial.Button
+	addPreferredBtn  cryptomaterial.Button
+	banBtn           cryptomaterial.Button
+	backButton       cryptomaterial.IconButton
+
+	scrollContainer *widget.List
+}
+
+func NewPeersPage(l *load.Load) *PeersPage {
+	pg := &PeersPage{
+		Load:             l,
+		GenericPageModal: app.NewGenericPageModal(PeersPageID),
+		wallet:           l.WL.SelectedWallet.Wallet,
+		banPeerBtns:      make(map[string]cryptomaterial.Button),
+		banDuration:      &widget.Enum{Value: banOneDay},
+		scrollContainer: &widget.List{
+			List: layout.List{Axis: layout.Vertical},
+		},
+	}
+
+	pg.backButton, _ = components.SubpageHeaderButtons(l)
+
+	newEditor := func(hint string) cryptomaterial.Editor {
+		editor := l.Theme.Editor(new(widget.Editor), hint)
+		editor.Editor.SingleLine = true
+		return editor
+	}
+	pg.persistentEditor = newEditor(values.String(values.StrPeerAddressHint))
+	pg.preferredEditor = newEditor(values.String(values.StrPeerAddressHint))
+	pg.banAddrEditor = newEditor(values.String(values.StrPeerAddressHint))
+	pg.banReasonEditor = newEditor(values.String(values.StrBanReason))
+
+	newButton := func(text string) cryptomaterial.Button {
+		btn := l.Theme.Button(text)
+		btn.Font.Weight = font.Medium
+		return btn
+	}
+	pg.addPersistentBtn = newButton(values.String(values.StrAdd))
+	pg.addPreferredBtn = newButton(values.String(values.StrAdd))
+	pg.banBtn = newButton(values.String(values.StrBan))
+
+	return pg
+}
+
+// OnNavigatedTo is called when the page is about to be displayed and
+// may be used to initialize page features that are only relevant when
+// the page is displayed.
+// Part of the load.Page interface.
+func (pg *PeersPage) OnNavigatedTo() {
+	pg.loadConfig()
+
+	pg.ctx, pg.ctxCancel = context.WithCancel(context.TODO())
+	go pg.refreshPeers(pg.ctx)
+}
+
+// loadConfig reads the peer configuration of the wallet.
+func (pg *PeersPage) loadConfig() {
+	pg.cfg = pg.wallet.PeerConfig()
+
+	newRows := func(addresses []string) []*peerRow {
+		rows := make([]*peerRow, 0, len(addresses))
+		for _, address := range addresses {
+			rows = append(rows, &peerRow{
+				address: address,
+				remove:  pg.Theme.OutlineButton(values.String(values.StrRemove)),
+			})
+		}
+		return rows
+	}
+	pg.persistent = newRows(pg.cfg.Persistent)
+	pg.preferred = newRows(pg.cfg.Preferred)
+
+	pg.bans = make([]*banRow, 0, len(pg.cfg.Bans))
+	for _, ban := range pg.cfg.Bans {
+		pg.bans = append(pg.bans, &banRow{
+			PeerBan: ban,
+			unban:   pg.Theme.OutlineButton(values.String(values.StrUnban)),
+		})
+	}
+}
+
+// refreshPeers reloads the connected peers until ctx is canceled.
+func (pg *PeersPage) refreshPeers(ctx context.Context) {
+	ticker := time.NewTicker(peersRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		peers, err := pg.wallet.PeerInfoRaw()
+		if err != nil {
+			peers = nil
+		}
+
+		pg.peersMtx.Lock()
+		pg.peers = peers
+		pg.peersMtx.Unlock()
+		pg.ParentWindow().Reload()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (pg *PeersPage) connectedPeers() []sharedW.PeerInfo {
+	pg.peersMtx.Lock()
+	defer pg.peersMtx.Unlock()
+	return pg.peers
+}
+
+// Layout draws the page UI components into the provided C
+// to be eventually drawn on screen.
+// Part of the load.Page interface.
+func (pg *PeersPage) Layout(gtx C) D {
+	body := func(gtx C) D {
+		sp := components.SubPage{
+			Load:       pg.Load,
+			Title:      values.String(values.StrPeers),
+			BackButton: pg.backButton,
+			Back: func() {
+				pg.ParentNavigator().CloseCurrentPage()
+			},
+			Body: pg.layoutContent,
+		}
+		return sp.Layout(pg.ParentWindow(), gtx)
+	}
+
+	if pg.Load.GetCurrentAppWidth() <= gtx.Dp(values.StartMobileView) {
+		return components.UniformMobile(gtx, false, false, body)
+	}
+	return body(gtx)
+}
+
+func (pg *PeersPage) layoutContent(gtx C) D {
+	sections := []layout.Widget{
+		pg.peersSection(values.StrPersistentPeers, values.StrPersistentPeersNote, pg.persistent, pg.persistentEditor, pg.addPersistentBtn),
+		pg.peersSection(values.StrPreferredPeers, values.StrPreferredPeersNote, pg.preferred, pg.preferredEditor, pg.addPreferredBtn),
+		pg.bansSection,
+		pg.connectedPeersSection,
+	}
+
+	return pg.Theme.List(pg.scrollContainer).Layout(gtx, len(sections), func(gtx C, i int) D {
+		return layout.Inset{Top: values.MarginPadding5, Bottom: values.MarginPadding10}.Layout(gtx, sections[i])
+	})
+}
+
+// section lays out a card with a title, a note and its content.
+func (pg *PeersPage) section(gtx C, title, note string, content ...layout.FlexChild) D {
+	children := []layout.FlexChild{
+		layout.Rigid(pg.Theme.Body1(title).Layout),
+		layout.Rigid(func(gtx C) D {
+			desc := pg.Theme.Caption(note)
+			desc.Color = pg.Theme.Color.GrayText2
+			return layout.Inset{Top: values.MarginPadding5, Bottom: values.MarginPadding5}.Layout(gtx, desc.Layout)
+		}),
+	}
+	children = append(children, content...)
+
+	return pg.Theme.Card().Layout(gtx, func(gtx C) D {
+		return layout.UniformInset(values.MarginPadding15).Layout(gtx, func(gtx C) D {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+		})
+	})
+}
+
+// row lays out a line of text and a button at the end of the line.
+func (pg *PeersPage) row(text, detail string, btn cryptomaterial.Button) layout.FlexChild {
+	return layout.Rigid(func(gtx C) D {
+		return layout.Inset{Top: values.MarginPadding10}.Layout(gtx, func(gtx C) D {
+			return components.EndToEndRow(gtx, func(gtx C) D {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(pg.Theme.Body2(text).Layout),
+					layout.Rigid(func(gtx C) D {
+						if detail == "" {
+							return D{}
+						}
+						lbl := pg.Theme.Caption(detail)
+						lbl.Color = pg.Theme.Color.GrayText2
+						return lbl.Layout(gtx)
+					}),
+				)
+			}, btn.Layout)
+		})
+	})
+}
+
+func (pg *PeersPage) emptyRow() layout.FlexChild {
+	return layout.Rigid(func(gtx C) D {
+		lbl := pg.Theme.Body2(values.String(values.StrNoPeers))
+		lbl.Color = pg.Theme.Color.GrayText3
+		return layout.Inset{Top: values.MarginPadding10}.Layout(gtx, lbl.Layout)
+	})
+}
+
+func (pg *PeersPage) peersSection(title, note string, rows []*peerRow, editor cryptomaterial.Editor, addBtn cryptomaterial.Button) layout.Widget {
+	return func(gtx C) D {
+		children := make([]layout.FlexChild, 0, len(rows)+2)
+		for _, row := range rows {
+			children = append(children, pg.row(row.address, "", row.remove))
+		}
+		if len(rows) == 0 {
+			children = append(children, pg.emptyRow())
+		}
+
+		noteText := values.String(note)
+		if pg.isPrivacyModeOn() {
+			noteText = values.String(values.StrPeersPrivacyModeNote)
+		}
+		children = append(children, layout.Rigid(func(gtx C) D {
+			if pg.isPrivacyModeOn() {
+				return D{}
+			}
+			return layout.Inset{Top: values.MarginPadding15}.Layout(gtx, func(gtx C) D {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Flexed(1, editor.Layout),
+					layout.Rigid(func(gtx C) D {
+						return layout.Inset{Left: values.MarginPadding10}.Layout(gtx, addBtn.Layout)
+					}),
+				)
+			})
+		}))
+		return pg.section(gtx, values.String(title), noteText, children...)
+	}
+}
+
+func (pg *PeersPage) bansSection(gtx C) D {
+	children := make([]layout.FlexChild, 0, len(pg.bans)+5)
+	for _, ban := range pg.bans {
+		detail := ban.Reason
+		expiry := values.String(values.StrBanPermanent)
+		if !ban.IsPermanent() {
+			expiry = values.StringF(values.StrBannedUntil, time.Unix(ban.Expiry, 0).Format("2006-01-02 15:04"))
+		}
+		if detail != "" {
+			detail += " · "
+		}
+		children = append(children, pg.row(ban.Host, detail+expiry, ban.unban))
+	}
+	if len(pg.bans) == 0 {
+		children = append(children, pg.emptyRow())
+	}
+
+	field := func(w layout.Widget) layout.FlexChild {
+		return layout.Rigid(func(gtx C) D {
+			return layout.Inset{Top: values.MarginPadding15}.Layout(gtx, w)
+		})
+	}
+	durations := []layout.FlexChild{}
+	for _, d := range []struct{ key, label string }{
+		{banOneDay, values.StrBanOneDay},
+		{banOneWeek, values.StrBanOneWeek},
+		{banPermanent, values.StrBanPermanent},
+	} {
+		radioBtn := pg.Theme.RadioButton(pg.banDuration, d.key, values.String(d.label),
+			pg.Theme.Color.DeepBlue, pg.Theme.Color.Primary)
+		durations = append(durations, layout.Rigid(func(gtx C) D {
+			return layout.Inset{Right: values.MarginPadding20}.Layout(gtx, radioBtn.Layout)
+		}))
+	}
+	children = append(children,
+		field(pg.banAddrEditor.Layout),
+		field(pg.banReasonEditor.Layout),
+		field(func(gtx C) D {
+			return layout.Flex{Axis: layout.Horizontal}.Layout(gtx, durations...)
+		}),
+		field(func(gtx C) D {
+			return layout.E.Layout(gtx, pg.banBtn.Layout)
+		}),
+	)
+
+	note := values.String(values.StrBannedPeersNote)
+	if pg.wallet.GetAssetType() != libutils.DCRWalletAsset {
+		note += " " + values.String(values.StrSharedPeersNote)
+	}
+	return pg.section(gtx, values.String(values.StrBannedPeers), note, children...)
+}
+
+func (pg *PeersPage) connectedPeersSection(gtx C) D {
+	peers := pg.connectedPeers()
+	children := make([]layout.FlexChild, 0, len(peers)+1)
+	for _, peer := range peers {
+		btn, ok := pg.banPeerBtns[peer.Addr]
+		if !ok {
+			btn = pg.Theme.OutlineButton(values.String(values.StrBan))
+			pg.banPeerBtns[peer.Addr] = btn
+		}
+
+		detail := fmt.Sprintf("%s · %s %d · %s %d ms · %s %s / %s",
+			peer.SubVer,
+			values.String(values.StrBestBlocks), peer.BestHeight,
+			values.String(values.StrLatency), peer.LatencyMs,
+			values.String(values.StrBytesSentReceived), formatBytes(peer.BytesSent), formatBytes(peer.BytesReceived))
+		children = append(children, pg.row(peer.Addr, detail, btn))
+	}
+	if len(peers) == 0 {
+		children = append(children, pg.emptyRow())
+	}
+
+	title := values.String(values.StrPeersConnected)
+	return pg.section(gtx, title, fmt.Sprintf("%d", len(peers)), children...)
+}
+
+// formatBytes formats n bytes with a binary unit.
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func (pg *PeersPage) isPrivacyModeOn() bool {
+	return pg.WL.AssetsManager.IsPrivacyModeOn()
+}
+
+// HandleUserInteractions is called just before Layout() to determine
+// if any user interaction recently occurred on the page and may be
+// used to update the page's UI components shortly before they are
+// displayed.
+// Part of the load.Page interface.
+func (pg *PeersPage) HandleUserInteractions() {
+	pg.addPersistentBtn.SetEnabled(strings.TrimSpace(pg.persistentEditor.Editor.Text()) != "")
+	pg.addPreferredBtn.SetEnabled(strings.TrimSpace(pg.preferredEditor.Editor.Text()) != "")
+	pg.banBtn.SetEnabled(strings.TrimSpace(pg.banAddrEditor.Editor.Text()) != "")
+
+	if pg.addPersistentBtn.Clicked() && !pg.isPrivacyModeOn() {
+		pg.addPeer(&pg.cfg.Persistent, &pg.persistentEditor)
+	}
+	if pg.addPreferredBtn.Clicked() && !pg.isPrivacyModeOn() {
+		pg.addPeer(&pg.cfg.Preferred, &pg.preferredEditor)
+	}
+
+	for _, row := range pg.persistent {
+		if row.remove.Clicked() {
+			pg.cfg.Persistent = removeAddress(pg.cfg.Persistent, row.address)
+			pg.saveConfig()
+			return
+		}
+	}
+	for _, row := range pg.preferred {
+		if row.remove.Clicked() {
+			pg.cfg.Preferred = removeAddress(pg.cfg.Preferred, row.address)
+			pg.saveConfig()
+			return
+		}
+	}
+	for _, row := range pg.bans {
+		if row.unban.Clicked() {
+			pg.cfg.Unban(row.Host)
+			pg.saveConfig()
+			return
+		}
+	}
+
+	for addr, btn := range pg.banPeerBtns {
+		if btn.Clicked() {
+			pg.banAddrEditor.Editor.SetText(sharedW.PeerHost(addr))
+		}
+	}
+
+	if pg.banBtn.Clicked() {
+		var duration time.Duration
+		switch pg.banDuration.Value {
+		case banOneDay:
+			duration = 24 * time.Hour
+		case banOneWeek:
+			duration = 7 * 24 * time.Hour
+		}
+		address := strings.TrimSpace(pg.banAddrEditor.Editor.Text())
+		reason := strings.TrimSpace(pg.banReasonEditor.Editor.Text())
+		if err := pg.cfg.Ban(address, reason, duration); err != nil {
+			pg.banAddrEditor.SetError(err.Error())
+			return
+		}
+		if pg.saveConfig() {
+			pg.banAddrEditor.Editor.SetText("")
+			pg.banReasonEditor.Editor.SetText("")
+		}
+	}
+}
+
+// addPeer adds the address entered in editor to list and saves the peer
+// configuration.
+func (pg *PeersPage) addPeer(list *[]string, editor *cryptomaterial.Editor) {
+	address := strings.TrimSpace(editor.Editor.Text())
+	if libutils.IsOnionHost(sharedW.PeerHost(address)) && !pg.WL.AssetsManager.IsProxied() {
+		editor.SetError(values.String(values.StrOnionPeerRequiresProxy))
+		return
+	}
+	// Adding a banned peer lifts its ban.
+	pg.cfg.Unban(address)
+
+	*list = append(*list, address)
+	if pg.saveConfig() {
+		editor.Editor.SetText("")
+	}
+}
+
+// saveConfig saves the edited peer configuration and reloads it from the
+// wallet, discarding the edits if they are invalid.
+func (pg *PeersPage) saveConfig() bool {
+	err := pg.wallet.SetPeerConfig(pg.cfg)
+	pg.loadConfig()
+	if err != nil {
+		errModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+		pg.ParentWindow().ShowModal(errModal)
+		return false
+	}
+	return true
+}
+
+func removeAddress(addresses []string, address string) []string {
+	kept := make([]string, 0, len(addresses))
+	for _, a := range addresses {
+		if a != address {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
+
+// OnNavigatedFrom is called when the page is about to be removed from
+// the displayed window. This method should ideally be used to disable
+// features that are irrelevant when the page is NOT displayed.
+// NOTE: The page may be re-displayed on the app's window, in which case
+// OnNavigatedTo() will be called again. This method should not destroy UI
+// components unless they'll be recreated in the OnNavigatedTo() method.
+// Part of the load.Page interface.
+func (pg *PeersPage) OnNavigatedFrom() {
+	if pg.ctxCancel != nil {
+		pg.ctxCancel()
+	}
+}
@@ -0,0 +1,128 @@
+package settings
+
+import (
+	"strings"
+
+	libutils "github.com/crypto-power/cryptopower/libwallet/utils"
+	"github.com/crypto-power/cryptopower/ui/modal"
+	"github.com/crypto-power/cryptopower/ui/values"
+)
+
+func (pg *SettingPage) proxyRow(gtx C) D {
+	label := values.String(values.StrNotProxied)
+	if proxy := pg.WL.AssetsManager.ProxyConfig(); proxy != nil {
+		label = proxy.Address
+	}
+	proxyRow := row{
+		title:     values.String(values.StrProxy),
+		clickable: pg.proxy,
+		label:     pg.Theme.Body2(label),
+	}
+	return pg.clickableRow(gtx, proxyRow)
+}
+
+func (pg *SettingPage) isolationRow(gtx C) D {
+	if !pg.WL.AssetsManager.IsProxied() {
+		return D{}
+	}
+	return pg.subSectionSwitch(gtx, values.String(values.StrStreamIsolation), pg.isolation)
+}
+
+func (pg *SettingPage) proxyDNSServerRow(gtx C) D {
+	proxy := pg.WL.AssetsManager.ProxyConfig()
+	if proxy == nil {
+		return D{}
+	}
+	label := values.String(values.StrResolvedByProxy)
+	if proxy.DNSServer != "" {
+		label = proxy.DNSServer
+	}
+	dnsRow := row{
+		title:     values.String(values.StrProxyDNSServer),
+		clickable: pg.proxyDNSServer,
+		label:     pg.Theme.Body2(label),
+	}
+	return pg.clickableRow(gtx, dnsRow)
+}
+
+// proxyDNSServerModal asks for the DNS server that resolves host names
+// through proxies that cannot resolve them, unlike Tor. An empty address
+// leaves the resolution to the proxy alone.
+func (pg *SettingPage) proxyDNSServerModal() {
+	current := pg.WL.AssetsManager.ProxyConfig()
+	if current == nil {
+		return
+	}
+	textModal := modal.NewTextInputModal(pg.Load).
+		Hint(values.String(values.StrProxyDNSServerAddress)).
+		PositiveButtonStyle(pg.Load.Theme.Color.Primary, pg.Load.Theme.Color.InvText).
+		SetPositiveButtonCallback(func(address string, tm *modal.TextInputModal) bool {
+			current.DNSServer = strings.TrimSpace(address)
+			if err := pg.WL.AssetsManager.SetProxy(current); err != nil {
+				tm.SetError(err.Error())
+				tm.SetLoading(false)
+				return false
+			}
+			tm.Dismiss()
+
+			pg.showNoticeSuccess(values.String(values.StrProxyUpdated))
+			return true
+		})
+	textModal.SetText(current.DNSServer)
+	textModal.Title(values.String(values.StrProxyDNSServer)).
+		SetPositiveButtonText(values.String(values.StrSave)).
+		SetNegativeButtonText(values.String(values.StrCancel))
+	pg.ParentWindow().ShowModal(textModal)
+}
+
+// proxySettingsModal asks for the address of the SOCKS5 proxy all network
+// traffic is routed through. An empty address removes the proxy.
+func (pg *SettingPage) proxySettingsModal() {
+	current := pg.WL.AssetsManager.ProxyConfig()
+	textModal := modal.NewTextInputModal(pg.Load).
+		Hint(values.String(values.StrProxyAddress)).
+		PositiveButtonStyle(pg.Load.Theme.Color.Primary, pg.Load.Theme.Color.InvText).
+		SetPositiveButtonCallback(func(address string, tm *modal.TextInputModal) bool {
+			var cfg *libutils.ProxyConfig
+			if address = strings.TrimSpace(address); address != "" {
+				cfg = &libutils.ProxyConfig{Address: address}
+				if current != nil {
+					cfg.Username = current.Username
+					cfg.Password = current.Password
+					cfg.StreamIsolation = current.StreamIsolation
+					cfg.DNSServer = current.DNSServer
+				}
+			}
+
+			if err := pg.WL.AssetsManager.SetProxy(cfg); err != nil {
+				tm.SetError(err.Error())
+				tm.SetLoading(false)
+				return false
+			}
+			tm.Dismiss()
+
+			pg.updatePrivacySettings()
+			pg.showNoticeSuccess(values.String(values.StrProxyUpdated))
+			return true
+		})
+	if current != nil {
+		textModal.SetText(current.Address)
+	}
+	textModal.Title(values.String(values.StrProxy)).
+		SetPositiveButtonText(values.String(values.StrSave)).
+		SetNegativeButtonText(values.String(values.StrCancel))
+	pg.ParentWindow().ShowModal(textModal)
+}
+
+func (pg *SettingPage) setStreamIsolation(isolate bool) {
+	cfg := pg.WL.AssetsManager.ProxyConfig()
+	if cfg == nil {
+		return
+	}
+	cfg.StreamIsolation = isolate
+	if err := pg.WL.AssetsManager.SetProxy(cfg); err != nil {
+		errModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+		pg.ParentWindow().ShowModal(errModal)
+		pg.isolation.SetChecked(!isolate)
+	}
+}
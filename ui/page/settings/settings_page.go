@@ -5,6 +5,7 @@ import (
 	"gioui.org/widget"
 
 	"github.com/crypto-power/cryptopower/app"
+	"github.com/crypto-power/cryptopower/libwallet"
 	sharedW "github.com/crypto-power/cryptopower/libwallet/assets/wallet"
 	libutils "github.com/crypto-power/cryptopower/libwallet/utils"
 	"github.com/crypto-power/cryptopower/logger"
@@ -53,12 +54,24 @@ type SettingPage struct {
 	networkInfoButton       cryptomaterial.IconButton
 	logLevel                *cryptomaterial.Clickable
 	viewLog                 *cryptomaterial.Clickable
+	exportBackup            *cryptomaterial.Clickable
+	importBackup            *cryptomaterial.Clickable
+	proxy                   *cryptomaterial.Clickable
+	proxyDNSServer          *cryptomaterial.Clickable
+	maxConcurrentSyncs      *cryptomaterial.Clickable
 
 	governanceAPI *cryptomaterial.Switch
 	exchangeAPI   *cryptomaterial.Switch
 	feeRateAPI    *cryptomaterial.Switch
 	vspAPI        *cryptomaterial.Switch
 	privacyActive *cryptomaterial.Switch
+	isolation     *cryptomaterial.Switch
+
+	syncSequentially *cryptomaterial.Switch
+	retryFailedSyncs *cryptomaterial.Switch
+
+	devMode     *cryptomaterial.Switch
+	devServices map[libutils.DevService]*cryptomaterial.Clickable
 
 	isDarkModeOn      bool
 	isStartupPassword bool
@@ -79,20 +92,29 @@ func NewSettingsPage(l *load.Load) *SettingPage {
 		feeRateAPI:              l.Theme.Switch(),
 		vspAPI:                  l.Theme.Switch(),
 		privacyActive:           l.Theme.Switch(),
-
-		changeStartupPass: l.Theme.NewClickable(false),
-		language:          l.Theme.NewClickable(false),
-		currency:          l.Theme.NewClickable(false),
-		help:              l.Theme.NewClickable(false),
-		about:             l.Theme.NewClickable(false),
-		appearanceMode:    l.Theme.NewClickable(false),
-		logLevel:          l.Theme.NewClickable(false),
-		viewLog:           l.Theme.NewClickable(false),
+		isolation:               l.Theme.Switch(),
+		syncSequentially:        l.Theme.Switch(),
+		retryFailedSyncs:        l.Theme.Switch(),
+
+		changeStartupPass:  l.Theme.NewClickable(false),
+		language:           l.Theme.NewClickable(false),
+		currency:           l.Theme.NewClickable(false),
+		help:               l.Theme.NewClickable(false),
+		about:              l.Theme.NewClickable(false),
+		appearanceMode:     l.Theme.NewClickable(false),
+		logLevel:           l.Theme.NewClickable(false),
+		viewLog:            l.Theme.NewClickable(false),
+		exportBackup:       l.Theme.NewClickable(false),
+		importBackup:       l.Theme.NewClickable(false),
+		proxy:              l.Theme.NewClickable(false),
+		proxyDNSServer:     l.Theme.NewClickable(false),
+		maxConcurrentSyncs: l.Theme.NewClickable(false),
 	}
 
 	_, pg.networkInfoButton = components.SubpageHeaderButtons(l)
 	pg.backButton, pg.infoButton = components.SubpageHeaderButtons(l)
 	pg.isDarkModeOn = pg.WL.AssetsManager.IsDarkModeOn()
+	pg.initDevModeWidgets()
 
 	return pg
 }
@@ -150,6 +172,7 @@ func (pg *SettingPage) pageContentLayout(gtx C) D {
 		pg.security(),
 		pg.info(),
 		pg.debug(),
+		pg.developer(),
 	}
 	gtx.Constraints.Min.X = gtx.Constraints.Max.X
 	return layout.Center.Layout(gtx, func(gtx C) D {
@@ -254,6 +277,13 @@ func (pg *SettingPage) general() layout.Widget {
 				layout.Rigid(func(gtx C) D {
 					return pg.subSectionSwitch(gtx, values.String(values.StrTxNotification), pg.transactionNotification)
 				}),
+				layout.Rigid(func(gtx C) D {
+					return pg.subSectionSwitch(gtx, values.String(values.StrSyncSequentially), pg.syncSequentially)
+				}),
+				layout.Rigid(pg.maxConcurrentSyncsRow),
+				layout.Rigid(func(gtx C) D {
+					return pg.subSectionSwitch(gtx, values.String(values.StrRetryFailedSyncs), pg.retryFailedSyncs)
+				}),
 			)
 		})
 	}
@@ -263,9 +293,17 @@ func (pg *SettingPage) networkSettings() layout.Widget {
 	return func(gtx C) D {
 		return pg.wrapSection(gtx, values.String(values.StrPrivacySettings), func(gtx C) D {
 			if pg.WL.AssetsManager.IsPrivacyModeOn() {
-				return D{}
+				// The proxy still applies to the wallets' peer connections.
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(pg.proxyRow),
+					layout.Rigid(pg.isolationRow),
+					layout.Rigid(pg.proxyDNSServerRow),
+				)
 			}
 			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(pg.proxyRow),
+				layout.Rigid(pg.isolationRow),
+				layout.Rigid(pg.proxyDNSServerRow),
 				layout.Rigid(func(gtx C) D {
 					lKey := pg.WL.AssetsManager.GetCurrencyConversionExchange()
 					l := preference.GetKeyValue(lKey, preference.ExchOptions)
@@ -311,6 +349,22 @@ func (pg *SettingPage) security() layout.Widget {
 					}
 					return D{}
 				}),
+				layout.Rigid(func(gtx C) D {
+					exportBackupRow := row{
+						title:     values.String(values.StrExportAppBackup),
+						clickable: pg.exportBackup,
+						label:     pg.Theme.Body2(""),
+					}
+					return pg.clickableRow(gtx, exportBackupRow)
+				}),
+				layout.Rigid(func(gtx C) D {
+					importBackupRow := row{
+						title:     values.String(values.StrImportAppBackup),
+						clickable: pg.importBackup,
+						label:     pg.Theme.Body2(""),
+					}
+					return pg.clickableRow(gtx, importBackupRow)
+				}),
 			)
 		})
 	}
@@ -458,6 +512,36 @@ func (pg *SettingPage) HandleUserInteractions() {
 		pg.updatePrivacySettings()
 	}
 
+	if pg.proxy.Clicked() {
+		pg.proxySettingsModal()
+	}
+
+	if pg.proxyDNSServer.Clicked() {
+		pg.proxyDNSServerModal()
+	}
+
+	if pg.isolation.Changed() {
+		pg.setStreamIsolation(pg.isolation.IsChecked())
+	}
+
+	if pg.syncSequentially.Changed() {
+		pg.updateSyncPolicy(func(policy *libwallet.SyncPolicy) {
+			policy.Sequential = pg.syncSequentially.IsChecked()
+		})
+	}
+
+	if pg.retryFailedSyncs.Changed() {
+		pg.updateSyncPolicy(func(policy *libwallet.SyncPolicy) {
+			policy.RetryFailed = pg.retryFailedSyncs.IsChecked()
+		})
+	}
+
+	if pg.maxConcurrentSyncs.Clicked() {
+		pg.maxConcurrentSyncsModal()
+	}
+
+	pg.handleDevModeEvents()
+
 	if pg.infoButton.Button.Clicked() {
 		info := modal.NewCustomModal(pg.Load).
 			SetContentAlignment(layout.Center, layout.Center, layout.Center).
@@ -498,6 +582,14 @@ func (pg *SettingPage) HandleUserInteractions() {
 		pg.ParentNavigator().Display(NewLogPage(pg.Load, pg.WL.AssetsManager.LogFile(), values.String(values.StrAppLog)))
 	}
 
+	if pg.exportBackup.Clicked() {
+		pg.exportAppBackup()
+	}
+
+	if pg.importBackup.Clicked() {
+		pg.importAppBackup()
+	}
+
 	for pg.changeStartupPass.Clicked() {
 		currentPasswordModal := modal.NewCreatePasswordModal(pg.Load).
 			EnableName(false).
@@ -618,10 +710,14 @@ func (pg *SettingPage) updateSettingOptions() {
 	}
 
 	pg.updatePrivacySettings()
+	pg.updateSyncPolicySettings()
+	pg.setInitialSwitchStatus(pg.devMode, pg.WL.AssetsManager.IsDevMode())
 }
 
 func (pg *SettingPage) updatePrivacySettings() {
 	pg.setInitialSwitchStatus(pg.privacyActive, pg.WL.AssetsManager.IsPrivacyModeOn())
+	proxy := pg.WL.AssetsManager.ProxyConfig()
+	pg.setInitialSwitchStatus(pg.isolation, proxy != nil && proxy.StreamIsolation)
 	if !pg.WL.AssetsManager.IsPrivacyModeOn() {
 		pg.setInitialSwitchStatus(pg.transactionNotification, pg.WL.AssetsManager.IsTransactionNotificationsOn())
 		pg.setInitialSwitchStatus(pg.governanceAPI, pg.WL.AssetsManager.IsHTTPAPIPrivacyModeOff(libutils.GovernanceHTTPAPI))
@@ -631,8 +727,17 @@ func (pg *SettingPage) updatePrivacySettings() {
 	} else {
 		if pg.WL.SelectedWallet != nil {
 			go func() {
-				// Clear all the peers saved if the privacy mode is on.
-				pg.WL.SelectedWallet.Wallet.SetStringConfigValueForKey(sharedW.SpvPersistentPeerAddressesConfigKey, "")
+				// Clear all the peers saved if the privacy mode is on. The
+				// bans are kept.
+				wallet := pg.WL.SelectedWallet.Wallet
+				cfg := wallet.PeerConfig()
+				if len(cfg.Persistent) == 0 && len(cfg.Preferred) == 0 {
+					return
+				}
+				cfg.Persistent, cfg.Preferred = nil, nil
+				if err := wallet.SetPeerConfig(cfg); err != nil {
+					log.Error(err)
+				}
 			}()
 		}
 	}
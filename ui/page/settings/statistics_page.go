@@ -3,6 +3,7 @@ package settings
 import (
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"gioui.org/layout"
@@ -16,6 +17,7 @@ import (
 	libutils "github.com/crypto-power/cryptopower/libwallet/utils"
 	"github.com/crypto-power/cryptopower/ui/cryptomaterial"
 	"github.com/crypto-power/cryptopower/ui/load"
+	"github.com/crypto-power/cryptopower/ui/modal"
 	"github.com/crypto-power/cryptopower/ui/page/components"
 	"github.com/crypto-power/cryptopower/ui/values"
 )
@@ -38,7 +40,24 @@ type StatPage struct {
 	startupTime   string
 	netType       string
 
-	backButton cryptomaterial.IconButton
+	backButton    cryptomaterial.IconButton
+	compactButton cryptomaterial.Button
+
+	// canCompact is set if the wallet database uses the badger driver,
+	// the only one that can be compacted while the wallet is open.
+	canCompact bool
+
+	compactMtx       sync.Mutex
+	compacting       bool
+	compactionResult string
+}
+
+// compactableWallet is implemented by wallets whose database can be compacted
+// while they are open.
+type compactableWallet interface {
+	CompactDB() error
+	DatabaseDriver() (string, error)
+	DatabaseSize() (int64, error)
 }
 
 func NewStatPage(l *load.Load) *StatPage {
@@ -53,6 +72,7 @@ func NewStatPage(l *load.Load) *StatPage {
 	}
 
 	pg.backButton, _ = components.SubpageHeaderButtons(l)
+	pg.compactButton = l.Theme.OutlineButton(values.String(values.StrCompactDB))
 
 	return pg
 }
@@ -89,6 +109,15 @@ func (pg *StatPage) OnNavigatedTo() {
 		pg.accounts = acc
 	}
 
+	pg.canCompact = false
+	if wallet, ok := pg.WL.SelectedWallet.Wallet.(compactableWallet); ok {
+		driver, err := wallet.DatabaseDriver()
+		if err != nil {
+			log.Errorf("Error getting the wallet database driver: %s", err.Error())
+		}
+		pg.canCompact = driver == dcr.BadgerDriver
+	}
+
 	pg.appStartTime()
 }
 
@@ -139,12 +168,32 @@ func (pg *StatPage) layoutStats(gtx C) D {
 		line.Layout,
 		item(values.String(values.StrDateSize), pg.WL.DataSize()),
 		line.Layout,
+	}
+	pg.compactMtx.Lock()
+	compacting, compactionResult := pg.compacting, pg.compactionResult
+	pg.compactMtx.Unlock()
+	if compactionResult != "" {
+		items = append(items,
+			item(values.String(values.StrDBCompaction), compactionResult),
+			line.Layout,
+		)
+	}
+	items = append(items,
 		item(values.String(values.StrTransactions), fmt.Sprintf("%d", len(pg.txs))),
 		line.Layout,
 		item(values.String(values.StrAccount)+"s", fmt.Sprintf("%d", len(pg.accounts.Accounts))),
+	)
+
+	listLength := 1
+	if pg.canCompact {
+		listLength = 2
 	}
 
-	return pg.Theme.List(pg.scrollbarList).Layout(gtx, 1, func(gtx C, i int) D {
+	return pg.Theme.List(pg.scrollbarList).Layout(gtx, listLength, func(gtx C, i int) D {
+		if i == 1 {
+			pg.compactButton.SetEnabled(!compacting)
+			return layout.Inset{Top: values.MarginPadding16}.Layout(gtx, pg.compactButton.Layout)
+		}
 		return layout.Inset{Right: values.MarginPadding2}.Layout(gtx, func(gtx C) D {
 			return card.Layout(gtx, func(gtx C) D {
 				return layout.Inset{Left: values.MarginPadding16}.Layout(gtx, func(gtx C) D {
@@ -222,6 +271,54 @@ func (pg *StatPage) appStartTime() {
 // Part of the load.Page interface.
 func (pg *StatPage) HandleUserInteractions() {
 	pg.appStartTime()
+
+	if pg.compactButton.Clicked() {
+		pg.compactDB()
+	}
+}
+
+// compactDB compacts the selected wallet's database in the background and
+// records the database size before and after the compaction.
+func (pg *StatPage) compactDB() {
+	wallet, ok := pg.WL.SelectedWallet.Wallet.(compactableWallet)
+	if !ok || !pg.canCompact {
+		return
+	}
+
+	pg.compactMtx.Lock()
+	defer pg.compactMtx.Unlock()
+	if pg.compacting {
+		return
+	}
+	pg.compacting = true
+
+	go func() {
+		defer pg.ParentWindow().Reload()
+
+		before := databaseSize(wallet)
+		err := wallet.CompactDB()
+
+		pg.compactMtx.Lock()
+		pg.compacting = false
+		if err == nil {
+			pg.compactionResult = before + " / " + databaseSize(wallet)
+		}
+		pg.compactMtx.Unlock()
+
+		if err != nil {
+			errModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+			pg.ParentWindow().ShowModal(errModal)
+		}
+	}()
+}
+
+// databaseSize returns the formatted size of the wallet database.
+func databaseSize(wallet compactableWallet) string {
+	size, err := wallet.DatabaseSize()
+	if err != nil {
+		return "Unknown"
+	}
+	return fmt.Sprintf("%.2f MB", float64(size)*1e-6)
 }
 
 // OnNavigatedFrom is called when the page is about to be removed from
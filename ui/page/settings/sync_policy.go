@@ -0,0 +1,84 @@
+package settings
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/crypto-power/cryptopower/libwallet"
+	"github.com/crypto-power/cryptopower/ui/modal"
+	"github.com/crypto-power/cryptopower/ui/values"
+)
+
+func (pg *SettingPage) maxConcurrentSyncsRow(gtx C) D {
+	policy := pg.WL.AssetsManager.SyncManager.Policy()
+	if policy.Sequential {
+		return D{}
+	}
+
+	label := values.String(values.StrNoLimit)
+	if policy.MaxConcurrent > 0 {
+		label = strconv.Itoa(policy.MaxConcurrent)
+	}
+	maxConcurrentRow := row{
+		title:     values.String(values.StrMaxConcurrentSyncs),
+		clickable: pg.maxConcurrentSyncs,
+		label:     pg.Theme.Body2(label),
+	}
+	return pg.clickableRow(gtx, maxConcurrentRow)
+}
+
+// maxConcurrentSyncsModal asks for the number of wallets allowed to sync at
+// the same time. Zero or an empty value removes the limit.
+func (pg *SettingPage) maxConcurrentSyncsModal() {
+	policy := pg.WL.AssetsManager.SyncManager.Policy()
+	textModal := modal.NewTextInputModal(pg.Load).
+		Hint(values.String(values.StrMaxConcurrentSyncs)).
+		PositiveButtonStyle(pg.Load.Theme.Color.Primary, pg.Load.Theme.Color.InvText).
+		SetPositiveButtonCallback(func(text string, tm *modal.TextInputModal) bool {
+			maxConcurrent := 0
+			if text = strings.TrimSpace(text); text != "" {
+				n, err := strconv.Atoi(text)
+				if err != nil || n < 0 {
+					tm.SetError(values.String(values.StrInvalidNumber))
+					tm.SetLoading(false)
+					return false
+				}
+				maxConcurrent = n
+			}
+
+			policy.MaxConcurrent = maxConcurrent
+			if err := pg.WL.AssetsManager.SyncManager.SetPolicy(policy); err != nil {
+				tm.SetError(err.Error())
+				tm.SetLoading(false)
+				return false
+			}
+			tm.Dismiss()
+
+			pg.showNoticeSuccess(values.String(values.StrSyncPolicyUpdated))
+			return true
+		})
+	if policy.MaxConcurrent > 0 {
+		textModal.SetText(strconv.Itoa(policy.MaxConcurrent))
+	}
+	textModal.Title(values.String(values.StrMaxConcurrentSyncs)).
+		SetPositiveButtonText(values.String(values.StrSave)).
+		SetNegativeButtonText(values.String(values.StrCancel))
+	pg.ParentWindow().ShowModal(textModal)
+}
+
+// updateSyncPolicy saves the sync policy with fn applied to it.
+func (pg *SettingPage) updateSyncPolicy(fn func(policy *libwallet.SyncPolicy)) {
+	policy := pg.WL.AssetsManager.SyncManager.Policy()
+	fn(policy)
+	if err := pg.WL.AssetsManager.SyncManager.SetPolicy(policy); err != nil {
+		errModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+		pg.ParentWindow().ShowModal(errModal)
+	}
+	pg.updateSyncPolicySettings()
+}
+
+func (pg *SettingPage) updateSyncPolicySettings() {
+	policy := pg.WL.AssetsManager.SyncManager.Policy()
+	pg.setInitialSwitchStatus(pg.syncSequentially, policy.Sequential)
+	pg.setInitialSwitchStatus(pg.retryFailedSyncs, policy.RetryFailed)
+}
@@ -0,0 +1,150 @@
+package staking
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"gioui.org/font"
+	"gioui.org/layout"
+
+	"github.com/crypto-power/cryptopower/libwallet/assets/dcr"
+	"github.com/crypto-power/cryptopower/ui/cryptomaterial"
+	"github.com/crypto-power/cryptopower/ui/modal"
+	"github.com/crypto-power/cryptopower/ui/values"
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+// analyticsChartMonths is the number of most recent months shown in the
+// rewards by month chart.
+const analyticsChartMonths = 12
+
+func (pg *Page) loadStakingAnalytics() {
+	analytics, err := pg.dcrImpl.StakingAnalytics()
+	if err != nil {
+		log.Errorf("staking analytics error: %v", err)
+		return
+	}
+	pg.analytics = analytics
+}
+
+func (pg *Page) stakingAnalyticsSection(gtx C) D {
+	if pg.analytics == nil || pg.analytics.Total.Tickets == 0 {
+		return D{}
+	}
+
+	total := pg.analytics.Total
+	formatPercent := func(f float64) string {
+		return strconv.FormatFloat(f*100, 'f', 2, 64) + "%"
+	}
+	formatAmount := func(amount int64) string {
+		return dcrutil.Amount(amount).String()
+	}
+
+	return pg.pageSections(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx C) D {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle, Spacing: layout.SpaceBetween}.Layout(gtx,
+					layout.Rigid(func(gtx C) D {
+						title := pg.Theme.Label(values.TextSize16, values.String(values.StrStakingAnalytics))
+						title.Font.Weight = font.SemiBold
+						return title.Layout(gtx)
+					}),
+					layout.Rigid(pg.exportAnalyticsBtn.Layout),
+				)
+			}),
+			layout.Rigid(func(gtx C) D {
+				return layout.Inset{Top: values.MarginPadding16}.Layout(gtx, func(gtx C) D {
+					return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+						layout.Rigid(pg.stakingRecord(formatPercent(total.ROI), values.String(values.StrRealizedROI))),
+						layout.Rigid(pg.stakingRecord(formatPercent(total.AnnualizedReturn), values.String(values.StrAnnualizedReturn))),
+						layout.Rigid(pg.stakingRecord(strconv.FormatFloat(total.AverageDaysToVote, 'f', 1, 64), values.String(values.StrAvgDaysToVote))),
+						layout.Rigid(pg.stakingRecord(formatAmount(total.Rewards), values.String(values.StrReward))),
+						layout.Rigid(pg.stakingRecord(formatAmount(total.TicketFees), values.String(values.StrTicketFees))),
+						layout.Rigid(pg.stakingRecord(formatAmount(total.VSPFees), values.String(values.StrVSPFees))),
+						layout.Rigid(pg.stakingRecord(formatAmount(total.NetRewards), values.String(values.StrNetRewards))),
+						layout.Rigid(pg.stakingRecord(fmt.Sprintf("%d", total.Voted), values.String(values.StrVoted))),
+						layout.Rigid(pg.stakingRecord(fmt.Sprintf("%d", total.Missed), values.String(values.StrMissed))),
+						layout.Rigid(pg.stakingRecord(fmt.Sprintf("%d", total.Expired), values.String(values.StrExpired))),
+						layout.Rigid(pg.stakingRecord(fmt.Sprintf("%d", total.Revoked), values.String(values.StrRevoked))),
+					)
+				})
+			}),
+			layout.Rigid(func(gtx C) D {
+				byMonth := pg.analytics.ByMonth
+				if len(byMonth) > analyticsChartMonths {
+					byMonth = byMonth[len(byMonth)-analyticsChartMonths:]
+				}
+				return pg.rewardsChart(gtx, values.String(values.StrNetRewardsByMonth), byMonth, func(key string) string {
+					return key
+				})
+			}),
+			layout.Rigid(func(gtx C) D {
+				return pg.rewardsChart(gtx, values.String(values.StrNetRewardsByVSP), pg.analytics.ByVSP, func(key string) string {
+					if key == "" {
+						return values.String(values.StrSolo)
+					}
+					return key
+				})
+			}),
+		)
+	})
+}
+
+// rewardsChart draws the net rewards of each group of tickets in a bar chart.
+func (pg *Page) rewardsChart(gtx C, title string, groups []*dcr.StakingStats, label func(string) string) D {
+	items := make([]cryptomaterial.BarChartItem, 0, len(groups))
+	for _, stats := range groups {
+		items = append(items, cryptomaterial.BarChartItem{
+			Label: label(stats.Key),
+			Value: dcrutil.Amount(stats.NetRewards).ToCoin(),
+		})
+	}
+
+	chart := pg.Theme.BarChart(items)
+	chart.FormatValue = func(value float64) string {
+		return strconv.FormatFloat(value, 'f', 2, 64)
+	}
+
+	return layout.Inset{Top: values.MarginPadding16}.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx C) D {
+				lbl := pg.Theme.Label(values.TextSize14, title)
+				lbl.Color = pg.Theme.Color.GrayText2
+				return layout.Inset{Bottom: values.MarginPadding8}.Layout(gtx, lbl.Layout)
+			}),
+			layout.Rigid(chart.Layout),
+		)
+	})
+}
+
+// exportStakingAnalytics writes the analytics of every ticket to a CSV file in
+// the wallet's data directory.
+func (pg *Page) exportStakingAnalytics() {
+	if pg.analytics == nil {
+		return
+	}
+
+	fileName := fmt.Sprintf("staking_analytics_%d.csv", time.Now().Unix())
+	filePath := filepath.Join(pg.dcrImpl.DataDir(), fileName)
+
+	err := func() error {
+		file, err := os.Create(filePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		return dcr.ExportStakingAnalyticsCSV(file, pg.analytics)
+	}()
+	if err != nil {
+		errModal := modal.NewErrorModal(pg.Load, err.Error(), modal.DefaultClickFunc())
+		pg.ParentWindow().ShowModal(errModal)
+		return
+	}
+
+	info := modal.NewSuccessModal(pg.Load, values.StringF(values.StrStakingAnalyticsExported, filePath), modal.DefaultClickFunc())
+	pg.ParentWindow().ShowModal(info)
+}
@@ -2,6 +2,7 @@ package staking
 
 import (
 	"context"
+	"errors"
 	"strconv"
 
 	"gioui.org/font"
@@ -28,6 +29,11 @@ type ticketBuyerModal struct {
 	saveSettingsBtn cryptomaterial.Button
 
 	balToMaintainEditor cryptomaterial.Editor
+	maxPriceEditor      cryptomaterial.Editor
+	ticketCapEditor     cryptomaterial.Editor
+	startHourEditor     cryptomaterial.Editor
+	endHourEditor       cryptomaterial.Editor
+	dryRun              cryptomaterial.CheckBoxStyle
 
 	accountSelector *components.WalletAndAccountSelector
 	vspSelector     *components.VSPSelector
@@ -54,6 +60,15 @@ func newTicketBuyerModal(l *load.Load) *ticketBuyerModal {
 
 	tb.balToMaintainEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrBalToMaintain))
 	tb.balToMaintainEditor.Editor.SingleLine = true
+	tb.maxPriceEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrMaxTicketPrice))
+	tb.maxPriceEditor.Editor.SingleLine = true
+	tb.ticketCapEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrMaxTicketsPerInterval))
+	tb.ticketCapEditor.Editor.SingleLine = true
+	tb.startHourEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrPurchaseStartHour))
+	tb.startHourEditor.Editor.SingleLine = true
+	tb.endHourEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrPurchaseEndHour))
+	tb.endHourEditor.Editor.SingleLine = true
+	tb.dryRun = l.Theme.CheckBox(new(widget.Bool), values.String(values.StrTicketBuyerDryRun))
 
 	tb.saveSettingsBtn.SetEnabled(false)
 
@@ -115,6 +130,17 @@ func (tb *ticketBuyerModal) OnResume() {
 		tb.vspSelector.SelectVSP(tbConfig.VspHost)
 		w := tb.WL.SelectedWallet.Wallet
 		tb.balToMaintainEditor.Editor.SetText(strconv.FormatFloat(w.ToAmount(tbConfig.BalanceToMaintain).ToCoin(), 'f', 0, 64))
+		if tbConfig.MaxTicketPrice > 0 {
+			tb.maxPriceEditor.Editor.SetText(strconv.FormatFloat(w.ToAmount(tbConfig.MaxTicketPrice).ToCoin(), 'f', -1, 64))
+		}
+		if tbConfig.MaxTicketsPerInterval > 0 {
+			tb.ticketCapEditor.Editor.SetText(strconv.Itoa(int(tbConfig.MaxTicketsPerInterval)))
+		}
+		if tbConfig.Schedule != nil {
+			tb.startHourEditor.Editor.SetText(strconv.Itoa(tbConfig.Schedule.StartHour))
+			tb.endHourEditor.Editor.SetText(strconv.Itoa(tbConfig.Schedule.EndHour))
+		}
+		tb.dryRun.CheckBox.Value = tbConfig.DryRun
 	}
 
 	if tb.accountSelector.SelectedAccount() == nil {
@@ -146,6 +172,27 @@ func (tb *ticketBuyerModal) Layout(gtx layout.Context) layout.Dimensions {
 				layout.Rigid(func(gtx C) D {
 					return tb.balToMaintainEditor.Layout(gtx)
 				}),
+				layout.Rigid(func(gtx C) D {
+					return layout.Inset{Top: values.MarginPadding16}.Layout(gtx, tb.maxPriceEditor.Layout)
+				}),
+				layout.Rigid(func(gtx C) D {
+					return layout.Inset{Top: values.MarginPadding16}.Layout(gtx, tb.ticketCapEditor.Layout)
+				}),
+				layout.Rigid(func(gtx C) D {
+					return layout.Inset{Top: values.MarginPadding16}.Layout(gtx, func(gtx C) D {
+						return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+							layout.Flexed(0.5, func(gtx C) D {
+								return layout.Inset{Right: values.MarginPadding4}.Layout(gtx, tb.startHourEditor.Layout)
+							}),
+							layout.Flexed(0.5, func(gtx C) D {
+								return layout.Inset{Left: values.MarginPadding4}.Layout(gtx, tb.endHourEditor.Layout)
+							}),
+						)
+					})
+				}),
+				layout.Rigid(func(gtx C) D {
+					return layout.Inset{Top: values.MarginPadding8}.Layout(gtx, tb.dryRun.Layout)
+				}),
 				layout.Rigid(func(gtx C) D {
 					return layout.Inset{
 						Top:    values.MarginPadding16,
@@ -221,18 +268,72 @@ func (tb *ticketBuyerModal) Handle() {
 	}
 
 	if tb.saveSettingsBtn.Clicked() {
-		vspHost := tb.vspSelector.SelectedVSP().Host
 		amount, err := strconv.ParseFloat(tb.balToMaintainEditor.Editor.Text(), 64)
 		if err != nil {
 			tb.SetError(err.Error())
 			return
 		}
 
-		balToMaintain := dcr.AmountAtom(amount)
-		account := tb.accountSelector.SelectedAccount()
+		tbConfig := tb.dcrImpl.AutoTicketsBuyerConfig()
+		tbConfig.VspHost = tb.vspSelector.SelectedVSP().Host
+		tbConfig.PurchaseAccount = tb.accountSelector.SelectedAccount().Number
+		tbConfig.BalanceToMaintain = dcr.AmountAtom(amount)
+		tbConfig.DryRun = tb.dryRun.CheckBox.Value
+		if err := tb.readStrategy(tbConfig); err != nil {
+			tb.SetError(err.Error())
+			return
+		}
 
-		tb.dcrImpl.SetAutoTicketsBuyerConfig(vspHost, account.Number, balToMaintain)
+		if err := tb.dcrImpl.SaveTicketBuyerConfig(tbConfig); err != nil {
+			tb.SetError(err.Error())
+			return
+		}
 		tb.settingsSaved()
 		tb.Dismiss()
 	}
 }
+
+// readStrategy sets the optional purchase limits and schedule entered in the
+// modal on tbConfig.
+func (tb *ticketBuyerModal) readStrategy(tbConfig *dcr.TicketBuyerConfig) error {
+	tbConfig.MaxTicketPrice = 0
+	if text := tb.maxPriceEditor.Editor.Text(); text != "" {
+		maxPrice, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return err
+		}
+		tbConfig.MaxTicketPrice = dcr.AmountAtom(maxPrice)
+	}
+
+	tbConfig.MaxTicketsPerInterval = 0
+	if text := tb.ticketCapEditor.Editor.Text(); text != "" {
+		ticketCap, err := strconv.ParseInt(text, 10, 32)
+		if err != nil {
+			return err
+		}
+		tbConfig.MaxTicketsPerInterval = int32(ticketCap)
+	}
+
+	startHour, endHour := tb.startHourEditor.Editor.Text(), tb.endHourEditor.Editor.Text()
+	if startHour == "" && endHour == "" {
+		tbConfig.Schedule = nil
+		return nil
+	}
+	if startHour == "" || endHour == "" {
+		return errors.New(values.String(values.StrPurchaseHoursRequired))
+	}
+
+	schedule := &dcr.TicketBuyerSchedule{}
+	if tbConfig.Schedule != nil {
+		schedule.Weekdays = tbConfig.Schedule.Weekdays
+	}
+	var err error
+	if schedule.StartHour, err = strconv.Atoi(startHour); err != nil {
+		return err
+	}
+	if schedule.EndHour, err = strconv.Atoi(endHour); err != nil {
+		return err
+	}
+	tbConfig.Schedule = schedule
+	return nil
+}
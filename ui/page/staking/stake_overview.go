@@ -3,6 +3,7 @@ package staking
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync/atomic"
 
 	"gioui.org/layout"
@@ -47,6 +48,7 @@ type Page struct {
 	scroll *components.Scroll[*transactionItem]
 
 	ticketOverview *dcr.StakingOverview
+	analytics      *dcr.StakingAnalytics
 
 	ticketsList    *cryptomaterial.ClickableList
 	stakeSettings  *cryptomaterial.Clickable
@@ -58,8 +60,9 @@ type Page struct {
 	totalRewards       string
 	showMaterialLoader bool
 
-	navToSettingsBtn cryptomaterial.Button
-	processingTicket uint32
+	navToSettingsBtn   cryptomaterial.Button
+	exportAnalyticsBtn cryptomaterial.Button
+	processingTicket   uint32
 
 	dcrImpl *dcr.Asset
 }
@@ -84,6 +87,7 @@ func NewStakingPage(l *load.Load) *Page {
 	pg.initTicketList()
 
 	pg.navToSettingsBtn = l.Theme.Button(values.StringF(values.StrEnableAPI, values.String(values.StrVsp)))
+	pg.exportAnalyticsBtn = l.Theme.OutlineButton(values.String(values.StrExportCSV))
 
 	return pg
 }
@@ -157,6 +161,7 @@ func (pg *Page) loadPageData() {
 			pg.ticketOverview = overview
 		}
 
+		pg.loadStakingAnalytics()
 		pg.ParentWindow().Reload()
 	}()
 }
@@ -203,8 +208,12 @@ func (pg *Page) layoutDesktop(gtx C) D {
 					gtx.Constraints.Min.X = gtx.Constraints.Max.X
 					return layout.Center.Layout(gtx, pg.materialLoader.Layout)
 				}
-				return pg.scroll.List().Layout(gtx, 1, func(gtx C, i int) D {
-					return pg.ticketListLayout(gtx)
+				widgets := []layout.Widget{
+					pg.stakingAnalyticsSection,
+					pg.ticketListLayout,
+				}
+				return pg.scroll.List().Layout(gtx, len(widgets), func(gtx C, i int) D {
+					return widgets[i](gtx)
 				})
 			})
 		}),
@@ -214,6 +223,7 @@ func (pg *Page) layoutDesktop(gtx C) D {
 func (pg *Page) layoutMobile(gtx layout.Context) layout.Dimensions {
 	widgets := []layout.Widget{
 		pg.stakePriceSection,
+		pg.stakingAnalyticsSection,
 		pg.ticketListLayout,
 	}
 
@@ -245,6 +255,10 @@ func (pg *Page) pageSections(gtx C, body layout.Widget) D {
 func (pg *Page) HandleUserInteractions() {
 	pg.setStakingButtonsState()
 
+	if pg.exportAnalyticsBtn.Clicked() {
+		pg.exportStakingAnalytics()
+	}
+
 	if pg.navToSettingsBtn.Clicked() {
 		pg.ParentWindow().Display(settings.NewSettingsPage(pg.Load))
 	}
@@ -391,7 +405,14 @@ func (pg *Page) startTicketBuyerPasswordModal() {
 				layout.Rigid(pg.Theme.Label(values.TextSize14, values.StringF(values.StrWalletToPurchaseFrom, pg.WL.SelectedWallet.Wallet.GetWalletName())).Layout),
 				layout.Rigid(pg.Theme.Label(values.TextSize14, values.StringF(values.StrSelectedAccount, name)).Layout),
 				layout.Rigid(pg.Theme.Label(values.TextSize14, values.StringF(values.StrBalToMaintainValue, balToMaintain)).Layout), layout.Rigid(func(gtx C) D {
-					label := pg.Theme.Label(values.TextSize14, fmt.Sprintf("VSP: %s", tbConfig.VspHost))
+					label := pg.Theme.Label(values.TextSize14, fmt.Sprintf("VSP: %s", strings.Join(append([]string{tbConfig.VspHost}, tbConfig.VspHosts...), ", ")))
+					return layout.Inset{Bottom: values.MarginPadding12}.Layout(gtx, label.Layout)
+				}),
+				layout.Rigid(func(gtx C) D {
+					if !tbConfig.DryRun {
+						return D{}
+					}
+					label := pg.Theme.Label(values.TextSize14, values.String(values.StrDryRunEnabled))
 					return layout.Inset{Bottom: values.MarginPadding12}.Layout(gtx, label.Layout)
 				}),
 				layout.Rigid(func(gtx C) D {
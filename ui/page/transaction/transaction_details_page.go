@@ -784,7 +784,7 @@ func (pg *TxDetailsPage) txnInputs(gtx C) D {
 		return pg.transactionInputsContainer.Layout(gtx, len(transaction.Inputs), func(gtx C, i int) D {
 			input := transaction.Inputs[i]
 			addr := utils.SplitSingleString(input.PreviousOutpoint, 20)
-			return pg.txnIORow(gtx, input.Amount, input.AccountNumber, addr, i)
+			return pg.txnIORow(gtx, input.Amount, input.AccountNumber, input.WatchOnly, addr, i)
 		})
 	}
 	return pg.pageSections(gtx, func(gtx C) D {
@@ -805,7 +805,7 @@ func (pg *TxDetailsPage) txnOutputs(gtx C) D {
 		x := len(transaction.Inputs)
 		return pg.transactionOutputsContainer.Layout(gtx, len(transaction.Outputs), func(gtx C, i int) D {
 			output := transaction.Outputs[i]
-			return pg.txnIORow(gtx, output.Amount, output.AccountNumber, output.Address, i+x)
+			return pg.txnIORow(gtx, output.Amount, output.AccountNumber, output.WatchOnly, output.Address, i+x)
 		})
 	}
 	return pg.pageSections(gtx, func(gtx C) D {
@@ -813,7 +813,7 @@ func (pg *TxDetailsPage) txnOutputs(gtx C) D {
 	})
 }
 
-func (pg *TxDetailsPage) txnIORow(gtx C, amount int64, acctNum int32, address string, i int) D {
+func (pg *TxDetailsPage) txnIORow(gtx C, amount int64, acctNum int32, watchOnly bool, address string, i int) D {
 	accountName := values.String(values.StrExternal)
 	if acctNum != -1 {
 		name, err := pg.wallet.AccountName(acctNum)
@@ -821,6 +821,9 @@ func (pg *TxDetailsPage) txnIORow(gtx C, amount int64, acctNum int32, address st
 			accountName = name
 		}
 	}
+	if watchOnly {
+		accountName += ", " + values.String(values.StrWatchOnly)
+	}
 
 	accountName = fmt.Sprintf("(%s)", accountName)
 	amt := pg.wallet.ToAmount(amount).String()
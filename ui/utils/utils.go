@@ -27,7 +27,7 @@ func ValidateLengthName(name string) bool {
 func ValidateHost(host string) bool {
 	address := strings.Trim(host, " ")
 
-	if net.ParseIP(address) != nil {
+	if net.ParseIP(address) != nil || utils.IsOnionHost(address) {
 		return true
 	}
 
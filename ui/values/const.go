@@ -7,4 +7,6 @@ const (
 	ConsensusDropdownGroup
 	OrderStatusDropdownGroup
 	DEXServerDropdownGroup
+	AddressAccountDropdownGroup
+	AddressFilterDropdownGroup
 )
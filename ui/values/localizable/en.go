@@ -791,4 +791,203 @@ const EN = `
 "assets" = "Assets"
 "noWalletsAvailable" = "You cannot spend from a watch only wallet, try creating another wallet."
 "createAssetWalletToSwapMsg" = "You need to create a %s wallet to swap."
+"addressExplorer" = "Address Explorer"
+"internal" = "Internal"
+"used" = "Used"
+"unused" = "Unused"
+"funded" = "Funded"
+"timesUsed" = "Times used"
+"firstSeen" = "First seen"
+"lastSeen" = "Last seen"
+"addressLabel" = "Label"
+"setAddressLabel" = "Set address label"
+"exportCSV" = "Export CSV"
+"addressesExported" = "Addresses exported to %v"
+"noAddresses" = "No addresses"
+"importKey" = "Import Key"
+"importKeyNote" = "Import a private key (WIF) into the imported account, or watch an address or the address of a public key (hex) without the ability to spend from it. The wallet is rescanned from the birthday for the transactions of the address. Private keys may be prefixed with their address type e.g. p2pkh:, p2wpkh-p2sh: or p2wpkh:."
+"importKeyNoteDCR" = "Import a private key (WIF) into the imported account, or watch the address of a public key (hex) without the ability to spend from it. Plain DCR addresses cannot be watched. The wallet is rescanned from the birthday for the transactions of the address."
+"keyHint" = "Private key (WIF), public key (hex) or address"
+"birthdayHint" = "Birthday (YYYY-MM-DD)"
+"importPrivateKey" = "Import private key"
+"watchAddress" = "Watch address"
+"invalidBirthday" = "Invalid birthday, use the YYYY-MM-DD format"
+"keyImported" = "%v imported, the wallet is rescanning from the birthday"
+"multisig" = "Multisig"
+"multisigNote" = "Share your multisig key with the cosigners and enter their keys to set up an m-of-n multisig. Every cosigner must enter the same keys and number of required signatures."
+"multisigKey" = "Your multisig key"
+"createMultisigKey" = "Create multisig key"
+"requiredSignatures" = "Required signatures"
+"cosignerKeys" = "Cosigner extended public keys, one per line"
+"setupMultisig" = "Set up multisig"
+"multisigPolicy" = "%d of %d signatures required"
+"multisigAddress" = "Multisig address"
+"createPSBT" = "Create PSBT"
+"psbtHint" = "Paste PSBTs, one per line"
+"signPSBT" = "Sign"
+"combinePSBTs" = "Combine"
+"broadcast" = "Broadcast"
+"psbtSignatures" = "Signatures: %d of %d"
+"txBroadcasted" = "Transaction %s broadcasted"
+"invalidThreshold" = "Invalid number of required signatures"
+"extendedPubKeyOrDescriptor" = "Extended public key or output descriptor"
+"outputDescriptor" = "Output descriptor"
+"descriptorCopied" = "Output descriptor copied"
+"seedWordCount" = "%d words"
+"seedPassphrase" = "BIP39 passphrase (optional)"
+"shamirBackup" = "Shamir backup"
+"shamirBackupNote" = "Split the wallet seed into SLIP-39 shares that are kept in separate places. Any threshold of the shares restores the wallet, fewer shares reveal nothing about the seed."
+"shareThreshold" = "Shares required to restore"
+"shareCount" = "Number of shares"
+"createShares" = "Create shares"
+"invalidShares" = "Invalid number of shares"
+"shareOfShares" = "Share %d of %d"
+"wroteShare" = "I have written down share %d"
+"enterShareToVerify" = "Enter share %d to verify that it was written down correctly."
+"shareMismatch" = "The share entered does not match"
+"shamirShares" = "Shamir shares"
+"seedSharesHint" = "Enter the SLIP-39 shares, one per line"
+"exportAppBackup" = "Export app backup"
+"importAppBackup" = "Import app backup"
+"backupPassphrase" = "Backup passphrase"
+"appBackupExported" = "App backup saved to %s"
+"backupFilePath" = "Backup file path"
+"walletSpendingPassphrase" = "Spending passphrase of %s"
+"appBackupImported" = "App backup imported. The imported wallets are being rescanned."
+"migratingDB" = "Upgrading %s data (%d/%d): %s"
+"compactDB" = "Compact database"
+"dbCompaction" = "Size before / after compaction"
+"databaseDriver" = "Database driver"
+"switchDatabaseDriver" = "Switch database driver"
+"switchDatabaseDriverInfo" = "The wallet database will be copied to the %s driver and checked before it replaces the current database. The wallet is closed while it is copied, stop syncing first."
+"switch" = "Switch"
+"databaseDriverSwitched" = "The wallet now uses the %s driver. The previous database is kept until you confirm the switch."
+"confirmDatabaseDriver" = "Confirm database driver"
+"confirmDatabaseDriverInfo" = "The wallet uses the migrated %s database. Keep it and delete the previous database, or revert to the previous database."
+"keep" = "Keep"
+"revert" = "Revert"
+"repair" = "Repair"
+"walletDiagnosis" = "Wallet diagnosis"
+"issueNone" = "No problem was found with the wallet files."
+"issueUnknownAsset" = "The wallet type is not supported."
+"issueMissingDB" = "The wallet database is missing."
+"issueCorruptDB" = "The wallet database is corrupted."
+"issueWrongDriver" = "The wallet database uses an unsupported database driver."
+"issueVersionMismatch" = "The wallet database was created by a newer version of the app."
+"issueLockedDB" = "The wallet database is in use by another process."
+"issueWalletData" = "The wallet transactions index cannot be opened."
+"repairClearStaleLock" = "Remove the stale database lock"
+"repairRebuildWalletData" = "Rebuild the transactions index"
+"repairRecreateWatchOnly" = "Recreate the watch-only wallet from its saved key"
+"badWalletRepairs" = "Suggested fixes, replaced files are kept as backups:"
+"noBadWalletRepairs" = "No automatic fix is available. Repairing will only try to load the wallet again, otherwise restore it from its seed or delete it."
+"badWalletRepaired" = "Wallet repaired"
+"badWalletNotRepaired" = "The wallet could not be repaired. %s"
+"proxy" = "Tor / SOCKS5 proxy"
+"proxyAddress" = "Proxy address (host:port), empty to connect directly"
+"notProxied" = "Direct"
+"streamIsolation" = "Stream isolation"
+"viaProxy" = "via proxy"
+"proxyUpdated" = "Proxy settings saved. Wallets were disconnected and reconnect through the new route when synced again."
+"onionPeerRequiresProxy" = "Onion peers can only be reached through a proxy"
+"fullNode" = "Full node"
+"fullNodeNote" = "Sync this wallet from your own node over RPC instead of the peer-to-peer network. Remove the node to go back to SPV sync."
+"spvSync" = "SPV"
+"rpcHost" = "RPC host (host:port)"
+"rpcUser" = "RPC username"
+"rpcPassword" = "RPC password"
+"rpcCertificate" = "TLS certificate (PEM)"
+"disableTLS" = "Disable TLS"
+"zmqBlockHost" = "ZMQ block endpoint (tcp://host:port)"
+"zmqTxHost" = "ZMQ transaction endpoint (tcp://host:port)"
+"fallbackToSPV" = "Use SPV sync when the node is unreachable"
+"fullNodeSaved" = "Full node settings saved. They apply the next time the wallet connects."
+"electrumServers" = "Electrum servers, one per line as host:port:s or host:port:t, optionally followed by the pinned certificate fingerprint"
+"persistentPeers" = "Persistent peers"
+"persistentPeersNote" = "The wallet only connects to these peers when any is set."
+"preferredPeers" = "Preferred peers"
+"preferredPeersNote" = "These peers are tried first, other peers are still used."
+"bannedPeers" = "Banned peers"
+"bannedPeersNote" = "The wallet does not connect to banned peers."
+"ban" = "Ban"
+"unban" = "Unban"
+"banReason" = "Reason"
+"banOneDay" = "1 day"
+"banOneWeek" = "1 week"
+"banPermanent" = "Permanent"
+"bannedUntil" = "Until %s"
+"noPeers" = "No peers"
+"latency" = "Latency"
+"bytesSentReceived" = "Sent / received"
+"peerAddressHint" = "IP address or host:port"
+"peersPrivacyModeNote" = "Peers cannot be added while privacy mode is on."
+"sharedPeersNote" = "The wallets of this network share their peers, so their peer settings are combined."
+"syncQueued" = "Waiting for other wallets to sync"
+"syncRetrying" = "Sync failed, retrying in %s"
+"syncFailed" = "Sync failed"
+"syncAll" = "Sync all"
+"pauseAll" = "Pause all"
+"walletsSynced" = "%d of %d wallets synced"
+"syncSequentially" = "Sync wallets one at a time"
+"retryFailedSyncs" = "Retry failed syncs"
+"maxConcurrentSyncs" = "Max. wallets syncing at once"
+"noLimit" = "No limit"
+"syncPolicyUpdated" = "Sync settings updated"
+"invalidNumber" = "Enter a valid number"
+"walletBirthday" = "Wallet birthday"
+"rescanFromDate" = "Rescan from (YYYY-MM-DD)"
+"rescanRange" = "Blocks %d to %d will be rescanned."
+"birthdayUpdated" = "Wallet birthday updated"
+"developerMode" = "Developer mode"
+"developerModeUpdated" = "Developer mode updated"
+"standInURL" = "Stand-in URL, e.g. http://127.0.0.1:7777"
+"noStandIn" = "Not set"
+"devDcrdata" = "Dcrdata"
+"devPoliteia" = "Politeia (applies after restart)"
+"devVSPList" = "VSP list"
+"devBTCFeeRate" = "BTC fee rates"
+"devLTCFeeRate" = "LTC fee rates"
+"devRateSource" = "Exchange rates"
+"devDCRExplorer" = "DCR block explorer"
+"devBTCExplorer" = "BTC block explorer"
+"devLTCExplorer" = "LTC block explorer"
+"walletOpenFailed" = "Failed to open"
+"opensWhenSelected" = "Opens when selected"
+"openOnlyWhenSelected" = "Open only when selected"
+"maxTicketPrice" = "Maximum ticket price (DCR, optional)"
+"maxTicketsPerInterval" = "Maximum tickets per price interval (optional)"
+"purchaseStartHour" = "Buy from hour (0-23, optional)"
+"purchaseEndHour" = "Buy until hour (0-23, optional)"
+"ticketBuyerDryRun" = "Dry run, log the purchases without buying"
+"purchaseHoursRequired" = "Both the start and the end hour are required"
+"dryRunEnabled" = "Dry run, no ticket will be bought"
+"stakingAnalytics" = "Staking analytics"
+"realizedROI" = "Realized ROI"
+"annualizedReturn" = "Annualized return"
+"avgDaysToVote" = "Average days to vote"
+"ticketFees" = "Ticket fees"
+"vspFees" = "VSP fees"
+"netRewards" = "Net rewards"
+"missed" = "Missed"
+"netRewardsByMonth" = "Net rewards by month"
+"netRewardsByVSP" = "Net rewards by VSP"
+"solo" = "Solo"
+"stakingAnalyticsExported" = "Staking analytics exported to %v"
+"watchOnlyImport" = "The backup holds no seed for these wallets, they will be restored as watch only wallets that cannot spend: %s. Continue?"
+"multisigDescriptor" = "Multisig descriptor"
+"multisigWallet" = "Multisig wallet"
+"multisigChange" = "Change"
+"psbtFeeRate" = "Fee rate (per kvB)"
+"confirmSignPSBT" = "Only sign if you expect this transaction. It pays %s with a fee of %s (%s per kvB)."
+"accountScope" = "Account type"
+"accountScopeBIP" = "BIP%d accounts"
+"walletImported" = "%s: imported"
+"walletImportSkipped" = "%s: skipped, a wallet with this name already exists"
+"walletImportDowngraded" = "%s: imported as a watch only wallet that cannot spend"
+"walletImportFailed" = "%s: not imported, %v"
+"selectRepair" = "Select the fix to apply"
+"confirmRecreateWatchOnly" = "The wallet database is moved aside and replaced by a watch-only wallet created from the saved account key. The new wallet cannot spend and only holds the default account. Continue?"
+"proxyDNSServer" = "Proxy DNS server"
+"proxyDNSServerAddress" = "DNS server address (host:port), empty to resolve only through the proxy"
+"resolvedByProxy" = "Resolved by the proxy"
 `
@@ -901,4 +901,203 @@ const (
 	StrAssets                          = "assets"
 	StrNoWalletsAvailable              = "noWalletsAvailable"
 	StrCreateAssetWalletToSwapMsg      = "createAssetWalletToSwapMsg"
+	StrAddressExplorer                 = "addressExplorer"
+	StrInternal                        = "internal"
+	StrUsed                            = "used"
+	StrUnused                          = "unused"
+	StrFunded                          = "funded"
+	StrTimesUsed                       = "timesUsed"
+	StrFirstSeen                       = "firstSeen"
+	StrLastSeen                        = "lastSeen"
+	StrAddressLabel                    = "addressLabel"
+	StrSetAddressLabel                 = "setAddressLabel"
+	StrExportCSV                       = "exportCSV"
+	StrAddressesExported               = "addressesExported"
+	StrNoAddresses                     = "noAddresses"
+	StrImportKey                       = "importKey"
+	StrImportKeyNote                   = "importKeyNote"
+	StrImportKeyNoteDCR                = "importKeyNoteDCR"
+	StrKeyHint                         = "keyHint"
+	StrBirthdayHint                    = "birthdayHint"
+	StrImportPrivateKey                = "importPrivateKey"
+	StrWatchAddress                    = "watchAddress"
+	StrInvalidBirthday                 = "invalidBirthday"
+	StrKeyImported                     = "keyImported"
+	StrMultisig                        = "multisig"
+	StrMultisigNote                    = "multisigNote"
+	StrMultisigKey                     = "multisigKey"
+	StrCreateMultisigKey               = "createMultisigKey"
+	StrRequiredSignatures              = "requiredSignatures"
+	StrCosignerKeys                    = "cosignerKeys"
+	StrSetupMultisig                   = "setupMultisig"
+	StrMultisigPolicy                  = "multisigPolicy"
+	StrMultisigAddress                 = "multisigAddress"
+	StrCreatePSBT                      = "createPSBT"
+	StrPSBTHint                        = "psbtHint"
+	StrSignPSBT                        = "signPSBT"
+	StrCombinePSBTs                    = "combinePSBTs"
+	StrBroadcast                       = "broadcast"
+	StrPSBTSignatures                  = "psbtSignatures"
+	StrTxBroadcasted                   = "txBroadcasted"
+	StrInvalidThreshold                = "invalidThreshold"
+	StrExtendedPubKeyOrDescriptor      = "extendedPubKeyOrDescriptor"
+	StrOutputDescriptor                = "outputDescriptor"
+	StrDescriptorCopied                = "descriptorCopied"
+	StrSeedWordCount                   = "seedWordCount"
+	StrSeedPassphrase                  = "seedPassphrase"
+	StrShamirBackup                    = "shamirBackup"
+	StrShamirBackupNote                = "shamirBackupNote"
+	StrShareThreshold                  = "shareThreshold"
+	StrShareCount                      = "shareCount"
+	StrCreateShares                    = "createShares"
+	StrInvalidShares                   = "invalidShares"
+	StrShareOfShares                   = "shareOfShares"
+	StrWroteShare                      = "wroteShare"
+	StrEnterShareToVerify              = "enterShareToVerify"
+	StrShareMismatch                   = "shareMismatch"
+	StrShamirShares                    = "shamirShares"
+	StrSeedSharesHint                  = "seedSharesHint"
+	StrExportAppBackup                 = "exportAppBackup"
+	StrImportAppBackup                 = "importAppBackup"
+	StrBackupPassphrase                = "backupPassphrase"
+	StrAppBackupExported               = "appBackupExported"
+	StrBackupFilePath                  = "backupFilePath"
+	StrWalletSpendingPassphrase        = "walletSpendingPassphrase"
+	StrAppBackupImported               = "appBackupImported"
+	StrMigratingDB                     = "migratingDB"
+	StrCompactDB                       = "compactDB"
+	StrDBCompaction                    = "dbCompaction"
+	StrDatabaseDriver                  = "databaseDriver"
+	StrSwitchDatabaseDriver            = "switchDatabaseDriver"
+	StrSwitchDatabaseDriverInfo        = "switchDatabaseDriverInfo"
+	StrSwitch                          = "switch"
+	StrDatabaseDriverSwitched          = "databaseDriverSwitched"
+	StrConfirmDatabaseDriver           = "confirmDatabaseDriver"
+	StrConfirmDatabaseDriverInfo       = "confirmDatabaseDriverInfo"
+	StrKeep                            = "keep"
+	StrRevert                          = "revert"
+	StrRepair                          = "repair"
+	StrWalletDiagnosis                 = "walletDiagnosis"
+	StrIssueNone                       = "issueNone"
+	StrIssueUnknownAsset               = "issueUnknownAsset"
+	StrIssueMissingDB                  = "issueMissingDB"
+	StrIssueCorruptDB                  = "issueCorruptDB"
+	StrIssueWrongDriver                = "issueWrongDriver"
+	StrIssueVersionMismatch            = "issueVersionMismatch"
+	StrIssueLockedDB                   = "issueLockedDB"
+	StrIssueWalletData                 = "issueWalletData"
+	StrRepairClearStaleLock            = "repairClearStaleLock"
+	StrRepairRebuildWalletData         = "repairRebuildWalletData"
+	StrRepairRecreateWatchOnly         = "repairRecreateWatchOnly"
+	StrBadWalletRepairs                = "badWalletRepairs"
+	StrNoBadWalletRepairs              = "noBadWalletRepairs"
+	StrBadWalletRepaired               = "badWalletRepaired"
+	StrBadWalletNotRepaired            = "badWalletNotRepaired"
+	StrProxy                           = "proxy"
+	StrProxyAddress                    = "proxyAddress"
+	StrNotProxied                      = "notProxied"
+	StrStreamIsolation                 = "streamIsolation"
+	StrViaProxy                        = "viaProxy"
+	StrProxyUpdated                    = "proxyUpdated"
+	StrOnionPeerRequiresProxy          = "onionPeerRequiresProxy"
+	StrFullNode                        = "fullNode"
+	StrFullNodeNote                    = "fullNodeNote"
+	StrSPVSync                         = "spvSync"
+	StrRPCHost                         = "rpcHost"
+	StrRPCUser                         = "rpcUser"
+	StrRPCPassword                     = "rpcPassword"
+	StrRPCCertificate                  = "rpcCertificate"
+	StrDisableTLS                      = "disableTLS"
+	StrZMQBlockHost                    = "zmqBlockHost"
+	StrZMQTxHost                       = "zmqTxHost"
+	StrFallbackToSPV                   = "fallbackToSPV"
+	StrFullNodeSaved                   = "fullNodeSaved"
+	StrElectrumServers                 = "electrumServers"
+	StrPersistentPeers                 = "persistentPeers"
+	StrPersistentPeersNote             = "persistentPeersNote"
+	StrPreferredPeers                  = "preferredPeers"
+	StrPreferredPeersNote              = "preferredPeersNote"
+	StrBannedPeers                     = "bannedPeers"
+	StrBannedPeersNote                 = "bannedPeersNote"
+	StrBan                             = "ban"
+	StrUnban                           = "unban"
+	StrBanReason                       = "banReason"
+	StrBanOneDay                       = "banOneDay"
+	StrBanOneWeek                      = "banOneWeek"
+	StrBanPermanent                    = "banPermanent"
+	StrBannedUntil                     = "bannedUntil"
+	StrNoPeers                         = "noPeers"
+	StrLatency                         = "latency"
+	StrBytesSentReceived               = "bytesSentReceived"
+	StrPeerAddressHint                 = "peerAddressHint"
+	StrPeersPrivacyModeNote            = "peersPrivacyModeNote"
+	StrSharedPeersNote                 = "sharedPeersNote"
+	StrSyncQueued                      = "syncQueued"
+	StrSyncRetrying                    = "syncRetrying"
+	StrSyncFailed                      = "syncFailed"
+	StrSyncAll                         = "syncAll"
+	StrPauseAll                        = "pauseAll"
+	StrWalletsSynced                   = "walletsSynced"
+	StrSyncSequentially                = "syncSequentially"
+	StrRetryFailedSyncs                = "retryFailedSyncs"
+	StrMaxConcurrentSyncs              = "maxConcurrentSyncs"
+	StrNoLimit                         = "noLimit"
+	StrSyncPolicyUpdated               = "syncPolicyUpdated"
+	StrInvalidNumber                   = "invalidNumber"
+	StrWalletBirthday                  = "walletBirthday"
+	StrRescanFromDate                  = "rescanFromDate"
+	StrRescanRange                     = "rescanRange"
+	StrBirthdayUpdated                 = "birthdayUpdated"
+	StrDeveloperMode                   = "developerMode"
+	StrDeveloperModeUpdated            = "developerModeUpdated"
+	StrStandInURL                      = "standInURL"
+	StrNoStandIn                       = "noStandIn"
+	StrDevDcrdata                      = "devDcrdata"
+	StrDevPoliteia                     = "devPoliteia"
+	StrDevVSPList                      = "devVSPList"
+	StrDevBTCFeeRate                   = "devBTCFeeRate"
+	StrDevLTCFeeRate                   = "devLTCFeeRate"
+	StrDevRateSource                   = "devRateSource"
+	StrDevDCRExplorer                  = "devDCRExplorer"
+	StrDevBTCExplorer                  = "devBTCExplorer"
+	StrDevLTCExplorer                  = "devLTCExplorer"
+	StrWalletOpenFailed                = "walletOpenFailed"
+	StrOpensWhenSelected               = "opensWhenSelected"
+	StrOpenOnlyWhenSelected            = "openOnlyWhenSelected"
+	StrMaxTicketPrice                  = "maxTicketPrice"
+	StrMaxTicketsPerInterval           = "maxTicketsPerInterval"
+	StrPurchaseStartHour               = "purchaseStartHour"
+	StrPurchaseEndHour                 = "purchaseEndHour"
+	StrTicketBuyerDryRun               = "ticketBuyerDryRun"
+	StrPurchaseHoursRequired           = "purchaseHoursRequired"
+	StrDryRunEnabled                   = "dryRunEnabled"
+	StrStakingAnalytics                = "stakingAnalytics"
+	StrRealizedROI                     = "realizedROI"
+	StrAnnualizedReturn                = "annualizedReturn"
+	StrAvgDaysToVote                   = "avgDaysToVote"
+	StrTicketFees                      = "ticketFees"
+	StrVSPFees                         = "vspFees"
+	StrNetRewards                      = "netRewards"
+	StrMissed                          = "missed"
+	StrNetRewardsByMonth               = "netRewardsByMonth"
+	StrNetRewardsByVSP                 = "netRewardsByVSP"
+	StrSolo                            = "solo"
+	StrStakingAnalyticsExported        = "stakingAnalyticsExported"
+	StrWatchOnlyImport                 = "watchOnlyImport"
+	StrMultisigDescriptor              = "multisigDescriptor"
+	StrMultisigWallet                  = "multisigWallet"
+	StrMultisigChange                  = "multisigChange"
+	StrPSBTFeeRate                     = "psbtFeeRate"
+	StrConfirmSignPSBT                 = "confirmSignPSBT"
+	StrAccountScope                    = "accountScope"
+	StrAccountScopeBIP                 = "accountScopeBIP"
+	StrWalletImported                  = "walletImported"
+	StrWalletImportSkipped             = "walletImportSkipped"
+	StrWalletImportDowngraded          = "walletImportDowngraded"
+	StrWalletImportFailed              = "walletImportFailed"
+	StrSelectRepair                    = "selectRepair"
+	StrConfirmRecreateWatchOnly        = "confirmRecreateWatchOnly"
+	StrProxyDNSServer                  = "proxyDNSServer"
+	StrProxyDNSServerAddress           = "proxyDNSServerAddress"
+	StrResolvedByProxy                 = "resolvedByProxy"
 )